@@ -4,9 +4,11 @@ import (
 	"fmt"
 
 	"github.com/yaoapp/yao/agent/content"
+	"github.com/yaoapp/yao/agent/content/link"
 	"github.com/yaoapp/yao/agent/content/text"
 	contentTypes "github.com/yaoapp/yao/agent/content/types"
 	"github.com/yaoapp/yao/agent/context"
+	"github.com/yaoapp/yao/agent/search"
 )
 
 // BuildContent processes messages through Vision function to convert extended content types
@@ -39,6 +41,9 @@ func (ast *Assistant) BuildContent(ctx *context.Context, messages []context.Mess
 		Connector:         connector,
 		StreamOptions:     options.StreamOptions,
 	}
+	if opts != nil {
+		parseOptions.Sheet = opts.Sheet
+	}
 
 	contentMessages, referenceContext, err := content.ParseUserInput(ctx, messages, parseOptions)
 	if err != nil {
@@ -46,7 +51,13 @@ func (ast *Assistant) BuildContent(ctx *context.Context, messages []context.Mess
 	}
 
 	// Inject reference context into messages
-	if referenceContext != nil {
+	if referenceContext != nil && len(referenceContext.References) > 0 {
+		if referenceContext.XML == "" {
+			referenceContext.XML = search.FormatReferencesXML(referenceContext.References)
+		}
+		if referenceContext.Prompt == "" {
+			referenceContext.Prompt = search.GetCitationPrompt(nil)
+		}
 		contentMessages = ast.injectSearchContext(contentMessages, referenceContext)
 	}
 
@@ -95,6 +106,10 @@ func convertFilesToText(ctx *context.Context, messages []context.Message) []cont
 				}
 				newParts = append(newParts, part)
 
+			case context.ContentLink:
+				// Keep the URL only, pages are not fetched
+				newParts = append(newParts, link.Fallback(part))
+
 			case context.ContentImageURL:
 				// Skip images - cannot convert to text without vision
 				continue
@@ -141,6 +156,16 @@ func convertInterfaceToParts(items []interface{}) []context.ContentPart {
 					part.File.Filename = filename
 				}
 			}
+		case "link":
+			if linkData, ok := m["link"].(map[string]interface{}); ok {
+				part.Link = &context.LinkContent{}
+				if url, ok := linkData["url"].(string); ok {
+					part.Link.URL = url
+				}
+				if title, ok := linkData["title"].(string); ok {
+					part.Link.Title = title
+				}
+			}
 		case "image_url":
 			part.Type = context.ContentImageURL
 		default:
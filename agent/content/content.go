@@ -6,9 +6,14 @@ import (
 
 	"github.com/yaoapp/kun/log"
 	"github.com/yaoapp/yao/agent/content/docx"
+	"github.com/yaoapp/yao/agent/content/email"
+	"github.com/yaoapp/yao/agent/content/epub"
+	"github.com/yaoapp/yao/agent/content/html"
 	"github.com/yaoapp/yao/agent/content/image"
+	"github.com/yaoapp/yao/agent/content/link"
 	"github.com/yaoapp/yao/agent/content/pdf"
 	"github.com/yaoapp/yao/agent/content/pptx"
+	"github.com/yaoapp/yao/agent/content/sheet"
 	"github.com/yaoapp/yao/agent/content/text"
 	"github.com/yaoapp/yao/agent/content/types"
 	agentContext "github.com/yaoapp/yao/agent/context"
@@ -44,6 +49,15 @@ func ParseUserInput(ctx *agentContext.Context, messages []agentContext.Message,
 		}
 	}
 
+	// Assign citation IDs to the references that have none
+	if referenceContext != nil {
+		for i, ref := range referenceContext.References {
+			if ref.ID == "" {
+				ref.ID = fmt.Sprintf("ref_%03d", i+1)
+			}
+		}
+	}
+
 	return parsedMessages, referenceContext, nil
 }
 
@@ -109,6 +123,15 @@ func parseContentPart(ctx *agentContext.Context, content agentContext.ContentPar
 	case agentContext.ContentData:
 		return content, nil, nil
 
+	case agentContext.ContentLink:
+		part, refs, err := link.New(options).Parse(ctx, content)
+		if err != nil {
+			// Keep the URL in the message so that the model still sees it
+			log.Warn("Failed to fetch link %v: %v", content.Link, err)
+			return link.Fallback(content), nil, nil
+		}
+		return part, refs, nil
+
 	default:
 		return content, nil, fmt.Errorf("unsupported content part type: %s", content.Type)
 	}
@@ -134,6 +157,27 @@ func parseFileContent(ctx *agentContext.Context, content agentContext.ContentPar
 	case strings.HasSuffix(filename, ".pptx"):
 		return pptx.New(options).Parse(ctx, content)
 
+	case sheet.IsSupportedExtension(filename):
+		// The file's own sheet options take priority over the request options
+		if content.File.Sheet != nil {
+			fileOptions := types.Options{}
+			if options != nil {
+				fileOptions = *options
+			}
+			fileOptions.Sheet = content.File.Sheet
+			options = &fileOptions
+		}
+		return sheet.New(options).Parse(ctx, content)
+
+	case strings.HasSuffix(filename, ".html"), strings.HasSuffix(filename, ".htm"):
+		return html.New(options).Parse(ctx, content)
+
+	case strings.HasSuffix(filename, ".eml"):
+		return email.New(options).Parse(ctx, content)
+
+	case strings.HasSuffix(filename, ".epub"):
+		return epub.New(options).Parse(ctx, content)
+
 	case text.IsSupportedExtension(filename):
 		return text.New(options).Parse(ctx, content)
 	}
@@ -143,6 +187,25 @@ func parseFileContent(ctx *agentContext.Context, content agentContext.ContentPar
 	return text.New(options).ParseRaw(ctx, content)
 }
 
+// convertToSheetOptions converts a sheet options map loaded from JSON/history to SheetOptions
+func convertToSheetOptions(data map[string]interface{}) *agentContext.SheetOptions {
+	options := &agentContext.SheetOptions{}
+	if sheets, ok := data["sheets"].([]interface{}); ok {
+		for _, name := range sheets {
+			if name, ok := name.(string); ok {
+				options.Sheets = append(options.Sheets, name)
+			}
+		}
+	}
+	if maxRows, ok := data["max_rows"].(float64); ok {
+		options.MaxRows = int(maxRows)
+	}
+	if maxColumns, ok := data["max_columns"].(float64); ok {
+		options.MaxColumns = int(maxColumns)
+	}
+	return options
+}
+
 // convertToContentParts converts []interface{} to []ContentPart
 // This is needed when content is loaded from JSON/history and is []interface{} instead of []ContentPart
 func convertToContentParts(content []interface{}) ([]agentContext.ContentPart, bool) {
@@ -190,6 +253,20 @@ func convertToContentParts(content []interface{}) ([]agentContext.ContentPart, b
 				if filename, ok := fileData["filename"].(string); ok {
 					part.File.Filename = filename
 				}
+				if sheetData, ok := fileData["sheet"].(map[string]interface{}); ok {
+					part.File.Sheet = convertToSheetOptions(sheetData)
+				}
+			}
+
+		case "link":
+			if linkData, ok := m["link"].(map[string]interface{}); ok {
+				part.Link = &agentContext.LinkContent{}
+				if url, ok := linkData["url"].(string); ok {
+					part.Link.URL = url
+				}
+				if title, ok := linkData["title"].(string); ok {
+					part.Link.Title = title
+				}
 			}
 
 		case "input_audio":
@@ -0,0 +1,73 @@
+package content
+
+import (
+	stdContext "context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaoapp/yao/agent/content/types"
+	agentContext "github.com/yaoapp/yao/agent/context"
+	"github.com/yaoapp/yao/excel"
+)
+
+func writeWorkbook(t *testing.T) string {
+	xls, err := excel.New()
+	require.NoError(t, err)
+	defer xls.Close()
+
+	require.NoError(t, xls.UpdateSheet("Sheet1", [][]interface{}{{"Name", "Price"}, {"Apple", 3}}))
+	rows := [][]interface{}{{"ID", "Value"}}
+	for i := 1; i <= 30; i++ {
+		rows = append(rows, []interface{}{i, fmt.Sprintf("v%d", i)})
+	}
+	require.NoError(t, xls.UpdateSheet("Orders", rows))
+
+	path := filepath.Join(t.TempDir(), "data.xlsx")
+	require.NoError(t, xls.SaveAs(path))
+	return path
+}
+
+// TestParseContentPartSheetOptions tests that sheet options reach the spreadsheet parser
+func TestParseContentPartSheetOptions(t *testing.T) {
+	ctx := agentContext.New(stdContext.Background(), nil, "test-chat")
+	path := writeWorkbook(t)
+	part := agentContext.ContentPart{
+		Type: agentContext.ContentFile,
+		File: &agentContext.FileAttachment{URL: path, Filename: "data.xlsx"},
+	}
+
+	// Request options
+	options := &types.Options{Sheet: &types.SheetOptions{Sheets: []string{"Orders"}, MaxRows: 5}}
+	parsed, _, err := parseContentPart(ctx, part, options)
+	require.NoError(t, err)
+	assert.NotContains(t, parsed.Text, "## Sheet1")
+	assert.Contains(t, parsed.Text, "| 5 | v5 |")
+	assert.NotContains(t, parsed.Text, "| 6 | v6 |")
+
+	// The file's own options take priority
+	part.File.Sheet = &agentContext.SheetOptions{Sheets: []string{"Sheet1"}}
+	parsed, _, err = parseContentPart(ctx, part, options)
+	require.NoError(t, err)
+	assert.Contains(t, parsed.Text, "## Sheet1")
+	assert.NotContains(t, parsed.Text, "## Orders")
+	assert.Equal(t, []string{"Orders"}, options.Sheet.Sheets)
+
+	// Options loaded from history
+	parts, ok := convertToContentParts([]interface{}{map[string]interface{}{
+		"type": "file",
+		"file": map[string]interface{}{
+			"url":      path,
+			"filename": "data.xlsx",
+			"sheet":    map[string]interface{}{"sheets": []interface{}{"Orders"}, "max_rows": float64(10)},
+		},
+	}})
+	require.True(t, ok)
+	parsed, _, err = parseContentPart(ctx, parts[0], nil)
+	require.NoError(t, err)
+	assert.NotContains(t, parsed.Text, "## Sheet1")
+	assert.Contains(t, parsed.Text, "| 10 | v10 |")
+	assert.NotContains(t, parsed.Text, "| 11 | v11 |")
+}
@@ -0,0 +1,343 @@
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"strings"
+
+	"github.com/yaoapp/yao/agent/content/html"
+	"github.com/yaoapp/yao/agent/content/types"
+	agentContext "github.com/yaoapp/yao/agent/context"
+	searchTypes "github.com/yaoapp/yao/agent/search/types"
+	"github.com/yaoapp/yao/attachment"
+	"golang.org/x/net/html/charset"
+)
+
+// maxDepth limits the nesting of multipart bodies
+const maxDepth = 10
+
+// Email handles email (.eml) content
+type Email struct {
+	options *types.Options
+}
+
+// Message is a parsed email
+type Message struct {
+	Subject     string
+	From        string
+	To          string
+	Cc          string
+	Date        string
+	Body        string // plain text body, or the HTML body converted to markdown
+	Attachments []Attachment
+}
+
+// Attachment is a file attached to an email
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Size        int
+}
+
+// New creates a new email handler
+func New(options *types.Options) *Email {
+	return &Email{options: options}
+}
+
+// Parse parses email content and returns the headers, body and attachment list
+func (h *Email) Parse(ctx *agentContext.Context, content agentContext.ContentPart) (agentContext.ContentPart, []*searchTypes.Reference, error) {
+	if content.File == nil || content.File.URL == "" {
+		return content, nil, fmt.Errorf("file content missing URL")
+	}
+
+	url := content.File.URL
+
+	// Check cache first
+	cachedText, found, err := h.readFromCache(ctx, url)
+	if err == nil && found {
+		return agentContext.ContentPart{
+			Type: agentContext.ContentText,
+			Text: cachedText,
+		}, nil, nil
+	}
+
+	// Read email file
+	data, err := h.readFile(ctx, url)
+	if err != nil {
+		return content, nil, fmt.Errorf("failed to read email: %w", err)
+	}
+
+	msg, err := Read(data)
+	if err != nil {
+		return content, nil, fmt.Errorf("failed to parse email: %w", err)
+	}
+
+	text := msg.Markdown()
+
+	// Cache the result
+	if err := h.saveToCache(ctx, url, text); err != nil {
+		// Log warning but don't fail
+		fmt.Printf("Warning: failed to cache email text: %v\n", err)
+	}
+
+	return agentContext.ContentPart{
+		Type: agentContext.ContentText,
+		Text: text,
+	}, nil, nil
+}
+
+// Read parses a raw RFC 5322 email
+func Read(data []byte) (*Message, error) {
+	raw, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := &mime.WordDecoder{CharsetReader: charset.NewReaderLabel}
+	header := func(name string) string {
+		value := raw.Header.Get(name)
+		if decoded, err := decoder.DecodeHeader(value); err == nil {
+			return decoded
+		}
+		return value
+	}
+
+	msg := &Message{
+		Subject: header("Subject"),
+		From:    header("From"),
+		To:      header("To"),
+		Cc:      header("Cc"),
+		Date:    header("Date"),
+	}
+
+	var plain, htmlBody []string
+	err = walk(raw.Header, raw.Body, 0, func(contentType string, params map[string]string, disposition, filename string, body []byte) {
+		if filename != "" || disposition == "attachment" {
+			if filename != "" {
+				if decoded, err := decoder.DecodeHeader(filename); err == nil {
+					filename = decoded
+				}
+			}
+			msg.Attachments = append(msg.Attachments, Attachment{Filename: filename, ContentType: contentType, Size: len(body)})
+			return
+		}
+
+		switch contentType {
+		case "text/plain", "":
+			plain = append(plain, decodeCharset(body, params["charset"]))
+		case "text/html":
+			htmlBody = append(htmlBody, decodeCharset(body, params["charset"]))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(plain) > 0:
+		msg.Body = strings.TrimSpace(strings.Join(plain, "\n\n"))
+	case len(htmlBody) > 0:
+		doc, err := html.Extract([]byte(strings.Join(htmlBody, "\n")))
+		if err != nil {
+			return nil, err
+		}
+		msg.Body = doc.Text
+	}
+	return msg, nil
+}
+
+// Markdown renders the email as markdown: headers, body and attachment list
+func (msg *Message) Markdown() string {
+	var sb strings.Builder
+	subject := msg.Subject
+	if subject == "" {
+		subject = "(no subject)"
+	}
+	sb.WriteString(fmt.Sprintf("# %s\n\n", subject))
+	for _, h := range [][2]string{{"From", msg.From}, {"To", msg.To}, {"Cc", msg.Cc}, {"Date", msg.Date}} {
+		if h[1] != "" {
+			sb.WriteString(fmt.Sprintf("- %s: %s\n", h[0], h[1]))
+		}
+	}
+
+	if msg.Body != "" {
+		sb.WriteString("\n")
+		sb.WriteString(msg.Body)
+		sb.WriteString("\n")
+	}
+
+	if len(msg.Attachments) > 0 {
+		sb.WriteString("\n## Attachments\n\n")
+		for _, a := range msg.Attachments {
+			name := a.Filename
+			if name == "" {
+				name = "(unnamed)"
+			}
+			sb.WriteString(fmt.Sprintf("- %s (%s, %s)\n", name, a.ContentType, formatSize(a.Size)))
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// partHeader is the header of a MIME part
+type partHeader interface {
+	Get(key string) string
+}
+
+// walk visits the leaf parts of a MIME body
+func walk(header partHeader, body io.Reader, depth int, visit func(contentType string, params map[string]string, disposition, filename string, body []byte)) error {
+	contentType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		contentType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(contentType, "multipart/") && depth < maxDepth {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := walk(part.Header, part, depth+1, visit); err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := io.ReadAll(decodeTransfer(body, header.Get("Content-Transfer-Encoding")))
+	if err != nil {
+		return err
+	}
+
+	disposition, dparams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := dparams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	if contentType == "message/rfc822" && filename == "" {
+		filename = "message.eml"
+	}
+	visit(contentType, params, disposition, filename, data)
+	return nil
+}
+
+// decodeTransfer decodes the Content-Transfer-Encoding of a part
+func decodeTransfer(body io.Reader, encoding string) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	}
+	return body
+}
+
+// decodeCharset converts a text body to UTF-8
+func decodeCharset(body []byte, label string) string {
+	if label == "" {
+		return string(body)
+	}
+	reader, err := charset.NewReaderLabel(label, bytes.NewReader(body))
+	if err != nil {
+		return string(body)
+	}
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		return string(body)
+	}
+	return string(decoded)
+}
+
+// formatSize formats a size in bytes
+func formatSize(size int) string {
+	switch {
+	case size >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(size)/1024/1024)
+	case size >= 1024:
+		return fmt.Sprintf("%.1f KB", float64(size)/1024)
+	}
+	return fmt.Sprintf("%d B", size)
+}
+
+// readFile reads email content from various sources
+func (h *Email) readFile(ctx *agentContext.Context, url string) ([]byte, error) {
+	if strings.HasPrefix(url, "__") {
+		return h.readFromUploader(ctx, url)
+	}
+
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("HTTP URL fetch not implemented yet: %s", url)
+	}
+
+	// Try to read as local file path
+	if _, err := os.Stat(url); err == nil {
+		return os.ReadFile(url)
+	}
+
+	return nil, fmt.Errorf("unsupported email source: %s", url)
+}
+
+// readFromUploader reads email content from file uploader
+func (h *Email) readFromUploader(ctx *agentContext.Context, wrapper string) ([]byte, error) {
+	uploaderName, fileID, ok := attachment.Parse(wrapper)
+	if !ok {
+		return nil, fmt.Errorf("invalid uploader wrapper format: %s", wrapper)
+	}
+
+	manager, exists := attachment.Managers[uploaderName]
+	if !exists {
+		return nil, fmt.Errorf("uploader '%s' not found", uploaderName)
+	}
+
+	data, err := manager.Read(ctx.Context, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	return data, nil
+}
+
+// readFromCache reads cached text content for an email
+func (h *Email) readFromCache(ctx *agentContext.Context, url string) (string, bool, error) {
+	uploaderName, fileID, isWrapper := attachment.Parse(url)
+	if !isWrapper {
+		return "", false, nil
+	}
+
+	manager, exists := attachment.Managers[uploaderName]
+	if !exists {
+		return "", false, nil
+	}
+
+	text, err := manager.GetText(ctx.Context, fileID, false)
+	if err == nil && text != "" {
+		return text, true, nil
+	}
+
+	return "", false, nil
+}
+
+// saveToCache saves processed text to cache
+func (h *Email) saveToCache(ctx *agentContext.Context, url string, text string) error {
+	uploaderName, fileID, isWrapper := attachment.Parse(url)
+	if !isWrapper {
+		return nil
+	}
+
+	manager, exists := attachment.Managers[uploaderName]
+	if !exists {
+		return nil
+	}
+
+	return manager.SaveText(ctx.Context, fileID, text)
+}
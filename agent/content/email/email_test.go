@@ -0,0 +1,90 @@
+package email_test
+
+import (
+	stdContext "context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaoapp/yao/agent/content/email"
+	agentContext "github.com/yaoapp/yao/agent/context"
+)
+
+const testEmail = `From: "Ada Lovelace" <ada@example.com>
+To: team@example.com
+Cc: =?UTF-8?B?5byg5LiJ?= <zhang@example.com>
+Subject: =?UTF-8?Q?Quarterly_report_=E2=9C=93?=
+Date: Mon, 2 Mar 2026 10:00:00 +0000
+MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="outer"
+
+--outer
+Content-Type: multipart/alternative; boundary="inner"
+
+--inner
+Content-Type: text/plain; charset=utf-8
+Content-Transfer-Encoding: quoted-printable
+
+Hi team,
+
+The report is attached. Revenue grew by 12% =E2=80=94 see page 3.
+--inner
+Content-Type: text/html; charset=utf-8
+
+<p>Hi team, <b>HTML version</b></p>
+--inner--
+
+--outer
+Content-Type: application/pdf; name="report.pdf"
+Content-Disposition: attachment; filename="report.pdf"
+Content-Transfer-Encoding: base64
+
+JVBERi0xLjQKJcOkw7zDtsOfCjIgMCBvYmoKPDwvTGVuZ3RoIDMgMCBSPj4Kc3RyZWFtCg==
+--outer--
+`
+
+// TestRead tests headers, the preferred plain body and the attachment list
+func TestRead(t *testing.T) {
+	msg, err := email.Read([]byte(strings.ReplaceAll(testEmail, "\n", "\r\n")))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Quarterly report ✓", msg.Subject)
+	assert.Equal(t, `"Ada Lovelace" <ada@example.com>`, msg.From)
+	assert.Equal(t, "张三 <zhang@example.com>", msg.Cc)
+	assert.Equal(t, "Hi team,\r\n\r\nThe report is attached. Revenue grew by 12% — see page 3.", msg.Body)
+	require.Len(t, msg.Attachments, 1)
+	assert.Equal(t, "report.pdf", msg.Attachments[0].Filename)
+	assert.Equal(t, "application/pdf", msg.Attachments[0].ContentType)
+	assert.Equal(t, 52, msg.Attachments[0].Size)
+}
+
+// TestReadHTML tests an HTML only email
+func TestReadHTML(t *testing.T) {
+	msg, err := email.Read([]byte("Subject: Hello\r\nContent-Type: text/html\r\n\r\n<html><body><p>Hello <b>World</b></p></body></html>"))
+	require.NoError(t, err)
+	assert.Equal(t, "Hello World", msg.Body)
+	assert.Empty(t, msg.Attachments)
+}
+
+// TestParse tests the markdown returned for an .eml file
+func TestParse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.eml")
+	require.NoError(t, os.WriteFile(path, []byte(testEmail), 0644))
+
+	ctx := agentContext.New(stdContext.Background(), nil, "test-chat")
+	part, refs, err := email.New(nil).Parse(ctx, agentContext.ContentPart{
+		Type: agentContext.ContentFile,
+		File: &agentContext.FileAttachment{URL: path, Filename: "report.eml"},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, refs)
+	assert.Equal(t, agentContext.ContentText, part.Type)
+
+	assert.True(t, strings.HasPrefix(part.Text, "# Quarterly report ✓\n\n- From: \"Ada Lovelace\" <ada@example.com>\n- To: team@example.com\n"))
+	assert.Contains(t, part.Text, "Revenue grew by 12%")
+	assert.NotContains(t, part.Text, "HTML version")
+	assert.Contains(t, part.Text, "## Attachments\n\n- report.pdf (application/pdf, 52 B)")
+}
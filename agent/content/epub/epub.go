@@ -0,0 +1,281 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/yaoapp/yao/agent/content/html"
+	"github.com/yaoapp/yao/agent/content/types"
+	agentContext "github.com/yaoapp/yao/agent/context"
+	searchTypes "github.com/yaoapp/yao/agent/search/types"
+	"github.com/yaoapp/yao/attachment"
+)
+
+// maxEntrySize limits the uncompressed size of one file in the archive
+const maxEntrySize = 20 * 1024 * 1024
+
+// Epub handles EPUB content
+type Epub struct {
+	options *types.Options
+}
+
+// Book is the readable content of an EPUB
+type Book struct {
+	Title    string
+	Authors  []string
+	Chapters []string // chapter texts in reading (spine) order
+}
+
+type container struct {
+	Rootfiles []struct {
+		FullPath string `xml:"full-path,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+type packageDocument struct {
+	Titles   []string `xml:"metadata>title"`
+	Creators []string `xml:"metadata>creator"`
+	Items    []struct {
+		ID        string `xml:"id,attr"`
+		Href      string `xml:"href,attr"`
+		MediaType string `xml:"media-type,attr"`
+	} `xml:"manifest>item"`
+	Spine []struct {
+		IDRef string `xml:"idref,attr"`
+	} `xml:"spine>itemref"`
+}
+
+// New creates a new EPUB handler
+func New(options *types.Options) *Epub {
+	return &Epub{options: options}
+}
+
+// Parse parses EPUB content and returns the chapters as markdown
+func (h *Epub) Parse(ctx *agentContext.Context, content agentContext.ContentPart) (agentContext.ContentPart, []*searchTypes.Reference, error) {
+	if content.File == nil || content.File.URL == "" {
+		return content, nil, fmt.Errorf("file content missing URL")
+	}
+
+	url := content.File.URL
+
+	// Check cache first
+	cachedText, found, err := h.readFromCache(ctx, url)
+	if err == nil && found {
+		return agentContext.ContentPart{
+			Type: agentContext.ContentText,
+			Text: cachedText,
+		}, nil, nil
+	}
+
+	// Read EPUB file
+	data, err := h.readFile(ctx, url)
+	if err != nil {
+		return content, nil, fmt.Errorf("failed to read EPUB: %w", err)
+	}
+
+	book, err := Read(data)
+	if err != nil {
+		return content, nil, fmt.Errorf("failed to parse EPUB: %w", err)
+	}
+
+	text := book.Markdown()
+	if len(book.Chapters) == 0 {
+		return content, nil, fmt.Errorf("no text content extracted from EPUB")
+	}
+
+	// Cache the result
+	if err := h.saveToCache(ctx, url, text); err != nil {
+		// Log warning but don't fail
+		fmt.Printf("Warning: failed to cache EPUB text: %v\n", err)
+	}
+
+	return agentContext.ContentPart{
+		Type: agentContext.ContentText,
+		Text: text,
+	}, nil, nil
+}
+
+// Read reads the metadata and the chapters of an EPUB archive
+func Read(data []byte) (*Book, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	var c container
+	if err := readXML(archive, "META-INF/container.xml", &c); err != nil {
+		return nil, err
+	}
+	if len(c.Rootfiles) == 0 || c.Rootfiles[0].FullPath == "" {
+		return nil, fmt.Errorf("missing rootfile in META-INF/container.xml")
+	}
+
+	opfPath := c.Rootfiles[0].FullPath
+	var opf packageDocument
+	if err := readXML(archive, opfPath, &opf); err != nil {
+		return nil, err
+	}
+
+	book := &Book{}
+	if len(opf.Titles) > 0 {
+		book.Title = strings.TrimSpace(opf.Titles[0])
+	}
+	for _, creator := range opf.Creators {
+		if creator = strings.TrimSpace(creator); creator != "" {
+			book.Authors = append(book.Authors, creator)
+		}
+	}
+
+	manifest := map[string]string{}
+	for _, item := range opf.Items {
+		if item.MediaType == "application/xhtml+xml" || item.MediaType == "text/html" {
+			href, err := url.PathUnescape(item.Href)
+			if err != nil {
+				href = item.Href
+			}
+			manifest[item.ID] = path.Join(path.Dir(opfPath), href)
+		}
+	}
+
+	for _, ref := range opf.Spine {
+		name, ok := manifest[ref.IDRef]
+		if !ok {
+			continue
+		}
+		chapter, err := readEntry(archive, name)
+		if err != nil {
+			return nil, err
+		}
+		doc, err := html.Extract(chapter)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if doc.Text != "" {
+			book.Chapters = append(book.Chapters, doc.Text)
+		}
+	}
+	return book, nil
+}
+
+// Markdown renders the book as markdown: title, authors and chapters
+func (b *Book) Markdown() string {
+	var sb strings.Builder
+	if b.Title != "" {
+		sb.WriteString(fmt.Sprintf("# %s\n\n", b.Title))
+	}
+	if len(b.Authors) > 0 {
+		sb.WriteString(fmt.Sprintf("Author: %s\n\n", strings.Join(b.Authors, ", ")))
+	}
+	sb.WriteString(strings.Join(b.Chapters, "\n\n---\n\n"))
+	return strings.TrimSpace(sb.String())
+}
+
+// readXML decodes an XML file of the archive
+func readXML(archive *zip.Reader, name string, v interface{}) error {
+	data, err := readEntry(archive, name)
+	if err != nil {
+		return err
+	}
+	if err := xml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// readEntry reads a file of the archive, the size is limited to maxEntrySize
+func readEntry(archive *zip.Reader, name string) ([]byte, error) {
+	file, err := archive.Open(strings.TrimPrefix(path.Clean(name), "/"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxEntrySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if len(data) > maxEntrySize {
+		return nil, fmt.Errorf("%s: file too large", name)
+	}
+	return data, nil
+}
+
+// readFile reads EPUB content from various sources
+func (h *Epub) readFile(ctx *agentContext.Context, url string) ([]byte, error) {
+	if strings.HasPrefix(url, "__") {
+		return h.readFromUploader(ctx, url)
+	}
+
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("HTTP URL fetch not implemented yet: %s", url)
+	}
+
+	// Try to read as local file path
+	if _, err := os.Stat(url); err == nil {
+		return os.ReadFile(url)
+	}
+
+	return nil, fmt.Errorf("unsupported EPUB source: %s", url)
+}
+
+// readFromUploader reads EPUB content from file uploader
+func (h *Epub) readFromUploader(ctx *agentContext.Context, wrapper string) ([]byte, error) {
+	uploaderName, fileID, ok := attachment.Parse(wrapper)
+	if !ok {
+		return nil, fmt.Errorf("invalid uploader wrapper format: %s", wrapper)
+	}
+
+	manager, exists := attachment.Managers[uploaderName]
+	if !exists {
+		return nil, fmt.Errorf("uploader '%s' not found", uploaderName)
+	}
+
+	data, err := manager.Read(ctx.Context, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	return data, nil
+}
+
+// readFromCache reads cached text content for an EPUB
+func (h *Epub) readFromCache(ctx *agentContext.Context, url string) (string, bool, error) {
+	uploaderName, fileID, isWrapper := attachment.Parse(url)
+	if !isWrapper {
+		return "", false, nil
+	}
+
+	manager, exists := attachment.Managers[uploaderName]
+	if !exists {
+		return "", false, nil
+	}
+
+	text, err := manager.GetText(ctx.Context, fileID, false)
+	if err == nil && text != "" {
+		return text, true, nil
+	}
+
+	return "", false, nil
+}
+
+// saveToCache saves processed text to cache
+func (h *Epub) saveToCache(ctx *agentContext.Context, url string, text string) error {
+	uploaderName, fileID, isWrapper := attachment.Parse(url)
+	if !isWrapper {
+		return nil
+	}
+
+	manager, exists := attachment.Managers[uploaderName]
+	if !exists {
+		return nil
+	}
+
+	return manager.SaveText(ctx.Context, fileID, text)
+}
@@ -0,0 +1,88 @@
+package epub_test
+
+import (
+	"archive/zip"
+	"bytes"
+	stdContext "context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaoapp/yao/agent/content/epub"
+	agentContext "github.com/yaoapp/yao/agent/context"
+)
+
+func writeEpub(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, name := range []string{"mimetype", "META-INF/container.xml", "OEBPS/content.opf", "OEBPS/text/chapter 1.xhtml", "OEBPS/text/ch2.xhtml"} {
+		content, ok := files[name]
+		if !ok {
+			continue
+		}
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+var testFiles = map[string]string{
+	"mimetype": "application/epub+zip",
+	"META-INF/container.xml": `<?xml version="1.0"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles><rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/></rootfiles>
+</container>`,
+	"OEBPS/content.opf": `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:title>The Yao Book</dc:title>
+    <dc:creator>Ada</dc:creator>
+    <dc:creator>Bob</dc:creator>
+  </metadata>
+  <manifest>
+    <item id="c1" href="text/chapter%201.xhtml" media-type="application/xhtml+xml"/>
+    <item id="c2" href="text/ch2.xhtml" media-type="application/xhtml+xml"/>
+    <item id="css" href="style.css" media-type="text/css"/>
+  </manifest>
+  <spine><itemref idref="c2"/><itemref idref="c1"/><itemref idref="css"/></spine>
+</package>`,
+	"OEBPS/text/chapter 1.xhtml": `<html xmlns="http://www.w3.org/1999/xhtml"><body><h1>Chapter One</h1><p>The beginning.</p></body></html>`,
+	"OEBPS/text/ch2.xhtml":       `<html xmlns="http://www.w3.org/1999/xhtml"><body><h1>Preface</h1><p>Read me first.</p></body></html>`,
+}
+
+// TestRead tests the metadata and the spine order
+func TestRead(t *testing.T) {
+	book, err := epub.Read(writeEpub(t, testFiles))
+	require.NoError(t, err)
+
+	assert.Equal(t, "The Yao Book", book.Title)
+	assert.Equal(t, []string{"Ada", "Bob"}, book.Authors)
+	assert.Equal(t, []string{"# Preface\nRead me first.", "# Chapter One\nThe beginning."}, book.Chapters)
+
+	_, err = epub.Read([]byte("not a zip"))
+	assert.Error(t, err)
+
+	_, err = epub.Read(writeEpub(t, map[string]string{"mimetype": "application/epub+zip"}))
+	assert.Error(t, err)
+}
+
+// TestParse tests the markdown returned for an .epub file
+func TestParse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "book.epub")
+	require.NoError(t, os.WriteFile(path, writeEpub(t, testFiles), 0644))
+
+	ctx := agentContext.New(stdContext.Background(), nil, "test-chat")
+	part, refs, err := epub.New(nil).Parse(ctx, agentContext.ContentPart{
+		Type: agentContext.ContentFile,
+		File: &agentContext.FileAttachment{URL: path, Filename: "book.epub"},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, refs)
+	assert.Equal(t, agentContext.ContentText, part.Type)
+	assert.Equal(t, "# The Yao Book\n\nAuthor: Ada, Bob\n\n# Preface\nRead me first.\n\n---\n\n# Chapter One\nThe beginning.", part.Text)
+}
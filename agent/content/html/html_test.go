@@ -0,0 +1,61 @@
+package html_test
+
+import (
+	stdContext "context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaoapp/yao/agent/content/html"
+	agentContext "github.com/yaoapp/yao/agent/context"
+)
+
+const testPage = `<html>
+<head><meta charset="iso-8859-1"><title>Caf` + "\xe9" + ` Menu</title><style>p { color: red }</style></head>
+<body>
+	<nav>Home | About</nav>
+	<h2>Drinks</h2>
+	<p>Our <a href="https://example.com/coffee">coffee</a> is roasted daily.</p>
+	<ol><li>Espresso</li><li>Latte</li></ol>
+	<table><tr><th>Item</th><th>Price</th></tr><tr><td>Tea</td><td>2</td></tr></table>
+	<script>alert("x")</script>
+	<footer>All rights reserved</footer>
+</body>
+</html>`
+
+// TestExtract tests the readable text of an HTML document
+func TestExtract(t *testing.T) {
+	doc, err := html.Extract([]byte(`<html><head><meta property="og:title" content="OG Title"><meta name="description" content="Desc"><title>Title</title></head>` +
+		`<body><header>Menu</header><main><h1>Heading</h1><p>Hello<br>World</p><pre>  a := 1
+  b := 2</pre></main></body></html>`))
+	require.NoError(t, err)
+	assert.Equal(t, "OG Title", doc.Title)
+	assert.Equal(t, "Desc", doc.Description)
+	assert.Equal(t, "# Heading\nHello\nWorld\n```\n  a := 1\n  b := 2\n```", doc.Text)
+}
+
+// TestParse tests HTML files converted to markdown
+func TestParse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "menu.html")
+	require.NoError(t, os.WriteFile(path, []byte(testPage), 0644))
+
+	ctx := agentContext.New(stdContext.Background(), nil, "test-chat")
+	part, refs, err := html.New(nil).Parse(ctx, agentContext.ContentPart{
+		Type: agentContext.ContentFile,
+		File: &agentContext.FileAttachment{URL: path, Filename: "menu.html"},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, refs)
+	assert.Equal(t, agentContext.ContentText, part.Type)
+
+	assert.Contains(t, part.Text, "# Café Menu")
+	assert.Contains(t, part.Text, "## Drinks")
+	assert.Contains(t, part.Text, "Our [coffee](https://example.com/coffee) is roasted daily.")
+	assert.Contains(t, part.Text, "- Espresso\n- Latte")
+	assert.Contains(t, part.Text, "| Tea | 2")
+	for _, noise := range []string{"Home", "color: red", "alert", "All rights reserved"} {
+		assert.NotContains(t, part.Text, noise)
+	}
+}
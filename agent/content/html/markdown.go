@@ -0,0 +1,207 @@
+package html
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	nethtml "golang.org/x/net/html"
+)
+
+// boilerplate is removed before the readable text is extracted
+const boilerplate = "script, style, noscript, template, iframe, object, embed, svg, canvas, " +
+	"nav, header, footer, aside, form, button, select, " +
+	"[role=navigation], [role=banner], [role=contentinfo], [role=complementary], [aria-hidden=true], " +
+	".nav, .navbar, .menu, .sidebar, .breadcrumb, .breadcrumbs, .footer, .header, " +
+	".advertisement, .ads, .ad, .share, .social, .cookie, .cookies, .comments, #comments"
+
+// mainContent is tried in order to find the main content of the page
+var mainContent = []string{"article", "main", "[role=main]", "#content", ".content", ".post", ".entry-content"}
+
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// Document is the readable content of an HTML document
+type Document struct {
+	Title       string
+	Description string
+	Text        string // markdown-like readable text
+}
+
+// Extract removes the boilerplate (scripts, navigation, headers, footers...) of an HTML document
+// and returns its title, description and readable text
+func Extract(data []byte) (*Document, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Document{}
+	result.Title = firstNonEmpty(
+		attr(doc, `meta[property="og:title"]`, "content"),
+		doc.Find("title").First().Text(),
+		doc.Find("h1").First().Text(),
+	)
+	result.Description = firstNonEmpty(
+		attr(doc, `meta[name="description"]`, "content"),
+		attr(doc, `meta[property="og:description"]`, "content"),
+	)
+
+	doc.Find(boilerplate).Remove()
+
+	root := doc.Find("body")
+	for _, selector := range mainContent {
+		if sel := doc.Find(selector).First(); sel.Length() > 0 && len(strings.TrimSpace(sel.Text())) > 200 {
+			root = sel
+			break
+		}
+	}
+	if root.Length() == 0 {
+		root = doc.Selection
+	}
+
+	var sb strings.Builder
+	for _, node := range root.Nodes {
+		writeNode(&sb, node, false)
+	}
+	result.Text = strings.TrimSpace(blankLines.ReplaceAllString(sb.String(), "\n\n"))
+	return result, nil
+}
+
+// writeNode writes the readable text of a node in a markdown-like layout
+func writeNode(sb *strings.Builder, node *nethtml.Node, pre bool) {
+	switch node.Type {
+	case nethtml.TextNode:
+		if pre {
+			sb.WriteString(node.Data)
+			return
+		}
+		text := strings.Join(strings.Fields(node.Data), " ")
+		if text == "" {
+			return
+		}
+		if startsWithSpace(node.Data) && !endsWithBreak(sb) {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(text)
+		if endsWithSpace(node.Data) {
+			sb.WriteString(" ")
+		}
+		return
+
+	case nethtml.ElementNode:
+	case nethtml.DocumentNode:
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			writeNode(sb, child, pre)
+		}
+		return
+
+	default:
+		return
+	}
+
+	prefix, block := "", false
+	switch node.Data {
+	case "br":
+		sb.WriteString("\n")
+		return
+	case "h1":
+		prefix, block = "# ", true
+	case "h2":
+		prefix, block = "## ", true
+	case "h3":
+		prefix, block = "### ", true
+	case "h4", "h5", "h6":
+		prefix, block = "#### ", true
+	case "li":
+		prefix, block = "- ", true
+	case "pre":
+		pre, block = true, true
+	case "p", "div", "section", "article", "main", "blockquote", "ul", "ol", "dl", "dt", "dd",
+		"table", "tr", "figure", "figcaption", "hr", "body":
+		block = true
+	case "td", "th":
+		sb.WriteString(" | ")
+	case "img":
+		if alt := nodeAttr(node, "alt"); alt != "" {
+			sb.WriteString("[" + alt + "]")
+		}
+		return
+	case "a":
+		href := nodeAttr(node, "href")
+		if !pre && (strings.HasPrefix(href, "http://") || strings.HasPrefix(href, "https://")) {
+			var inner strings.Builder
+			for child := node.FirstChild; child != nil; child = child.NextSibling {
+				writeNode(&inner, child, pre)
+			}
+			if text := strings.TrimSpace(inner.String()); text != "" {
+				if !endsWithBreak(sb) {
+					sb.WriteString(" ")
+				}
+				sb.WriteString("[" + text + "](" + href + ")")
+				return
+			}
+		}
+	}
+
+	if block {
+		if !atLineStart(sb) {
+			sb.WriteString("\n")
+		}
+		if node.Data == "pre" {
+			sb.WriteString("```\n")
+		}
+		sb.WriteString(prefix)
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		writeNode(sb, child, pre)
+	}
+	if block {
+		if node.Data == "pre" {
+			sb.WriteString("\n```")
+		}
+		sb.WriteString("\n")
+	}
+}
+
+func nodeAttr(node *nethtml.Node, name string) string {
+	for _, a := range node.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func attr(doc *goquery.Document, selector, name string) string {
+	value, _ := doc.Find(selector).First().Attr(name)
+	return value
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.Join(strings.Fields(v), " "); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func startsWithSpace(s string) bool {
+	return s != "" && strings.TrimLeft(s, " \t\r\n") != s
+}
+
+func endsWithSpace(s string) bool {
+	return s != "" && strings.TrimRight(s, " \t\r\n") != s
+}
+
+func endsWithBreak(sb *strings.Builder) bool {
+	s := sb.String()
+	return s == "" || strings.HasSuffix(s, "\n") || strings.HasSuffix(s, " ")
+}
+
+// atLineStart reports whether nothing but a list or heading marker was written on the current line
+func atLineStart(sb *strings.Builder) bool {
+	s := sb.String()
+	return s == "" || strings.HasSuffix(s, "\n") || strings.HasSuffix(s, "- ") || strings.HasSuffix(s, "# ")
+}
@@ -1 +1,350 @@
 package link
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/yaoapp/yao/agent/content/html"
+	"github.com/yaoapp/yao/agent/content/types"
+	agentContext "github.com/yaoapp/yao/agent/context"
+	searchTypes "github.com/yaoapp/yao/agent/search/types"
+	"golang.org/x/net/html/charset"
+)
+
+// Fetch limits, package variables so that they can be tuned by the application
+var (
+	// MaxBytes is the maximum response body read from a page
+	MaxBytes int64 = 5 * 1024 * 1024
+
+	// MaxChars is the maximum length of the readable text kept for a page
+	MaxChars = 20000
+
+	// Timeout is the timeout of one page fetch, redirects included
+	Timeout = 15 * time.Second
+
+	// CacheTTL is how long a fetched page is cached
+	CacheTTL = time.Hour
+
+	// AllowPrivate allows fetching loopback and private network addresses (tests only)
+	AllowPrivate = false
+)
+
+// ErrBlockedAddress is returned when a link resolves to a loopback or private network address
+var ErrBlockedAddress = errors.New("link resolves to a blocked address")
+
+// Link handles web page links
+type Link struct {
+	options *types.Options
+}
+
+// Page is a fetched and cleaned web page
+type Page struct {
+	URL         string    `json:"url"`
+	Title       string    `json:"title,omitempty"`
+	Description string    `json:"description,omitempty"`
+	ContentType string    `json:"content_type"`
+	Text        string    `json:"text"`
+	Truncated   bool      `json:"truncated,omitempty"`
+	FetchedAt   time.Time `json:"fetched_at"`
+}
+
+// New creates a new link handler
+func New(options *types.Options) *Link {
+	return &Link{options: options}
+}
+
+// Parse fetches the linked page and returns it as a reference
+// The content part is replaced by a short text pointing at the reference, the page text goes to the reference
+func (h *Link) Parse(ctx *agentContext.Context, content agentContext.ContentPart) (agentContext.ContentPart, []*searchTypes.Reference, error) {
+	if content.Link == nil || content.Link.URL == "" {
+		return content, nil, fmt.Errorf("link content missing URL")
+	}
+
+	page, err := h.Fetch(ctx, content.Link.URL)
+	if err != nil {
+		return content, nil, err
+	}
+
+	title := page.Title
+	if content.Link.Title != "" {
+		title = content.Link.Title
+	}
+
+	ref := &searchTypes.Reference{
+		Type:    searchTypes.SearchTypeWeb,
+		Source:  searchTypes.SourceUser,
+		Weight:  1.0,
+		Score:   1.0,
+		Title:   title,
+		Content: page.Text,
+		URL:     page.URL,
+		Meta: map[string]interface{}{
+			"content_type": page.ContentType,
+			"fetched_at":   page.FetchedAt,
+			"truncated":    page.Truncated,
+		},
+	}
+	if page.Description != "" {
+		ref.Meta["description"] = page.Description
+	}
+
+	text := fmt.Sprintf("[Link: %s](%s)", title, page.URL)
+	if title == "" {
+		text = fmt.Sprintf("[Link](%s)", page.URL)
+	}
+	return agentContext.ContentPart{
+		Type: agentContext.ContentText,
+		Text: text,
+	}, []*searchTypes.Reference{ref}, nil
+}
+
+// Fallback returns a text part with the bare URL, used when the page cannot be fetched
+func Fallback(content agentContext.ContentPart) agentContext.ContentPart {
+	if content.Link == nil {
+		return agentContext.ContentPart{Type: agentContext.ContentText}
+	}
+	text := content.Link.URL
+	if content.Link.Title != "" {
+		text = fmt.Sprintf("%s (%s)", content.Link.Title, content.Link.URL)
+	}
+	return agentContext.ContentPart{Type: agentContext.ContentText, Text: text}
+}
+
+// Fetch returns the cleaned page of a URL, from the cache when available
+func (h *Link) Fetch(ctx *agentContext.Context, rawURL string) (*Page, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("invalid link %s: %w", rawURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported link scheme: %s", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid link %s: missing host", rawURL)
+	}
+	u.Fragment = ""
+	key := cacheKey(u.String())
+
+	if page, ok := readCache(ctx, key); ok {
+		return page, nil
+	}
+
+	page, err := fetch(ctx, u.String())
+	if err != nil {
+		return nil, err
+	}
+
+	saveCache(ctx, key, page)
+	return page, nil
+}
+
+// fetch downloads a page and converts it to readable text
+func fetch(ctx *agentContext.Context, pageURL string) (*Page, error) {
+	parent := context.Background()
+	if ctx != nil && ctx.Context != nil {
+		parent = ctx.Context
+	}
+	reqCtx, cancel := context.WithTimeout(parent, Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch link: %w", err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; YaoAgent/1.0)")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,text/plain;q=0.9,*/*;q=0.5")
+
+	resp, err := client().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch link: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to fetch link: %s returned %d", pageURL, resp.StatusCode)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "" {
+		mediaType = "text/html"
+	}
+
+	// Read one byte past the limit to detect oversized pages
+	body, err := charset.NewReader(io.LimitReader(resp.Body, MaxBytes+1), contentType)
+	if err != nil {
+		body = io.LimitReader(resp.Body, MaxBytes+1)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read link: %w", err)
+	}
+	truncated := false
+	if int64(len(data)) > MaxBytes {
+		data = data[:MaxBytes]
+		truncated = true
+	}
+
+	page := &Page{
+		URL:         resp.Request.URL.String(),
+		ContentType: mediaType,
+		FetchedAt:   time.Now(),
+	}
+
+	switch {
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		doc, err := html.Extract(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse link: %w", err)
+		}
+		page.Title, page.Description, page.Text = doc.Title, doc.Description, doc.Text
+
+	case strings.HasPrefix(mediaType, "text/") || mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		page.Text = strings.TrimSpace(strings.ToValidUTF8(string(data), ""))
+
+	default:
+		return nil, fmt.Errorf("unsupported link content type: %s", mediaType)
+	}
+
+	if page.Text == "" {
+		return nil, fmt.Errorf("no text content extracted from %s", pageURL)
+	}
+
+	if runes := []rune(page.Text); len(runes) > MaxChars {
+		page.Text = string(runes[:MaxChars])
+		truncated = true
+	}
+	page.Truncated = truncated
+	return page, nil
+}
+
+// client returns an HTTP client that refuses to connect to blocked addresses.
+// The check runs on the dialed address, so redirects and DNS rebinding are covered.
+func client() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			if AllowPrivate {
+				return nil
+			}
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isBlocked(ip) {
+				return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil
+
+	return &http.Client{
+		Timeout:   Timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return fmt.Errorf("too many redirects")
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("unsupported redirect scheme: %s", req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+}
+
+// isBlocked reports whether an IP is loopback, private, link-local or unspecified
+func isBlocked(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast()
+}
+
+// memoryCache is used when the context has no cache store
+var memoryCache = struct {
+	sync.Mutex
+	items map[string]memoryItem
+}{items: map[string]memoryItem{}}
+
+type memoryItem struct {
+	page    *Page
+	expires time.Time
+}
+
+// cacheKey returns the cache key of a URL
+func cacheKey(pageURL string) string {
+	sum := sha256.Sum256([]byte(pageURL))
+	return "link:" + hex.EncodeToString(sum[:])
+}
+
+// readCache reads a cached page
+func readCache(ctx *agentContext.Context, key string) (*Page, bool) {
+	if ctx != nil && ctx.Cache != nil {
+		value, ok := ctx.Cache.Get(key)
+		if !ok {
+			return nil, false
+		}
+		raw, ok := value.(string)
+		if !ok {
+			return nil, false
+		}
+		var page Page
+		if err := json.Unmarshal([]byte(raw), &page); err != nil {
+			return nil, false
+		}
+		return &page, true
+	}
+
+	memoryCache.Lock()
+	defer memoryCache.Unlock()
+	item, ok := memoryCache.items[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(item.expires) {
+		delete(memoryCache.items, key)
+		return nil, false
+	}
+	return item.page, true
+}
+
+// saveCache caches a page
+func saveCache(ctx *agentContext.Context, key string, page *Page) {
+	if ctx != nil && ctx.Cache != nil {
+		raw, err := json.Marshal(page)
+		if err != nil {
+			return
+		}
+		if err := ctx.Cache.Set(key, string(raw), CacheTTL); err != nil {
+			// Log warning but don't fail
+			fmt.Printf("Warning: failed to cache link: %v\n", err)
+		}
+		return
+	}
+
+	memoryCache.Lock()
+	defer memoryCache.Unlock()
+	now := time.Now()
+	for k, item := range memoryCache.items {
+		if now.After(item.expires) {
+			delete(memoryCache.items, k)
+		}
+	}
+	memoryCache.items[key] = memoryItem{page: page, expires: now.Add(CacheTTL)}
+}
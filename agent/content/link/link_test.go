@@ -0,0 +1,154 @@
+package link_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaoapp/yao/agent/content/link"
+	agentContext "github.com/yaoapp/yao/agent/context"
+	searchTypes "github.com/yaoapp/yao/agent/search/types"
+)
+
+const testPage = `<!DOCTYPE html>
+<html>
+<head>
+	<title>Fallback Title</title>
+	<meta property="og:title" content="Yao Release Notes">
+	<meta name="description" content="What is new in Yao">
+	<script>var tracking = "should not appear";</script>
+	<style>body { color: red; }</style>
+</head>
+<body>
+	<nav><a href="/">Home</a> <a href="/docs">Docs</a></nav>
+	<header>Site Header</header>
+	<article>
+		<h1>Release Notes</h1>
+		<p>Yao now parses <b>links</b> in the agent input and cites them as references.
+		The page is fetched, cleaned and cached for an hour so that repeated questions are fast.</p>
+		<ul><li>First item</li><li>Second item</li></ul>
+		<pre>go test ./...</pre>
+	</article>
+	<aside>Related posts</aside>
+	<footer>Copyright footer</footer>
+</body>
+</html>`
+
+func linkPart(url string) agentContext.ContentPart {
+	return agentContext.ContentPart{
+		Type: agentContext.ContentLink,
+		Link: &agentContext.LinkContent{URL: url},
+	}
+}
+
+func newServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	link.AllowPrivate = true
+	server := httptest.NewServer(handler)
+	t.Cleanup(func() {
+		server.Close()
+		link.AllowPrivate = false
+	})
+	return server
+}
+
+// TestParseHTML tests boilerplate removal and the returned reference
+func TestParseHTML(t *testing.T) {
+	server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, testPage)
+	})
+
+	part, refs, err := link.New(nil).Parse(nil, linkPart(server.URL+"/notes"))
+	require.NoError(t, err)
+	require.Len(t, refs, 1)
+
+	assert.Equal(t, agentContext.ContentText, part.Type)
+	assert.Contains(t, part.Text, "Yao Release Notes")
+
+	ref := refs[0]
+	assert.Equal(t, searchTypes.SearchTypeWeb, ref.Type)
+	assert.Equal(t, searchTypes.SourceUser, ref.Source)
+	assert.Equal(t, "Yao Release Notes", ref.Title)
+	assert.Equal(t, server.URL+"/notes", ref.URL)
+	assert.Equal(t, "What is new in Yao", ref.Meta["description"])
+
+	assert.Contains(t, ref.Content, "# Release Notes")
+	assert.Contains(t, ref.Content, "Yao now parses links in the agent input")
+	assert.Contains(t, ref.Content, "- First item")
+	assert.Contains(t, ref.Content, "go test ./...")
+	for _, noise := range []string{"tracking", "color: red", "Home", "Site Header", "Related posts", "Copyright footer"} {
+		assert.NotContains(t, ref.Content, noise)
+	}
+}
+
+// TestParseCache tests that a URL is fetched once
+func TestParseCache(t *testing.T) {
+	var hits int32
+	server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, "plain text page")
+	})
+
+	h := link.New(nil)
+	for i := 0; i < 3; i++ {
+		_, refs, err := h.Parse(nil, linkPart(server.URL+"/cached#section"))
+		require.NoError(t, err)
+		assert.Equal(t, "plain text page", refs[0].Content)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+}
+
+// TestParseSizeLimit tests that oversized pages are truncated
+func TestParseSizeLimit(t *testing.T) {
+	maxBytes, maxChars := link.MaxBytes, link.MaxChars
+	link.MaxBytes, link.MaxChars = 1000, 100
+	defer func() { link.MaxBytes, link.MaxChars = maxBytes, maxChars }()
+
+	server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, strings.Repeat("a", 5000))
+	})
+
+	_, refs, err := link.New(nil).Parse(nil, linkPart(server.URL+"/large"))
+	require.NoError(t, err)
+	assert.Len(t, refs[0].Content, 100)
+	assert.Equal(t, true, refs[0].Meta["truncated"])
+}
+
+// TestParseErrors tests unsupported and blocked links
+func TestParseErrors(t *testing.T) {
+	server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/image":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte{0x89, 'P', 'N', 'G'})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	h := link.New(nil)
+	_, _, err := h.Parse(nil, linkPart(server.URL+"/image"))
+	assert.Error(t, err)
+
+	_, _, err = h.Parse(nil, linkPart(server.URL+"/missing"))
+	assert.Error(t, err)
+
+	_, _, err = h.Parse(nil, linkPart("ftp://example.com/file"))
+	assert.Error(t, err)
+
+	// Loopback addresses are blocked by default
+	link.AllowPrivate = false
+	_, _, err = h.Parse(nil, linkPart(server.URL+"/blocked"))
+	assert.ErrorIs(t, err, link.ErrBlockedAddress)
+
+	fallback := link.Fallback(linkPart("https://example.com/page"))
+	assert.Equal(t, agentContext.ContentText, fallback.Type)
+	assert.Equal(t, "https://example.com/page", fallback.Text)
+}
@@ -0,0 +1,355 @@
+package sheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yaoapp/yao/agent/content/types"
+	agentContext "github.com/yaoapp/yao/agent/context"
+	searchTypes "github.com/yaoapp/yao/agent/search/types"
+	"github.com/yaoapp/yao/attachment"
+	"github.com/yaoapp/yao/excel"
+)
+
+// Default limits
+const (
+	DefaultMaxRows    = 200
+	DefaultMaxColumns = 50
+)
+
+// SupportedExtensions spreadsheet file extensions
+var SupportedExtensions = map[string]bool{
+	".xlsx": true,
+	".xlsm": true,
+	".csv":  true,
+	".tsv":  true,
+}
+
+// Sheet handles spreadsheet content (xlsx, csv)
+type Sheet struct {
+	options *types.Options
+}
+
+// Table is a sheet read with the row and column limits applied
+type Table struct {
+	Name      string
+	Rows      [][]string // the first row is the header
+	TotalRows int        // data rows in the sheet, header excluded
+	Columns   int        // columns in the sheet
+}
+
+// New creates a new spreadsheet handler
+func New(options *types.Options) *Sheet {
+	return &Sheet{options: options}
+}
+
+// IsSupportedExtension checks if a file extension is a spreadsheet
+func IsSupportedExtension(filename string) bool {
+	return SupportedExtensions[strings.ToLower(filepath.Ext(filename))]
+}
+
+// Parse parses spreadsheet content and returns the sheets as markdown tables
+func (h *Sheet) Parse(ctx *agentContext.Context, content agentContext.ContentPart) (agentContext.ContentPart, []*searchTypes.Reference, error) {
+	if content.File == nil || content.File.URL == "" {
+		return content, nil, fmt.Errorf("file content missing URL")
+	}
+
+	url := content.File.URL
+	filename := content.File.Filename
+
+	// The cached text is only valid for the default options
+	cacheable := h.options == nil || h.options.Sheet == nil
+	if cacheable {
+		cachedText, found, err := h.readFromCache(ctx, url)
+		if err == nil && found {
+			return agentContext.ContentPart{
+				Type: agentContext.ContentText,
+				Text: cachedText,
+			}, nil, nil
+		}
+	}
+
+	// Read spreadsheet file
+	data, err := h.readFile(ctx, url)
+	if err != nil {
+		return content, nil, fmt.Errorf("failed to read spreadsheet: %w", err)
+	}
+
+	var tables []*Table
+	switch ext := strings.ToLower(filepath.Ext(filename)); ext {
+	case ".csv", ".tsv":
+		comma := ','
+		if ext == ".tsv" {
+			comma = '\t'
+		}
+		table, err := h.ReadCSV(data, comma)
+		if err != nil {
+			return content, nil, fmt.Errorf("failed to parse CSV: %w", err)
+		}
+		table.Name = filename
+		tables = []*Table{table}
+
+	default:
+		tables, err = h.ReadWorkbook(data)
+		if err != nil {
+			return content, nil, fmt.Errorf("failed to parse spreadsheet: %w", err)
+		}
+	}
+
+	text := Markdown(filename, tables)
+	if text == "" {
+		return content, nil, fmt.Errorf("no data extracted from spreadsheet")
+	}
+
+	// Cache the result
+	if cacheable {
+		if err := h.saveToCache(ctx, url, text); err != nil {
+			// Log warning but don't fail
+			fmt.Printf("Warning: failed to cache spreadsheet text: %v\n", err)
+		}
+	}
+
+	return agentContext.ContentPart{
+		Type: agentContext.ContentText,
+		Text: text,
+	}, nil, nil
+}
+
+// ReadWorkbook reads the selected sheets of an xlsx workbook
+func (h *Sheet) ReadWorkbook(data []byte) ([]*Table, error) {
+	xls, err := excel.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer xls.Close()
+
+	maxRows, maxColumns := h.limits()
+	tables := []*Table{}
+	for _, name := range h.selectSheets(xls.ListSheets()) {
+		total, columns, err := xls.GetSheetDimension(name)
+		if err != nil {
+			return nil, err
+		}
+		rows, err := xls.ReadSheetRows(name, 0, maxRows+1)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, &Table{
+			Name:      name,
+			Rows:      limitColumns(rows, maxColumns),
+			TotalRows: max(total-1, 0),
+			Columns:   columns,
+		})
+	}
+	return tables, nil
+}
+
+// ReadCSV reads a CSV (or TSV) file
+func (h *Sheet) ReadCSV(data []byte, comma rune) (*Table, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	maxRows, maxColumns := h.limits()
+	table := &Table{}
+	rows := [][]string{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) > table.Columns {
+			table.Columns = len(record)
+		}
+		if len(rows) <= maxRows {
+			rows = append(rows, record)
+		}
+		table.TotalRows++
+	}
+
+	table.Rows = limitColumns(rows, maxColumns)
+	table.TotalRows = max(table.TotalRows-1, 0)
+	return table, nil
+}
+
+// limits returns the row and column limits
+func (h *Sheet) limits() (int, int) {
+	maxRows, maxColumns := DefaultMaxRows, DefaultMaxColumns
+	if h.options != nil && h.options.Sheet != nil {
+		if h.options.Sheet.MaxRows > 0 {
+			maxRows = h.options.Sheet.MaxRows
+		}
+		if h.options.Sheet.MaxColumns > 0 {
+			maxColumns = h.options.Sheet.MaxColumns
+		}
+	}
+	return maxRows, maxColumns
+}
+
+// selectSheets filters the sheets by the selected names (case insensitive), all sheets if none is selected
+func (h *Sheet) selectSheets(sheets []string) []string {
+	if h.options == nil || h.options.Sheet == nil || len(h.options.Sheet.Sheets) == 0 {
+		return sheets
+	}
+
+	selected := []string{}
+	for _, name := range sheets {
+		for _, want := range h.options.Sheet.Sheets {
+			if strings.EqualFold(name, want) {
+				selected = append(selected, name)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// Markdown renders the tables as markdown, one section per sheet
+func Markdown(filename string, tables []*Table) string {
+	var sb strings.Builder
+	if filename != "" {
+		sb.WriteString(fmt.Sprintf("File: %s\n", filename))
+	}
+
+	written := 0
+	for _, table := range tables {
+		if len(table.Rows) == 0 {
+			continue
+		}
+		written++
+
+		sb.WriteString(fmt.Sprintf("\n## %s\n\n", table.Name))
+		width := 0
+		for _, row := range table.Rows {
+			width = max(width, len(row))
+		}
+
+		for i, row := range table.Rows {
+			writeRow(&sb, row, width)
+			if i == 0 {
+				sb.WriteString("|" + strings.Repeat(" --- |", width) + "\n")
+			}
+		}
+
+		shownRows := len(table.Rows) - 1
+		if shownRows < table.TotalRows {
+			sb.WriteString(fmt.Sprintf("\n_Showing the first %d of %d rows._\n", shownRows, table.TotalRows))
+		}
+		if width < table.Columns {
+			sb.WriteString(fmt.Sprintf("\n_Showing the first %d of %d columns._\n", width, table.Columns))
+		}
+	}
+
+	if written == 0 {
+		return ""
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// writeRow writes one table row, padding the missing cells
+func writeRow(sb *strings.Builder, row []string, width int) {
+	sb.WriteString("|")
+	for i := 0; i < width; i++ {
+		cell := ""
+		if i < len(row) {
+			cell = row[i]
+		}
+		cell = strings.ReplaceAll(strings.TrimSpace(cell), "|", "\\|")
+		cell = strings.Join(strings.Fields(cell), " ")
+		sb.WriteString(" " + cell + " |")
+	}
+	sb.WriteString("\n")
+}
+
+// limitColumns truncates every row to max columns
+func limitColumns(rows [][]string, maxColumns int) [][]string {
+	for i, row := range rows {
+		if len(row) > maxColumns {
+			rows[i] = row[:maxColumns]
+		}
+	}
+	return rows
+}
+
+// readFile reads spreadsheet content from various sources
+func (h *Sheet) readFile(ctx *agentContext.Context, url string) ([]byte, error) {
+	if strings.HasPrefix(url, "__") {
+		return h.readFromUploader(ctx, url)
+	}
+
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("HTTP URL fetch not implemented yet: %s", url)
+	}
+
+	// Try to read as local file path
+	if _, err := os.Stat(url); err == nil {
+		return os.ReadFile(url)
+	}
+
+	return nil, fmt.Errorf("unsupported spreadsheet source: %s", url)
+}
+
+// readFromUploader reads spreadsheet content from file uploader
+func (h *Sheet) readFromUploader(ctx *agentContext.Context, wrapper string) ([]byte, error) {
+	uploaderName, fileID, ok := attachment.Parse(wrapper)
+	if !ok {
+		return nil, fmt.Errorf("invalid uploader wrapper format: %s", wrapper)
+	}
+
+	manager, exists := attachment.Managers[uploaderName]
+	if !exists {
+		return nil, fmt.Errorf("uploader '%s' not found", uploaderName)
+	}
+
+	data, err := manager.Read(ctx.Context, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	return data, nil
+}
+
+// readFromCache reads cached text content for a spreadsheet
+func (h *Sheet) readFromCache(ctx *agentContext.Context, url string) (string, bool, error) {
+	uploaderName, fileID, isWrapper := attachment.Parse(url)
+	if !isWrapper {
+		return "", false, nil
+	}
+
+	manager, exists := attachment.Managers[uploaderName]
+	if !exists {
+		return "", false, nil
+	}
+
+	text, err := manager.GetText(ctx.Context, fileID, false)
+	if err == nil && text != "" {
+		return text, true, nil
+	}
+
+	return "", false, nil
+}
+
+// saveToCache saves processed text to cache
+func (h *Sheet) saveToCache(ctx *agentContext.Context, url string, text string) error {
+	uploaderName, fileID, isWrapper := attachment.Parse(url)
+	if !isWrapper {
+		return nil
+	}
+
+	manager, exists := attachment.Managers[uploaderName]
+	if !exists {
+		return nil
+	}
+
+	return manager.SaveText(ctx.Context, fileID, text)
+}
@@ -0,0 +1,115 @@
+package sheet_test
+
+import (
+	stdContext "context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaoapp/yao/agent/content/sheet"
+	contentTypes "github.com/yaoapp/yao/agent/content/types"
+	agentContext "github.com/yaoapp/yao/agent/context"
+	"github.com/yaoapp/yao/excel"
+)
+
+func filePart(path string) agentContext.ContentPart {
+	return agentContext.ContentPart{
+		Type: agentContext.ContentFile,
+		File: &agentContext.FileAttachment{URL: path, Filename: filepath.Base(path)},
+	}
+}
+
+func writeWorkbook(t *testing.T) string {
+	xls, err := excel.New()
+	require.NoError(t, err)
+	defer xls.Close()
+
+	require.NoError(t, xls.UpdateSheet("Sheet1", [][]interface{}{
+		{"Name", "Price"},
+		{"Apple", 3},
+		{"Pipe | Fruit", 5},
+	}))
+	rows := [][]interface{}{{"ID", "Value"}}
+	for i := 1; i <= 30; i++ {
+		rows = append(rows, []interface{}{i, fmt.Sprintf("v%d", i)})
+	}
+	require.NoError(t, xls.UpdateSheet("Orders", rows))
+
+	path := filepath.Join(t.TempDir(), "data.xlsx")
+	require.NoError(t, xls.SaveAs(path))
+	return path
+}
+
+// TestParseWorkbook tests that every sheet becomes a markdown table
+func TestParseWorkbook(t *testing.T) {
+	ctx := agentContext.New(stdContext.Background(), nil, "test-chat")
+	part, refs, err := sheet.New(nil).Parse(ctx, filePart(writeWorkbook(t)))
+	require.NoError(t, err)
+	assert.Nil(t, refs)
+	assert.Equal(t, agentContext.ContentText, part.Type)
+
+	assert.Contains(t, part.Text, "File: data.xlsx")
+	assert.Contains(t, part.Text, "## Sheet1")
+	assert.Contains(t, part.Text, "| Name | Price |\n| --- | --- |\n| Apple | 3 |")
+	assert.Contains(t, part.Text, `| Pipe \| Fruit | 5 |`)
+	assert.Contains(t, part.Text, "## Orders")
+	assert.Contains(t, part.Text, "| 30 | v30 |")
+}
+
+// TestParseWorkbookOptions tests sheet selection and row limits
+func TestParseWorkbookOptions(t *testing.T) {
+	ctx := agentContext.New(stdContext.Background(), nil, "test-chat")
+	options := &contentTypes.Options{
+		Sheet: &contentTypes.SheetOptions{Sheets: []string{"orders"}, MaxRows: 10},
+	}
+
+	part, _, err := sheet.New(options).Parse(ctx, filePart(writeWorkbook(t)))
+	require.NoError(t, err)
+	assert.NotContains(t, part.Text, "## Sheet1")
+	assert.Contains(t, part.Text, "## Orders")
+	assert.Contains(t, part.Text, "| 10 | v10 |")
+	assert.NotContains(t, part.Text, "| 11 | v11 |")
+	assert.Contains(t, part.Text, "_Showing the first 10 of 30 rows._")
+
+	// No sheet matches
+	options.Sheet.Sheets = []string{"missing"}
+	_, _, err = sheet.New(options).Parse(ctx, filePart(writeWorkbook(t)))
+	assert.Error(t, err)
+}
+
+// TestParseCSV tests CSV and TSV files
+func TestParseCSV(t *testing.T) {
+	ctx := agentContext.New(stdContext.Background(), nil, "test-chat")
+	dir := t.TempDir()
+
+	csvPath := filepath.Join(dir, "users.csv")
+	require.NoError(t, os.WriteFile(csvPath, []byte("\xef\xbb\xbfname,email\nAda,\"ada@example.com\"\nBob\n"), 0644))
+	part, _, err := sheet.New(nil).Parse(ctx, filePart(csvPath))
+	require.NoError(t, err)
+	assert.Contains(t, part.Text, "| name | email |\n| --- | --- |\n| Ada | ada@example.com |\n| Bob |  |")
+
+	tsvPath := filepath.Join(dir, "wide.tsv")
+	header := make([]string, 60)
+	for i := range header {
+		header[i] = fmt.Sprintf("c%d", i)
+	}
+	require.NoError(t, os.WriteFile(tsvPath, []byte(strings.Join(header, "\t")+"\n"), 0644))
+	part, _, err = sheet.New(nil).Parse(ctx, filePart(tsvPath))
+	require.NoError(t, err)
+	assert.Contains(t, part.Text, "| c49 |")
+	assert.NotContains(t, part.Text, "c50")
+	assert.Contains(t, part.Text, "_Showing the first 50 of 60 columns._")
+}
+
+// TestIsSupportedExtension tests the IsSupportedExtension function
+func TestIsSupportedExtension(t *testing.T) {
+	assert.True(t, sheet.IsSupportedExtension("data.XLSX"))
+	assert.True(t, sheet.IsSupportedExtension("data.csv"))
+	assert.True(t, sheet.IsSupportedExtension("data.tsv"))
+	assert.False(t, sheet.IsSupportedExtension("data.xls"))
+	assert.False(t, sheet.IsSupportedExtension("data.txt"))
+}
@@ -23,4 +23,10 @@ type Options struct {
 
 	// SilentLoading, if true, suppress loading messages (used when called from parent handler)
 	SilentLoading bool
+
+	// Sheet, spreadsheet (xlsx, csv) parsing options, nil uses the defaults
+	Sheet *SheetOptions
 }
+
+// SheetOptions represents the spreadsheet parsing options
+type SheetOptions = agentContext.SheetOptions
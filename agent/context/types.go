@@ -323,6 +323,9 @@ type Options struct {
 	// Metadata for passing custom data to hooks (e.g., scenario selection)
 	Metadata map[string]any `json:"metadata,omitempty"` // Custom metadata passed to Create/Next hooks
 
+	// Sheet, spreadsheet (xlsx, csv) parsing options for the attached files, nil uses the defaults
+	Sheet *SheetOptions `json:"sheet,omitempty"` // Spreadsheet parsing options, a file's own options take priority
+
 	// HistorySize controls the max number of history messages loaded for LLM context.
 	// Priority: HistorySize > StoreSetting.MaxSize > default (20)
 	// 0 means use StoreSetting or default.
@@ -580,6 +583,7 @@ const (
 	ContentInputAudio ContentPartType = "input_audio" // Input audio content (Audio)
 	ContentFile       ContentPartType = "file"        // File attachment (documents, etc.)
 	ContentData       ContentPartType = "data"        // Generic data content (base64, binary, etc.)
+	ContentLink       ContentPartType = "link"        // Web page link (fetched and cited as a reference)
 )
 
 // ContentPart represents a part of the message content (for multimodal messages)
@@ -591,6 +595,7 @@ type ContentPart struct {
 	InputAudio *InputAudio     `json:"input_audio,omitempty"` // For type="input_audio": the input audio data
 	File       *FileAttachment `json:"file,omitempty"`        // For type="file": file attachment
 	Data       *DataContent    `json:"data,omitempty"`        // For type="data": generic data content
+	Link       *LinkContent    `json:"link,omitempty"`        // For type="link": web page link
 }
 
 // ImageDetailLevel represents the detail level for image processing
@@ -618,8 +623,23 @@ type InputAudio struct {
 // FileAttachment represents a file attachment in the message content
 // Compatible with frontend InputArea format: { type: 'file', file: { url, filename } }
 type FileAttachment struct {
-	URL      string `json:"url"`                // Required: URL of the file (http:// or __uploader://fileid wrapper)
-	Filename string `json:"filename,omitempty"` // Optional: original filename
+	URL      string        `json:"url"`                // Required: URL of the file (http:// or __uploader://fileid wrapper)
+	Filename string        `json:"filename,omitempty"` // Optional: original filename
+	Sheet    *SheetOptions `json:"sheet,omitempty"`    // Optional: spreadsheet parsing options for this file
+}
+
+// SheetOptions represents the spreadsheet parsing options
+type SheetOptions struct {
+	Sheets     []string `json:"sheets,omitempty"`      // Sheets to include, empty for all sheets
+	MaxRows    int      `json:"max_rows,omitempty"`    // Maximum data rows per sheet, 0 for the default (200)
+	MaxColumns int      `json:"max_columns,omitempty"` // Maximum columns per sheet, 0 for the default (50)
+}
+
+// LinkContent represents a web page link in the message content
+// The page is fetched, cleaned to readable text and added to the references
+type LinkContent struct {
+	URL   string `json:"url"`             // Required: http(s) URL of the page
+	Title string `json:"title,omitempty"` // Optional: title shown to the user
 }
 
 // DataSourceType represents the type of data source
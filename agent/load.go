@@ -175,12 +175,12 @@ func initStore() error {
 		return err
 
 	} else if conn.Is(connector.REDIS) {
-		agentDSL.Store = storeRedis.NewRedis()
-		return nil
+		agentDSL.Store, err = storeRedis.NewRedis(agentDSL.StoreSetting)
+		return err
 
 	} else if conn.Is(connector.MONGO) {
-		agentDSL.Store = storeMongo.NewMongo()
-		return nil
+		agentDSL.Store, err = storeMongo.NewMongo(agentDSL.StoreSetting)
+		return err
 	}
 
 	return fmt.Errorf("Agent store connector %s not support", agentDSL.StoreSetting.Connector)
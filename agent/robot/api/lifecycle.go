@@ -10,6 +10,7 @@ import (
 	dtadapter "github.com/yaoapp/yao/agent/robot/events/integrations/dingtalk"
 	dcadapter "github.com/yaoapp/yao/agent/robot/events/integrations/discord"
 	fsadapter "github.com/yaoapp/yao/agent/robot/events/integrations/feishu"
+	sladapter "github.com/yaoapp/yao/agent/robot/events/integrations/slack"
 	"github.com/yaoapp/yao/agent/robot/events/integrations/telegram"
 	weixinadapter "github.com/yaoapp/yao/agent/robot/events/integrations/weixin"
 	"github.com/yaoapp/yao/agent/robot/logger"
@@ -67,6 +68,7 @@ func Start() error {
 		"dingtalk": dtadapter.NewAdapter(),
 		"discord":  dcadapter.NewAdapter(),
 		"weixin":   weixinadapter.NewAdapter(),
+		"slack":    sladapter.NewAdapter(),
 	}
 	globalDispatcher = integrations.NewDispatcher(globalManager.Cache(), adapters)
 	if err := globalDispatcher.Start(context.Background()); err != nil {
@@ -1,34 +1,188 @@
 package dedup
 
 import (
+	"context"
 	"sync"
 	"time"
 
+	"github.com/yaoapp/yao/agent/robot/store"
 	"github.com/yaoapp/yao/agent/robot/types"
 )
 
+// MarkStore persists the dedup marks of a robot (implemented by store.RobotStore)
+type MarkStore interface {
+	GetDedupMarks(ctx context.Context, memberID string) ([]store.DedupMark, error)
+	UpdateDedupMarks(ctx context.Context, memberID string, marks []store.DedupMark) error
+}
+
 // Dedup implements types.Dedup interface
-// This is a stub implementation for Phase 2
+// Time-window dedup keyed by member, trigger type and a fingerprint of the trigger input:
+//   - same fingerprint within the window: DedupSkip
+//   - event of the same source and type while the marked execution is still running,
+//     with dedup.merge on: DedupMerge
+//   - otherwise: DedupProceed
+//
+// Marks are kept in memory and, with a MarkStore, saved with the robot and loaded
+// again after a restart.
 type Dedup struct {
-	marks map[string]time.Time // key -> expiry time
-	mu    sync.RWMutex
+	store  MarkStore
+	marks  map[string][]store.DedupMark // memberID -> marks
+	loaded map[string]bool              // memberID -> marks loaded from the store
+	mu     sync.Mutex
 }
 
-// New creates a new dedup instance
+// New creates a new in-memory dedup instance
 func New() *Dedup {
+	return NewWithStore(nil)
+}
+
+// NewWithStore creates a new dedup instance that persists its marks to s
+func NewWithStore(s MarkStore) *Dedup {
 	return &Dedup{
-		marks: make(map[string]time.Time),
+		store:  s,
+		marks:  make(map[string][]store.DedupMark),
+		loaded: make(map[string]bool),
 	}
 }
 
 // Check checks if execution should be deduplicated
-// Stub: always returns proceed (will be implemented in Phase 3)
-func (d *Dedup) Check(ctx *types.Context, memberID string, trigger types.TriggerType) (types.DedupResult, error) {
-	return types.DedupProceed, nil
+// Returns the ID of the matched execution for DedupSkip and DedupMerge
+func (d *Dedup) Check(ctx *types.Context, robot *types.Robot, trigger types.TriggerType, data interface{}) (types.DedupResult, string, error) {
+	if robot == nil {
+		return types.DedupProceed, "", nil
+	}
+	cfg := dedupConfig(robot)
+	if cfg.Window(trigger) <= 0 {
+		return types.DedupProceed, "", nil
+	}
+
+	fingerprint := Fingerprint(trigger, data)
+	group := Group(trigger, data)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	marks, err := d.load(ctx, robot.MemberID)
+	if err != nil {
+		return types.DedupProceed, "", err
+	}
+
+	now := time.Now()
+	merge := ""
+	for _, mark := range marks {
+		if mark.Trigger != trigger || !now.Before(mark.ExpiresAt) {
+			continue
+		}
+		if mark.Fingerprint == fingerprint {
+			return types.DedupSkip, mark.ExecutionID, nil
+		}
+		if merge == "" && group != "" && mark.Group == group && cfg.MergeEnabled() && isRunning(robot, mark.ExecutionID) {
+			merge = mark.ExecutionID
+		}
+	}
+
+	if merge != "" {
+		return types.DedupMerge, merge, nil
+	}
+	return types.DedupProceed, "", nil
 }
 
 // Mark marks an execution to prevent duplicates within window
-// Stub: does nothing (will be implemented in Phase 3)
-func (d *Dedup) Mark(memberID string, trigger types.TriggerType, window time.Duration) {
-	// Stub: no-op
+// Expired marks of the robot are dropped; the marks are saved when a store is set
+func (d *Dedup) Mark(ctx *types.Context, robot *types.Robot, trigger types.TriggerType, data interface{}, execID string) error {
+	if robot == nil {
+		return nil
+	}
+	window := dedupConfig(robot).Window(trigger)
+	if window <= 0 {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// A failed load leaves the in-memory marks, the mark still applies on this node
+	marks, loadErr := d.load(ctx, robot.MemberID)
+
+	now := time.Now()
+	kept := make([]store.DedupMark, 0, len(marks)+1)
+	for _, mark := range marks {
+		if now.Before(mark.ExpiresAt) {
+			kept = append(kept, mark)
+		}
+	}
+	kept = append(kept, store.DedupMark{
+		Trigger:     trigger,
+		Fingerprint: Fingerprint(trigger, data),
+		Group:       Group(trigger, data),
+		ExecutionID: execID,
+		ExpiresAt:   now.Add(window),
+	})
+	d.marks[robot.MemberID] = kept
+
+	if loadErr != nil {
+		return loadErr
+	}
+	if d.store != nil {
+		return d.store.UpdateDedupMarks(contextOf(ctx), robot.MemberID, kept)
+	}
+	return nil
+}
+
+// Forget drops the marks of a robot from memory (they are loaded again from the store)
+func (d *Dedup) Forget(memberID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.marks, memberID)
+	delete(d.loaded, memberID)
+}
+
+// load returns the marks of a robot, loading them from the store the first time
+// Must be called with d.mu held
+func (d *Dedup) load(ctx *types.Context, memberID string) ([]store.DedupMark, error) {
+	if d.store == nil || d.loaded[memberID] {
+		return d.marks[memberID], nil
+	}
+
+	saved, err := d.store.GetDedupMarks(contextOf(ctx), memberID)
+	if err != nil {
+		return d.marks[memberID], err
+	}
+
+	// Keep the marks made on this node while the store was unavailable
+	seen := map[string]bool{}
+	for _, mark := range d.marks[memberID] {
+		seen[mark.ExecutionID] = true
+	}
+	marks := d.marks[memberID]
+	for _, mark := range saved {
+		if !seen[mark.ExecutionID] {
+			marks = append(marks, mark)
+		}
+	}
+	d.marks[memberID] = marks
+	d.loaded[memberID] = true
+	return marks, nil
+}
+
+// dedupConfig returns the dedup config of a robot, nil for the defaults
+func dedupConfig(robot *types.Robot) *types.DedupConfig {
+	if robot.Config == nil {
+		return nil
+	}
+	return robot.Config.Dedup
+}
+
+// isRunning checks whether an execution is still tracked as pending or running on the robot
+func isRunning(robot *types.Robot, execID string) bool {
+	exec := robot.GetExecution(execID)
+	return exec != nil && (exec.Status == types.ExecPending || exec.Status == types.ExecRunning)
+}
+
+// contextOf returns the standard context of a robot context
+func contextOf(ctx *types.Context) context.Context {
+	if ctx == nil || ctx.Context == nil {
+		return context.Background()
+	}
+	return ctx.Context
 }
@@ -0,0 +1,206 @@
+package dedup_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaoapp/yao/agent/robot/dedup"
+	"github.com/yaoapp/yao/agent/robot/store"
+	"github.com/yaoapp/yao/agent/robot/types"
+)
+
+func TestDedupSkip(t *testing.T) {
+	d := dedup.New()
+	ctx := types.NewContext(context.Background(), nil)
+	robot := newRobot("robot_dedup_skip", &types.DedupConfig{})
+
+	event := eventInput("order.paid", map[string]interface{}{"order_id": 1})
+
+	result, _, err := d.Check(ctx, robot, types.TriggerEvent, event)
+	require.NoError(t, err)
+	assert.Equal(t, types.DedupProceed, result)
+	require.NoError(t, d.Mark(ctx, robot, types.TriggerEvent, event, "exec_1"))
+
+	// Same event again
+	result, matched, err := d.Check(ctx, robot, types.TriggerEvent, eventInput("order.paid", map[string]interface{}{"order_id": 1}))
+	require.NoError(t, err)
+	assert.Equal(t, types.DedupSkip, result)
+	assert.Equal(t, "exec_1", matched)
+
+	// Another payload, another robot, another trigger type
+	result, _, _ = d.Check(ctx, robot, types.TriggerEvent, eventInput("order.paid", map[string]interface{}{"order_id": 2}))
+	assert.Equal(t, types.DedupProceed, result)
+	result, _, _ = d.Check(ctx, newRobot("robot_dedup_other", nil), types.TriggerEvent, event)
+	assert.Equal(t, types.DedupProceed, result)
+	result, _, _ = d.Check(ctx, robot, types.TriggerClock, types.NewClockContext(time.Now(), ""))
+	assert.Equal(t, types.DedupProceed, result)
+}
+
+func TestDedupWindowExpiry(t *testing.T) {
+	d := dedup.New()
+	ctx := types.NewContext(context.Background(), nil)
+	robot := newRobot("robot_dedup_expiry", &types.DedupConfig{Event: "50ms"})
+	event := eventInput("lead.created", map[string]interface{}{"email": "john@example.com"})
+
+	require.NoError(t, d.Mark(ctx, robot, types.TriggerEvent, event, "exec_1"))
+	result, _, _ := d.Check(ctx, robot, types.TriggerEvent, event)
+	assert.Equal(t, types.DedupSkip, result)
+
+	time.Sleep(80 * time.Millisecond)
+	result, _, _ = d.Check(ctx, robot, types.TriggerEvent, event)
+	assert.Equal(t, types.DedupProceed, result)
+}
+
+func TestDedupDisabled(t *testing.T) {
+	d := dedup.New()
+	ctx := types.NewContext(context.Background(), nil)
+
+	// Human dedup is off by default
+	robot := newRobot("robot_dedup_human", nil)
+	input := &types.TriggerInput{Action: types.ActionTaskAdd}
+	require.NoError(t, d.Mark(ctx, robot, types.TriggerHuman, input, "exec_1"))
+	result, _, _ := d.Check(ctx, robot, types.TriggerHuman, input)
+	assert.Equal(t, types.DedupProceed, result)
+
+	// Event dedup turned off
+	robot = newRobot("robot_dedup_off", &types.DedupConfig{Event: "0"})
+	event := eventInput("ping", nil)
+	require.NoError(t, d.Mark(ctx, robot, types.TriggerEvent, event, "exec_2"))
+	result, _, _ = d.Check(ctx, robot, types.TriggerEvent, event)
+	assert.Equal(t, types.DedupProceed, result)
+}
+
+func TestDedupClockTick(t *testing.T) {
+	d := dedup.New()
+	ctx := types.NewContext(context.Background(), nil)
+	robot := newRobot("robot_dedup_clock", nil)
+
+	tick := time.Date(2025, 1, 15, 9, 0, 10, 0, time.UTC)
+	require.NoError(t, d.Mark(ctx, robot, types.TriggerClock, types.NewClockContext(tick, ""), "exec_1"))
+
+	// Same tick minute
+	result, _, _ := d.Check(ctx, robot, types.TriggerClock, types.NewClockContext(tick.Add(30*time.Second), ""))
+	assert.Equal(t, types.DedupSkip, result)
+
+	// Next tick
+	result, _, _ = d.Check(ctx, robot, types.TriggerClock, types.NewClockContext(tick.Add(time.Minute), ""))
+	assert.Equal(t, types.DedupProceed, result)
+}
+
+func TestDedupMerge(t *testing.T) {
+	d := dedup.New()
+	ctx := types.NewContext(context.Background(), nil)
+	robot := newRobot("robot_dedup_merge", &types.DedupConfig{Merge: true})
+	robot.AddExecution(&types.Execution{ID: "exec_1", MemberID: robot.MemberID, Status: types.ExecRunning})
+
+	require.NoError(t, d.Mark(ctx, robot, types.TriggerEvent, eventInput("lead.created", map[string]interface{}{"id": 1}), "exec_1"))
+
+	// New payload of the same event type while exec_1 is running
+	next := eventInput("lead.created", map[string]interface{}{"id": 2})
+	result, matched, err := d.Check(ctx, robot, types.TriggerEvent, next)
+	require.NoError(t, err)
+	assert.Equal(t, types.DedupMerge, result)
+	assert.Equal(t, "exec_1", matched)
+
+	// Other event types are not merged
+	result, _, _ = d.Check(ctx, robot, types.TriggerEvent, eventInput("lead.updated", map[string]interface{}{"id": 2}))
+	assert.Equal(t, types.DedupProceed, result)
+
+	// Not merged once the execution is done
+	robot.RemoveExecution("exec_1")
+	result, _, _ = d.Check(ctx, robot, types.TriggerEvent, next)
+	assert.Equal(t, types.DedupProceed, result)
+
+	// Not merged without dedup.merge
+	plain := newRobot("robot_dedup_nomerge", nil)
+	plain.AddExecution(&types.Execution{ID: "exec_2", MemberID: plain.MemberID, Status: types.ExecRunning})
+	require.NoError(t, d.Mark(ctx, plain, types.TriggerEvent, eventInput("lead.created", map[string]interface{}{"id": 1}), "exec_2"))
+	result, _, _ = d.Check(ctx, plain, types.TriggerEvent, next)
+	assert.Equal(t, types.DedupProceed, result)
+}
+
+func TestDedupPersistence(t *testing.T) {
+	ms := newMemoryStore()
+	ctx := types.NewContext(context.Background(), nil)
+	robot := newRobot("robot_dedup_persist", nil)
+	event := eventInput("order.paid", map[string]interface{}{"order_id": 1})
+
+	before := dedup.NewWithStore(ms)
+	require.NoError(t, before.Mark(ctx, robot, types.TriggerEvent, event, "exec_1"))
+	assert.Len(t, ms.marks[robot.MemberID], 1)
+
+	// A new instance (after a restart) loads the marks from the store
+	after := dedup.NewWithStore(ms)
+	result, matched, err := after.Check(ctx, robot, types.TriggerEvent, event)
+	require.NoError(t, err)
+	assert.Equal(t, types.DedupSkip, result)
+	assert.Equal(t, "exec_1", matched)
+
+	// Expired marks are dropped on the next save
+	ms.marks[robot.MemberID][0].ExpiresAt = time.Now().Add(-time.Second)
+	after.Forget(robot.MemberID)
+	require.NoError(t, after.Mark(ctx, robot, types.TriggerEvent, eventInput("order.paid", map[string]interface{}{"order_id": 2}), "exec_2"))
+	require.Len(t, ms.marks[robot.MemberID], 1)
+	assert.Equal(t, "exec_2", ms.marks[robot.MemberID][0].ExecutionID)
+
+	// A failing store does not block the trigger
+	ms.err = fmt.Errorf("database unavailable")
+	failing := dedup.NewWithStore(ms)
+	result, _, err = failing.Check(ctx, robot, types.TriggerEvent, event)
+	assert.Error(t, err)
+	assert.Equal(t, types.DedupProceed, result)
+}
+
+func TestFingerprint(t *testing.T) {
+	a := dedup.Fingerprint(types.TriggerEvent, eventInput("order.paid", map[string]interface{}{"a": 1, "b": "x"}))
+	b := dedup.Fingerprint(types.TriggerEvent, &types.EventRequest{Source: "webhook", EventType: "order.paid", Data: map[string]interface{}{"b": "x", "a": 1}})
+	c := dedup.Fingerprint(types.TriggerEvent, eventInput("order.paid", map[string]interface{}{"a": 2, "b": "x"}))
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+
+	assert.Equal(t, "webhook:order.paid", dedup.Group(types.TriggerEvent, eventInput("order.paid", nil)))
+	assert.Equal(t, "", dedup.Group(types.TriggerClock, nil))
+}
+
+// ==================== Helpers ====================
+
+func newRobot(memberID string, cfg *types.DedupConfig) *types.Robot {
+	return &types.Robot{
+		MemberID: memberID,
+		TeamID:   "team_dedup",
+		Config:   &types.Config{Dedup: cfg, Quota: &types.Quota{Max: 5}},
+	}
+}
+
+func eventInput(eventType string, data map[string]interface{}) *types.TriggerInput {
+	return &types.TriggerInput{Source: types.EventWebhook, EventType: eventType, Data: data}
+}
+
+// memoryStore is an in-memory dedup.MarkStore
+type memoryStore struct {
+	marks map[string][]store.DedupMark
+	err   error
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{marks: map[string][]store.DedupMark{}}
+}
+
+func (s *memoryStore) GetDedupMarks(ctx context.Context, memberID string) ([]store.DedupMark, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return append([]store.DedupMark(nil), s.marks[memberID]...), nil
+}
+
+func (s *memoryStore) UpdateDedupMarks(ctx context.Context, memberID string, marks []store.DedupMark) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.marks[memberID] = append([]store.DedupMark(nil), marks...)
+	return nil
+}
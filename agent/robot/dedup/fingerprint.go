@@ -0,0 +1,85 @@
+package dedup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/yaoapp/yao/agent/robot/types"
+)
+
+// Fingerprint returns the content fingerprint of a trigger input
+//   - clock: the minute of the tick, so one tick runs once
+//   - human: action, user and messages
+//   - event: source, event type and payload
+//
+// Map keys are sorted by encoding/json, so equal payloads have equal fingerprints.
+func Fingerprint(trigger types.TriggerType, data interface{}) string {
+	var content interface{}
+	switch trigger {
+	case types.TriggerClock:
+		content = clockMinute(data)
+
+	case types.TriggerHuman:
+		switch v := data.(type) {
+		case *types.TriggerInput:
+			content = []interface{}{v.Action, v.UserID, v.Messages}
+		case *types.InterveneRequest:
+			content = []interface{}{v.Action, "", v.Messages}
+		default:
+			content = data
+		}
+
+	case types.TriggerEvent:
+		switch v := data.(type) {
+		case *types.TriggerInput:
+			content = []interface{}{v.Source, v.EventType, v.Data}
+		case *types.EventRequest:
+			content = []interface{}{v.Source, v.EventType, v.Data}
+		default:
+			content = data
+		}
+
+	default:
+		content = data
+	}
+
+	raw, err := json.Marshal(content)
+	if err != nil {
+		raw = []byte(fmt.Sprintf("%v", content))
+	}
+	sum := sha256.Sum256(append([]byte(string(trigger)+":"), raw...))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Group returns the merge group of a trigger input: "source:event_type" for events, empty otherwise
+func Group(trigger types.TriggerType, data interface{}) string {
+	if trigger != types.TriggerEvent {
+		return ""
+	}
+	switch v := data.(type) {
+	case *types.TriggerInput:
+		return fmt.Sprintf("%s:%s", v.Source, v.EventType)
+	case *types.EventRequest:
+		return fmt.Sprintf("%s:%s", v.Source, v.EventType)
+	}
+	return ""
+}
+
+// clockMinute returns the tick minute of a clock trigger input
+func clockMinute(data interface{}) string {
+	now := time.Now()
+	switch v := data.(type) {
+	case *types.ClockContext:
+		if v != nil {
+			now = v.Now
+		}
+	case *types.TriggerInput:
+		if v != nil && v.Clock != nil {
+			now = v.Clock.Now
+		}
+	}
+	return now.UTC().Truncate(time.Minute).Format(time.RFC3339)
+}
@@ -21,9 +21,10 @@ import (
 	eventtypes "github.com/yaoapp/yao/event/types"
 	"github.com/yaoapp/yao/messenger"
 	messengerTypes "github.com/yaoapp/yao/messenger/types"
+	"github.com/yaoapp/yao/notification"
 )
 
-// handleDelivery routes delivery content to configured channels (email, webhook, process).
+// handleDelivery routes delivery content to configured channels (email, webhook, process, notify).
 func (h *robotHandler) handleDelivery(ctx context.Context, ev *eventtypes.Event, resp chan<- eventtypes.Result) {
 	var payload DeliveryPayload
 	if err := ev.Should(&payload); err != nil {
@@ -91,6 +92,16 @@ func (h *robotHandler) handleDelivery(ctx context.Context, ev *eventtypes.Event,
 		}
 	}
 
+	if prefs.Notify != nil && prefs.Notify.Enabled {
+		for _, target := range prefs.Notify.Targets {
+			r := h.sendNotification(ctx, content, target, deliveryCtx)
+			results = append(results, r)
+			if !r.Success && lastErr == nil {
+				lastErr = fmt.Errorf("notify delivery failed: %s", r.Error)
+			}
+		}
+	}
+
 	// Push delivery to integration channels only when the task originated from one
 	if reply := getReplyFunc(); reply != nil && payload.ChatID != "" {
 		channel, chatID := splitChannelChatID(payload.ChatID)
@@ -385,6 +396,54 @@ func (h *robotHandler) callProcess(
 	return result
 }
 
+// ============================================================================
+// Notify
+// ============================================================================
+
+func (h *robotHandler) sendNotification(
+	ctx context.Context,
+	content *robottypes.DeliveryContent,
+	target robottypes.NotifyTarget,
+	deliveryCtx *robottypes.DeliveryContext,
+) robottypes.ChannelResult {
+	now := time.Now()
+	result := robottypes.ChannelResult{
+		Type:   robottypes.DeliveryNotify,
+		Target: target.UserID,
+		SentAt: &now,
+	}
+
+	data := map[string]interface{}{
+		"execution_id": deliveryCtx.ExecutionID,
+		"member_id":    deliveryCtx.MemberID,
+	}
+	if len(content.Attachments) > 0 {
+		data["attachments"] = content.Attachments
+	}
+
+	n, err := notification.Send(ctx, &notification.Notification{
+		UserID:   target.UserID,
+		TeamID:   deliveryCtx.TeamID,
+		Type:     Delivery,
+		Title:    buildEmailSubject("", "", content, deliveryCtx),
+		Content:  content.Body,
+		Link:     target.Link,
+		Data:     data,
+		Source:   notification.SourceRobot,
+		SourceID: deliveryCtx.ExecutionID,
+		SenderID: deliveryCtx.MemberID,
+	})
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Success = true
+	result.Recipients = []string{target.UserID}
+	result.Details = map[string]interface{}{"notification_id": n.NotificationID}
+	return result
+}
+
 // ============================================================================
 // Helpers
 // ============================================================================
@@ -62,6 +62,7 @@ const (
 	TaskNeedInput = "robot.task.need_input"
 	TaskFailed    = "robot.task.failed"
 	TaskCompleted = "robot.task.completed"
+	TaskOutput    = "robot.task.output"
 	ExecWaiting   = "robot.exec.waiting"
 	ExecResumed   = "robot.exec.resumed"
 	ExecCompleted = "robot.exec.completed"
@@ -109,6 +110,18 @@ type TaskPayload struct {
 	ChatID      string `json:"chat_id,omitempty"`
 }
 
+// TaskOutputPayload is the event payload for TaskOutput events, one per output line
+// of a task running in a sandbox.
+type TaskOutputPayload struct {
+	ExecutionID string `json:"execution_id"`
+	MemberID    string `json:"member_id"`
+	TeamID      string `json:"team_id"`
+	TaskID      string `json:"task_id"`
+	Stream      string `json:"stream"` // "stdout" or "stderr"
+	Line        string `json:"line"`
+	ChatID      string `json:"chat_id,omitempty"`
+}
+
 // DeliveryPayload is the event payload for Delivery events.
 type DeliveryPayload struct {
 	ExecutionID string                          `json:"execution_id"`
@@ -12,7 +12,10 @@ import (
 func init() {
 	event.Register("robot", &robotHandler{
 		httpClient: &http.Client{Timeout: 30 * time.Second},
-	})
+	}, event.AutoAck(),
+		event.Payload(Delivery, DeliveryPayload{}),
+		event.Payload(Message, MessagePayload{}),
+	)
 }
 
 // robotHandler processes all robot.* events.
@@ -142,6 +142,9 @@ func parseIntegrations(intg *robottypes.Integrations) []string {
 	if intg.Weixin != nil {
 		keys = append(keys, "weixin")
 	}
+	if intg.Slack != nil {
+		keys = append(keys, "slack")
+	}
 	return keys
 }
 
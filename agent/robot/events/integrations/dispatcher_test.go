@@ -278,6 +278,10 @@ func TestParseIntegrations(t *testing.T) {
 		{"telegram only", &robottypes.Integrations{
 			Telegram: &robottypes.TelegramConfig{Enabled: true},
 		}, []string{"telegram"}},
+		{"telegram and slack", &robottypes.Integrations{
+			Telegram: &robottypes.TelegramConfig{Enabled: true},
+			Slack:    &robottypes.SlackConfig{Enabled: true},
+		}, []string{"telegram", "slack"}},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,151 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	agentcontext "github.com/yaoapp/yao/agent/context"
+	events "github.com/yaoapp/yao/agent/robot/events"
+	"github.com/yaoapp/yao/event"
+	slackapi "github.com/yaoapp/yao/integrations/slack"
+)
+
+// handleEnvelope converts an Events API envelope received over Socket Mode or
+// the webhook and hands the message over to handleMessages.
+func (a *Adapter) handleEnvelope(ctx context.Context, entry *botEntry, env *slackapi.EventsAPIEnvelope) {
+	cm := slackapi.ConvertEnvelope(env)
+	if cm == nil || cm.IsBot {
+		return
+	}
+
+	if cm.HasMedia() {
+		groups := []string{"slack", entry.robotID}
+		entry.bot.ResolveMedia(ctx, cm, groups)
+	}
+
+	a.handleMessages(ctx, entry, []*slackapi.ConvertedMessage{cm})
+}
+
+// handleMessages builds a single event payload from a batch of ConvertedMessages
+// belonging to the same channel.
+func (a *Adapter) handleMessages(ctx context.Context, entry *botEntry, cms []*slackapi.ConvertedMessage) {
+	if len(cms) == 0 {
+		return
+	}
+
+	var allParts []interface{}
+	var lastCM *slackapi.ConvertedMessage
+
+	for _, cm := range cms {
+		if cm == nil {
+			continue
+		}
+
+		// Skip slash commands typed as messages
+		if strings.HasPrefix(strings.TrimSpace(cm.Text), "/") && !cm.HasMedia() {
+			continue
+		}
+
+		// A mention arrives both as message and app_mention, the ts identifies the message
+		dedupKey := fmt.Sprintf("slack:%s:%s:%s", entry.robotID, cm.Channel, cm.TS)
+		if !a.dedup.markSeen(dedupKey) {
+			continue
+		}
+
+		parts := buildContentParts(cm)
+		if len(parts) == 0 {
+			continue
+		}
+
+		allParts = append(allParts, parts...)
+		lastCM = cm
+	}
+
+	if len(allParts) == 0 || lastCM == nil {
+		return
+	}
+
+	content := mergeContentParts(allParts)
+
+	msgPayload := events.MessagePayload{
+		RobotID: entry.robotID,
+		Messages: []agentcontext.Message{
+			{Role: agentcontext.RoleUser, Content: content},
+		},
+		Metadata: &events.MessageMetadata{
+			Channel:   "slack",
+			MessageID: lastCM.TS,
+			AppID:     entry.appID,
+			ChatID:    lastCM.Channel,
+			SenderID:  lastCM.UserID,
+			Locale:    "en", // Slack events carry no locale
+			Extra: map[string]any{
+				"slack_ts":        lastCM.TS,
+				"slack_thread_ts": lastCM.ReplyThreadTS(),
+				"team_id":         lastCM.TeamID,
+				"is_dm":           lastCM.IsDM,
+				"sender_id":       lastCM.UserID,
+				"app_id":          entry.appID,
+			},
+		},
+	}
+
+	if _, err := event.Push(ctx, events.Message, msgPayload); err != nil {
+		log.Error("slack adapter: event.Push robot.message failed robot=%s: %v", entry.robotID, err)
+	}
+}
+
+// buildContentParts extracts content parts from a single ConvertedMessage.
+func buildContentParts(cm *slackapi.ConvertedMessage) []interface{} {
+	var parts []interface{}
+
+	if cm.HasText() {
+		parts = append(parts, map[string]interface{}{
+			"type": "text",
+			"text": cm.Text,
+		})
+	}
+
+	for _, mi := range cm.MediaItems {
+		if mi.Wrapper == "" {
+			continue
+		}
+		parts = append(parts, map[string]interface{}{
+			"type":      "file",
+			"file_url":  mi.Wrapper,
+			"mime_type": mi.MimeType,
+			"file_name": mi.FileName,
+		})
+	}
+
+	return parts
+}
+
+// mergeContentParts merges collected parts into a single content value.
+// If all parts are text-only, they are joined with newlines into a plain string.
+// Otherwise the full parts array is returned.
+func mergeContentParts(parts []interface{}) interface{} {
+	allText := true
+	for _, p := range parts {
+		m, ok := p.(map[string]interface{})
+		if !ok || m["type"] != "text" {
+			allText = false
+			break
+		}
+	}
+
+	if allText {
+		var buf strings.Builder
+		for i, p := range parts {
+			if i > 0 {
+				buf.WriteString("\n")
+			}
+			m := p.(map[string]interface{})
+			buf.WriteString(m["text"].(string))
+		}
+		return buf.String()
+	}
+
+	return parts
+}
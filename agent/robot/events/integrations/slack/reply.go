@@ -0,0 +1,172 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	agentcontext "github.com/yaoapp/yao/agent/context"
+	events "github.com/yaoapp/yao/agent/robot/events"
+	slackapi "github.com/yaoapp/yao/integrations/slack"
+)
+
+// Reply sends the assistant message back to the originating Slack channel,
+// in the thread of the message it answers.
+func (a *Adapter) Reply(ctx context.Context, msg *agentcontext.Message, metadata *events.MessageMetadata) error {
+	if msg == nil || metadata == nil {
+		return fmt.Errorf("nil message or metadata")
+	}
+
+	entry := a.resolveByChat(metadata)
+	if entry == nil {
+		return fmt.Errorf("no bot registered for slack metadata (appID=%s)", metadata.AppID)
+	}
+
+	var threadTS string
+	if metadata.Extra != nil {
+		threadTS, _ = metadata.Extra["slack_thread_ts"].(string)
+	}
+
+	return a.sendContent(ctx, entry.bot, metadata.ChatID, threadTS, msg.Content)
+}
+
+// sendContent dispatches based on the Content type.
+func (a *Adapter) sendContent(ctx context.Context, bot *slackapi.Bot, channel, threadTS string, content interface{}) error {
+	switch c := content.(type) {
+	case string:
+		if strings.TrimSpace(c) == "" {
+			return nil
+		}
+		_, err := bot.SendMessage(ctx, channel, c, threadTS)
+		return err
+
+	case []interface{}:
+		return a.sendParts(ctx, bot, channel, threadTS, c)
+
+	default:
+		parts, ok := toContentParts(content)
+		if ok {
+			return a.sendPartsTyped(ctx, bot, channel, threadTS, parts)
+		}
+		_, err := bot.SendMessage(ctx, channel, fmt.Sprintf("%v", content), threadTS)
+		return err
+	}
+}
+
+// sendParts handles []interface{} content parts (common from JSON unmarshalling).
+func (a *Adapter) sendParts(ctx context.Context, bot *slackapi.Bot, channel, threadTS string, parts []interface{}) error {
+	var textBuf strings.Builder
+	for _, part := range parts {
+		m, ok := part.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		partType, _ := m["type"].(string)
+		switch partType {
+		case "text":
+			if text, ok := m["text"].(string); ok {
+				textBuf.WriteString(text)
+			}
+		case "image_url":
+			if err := a.flushText(ctx, bot, channel, threadTS, &textBuf); err != nil {
+				return err
+			}
+			if imgMap, ok := m["image_url"].(map[string]interface{}); ok {
+				if url, ok := imgMap["url"].(string); ok {
+					if err := sendFileOrWrapper(ctx, bot, channel, threadTS, url, ""); err != nil {
+						log.Error("slack reply: send image: %v", err)
+					}
+				}
+			}
+		case "file":
+			if err := a.flushText(ctx, bot, channel, threadTS, &textBuf); err != nil {
+				return err
+			}
+			fileURL, _ := m["file_url"].(string)
+			filename, _ := m["file_name"].(string)
+			if fileMap, ok := m["file"].(map[string]interface{}); ok {
+				fileURL, _ = fileMap["url"].(string)
+				filename, _ = fileMap["filename"].(string)
+			}
+			if fileURL != "" {
+				if err := sendFileOrWrapper(ctx, bot, channel, threadTS, fileURL, filename); err != nil {
+					log.Error("slack reply: send file: %v", err)
+				}
+			}
+		}
+	}
+	return a.flushText(ctx, bot, channel, threadTS, &textBuf)
+}
+
+// sendPartsTyped handles typed []agentcontext.ContentPart slices.
+func (a *Adapter) sendPartsTyped(ctx context.Context, bot *slackapi.Bot, channel, threadTS string, parts []agentcontext.ContentPart) error {
+	var textBuf strings.Builder
+	for _, part := range parts {
+		switch part.Type {
+		case agentcontext.ContentText:
+			textBuf.WriteString(part.Text)
+		case agentcontext.ContentImageURL:
+			if err := a.flushText(ctx, bot, channel, threadTS, &textBuf); err != nil {
+				return err
+			}
+			if part.ImageURL != nil {
+				if err := sendFileOrWrapper(ctx, bot, channel, threadTS, part.ImageURL.URL, ""); err != nil {
+					log.Error("slack reply: send image: %v", err)
+				}
+			}
+		case agentcontext.ContentFile:
+			if err := a.flushText(ctx, bot, channel, threadTS, &textBuf); err != nil {
+				return err
+			}
+			if part.File != nil {
+				if err := sendFileOrWrapper(ctx, bot, channel, threadTS, part.File.URL, part.File.Filename); err != nil {
+					log.Error("slack reply: send file: %v", err)
+				}
+			}
+		}
+	}
+	return a.flushText(ctx, bot, channel, threadTS, &textBuf)
+}
+
+func (a *Adapter) flushText(ctx context.Context, bot *slackapi.Bot, channel, threadTS string, buf *strings.Builder) error {
+	if buf.Len() == 0 {
+		return nil
+	}
+	_, err := bot.SendMessage(ctx, channel, buf.String(), threadTS)
+	buf.Reset()
+	return err
+}
+
+// sendFileOrWrapper uploads a file from a wrapper (__yao.attachment://xxx),
+// public URLs are posted as links and unfurled by Slack.
+func sendFileOrWrapper(ctx context.Context, bot *slackapi.Bot, channel, threadTS, url, filename string) error {
+	if strings.Contains(url, "://") && !strings.HasPrefix(url, "http") {
+		return bot.SendMedia(ctx, channel, threadTS, url, filename)
+	}
+	if strings.HasPrefix(url, "http") {
+		_, err := bot.SendMessage(ctx, channel, url, threadTS)
+		return err
+	}
+	return fmt.Errorf("unsupported file URL scheme: %s", url)
+}
+
+// toContentParts tries to type-assert content to []agentcontext.ContentPart.
+func toContentParts(content interface{}) ([]agentcontext.ContentPart, bool) {
+	parts, ok := content.([]agentcontext.ContentPart)
+	return parts, ok
+}
+
+// resolveByChat finds the bot entry matching the metadata.
+func (a *Adapter) resolveByChat(metadata *events.MessageMetadata) *botEntry {
+	if metadata.AppID != "" {
+		if entry, ok := a.resolveByAppID(metadata.AppID); ok {
+			return entry
+		}
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	for _, entry := range a.bots {
+		return entry
+	}
+	return nil
+}
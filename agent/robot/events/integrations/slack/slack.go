@@ -0,0 +1,163 @@
+package slack
+
+import (
+	"context"
+	"sync"
+
+	"github.com/yaoapp/yao/agent/robot/logger"
+	robottypes "github.com/yaoapp/yao/agent/robot/types"
+	slackapi "github.com/yaoapp/yao/integrations/slack"
+	webhooktypes "github.com/yaoapp/yao/openapi/integrations"
+)
+
+var log = logger.New("slack")
+
+// Adapter implements the integrations.Adapter interface for Slack.
+//
+// Architecture:
+//   - One Socket Mode connection per bot configured with an app token
+//   - One webhook goroutine listens to integration.webhook.slack events (Events API)
+//   - One dedup cleaner goroutine removes expired keys every hour
+type Adapter struct {
+	mu      sync.RWMutex
+	bots    map[string]*botEntry // robotID -> *botEntry
+	appIdx  map[string]string    // appID  -> robotID (webhook routing)
+	dedup   *dedupStore
+	webhSub string
+	stopCh  chan struct{}
+}
+
+// botEntry holds the state for one robot's Slack integration.
+type botEntry struct {
+	robotID  string
+	appID    string
+	bot      *slackapi.Bot
+	cancelFn context.CancelFunc // stops the Socket Mode loop, nil for Events API bots
+}
+
+// NewAdapter creates a new Slack adapter.
+func NewAdapter() *Adapter {
+	a := &Adapter{
+		bots:   make(map[string]*botEntry),
+		appIdx: make(map[string]string),
+		dedup:  newDedupStore(),
+		stopCh: make(chan struct{}),
+	}
+	go a.dedup.cleaner(a.stopCh)
+	a.StartWebhookSubscription()
+	webhooktypes.RegisterResponder("slack", a.respondWebhook)
+	return a
+}
+
+// Apply is called by the Dispatcher when a robot config is created or updated.
+func (a *Adapter) Apply(ctx context.Context, robot *robottypes.Robot) {
+	slConf := extractConfig(robot)
+	log.Debug("Apply robot=%s slConf=%v", robot.MemberID, slConf != nil)
+
+	if slConf == nil || !slConf.Enabled || slConf.BotToken == "" {
+		a.removeBot(robot.MemberID)
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if existing, ok := a.bots[robot.MemberID]; ok {
+		if existing.bot.Token() == slConf.BotToken &&
+			existing.bot.AppToken() == slConf.AppToken &&
+			existing.bot.SigningSecret() == slConf.SigningSecret &&
+			existing.appID == slConf.AppID {
+			return
+		}
+		a.removeBotLocked(robot.MemberID)
+	}
+
+	entry := &botEntry{
+		robotID: robot.MemberID,
+		appID:   slConf.AppID,
+		bot:     slackapi.NewBot(slConf.BotToken, slConf.AppToken, slConf.SigningSecret),
+	}
+	a.bots[robot.MemberID] = entry
+	if slConf.AppID != "" {
+		a.appIdx[slConf.AppID] = robot.MemberID
+	}
+
+	if slConf.AppToken != "" {
+		socketCtx, socketCancel := context.WithCancel(context.Background())
+		entry.cancelFn = socketCancel
+		go a.socketLoop(socketCtx, entry)
+	}
+
+	log.Info("slack adapter: registered robot=%s app=%s socket_mode=%v", robot.MemberID, slConf.AppID, slConf.AppToken != "")
+}
+
+// Remove is called by the Dispatcher when a robot is deleted.
+func (a *Adapter) Remove(ctx context.Context, robotID string) {
+	a.removeBot(robotID)
+}
+
+// Shutdown stops all Socket Mode connections, the webhook subscription and the dedup cleaner.
+func (a *Adapter) Shutdown() {
+	close(a.stopCh)
+	webhooktypes.RegisterResponder("slack", nil)
+	a.StopWebhookSubscription()
+	a.mu.Lock()
+	for _, entry := range a.bots {
+		if entry.cancelFn != nil {
+			entry.cancelFn()
+		}
+	}
+	a.mu.Unlock()
+	log.Info("slack adapter: shutdown complete")
+}
+
+// ResolveBot returns the slackapi.Bot for a given appID, used by the webhook
+// verification layer. Returns nil if not found.
+func (a *Adapter) ResolveBot(appID string) *slackapi.Bot {
+	entry, ok := a.resolveByAppID(appID)
+	if !ok {
+		return nil
+	}
+	return entry.bot
+}
+
+// --- Bot registry ---
+
+func (a *Adapter) removeBot(robotID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.removeBotLocked(robotID)
+}
+
+func (a *Adapter) removeBotLocked(robotID string) {
+	entry, ok := a.bots[robotID]
+	if !ok {
+		return
+	}
+	if entry.cancelFn != nil {
+		entry.cancelFn()
+	}
+	if entry.appID != "" {
+		delete(a.appIdx, entry.appID)
+	}
+	delete(a.bots, robotID)
+	log.Info("slack adapter: unregistered robot=%s", robotID)
+}
+
+func (a *Adapter) resolveByAppID(appID string) (*botEntry, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	robotID, ok := a.appIdx[appID]
+	if !ok {
+		return nil, false
+	}
+	entry, ok := a.bots[robotID]
+	return entry, ok
+}
+
+func extractConfig(robot *robottypes.Robot) *robottypes.SlackConfig {
+	if robot.Config == nil || robot.Config.Integrations == nil {
+		return nil
+	}
+	return robot.Config.Integrations.Slack
+}
@@ -0,0 +1,113 @@
+package slack
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	robottypes "github.com/yaoapp/yao/agent/robot/types"
+	webhooktypes "github.com/yaoapp/yao/openapi/integrations"
+)
+
+func newTestAdapter() *Adapter {
+	return &Adapter{
+		bots:   make(map[string]*botEntry),
+		appIdx: make(map[string]string),
+		dedup:  newDedupStore(),
+		stopCh: make(chan struct{}),
+	}
+}
+
+func testRobot(conf *robottypes.SlackConfig) *robottypes.Robot {
+	return &robottypes.Robot{
+		MemberID: "robot_slack_test",
+		TeamID:   "team_slack_test",
+		Config: &robottypes.Config{
+			Integrations: &robottypes.Integrations{Slack: conf},
+		},
+	}
+}
+
+func TestAdapter_ApplyRemove(t *testing.T) {
+	a := newTestAdapter()
+	defer close(a.stopCh)
+
+	conf := &robottypes.SlackConfig{Enabled: true, BotToken: "xoxb-test", SigningSecret: "s3cr3t", AppID: "slack-app"}
+	a.Apply(context.Background(), testRobot(conf))
+
+	bot := a.ResolveBot("slack-app")
+	require.NotNil(t, bot)
+	assert.Equal(t, "xoxb-test", bot.Token())
+
+	a.mu.RLock()
+	assert.Nil(t, a.bots["robot_slack_test"].cancelFn, "no socket mode without an app token")
+	a.mu.RUnlock()
+
+	// Disabling removes the bot
+	conf.Enabled = false
+	a.Apply(context.Background(), testRobot(conf))
+	assert.Nil(t, a.ResolveBot("slack-app"))
+}
+
+func TestAdapter_RespondWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	a := newTestAdapter()
+	defer close(a.stopCh)
+	a.Apply(context.Background(), testRobot(&robottypes.SlackConfig{
+		Enabled: true, BotToken: "xoxb-test", SigningSecret: "s3cr3t", AppID: "slack-app",
+	}))
+
+	body := []byte(`{"type":"url_verification","challenge":"chal-123"}`)
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte("s3cr3t"))
+	mac.Write([]byte("v0:" + ts + ":"))
+	mac.Write(body)
+	signature := "v0=" + hex.EncodeToString(mac.Sum(nil))
+
+	respond := func(signature string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		handled := a.respondWebhook(c, &webhooktypes.WebhookPayload{
+			Provider: "slack",
+			AppID:    "slack-app",
+			Body:     body,
+			Headers:  map[string]string{"X-Slack-Request-Timestamp": ts, "X-Slack-Signature": signature},
+		})
+		require.True(t, handled)
+		return w
+	}
+
+	w := respond(signature)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "chal-123")
+
+	w = respond("v0=invalid")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Event callbacks are handled asynchronously
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	assert.False(t, a.respondWebhook(c, &webhooktypes.WebhookPayload{AppID: "slack-app", Body: []byte(`{"type":"event_callback"}`)}))
+}
+
+func TestMergeContentParts(t *testing.T) {
+	text := mergeContentParts([]interface{}{
+		map[string]interface{}{"type": "text", "text": "a"},
+		map[string]interface{}{"type": "text", "text": "b"},
+	})
+	assert.Equal(t, "a\nb", text)
+
+	parts := mergeContentParts([]interface{}{
+		map[string]interface{}{"type": "text", "text": "a"},
+		map[string]interface{}{"type": "file", "file_url": "__yao.attachment://x"},
+	})
+	assert.Len(t, parts, 2)
+}
@@ -0,0 +1,44 @@
+package slack
+
+import (
+	"context"
+	"time"
+
+	slackapi "github.com/yaoapp/yao/integrations/slack"
+)
+
+const reconnectDelay = 5 * time.Second
+
+// socketLoop keeps a Socket Mode connection open for a single bot.
+// Slack asks clients to reconnect regularly, so it reconnects whenever the
+// connection ends until the bot is removed or the adapter shuts down.
+func (a *Adapter) socketLoop(ctx context.Context, entry *botEntry) {
+	log.Info("slack socketLoop started robot=%s app=%s", entry.robotID, entry.appID)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("slack socketLoop stopped robot=%s", entry.robotID)
+			return
+		case <-a.stopCh:
+			return
+		default:
+		}
+
+		err := entry.bot.RunSocketMode(ctx, func(env *slackapi.EventsAPIEnvelope) {
+			// Envelopes are already acknowledged, handle them off the read loop
+			go a.handleEnvelope(ctx, entry, env)
+		})
+		if err != nil {
+			log.Warn("slack socket mode disconnected robot=%s: %v, reconnecting in %s", entry.robotID, err, reconnectDelay)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-a.stopCh:
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
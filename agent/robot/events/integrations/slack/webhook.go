@@ -0,0 +1,85 @@
+package slack
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yaoapp/yao/event"
+	eventtypes "github.com/yaoapp/yao/event/types"
+	slackapi "github.com/yaoapp/yao/integrations/slack"
+	webhooktypes "github.com/yaoapp/yao/openapi/integrations"
+)
+
+// StartWebhookSubscription subscribes to integration.webhook.slack events
+// pushed by the Events API endpoint.
+func (a *Adapter) StartWebhookSubscription() {
+	ch := make(chan *eventtypes.Event, 128)
+	a.webhSub = event.Subscribe("integration.webhook.slack", ch)
+	go a.handleWebhooks(ch)
+	log.Info("slack adapter: webhook subscription started")
+}
+
+// StopWebhookSubscription unsubscribes from webhook events.
+func (a *Adapter) StopWebhookSubscription() {
+	if a.webhSub != "" {
+		event.Unsubscribe(a.webhSub)
+		a.webhSub = ""
+	}
+}
+
+func (a *Adapter) handleWebhooks(ch <-chan *eventtypes.Event) {
+	for ev := range ch {
+		var payload webhooktypes.WebhookPayload
+		if err := ev.Should(&payload); err != nil {
+			log.Error("slack adapter: invalid webhook event: %v", err)
+			continue
+		}
+
+		entry, ok := a.verifiedEntry(&payload)
+		if !ok {
+			continue
+		}
+
+		env, err := slackapi.ParseEventsAPI(payload.Body)
+		if err != nil {
+			log.Error("slack adapter: webhook unmarshal failed: %v", err)
+			continue
+		}
+		a.handleEnvelope(context.Background(), entry, env)
+	}
+}
+
+// respondWebhook answers the url_verification challenge Slack sends when the
+// Events API request URL is saved. Other requests are left to handleWebhooks.
+func (a *Adapter) respondWebhook(c *gin.Context, payload *webhooktypes.WebhookPayload) bool {
+	env, err := slackapi.ParseEventsAPI(payload.Body)
+	if err != nil || env.Type != slackapi.EnvelopeURLVerification {
+		return false
+	}
+
+	if _, ok := a.verifiedEntry(payload); !ok {
+		c.Status(http.StatusUnauthorized)
+		return true
+	}
+
+	c.JSON(http.StatusOK, gin.H{"challenge": env.Challenge})
+	return true
+}
+
+// verifiedEntry returns the bot the webhook is addressed to when its signature is valid.
+func (a *Adapter) verifiedEntry(payload *webhooktypes.WebhookPayload) (*botEntry, bool) {
+	entry, ok := a.resolveByAppID(payload.AppID)
+	if !ok {
+		log.Warn("slack adapter: unknown app_id=%s", payload.AppID)
+		return nil, false
+	}
+
+	timestamp := payload.Headers["X-Slack-Request-Timestamp"]
+	signature := payload.Headers["X-Slack-Signature"]
+	if !entry.bot.VerifySignature(timestamp, signature, payload.Body) {
+		log.Warn("slack adapter: webhook signature mismatch app_id=%s", payload.AppID)
+		return nil, false
+	}
+	return entry, true
+}
@@ -19,7 +19,9 @@
 //	├── dryrun/
 //	│   └── executor.go       # Simulated execution (testing/demo)
 //	├── sandbox/
-//	│   └── executor.go       # Container-isolated execution (NOT IMPLEMENTED)
+//	│   ├── executor.go       # Container-isolated execution
+//	│   ├── box.go            # Box lifecycle (one box per execution)
+//	│   └── task.go           # P3 task protocol inside the box
 //	└── executor.go           # Factory functions (this file)
 //
 // Usage:
@@ -30,8 +32,8 @@
 //	// Testing - simulated execution
 //	exec := executor.NewDryRun()
 //
-//	// Sandbox - NOT IMPLEMENTED (requires container infrastructure)
-//	// exec := executor.NewSandbox() // placeholder only
+//	// Sandbox - P3 tasks run in sandbox/v2 boxes
+//	exec := executor.NewSandbox()
 //
 //	// With mode selection
 //	exec := executor.NewWithMode(executor.ModeDryRun)
@@ -102,18 +104,14 @@ func NewDryRunWithCallbacks(delay time.Duration, onStart, onEnd func()) *DryRunE
 	})
 }
 
-// NewSandbox creates a sandbox executor placeholder
-//
-// ⚠️ NOT IMPLEMENTED: True sandbox requires container-level isolation
-// (Docker/gVisor/Firecracker). Current implementation behaves like DryRun.
+// NewSandbox creates a sandbox executor
+// P3 tasks run inside a sandbox/v2 box with resource limits, no network by
+// config and a read-only workspace mount
 func NewSandbox() Executor {
 	return sandbox.New()
 }
 
-// NewSandboxWithConfig creates a sandbox executor placeholder with configuration
-//
-// ⚠️ NOT IMPLEMENTED: Config options are placeholders. Current implementation
-// behaves like DryRun and does NOT provide real security isolation.
+// NewSandboxWithConfig creates a sandbox executor with configuration
 func NewSandboxWithConfig(config SandboxConfig) Executor {
 	return sandbox.NewWithConfig(config)
 }
@@ -143,6 +141,8 @@ func NewWithSetting(setting *Setting) Executor {
 		return NewSandboxWithConfig(SandboxConfig{
 			MaxDuration:   setting.MaxDuration,
 			MaxMemory:     setting.MaxMemory,
+			MaxCPUs:       setting.MaxCPUs,
+			Image:         setting.Image,
 			AllowedAgents: setting.AllowedAgents,
 			NetworkAccess: setting.NetworkAccess,
 			FileAccess:    setting.FileAccess,
@@ -0,0 +1,131 @@
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	kunlog "github.com/yaoapp/kun/log"
+	robottypes "github.com/yaoapp/yao/agent/robot/types"
+	agentsandbox "github.com/yaoapp/yao/agent/sandbox/v2"
+	infra "github.com/yaoapp/yao/sandbox/v2"
+)
+
+// Box is the part of a sandbox box the executor uses
+type Box interface {
+	ID() string
+	Stream(ctx context.Context, cmd []string, opts ...infra.ExecOption) (*infra.ExecStream, error)
+}
+
+// Provider creates and removes the boxes tasks run in
+type Provider interface {
+	Create(ctx context.Context, opts infra.CreateOptions) (Box, error)
+	Remove(ctx context.Context, id string) error
+}
+
+// managerProvider creates boxes with the global sandbox manager
+type managerProvider struct{}
+
+func (managerProvider) Create(ctx context.Context, opts infra.CreateOptions) (Box, error) {
+	box, err := infra.M().Create(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return box, nil
+}
+
+func (managerProvider) Remove(ctx context.Context, id string) error {
+	return infra.M().Remove(ctx, id)
+}
+
+// execBox is the box of one execution, created on its first task
+type execBox struct {
+	once sync.Once
+	box  Box
+	err  error
+}
+
+// box returns the box of an execution, creating it on first use
+func (e *Executor) box(ctx *robottypes.Context, exec *robottypes.Execution) (Box, error) {
+	v, _ := e.boxes.LoadOrStore(exec.ID, &execBox{})
+	eb := v.(*execBox)
+	eb.once.Do(func() {
+		eb.box, eb.err = e.provider.Create(ctx.Context, e.createOptions(exec))
+		if eb.err != nil {
+			eb.err = fmt.Errorf("failed to create sandbox: %w", eb.err)
+		}
+	})
+	return eb.box, eb.err
+}
+
+// releaseBox removes the box of an execution, if any
+func (e *Executor) releaseBox(execID string) {
+	v, ok := e.boxes.LoadAndDelete(execID)
+	if !ok {
+		return
+	}
+	eb := v.(*execBox)
+	if eb.box == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := e.provider.Remove(ctx, eb.box.ID()); err != nil {
+		kunlog.With(kunlog.F{
+			"execution_id": execID,
+			"box_id":       eb.box.ID(),
+			"error":        err,
+		}).Warn("Failed to remove sandbox: %v", err)
+	}
+}
+
+// createOptions returns the box options of an execution
+func (e *Executor) createOptions(exec *robottypes.Execution) infra.CreateOptions {
+	robot := exec.GetRobot()
+	opts := infra.CreateOptions{
+		Owner:       exec.MemberID,
+		Image:       e.config.Image,
+		Memory:      e.config.MaxMemory,
+		CPUs:        e.config.MaxCPUs,
+		NoNetwork:   !e.config.NetworkAccess,
+		Policy:      infra.OneShot,
+		MaxLifetime: e.config.MaxDuration,
+		Labels: map[string]string{
+			"robot-member-id":    exec.MemberID,
+			"robot-execution-id": exec.ID,
+		},
+		Env: map[string]string{},
+	}
+
+	if robot != nil {
+		opts.DisplayName = robot.DisplayName
+		if robot.Workspace != "" {
+			opts.WorkspaceID = robot.Workspace
+			opts.MountPath = DefaultMountPath
+			opts.MountMode = "ro"
+			if e.config.FileAccess {
+				opts.MountMode = "rw"
+			}
+		}
+	}
+
+	// The robot identity lets the task call back Yao, pointless without network
+	if e.config.NetworkAccess {
+		tok, err := agentsandbox.IssueSandboxToken(exec.TeamID, exec.MemberID)
+		if err != nil {
+			kunlog.With(kunlog.F{
+				"execution_id": exec.ID,
+				"member_id":    exec.MemberID,
+				"error":        err,
+			}).Warn("Failed to issue sandbox token: %v", err)
+		} else if tok != nil {
+			opts.Env["YAO_TOKEN"] = tok.Token
+			if tok.RefreshToken != "" {
+				opts.Env["YAO_REFRESH_TOKEN"] = tok.RefreshToken
+			}
+		}
+	}
+	return opts
+}
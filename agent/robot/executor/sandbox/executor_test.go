@@ -0,0 +1,162 @@
+package sandbox_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaoapp/yao/agent/robot/executor/sandbox"
+	"github.com/yaoapp/yao/agent/robot/executor/standard"
+	"github.com/yaoapp/yao/agent/robot/executor/types"
+	robottypes "github.com/yaoapp/yao/agent/robot/types"
+	infra "github.com/yaoapp/yao/sandbox/v2"
+)
+
+// fakeBox replays a fixed output for every command
+type fakeBox struct {
+	id       string
+	stdout   string
+	stderr   string
+	exitCode int
+	stdin    bytes.Buffer
+	cmds     [][]string
+}
+
+func (b *fakeBox) ID() string { return b.id }
+
+func (b *fakeBox) Stream(_ context.Context, cmd []string, _ ...infra.ExecOption) (*infra.ExecStream, error) {
+	b.cmds = append(b.cmds, cmd)
+	return &infra.ExecStream{
+		Stdout: io.NopCloser(strings.NewReader(b.stdout)),
+		Stderr: io.NopCloser(strings.NewReader(b.stderr)),
+		Stdin:  nopWriteCloser{&b.stdin},
+		Wait:   func() (int, error) { return b.exitCode, nil },
+		Cancel: func() {},
+	}, nil
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+// fakeProvider records the created and removed boxes
+type fakeProvider struct {
+	mu      sync.Mutex
+	box     *fakeBox
+	created []infra.CreateOptions
+	removed []string
+}
+
+func (p *fakeProvider) Create(_ context.Context, opts infra.CreateOptions) (sandbox.Box, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.created = append(p.created, opts)
+	return p.box, nil
+}
+
+func (p *fakeProvider) Remove(_ context.Context, id string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.removed = append(p.removed, id)
+	return nil
+}
+
+func newExecution(robot *robottypes.Robot) *robottypes.Execution {
+	exec := &robottypes.Execution{ID: "exec-1", MemberID: robot.MemberID, TeamID: robot.TeamID}
+	exec.SetRobot(robot)
+	return exec
+}
+
+func TestRunTaskInBox(t *testing.T) {
+	box := &fakeBox{id: "box-1", stdout: "working\n{\"answer\": 42}\n", stderr: "step 1\n"}
+	provider := &fakeProvider{box: box}
+	e := sandbox.NewWithProvider(types.SandboxConfig{MaxMemory: 256 * 1024 * 1024, MaxCPUs: 0.5}, provider)
+
+	robot := &robottypes.Robot{MemberID: "member-1", TeamID: "team-1", Workspace: "ws-1"}
+	exec := newExecution(robot)
+	task := &robottypes.Task{ID: "task-1", ExecutorType: robottypes.ExecutorProcess, ExecutorID: "scripts.test.Run"}
+	ctx := robottypes.NewContext(context.Background(), nil)
+
+	result := sandbox.RunTask(e, ctx, exec, task, &standard.RunnerContext{Goals: &robottypes.Goals{Content: "## Goals"}})
+	require.NotNil(t, result)
+	assert.True(t, result.Success, result.Error)
+	assert.Equal(t, "working\n{\"answer\": 42}", result.Output)
+	assert.Equal(t, [][]string{sandbox.DefaultCommand}, box.cmds)
+	assert.Contains(t, box.stdin.String(), `"execution_id":"exec-1"`)
+	assert.Contains(t, box.stdin.String(), `"goals":"## Goals"`)
+
+	// One box per execution, with the limits and a read-only workspace without network
+	sandbox.RunTask(e, ctx, exec, task, nil)
+	require.Len(t, provider.created, 1)
+	opts := provider.created[0]
+	assert.Equal(t, "member-1", opts.Owner)
+	assert.Equal(t, sandbox.DefaultImage, opts.Image)
+	assert.Equal(t, int64(256*1024*1024), opts.Memory)
+	assert.Equal(t, 0.5, opts.CPUs)
+	assert.True(t, opts.NoNetwork)
+	assert.Equal(t, "ws-1", opts.WorkspaceID)
+	assert.Equal(t, "ro", opts.MountMode)
+	assert.Equal(t, infra.OneShot, opts.Policy)
+
+	sandbox.ReleaseBox(e, exec.ID)
+	assert.Equal(t, []string{"box-1"}, provider.removed)
+}
+
+func TestRunTaskOutput(t *testing.T) {
+	ctx := robottypes.NewContext(context.Background(), nil)
+	robot := &robottypes.Robot{MemberID: "member-1"}
+	task := &robottypes.Task{ID: "task-1", ExecutorType: robottypes.ExecutorProcess}
+
+	t.Run("json", func(t *testing.T) {
+		e := sandbox.NewWithProvider(types.SandboxConfig{}, &fakeProvider{box: &fakeBox{id: "b", stdout: `{"rows": 3}`}})
+		result := sandbox.RunTask(e, ctx, newExecution(robot), task, nil)
+		assert.True(t, result.Success)
+		assert.Equal(t, map[string]interface{}{"rows": float64(3)}, result.Output)
+	})
+
+	t.Run("exit code", func(t *testing.T) {
+		e := sandbox.NewWithProvider(types.SandboxConfig{}, &fakeProvider{box: &fakeBox{id: "b", stderr: "boom\n", exitCode: 2}})
+		result := sandbox.RunTask(e, ctx, newExecution(robot), task, nil)
+		assert.False(t, result.Success)
+		assert.Contains(t, result.Error, "code 2")
+		assert.Contains(t, result.Error, "boom")
+	})
+
+	t.Run("need input", func(t *testing.T) {
+		e := sandbox.NewWithProvider(types.SandboxConfig{}, &fakeProvider{box: &fakeBox{id: "b", stdout: `{"need_input": "Which region?"}`}})
+		result := sandbox.RunTask(e, ctx, newExecution(robot), task, nil)
+		assert.True(t, result.NeedInput)
+		assert.Equal(t, "Which region?", result.InputQuestion)
+	})
+
+	t.Run("agent not allowed", func(t *testing.T) {
+		provider := &fakeProvider{box: &fakeBox{id: "b"}}
+		e := sandbox.NewWithProvider(types.SandboxConfig{AllowedAgents: []string{"safe"}}, provider)
+		result := sandbox.RunTask(e, ctx, newExecution(robot), &robottypes.Task{ID: "t", ExecutorType: robottypes.ExecutorAssistant, ExecutorID: "unsafe"}, nil)
+		assert.False(t, result.Success)
+		assert.Contains(t, result.Error, "not allowed")
+		assert.Empty(t, provider.created)
+	})
+}
+
+func TestCreateOptionsFileAccess(t *testing.T) {
+	provider := &fakeProvider{box: &fakeBox{id: "b"}}
+	e := sandbox.NewWithProvider(types.SandboxConfig{FileAccess: true, Image: "custom:1"}, provider)
+	robot := &robottypes.Robot{MemberID: "member-1", Workspace: "ws-1"}
+
+	sandbox.RunTask(e, robottypes.NewContext(context.Background(), nil), newExecution(robot), &robottypes.Task{ID: "t"}, nil)
+	require.Len(t, provider.created, 1)
+	assert.Equal(t, "rw", provider.created[0].MountMode)
+	assert.Equal(t, "custom:1", provider.created[0].Image)
+}
+
+func TestParseOutput(t *testing.T) {
+	assert.Nil(t, sandbox.ParseOutput([]byte("  \n")))
+	assert.Equal(t, "plain text", sandbox.ParseOutput([]byte("plain text\n")))
+	assert.Equal(t, []interface{}{float64(1), float64(2)}, sandbox.ParseOutput([]byte("[1, 2]")))
+}
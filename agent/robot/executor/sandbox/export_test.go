@@ -0,0 +1,16 @@
+package sandbox
+
+import (
+	"github.com/yaoapp/yao/agent/robot/executor/standard"
+	robottypes "github.com/yaoapp/yao/agent/robot/types"
+)
+
+// RunTask runs one task like P3 does, for tests.
+func RunTask(e *Executor, ctx *robottypes.Context, exec *robottypes.Execution, task *robottypes.Task, taskCtx *standard.RunnerContext) *robottypes.TaskResult {
+	return e.runTask(ctx, exec, task, taskCtx)
+}
+
+// ReleaseBox removes the box of an execution, for tests.
+func ReleaseBox(e *Executor, execID string) {
+	e.releaseBox(execID)
+}
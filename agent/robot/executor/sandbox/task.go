@@ -0,0 +1,233 @@
+package sandbox
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	robotevents "github.com/yaoapp/yao/agent/robot/events"
+	"github.com/yaoapp/yao/agent/robot/executor/standard"
+	robottypes "github.com/yaoapp/yao/agent/robot/types"
+	"github.com/yaoapp/yao/event"
+	infra "github.com/yaoapp/yao/sandbox/v2"
+)
+
+// Output limits
+const (
+	MaxOutputBytes = 1024 * 1024 // stdout kept as the task output
+	maxStderrBytes = 4096        // stderr tail kept for the error message
+	maxLineBytes   = 64 * 1024   // longer output lines are split
+)
+
+// TaskRequest is written as JSON to the stdin of the task command
+type TaskRequest struct {
+	ExecutionID     string                  `json:"execution_id"`
+	MemberID        string                  `json:"member_id"`
+	TeamID          string                  `json:"team_id,omitempty"`
+	Task            *robottypes.Task        `json:"task"`
+	Goals           string                  `json:"goals,omitempty"`
+	SystemPrompt    string                  `json:"system_prompt,omitempty"`
+	PreviousResults []robottypes.TaskResult `json:"previous_results,omitempty"`
+	Workspace       string                  `json:"workspace,omitempty"` // mount path of the robot workspace
+}
+
+// runTask runs one P3 task inside the box of the execution
+func (e *Executor) runTask(ctx *robottypes.Context, exec *robottypes.Execution, task *robottypes.Task, taskCtx *standard.RunnerContext) *robottypes.TaskResult {
+	startTime := time.Now()
+	result := &robottypes.TaskResult{TaskID: task.ID}
+	fail := func(err error) *robottypes.TaskResult {
+		result.Success = false
+		result.Error = err.Error()
+		result.Duration = time.Since(startTime).Milliseconds()
+		return result
+	}
+
+	if task.ExecutorType == robottypes.ExecutorAssistant && !e.isAgentAllowed(task.ExecutorID) {
+		return fail(fmt.Errorf("agent %s is not allowed in sandbox", task.ExecutorID))
+	}
+
+	box, err := e.box(ctx, exec)
+	if err != nil {
+		return fail(err)
+	}
+
+	stdin, err := json.Marshal(e.taskRequest(exec, task, taskCtx))
+	if err != nil {
+		return fail(fmt.Errorf("failed to encode task: %w", err))
+	}
+
+	opts := []infra.ExecOption{}
+	if exec.GetRobot() != nil && exec.GetRobot().Workspace != "" {
+		opts = append(opts, infra.WithWorkDir(DefaultMountPath))
+	}
+	stream, err := box.Stream(ctx.Context, e.config.Command, opts...)
+	if err != nil {
+		return fail(fmt.Errorf("failed to start task: %w", err))
+	}
+
+	// Stop the command when the execution is cancelled or times out
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Context.Done():
+			if stream.Cancel != nil {
+				stream.Cancel()
+			}
+		case <-done:
+		}
+	}()
+
+	// Write stdin while the output is read, a large request could fill the pipes
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		if stream.Stdin != nil {
+			_, _ = stream.Stdin.Write(stdin)
+			_ = stream.Stdin.Close()
+		}
+	}()
+
+	var stdout, stderr limitedBuffer
+	stdout.limit, stderr.limit = MaxOutputBytes, maxStderrBytes
+	stderr.tail = true
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		e.pipe(ctx, exec, task, "stdout", stream.Stdout, &stdout)
+	}()
+	go func() {
+		defer wg.Done()
+		e.pipe(ctx, exec, task, "stderr", stream.Stderr, &stderr)
+	}()
+	wg.Wait()
+
+	exitCode, err := stream.Wait()
+	<-written
+	result.Duration = time.Since(startTime).Milliseconds()
+	if ctx.Context.Err() != nil {
+		return fail(fmt.Errorf("task interrupted: %w", ctx.Context.Err()))
+	}
+	if err != nil {
+		return fail(fmt.Errorf("task failed: %w", err))
+	}
+	if exitCode != 0 {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(stdout.String())
+		}
+		return fail(fmt.Errorf("task exited with code %d: %s", exitCode, msg))
+	}
+
+	result.Output = ParseOutput(stdout.Bytes())
+	result.Success = true
+	if out, ok := result.Output.(map[string]interface{}); ok {
+		if question, ok := out["need_input"].(string); ok && question != "" {
+			result.NeedInput = true
+			result.InputQuestion = question
+		}
+	}
+	if stdout.truncated {
+		result.Output = map[string]interface{}{"output": result.Output, "truncated": true}
+	}
+	return result
+}
+
+// taskRequest builds the stdin of the task command
+func (e *Executor) taskRequest(exec *robottypes.Execution, task *robottypes.Task, taskCtx *standard.RunnerContext) *TaskRequest {
+	req := &TaskRequest{
+		ExecutionID: exec.ID,
+		MemberID:    exec.MemberID,
+		TeamID:      exec.TeamID,
+		Task:        task,
+	}
+	if robot := exec.GetRobot(); robot != nil && robot.Workspace != "" {
+		req.Workspace = DefaultMountPath
+	}
+	if taskCtx != nil {
+		req.SystemPrompt = taskCtx.SystemPrompt
+		req.PreviousResults = taskCtx.PreviousResults
+		if taskCtx.Goals != nil {
+			req.Goals = taskCtx.Goals.Content
+		}
+	}
+	return req
+}
+
+// pipe reads an output stream line by line, keeps it in buf and pushes every
+// line as a robot.task.output event
+func (e *Executor) pipe(ctx *robottypes.Context, exec *robottypes.Execution, task *robottypes.Task, name string, r io.Reader, buf *limitedBuffer) {
+	if r == nil {
+		return
+	}
+	reader := bufio.NewReaderSize(r, maxLineBytes)
+	for {
+		line, err := reader.ReadSlice('\n')
+		if len(line) > 0 {
+			buf.Write(line)
+			if text := strings.TrimRight(string(line), "\r\n"); text != "" {
+				event.Push(ctx.Context, robotevents.TaskOutput, robotevents.TaskOutputPayload{
+					ExecutionID: exec.ID,
+					MemberID:    exec.MemberID,
+					TeamID:      exec.TeamID,
+					TaskID:      task.ID,
+					Stream:      name,
+					Line:        text,
+					ChatID:      exec.ChatID,
+				})
+			}
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// ParseOutput returns the task output: the JSON value of stdout when it parses, the text otherwise
+func ParseOutput(data []byte) interface{} {
+	text := strings.TrimSpace(string(data))
+	if text == "" {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(text), &value); err == nil {
+		return value
+	}
+	return text
+}
+
+// limitedBuffer keeps the first (or, with tail, the last) limit bytes written to it
+type limitedBuffer struct {
+	bytes.Buffer
+	limit     int
+	tail      bool
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if b.tail {
+		b.Buffer.Write(p)
+		if over := b.Len() - b.limit; over > 0 {
+			b.Next(over)
+			b.truncated = true
+		}
+		return n, nil
+	}
+	if room := b.limit - b.Len(); room < len(p) {
+		p = p[:max(room, 0)]
+		b.truncated = true
+	}
+	b.Buffer.Write(p)
+	return n, nil
+}
@@ -84,6 +84,7 @@ func (e *Executor) RunDelivery(ctx *robottypes.Context, exec *robottypes.Executi
 // Registered handlers (see events/handlers.go) route to email/webhook/process channels.
 func (e *Executor) pushDeliveryEvent(ctx *robottypes.Context, exec *robottypes.Execution, robot *robottypes.Robot) error {
 	prefs := buildDeliveryPreferences(robot)
+	prefs.Notify = buildNotifyPreference(robot, exec)
 
 	chatID := exec.ChatID
 	var extra map[string]any
@@ -267,6 +268,44 @@ func buildDeliveryPreferences(robot *robottypes.Robot) *robottypes.DeliveryPrefe
 	return prefs
 }
 
+// buildNotifyPreference returns the in-app notification recipients of an execution:
+// the robot manager, the user who triggered it and the configured targets
+// Returns nil when notification is disabled in the robot config or nobody is to be notified
+func buildNotifyPreference(robot *robottypes.Robot, exec *robottypes.Execution) *robottypes.NotifyPreference {
+	var configured *robottypes.NotifyPreference
+	if robot.Config != nil && robot.Config.Delivery != nil {
+		configured = robot.Config.Delivery.Notify
+	}
+	if configured != nil && !configured.Enabled {
+		return nil
+	}
+
+	targets := []robottypes.NotifyTarget{}
+	seen := map[string]bool{}
+	add := func(target robottypes.NotifyTarget) {
+		if target.UserID == "" || seen[target.UserID] {
+			return
+		}
+		seen[target.UserID] = true
+		targets = append(targets, target)
+	}
+
+	add(robottypes.NotifyTarget{UserID: robot.ManagerID})
+	if exec != nil && exec.Input != nil {
+		add(robottypes.NotifyTarget{UserID: exec.Input.UserID})
+	}
+	if configured != nil {
+		for _, target := range configured.Targets {
+			add(target)
+		}
+	}
+
+	if len(targets) == 0 {
+		return nil
+	}
+	return &robottypes.NotifyPreference{Enabled: true, Targets: targets}
+}
+
 func getManagerEmail(managerID string) string {
 	if managerID == "" {
 		return ""
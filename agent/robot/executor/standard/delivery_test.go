@@ -190,6 +190,52 @@ func TestRobotEmailInDelivery(t *testing.T) {
 	})
 }
 
+// ============================================================================
+// Notify Preference Tests
+// ============================================================================
+
+func TestBuildNotifyPreference(t *testing.T) {
+	exec := &types.Execution{ID: "exec-1", Input: &types.TriggerInput{UserID: "user-2"}}
+
+	t.Run("notifies manager and triggering user by default", func(t *testing.T) {
+		robot := &types.Robot{MemberID: "robot-001", ManagerID: "user-1"}
+		prefs := standard.BuildNotifyPreference(robot, exec)
+		require.NotNil(t, prefs)
+		assert.True(t, prefs.Enabled)
+		assert.Equal(t, []types.NotifyTarget{{UserID: "user-1"}, {UserID: "user-2"}}, prefs.Targets)
+	})
+
+	t.Run("adds configured targets once", func(t *testing.T) {
+		robot := &types.Robot{
+			MemberID:  "robot-001",
+			ManagerID: "user-1",
+			Config: &types.Config{Delivery: &types.DeliveryPreferences{
+				Notify: &types.NotifyPreference{Enabled: true, Targets: []types.NotifyTarget{
+					{UserID: "user-1"},
+					{UserID: "user-3", Link: "/robots/robot-001"},
+				}},
+			}},
+		}
+		prefs := standard.BuildNotifyPreference(robot, exec)
+		require.NotNil(t, prefs)
+		assert.Len(t, prefs.Targets, 3)
+		assert.Equal(t, "/robots/robot-001", prefs.Targets[2].Link)
+	})
+
+	t.Run("disabled in config", func(t *testing.T) {
+		robot := &types.Robot{
+			MemberID:  "robot-001",
+			ManagerID: "user-1",
+			Config:    &types.Config{Delivery: &types.DeliveryPreferences{Notify: &types.NotifyPreference{Enabled: false}}},
+		}
+		assert.Nil(t, standard.BuildNotifyPreference(robot, exec))
+	})
+
+	t.Run("nobody to notify", func(t *testing.T) {
+		assert.Nil(t, standard.BuildNotifyPreference(&types.Robot{MemberID: "robot-001"}, &types.Execution{}))
+	})
+}
+
 // ============================================================================
 // FormatDeliveryInput Tests
 // ============================================================================
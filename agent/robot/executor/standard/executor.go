@@ -29,6 +29,7 @@ type Executor struct {
 	currentCount atomic.Int32
 	onStart      func()
 	onEnd        func()
+	runTask      TaskFunc
 }
 
 // New creates a new standard executor
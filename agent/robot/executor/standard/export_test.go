@@ -0,0 +1,4 @@
+package standard
+
+// BuildNotifyPreference exposes buildNotifyPreference for tests
+var BuildNotifyPreference = buildNotifyPreference
@@ -60,6 +60,10 @@ func (e *Executor) RunGoals(ctx *robottypes.Context, exec *robottypes.Execution,
 		if exec.Input != nil {
 			userContent = formatter.FormatTriggerInput(exec.Input)
 		}
+		// Events of the same type that dedup merged into this execution
+		if merged := formatter.FormatMergedEvents(exec.MergedEvents()); merged != "" {
+			userContent += "\n" + merged
+		}
 	}
 
 	// Add robot identity context if not already included
@@ -82,6 +86,11 @@ func (e *Executor) RunGoals(ctx *robottypes.Context, exec *robottypes.Execution,
 		return fmt.Errorf("no input available for goals generation")
 	}
 
+	// Add what the robot learned from previous executions
+	if learned := recallLearnings(ctx, robot, userContent); learned != "" {
+		userContent += "\n\n" + learned
+	}
+
 	// Call agent
 	caller := NewAgentCaller()
 	caller.Connector = robot.LanguageModel
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/yaoapp/gou/mcp"
 	"github.com/yaoapp/yao/agent/assistant"
@@ -15,12 +16,12 @@ import (
 
 // InputFormatter provides methods to format input data for assistant prompts
 // Each phase has specific input requirements:
-// - P0 (Inspiration): ClockContext + Robot identity + Available resources
-// - P1 (Goals): InspirationReport/TriggerInput + Robot identity + Available resources
+// - P0 (Inspiration): ClockContext + Robot identity + Available resources + Past learnings
+// - P1 (Goals): InspirationReport/TriggerInput + Robot identity + Available resources + Past learnings
 // - P2 (Tasks): Goals + Available resources
 // - P3 (Run): Tasks
 // - P4 (Delivery): Task results
-// - P5 (Learning): Execution summary
+// - P5 (Learning): Execution summary + Known learnings
 type InputFormatter struct{}
 
 // NewInputFormatter creates a new InputFormatter
@@ -434,6 +435,35 @@ func (f *InputFormatter) FormatTriggerInput(input *robottypes.TriggerInput) stri
 	return ""
 }
 
+// FormatMergedEvents formats the events merged into an execution by dedup
+func (f *InputFormatter) FormatMergedEvents(events []robottypes.MergedEvent) string {
+	if len(events) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## Merged Events\n\n")
+	sb.WriteString(fmt.Sprintf("%d more event(s) of the same type arrived after this execution started. Handle them together with the event above.\n", len(events)))
+	for i, event := range events {
+		sb.WriteString(fmt.Sprintf("\n### Event %d", i+1))
+		if event.EventType != "" {
+			sb.WriteString(fmt.Sprintf(" (%s)", event.EventType))
+		}
+		sb.WriteString("\n\n")
+		if !event.ReceivedAt.IsZero() {
+			sb.WriteString(fmt.Sprintf("- **Received**: %s\n\n", event.ReceivedAt.Format(time.RFC3339)))
+		}
+		if event.Data != nil {
+			sb.WriteString("```json\n")
+			if data, err := json.MarshalIndent(event.Data, "", "  "); err == nil {
+				sb.WriteString(string(data))
+			}
+			sb.WriteString("\n```\n")
+		}
+	}
+	return sb.String()
+}
+
 // FormatGoals formats Goals as user message content
 // Used by P2 (Tasks) phase
 func (f *InputFormatter) FormatGoals(goals *robottypes.Goals, robot *robottypes.Robot) string {
@@ -637,13 +667,17 @@ func (f *InputFormatter) FormatExecutionSummary(exec *robottypes.Execution) stri
 	if len(exec.Tasks) > 0 {
 		sb.WriteString("## Tasks (P2)\n\n")
 		for i, task := range exec.Tasks {
-			sb.WriteString(fmt.Sprintf("%d. [%s] %s (executor: %s)\n",
+			sb.WriteString(fmt.Sprintf("%d. [%s] %s (executor: %s)",
 				i+1, task.Status, task.ID, task.ExecutorID))
+			if task.Description != "" {
+				sb.WriteString(": " + task.Description)
+			}
+			sb.WriteString("\n")
 		}
 		sb.WriteString("\n")
 	}
 
-	// Results (P3)
+	// Results (P3), with the failure reasons and validation issues
 	if len(exec.Results) > 0 {
 		sb.WriteString("## Results (P3)\n\n")
 		for _, result := range exec.Results {
@@ -651,11 +685,51 @@ func (f *InputFormatter) FormatExecutionSummary(exec *robottypes.Execution) stri
 			if !result.Success {
 				status = "✗"
 			}
-			sb.WriteString(fmt.Sprintf("- %s %s (%dms)\n", status, result.TaskID, result.Duration))
+			sb.WriteString(fmt.Sprintf("- %s %s (%dms)", status, result.TaskID, result.Duration))
+			if result.Error != "" {
+				sb.WriteString(": " + result.Error)
+			}
+			sb.WriteString("\n")
+			if result.Validation != nil && !result.Validation.Passed {
+				for _, issue := range result.Validation.Issues {
+					sb.WriteString(fmt.Sprintf("  - Issue: %s\n", issue))
+				}
+				for _, suggestion := range result.Validation.Suggestions {
+					sb.WriteString(fmt.Sprintf("  - Suggestion: %s\n", suggestion))
+				}
+			}
 		}
 		sb.WriteString("\n")
 	}
 
+	// Human interventions: the human trigger, tasks added by humans and questions asked to humans
+	var interventions []string
+	if exec.TriggerType == robottypes.TriggerHuman && exec.Input != nil {
+		line := fmt.Sprintf("- Triggered by human (action: %s)", exec.Input.Action)
+		for _, msg := range exec.Input.Messages {
+			if content, ok := msg.Content.(string); ok && content != "" {
+				line += ": " + content
+				break
+			}
+		}
+		interventions = append(interventions, line)
+	}
+	for _, task := range exec.Tasks {
+		if task.Source == robottypes.TaskSourceHuman {
+			interventions = append(interventions, fmt.Sprintf("- Task %s added by human", task.ID))
+		}
+	}
+	for _, result := range exec.Results {
+		if result.NeedInput && result.InputQuestion != "" {
+			interventions = append(interventions, fmt.Sprintf("- Task %s asked for human input: %s", result.TaskID, result.InputQuestion))
+		}
+	}
+	if len(interventions) > 0 {
+		sb.WriteString("## Human Interventions\n\n")
+		sb.WriteString(strings.Join(interventions, "\n"))
+		sb.WriteString("\n\n")
+	}
+
 	// Delivery (P4)
 	if exec.Delivery != nil {
 		sb.WriteString("## Delivery (P4)\n\n")
@@ -676,6 +750,34 @@ func (f *InputFormatter) FormatExecutionSummary(exec *robottypes.Execution) stri
 	return sb.String()
 }
 
+// FormatKnownLearnings formats the learnings a robot already has for P5 (Learning) phase,
+// so that the Learning Agent only reports new ones
+func (f *InputFormatter) FormatKnownLearnings(entries []robottypes.LearningEntry) string {
+	return formatLearnings("## Known Learnings",
+		"The robot already knows the following. Do not repeat them, only report new or corrected learnings.", entries)
+}
+
+// FormatPastLearnings formats the learnings of previous executions for P0 (Inspiration) and P1 (Goals) phases
+func (f *InputFormatter) FormatPastLearnings(entries []robottypes.LearningEntry) string {
+	return formatLearnings("## Past Learnings",
+		"Lessons from previous executions. Apply them when relevant.", entries)
+}
+
+// formatLearnings formats learning entries as a markdown list under a heading
+func formatLearnings(heading, intro string, entries []robottypes.LearningEntry) string {
+	if len(entries) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(heading + "\n\n")
+	sb.WriteString(intro + "\n\n")
+	for _, entry := range entries {
+		sb.WriteString(fmt.Sprintf("- [%s] %s\n", entry.Type, strings.ReplaceAll(strings.TrimSpace(entry.Content), "\n", " ")))
+	}
+	return sb.String()
+}
+
 // BuildMessages is a convenience method to build messages array from content
 func (f *InputFormatter) BuildMessages(userContent string) []agentcontext.Message {
 	return []agentcontext.Message{
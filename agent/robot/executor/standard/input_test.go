@@ -312,6 +312,26 @@ func TestInputFormatterFormatTriggerInput(t *testing.T) {
 	})
 }
 
+func TestInputFormatterFormatMergedEvents(t *testing.T) {
+	formatter := standard.NewInputFormatter()
+
+	t.Run("formats merged events", func(t *testing.T) {
+		exec := &types.Execution{Input: &types.TriggerInput{Source: types.EventWebhook, EventType: "lead.created"}}
+		exec.MergeEvent(types.MergedEvent{EventType: "lead.created", Data: map[string]interface{}{"email": "a@example.com"}, ReceivedAt: time.Now()})
+		exec.MergeEvent(types.MergedEvent{EventType: "lead.created", Data: map[string]interface{}{"email": "b@example.com"}})
+
+		result := formatter.FormatMergedEvents(exec.MergedEvents())
+		assert.Contains(t, result, "## Merged Events")
+		assert.Contains(t, result, "### Event 1 (lead.created)")
+		assert.Contains(t, result, "a@example.com")
+		assert.Contains(t, result, "b@example.com")
+	})
+
+	t.Run("returns empty without merged events", func(t *testing.T) {
+		assert.Empty(t, formatter.FormatMergedEvents(nil))
+	})
+}
+
 func TestInputFormatterFormatGoals(t *testing.T) {
 	formatter := standard.NewInputFormatter()
 
@@ -536,12 +556,76 @@ func TestInputFormatterFormatExecutionSummary(t *testing.T) {
 		assert.Contains(t, result, "**Error**: Task execution failed")
 	})
 
+	t.Run("formats failures and human interventions", func(t *testing.T) {
+		exec := &types.Execution{
+			ID:          "exec-789",
+			TriggerType: types.TriggerHuman,
+			Status:      types.ExecCompleted,
+			StartTime:   time.Now(),
+			Input: &types.TriggerInput{
+				Action:   types.ActionTaskAdd,
+				Messages: []agentcontext.Message{{Role: agentcontext.RoleUser, Content: "Add the Q3 numbers"}},
+			},
+			Tasks: []types.Task{
+				{ID: "t1", Description: "Query sales", Status: types.TaskFailed, ExecutorID: "db.query"},
+				{ID: "t2", Status: types.TaskCompleted, ExecutorID: "report.gen", Source: types.TaskSourceHuman},
+			},
+			Results: []types.TaskResult{
+				{TaskID: "t1", Success: false, Error: "connection refused", Duration: 50},
+				{
+					TaskID: "t2", Success: true, Duration: 80,
+					NeedInput: true, InputQuestion: "Which quarter?",
+					Validation: &types.ValidationResult{Passed: false, Issues: []string{"missing totals"}},
+				},
+			},
+		}
+
+		result := formatter.FormatExecutionSummary(exec)
+
+		assert.Contains(t, result, "db.query): Query sales")
+		assert.Contains(t, result, "✗ t1 (50ms): connection refused")
+		assert.Contains(t, result, "Issue: missing totals")
+		assert.Contains(t, result, "## Human Interventions")
+		assert.Contains(t, result, "Triggered by human (action: task.add): Add the Q3 numbers")
+		assert.Contains(t, result, "Task t2 added by human")
+		assert.Contains(t, result, "Task t2 asked for human input: Which quarter?")
+	})
+
 	t.Run("returns empty for nil execution", func(t *testing.T) {
 		result := formatter.FormatExecutionSummary(nil)
 		assert.Empty(t, result)
 	})
 }
 
+func TestInputFormatterFormatKnownLearnings(t *testing.T) {
+	formatter := standard.NewInputFormatter()
+
+	t.Run("formats known learnings", func(t *testing.T) {
+		result := formatter.FormatKnownLearnings([]types.LearningEntry{
+			{Type: types.LearnFeedback, Content: "CRM API times out\nbefore 9am"},
+			{Type: types.LearnInsight, Content: "Sales peak on Mondays"},
+		})
+
+		assert.Contains(t, result, "## Known Learnings")
+		assert.Contains(t, result, "- [feedback] CRM API times out before 9am")
+		assert.Contains(t, result, "- [insight] Sales peak on Mondays")
+	})
+
+	t.Run("formats past learnings", func(t *testing.T) {
+		result := formatter.FormatPastLearnings([]types.LearningEntry{
+			{Type: types.LearnInsight, Content: "Sales peak on Mondays"},
+		})
+
+		assert.Contains(t, result, "## Past Learnings")
+		assert.Contains(t, result, "- [insight] Sales peak on Mondays")
+	})
+
+	t.Run("returns empty without learnings", func(t *testing.T) {
+		assert.Empty(t, formatter.FormatKnownLearnings(nil))
+		assert.Empty(t, formatter.FormatPastLearnings(nil))
+	})
+}
+
 func TestInputFormatterBuildMessages(t *testing.T) {
 	formatter := standard.NewInputFormatter()
 
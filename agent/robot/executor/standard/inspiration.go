@@ -51,6 +51,11 @@ func (e *Executor) RunInspiration(ctx *robottypes.Context, exec *robottypes.Exec
 		userContent += "\n\n" + resourcesContent
 	}
 
+	// Add what the robot learned from previous executions
+	if learned := recallLearnings(ctx, robot, userContent); learned != "" {
+		userContent += "\n\n" + learned
+	}
+
 	// Call agent
 	caller := NewAgentCaller()
 	caller.Connector = robot.LanguageModel
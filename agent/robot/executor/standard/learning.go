@@ -1,39 +1,199 @@
 package standard
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/yaoapp/yao/agent/robot/store"
 	robottypes "github.com/yaoapp/yao/agent/robot/types"
 )
 
+// Learning limits
+const (
+	maxKnownLearnings    = 20 // stored learnings shown to the Learning Agent
+	maxLearnings         = 10 // new learnings kept per execution
+	maxRecalledLearnings = 10 // stored learnings shown to the Inspiration and Goals Agents
+)
+
 // RunLearning executes P5: Learning phase
-// Extracts learnings and saves to knowledge base
+// Calls the Learning Agent to extract knowledge from the execution and saves it
+// to the robot's learning KB, where later executions find it
 //
 // Input:
-//   - Execution summary (all phases)
+//   - Execution summary (goals, tasks, results, failures, human interventions)
+//   - Learnings the robot already has, so that only new ones are reported
 //
 // Output:
-//   - LearningEntry list with extracted knowledge
+//   - LearningEntry list with the new knowledge
 //
 // Learning Types:
 //   - LearnExecution: Execution patterns
-//   - LearnTask: Task-specific insights
-//   - LearnError: Error patterns for improvement
+//   - LearnFeedback: Error/fix feedback
+//   - LearnInsight: Patterns and tips
+//
+// The Learning Agent returns:
+//
+//	{"learnings": [{"type": "feedback", "content": "...", "tags": ["..."]}]}
 //
-// TODO: Implement real learning extraction
+// Learning only runs when enabled in the robot config (learn.on). It is best
+// effort: failures are logged and never fail the execution.
 func (e *Executor) RunLearning(ctx *robottypes.Context, exec *robottypes.Execution, _ interface{}) error {
-	// Get robot for locale
 	robot := exec.GetRobot()
+	if robot == nil {
+		return fmt.Errorf("robot not found in execution")
+	}
+	if robot.Config == nil || robot.Config.Learn == nil || !robot.Config.Learn.On {
+		return nil
+	}
 
 	// Update UI field with i18n
 	locale := getEffectiveLocale(robot, exec.Input)
 	e.updateUIFields(ctx, exec, "", getLocalizedMessage(locale, "learning_from_exec"))
 
-	e.simulateStreamDelay()
+	// Get agent ID for learning phase (per-robot config > global Uses > empty)
+	agentID := robottypes.ResolvePhaseAgent(robot.Config, robottypes.PhaseLearning)
+	if agentID == "" {
+		log.Warn("no Learning Agent configured for robot %s, learning skipped (set uses.learning in agent.yml or resources.phases in robot config)", robot.MemberID)
+		return nil
+	}
+
+	formatter := NewInputFormatter()
+	summary := formatter.FormatExecutionSummary(exec)
+
+	// What the robot already knows about this kind of execution
+	learnings := store.NewLearningStore()
+	known, err := learnings.Search(ctx.Context, robot, summary, maxKnownLearnings)
+	if err != nil {
+		log.Warn("failed to load learnings of robot %s: %v", robot.MemberID, err)
+		known = nil
+	}
+
+	userContent := summary
+	if knownContent := formatter.FormatKnownLearnings(known); knownContent != "" {
+		userContent += "\n\n" + knownContent
+	}
+
+	// Call agent
+	caller := NewAgentCaller()
+	caller.Connector = robot.LanguageModel
+	caller.Workspace = robot.Workspace
+	result, err := caller.CallWithMessages(ctx, agentID, userContent)
+	if err != nil {
+		log.Warn("learning agent (%s) call failed: %v", agentID, err)
+		return nil
+	}
+
+	var data interface{}
+	if obj, err := result.GetJSON(); err == nil {
+		data = obj
+	} else if arr, err := result.GetJSONArray(); err == nil {
+		data = arr
+	} else {
+		log.Warn("learning agent (%s) returned invalid JSON: %v", agentID, err)
+		return nil
+	}
+
+	entries := FilterLearnings(ParseLearnings(data), robot.Config.Learn, known)
+	for i := range entries {
+		entries[i].Meta = map[string]interface{}{
+			"execution_id": exec.ID,
+			"trigger_type": string(exec.TriggerType),
+		}
+	}
 
-	exec.Learning = []robottypes.LearningEntry{
-		{
-			Type:    robottypes.LearnExecution,
-			Content: "Completed daily tasks successfully",
-		},
+	saved, err := learnings.Save(ctx.Context, robot, exec.ID, entries)
+	if err != nil {
+		log.Warn("failed to save learnings of robot %s: %v", robot.MemberID, err)
+		exec.Learning = entries
+		return nil
 	}
+
+	exec.Learning = saved
 	return nil
 }
+
+// ParseLearnings converts the Learning Agent output to learning entries
+// Accepts {"learnings": [...]} or a bare array; entries without content are dropped,
+// unknown types are read as insights
+func ParseLearnings(data interface{}) []robottypes.LearningEntry {
+	var items []interface{}
+	switch v := data.(type) {
+	case map[string]interface{}:
+		items, _ = v["learnings"].([]interface{})
+	case []interface{}:
+		items = v
+	}
+
+	entries := []robottypes.LearningEntry{}
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		content, _ := m["content"].(string)
+		content = strings.TrimSpace(content)
+		if content == "" {
+			continue
+		}
+
+		entry := robottypes.LearningEntry{Type: robottypes.LearnInsight, Content: content}
+		if t, ok := m["type"].(string); ok {
+			switch lt := robottypes.LearningType(strings.ToLower(t)); lt {
+			case robottypes.LearnExecution, robottypes.LearnFeedback, robottypes.LearnInsight:
+				entry.Type = lt
+			}
+		}
+
+		if tags, ok := m["tags"].([]interface{}); ok {
+			for _, tag := range tags {
+				if s, ok := tag.(string); ok && s != "" {
+					entry.Tags = append(entry.Tags, s)
+				}
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// FilterLearnings keeps the learning types enabled in the config and drops the
+// entries the robot already knows or that repeat each other, at most maxLearnings
+func FilterLearnings(entries []robottypes.LearningEntry, learn *robottypes.Learn, known []robottypes.LearningEntry) []robottypes.LearningEntry {
+	seen := map[string]bool{}
+	for _, entry := range known {
+		seen[store.NormalizeLearning(entry.Content)] = true
+	}
+
+	filtered := []robottypes.LearningEntry{}
+	for _, entry := range entries {
+		if !learn.Accepts(entry.Type) {
+			continue
+		}
+		key := store.NormalizeLearning(entry.Content)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		filtered = append(filtered, entry)
+		if len(filtered) == maxLearnings {
+			break
+		}
+	}
+	return filtered
+}
+
+// recallLearnings returns the stored learnings relevant to a phase input, formatted
+// for the Inspiration and Goals Agents. Empty when learning is off or nothing is found.
+func recallLearnings(ctx *robottypes.Context, robot *robottypes.Robot, query string) string {
+	if robot.Config == nil || robot.Config.Learn == nil || !robot.Config.Learn.On || query == "" {
+		return ""
+	}
+
+	entries, err := store.NewLearningStore().Search(ctx.Context, robot, query, maxRecalledLearnings)
+	if err != nil {
+		log.Warn("failed to recall learnings of robot %s: %v", robot.MemberID, err)
+		return ""
+	}
+	return NewInputFormatter().FormatPastLearnings(entries)
+}
@@ -0,0 +1,193 @@
+package standard_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaoapp/yao/agent/robot/executor/standard"
+	"github.com/yaoapp/yao/agent/robot/types"
+	"github.com/yaoapp/yao/agent/testutils"
+)
+
+// ============================================================================
+// P5 Learning Phase Tests
+// ============================================================================
+
+func TestRunLearningDisabled(t *testing.T) {
+	ctx := types.NewContext(context.Background(), testAuth())
+
+	t.Run("skips when learning is not enabled", func(t *testing.T) {
+		robot := createLearningTestRobot(t, "robot.learning")
+		robot.Config.Learn = nil
+		exec := createLearningTestExecution(robot)
+
+		e := standard.New()
+		err := e.RunLearning(ctx, exec, nil)
+
+		require.NoError(t, err)
+		assert.Empty(t, exec.Learning)
+	})
+
+	t.Run("returns error when robot is nil", func(t *testing.T) {
+		exec := &types.Execution{ID: "test-exec-learning-nil"}
+
+		e := standard.New()
+		err := e.RunLearning(ctx, exec, nil)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestRunLearningBasic(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	testutils.Prepare(t)
+	defer testutils.Clean(t)
+
+	ctx := types.NewContext(context.Background(), testAuth())
+
+	t.Run("extracts learnings from execution", func(t *testing.T) {
+		robot := createLearningTestRobot(t, "robot.learning")
+		exec := createLearningTestExecution(robot)
+
+		e := standard.New()
+		err := e.RunLearning(ctx, exec, nil)
+
+		// Learning never fails the execution
+		require.NoError(t, err)
+		for _, entry := range exec.Learning {
+			assert.NotEmpty(t, entry.Content)
+			assert.Contains(t, []types.LearningType{types.LearnExecution, types.LearnFeedback, types.LearnInsight}, entry.Type)
+		}
+	})
+
+	t.Run("skips when agent is not configured", func(t *testing.T) {
+		robot := createLearningTestRobot(t, "")
+		robot.Config.Resources.Phases = map[types.Phase]string{types.PhaseLearning: ""}
+		exec := createLearningTestExecution(robot)
+
+		e := standard.New()
+		err := e.RunLearning(ctx, exec, nil)
+
+		require.NoError(t, err)
+	})
+}
+
+func TestParseLearnings(t *testing.T) {
+	t.Run("parses learnings object", func(t *testing.T) {
+		data := map[string]interface{}{
+			"learnings": []interface{}{
+				map[string]interface{}{"type": "feedback", "content": " Retry the CRM export after 5 minutes ", "tags": []interface{}{"crm", "retry"}},
+				map[string]interface{}{"type": "Execution", "content": "Weekly report takes 3 tasks"},
+				map[string]interface{}{"type": "unknown", "content": "Sales peak on Mondays"},
+				map[string]interface{}{"type": "insight", "content": ""},
+				"not an object",
+			},
+		}
+
+		entries := standard.ParseLearnings(data)
+
+		require.Len(t, entries, 3)
+		assert.Equal(t, types.LearnFeedback, entries[0].Type)
+		assert.Equal(t, "Retry the CRM export after 5 minutes", entries[0].Content)
+		assert.Equal(t, []string{"crm", "retry"}, entries[0].Tags)
+		assert.Equal(t, types.LearnExecution, entries[1].Type)
+		assert.Equal(t, types.LearnInsight, entries[2].Type)
+	})
+
+	t.Run("parses bare array", func(t *testing.T) {
+		entries := standard.ParseLearnings([]interface{}{
+			map[string]interface{}{"type": "insight", "content": "Use the summary agent for long pages"},
+		})
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("returns empty for invalid data", func(t *testing.T) {
+		assert.Empty(t, standard.ParseLearnings(nil))
+		assert.Empty(t, standard.ParseLearnings("text"))
+		assert.Empty(t, standard.ParseLearnings(map[string]interface{}{"content": "no learnings key"}))
+	})
+}
+
+func TestFilterLearnings(t *testing.T) {
+	entries := []types.LearningEntry{
+		{Type: types.LearnExecution, Content: "Report generated in 3 steps"},
+		{Type: types.LearnFeedback, Content: "The CRM API times out before 9am."},
+		{Type: types.LearnFeedback, Content: "the crm api times out before 9am"},
+		{Type: types.LearnInsight, Content: "Sales peak on Mondays"},
+	}
+	known := []types.LearningEntry{
+		{Type: types.LearnInsight, Content: "Sales peak on Mondays!"},
+	}
+
+	t.Run("drops known and repeated learnings", func(t *testing.T) {
+		filtered := standard.FilterLearnings(entries, &types.Learn{On: true}, known)
+
+		require.Len(t, filtered, 2)
+		assert.Equal(t, "Report generated in 3 steps", filtered[0].Content)
+		assert.Equal(t, "The CRM API times out before 9am.", filtered[1].Content)
+	})
+
+	t.Run("keeps configured types only", func(t *testing.T) {
+		filtered := standard.FilterLearnings(entries, &types.Learn{On: true, Types: []string{"feedback"}}, nil)
+
+		require.Len(t, filtered, 1)
+		assert.Equal(t, types.LearnFeedback, filtered[0].Type)
+	})
+}
+
+// ============================================================================
+// Helper Functions
+// ============================================================================
+
+func createLearningTestRobot(t *testing.T, agentID string) *types.Robot {
+	t.Helper()
+	return &types.Robot{
+		MemberID:    "test-robot-learning",
+		TeamID:      "test-team-1",
+		DisplayName: "Learning Test Robot",
+		Config: &types.Config{
+			Identity: &types.Identity{
+				Role:   "Sales Analyst",
+				Duties: []string{"Analyze sales", "Make weekly reports"},
+			},
+			Resources: &types.Resources{
+				Phases: map[types.Phase]string{
+					types.PhaseLearning: agentID,
+				},
+			},
+			Learn: &types.Learn{On: true},
+		},
+	}
+}
+
+func createLearningTestExecution(robot *types.Robot) *types.Execution {
+	start := time.Now().Add(-5 * time.Minute)
+	end := time.Now()
+	exec := &types.Execution{
+		ID:          "test-exec-learning-1",
+		MemberID:    robot.MemberID,
+		TeamID:      robot.TeamID,
+		TriggerType: types.TriggerClock,
+		StartTime:   start,
+		EndTime:     &end,
+		Status:      types.ExecCompleted,
+		Phase:       types.PhaseLearning,
+		Goals:       &types.Goals{Content: "## Goals\n\n1. Export this week's sales from the CRM\n2. Send the weekly report"},
+		Tasks: []types.Task{
+			{ID: "task-001", Description: "Export sales", ExecutorID: "experts.data-analyst", Status: types.TaskFailed},
+			{ID: "task-002", Description: "Write report", ExecutorID: "experts.text-writer", Status: types.TaskCompleted, Source: types.TaskSourceHuman},
+		},
+		Results: []types.TaskResult{
+			{TaskID: "task-001", Success: false, Error: "CRM API timeout after 30s", Duration: 30000},
+			{TaskID: "task-002", Success: true, Duration: 1200},
+		},
+	}
+	exec.SetRobot(robot)
+	return exec
+}
@@ -17,6 +17,15 @@ type RunConfig struct {
 	ContinueOnFailure bool
 }
 
+// TaskFunc runs a single P3 task in place of the in-process Runner
+// (the sandbox executor runs tasks inside a container)
+type TaskFunc func(ctx *robottypes.Context, exec *robottypes.Execution, task *robottypes.Task, taskCtx *RunnerContext) *robottypes.TaskResult
+
+// SetTaskFunc replaces the in-process Runner for P3 tasks, nil restores it
+func (e *Executor) SetTaskFunc(fn TaskFunc) {
+	e.runTask = fn
+}
+
 // DefaultRunConfig returns the default P3 configuration
 func DefaultRunConfig() *RunConfig {
 	return &RunConfig{
@@ -95,7 +104,12 @@ func (e *Executor) RunExecution(ctx *robottypes.Context, exec *robottypes.Execut
 		taskCtx := runner.BuildTaskContext(exec, i)
 
 		// Execute task (single call, no validation loop)
-		result := runner.ExecuteTask(task, taskCtx)
+		var result *robottypes.TaskResult
+		if e.runTask != nil {
+			result = e.runTask(ctx, exec, task, taskCtx)
+		} else {
+			result = runner.ExecuteTask(task, taskCtx)
+		}
 
 		// Task needs human input — suspend execution without recording a half-result
 		if result.NeedInput {
@@ -25,6 +25,9 @@ func BuildTriggerInput(trigger robottypes.TriggerType, data interface{}) *robott
 		}
 
 	case robottypes.TriggerEvent:
+		if existing, ok := data.(*robottypes.TriggerInput); ok {
+			return existing
+		}
 		if req, ok := data.(*robottypes.EventRequest); ok {
 			input.Source = robottypes.EventSource(req.Source)
 			input.EventType = req.EventType
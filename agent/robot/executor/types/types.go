@@ -91,29 +91,36 @@ type DryRunConfig struct {
 }
 
 // SandboxConfig holds sandbox specific configuration
-//
-// ⚠️ NOT IMPLEMENTED: These settings are placeholders for future
-// container-based isolation. True sandbox requires infrastructure support
-// (Docker/gVisor/Firecracker). Current implementation behaves like DryRun.
+// P3 tasks run inside a sandbox/v2 box created for each execution; the
+// other phases run like the standard executor
 type SandboxConfig struct {
 	Config
 
 	// MaxDuration limits total execution time
 	MaxDuration time.Duration
 
-	// MaxMemory limits memory usage (bytes) - requires container runtime
+	// MaxMemory limits the box memory (bytes), 0 = no limit
 	MaxMemory int64
 
+	// MaxCPUs limits the box CPUs, 0 = no limit
+	MaxCPUs float64
+
+	// Image is the box image, it must provide Command
+	Image string
+
+	// Command runs one task inside the box (see the sandbox package for the protocol)
+	Command []string
+
 	// AllowedAgents restricts which agents can be called
 	AllowedAgents []string
 
 	// AllowedTools restricts which tools can be used
 	AllowedTools []string
 
-	// NetworkAccess controls network access - requires container networking
+	// NetworkAccess gives the box network access, needed to call back Yao over gRPC
 	NetworkAccess bool
 
-	// FileAccess controls file system access - requires container filesystem
+	// FileAccess mounts the robot workspace read-write instead of read-only
 	FileAccess bool
 }
 
@@ -123,7 +130,7 @@ type Mode string
 const (
 	ModeStandard Mode = "standard" // Real Agent execution (production)
 	ModeDryRun   Mode = "dryrun"   // Simulated execution (testing/demo)
-	ModeSandbox  Mode = "sandbox"  // Container-isolated execution (NOT IMPLEMENTED)
+	ModeSandbox  Mode = "sandbox"  // Container-isolated task execution
 )
 
 // Setting holds executor settings from configuration
@@ -131,6 +138,8 @@ type Setting struct {
 	Mode          Mode          `json:"mode,omitempty" yaml:"mode,omitempty"`                     // Executor mode
 	MaxDuration   time.Duration `json:"max_duration,omitempty" yaml:"max_duration,omitempty"`     // Max execution time
 	MaxMemory     int64         `json:"max_memory,omitempty" yaml:"max_memory,omitempty"`         // Max memory (bytes)
+	MaxCPUs       float64       `json:"max_cpus,omitempty" yaml:"max_cpus,omitempty"`             // Max CPUs
+	Image         string        `json:"image,omitempty" yaml:"image,omitempty"`                   // Sandbox image
 	AllowedAgents []string      `json:"allowed_agents,omitempty" yaml:"allowed_agents,omitempty"` // Allowed agent IDs
 	NetworkAccess bool          `json:"network_access,omitempty" yaml:"network_access,omitempty"` // Allow network
 	FileAccess    bool          `json:"file_access,omitempty" yaml:"file_access,omitempty"`       // Allow file system
@@ -0,0 +1,76 @@
+package manager
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/yao/agent/robot/trigger"
+	"github.com/yaoapp/yao/agent/robot/types"
+)
+
+// startDatabaseTriggers subscribes to the model changes for the database events of the robots
+func (m *Manager) startDatabaseTriggers() {
+	m.dbBatcher = trigger.NewChangeBatcher(m.submitModelChanges)
+	m.dbUnsubscribe = trigger.OnModelChange(m.handleModelChange)
+}
+
+// stopDatabaseTriggers stops listening to the model changes, pending batches are dropped
+func (m *Manager) stopDatabaseTriggers() {
+	if m.dbUnsubscribe != nil {
+		m.dbUnsubscribe()
+		m.dbUnsubscribe = nil
+	}
+	if m.dbBatcher != nil {
+		if dropped := m.dbBatcher.Stop(); dropped > 0 {
+			log.Warn("%d batched model changes dropped on stop", dropped)
+		}
+	}
+}
+
+// handleModelChange routes a model change to the robots with a matching database event
+func (m *Manager) handleModelChange(change trigger.ModelChange) {
+	batcher := m.dbBatcher
+	if batcher == nil {
+		return
+	}
+
+	loaded := false
+	for _, robot := range m.cache.ListAll() {
+		if robot.Config == nil || robot.Status == types.RobotPaused {
+			continue
+		}
+		for i, ev := range robot.Config.Events {
+			if ev.Type != types.EventDatabase || !strings.EqualFold(ev.Source, change.Model) {
+				continue
+			}
+			// Filters are checked against the full row, loaded once for all robots
+			if !loaded {
+				change.Load()
+				loaded = true
+			}
+			if trigger.MatchModelChange(ev, change) {
+				batcher.Add(robot.MemberID, i, ev, change)
+			}
+		}
+	}
+}
+
+// submitModelChanges triggers a robot execution with a batch of model changes
+func (m *Manager) submitModelChanges(memberID string, ev types.Event, changes []trigger.ModelChange) {
+	req := &types.EventRequest{
+		MemberID:  memberID,
+		Source:    ev.Source,
+		EventType: trigger.ChangeEventType(changes),
+		Data:      trigger.ChangeEventData(changes),
+	}
+
+	ctx := types.NewContext(m.ctx, nil)
+	if _, err := m.HandleEvent(ctx, req); err != nil {
+		if errors.Is(err, types.ErrDuplicateExecution) || errors.Is(err, types.ErrTriggerDisabled) {
+			log.Debug("database event %s of robot %s skipped: %v", req.EventType, memberID, err)
+			return
+		}
+		log.Error("database event %s of robot %s failed: %v", req.EventType, memberID, err)
+	}
+}
@@ -6,10 +6,13 @@ import (
 	"sync"
 	"time"
 
+	"github.com/yaoapp/kun/log"
 	"github.com/yaoapp/yao/agent/robot/cache"
+	"github.com/yaoapp/yao/agent/robot/dedup"
 	"github.com/yaoapp/yao/agent/robot/events"
 	"github.com/yaoapp/yao/agent/robot/executor"
 	"github.com/yaoapp/yao/agent/robot/pool"
+	"github.com/yaoapp/yao/agent/robot/store"
 	"github.com/yaoapp/yao/agent/robot/trigger"
 	"github.com/yaoapp/yao/agent/robot/types"
 	"github.com/yaoapp/yao/event"
@@ -26,6 +29,7 @@ type Config struct {
 	TickInterval time.Duration  // how often to check clock triggers (default: 1 minute)
 	PoolConfig   *pool.Config   // worker pool configuration
 	Executor     types.Executor // optional: custom executor (default: real executor)
+	Dedup        types.Dedup    // optional: custom dedup (default: dedup persisted with the robot store)
 }
 
 // DefaultConfig returns default manager configuration
@@ -43,6 +47,10 @@ type Manager struct {
 	cache    *cache.Cache
 	pool     *pool.Pool
 	executor types.Executor
+	dedup    types.Dedup
+
+	// Serializes dedup check, submit and mark, so that concurrent duplicates are caught
+	dedupMu sync.Mutex
 
 	// Execution control for pause/resume/stop
 	execController *trigger.ExecutionController
@@ -51,6 +59,10 @@ type Manager struct {
 	ticker     *time.Ticker
 	tickerDone chan struct{}
 
+	// Database change triggers
+	dbBatcher     *trigger.ChangeBatcher
+	dbUnsubscribe func()
+
 	// State
 	started bool
 	mu      sync.RWMutex
@@ -89,12 +101,19 @@ func NewWithConfig(config *Config) *Manager {
 		e = executor.New()
 	}
 
+	// Use custom dedup if provided, otherwise persist the dedup windows with the robots
+	d := config.Dedup
+	if d == nil {
+		d = dedup.NewWithStore(store.NewRobotStore())
+	}
+
 	// Wire up pool with executor
 	p.SetExecutor(e)
 
 	// Create shared executor instances for each mode
 	// These are reused across all executions to maintain accurate counters
 	dryRunExecutor := executor.NewDryRun()
+	sandboxExecutor := executor.NewSandbox()
 
 	// Set executor factory for mode-specific executors
 	p.SetExecutorFactory(func(mode types.ExecutorMode) types.Executor {
@@ -102,8 +121,7 @@ func NewWithConfig(config *Config) *Manager {
 		case types.ExecutorDryRun:
 			return dryRunExecutor
 		case types.ExecutorSandbox:
-			// Sandbox not implemented, fall back to DryRun
-			return dryRunExecutor
+			return sandboxExecutor
 		default:
 			// Standard mode or empty - use the configured executor
 			return e
@@ -115,6 +133,7 @@ func NewWithConfig(config *Config) *Manager {
 		cache:          c,
 		pool:           p,
 		executor:       e,
+		dedup:          d,
 		execController: ec,
 	}
 }
@@ -164,6 +183,9 @@ func (m *Manager) Start() error {
 
 	go m.tickerLoop()
 
+	// Listen to model changes for database event triggers
+	m.startDatabaseTriggers()
+
 	// Start cache auto-refresh (every hour)
 	m.cache.StartAutoRefresh(ctx, nil)
 
@@ -181,7 +203,7 @@ func (m *Manager) Start() error {
 }
 
 // Stop stops the manager gracefully
-// 1. Stop clock ticker
+// 1. Stop clock ticker and database triggers
 // 2. Stop cache auto-refresh
 // 3. Stop worker pool (waits for running jobs)
 func (m *Manager) Stop() error {
@@ -198,6 +220,9 @@ func (m *Manager) Stop() error {
 		close(m.tickerDone)
 	}
 
+	// Stop database triggers
+	m.stopDatabaseTriggers()
+
 	// Stop cache auto-refresh
 	m.cache.StopAutoRefresh()
 
@@ -268,11 +293,20 @@ func (m *Manager) Tick(parentCtx context.Context, now time.Time) error {
 			continue
 		}
 
-		// TODO: dedup check (Phase 11.1)
-		// result, err := m.dedup.Check(ctx, robot.MemberID, types.TriggerClock)
-		// if err != nil || result == types.DedupSkip {
-		//     continue
-		// }
+		// Create context with robot's own identity
+		robotAuth := m.buildRobotAuth(robot)
+
+		// Create clock context for P0 inspiration
+		clockCtx := types.NewClockContext(now, robot.Config.Clock.TZ)
+
+		// Skip the tick if it already started an execution (e.g. before a restart)
+		// Daemon robots restart as soon as a slot is free, the slot quota limits them
+		dedupClock := robot.Config.Clock.Mode != types.ClockDaemon
+		if dedupClock {
+			if result, _ := m.dedupCheck(types.NewContext(parentCtx, robotAuth), robot, types.TriggerClock, clockCtx); result != types.DedupProceed {
+				continue
+			}
+		}
 
 		// Pre-generate execution ID
 		execID := pool.GenerateExecID()
@@ -296,12 +330,8 @@ func (m *Manager) Tick(parentCtx context.Context, now time.Time) error {
 		ctrlExec := m.execController.Track(execID, robot.MemberID, robot.TeamID)
 
 		// Create context with robot's own identity and cancellable context
-		robotAuth := m.buildRobotAuth(robot)
 		execCtx := types.NewContext(ctrlExec.Context(), robotAuth)
 
-		// Create clock context for P0 inspiration
-		clockCtx := types.NewClockContext(now, robot.Config.Clock.TZ)
-
 		// Submit to pool with the cancellable context and execution control
 		_, err := m.pool.SubmitWithID(execCtx, robot, types.TriggerClock, clockCtx, execID, ctrlExec)
 		if err != nil {
@@ -309,6 +339,9 @@ func (m *Manager) Tick(parentCtx context.Context, now time.Time) error {
 			m.execController.Untrack(execID)
 			continue
 		}
+		if dedupClock {
+			m.dedupMark(execCtx, robot, types.TriggerClock, clockCtx, execID)
+		}
 
 		// Update robot's last run time
 		robot.LastRun = now
@@ -424,7 +457,7 @@ func (m *Manager) matchesDay(clock *types.Clock, now time.Time) bool {
 }
 
 // TriggerManual manually triggers a robot execution (for testing or API calls)
-// This bypasses clock checking and directly submits to pool
+// This bypasses clock checking and dedup and directly submits to pool
 // For non-autonomous robots: lazy-loads from DB, executes, then unloads
 func (m *Manager) TriggerManual(ctx *types.Context, memberID string, trigger types.TriggerType, data interface{}) (string, error) {
 	m.mu.RLock()
@@ -539,6 +572,16 @@ func (m *Manager) Intervene(ctx *types.Context, req *types.InterveneRequest) (*t
 		}, nil
 	}
 
+	// Skip duplicates of an intervention submitted within the dedup window
+	m.dedupMu.Lock()
+	defer m.dedupMu.Unlock()
+	if result, matched := m.dedupCheck(ctx, robot, types.TriggerHuman, triggerInput); result == types.DedupSkip {
+		if lazyLoaded {
+			m.cache.Remove(req.MemberID)
+		}
+		return nil, fmt.Errorf("%w (execution %s)", types.ErrDuplicateExecution, matched)
+	}
+
 	// Determine executor mode: request > robot config > default
 	executorMode := m.resolveExecutorMode(req.ExecutorMode, robot)
 
@@ -551,6 +594,7 @@ func (m *Manager) Intervene(ctx *types.Context, req *types.InterveneRequest) (*t
 		}
 		return nil, err
 	}
+	m.dedupMark(ctx, robot, types.TriggerHuman, triggerInput, execID)
 
 	// Track execution for pause/resume/stop
 	m.execController.Track(execID, req.MemberID, req.TeamID)
@@ -604,6 +648,27 @@ func (m *Manager) HandleEvent(ctx *types.Context, req *types.EventRequest) (*typ
 	// Build trigger input
 	triggerInput := trigger.BuildEventInput(req)
 
+	// Skip repeated events, fold new payloads into a running execution when merge is on
+	m.dedupMu.Lock()
+	defer m.dedupMu.Unlock()
+	switch result, matched := m.dedupCheck(ctx, robot, types.TriggerEvent, triggerInput); result {
+	case types.DedupSkip:
+		if lazyLoaded {
+			m.cache.Remove(req.MemberID)
+		}
+		return nil, fmt.Errorf("%w (execution %s)", types.ErrDuplicateExecution, matched)
+
+	case types.DedupMerge:
+		if m.mergeEvent(ctx, robot, matched, triggerInput) {
+			return &types.ExecutionResult{
+				ExecutionID: matched,
+				Status:      types.ExecRunning,
+				Message:     fmt.Sprintf("Event trigger (%s: %s) merged into running execution", req.Source, req.EventType),
+			}, nil
+		}
+		// The execution finished in the meantime, start a new one
+	}
+
 	// Determine executor mode: request > robot config > default
 	executorMode := m.resolveExecutorMode(req.ExecutorMode, robot)
 
@@ -616,6 +681,7 @@ func (m *Manager) HandleEvent(ctx *types.Context, req *types.EventRequest) (*typ
 		}
 		return nil, err
 	}
+	m.dedupMark(ctx, robot, types.TriggerEvent, triggerInput, execID)
 
 	// Track execution for pause/resume/stop
 	m.execController.Track(execID, req.MemberID, "")
@@ -787,6 +853,46 @@ func (m *Manager) scheduleCleanup(robot *types.Robot) {
 	}()
 }
 
+// dedupCheck checks a trigger against the robot's dedup windows
+// A failing dedup store never blocks a trigger: the error is logged and the trigger proceeds
+func (m *Manager) dedupCheck(ctx *types.Context, robot *types.Robot, trigger types.TriggerType, data interface{}) (types.DedupResult, string) {
+	result, matched, err := m.dedup.Check(ctx, robot, trigger, data)
+	if err != nil {
+		log.Warn("dedup check failed for %s (%s): %v", robot.MemberID, trigger, err)
+		return types.DedupProceed, ""
+	}
+	if result == types.DedupSkip {
+		log.Info("duplicate %s trigger for %s skipped (execution %s)", trigger, robot.MemberID, matched)
+	}
+	return result, matched
+}
+
+// dedupMark opens the dedup window of a submitted execution
+func (m *Manager) dedupMark(ctx *types.Context, robot *types.Robot, trigger types.TriggerType, data interface{}, execID string) {
+	if err := m.dedup.Mark(ctx, robot, trigger, data, execID); err != nil {
+		log.Warn("failed to save dedup mark for %s (%s): %v", robot.MemberID, execID, err)
+	}
+}
+
+// mergeEvent folds an event into a running execution and saves the merged input
+// Returns false when the execution is no longer running
+func (m *Manager) mergeEvent(ctx *types.Context, robot *types.Robot, execID string, input *types.TriggerInput) bool {
+	exec := robot.GetExecution(execID)
+	if exec == nil || (exec.Status != types.ExecPending && exec.Status != types.ExecRunning) {
+		return false
+	}
+
+	merged := exec.MergeEvent(types.MergedEvent{
+		EventType:  input.EventType,
+		Data:       input.Data,
+		ReceivedAt: time.Now(),
+	})
+	if err := store.NewExecutionStore().UpdateInput(ctx.Context, execID, merged); err != nil {
+		log.Warn("failed to save merged event of execution %s: %v", execID, err)
+	}
+	return true
+}
+
 // resolveExecutorMode determines the executor mode to use
 // Priority: request > robot config > default (standard)
 func (m *Manager) resolveExecutorMode(requestMode types.ExecutorMode, robot *types.Robot) types.ExecutorMode {
@@ -0,0 +1,91 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/yaoapp/gou/model"
+	"github.com/yaoapp/yao/agent/robot/types"
+)
+
+// DedupMark - a submitted execution that opens a dedup window
+// Saved with the robot (__yao.member.robot_dedup) so that the windows survive restarts
+type DedupMark struct {
+	Trigger     types.TriggerType `json:"trigger"`
+	Fingerprint string            `json:"fingerprint"`     // hash of the trigger input
+	Group       string            `json:"group,omitempty"` // event source and type, for merging
+	ExecutionID string            `json:"execution_id"`
+	ExpiresAt   time.Time         `json:"expires_at"`
+}
+
+// GetDedupMarks returns the dedup marks saved with a robot
+func (s *RobotStore) GetDedupMarks(ctx context.Context, memberID string) ([]DedupMark, error) {
+	mod := model.Select(s.modelID)
+	if mod == nil {
+		return nil, fmt.Errorf("model %s not found", s.modelID)
+	}
+
+	rows, err := mod.Get(model.QueryParam{
+		Select: []interface{}{"robot_dedup"},
+		Wheres: []model.QueryWhere{
+			{Column: "member_id", Value: memberID},
+			{Column: "member_type", Value: "robot"},
+		},
+		Limit: 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get robot dedup marks: %w", err)
+	}
+	if len(rows) == 0 || rows[0]["robot_dedup"] == nil {
+		return []DedupMark{}, nil
+	}
+
+	var data []byte
+	switch v := rows[0]["robot_dedup"].(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		data, err = json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse robot dedup marks: %w", err)
+		}
+	}
+
+	marks := []DedupMark{}
+	if len(data) == 0 || string(data) == "null" {
+		return marks, nil
+	}
+	if err := json.Unmarshal(data, &marks); err != nil {
+		return nil, fmt.Errorf("failed to parse robot dedup marks: %w", err)
+	}
+	return marks, nil
+}
+
+// UpdateDedupMarks replaces the dedup marks saved with a robot
+func (s *RobotStore) UpdateDedupMarks(ctx context.Context, memberID string, marks []DedupMark) error {
+	mod := model.Select(s.modelID)
+	if mod == nil {
+		return fmt.Errorf("model %s not found", s.modelID)
+	}
+
+	if marks == nil {
+		marks = []DedupMark{}
+	}
+	_, err := mod.UpdateWhere(
+		model.QueryParam{
+			Wheres: []model.QueryWhere{
+				{Column: "member_id", Value: memberID},
+				{Column: "member_type", Value: "robot"},
+			},
+		},
+		map[string]interface{}{"robot_dedup": marks},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update robot dedup marks: %w", err)
+	}
+	return nil
+}
@@ -366,6 +366,28 @@ func (s *ExecutionStore) UpdatePhase(ctx context.Context, executionID string, ph
 	return nil
 }
 
+// UpdateInput updates the trigger input (e.g. events merged by dedup)
+func (s *ExecutionStore) UpdateInput(ctx context.Context, executionID string, input *types.TriggerInput) error {
+	mod := model.Select(s.modelID)
+	if mod == nil {
+		return fmt.Errorf("model %s not found", s.modelID)
+	}
+
+	_, err := mod.UpdateWhere(
+		model.QueryParam{
+			Wheres: []model.QueryWhere{
+				{Column: "execution_id", Value: executionID},
+			},
+		},
+		map[string]interface{}{"input": input},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update input: %w", err)
+	}
+
+	return nil
+}
+
 // UpdateStatus updates the execution status
 func (s *ExecutionStore) UpdateStatus(ctx context.Context, executionID string, status types.ExecStatus, errorMsg string) error {
 	mod := model.Select(s.modelID)
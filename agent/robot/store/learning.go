@@ -0,0 +1,313 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/yaoapp/yao/agent/assistant"
+	"github.com/yaoapp/yao/agent/robot/types"
+	"github.com/yaoapp/yao/kb"
+	kbapi "github.com/yaoapp/yao/kb/api"
+)
+
+// DuplicateThreshold is the search score above which a learning is considered already known
+var DuplicateThreshold = 0.9
+
+// LearningStore - persistent storage for robot learnings
+// Each learning is a text document in the robot's learning collection:
+// Learn.Collection if configured, otherwise the private KB robot_{team_id}_{member_id}_kb,
+// created on first save with the chat KB settings (agent/kb.yml)
+type LearningStore struct {
+	api kbapi.API
+}
+
+// NewLearningStore creates a new learning store on the global KB
+func NewLearningStore() *LearningStore {
+	return &LearningStore{}
+}
+
+// NewLearningStoreWithAPI creates a new learning store on the given KB API
+func NewLearningStoreWithAPI(api kbapi.API) *LearningStore {
+	return &LearningStore{api: api}
+}
+
+// LearningCollectionID returns the KB collection that stores the learnings of a robot
+func LearningCollectionID(robot *types.Robot) string {
+	if robot.Config != nil && robot.Config.Learn != nil && robot.Config.Learn.Collection != "" {
+		return robot.Config.Learn.Collection
+	}
+	return fmt.Sprintf("robot_%s_%s_kb", sanitizeID(robot.TeamID), sanitizeID(robot.MemberID))
+}
+
+// LearningDocID returns the document ID of a learning
+// The ID is derived from the normalized content, so the same learning is stored once
+func LearningDocID(collectionID string, content string) string {
+	sum := sha256.Sum256([]byte(NormalizeLearning(content)))
+	return fmt.Sprintf("%s__learn_%s", collectionID, hex.EncodeToString(sum[:8]))
+}
+
+// NormalizeLearning lowercases a learning and drops punctuation and extra spaces
+func NormalizeLearning(content string) string {
+	fields := strings.FieldsFunc(strings.ToLower(content), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// Search returns the stored learnings relevant to a query, best match first
+func (s *LearningStore) Search(ctx context.Context, robot *types.Robot, query string, limit int) ([]types.LearningEntry, error) {
+	api, err := s.kbAPI()
+	if err != nil {
+		return nil, err
+	}
+
+	collectionID := LearningCollectionID(robot)
+	exists, err := api.CollectionExists(ctx, collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check learning collection %s: %w", collectionID, err)
+	}
+	if exists == nil || !exists.Exists {
+		return []types.LearningEntry{}, nil
+	}
+
+	result, err := api.Search(ctx, []kbapi.Query{{
+		CollectionID: collectionID,
+		Input:        query,
+		Mode:         kbapi.SearchModeVector,
+		Page:         1,
+		PageSize:     limit,
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search learnings: %w", err)
+	}
+
+	entries := []types.LearningEntry{}
+	for _, seg := range result.Segments {
+		if seg.Text == "" {
+			continue
+		}
+		entries = append(entries, learningFromMetadata(seg.Text, seg.Metadata))
+	}
+	return entries, nil
+}
+
+// Save saves learning entries to the robot's learning collection and returns the saved ones
+// Entries already stored, identically or with a search score above DuplicateThreshold, are skipped
+func (s *LearningStore) Save(ctx context.Context, robot *types.Robot, executionID string, entries []types.LearningEntry) ([]types.LearningEntry, error) {
+	if len(entries) == 0 {
+		return []types.LearningEntry{}, nil
+	}
+
+	api, err := s.kbAPI()
+	if err != nil {
+		return nil, err
+	}
+
+	setting := assistant.GetGlobalKBSetting()
+	if setting == nil || setting.Chat == nil || setting.Chat.EmbeddingProviderID == "" {
+		return nil, fmt.Errorf("no embedding configured for robot learning (set chat in agent/kb.yml)")
+	}
+
+	collectionID := LearningCollectionID(robot)
+	created, err := s.ensureCollection(ctx, api, robot, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	chunking, err := learningChunking(setting.Chat.Locale)
+	if err != nil {
+		return nil, err
+	}
+	embedding := &kbapi.ProviderConfigParams{
+		ProviderID: setting.Chat.EmbeddingProviderID,
+		OptionID:   setting.Chat.EmbeddingOptionID,
+	}
+
+	saved := []types.LearningEntry{}
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		docID := LearningDocID(collectionID, entry.Content)
+		if seen[docID] || (!created && s.known(ctx, api, collectionID, docID, entry.Content)) {
+			continue
+		}
+		seen[docID] = true
+
+		metadata := map[string]interface{}{
+			"title":         truncateTitle(entry.Content),
+			"source":        "robot_learning",
+			"learning_type": string(entry.Type),
+			"member_id":     robot.MemberID,
+			"team_id":       robot.TeamID,
+			"execution_id":  executionID,
+			"learned_at":    time.Now().Format(time.RFC3339),
+		}
+		if len(entry.Tags) > 0 {
+			metadata["tags"] = entry.Tags
+		}
+
+		_, err := api.AddText(ctx, &kbapi.AddTextParams{
+			CollectionID: collectionID,
+			DocID:        docID,
+			Text:         entry.Content,
+			Locale:       setting.Chat.Locale,
+			Metadata:     metadata,
+			Chunking:     chunking,
+			Embedding:    embedding,
+			AuthScope:    learningAuthScope(robot),
+		})
+		if err != nil {
+			return saved, fmt.Errorf("failed to save learning: %w", err)
+		}
+		saved = append(saved, entry)
+	}
+	return saved, nil
+}
+
+// known checks whether a learning is already stored
+func (s *LearningStore) known(ctx context.Context, api kbapi.API, collectionID, docID, content string) bool {
+	if doc, err := api.GetDocument(ctx, docID, nil); err == nil && doc != nil {
+		return true
+	}
+
+	result, err := api.Search(ctx, []kbapi.Query{{
+		CollectionID: collectionID,
+		Input:        content,
+		Mode:         kbapi.SearchModeVector,
+		Threshold:    DuplicateThreshold,
+		Page:         1,
+		PageSize:     1,
+	}})
+	return err == nil && result != nil && len(result.Segments) > 0
+}
+
+// ensureCollection creates the learning collection if missing, returns true if it was created
+func (s *LearningStore) ensureCollection(ctx context.Context, api kbapi.API, robot *types.Robot, collectionID string) (bool, error) {
+	exists, err := api.CollectionExists(ctx, collectionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check learning collection %s: %w", collectionID, err)
+	}
+	if exists != nil && exists.Exists {
+		return false, nil
+	}
+
+	chat := assistant.GetGlobalKBSetting().Chat
+	metadata := map[string]interface{}{}
+	for k, v := range chat.Metadata {
+		metadata[k] = v
+	}
+	name := robot.DisplayName
+	if name == "" {
+		name = robot.MemberID
+	}
+	metadata["name"] = fmt.Sprintf("%s Learnings", name)
+	metadata["description"] = "Knowledge the robot learned from its executions"
+	metadata["team_id"] = robot.TeamID
+	metadata["member_id"] = robot.MemberID
+
+	_, err = api.CreateCollection(ctx, &kbapi.CreateCollectionParams{
+		ID:                  collectionID,
+		EmbeddingProviderID: chat.EmbeddingProviderID,
+		EmbeddingOptionID:   chat.EmbeddingOptionID,
+		Locale:              chat.Locale,
+		Config:              chat.Config,
+		Metadata:            metadata,
+		AuthScope:           learningAuthScope(robot),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to create learning collection %s: %w", collectionID, err)
+	}
+	return true, nil
+}
+
+// learningChunking returns the chunking provider: the chat KB default, or the default KB chunking provider
+func learningChunking(locale string) (*kbapi.ProviderConfigParams, error) {
+	setting := assistant.GetGlobalKBSetting()
+	if setting.Chat.DocumentDefaults != nil && setting.Chat.DocumentDefaults.Chunking != nil {
+		return &kbapi.ProviderConfigParams{
+			ProviderID: setting.Chat.DocumentDefaults.Chunking.ProviderID,
+			OptionID:   setting.Chat.DocumentDefaults.Chunking.OptionID,
+		}, nil
+	}
+
+	providers, err := kb.GetProviders("chunking", nil, locale)
+	if err != nil {
+		return nil, err
+	}
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no chunking provider configured")
+	}
+	provider := providers[0]
+	for _, p := range providers {
+		if p.Default {
+			provider = p
+			break
+		}
+	}
+	return &kbapi.ProviderConfigParams{ProviderID: provider.ID}, nil
+}
+
+// learningAuthScope returns the permission fields of the robot's learning documents
+func learningAuthScope(robot *types.Robot) map[string]interface{} {
+	scope := map[string]interface{}{"__yao_created_by": robot.MemberID}
+	if robot.TeamID != "" {
+		scope["__yao_team_id"] = robot.TeamID
+	}
+	return scope
+}
+
+// learningFromMetadata rebuilds a learning entry from a stored segment
+func learningFromMetadata(text string, metadata map[string]interface{}) types.LearningEntry {
+	entry := types.LearningEntry{Type: types.LearnInsight, Content: text}
+	if metadata == nil {
+		return entry
+	}
+	if t, ok := metadata["learning_type"].(string); ok && t != "" {
+		entry.Type = types.LearningType(t)
+	}
+	switch tags := metadata["tags"].(type) {
+	case []string:
+		entry.Tags = tags
+	case []interface{}:
+		for _, tag := range tags {
+			if s, ok := tag.(string); ok {
+				entry.Tags = append(entry.Tags, s)
+			}
+		}
+	}
+	return entry
+}
+
+// kbAPI returns the KB API
+func (s *LearningStore) kbAPI() (kbapi.API, error) {
+	if s.api != nil {
+		return s.api, nil
+	}
+	if kb.API == nil {
+		return nil, fmt.Errorf("knowledge base not initialized")
+	}
+	return kb.API, nil
+}
+
+// sanitizeID replaces the characters not allowed in a collection ID with underscores
+func sanitizeID(id string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, id)
+}
+
+// truncateTitle returns the first line of a learning, at most 80 characters
+func truncateTitle(content string) string {
+	title := strings.TrimSpace(strings.SplitN(content, "\n", 2)[0])
+	if runes := []rune(title); len(runes) > 80 {
+		return string(runes[:80]) + "..."
+	}
+	return title
+}
@@ -0,0 +1,37 @@
+package store_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/yao/agent/robot/store"
+	"github.com/yaoapp/yao/agent/robot/types"
+)
+
+// TestLearningCollectionID tests the private and configured learning collections
+func TestLearningCollectionID(t *testing.T) {
+	robot := &types.Robot{MemberID: "robot-001", TeamID: "team.1"}
+	assert.Equal(t, "robot_team_1_robot_001_kb", store.LearningCollectionID(robot))
+
+	robot.Config = &types.Config{Learn: &types.Learn{On: true, Collection: "sales_learnings"}}
+	assert.Equal(t, "sales_learnings", store.LearningCollectionID(robot))
+}
+
+// TestLearningDocID tests that equivalent learnings share a document ID
+func TestLearningDocID(t *testing.T) {
+	a := store.LearningDocID("robot_kb", "The CRM API times out before 9am.")
+	b := store.LearningDocID("robot_kb", "  the crm api times out, before 9AM ")
+	c := store.LearningDocID("robot_kb", "Sales peak on Mondays")
+
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+	assert.True(t, strings.HasPrefix(a, "robot_kb__learn_"))
+}
+
+// TestNormalizeLearning tests learning normalization
+func TestNormalizeLearning(t *testing.T) {
+	assert.Equal(t, "retry after 5 minutes", store.NormalizeLearning("Retry -- after 5 minutes!"))
+	assert.Equal(t, "销售 周一 最高", store.NormalizeLearning("销售，周一 最高。"))
+	assert.Equal(t, "", store.NormalizeLearning(" ... "))
+}
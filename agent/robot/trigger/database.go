@@ -0,0 +1,447 @@
+package trigger
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yaoapp/gou/model"
+	"github.com/yaoapp/gou/process"
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/yao/agent/robot/types"
+)
+
+// Model change actions
+const (
+	ChangeCreated = "created"
+	ChangeUpdated = "updated"
+	ChangeDeleted = "deleted"
+)
+
+// ModelChange is a write on a Yao model made through a models.* process
+type ModelChange struct {
+	Model  string                 `json:"model"`
+	Action string                 `json:"action"`
+	ID     interface{}            `json:"id,omitempty"`
+	Record map[string]interface{} `json:"record,omitempty"` // the written fields, the full row once loaded
+	Query  interface{}            `json:"query,omitempty"`  // the query of updatewhere / deletewhere / destroywhere
+	Time   time.Time              `json:"time"`
+}
+
+// modelWriteMethods are the models.* process methods that change data
+var modelWriteMethods = []string{
+	"create", "save", "update", "delete", "destroy", "insert", "upsert",
+	"updatewhere", "deletewhere", "destroywhere", "eachsave", "eachsaveafterdelete",
+}
+
+var (
+	changeMu        sync.RWMutex
+	changeListeners = map[int]func(ModelChange){}
+	changeNextID    int
+)
+
+// The models.* handlers are registered by the gou model package init, which runs before
+// this one. Wrapping them here, before anything can execute a process, avoids writing
+// process.Handlers while it is read.
+func init() {
+	hookModelProcesses()
+}
+
+// OnModelChange registers a listener for the model changes and returns the function
+// that removes it. Listeners run in a background goroutine after the write succeeded; changes
+// made without the models.* processes (e.g. raw queries) are not seen.
+func OnModelChange(fn func(ModelChange)) func() {
+	changeMu.Lock()
+	id := changeNextID
+	changeNextID++
+	changeListeners[id] = fn
+	changeMu.Unlock()
+
+	return func() {
+		changeMu.Lock()
+		delete(changeListeners, id)
+		changeMu.Unlock()
+	}
+}
+
+// hookModelProcesses wraps the models.* write process handlers
+// The wrappers publish nothing until a listener is registered
+func hookModelProcesses() {
+	for _, method := range modelWriteMethods {
+		key := "models." + method
+		handler, ok := process.Handlers[key]
+		if !ok {
+			log.Warn("[Robot] process %s is not registered, its writes do not trigger robots", key)
+			continue
+		}
+		process.Handlers[key] = wrapModelHandler(method, handler)
+	}
+}
+
+// wrapModelHandler publishes the changes of a successful write
+// A failed write panics (gou exceptions), so nothing is published
+func wrapModelHandler(method string, handler process.Handler) process.Handler {
+	return func(p *process.Process) interface{} {
+		res := handler(p)
+
+		changeMu.RLock()
+		listeners := make([]func(ModelChange), 0, len(changeListeners))
+		for _, fn := range changeListeners {
+			listeners = append(listeners, fn)
+		}
+		changeMu.RUnlock()
+		if len(listeners) == 0 {
+			return res
+		}
+
+		changes := ModelChanges(method, p.ID, p.Args, res)
+		if len(changes) > 0 {
+			go func() {
+				for _, change := range changes {
+					for _, fn := range listeners {
+						fn(change)
+					}
+				}
+			}()
+		}
+		return res
+	}
+}
+
+// ModelChanges returns the changes made by a models.* write process
+// upsert is reported as updated, it cannot tell whether the row existed
+func ModelChanges(method string, modelID string, args []interface{}, res interface{}) []ModelChange {
+	now := time.Now()
+	change := func(action string, id interface{}, record map[string]interface{}) ModelChange {
+		return ModelChange{Model: modelID, Action: action, ID: id, Record: record, Time: now}
+	}
+	arg := func(i int) interface{} {
+		if i < len(args) {
+			return args[i]
+		}
+		return nil
+	}
+	pk := primaryKey(modelID)
+
+	switch strings.ToLower(method) {
+	case "create":
+		return []ModelChange{change(ChangeCreated, res, toRecord(arg(0)))}
+
+	case "save":
+		row := toRecord(arg(0))
+		if id, ok := row[pk]; ok && id != nil {
+			return []ModelChange{change(ChangeUpdated, id, row)}
+		}
+		return []ModelChange{change(ChangeCreated, res, row)}
+
+	case "upsert":
+		return []ModelChange{change(ChangeUpdated, res, toRecord(arg(0)))}
+
+	case "update":
+		return []ModelChange{change(ChangeUpdated, arg(0), toRecord(arg(1)))}
+
+	case "delete", "destroy":
+		return []ModelChange{change(ChangeDeleted, arg(0), nil)}
+
+	case "updatewhere":
+		c := change(ChangeUpdated, nil, toRecord(arg(1)))
+		c.Query = arg(0)
+		return []ModelChange{c}
+
+	case "deletewhere", "destroywhere":
+		c := change(ChangeDeleted, nil, nil)
+		c.Query = arg(0)
+		return []ModelChange{c}
+
+	case "insert":
+		columns := toSlice(arg(0))
+		changes := []ModelChange{}
+		for _, values := range toSlice(arg(1)) {
+			row := map[string]interface{}{}
+			for i, value := range toSlice(values) {
+				if i < len(columns) {
+					row[fmt.Sprint(columns[i])] = value
+				}
+			}
+			changes = append(changes, change(ChangeCreated, row[pk], row))
+		}
+		return changes
+
+	case "eachsave":
+		return eachSaveChanges(change, pk, toSlice(arg(0)), toSlice(res))
+
+	case "eachsaveafterdelete":
+		changes := []ModelChange{}
+		for _, id := range toSlice(arg(0)) {
+			changes = append(changes, change(ChangeDeleted, id, nil))
+		}
+		return append(changes, eachSaveChanges(change, pk, toSlice(arg(1)), toSlice(res))...)
+	}
+	return nil
+}
+
+// eachSaveChanges returns the changes of the rows saved by eachsave, ids are in the row order
+func eachSaveChanges(change func(string, interface{}, map[string]interface{}) ModelChange, pk string, rows []interface{}, ids []interface{}) []ModelChange {
+	changes := []ModelChange{}
+	for i, r := range rows {
+		row := toRecord(r)
+		if id, ok := row[pk]; ok && id != nil {
+			changes = append(changes, change(ChangeUpdated, id, row))
+			continue
+		}
+		var id interface{}
+		if i < len(ids) {
+			id = ids[i]
+		}
+		changes = append(changes, change(ChangeCreated, id, row))
+	}
+	return changes
+}
+
+// Load replaces the written fields of a created or updated row with the full row
+// The written fields are kept when the row cannot be loaded
+func (c *ModelChange) Load() {
+	if c.Action == ChangeDeleted || c.ID == nil || !model.Exists(c.Model) {
+		return
+	}
+	row, err := model.Select(c.Model).Find(c.ID, model.QueryParam{})
+	if err != nil || row == nil {
+		return
+	}
+	c.Record = toRecord(row)
+}
+
+// MatchModelChange checks if a model change matches a database event config
+func MatchModelChange(ev types.Event, c ModelChange) bool {
+	if ev.Type != types.EventDatabase || !strings.EqualFold(ev.Source, c.Model) {
+		return false
+	}
+
+	if len(ev.Actions) > 0 {
+		matched := false
+		for _, action := range ev.Actions {
+			if normalizeAction(action) == c.Action {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	for field, want := range ev.Filter {
+		got, ok := c.Record[field]
+		if !ok || !matchValue(want, got) {
+			return false
+		}
+	}
+	return true
+}
+
+// ChangeEventType returns the event type of a batch of changes: "<model>.<action>",
+// or "<model>.changed" when the batch mixes actions
+func ChangeEventType(changes []ModelChange) string {
+	if len(changes) == 0 {
+		return ""
+	}
+	action := changes[0].Action
+	for _, c := range changes[1:] {
+		if c.Action != action {
+			action = "changed"
+			break
+		}
+	}
+	return changes[0].Model + "." + action
+}
+
+// ChangeEventData returns the trigger data of a batch of changes
+// A single change is passed as is, a batch as {"model", "count", "changes"}
+func ChangeEventData(changes []ModelChange) map[string]interface{} {
+	if len(changes) == 0 {
+		return nil
+	}
+	if len(changes) == 1 {
+		c := changes[0]
+		data := map[string]interface{}{
+			"model":  c.Model,
+			"action": c.Action,
+			"time":   c.Time.Format(time.RFC3339),
+		}
+		if c.ID != nil {
+			data["id"] = c.ID
+		}
+		if c.Record != nil {
+			data["record"] = c.Record
+		}
+		if c.Query != nil {
+			data["query"] = c.Query
+		}
+		return data
+	}
+
+	items := make([]interface{}, 0, len(changes))
+	for _, c := range changes {
+		items = append(items, ChangeEventData([]ModelChange{c}))
+	}
+	return map[string]interface{}{
+		"model":   changes[0].Model,
+		"count":   len(changes),
+		"changes": items,
+	}
+}
+
+// ChangeBatcher groups the changes of one robot event over the batch window
+type ChangeBatcher struct {
+	mu      sync.Mutex
+	pending map[string]*changeBatch
+	flush   func(memberID string, ev types.Event, changes []ModelChange)
+	stopped bool
+}
+
+type changeBatch struct {
+	memberID string
+	event    types.Event
+	changes  []ModelChange
+	timer    *time.Timer
+}
+
+// NewChangeBatcher creates a batcher that hands every batch to flush
+func NewChangeBatcher(flush func(memberID string, ev types.Event, changes []ModelChange)) *ChangeBatcher {
+	return &ChangeBatcher{pending: map[string]*changeBatch{}, flush: flush}
+}
+
+// Add adds a change to the batch of a robot event (index is the event position in the robot config)
+// Without a batch window the change is flushed at once
+func (b *ChangeBatcher) Add(memberID string, index int, ev types.Event, c ModelChange) {
+	window := ev.Batch.GetWindow()
+	if window == 0 {
+		b.flush(memberID, ev, []ModelChange{c})
+		return
+	}
+
+	key := fmt.Sprintf("%s|%d|%s", memberID, index, strings.ToLower(ev.Source))
+	b.mu.Lock()
+	if b.stopped {
+		b.mu.Unlock()
+		return
+	}
+	batch, ok := b.pending[key]
+	if !ok {
+		batch = &changeBatch{memberID: memberID, event: ev}
+		b.pending[key] = batch
+		batch.timer = time.AfterFunc(window, func() { b.flushKey(key) })
+	}
+	batch.changes = append(batch.changes, c)
+	full := len(batch.changes) >= ev.Batch.GetMax()
+	b.mu.Unlock()
+
+	if full {
+		b.flushKey(key)
+	}
+}
+
+// flushKey flushes one pending batch
+func (b *ChangeBatcher) flushKey(key string) {
+	b.mu.Lock()
+	batch, ok := b.pending[key]
+	if ok {
+		delete(b.pending, key)
+		batch.timer.Stop()
+	}
+	b.mu.Unlock()
+
+	if ok && len(batch.changes) > 0 {
+		b.flush(batch.memberID, batch.event, batch.changes)
+	}
+}
+
+// Pending returns the number of changes waiting in batches
+func (b *ChangeBatcher) Pending() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := 0
+	for _, batch := range b.pending {
+		n += len(batch.changes)
+	}
+	return n
+}
+
+// Stop drops the pending batches and ignores new changes, returns the number of dropped changes
+func (b *ChangeBatcher) Stop() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stopped = true
+	n := 0
+	for key, batch := range b.pending {
+		batch.timer.Stop()
+		n += len(batch.changes)
+		delete(b.pending, key)
+	}
+	return n
+}
+
+// normalizeAction accepts create / update / delete for created / updated / deleted
+func normalizeAction(action string) string {
+	action = strings.ToLower(strings.TrimSpace(action))
+	if !strings.HasSuffix(action, "d") {
+		action += "d"
+	}
+	return action
+}
+
+// matchValue compares a filter value with a record value, a list matches any of its values
+func matchValue(want, got interface{}) bool {
+	if list := toSlice(want); list != nil {
+		for _, w := range list {
+			if matchValue(w, got) {
+				return true
+			}
+		}
+		return false
+	}
+	return fmt.Sprint(want) == fmt.Sprint(got)
+}
+
+// primaryKey returns the primary key of a model, "id" when unknown
+func primaryKey(modelID string) string {
+	if mod, ok := model.Models[modelID]; ok && mod != nil && mod.PrimaryKey != "" {
+		return mod.PrimaryKey
+	}
+	return "id"
+}
+
+// toRecord converts any map with string keys to a record
+func toRecord(v interface{}) map[string]interface{} {
+	if m, ok := v.(map[string]interface{}); ok {
+		return m
+	}
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil
+	}
+	m := make(map[string]interface{}, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		m[iter.Key().String()] = iter.Value().Interface()
+	}
+	return m
+}
+
+// toSlice converts any slice or array to []interface{}, nil for other values
+func toSlice(v interface{}) []interface{} {
+	if s, ok := v.([]interface{}); ok {
+		return s
+	}
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) {
+		return nil
+	}
+	s := make([]interface{}, rv.Len())
+	for i := range s {
+		s[i] = rv.Index(i).Interface()
+	}
+	return s
+}
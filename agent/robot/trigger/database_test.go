@@ -0,0 +1,232 @@
+package trigger_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaoapp/gou/process"
+	"github.com/yaoapp/yao/agent/robot/trigger"
+	"github.com/yaoapp/yao/agent/robot/types"
+	"github.com/yaoapp/yao/config"
+	"github.com/yaoapp/yao/test"
+)
+
+// ==================== ModelChanges Tests ====================
+
+func TestModelChanges(t *testing.T) {
+	t.Run("create", func(t *testing.T) {
+		changes := trigger.ModelChanges("Create", "order", []interface{}{map[string]interface{}{"status": "new"}}, 7)
+		require.Len(t, changes, 1)
+		assert.Equal(t, trigger.ChangeCreated, changes[0].Action)
+		assert.Equal(t, 7, changes[0].ID)
+		assert.Equal(t, "new", changes[0].Record["status"])
+	})
+
+	t.Run("save with id is an update", func(t *testing.T) {
+		changes := trigger.ModelChanges("save", "order", []interface{}{map[string]interface{}{"id": 3, "status": "paid"}}, 3)
+		require.Len(t, changes, 1)
+		assert.Equal(t, trigger.ChangeUpdated, changes[0].Action)
+		assert.Equal(t, 3, changes[0].ID)
+	})
+
+	t.Run("update and delete", func(t *testing.T) {
+		changes := trigger.ModelChanges("update", "order", []interface{}{5, map[string]interface{}{"status": "paid"}}, nil)
+		require.Len(t, changes, 1)
+		assert.Equal(t, trigger.ChangeUpdated, changes[0].Action)
+		assert.Equal(t, 5, changes[0].ID)
+
+		changes = trigger.ModelChanges("destroy", "order", []interface{}{5}, nil)
+		require.Len(t, changes, 1)
+		assert.Equal(t, trigger.ChangeDeleted, changes[0].Action)
+	})
+
+	t.Run("deletewhere keeps the query", func(t *testing.T) {
+		query := map[string]interface{}{"wheres": []interface{}{}}
+		changes := trigger.ModelChanges("deletewhere", "order", []interface{}{query}, 4)
+		require.Len(t, changes, 1)
+		assert.Nil(t, changes[0].ID)
+		assert.Equal(t, query, changes[0].Query)
+	})
+
+	t.Run("insert", func(t *testing.T) {
+		changes := trigger.ModelChanges("insert", "order", []interface{}{
+			[]string{"id", "status"},
+			[][]interface{}{{1, "new"}, {2, "paid"}},
+		}, nil)
+		require.Len(t, changes, 2)
+		assert.Equal(t, 2, changes[1].ID)
+		assert.Equal(t, "paid", changes[1].Record["status"])
+	})
+
+	t.Run("eachsave", func(t *testing.T) {
+		changes := trigger.ModelChanges("eachsave", "order", []interface{}{
+			[]interface{}{map[string]interface{}{"id": 1}, map[string]interface{}{"status": "new"}},
+		}, []interface{}{1, 9})
+		require.Len(t, changes, 2)
+		assert.Equal(t, trigger.ChangeUpdated, changes[0].Action)
+		assert.Equal(t, trigger.ChangeCreated, changes[1].Action)
+		assert.Equal(t, 9, changes[1].ID)
+	})
+
+	t.Run("read methods", func(t *testing.T) {
+		assert.Nil(t, trigger.ModelChanges("find", "order", []interface{}{1}, nil))
+	})
+}
+
+// ==================== MatchModelChange Tests ====================
+
+func TestMatchModelChange(t *testing.T) {
+	change := trigger.ModelChange{
+		Model:  "order",
+		Action: trigger.ChangeCreated,
+		Record: map[string]interface{}{"status": "new", "amount": 100},
+	}
+
+	t.Run("source", func(t *testing.T) {
+		assert.True(t, trigger.MatchModelChange(types.Event{Type: types.EventDatabase, Source: "Order"}, change))
+		assert.False(t, trigger.MatchModelChange(types.Event{Type: types.EventDatabase, Source: "user"}, change))
+		assert.False(t, trigger.MatchModelChange(types.Event{Type: types.EventWebhook, Source: "order"}, change))
+	})
+
+	t.Run("actions", func(t *testing.T) {
+		assert.True(t, trigger.MatchModelChange(types.Event{Type: types.EventDatabase, Source: "order", Actions: []string{"create"}}, change))
+		assert.False(t, trigger.MatchModelChange(types.Event{Type: types.EventDatabase, Source: "order", Actions: []string{"updated", "deleted"}}, change))
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		ev := types.Event{Type: types.EventDatabase, Source: "order", Filter: map[string]interface{}{"status": "new", "amount": "100"}}
+		assert.True(t, trigger.MatchModelChange(ev, change))
+
+		ev.Filter = map[string]interface{}{"status": []interface{}{"paid", "new"}}
+		assert.True(t, trigger.MatchModelChange(ev, change))
+
+		ev.Filter = map[string]interface{}{"status": "paid"}
+		assert.False(t, trigger.MatchModelChange(ev, change))
+
+		ev.Filter = map[string]interface{}{"missing": "x"}
+		assert.False(t, trigger.MatchModelChange(ev, change))
+	})
+}
+
+// ==================== Event Data Tests ====================
+
+func TestChangeEventData(t *testing.T) {
+	created := trigger.ModelChange{Model: "order", Action: trigger.ChangeCreated, ID: 1, Record: map[string]interface{}{"status": "new"}, Time: time.Now()}
+	updated := trigger.ModelChange{Model: "order", Action: trigger.ChangeUpdated, ID: 1, Time: time.Now()}
+
+	assert.Equal(t, "", trigger.ChangeEventType(nil))
+	assert.Equal(t, "order.created", trigger.ChangeEventType([]trigger.ModelChange{created, created}))
+	assert.Equal(t, "order.changed", trigger.ChangeEventType([]trigger.ModelChange{created, updated}))
+
+	assert.Nil(t, trigger.ChangeEventData(nil))
+
+	data := trigger.ChangeEventData([]trigger.ModelChange{created})
+	assert.Equal(t, "order", data["model"])
+	assert.Equal(t, "created", data["action"])
+	assert.Equal(t, 1, data["id"])
+	assert.Equal(t, created.Record, data["record"])
+
+	data = trigger.ChangeEventData([]trigger.ModelChange{created, updated})
+	assert.Equal(t, 2, data["count"])
+	assert.Len(t, data["changes"], 2)
+}
+
+// ==================== ChangeBatcher Tests ====================
+
+type flushed struct {
+	mu      sync.Mutex
+	batches [][]trigger.ModelChange
+}
+
+func (f *flushed) flush(_ string, _ types.Event, changes []trigger.ModelChange) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.batches = append(f.batches, changes)
+}
+
+func (f *flushed) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.batches)
+}
+
+func TestChangeBatcher(t *testing.T) {
+	change := trigger.ModelChange{Model: "order", Action: trigger.ChangeCreated}
+
+	t.Run("no window flushes at once", func(t *testing.T) {
+		f := &flushed{}
+		b := trigger.NewChangeBatcher(f.flush)
+		b.Add("robot_001", 0, types.Event{Type: types.EventDatabase, Source: "order"}, change)
+		assert.Equal(t, 1, f.count())
+		assert.Equal(t, 0, b.Pending())
+	})
+
+	t.Run("flush on max", func(t *testing.T) {
+		f := &flushed{}
+		b := trigger.NewChangeBatcher(f.flush)
+		ev := types.Event{Type: types.EventDatabase, Source: "order", Batch: &types.EventBatch{Window: "1h", Max: 3}}
+		b.Add("robot_001", 0, ev, change)
+		b.Add("robot_001", 0, ev, change)
+		assert.Equal(t, 0, f.count())
+		assert.Equal(t, 2, b.Pending())
+
+		b.Add("robot_001", 0, ev, change)
+		require.Equal(t, 1, f.count())
+		assert.Len(t, f.batches[0], 3)
+		assert.Equal(t, 0, b.Pending())
+	})
+
+	t.Run("flush on window", func(t *testing.T) {
+		f := &flushed{}
+		b := trigger.NewChangeBatcher(f.flush)
+		ev := types.Event{Type: types.EventDatabase, Source: "order", Batch: &types.EventBatch{Window: "50ms"}}
+		b.Add("robot_001", 0, ev, change)
+		b.Add("robot_001", 0, ev, change)
+		b.Add("robot_002", 0, ev, change)
+
+		assert.Eventually(t, func() bool { return f.count() == 2 }, time.Second, 10*time.Millisecond)
+		assert.Equal(t, 0, b.Pending())
+	})
+
+	t.Run("stop drops pending", func(t *testing.T) {
+		f := &flushed{}
+		b := trigger.NewChangeBatcher(f.flush)
+		ev := types.Event{Type: types.EventDatabase, Source: "order", Batch: &types.EventBatch{Window: "50ms"}}
+		b.Add("robot_001", 0, ev, change)
+		assert.Equal(t, 1, b.Stop())
+
+		b.Add("robot_001", 0, ev, change)
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, 0, f.count())
+	})
+}
+
+// ==================== OnModelChange Tests ====================
+
+func TestOnModelChange(t *testing.T) {
+	test.Prepare(t, config.Conf)
+	defer test.Clean()
+
+	changes := make(chan trigger.ModelChange, 1)
+	remove := trigger.OnModelChange(func(change trigger.ModelChange) { changes <- change })
+	defer remove()
+
+	// The models.* handlers are wrapped at init, before any process runs
+	err := process.New("models.__yao.job.category.UpdateWhere",
+		map[string]interface{}{"wheres": []map[string]interface{}{{"column": "name", "value": "__robot_trigger_test__"}}},
+		map[string]interface{}{"description": "changed"},
+	).Execute()
+	require.NoError(t, err)
+
+	select {
+	case change := <-changes:
+		assert.Equal(t, "__yao.job.category", change.Model)
+		assert.Equal(t, trigger.ChangeUpdated, change.Action)
+		assert.Equal(t, "changed", change.Record["description"])
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the model change to be published")
+	}
+}
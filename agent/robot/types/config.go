@@ -2,6 +2,7 @@ package types
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 )
 
@@ -17,6 +18,7 @@ type Config struct {
 	Resources     *Resources           `json:"resources,omitempty"`
 	Delivery      *DeliveryPreferences `json:"delivery,omitempty"` // delivery preferences (see robot.go)
 	Events        []Event              `json:"events,omitempty"`
+	Dedup         *DedupConfig         `json:"dedup,omitempty"`          // duplicate execution windows
 	Executor      *ExecutorConfig      `json:"executor,omitempty"`       // executor mode settings
 	DefaultLocale string               `json:"default_locale,omitempty"` // default language for clock/event triggers ("en", "zh")
 	Integrations  *Integrations        `json:"integrations,omitempty"`   // external channel integrations (telegram, etc.)
@@ -29,6 +31,7 @@ type Integrations struct {
 	DingTalk *DingTalkConfig `json:"dingtalk,omitempty"`
 	Discord  *DiscordConfig  `json:"discord,omitempty"`
 	Weixin   *WeixinConfig   `json:"weixin,omitempty"`
+	Slack    *SlackConfig    `json:"slack,omitempty"`
 }
 
 // TelegramConfig holds Telegram Bot integration settings.
@@ -62,6 +65,17 @@ type DiscordConfig struct {
 	AppID    string `json:"app_id,omitempty"`
 }
 
+// SlackConfig holds Slack App integration settings.
+// Events arrive over Socket Mode when AppToken is set, otherwise over the
+// Events API webhook, which is verified with SigningSecret.
+type SlackConfig struct {
+	Enabled       bool   `json:"enabled"`
+	BotToken      string `json:"bot_token"`                // xoxb-..., used for the Web API
+	AppToken      string `json:"app_token,omitempty"`      // xapp-..., enables Socket Mode
+	SigningSecret string `json:"signing_secret,omitempty"` // verifies Events API requests
+	AppID         string `json:"app_id,omitempty"`         // auto-generated, used for webhook URL routing
+}
+
 // ExecutorConfig - executor settings
 type ExecutorConfig struct {
 	Mode        ExecutorMode `json:"mode,omitempty"`         // standard | dryrun | sandbox
@@ -98,6 +112,16 @@ func (c *Config) Validate() error {
 			return err
 		}
 	}
+	if c.Dedup != nil {
+		if err := c.Dedup.Validate(); err != nil {
+			return err
+		}
+	}
+	for i := range c.Events {
+		if err := c.Events[i].Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -138,6 +162,78 @@ func (t *Triggers) IsEnabled(typ TriggerType) bool {
 	return false
 }
 
+// Default dedup windows
+const (
+	DefaultClockDedupWindow               = time.Minute
+	DefaultHumanDedupWindow time.Duration = 0 // interventions are explicit, dedup is opt-in
+	DefaultEventDedupWindow               = 5 * time.Minute
+)
+
+// DedupConfig - duplicate execution windows per trigger type
+// A trigger with the same input as an execution started within the window is skipped.
+// Windows are durations ("10m"), "0" turns dedup off for the trigger type.
+type DedupConfig struct {
+	Clock string `json:"clock,omitempty"` // default: 1m
+	Human string `json:"human,omitempty"` // default: off
+	Event string `json:"event,omitempty"` // default: 5m
+	Merge bool   `json:"merge,omitempty"` // fold new payloads of the same event type into the running execution
+}
+
+// Window returns the dedup window of a trigger type, 0 when dedup is off
+func (d *DedupConfig) Window(trigger TriggerType) time.Duration {
+	var value string
+	var fallback time.Duration
+	switch trigger {
+	case TriggerClock:
+		fallback = DefaultClockDedupWindow
+		if d != nil {
+			value = d.Clock
+		}
+	case TriggerHuman:
+		fallback = DefaultHumanDedupWindow
+		if d != nil {
+			value = d.Human
+		}
+	case TriggerEvent:
+		fallback = DefaultEventDedupWindow
+		if d != nil {
+			value = d.Event
+		}
+	default:
+		return 0
+	}
+
+	if value == "" {
+		return fallback
+	}
+	if value == "0" {
+		return 0
+	}
+	window, err := time.ParseDuration(value)
+	if err != nil {
+		return fallback
+	}
+	return max(window, 0)
+}
+
+// Validate validates the dedup windows
+func (d *DedupConfig) Validate() error {
+	for _, value := range []string{d.Clock, d.Human, d.Event} {
+		if value == "" || value == "0" {
+			continue
+		}
+		if window, err := time.ParseDuration(value); err != nil || window < 0 {
+			return ErrDedupWindowInvalid
+		}
+	}
+	return nil
+}
+
+// MergeEnabled returns whether event payloads are merged into a running execution
+func (d *DedupConfig) MergeEnabled() bool {
+	return d != nil && d.Merge
+}
+
 // Clock - when to wake up
 type Clock struct {
 	Mode    ClockMode `json:"mode"`              // times | interval | daemon
@@ -246,9 +342,23 @@ type DB struct {
 // Learn - learning config for robot's private KB
 // Private KB is auto-created: robot_{team_id}_{member_id}_kb
 type Learn struct {
-	On    bool     `json:"on"`
-	Types []string `json:"types,omitempty"` // execution, feedback, insight
-	Keep  int      `json:"keep,omitempty"`  // days, 0 = forever
+	On         bool     `json:"on"`
+	Types      []string `json:"types,omitempty"`      // execution, feedback, insight
+	Keep       int      `json:"keep,omitempty"`       // days, 0 = forever
+	Collection string   `json:"collection,omitempty"` // KB collection ID, empty = private KB
+}
+
+// Accepts returns whether a learning type is kept (all types when none is configured)
+func (l *Learn) Accepts(t LearningType) bool {
+	if l == nil || len(l.Types) == 0 {
+		return true
+	}
+	for _, typ := range l.Types {
+		if LearningType(typ) == t {
+			return true
+		}
+	}
+	return false
 }
 
 // Resources - available agents and tools
@@ -318,10 +428,67 @@ type WeixinConfig struct {
 }
 
 // Event - event trigger config
+// A database event subscribes to the changes of a Yao model (Source is the model ID),
+// optionally restricted to some actions and to the records matching Filter
 type Event struct {
-	Type   EventSource            `json:"type"`   // webhook | database
-	Source string                 `json:"source"` // webhook path or table name
-	Filter map[string]interface{} `json:"filter,omitempty"`
+	Type    EventSource            `json:"type"`              // webhook | database
+	Source  string                 `json:"source"`            // webhook path or model ID
+	Actions []string               `json:"actions,omitempty"` // database: created | updated | deleted, empty = all
+	Filter  map[string]interface{} `json:"filter,omitempty"`  // database: field values the record must have (a list matches any of its values)
+	Batch   *EventBatch            `json:"batch,omitempty"`   // database: group bursts of changes into one execution
+}
+
+// EventBatch - batching of database change events
+// Changes are collected for Window after the first one, or until Max changes
+type EventBatch struct {
+	Window string `json:"window,omitempty"` // e.g. "30s", empty or "0" = no batching
+	Max    int    `json:"max,omitempty"`    // flush when this many changes are collected, 0 = DefaultEventBatchMax
+}
+
+// DefaultEventBatchMax is the default maximum number of changes in a batch
+const DefaultEventBatchMax = 100
+
+// GetWindow returns the batch window, 0 when batching is off
+func (b *EventBatch) GetWindow() time.Duration {
+	if b == nil || b.Window == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(b.Window)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
+// GetMax returns the maximum number of changes in a batch
+func (b *EventBatch) GetMax() int {
+	if b == nil || b.Max <= 0 {
+		return DefaultEventBatchMax
+	}
+	return b.Max
+}
+
+// Validate validates the event config
+func (e *Event) Validate() error {
+	if e.Type != EventDatabase {
+		return nil
+	}
+	if e.Source == "" {
+		return ErrEventSourceRequired
+	}
+	for _, action := range e.Actions {
+		switch action {
+		case "created", "updated", "deleted", "create", "update", "delete":
+		default:
+			return fmt.Errorf("%w: %s", ErrEventActionInvalid, action)
+		}
+	}
+	if e.Batch != nil && e.Batch.Window != "" {
+		if _, err := time.ParseDuration(e.Batch.Window); err != nil {
+			return fmt.Errorf("%w: %s", ErrEventBatchInvalid, e.Batch.Window)
+		}
+	}
+	return nil
 }
 
 // ParseConfig parses robot_config from various formats (string, []byte, map)
@@ -49,6 +49,46 @@ func TestConfigValidate(t *testing.T) {
 	})
 }
 
+func TestEventValidate(t *testing.T) {
+	t.Run("non database events are not checked", func(t *testing.T) {
+		ev := &types.Event{Type: types.EventWebhook}
+		assert.NoError(t, ev.Validate())
+	})
+
+	t.Run("valid database event", func(t *testing.T) {
+		ev := &types.Event{
+			Type:    types.EventDatabase,
+			Source:  "order",
+			Actions: []string{"create", "updated"},
+			Batch:   &types.EventBatch{Window: "30s", Max: 10},
+		}
+		assert.NoError(t, ev.Validate())
+		assert.Equal(t, 30*time.Second, ev.Batch.GetWindow())
+		assert.Equal(t, 10, ev.Batch.GetMax())
+	})
+
+	t.Run("missing source", func(t *testing.T) {
+		ev := &types.Event{Type: types.EventDatabase}
+		assert.Equal(t, types.ErrEventSourceRequired, ev.Validate())
+	})
+
+	t.Run("invalid action", func(t *testing.T) {
+		ev := &types.Event{Type: types.EventDatabase, Source: "order", Actions: []string{"select"}}
+		assert.ErrorIs(t, ev.Validate(), types.ErrEventActionInvalid)
+	})
+
+	t.Run("invalid batch window", func(t *testing.T) {
+		ev := &types.Event{Type: types.EventDatabase, Source: "order", Batch: &types.EventBatch{Window: "soon"}}
+		assert.ErrorIs(t, ev.Validate(), types.ErrEventBatchInvalid)
+	})
+
+	t.Run("batch defaults", func(t *testing.T) {
+		var batch *types.EventBatch
+		assert.Equal(t, time.Duration(0), batch.GetWindow())
+		assert.Equal(t, types.DefaultEventBatchMax, batch.GetMax())
+	})
+}
+
 func TestClockValidate(t *testing.T) {
 	t.Run("valid times mode", func(t *testing.T) {
 		clock := &types.Clock{
@@ -215,6 +255,52 @@ func TestQuotaDefaults(t *testing.T) {
 	})
 }
 
+func TestLearnAccepts(t *testing.T) {
+	t.Run("nil learn accepts all", func(t *testing.T) {
+		var learn *types.Learn
+		assert.True(t, learn.Accepts(types.LearnExecution))
+		assert.True(t, learn.Accepts(types.LearnInsight))
+	})
+
+	t.Run("no types accepts all", func(t *testing.T) {
+		learn := &types.Learn{On: true}
+		assert.True(t, learn.Accepts(types.LearnFeedback))
+	})
+
+	t.Run("configured types", func(t *testing.T) {
+		learn := &types.Learn{On: true, Types: []string{"feedback", "insight"}}
+		assert.False(t, learn.Accepts(types.LearnExecution))
+		assert.True(t, learn.Accepts(types.LearnFeedback))
+		assert.True(t, learn.Accepts(types.LearnInsight))
+	})
+}
+
+func TestDedupConfigWindow(t *testing.T) {
+	t.Run("nil config uses defaults", func(t *testing.T) {
+		var dedup *types.DedupConfig
+		assert.Equal(t, types.DefaultClockDedupWindow, dedup.Window(types.TriggerClock))
+		assert.Equal(t, time.Duration(0), dedup.Window(types.TriggerHuman))
+		assert.Equal(t, types.DefaultEventDedupWindow, dedup.Window(types.TriggerEvent))
+		assert.False(t, dedup.MergeEnabled())
+	})
+
+	t.Run("configured windows", func(t *testing.T) {
+		dedup := &types.DedupConfig{Clock: "0", Human: "30s", Event: "10m", Merge: true}
+		assert.Equal(t, time.Duration(0), dedup.Window(types.TriggerClock))
+		assert.Equal(t, 30*time.Second, dedup.Window(types.TriggerHuman))
+		assert.Equal(t, 10*time.Minute, dedup.Window(types.TriggerEvent))
+		assert.True(t, dedup.MergeEnabled())
+	})
+
+	t.Run("invalid window", func(t *testing.T) {
+		dedup := &types.DedupConfig{Event: "soon"}
+		assert.Equal(t, types.DefaultEventDedupWindow, dedup.Window(types.TriggerEvent))
+
+		config := &types.Config{Identity: &types.Identity{Role: "Sales Manager"}, Dedup: dedup}
+		assert.Equal(t, types.ErrDedupWindowInvalid, config.Validate())
+	})
+}
+
 func TestResourcesGetPhaseAgent(t *testing.T) {
 	t.Run("nil resources without global resolver - returns empty", func(t *testing.T) {
 		orig := types.GlobalPhaseAgentResolver
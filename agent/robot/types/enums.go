@@ -124,7 +124,7 @@ const (
 	DeliveryEmail   DeliveryType = "email"   // Send via yao/messenger
 	DeliveryWebhook DeliveryType = "webhook" // POST to external URL
 	DeliveryProcess DeliveryType = "process" // Call Yao Process
-	DeliveryNotify  DeliveryType = "notify"  // In-app notification (future, auto by subscriptions)
+	DeliveryNotify  DeliveryType = "notify"  // In-app notification (robot manager, triggering user and notify targets)
 )
 
 // DedupResult - deduplication result
@@ -210,8 +210,8 @@ const (
 	ExecutorStandard ExecutorMode = "standard"
 	// ExecutorDryRun simulates execution without LLM calls (testing/demo)
 	ExecutorDryRun ExecutorMode = "dryrun"
-	// ExecutorSandbox runs in container-isolated environment (NOT IMPLEMENTED)
-	// Requires Docker/gVisor/Firecracker infrastructure
+	// ExecutorSandbox runs the P3 tasks in container-isolated sandbox boxes
+	// Requires a sandbox node with a container runtime
 	ExecutorSandbox ExecutorMode = "sandbox"
 )
 
@@ -14,6 +14,18 @@ var ErrClockIntervalEmpty = errors.New("clock.every is required for interval mod
 // ErrClockModeInvalid indicates clock.mode must be times, interval, or daemon
 var ErrClockModeInvalid = errors.New("clock.mode must be times, interval, or daemon")
 
+// ErrDedupWindowInvalid indicates a dedup window is not a valid duration
+var ErrDedupWindowInvalid = errors.New("dedup window must be a duration such as 30s or 10m")
+
+// ErrEventSourceRequired indicates a database event has no model
+var ErrEventSourceRequired = errors.New("database event source (model ID) is required")
+
+// ErrEventActionInvalid indicates a database event action is not created, updated or deleted
+var ErrEventActionInvalid = errors.New("database event action must be created, updated or deleted")
+
+// ErrEventBatchInvalid indicates a database event batch window is not a valid duration
+var ErrEventBatchInvalid = errors.New("database event batch window must be a duration such as 30s")
+
 // ErrRobotNotFound indicates robot not found
 var ErrRobotNotFound = errors.New("robot not found")
 
@@ -29,6 +41,9 @@ var ErrQuotaExceeded = errors.New("robot quota exceeded")
 // ErrTriggerDisabled indicates trigger type is disabled for this robot
 var ErrTriggerDisabled = errors.New("trigger type is disabled for this robot")
 
+// ErrDuplicateExecution indicates the trigger repeats an execution started within the dedup window
+var ErrDuplicateExecution = errors.New("duplicate execution skipped")
+
 // ErrExecutionCancelled indicates execution was cancelled
 var ErrExecutionCancelled = errors.New("execution was cancelled")
 
@@ -70,8 +70,11 @@ type Cache interface {
 
 // Dedup - deduplication check
 type Dedup interface {
-	Check(ctx *Context, memberID string, trigger TriggerType) (DedupResult, error)
-	Mark(memberID string, trigger TriggerType, window time.Duration)
+	// Check checks a trigger against the executions marked within the robot's dedup window.
+	// Returns the ID of the matched execution for DedupSkip and DedupMerge.
+	Check(ctx *Context, robot *Robot, trigger TriggerType, data interface{}) (DedupResult, string, error)
+	// Mark records a submitted execution so that duplicates within the window are detected
+	Mark(ctx *Context, robot *Robot, trigger TriggerType, data interface{}, execID string) error
 }
 
 // Store - data storage operations (KB, DB)
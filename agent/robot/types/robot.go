@@ -232,6 +232,34 @@ type Execution struct {
 	robot  *Robot             `json:"-"`
 }
 
+// mergeMu guards TriggerInput.Merged of running executions
+var mergeMu sync.Mutex
+
+// MergeEvent folds an event into the execution input and returns a copy of the
+// input with all merged events, for persistence
+func (e *Execution) MergeEvent(event MergedEvent) *TriggerInput {
+	mergeMu.Lock()
+	defer mergeMu.Unlock()
+	if e.Input == nil {
+		e.Input = &TriggerInput{}
+	}
+	e.Input.Merged = append(e.Input.Merged, event)
+
+	input := *e.Input
+	input.Merged = append([]MergedEvent(nil), e.Input.Merged...)
+	return &input
+}
+
+// MergedEvents returns the events merged into the execution so far
+func (e *Execution) MergedEvents() []MergedEvent {
+	mergeMu.Lock()
+	defer mergeMu.Unlock()
+	if e.Input == nil || len(e.Input.Merged) == 0 {
+		return nil
+	}
+	return append([]MergedEvent(nil), e.Input.Merged...)
+}
+
 // ResumeContext holds the state needed to resume a suspended execution
 type ResumeContext struct {
 	TaskIndex       int          `json:"task_index"`       // Index of the task to resume from
@@ -284,11 +312,19 @@ type TriggerInput struct {
 	Source    EventSource            `json:"source,omitempty"`     // webhook | database
 	EventType string                 `json:"event_type,omitempty"` // lead.created, etc.
 	Data      map[string]interface{} `json:"data,omitempty"`       // event payload
+	Merged    []MergedEvent          `json:"merged,omitempty"`     // events folded in by dedup (see Execution.MergeEvent)
 
 	// For clock trigger
 	Clock *ClockContext `json:"clock,omitempty"` // time context when triggered
 }
 
+// MergedEvent - an event of the same type folded into a running execution by dedup
+type MergedEvent struct {
+	EventType  string                 `json:"event_type,omitempty"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+	ReceivedAt time.Time              `json:"received_at"`
+}
+
 // CurrentState - current executing goal and task
 type CurrentState struct {
 	Task      *Task  `json:"task,omitempty"`     // current task being executed
@@ -429,6 +465,7 @@ type DeliveryPreferences struct {
 	Email   *EmailPreference   `json:"email,omitempty"`   // Email delivery settings
 	Webhook *WebhookPreference `json:"webhook,omitempty"` // Webhook delivery settings
 	Process *ProcessPreference `json:"process,omitempty"` // Process delivery settings
+	Notify  *NotifyPreference  `json:"notify,omitempty"`  // In-app notification settings
 }
 
 // EmailPreference - Email delivery configuration
@@ -470,6 +507,19 @@ type ProcessTarget struct {
 	Args    []any  `json:"args,omitempty"` // Process arguments
 }
 
+// NotifyPreference - In-app notification delivery configuration
+// The robot manager and the user who triggered the execution are notified by default
+type NotifyPreference struct {
+	Enabled bool           `json:"enabled"`           // Whether in-app notification is enabled
+	Targets []NotifyTarget `json:"targets,omitempty"` // Additional users to notify
+}
+
+// NotifyTarget - Single notification recipient
+type NotifyTarget struct {
+	UserID string `json:"user_id"`        // Recipient user ID
+	Link   string `json:"link,omitempty"` // UI link opened from the notification
+}
+
 // ChannelResult - Result of delivery to a single channel target
 type ChannelResult struct {
 	Type       DeliveryType `json:"type"`                 // email | webhook | process
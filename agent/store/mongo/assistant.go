@@ -0,0 +1,515 @@
+package mongo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/yao/agent/i18n"
+	"github.com/yaoapp/yao/agent/store/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// numberJSON decodes generic maps with json.Number so integers keep their type and precision
+var numberJSON = jsoniter.Config{EscapeHTML: true, UseNumber: true}.Froze()
+
+// localesText keeps the locales as a string so keyword search can match translations (like the xun store)
+const localesText = "__yao_locales_text"
+
+// =============================================================================
+// Assistant Management
+// =============================================================================
+
+// SaveAssistant creates or updates an assistant
+func (m *Mongo) SaveAssistant(assistant *types.AssistantModel) (string, error) {
+	if assistant == nil {
+		return "", fmt.Errorf("assistant cannot be nil")
+	}
+
+	// Validate required fields
+	if assistant.Name == "" {
+		return "", fmt.Errorf("field name is required")
+	}
+	if assistant.Type == "" {
+		return "", fmt.Errorf("field type is required")
+	}
+	if assistant.Connector == "" {
+		return "", fmt.Errorf("field connector is required")
+	}
+
+	// Generate assistant_id if not provided
+	if assistant.ID == "" {
+		var err error
+		assistant.ID, err = m.GenerateAssistantID()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	data, err := encodeAssistant(assistant)
+	if err != nil {
+		return "", err
+	}
+
+	// Share field defaults to private
+	if assistant.Share == "" {
+		data["share"] = "private"
+	}
+
+	// Set timestamps, created_at is kept on update
+	now := time.Now().UnixNano()
+	existing, exists, err := m.loadAssistantData(assistant.ID)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		data["created_at"] = existing["created_at"]
+		if assistant.UpdatedAt == 0 {
+			data["updated_at"] = now
+		}
+	} else {
+		if assistant.CreatedAt == 0 {
+			data["created_at"] = now
+		}
+		data["updated_at"] = 0
+	}
+
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	opts := options.Replace().SetUpsert(true)
+	if _, err := m.assistants().ReplaceOne(ctx, bson.M{"assistant_id": assistant.ID}, data, opts); err != nil {
+		return "", err
+	}
+	return assistant.ID, nil
+}
+
+// UpdateAssistant updates specific fields of an assistant
+func (m *Mongo) UpdateAssistant(assistantID string, updates map[string]interface{}) error {
+	if assistantID == "" {
+		return fmt.Errorf("assistant_id is required")
+	}
+	if len(updates) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	data, exists, err := m.loadAssistantData(assistantID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("assistant %s not found", assistantID)
+	}
+
+	for key, value := range updates {
+		// Skip system fields that shouldn't be updated directly
+		if key == "assistant_id" || key == "created_at" || key == "_id" || key == localesText {
+			continue
+		}
+
+		// Empty strings clear nullable fields
+		if s, ok := value.(string); ok && s == "" {
+			delete(data, key)
+			continue
+		}
+		data[key] = value
+	}
+
+	// Always update updated_at timestamp
+	data["updated_at"] = time.Now().UnixNano()
+
+	// Round trip through the model to reject updates with invalid types
+	model, err := decodeAssistant(data)
+	if err != nil {
+		return fmt.Errorf("invalid assistant updates: %w", err)
+	}
+	delete(data, localesText)
+	if len(model.Locales) > 0 {
+		data[localesText], _ = jsoniter.MarshalToString(model.Locales)
+	}
+
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	_, err = m.assistants().ReplaceOne(ctx, bson.M{"assistant_id": assistantID}, data)
+	return err
+}
+
+// DeleteAssistant deletes an assistant by assistant_id
+func (m *Mongo) DeleteAssistant(assistantID string) error {
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	res, err := m.assistants().DeleteOne(ctx, bson.M{"assistant_id": assistantID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("assistant %s not found", assistantID)
+	}
+	return nil
+}
+
+// GetAssistants retrieves assistants with pagination and filtering
+func (m *Mongo) GetAssistants(filter types.AssistantFilter, locale ...string) (*types.AssistantList, error) {
+	// Set defaults for pagination
+	if filter.PageSize <= 0 {
+		filter.PageSize = 20
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+
+	query, err := assistantQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	// Get total count
+	count, err := m.assistants().CountDocuments(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Calculate pagination
+	total := int(count)
+	totalPages := int(math.Ceil(float64(total) / float64(filter.PageSize)))
+	nextPage := filter.Page + 1
+	if nextPage > totalPages {
+		nextPage = 0
+	}
+	prevPage := filter.Page - 1
+	if prevPage < 1 {
+		prevPage = 0
+	}
+
+	// Same order as the database store: sort asc, updated_at desc
+	opts := pagination(filter.Page, filter.PageSize).
+		SetSort(bson.D{{Key: "sort", Value: 1}, {Key: "updated_at", Value: -1}})
+
+	// Apply select fields (only if fields are explicitly specified)
+	if len(filter.Select) > 0 {
+		opts.SetProjection(projection(filter.Select))
+	}
+
+	cursor, err := m.assistants().Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	assistants := []*types.AssistantModel{}
+	for cursor.Next(ctx) {
+		data := bson.M{}
+		if err := cursor.Decode(&data); err != nil {
+			return nil, err
+		}
+
+		model, err := decodeAssistant(data)
+		if err != nil {
+			log.Error("Failed to convert assistant %v: %s", data["assistant_id"], err.Error())
+			continue
+		}
+
+		// Apply i18n translations if locale is provided
+		if len(locale) > 0 && locale[0] != "" {
+			types.TranslateAssistant(model, model.ID, locale[0])
+		}
+
+		assistants = append(assistants, model)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return &types.AssistantList{
+		Data:      assistants,
+		Page:      filter.Page,
+		PageSize:  filter.PageSize,
+		PageCount: totalPages,
+		Next:      nextPage,
+		Prev:      prevPage,
+		Total:     total,
+	}, nil
+}
+
+// GetAssistant retrieves a single assistant by ID
+func (m *Mongo) GetAssistant(assistantID string, fields []string, locale ...string) (*types.AssistantModel, error) {
+	// If no fields specified, use default fields
+	fieldsToSelect := fields
+	if len(fieldsToSelect) == 0 {
+		fieldsToSelect = types.AssistantDefaultFields
+	}
+
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	data := bson.M{}
+	opts := options.FindOne().SetProjection(projection(fieldsToSelect))
+	err := m.assistants().FindOne(ctx, bson.M{"assistant_id": assistantID}, opts).Decode(&data)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("assistant %s not found", assistantID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	model, err := decodeAssistant(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply i18n translation if locale is provided
+	if len(locale) > 0 && locale[0] != "" {
+		types.TranslateAssistant(model, assistantID, locale[0])
+	}
+
+	return model, nil
+}
+
+// DeleteAssistants deletes assistants based on filter conditions
+func (m *Mongo) DeleteAssistants(filter types.AssistantFilter) (int64, error) {
+	query, err := assistantQuery(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	res, err := m.assistants().DeleteMany(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+// GetAssistantTags retrieves all unique tags from assistants with filtering
+func (m *Mongo) GetAssistantTags(filter types.AssistantFilter, locale ...string) ([]types.Tag, error) {
+	// Tags are collected over every matched assistant, not one page
+	filter.Tags = nil
+
+	query, err := assistantQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	values, err := m.assistants().Distinct(ctx, "tags", query)
+	if err != nil {
+		return nil, err
+	}
+
+	lang := "en"
+	if len(locale) > 0 {
+		lang = locale[0]
+	}
+
+	tags := make([]types.Tag, 0, len(values))
+	for _, value := range values {
+		tag, ok := value.(string)
+		if !ok || tag == "" {
+			continue
+		}
+		tags = append(tags, types.Tag{
+			Value: tag,
+			Label: i18n.TranslateGlobal(lang, tag).(string),
+		})
+	}
+	return tags, nil
+}
+
+// GenerateAssistantID generates a random-looking 6-digit ID
+func (m *Mongo) GenerateAssistantID() (string, error) {
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	maxAttempts := 10 // Maximum number of attempts to generate a unique ID
+	for i := 0; i < maxAttempts; i++ {
+		timestamp := time.Now().UnixNano()
+		random := (timestamp ^ (timestamp >> 12)) % 1000000
+		hash := fmt.Sprintf("%06d", random)
+
+		count, err := m.assistants().CountDocuments(ctx, bson.M{"assistant_id": hash})
+		if err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return hash, nil
+		}
+
+		// If ID exists, wait a bit and try again
+		time.Sleep(time.Millisecond)
+	}
+
+	return "", fmt.Errorf("failed to generate unique ID after %d attempts", maxAttempts)
+}
+
+// =============================================================================
+// Helper Functions
+// =============================================================================
+
+// loadAssistantData loads the stored fields of an assistant
+func (m *Mongo) loadAssistantData(assistantID string) (map[string]interface{}, bool, error) {
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	data := bson.M{}
+	opts := options.FindOne().SetProjection(bson.M{"_id": 0})
+	err := m.assistants().FindOne(ctx, bson.M{"assistant_id": assistantID}, opts).Decode(&data)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return normalize(data), true, nil
+}
+
+// assistantQuery builds the query document of an assistant filter, permissions come from the Access rules
+func assistantQuery(filter types.AssistantFilter) (bson.M, error) {
+	query := bson.M{}
+	if err := accessQuery(query, filter.QueryFilter != nil, filter.Access); err != nil {
+		return nil, err
+	}
+
+	// Any of the tags matches
+	if len(filter.Tags) > 0 {
+		query["tags"] = bson.M{"$in": filter.Tags}
+	}
+
+	if filter.Keywords != "" {
+		pattern := bson.M{"$regex": regexp.QuoteMeta(filter.Keywords), "$options": "i"}
+		query["$or"] = bson.A{
+			bson.M{"name": pattern},
+			bson.M{"description": pattern},
+			bson.M{"capabilities": pattern},
+			bson.M{localesText: pattern},
+		}
+	}
+
+	if cond := inCondition(filter.Type, filter.Types); cond != nil {
+		query["type"] = cond
+	}
+	if filter.Connector != "" {
+		query["connector"] = filter.Connector
+	}
+	if cond := inCondition(filter.AssistantID, filter.AssistantIDs); cond != nil {
+		query["assistant_id"] = cond
+	}
+
+	// Boolean fields are omitted from the document when false
+	if filter.Mentionable != nil {
+		query["mentionable"] = boolCondition(*filter.Mentionable)
+	}
+	if filter.Automated != nil {
+		query["automated"] = boolCondition(*filter.Automated)
+	}
+	if filter.BuiltIn != nil {
+		query["built_in"] = boolCondition(*filter.BuiltIn)
+	}
+	if filter.Sandbox != nil {
+		if *filter.Sandbox {
+			query["sandbox"] = bson.M{"$ne": nil}
+		} else {
+			query["sandbox"] = nil
+		}
+	}
+
+	return query, nil
+}
+
+// inCondition matches a field equal to value and/or in values
+func inCondition(value string, values []string) interface{} {
+	cond := bson.M{}
+	if value != "" {
+		cond["$eq"] = value
+	}
+	if len(values) > 0 {
+		cond["$in"] = values
+	}
+	if len(cond) == 0 {
+		return nil
+	}
+	return cond
+}
+
+// boolCondition matches a boolean field that may be missing when false
+func boolCondition(value bool) interface{} {
+	if value {
+		return true
+	}
+	return bson.M{"$ne": true}
+}
+
+// projection returns the find projection of the whitelisted fields
+func projection(fields []string) bson.M {
+	proj := bson.M{"_id": 0}
+	for _, field := range types.ValidateAssistantFields(fields) {
+		if field == "id" {
+			continue
+		}
+		proj[field] = 1
+	}
+	return proj
+}
+
+// encodeAssistant converts an assistant into its stored fields
+func encodeAssistant(assistant *types.AssistantModel) (map[string]interface{}, error) {
+	data := map[string]interface{}{}
+	raw, err := jsoniter.Marshal(assistant)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal assistant: %w", err)
+	}
+	if err := numberJSON.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal assistant: %w", err)
+	}
+
+	permissions := map[string]string{
+		"__yao_created_by": assistant.YaoCreatedBy,
+		"__yao_updated_by": assistant.YaoUpdatedBy,
+		"__yao_team_id":    assistant.YaoTeamID,
+		"__yao_tenant_id":  assistant.YaoTenantID,
+	}
+	for field, value := range permissions {
+		if value != "" {
+			data[field] = value
+		}
+	}
+
+	if len(assistant.Locales) > 0 {
+		data[localesText], _ = jsoniter.MarshalToString(assistant.Locales)
+	}
+	return data, nil
+}
+
+// decodeAssistant converts stored fields into an assistant
+func decodeAssistant(data map[string]interface{}) (*types.AssistantModel, error) {
+	data = normalize(data)
+	raw, err := jsoniter.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	model := &types.AssistantModel{}
+	if err := jsoniter.Unmarshal(raw, model); err != nil {
+		return nil, err
+	}
+
+	model.YaoCreatedBy, _ = data["__yao_created_by"].(string)
+	model.YaoUpdatedBy, _ = data["__yao_updated_by"].(string)
+	model.YaoTeamID, _ = data["__yao_team_id"].(string)
+	model.YaoTenantID, _ = data["__yao_tenant_id"].(string)
+	return model, nil
+}
@@ -0,0 +1,367 @@
+package mongo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yaoapp/yao/agent/store/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// chatDoc is the document form of a chat
+type chatDoc struct {
+	ChatID        string                 `bson:"chat_id"`
+	Title         string                 `bson:"title,omitempty"`
+	AssistantID   string                 `bson:"assistant_id"`
+	LastConnector string                 `bson:"last_connector,omitempty"`
+	LastMode      string                 `bson:"last_mode,omitempty"`
+	Status        string                 `bson:"status"`
+	Public        bool                   `bson:"public"`
+	Share         string                 `bson:"share"`
+	Sort          int                    `bson:"sort"`
+	LastMessageAt *time.Time             `bson:"last_message_at"`
+	Metadata      map[string]interface{} `bson:"metadata,omitempty"`
+	CreatedAt     time.Time              `bson:"created_at"`
+	UpdatedAt     time.Time              `bson:"updated_at"`
+	CreatedBy     string                 `bson:"__yao_created_by,omitempty"`
+	UpdatedBy     string                 `bson:"__yao_updated_by,omitempty"`
+	TeamID        string                 `bson:"__yao_team_id,omitempty"`
+	TenantID      string                 `bson:"__yao_tenant_id,omitempty"`
+}
+
+// =============================================================================
+// Chat Management
+// =============================================================================
+
+// CreateChat creates a new chat session
+func (m *Mongo) CreateChat(chat *types.Chat) error {
+	if chat == nil {
+		return fmt.Errorf("chat cannot be nil")
+	}
+
+	// Validate required fields
+	if chat.AssistantID == "" {
+		return fmt.Errorf("assistant_id is required")
+	}
+
+	// Generate chat_id if not provided
+	if chat.ChatID == "" {
+		chat.ChatID = uuid.New().String()
+	}
+
+	// Set defaults
+	if chat.Status == "" {
+		chat.Status = "active"
+	}
+	if chat.Share == "" {
+		chat.Share = "private"
+	}
+
+	now := time.Now()
+	chat.CreatedAt = now
+	chat.UpdatedAt = now
+
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	_, err := m.chats().InsertOne(ctx, toChatDoc(chat))
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("chat %s already exists", chat.ChatID)
+	}
+	return err
+}
+
+// GetChat retrieves a single chat by ID
+func (m *Mongo) GetChat(chatID string) (*types.Chat, error) {
+	if chatID == "" {
+		return nil, fmt.Errorf("chat_id is required")
+	}
+
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	var doc chatDoc
+	err := m.chats().FindOne(ctx, bson.M{"chat_id": chatID}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("chat %s not found", chatID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.toChat(), nil
+}
+
+// UpdateChat updates chat fields
+func (m *Mongo) UpdateChat(chatID string, updates map[string]interface{}) error {
+	if chatID == "" {
+		return fmt.Errorf("chat_id is required")
+	}
+	if len(updates) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	set := bson.M{}
+	for key, value := range updates {
+		// Skip system fields
+		if key == "chat_id" || key == "created_at" || key == "_id" {
+			continue
+		}
+		set[key] = value
+	}
+
+	// Always update updated_at
+	set["updated_at"] = time.Now()
+
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	res, err := m.chats().UpdateOne(ctx, bson.M{"chat_id": chatID}, bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("chat %s not found", chatID)
+	}
+	return nil
+}
+
+// DeleteChat deletes a chat and its associated messages
+func (m *Mongo) DeleteChat(chatID string) error {
+	if chatID == "" {
+		return fmt.Errorf("chat_id is required")
+	}
+
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	res, err := m.chats().DeleteOne(ctx, bson.M{"chat_id": chatID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("chat %s not found", chatID)
+	}
+
+	_, err = m.messages().DeleteMany(ctx, bson.M{"chat_id": chatID})
+	return err
+}
+
+// ListChats retrieves a paginated list of chats with optional grouping
+func (m *Mongo) ListChats(filter types.ChatFilter) (*types.ChatList, error) {
+	// Set defaults
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = 20
+	}
+	if filter.OrderBy == "" {
+		filter.OrderBy = "last_message_at"
+	}
+	if filter.Order == "" {
+		filter.Order = "desc"
+	}
+	if filter.TimeField == "" {
+		filter.TimeField = "last_message_at"
+	}
+
+	query, err := chatQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	// Get total count
+	total, err := m.chats().CountDocuments(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Calculate pagination
+	pageCount := int(math.Ceil(float64(total) / float64(filter.PageSize)))
+	if pageCount < 1 {
+		pageCount = 1
+	}
+
+	direction := -1
+	if strings.ToLower(filter.Order) == "asc" {
+		direction = 1
+	}
+	opts := pagination(filter.Page, filter.PageSize).
+		SetSort(bson.D{{Key: filter.OrderBy, Value: direction}, {Key: "_id", Value: direction}})
+
+	cursor, err := m.chats().Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	docs := []chatDoc{}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	chats := make([]*types.Chat, 0, len(docs))
+	for i := range docs {
+		chats = append(chats, docs[i].toChat())
+	}
+
+	result := &types.ChatList{
+		Data:      chats,
+		Page:      filter.Page,
+		PageSize:  filter.PageSize,
+		PageCount: pageCount,
+		Total:     int(total),
+	}
+
+	// Apply time-based grouping if requested
+	if filter.GroupBy == "time" {
+		result.Groups = types.GroupChatsByTime(chats)
+	}
+
+	return result, nil
+}
+
+// =============================================================================
+// Helper Functions
+// =============================================================================
+
+// chatQuery builds the query document of a chat filter, permissions come from the Access rules
+func chatQuery(filter types.ChatFilter) (bson.M, error) {
+	query := bson.M{}
+	if err := accessQuery(query, filter.QueryFilter != nil, filter.Access); err != nil {
+		return nil, err
+	}
+
+	// Apply permission filters (UserID and TeamID)
+	if filter.UserID != "" {
+		query["__yao_created_by"] = filter.UserID
+	}
+	if filter.TeamID != "" {
+		query["__yao_team_id"] = filter.TeamID
+	}
+
+	// Apply business filters
+	if filter.AssistantID != "" {
+		query["assistant_id"] = filter.AssistantID
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.Keywords != "" {
+		query["title"] = bson.M{"$regex": regexp.QuoteMeta(filter.Keywords), "$options": "i"}
+	}
+	if filter.ChatIDPrefix != "" {
+		query["chat_id"] = bson.M{"$regex": "^" + regexp.QuoteMeta(filter.ChatIDPrefix)}
+	}
+
+	// Apply time range filter; null times never match a range (like SQL)
+	if filter.StartTime != nil || filter.EndTime != nil {
+		cond := bson.M{"$ne": nil}
+		if filter.StartTime != nil {
+			cond["$gte"] = *filter.StartTime
+		}
+		if filter.EndTime != nil {
+			cond["$lte"] = *filter.EndTime
+		}
+		query[filter.TimeField] = cond
+	}
+
+	return query, nil
+}
+
+// toChatDoc converts a chat to its document form
+func toChatDoc(chat *types.Chat) *chatDoc {
+	return &chatDoc{
+		ChatID:        chat.ChatID,
+		Title:         chat.Title,
+		AssistantID:   chat.AssistantID,
+		LastConnector: chat.LastConnector,
+		LastMode:      chat.LastMode,
+		Status:        chat.Status,
+		Public:        chat.Public,
+		Share:         chat.Share,
+		Sort:          chat.Sort,
+		LastMessageAt: chat.LastMessageAt,
+		Metadata:      chat.Metadata,
+		CreatedAt:     chat.CreatedAt,
+		UpdatedAt:     chat.UpdatedAt,
+		CreatedBy:     chat.CreatedBy,
+		UpdatedBy:     chat.UpdatedBy,
+		TeamID:        chat.TeamID,
+		TenantID:      chat.TenantID,
+	}
+}
+
+// toChat converts a chat document to a chat
+func (doc *chatDoc) toChat() *types.Chat {
+	return &types.Chat{
+		ChatID:        doc.ChatID,
+		Title:         doc.Title,
+		AssistantID:   doc.AssistantID,
+		LastConnector: doc.LastConnector,
+		LastMode:      doc.LastMode,
+		Status:        doc.Status,
+		Public:        doc.Public,
+		Share:         doc.Share,
+		Sort:          doc.Sort,
+		LastMessageAt: doc.LastMessageAt,
+		Metadata:      normalize(doc.Metadata),
+		CreatedAt:     doc.CreatedAt,
+		UpdatedAt:     doc.UpdatedAt,
+		CreatedBy:     doc.CreatedBy,
+		UpdatedBy:     doc.UpdatedBy,
+		TeamID:        doc.TeamID,
+		TenantID:      doc.TenantID,
+	}
+}
+
+// normalize converts decoded BSON containers (primitive.D / primitive.A / primitive.M)
+// into plain maps and slices so callers see the same types as the other stores
+func normalize(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		out[key] = normalizeValue(value)
+	}
+	return out
+}
+
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bson.D:
+		out := make(map[string]interface{}, len(v))
+		for _, e := range v {
+			out[e.Key] = normalizeValue(e.Value)
+		}
+		return out
+	case bson.M:
+		return normalize(v)
+	case map[string]interface{}:
+		return normalize(v)
+	case bson.A:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = normalizeValue(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = normalizeValue(item)
+		}
+		return out
+	}
+	return value
+}
@@ -0,0 +1,240 @@
+package mongo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yaoapp/yao/agent/store/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// messageDoc is the document form of a message
+type messageDoc struct {
+	MessageID   string                 `bson:"message_id"`
+	ChatID      string                 `bson:"chat_id"`
+	RequestID   string                 `bson:"request_id,omitempty"`
+	Role        string                 `bson:"role"`
+	Type        string                 `bson:"type"`
+	Props       map[string]interface{} `bson:"props"`
+	BlockID     string                 `bson:"block_id,omitempty"`
+	ThreadID    string                 `bson:"thread_id,omitempty"`
+	AssistantID string                 `bson:"assistant_id,omitempty"`
+	Connector   string                 `bson:"connector,omitempty"`
+	Mode        string                 `bson:"mode,omitempty"`
+	Sequence    int                    `bson:"sequence"`
+	Metadata    map[string]interface{} `bson:"metadata,omitempty"`
+	CreatedAt   time.Time              `bson:"created_at"`
+	UpdatedAt   time.Time              `bson:"updated_at"`
+}
+
+// =============================================================================
+// Message Management
+// =============================================================================
+
+// SaveMessages batch saves messages for a chat
+// All messages are inserted with a single InsertMany call (ordered)
+func (m *Mongo) SaveMessages(chatID string, messages []*types.Message) error {
+	if chatID == "" {
+		return fmt.Errorf("chat_id is required")
+	}
+	if len(messages) == 0 {
+		return nil // Nothing to save
+	}
+
+	// Validate and prepare all messages before inserting
+	now := time.Now()
+	docs := make([]interface{}, 0, len(messages))
+	for _, msg := range messages {
+		if msg == nil {
+			continue
+		}
+
+		// Validate required fields
+		if msg.Role == "" {
+			return fmt.Errorf("message role is required")
+		}
+		if msg.Type == "" {
+			return fmt.Errorf("message type is required")
+		}
+		if msg.Props == nil {
+			return fmt.Errorf("message props is required")
+		}
+
+		if msg.MessageID == "" {
+			msg.MessageID = uuid.New().String()
+		}
+
+		docs = append(docs, &messageDoc{
+			MessageID:   msg.MessageID,
+			ChatID:      chatID,
+			RequestID:   msg.RequestID,
+			Role:        msg.Role,
+			Type:        msg.Type,
+			Props:       msg.Props,
+			BlockID:     msg.BlockID,
+			ThreadID:    msg.ThreadID,
+			AssistantID: msg.AssistantID,
+			Connector:   msg.Connector,
+			Mode:        msg.Mode,
+			Sequence:    msg.Sequence,
+			Metadata:    msg.Metadata,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+	}
+
+	if len(docs) == 0 {
+		return nil
+	}
+
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	if _, err := m.messages().InsertMany(ctx, docs); err != nil {
+		return fmt.Errorf("failed to save messages: %w", err)
+	}
+	return nil
+}
+
+// GetMessages retrieves messages for a chat with filtering
+func (m *Mongo) GetMessages(chatID string, filter types.MessageFilter) ([]*types.Message, error) {
+	if chatID == "" {
+		return nil, fmt.Errorf("chat_id is required")
+	}
+
+	query := bson.M{"chat_id": chatID}
+	if filter.RequestID != "" {
+		query["request_id"] = filter.RequestID
+	}
+	if filter.Role != "" {
+		query["role"] = filter.Role
+	}
+	if filter.BlockID != "" {
+		query["block_id"] = filter.BlockID
+	}
+	if filter.ThreadID != "" {
+		query["thread_id"] = filter.ThreadID
+	}
+	if filter.Type != "" {
+		query["type"] = filter.Type
+	}
+
+	// Limit without Offset returns the N most-recent messages in chronological
+	// order; Limit with Offset is forward pagination (same as the xun store).
+	latest := filter.Limit > 0 && filter.Offset <= 0
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	if latest {
+		opts.SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(int64(filter.Limit))
+	} else {
+		if filter.Offset > 0 {
+			opts.SetSkip(int64(filter.Offset))
+		}
+		if filter.Limit > 0 {
+			opts.SetLimit(int64(filter.Limit))
+		}
+	}
+
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	cursor, err := m.messages().Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	docs := []messageDoc{}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	messages := make([]*types.Message, len(docs))
+	for i := range docs {
+		messages[i] = docs[i].toMessage()
+	}
+
+	// Reverse the most-recent window back to chronological order
+	if latest {
+		for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+			messages[i], messages[j] = messages[j], messages[i]
+		}
+	}
+
+	return messages, nil
+}
+
+// UpdateMessage updates a single message
+func (m *Mongo) UpdateMessage(messageID string, updates map[string]interface{}) error {
+	if messageID == "" {
+		return fmt.Errorf("message_id is required")
+	}
+	if len(updates) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	set := bson.M{}
+	for key, value := range updates {
+		// Skip system fields
+		if key == "message_id" || key == "chat_id" || key == "created_at" || key == "_id" {
+			continue
+		}
+		set[key] = value
+	}
+
+	// Always update updated_at
+	set["updated_at"] = time.Now()
+
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	res, err := m.messages().UpdateOne(ctx, bson.M{"message_id": messageID}, bson.M{"$set": set})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("message %s not found", messageID)
+	}
+	return nil
+}
+
+// DeleteMessages deletes specific messages from a chat
+func (m *Mongo) DeleteMessages(chatID string, messageIDs []string) error {
+	if chatID == "" {
+		return fmt.Errorf("chat_id is required")
+	}
+	if len(messageIDs) == 0 {
+		return nil // Nothing to delete
+	}
+
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	_, err := m.messages().DeleteMany(ctx, bson.M{
+		"chat_id":    chatID,
+		"message_id": bson.M{"$in": messageIDs},
+	})
+	return err
+}
+
+// toMessage converts a message document to a message
+func (doc *messageDoc) toMessage() *types.Message {
+	return &types.Message{
+		MessageID:   doc.MessageID,
+		ChatID:      doc.ChatID,
+		RequestID:   doc.RequestID,
+		Role:        doc.Role,
+		Type:        doc.Type,
+		Props:       normalize(doc.Props),
+		BlockID:     doc.BlockID,
+		ThreadID:    doc.ThreadID,
+		AssistantID: doc.AssistantID,
+		Connector:   doc.Connector,
+		Mode:        doc.Mode,
+		Sequence:    doc.Sequence,
+		Metadata:    normalize(doc.Metadata),
+		CreatedAt:   doc.CreatedAt,
+		UpdatedAt:   doc.UpdatedAt,
+	}
+}
@@ -1,187 +1,172 @@
 package mongo
 
-import "github.com/yaoapp/yao/agent/store/types"
-
-// Mongo represents a MongoDB-based conversation storage
-type Mongo struct{}
-
-// NewMongo create a new mongo store
-func NewMongo() types.Store {
-	return &Mongo{}
-}
-
-// =============================================================================
-// Chat Management
-// =============================================================================
-
-// CreateChat creates a new chat session
-func (m *Mongo) CreateChat(chat *types.Chat) error {
-	// TODO: implement
-	return nil
-}
-
-// GetChat retrieves a single chat by ID
-func (m *Mongo) GetChat(chatID string) (*types.Chat, error) {
-	// TODO: implement
-	return nil, nil
-}
-
-// UpdateChat updates chat fields
-func (m *Mongo) UpdateChat(chatID string, updates map[string]interface{}) error {
-	// TODO: implement
-	return nil
-}
-
-// DeleteChat deletes a chat and its associated messages
-func (m *Mongo) DeleteChat(chatID string) error {
-	// TODO: implement
-	return nil
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yaoapp/gou/connector"
+	mongoconn "github.com/yaoapp/gou/connector/mongo"
+	"github.com/yaoapp/yao/agent/store/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DefaultTimeout is the default timeout of a single store operation
+const DefaultTimeout = 30 * time.Second
+
+// Mongo implements the Store interface using MongoDB documents.
+// Collections (the prefix can be set with setting.Options["prefix"]):
+//
+//	agent_chat       chat sessions
+//	agent_message    chat messages, ordered by _id (insertion order)
+//	agent_resume     resume records
+//	agent_search     search records and references
+//	agent_assistant  assistants, complex fields are stored as JSON strings like the xun store
+//
+// Filtering, sorting and pagination follow the xun (database) implementation.
+// The SQL QueryFilter callbacks of ChatFilter and AssistantFilter cannot be
+// evaluated on MongoDB; permission filtering translates the Access rules into
+// an $or condition instead, and a QueryFilter without Access rules is rejected
+// rather than ignored.
+type Mongo struct {
+	db      *mongo.Database
+	setting types.Setting
+	prefix  string
+	timeout time.Duration
 }
 
-// ListChats retrieves a paginated list of chats with optional grouping
-func (m *Mongo) ListChats(filter types.ChatFilter) (*types.ChatList, error) {
-	// TODO: implement
-	return nil, nil
-}
-
-// =============================================================================
-// Message Management
-// =============================================================================
-
-// SaveMessages batch saves messages for a chat
-func (m *Mongo) SaveMessages(chatID string, messages []*types.Message) error {
-	// TODO: implement
-	return nil
-}
-
-// GetMessages retrieves messages for a chat with filtering
-func (m *Mongo) GetMessages(chatID string, filter types.MessageFilter) ([]*types.Message, error) {
-	// TODO: implement
-	return nil, nil
-}
-
-// UpdateMessage updates a single message
-func (m *Mongo) UpdateMessage(messageID string, updates map[string]interface{}) error {
-	// TODO: implement
-	return nil
-}
-
-// DeleteMessages deletes specific messages from a chat
-func (m *Mongo) DeleteMessages(chatID string, messageIDs []string) error {
-	// TODO: implement
-	return nil
-}
-
-// =============================================================================
-// Resume Management (only called on failure/interrupt)
-// =============================================================================
-
-// SaveResume batch saves resume records
-func (m *Mongo) SaveResume(records []*types.Resume) error {
-	// TODO: implement
-	return nil
-}
-
-// GetResume retrieves all resume records for a chat
-func (m *Mongo) GetResume(chatID string) ([]*types.Resume, error) {
-	// TODO: implement
-	return nil, nil
-}
-
-// GetLastResume retrieves the last resume record for a chat
-func (m *Mongo) GetLastResume(chatID string) (*types.Resume, error) {
-	// TODO: implement
-	return nil, nil
-}
-
-// GetResumeByStackID retrieves resume records for a specific stack
-func (m *Mongo) GetResumeByStackID(stackID string) ([]*types.Resume, error) {
-	// TODO: implement
-	return nil, nil
-}
-
-// GetStackPath returns the stack path from root to the given stack
-func (m *Mongo) GetStackPath(stackID string) ([]string, error) {
-	// TODO: implement
-	return nil, nil
-}
-
-// DeleteResume deletes all resume records for a chat
-func (m *Mongo) DeleteResume(chatID string) error {
-	// TODO: implement
-	return nil
-}
-
-// =============================================================================
-// Assistant Management
-// =============================================================================
-
-// SaveAssistant saves assistant information
-func (m *Mongo) SaveAssistant(assistant *types.AssistantModel) (string, error) {
-	// TODO: implement
-	return assistant.ID, nil
-}
-
-// UpdateAssistant updates specific fields of an assistant
-func (m *Mongo) UpdateAssistant(assistantID string, updates map[string]interface{}) error {
-	// TODO: implement
-	return nil
-}
-
-// DeleteAssistant deletes an assistant
-func (m *Mongo) DeleteAssistant(assistantID string) error {
-	// TODO: implement
-	return nil
-}
+// NewMongo create a new mongo store
+func NewMongo(setting types.Setting) (types.Store, error) {
+	conn, err := connector.Select(setting.Connector)
+	if err != nil {
+		return nil, fmt.Errorf("select store connector %s error: %s", setting.Connector, err.Error())
+	}
 
-// GetAssistants retrieves a list of assistants
-func (m *Mongo) GetAssistants(filter types.AssistantFilter, locale ...string) (*types.AssistantList, error) {
-	// TODO: implement
-	return &types.AssistantList{}, nil
-}
+	mconn, ok := conn.(*mongoconn.Connector)
+	if !ok || mconn.Database == nil {
+		return nil, fmt.Errorf("store connector %s is not a mongo connector", setting.Connector)
+	}
 
-// GetAssistantTags retrieves all unique tags from assistants with filtering
-func (m *Mongo) GetAssistantTags(filter types.AssistantFilter, locale ...string) ([]types.Tag, error) {
-	// TODO: implement
-	return []types.Tag{}, nil
+	return New(mconn.Database, setting)
 }
 
-// GetAssistant retrieves a single assistant by ID
-func (m *Mongo) GetAssistant(assistantID string, fields []string, locale ...string) (*types.AssistantModel, error) {
-	// TODO: implement
-	return nil, nil
-}
+// New create a mongo store on the given database and ensure the indexes
+func New(db *mongo.Database, setting types.Setting) (*Mongo, error) {
+	prefix := "agent_"
+	if setting.Options != nil {
+		if p, ok := setting.Options["prefix"].(string); ok && p != "" {
+			prefix = p
+		}
+	}
 
-// DeleteAssistants deletes assistants based on filter conditions
-func (m *Mongo) DeleteAssistants(filter types.AssistantFilter) (int64, error) {
-	// TODO: implement
-	return 0, nil
+	m := &Mongo{db: db, setting: setting, prefix: prefix, timeout: DefaultTimeout}
+	if err := m.ensureIndexes(); err != nil {
+		return nil, fmt.Errorf("create store indexes error: %s", err.Error())
+	}
+	return m, nil
 }
 
 // =============================================================================
-// Search Management
+// Collections
 // =============================================================================
 
-// SaveSearch saves a search record for a request
-func (m *Mongo) SaveSearch(search *types.Search) error {
-	// TODO: implement
+func (m *Mongo) chats() *mongo.Collection      { return m.db.Collection(m.prefix + "chat") }
+func (m *Mongo) messages() *mongo.Collection   { return m.db.Collection(m.prefix + "message") }
+func (m *Mongo) resumes() *mongo.Collection    { return m.db.Collection(m.prefix + "resume") }
+func (m *Mongo) searches() *mongo.Collection   { return m.db.Collection(m.prefix + "search") }
+func (m *Mongo) assistants() *mongo.Collection { return m.db.Collection(m.prefix + "assistant") }
+
+// ctx returns a context bounded by the store timeout
+func (m *Mongo) ctx() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), m.timeout)
+}
+
+// ensureIndexes creates the indexes used by the store queries (idempotent)
+func (m *Mongo) ensureIndexes() error {
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	unique := options.Index().SetUnique(true)
+	indexes := map[*mongo.Collection][]mongo.IndexModel{
+		m.chats(): {
+			{Keys: bson.D{{Key: "chat_id", Value: 1}}, Options: unique},
+			{Keys: bson.D{{Key: "__yao_created_by", Value: 1}, {Key: "last_message_at", Value: -1}}},
+			{Keys: bson.D{{Key: "__yao_team_id", Value: 1}, {Key: "last_message_at", Value: -1}}},
+			{Keys: bson.D{{Key: "assistant_id", Value: 1}}},
+		},
+		m.messages(): {
+			{Keys: bson.D{{Key: "message_id", Value: 1}}, Options: unique},
+			{Keys: bson.D{{Key: "chat_id", Value: 1}, {Key: "_id", Value: 1}}},
+			{Keys: bson.D{{Key: "chat_id", Value: 1}, {Key: "block_id", Value: 1}}},
+			{Keys: bson.D{{Key: "chat_id", Value: 1}, {Key: "thread_id", Value: 1}}},
+			{Keys: bson.D{{Key: "request_id", Value: 1}}},
+		},
+		m.resumes(): {
+			{Keys: bson.D{{Key: "resume_id", Value: 1}}, Options: unique},
+			{Keys: bson.D{{Key: "chat_id", Value: 1}, {Key: "sequence", Value: 1}}},
+			{Keys: bson.D{{Key: "stack_id", Value: 1}, {Key: "sequence", Value: 1}}},
+			{Keys: bson.D{{Key: "request_id", Value: 1}}},
+		},
+		m.searches(): {
+			{Keys: bson.D{{Key: "request_id", Value: 1}, {Key: "created_at", Value: 1}}},
+			{Keys: bson.D{{Key: "chat_id", Value: 1}}},
+		},
+		m.assistants(): {
+			{Keys: bson.D{{Key: "assistant_id", Value: 1}}, Options: unique},
+			{Keys: bson.D{{Key: "sort", Value: 1}, {Key: "updated_at", Value: -1}}},
+			{Keys: bson.D{{Key: "type", Value: 1}}},
+			{Keys: bson.D{{Key: "tags", Value: 1}}},
+		},
+	}
+
+	for coll, models := range indexes {
+		if _, err := coll.Indexes().CreateMany(ctx, models); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// GetSearches retrieves all search records for a request
-func (m *Mongo) GetSearches(requestID string) ([]*types.Search, error) {
-	// TODO: implement
-	return nil, nil
-}
-
-// GetReference retrieves a single reference by request ID and index
-func (m *Mongo) GetReference(requestID string, index int) (*types.Reference, error) {
-	// TODO: implement
-	return nil, nil
-}
-
-// DeleteSearches deletes all search records for a chat
-func (m *Mongo) DeleteSearches(chatID string) error {
-	// TODO: implement
+// pagination returns the skip/limit find options of a page
+func pagination(page, pageSize int) *options.FindOptions {
+	return options.Find().
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize))
+}
+
+// accessQuery adds the permission condition of the access rules to a query document
+func accessQuery(query bson.M, queryFilter bool, rules []types.AccessRule) error {
+	if len(rules) == 0 {
+		if queryFilter {
+			return fmt.Errorf("QueryFilter is not supported by the mongo store, set Access rules for permission filtering")
+		}
+		return nil
+	}
+
+	or := bson.A{}
+	for _, rule := range rules {
+		cond := bson.M{}
+		if rule.Public {
+			cond["public"] = true
+		}
+		if rule.CreatedBy != "" {
+			cond["__yao_created_by"] = rule.CreatedBy
+		}
+		if rule.TeamID != "" {
+			cond["__yao_team_id"] = rule.TeamID
+		}
+		if rule.NoTeam {
+			cond["__yao_team_id"] = bson.M{"$in": bson.A{nil, ""}}
+		}
+		if rule.Share != "" {
+			cond["share"] = rule.Share
+		}
+		or = append(or, cond)
+	}
+
+	// Wrapped in $and so it does not collide with other $or conditions
+	query["$and"] = bson.A{bson.M{"$or": or}}
 	return nil
 }
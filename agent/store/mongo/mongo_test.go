@@ -0,0 +1,474 @@
+package mongo_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yaoapp/gou/connector"
+	mongoconn "github.com/yaoapp/gou/connector/mongo"
+	"github.com/yaoapp/xun/dbal/query"
+	"github.com/yaoapp/yao/agent/store/mongo"
+	"github.com/yaoapp/yao/agent/store/types"
+)
+
+// newTestStore creates a store on the test MongoDB with a unique collection prefix
+// The collections are dropped when the test finishes
+func newTestStore(t *testing.T) *mongo.Mongo {
+	host := os.Getenv("MONGO_TEST_HOST")
+	if host == "" {
+		t.Skip("MongoDB not available - set MONGO_TEST_HOST environment variable")
+	}
+
+	conn, err := connector.New("mongo", "agent_store_test", []byte(`{
+		"name": "Agent Store Test MongoDB",
+		"type": "mongo",
+		"options": {
+			"db": "agent_store_test",
+			"hosts": [{
+				"host": "`+host+`",
+				"port": "`+os.Getenv("MONGO_TEST_PORT")+`",
+				"user": "`+os.Getenv("MONGO_TEST_USER")+`",
+				"pass": "`+os.Getenv("MONGO_TEST_PASS")+`"
+			}]
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+
+	db := conn.(*mongoconn.Connector).Database
+	prefix := fmt.Sprintf("test_%s_", uuid.New().String()[:8])
+	store, err := mongo.New(db, types.Setting{Options: map[string]interface{}{"prefix": prefix}})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		for _, name := range []string{"chat", "message", "resume", "search", "assistant"} {
+			db.Collection(prefix + name).Drop(ctx)
+		}
+	})
+	return store
+}
+
+// TestChat tests chat persistence, listing and grouping
+func TestChat(t *testing.T) {
+	store := newTestStore(t)
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		lastMessageAt := now.Add(-time.Duration(i) * time.Hour)
+		chat := &types.Chat{
+			ChatID:        fmt.Sprintf("chat_%d", i),
+			Title:         fmt.Sprintf("Chat %d", i),
+			AssistantID:   fmt.Sprintf("assistant_%d", i%2),
+			LastMessageAt: &lastMessageAt,
+			Metadata:      map[string]interface{}{"index": i, "tags": []string{"a", "b"}},
+			CreatedBy:     "user_1",
+		}
+		if err := store.CreateChat(chat); err != nil {
+			t.Fatalf("Failed to create chat: %v", err)
+		}
+	}
+
+	t.Run("CreateDuplicateFails", func(t *testing.T) {
+		if err := store.CreateChat(&types.Chat{ChatID: "chat_0", AssistantID: "assistant_0"}); err == nil {
+			t.Error("Expected error when creating a duplicate chat")
+		}
+	})
+
+	t.Run("GetChat", func(t *testing.T) {
+		chat, err := store.GetChat("chat_1")
+		if err != nil {
+			t.Fatalf("Failed to get chat: %v", err)
+		}
+		if chat.Title != "Chat 1" || chat.Status != "active" || chat.Share != "private" {
+			t.Errorf("Unexpected chat: %+v", chat)
+		}
+		if _, ok := chat.Metadata["tags"].([]interface{}); !ok {
+			t.Errorf("Expected metadata arrays to be plain slices, got %T", chat.Metadata["tags"])
+		}
+	})
+
+	t.Run("UpdateChat", func(t *testing.T) {
+		if err := store.UpdateChat("chat_2", map[string]interface{}{"title": "Renamed", "chat_id": "hijacked"}); err != nil {
+			t.Fatalf("Failed to update chat: %v", err)
+		}
+		chat, err := store.GetChat("chat_2")
+		if err != nil {
+			t.Fatalf("Failed to get chat: %v", err)
+		}
+		if chat.Title != "Renamed" {
+			t.Errorf("Expected title 'Renamed', got '%s'", chat.Title)
+		}
+		if err := store.UpdateChat("missing", map[string]interface{}{"title": "x"}); err == nil {
+			t.Error("Expected error when updating non-existent chat")
+		}
+	})
+
+	t.Run("ListChats", func(t *testing.T) {
+		result, err := store.ListChats(types.ChatFilter{UserID: "user_1", PageSize: 2, GroupBy: "time"})
+		if err != nil {
+			t.Fatalf("Failed to list chats: %v", err)
+		}
+		if result.Total != 5 || result.PageCount != 3 || len(result.Data) != 2 {
+			t.Errorf("Unexpected pagination: total=%d pages=%d size=%d", result.Total, result.PageCount, len(result.Data))
+		}
+		if result.Data[0].ChatID != "chat_0" {
+			t.Errorf("Expected most recent chat first, got %s", result.Data[0].ChatID)
+		}
+		if len(result.Groups) == 0 {
+			t.Error("Expected time groups")
+		}
+
+		result, err = store.ListChats(types.ChatFilter{AssistantID: "assistant_1", Keywords: "chat"})
+		if err != nil {
+			t.Fatalf("Failed to list chats: %v", err)
+		}
+		if result.Total != 2 {
+			t.Errorf("Expected 2 chats of assistant_1, got %d", result.Total)
+		}
+	})
+
+	t.Run("DeleteChat", func(t *testing.T) {
+		if err := store.DeleteChat("chat_4"); err != nil {
+			t.Fatalf("Failed to delete chat: %v", err)
+		}
+		if _, err := store.GetChat("chat_4"); err == nil {
+			t.Error("Expected error for deleted chat")
+		}
+	})
+}
+
+// TestMessages tests message persistence, filters and ordering
+func TestMessages(t *testing.T) {
+	store := newTestStore(t)
+
+	chat := &types.Chat{AssistantID: "test_assistant"}
+	if err := store.CreateChat(chat); err != nil {
+		t.Fatalf("Failed to create chat: %v", err)
+	}
+
+	messages := []*types.Message{}
+	for i := 0; i < 6; i++ {
+		messages = append(messages, &types.Message{
+			MessageID: fmt.Sprintf("msg_%d", i),
+			RequestID: fmt.Sprintf("req_%d", i/2),
+			Role:      []string{"user", "assistant"}[i%2],
+			Type:      "text",
+			Props:     map[string]interface{}{"content": fmt.Sprintf("message %d", i)},
+			Sequence:  i,
+		})
+	}
+	if err := store.SaveMessages(chat.ChatID, messages); err != nil {
+		t.Fatalf("Failed to save messages: %v", err)
+	}
+
+	t.Run("GetAllMessagesInOrder", func(t *testing.T) {
+		result, err := store.GetMessages(chat.ChatID, types.MessageFilter{})
+		if err != nil {
+			t.Fatalf("Failed to get messages: %v", err)
+		}
+		if len(result) != 6 || result[0].MessageID != "msg_0" || result[5].MessageID != "msg_5" {
+			t.Errorf("Expected 6 messages in insertion order, got %d", len(result))
+		}
+	})
+
+	t.Run("LimitReturnsMostRecent", func(t *testing.T) {
+		result, err := store.GetMessages(chat.ChatID, types.MessageFilter{Limit: 2})
+		if err != nil {
+			t.Fatalf("Failed to get messages: %v", err)
+		}
+		if len(result) != 2 || result[0].MessageID != "msg_4" || result[1].MessageID != "msg_5" {
+			t.Errorf("Expected [msg_4 msg_5], got %d messages", len(result))
+		}
+	})
+
+	t.Run("LimitWithOffsetPaginatesForward", func(t *testing.T) {
+		result, err := store.GetMessages(chat.ChatID, types.MessageFilter{Limit: 2, Offset: 1, Role: "assistant"})
+		if err != nil {
+			t.Fatalf("Failed to get messages: %v", err)
+		}
+		if len(result) != 2 || result[0].MessageID != "msg_3" || result[1].MessageID != "msg_5" {
+			t.Errorf("Expected [msg_3 msg_5], got %d messages", len(result))
+		}
+	})
+
+	t.Run("UpdateAndDeleteMessages", func(t *testing.T) {
+		if err := store.UpdateMessage("msg_0", map[string]interface{}{"props": map[string]interface{}{"content": "done"}}); err != nil {
+			t.Fatalf("Failed to update message: %v", err)
+		}
+		if err := store.DeleteMessages(chat.ChatID, []string{"msg_1"}); err != nil {
+			t.Fatalf("Failed to delete messages: %v", err)
+		}
+
+		result, err := store.GetMessages(chat.ChatID, types.MessageFilter{RequestID: "req_0"})
+		if err != nil {
+			t.Fatalf("Failed to get messages: %v", err)
+		}
+		if len(result) != 1 || result[0].Props["content"] != "done" {
+			t.Errorf("Expected the updated msg_0 only, got %d messages", len(result))
+		}
+	})
+}
+
+// TestResumeAndSearch tests resume records, stack paths and search references
+func TestResumeAndSearch(t *testing.T) {
+	store := newTestStore(t)
+
+	err := store.SaveResume([]*types.Resume{
+		{ChatID: "resume_chat", RequestID: "req_1", AssistantID: "root", StackID: "stack_root", Type: types.ResumeTypeLLM, Status: types.ResumeStatusInterrupted, Sequence: 1},
+		{ChatID: "resume_chat", RequestID: "req_1", AssistantID: "leaf", StackID: "stack_leaf", StackParentID: "stack_root", StackDepth: 1, Type: types.ResumeTypeTool, Status: types.ResumeStatusFailed, Sequence: 2},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save resume records: %v", err)
+	}
+
+	t.Run("GetLastResumeAndStackPath", func(t *testing.T) {
+		last, err := store.GetLastResume("resume_chat")
+		if err != nil {
+			t.Fatalf("Failed to get last resume: %v", err)
+		}
+		if last == nil || last.StackID != "stack_leaf" {
+			t.Errorf("Expected last resume on stack_leaf, got %+v", last)
+		}
+
+		path, err := store.GetStackPath("stack_leaf")
+		if err != nil {
+			t.Fatalf("Failed to get stack path: %v", err)
+		}
+		if len(path) != 2 || path[0] != "stack_root" {
+			t.Errorf("Expected [stack_root stack_leaf], got %v", path)
+		}
+	})
+
+	t.Run("DeleteResume", func(t *testing.T) {
+		if err := store.DeleteResume("resume_chat"); err != nil {
+			t.Fatalf("Failed to delete resume: %v", err)
+		}
+		last, err := store.GetLastResume("resume_chat")
+		if err != nil {
+			t.Fatalf("Failed to get last resume: %v", err)
+		}
+		if last != nil {
+			t.Error("Expected nil after delete")
+		}
+	})
+
+	t.Run("SearchReferences", func(t *testing.T) {
+		err := store.SaveSearch(&types.Search{
+			RequestID:  "search_req",
+			ChatID:     "search_chat",
+			Source:     "web",
+			References: []types.Reference{{Index: 1, Type: "web", Title: "Yao"}},
+		})
+		if err != nil {
+			t.Fatalf("Failed to save search: %v", err)
+		}
+
+		ref, err := store.GetReference("search_req", 1)
+		if err != nil {
+			t.Fatalf("Failed to get reference: %v", err)
+		}
+		if ref.Title != "Yao" {
+			t.Errorf("Expected reference 'Yao', got '%s'", ref.Title)
+		}
+
+		if err := store.DeleteSearches("search_chat"); err != nil {
+			t.Fatalf("Failed to delete searches: %v", err)
+		}
+		result, err := store.GetSearches("search_req")
+		if err != nil {
+			t.Fatalf("Failed to get searches: %v", err)
+		}
+		if len(result) != 0 {
+			t.Errorf("Expected no searches after delete, got %d", len(result))
+		}
+	})
+}
+
+// TestAssistant tests assistant persistence, filtering and tags
+func TestAssistant(t *testing.T) {
+	store := newTestStore(t)
+
+	for i := 0; i < 4; i++ {
+		assistant := &types.AssistantModel{
+			ID:          fmt.Sprintf("assistant_%d", i),
+			Name:        fmt.Sprintf("Assistant %d", i),
+			Type:        "assistant",
+			Connector:   "openai",
+			Description: "A helpful assistant",
+			Sort:        i % 2,
+			Tags:        []string{fmt.Sprintf("tag_%d", i%2), "common"},
+			Mentionable: i%2 == 0,
+			Options:     map[string]interface{}{"temperature": 0.7},
+			YaoTeamID:   "team_1",
+		}
+		if i == 3 {
+			assistant.Type = "robot"
+			assistant.Connector = "claude"
+		}
+		if _, err := store.SaveAssistant(assistant); err != nil {
+			t.Fatalf("Failed to save assistant: %v", err)
+		}
+	}
+
+	t.Run("GetAssistant", func(t *testing.T) {
+		assistant, err := store.GetAssistant("assistant_0", []string{"assistant_id", "name", "options", "tags", "share", "created_at", "__yao_team_id"})
+		if err != nil {
+			t.Fatalf("Failed to get assistant: %v", err)
+		}
+		if assistant.Name != "Assistant 0" || assistant.Share != "private" || assistant.CreatedAt == 0 {
+			t.Errorf("Unexpected assistant: %+v", assistant)
+		}
+		if assistant.Options["temperature"] != 0.7 || len(assistant.Tags) != 2 {
+			t.Errorf("Expected options and tags to be kept")
+		}
+		if assistant.YaoTeamID != "team_1" {
+			t.Errorf("Expected team permission field to be kept, got '%s'", assistant.YaoTeamID)
+		}
+	})
+
+	t.Run("UpdateAssistant", func(t *testing.T) {
+		if err := store.UpdateAssistant("assistant_1", map[string]interface{}{"name": "Renamed"}); err != nil {
+			t.Fatalf("Failed to update assistant: %v", err)
+		}
+		result, err := store.GetAssistants(types.AssistantFilter{Keywords: "renamed"})
+		if err != nil {
+			t.Fatalf("Failed to get assistants: %v", err)
+		}
+		if result.Total != 1 {
+			t.Errorf("Expected 1 assistant matching keywords, got %d", result.Total)
+		}
+	})
+
+	t.Run("GetAssistantsWithFilters", func(t *testing.T) {
+		mentionable := false
+		result, err := store.GetAssistants(types.AssistantFilter{Mentionable: &mentionable, Page: 1, PageSize: 1})
+		if err != nil {
+			t.Fatalf("Failed to get assistants: %v", err)
+		}
+		if result.Total != 2 || result.PageCount != 2 || result.Next != 2 {
+			t.Errorf("Unexpected pagination: %+v", result)
+		}
+
+		result, err = store.GetAssistants(types.AssistantFilter{Tags: []string{"tag_1"}, Types: []string{"robot"}})
+		if err != nil {
+			t.Fatalf("Failed to get assistants: %v", err)
+		}
+		if result.Total != 1 || result.Data[0].ID != "assistant_3" {
+			t.Errorf("Expected only assistant_3, got %d", result.Total)
+		}
+	})
+
+	t.Run("GetAssistantTags", func(t *testing.T) {
+		tags, err := store.GetAssistantTags(types.AssistantFilter{Type: "assistant"})
+		if err != nil {
+			t.Fatalf("Failed to get tags: %v", err)
+		}
+		if len(tags) != 3 {
+			t.Errorf("Expected 3 unique tags, got %d", len(tags))
+		}
+	})
+
+	t.Run("DeleteAssistants", func(t *testing.T) {
+		count, err := store.DeleteAssistants(types.AssistantFilter{Connector: "claude"})
+		if err != nil {
+			t.Fatalf("Failed to delete assistants: %v", err)
+		}
+		if count != 1 {
+			t.Errorf("Expected 1 deleted assistant, got %d", count)
+		}
+		if err := store.DeleteAssistant("assistant_3"); err == nil {
+			t.Error("Expected error when deleting an already deleted assistant")
+		}
+	})
+}
+
+// TestPermission tests that access rules keep other users' private chats and assistants out of listings
+func TestPermission(t *testing.T) {
+	store := newTestStore(t)
+
+	chats := []*types.Chat{
+		{ChatID: "perm_alice_private", AssistantID: "a", CreatedBy: "alice", TeamID: "team_perm", Share: "private"},
+		{ChatID: "perm_alice_shared", AssistantID: "a", CreatedBy: "alice", TeamID: "team_perm", Share: "team"},
+		{ChatID: "perm_bob_private", AssistantID: "a", CreatedBy: "bob", TeamID: "team_perm", Share: "private"},
+		{ChatID: "perm_carol_other", AssistantID: "a", CreatedBy: "carol", TeamID: "team_other", Share: "team"},
+	}
+	for _, chat := range chats {
+		if err := store.CreateChat(chat); err != nil {
+			t.Fatalf("Failed to create chat: %v", err)
+		}
+	}
+
+	assistants := []*types.AssistantModel{
+		{ID: "perm_alice_private", Name: "Alice Private", Share: "private", YaoCreatedBy: "alice", YaoTeamID: "team_perm"},
+		{ID: "perm_alice_shared", Name: "Alice Shared", Share: "team", YaoCreatedBy: "alice", YaoTeamID: "team_perm"},
+		{ID: "perm_bob_personal", Name: "Bob Personal", Share: "private", YaoCreatedBy: "bob"},
+		{ID: "perm_public", Name: "Public", Public: true, Share: "private", YaoCreatedBy: "carol", YaoTeamID: "team_other"},
+	}
+	for _, assistant := range assistants {
+		assistant.Type = "assistant"
+		assistant.Connector = "openai"
+		if _, err := store.SaveAssistant(assistant); err != nil {
+			t.Fatalf("Failed to save assistant: %v", err)
+		}
+	}
+
+	t.Run("TeamMemberCannotListOthersPrivateChats", func(t *testing.T) {
+		result, err := store.ListChats(types.ChatFilter{Access: []types.AccessRule{
+			{CreatedBy: "bob"},
+			{TeamID: "team_perm", Share: "team"},
+		}})
+		if err != nil {
+			t.Fatalf("Failed to list chats: %v", err)
+		}
+
+		ids := map[string]bool{}
+		for _, chat := range result.Data {
+			ids[chat.ChatID] = true
+		}
+		if ids["perm_alice_private"] || ids["perm_carol_other"] {
+			t.Errorf("Expected other users' private and other teams' chats to be hidden, got %v", ids)
+		}
+		if result.Total != 2 || !ids["perm_bob_private"] || !ids["perm_alice_shared"] {
+			t.Errorf("Expected bob's own and the team shared chat, got %v", ids)
+		}
+	})
+
+	t.Run("OwnerOnlyAssistantsWithKeywords", func(t *testing.T) {
+		result, err := store.GetAssistants(types.AssistantFilter{
+			Keywords: "p",
+			Access: []types.AccessRule{
+				{Public: true},
+				{NoTeam: true, CreatedBy: "bob"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to get assistants: %v", err)
+		}
+
+		ids := map[string]bool{}
+		for _, assistant := range result.Data {
+			ids[assistant.ID] = true
+		}
+		if result.Total != 2 || !ids["perm_bob_personal"] || !ids["perm_public"] {
+			t.Errorf("Expected bob's personal and the public assistant, got %v", ids)
+		}
+	})
+
+	t.Run("QueryFilterWithoutAccessFails", func(t *testing.T) {
+		if _, err := store.ListChats(types.ChatFilter{QueryFilter: func(query.Query) {}}); err == nil {
+			t.Error("Expected error when chat permissions are given only as QueryFilter")
+		}
+		if _, err := store.GetAssistants(types.AssistantFilter{QueryFilter: func(query.Query) {}}); err == nil {
+			t.Error("Expected error when assistant permissions are given only as QueryFilter")
+		}
+	})
+}
@@ -0,0 +1,251 @@
+package mongo
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yaoapp/yao/agent/store/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// resumeDoc is the document form of a resume record
+type resumeDoc struct {
+	ResumeID      string                 `bson:"resume_id"`
+	ChatID        string                 `bson:"chat_id"`
+	RequestID     string                 `bson:"request_id"`
+	AssistantID   string                 `bson:"assistant_id"`
+	StackID       string                 `bson:"stack_id"`
+	StackParentID string                 `bson:"stack_parent_id,omitempty"`
+	StackDepth    int                    `bson:"stack_depth"`
+	Type          string                 `bson:"type"`
+	Status        string                 `bson:"status"`
+	Input         map[string]interface{} `bson:"input,omitempty"`
+	Output        map[string]interface{} `bson:"output,omitempty"`
+	SpaceSnapshot map[string]interface{} `bson:"space_snapshot,omitempty"`
+	Error         string                 `bson:"error,omitempty"`
+	Sequence      int                    `bson:"sequence"`
+	Metadata      map[string]interface{} `bson:"metadata,omitempty"`
+	CreatedAt     time.Time              `bson:"created_at"`
+	UpdatedAt     time.Time              `bson:"updated_at"`
+}
+
+// bySequence sorts resume records by sequence, keeping insertion order for ties
+var bySequence = bson.D{{Key: "sequence", Value: 1}, {Key: "_id", Value: 1}}
+
+// =============================================================================
+// Resume Management (only called on failure/interrupt)
+// =============================================================================
+
+// SaveResume batch saves resume records
+// Only called when request is interrupted or failed
+func (m *Mongo) SaveResume(records []*types.Resume) error {
+	if len(records) == 0 {
+		return nil // Nothing to save
+	}
+
+	// Validate and prepare all records before inserting
+	now := time.Now()
+	docs := make([]interface{}, 0, len(records))
+	for _, record := range records {
+		if record == nil {
+			continue
+		}
+
+		// Validate required fields
+		if record.ChatID == "" {
+			return fmt.Errorf("chat_id is required")
+		}
+		if record.RequestID == "" {
+			return fmt.Errorf("request_id is required")
+		}
+		if record.AssistantID == "" {
+			return fmt.Errorf("assistant_id is required")
+		}
+		if record.StackID == "" {
+			return fmt.Errorf("stack_id is required")
+		}
+		if record.Type == "" {
+			return fmt.Errorf("type is required")
+		}
+		if record.Status == "" {
+			return fmt.Errorf("status is required")
+		}
+
+		if record.ResumeID == "" {
+			record.ResumeID = uuid.New().String()
+		}
+
+		docs = append(docs, &resumeDoc{
+			ResumeID:      record.ResumeID,
+			ChatID:        record.ChatID,
+			RequestID:     record.RequestID,
+			AssistantID:   record.AssistantID,
+			StackID:       record.StackID,
+			StackParentID: record.StackParentID,
+			StackDepth:    record.StackDepth,
+			Type:          record.Type,
+			Status:        record.Status,
+			Input:         record.Input,
+			Output:        record.Output,
+			SpaceSnapshot: record.SpaceSnapshot,
+			Error:         record.Error,
+			Sequence:      record.Sequence,
+			Metadata:      record.Metadata,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+	}
+
+	if len(docs) == 0 {
+		return nil
+	}
+
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	if _, err := m.resumes().InsertMany(ctx, docs); err != nil {
+		return fmt.Errorf("failed to save resume records: %w", err)
+	}
+	return nil
+}
+
+// GetResume retrieves all resume records for a chat ordered by sequence
+func (m *Mongo) GetResume(chatID string) ([]*types.Resume, error) {
+	if chatID == "" {
+		return nil, fmt.Errorf("chat_id is required")
+	}
+	return m.findResume(bson.M{"chat_id": chatID})
+}
+
+// GetLastResume retrieves the last (highest sequence) resume record for a chat
+func (m *Mongo) GetLastResume(chatID string) (*types.Resume, error) {
+	if chatID == "" {
+		return nil, fmt.Errorf("chat_id is required")
+	}
+
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	opts := options.FindOne().SetSort(bson.D{{Key: "sequence", Value: -1}, {Key: "_id", Value: -1}})
+
+	var doc resumeDoc
+	err := m.resumes().FindOne(ctx, bson.M{"chat_id": chatID}, opts).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil // No resume records found
+	}
+	if err != nil {
+		return nil, err
+	}
+	return doc.toResume(), nil
+}
+
+// GetResumeByStackID retrieves resume records for a specific stack
+func (m *Mongo) GetResumeByStackID(stackID string) ([]*types.Resume, error) {
+	if stackID == "" {
+		return nil, fmt.Errorf("stack_id is required")
+	}
+	return m.findResume(bson.M{"stack_id": stackID})
+}
+
+// GetStackPath returns the stack path from root to the given stack
+// Returns: [root_stack_id, ..., current_stack_id]
+func (m *Mongo) GetStackPath(stackID string) ([]string, error) {
+	if stackID == "" {
+		return nil, fmt.Errorf("stack_id is required")
+	}
+
+	path := []string{stackID}
+	visited := map[string]bool{stackID: true}
+	currentStackID := stackID
+
+	// Walk up the stack tree by following stack_parent_id
+	for {
+		records, err := m.GetResumeByStackID(currentStackID)
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == 0 {
+			break
+		}
+
+		parentID := records[0].StackParentID
+		if parentID == "" || visited[parentID] {
+			break // Reached root (or a malformed cycle)
+		}
+
+		// Prepend parent to path
+		path = append([]string{parentID}, path...)
+		visited[parentID] = true
+		currentStackID = parentID
+	}
+
+	return path, nil
+}
+
+// DeleteResume deletes all resume records for a chat
+// Called after successful resume to clean up
+func (m *Mongo) DeleteResume(chatID string) error {
+	if chatID == "" {
+		return fmt.Errorf("chat_id is required")
+	}
+
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	_, err := m.resumes().DeleteMany(ctx, bson.M{"chat_id": chatID})
+	return err
+}
+
+// =============================================================================
+// Helper Functions
+// =============================================================================
+
+// findResume finds resume records ordered by sequence
+func (m *Mongo) findResume(query bson.M) ([]*types.Resume, error) {
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	cursor, err := m.resumes().Find(ctx, query, options.Find().SetSort(bySequence))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	docs := []resumeDoc{}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	records := make([]*types.Resume, len(docs))
+	for i := range docs {
+		records[i] = docs[i].toResume()
+	}
+	return records, nil
+}
+
+// toResume converts a resume document to a resume record
+func (doc *resumeDoc) toResume() *types.Resume {
+	return &types.Resume{
+		ResumeID:      doc.ResumeID,
+		ChatID:        doc.ChatID,
+		RequestID:     doc.RequestID,
+		AssistantID:   doc.AssistantID,
+		StackID:       doc.StackID,
+		StackParentID: doc.StackParentID,
+		StackDepth:    doc.StackDepth,
+		Type:          doc.Type,
+		Status:        doc.Status,
+		Input:         normalize(doc.Input),
+		Output:        normalize(doc.Output),
+		SpaceSnapshot: normalize(doc.SpaceSnapshot),
+		Error:         doc.Error,
+		Sequence:      doc.Sequence,
+		Metadata:      normalize(doc.Metadata),
+		CreatedAt:     doc.CreatedAt,
+		UpdatedAt:     doc.UpdatedAt,
+	}
+}
@@ -0,0 +1,140 @@
+package mongo
+
+import (
+	"fmt"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/yao/agent/store/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// searchDoc is the document form of a search record.
+// The record itself is kept as JSON, only the lookup fields are indexed.
+type searchDoc struct {
+	ID        int64     `bson:"id"`
+	RequestID string    `bson:"request_id"`
+	ChatID    string    `bson:"chat_id"`
+	Source    string    `bson:"source"`
+	Data      string    `bson:"data"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+// =============================================================================
+// Search Management
+// =============================================================================
+
+// SaveSearch saves a search record for a request
+func (m *Mongo) SaveSearch(search *types.Search) error {
+	if search == nil {
+		return fmt.Errorf("search is nil")
+	}
+	if search.RequestID == "" {
+		return fmt.Errorf("request_id is required")
+	}
+	if search.ChatID == "" {
+		return fmt.Errorf("chat_id is required")
+	}
+	if search.Source == "" {
+		return fmt.Errorf("source is required")
+	}
+
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	count, err := m.searches().CountDocuments(ctx, bson.M{"request_id": search.RequestID})
+	if err != nil {
+		return err
+	}
+
+	row := *search
+	row.ID = count + 1
+	row.CreatedAt = time.Now()
+
+	data, err := jsoniter.MarshalToString(&row)
+	if err != nil {
+		return fmt.Errorf("failed to marshal search: %w", err)
+	}
+
+	_, err = m.searches().InsertOne(ctx, &searchDoc{
+		ID:        row.ID,
+		RequestID: row.RequestID,
+		ChatID:    row.ChatID,
+		Source:    row.Source,
+		Data:      data,
+		CreatedAt: row.CreatedAt,
+	})
+	return err
+}
+
+// GetSearches retrieves all search records for a request
+func (m *Mongo) GetSearches(requestID string) ([]*types.Search, error) {
+	if requestID == "" {
+		return nil, fmt.Errorf("request_id is required")
+	}
+
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := m.searches().Find(ctx, bson.M{"request_id": requestID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	docs := []searchDoc{}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	searches := make([]*types.Search, 0, len(docs))
+	for _, doc := range docs {
+		var search types.Search
+		if err := jsoniter.UnmarshalFromString(doc.Data, &search); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal search: %w", err)
+		}
+		searches = append(searches, &search)
+	}
+	return searches, nil
+}
+
+// GetReference retrieves a single reference by request ID and index
+func (m *Mongo) GetReference(requestID string, index int) (*types.Reference, error) {
+	if requestID == "" {
+		return nil, fmt.Errorf("request_id is required")
+	}
+	if index < 1 {
+		return nil, fmt.Errorf("index must be >= 1")
+	}
+
+	searches, err := m.GetSearches(requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the reference with matching index
+	for _, search := range searches {
+		for _, ref := range search.References {
+			if ref.Index == index {
+				return &ref, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("reference not found: request_id=%s, index=%d", requestID, index)
+}
+
+// DeleteSearches deletes all search records for a chat
+func (m *Mongo) DeleteSearches(chatID string) error {
+	if chatID == "" {
+		return fmt.Errorf("chat_id is required")
+	}
+
+	ctx, cancel := m.ctx()
+	defer cancel()
+
+	_, err := m.searches().DeleteMany(ctx, bson.M{"chat_id": chatID})
+	return err
+}
@@ -0,0 +1,507 @@
+package redis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/yao/agent/i18n"
+	"github.com/yaoapp/yao/agent/store/types"
+)
+
+// permissionFields are persisted next to the assistant JSON because
+// AssistantModel hides them from its JSON encoding
+var permissionFields = []string{"__yao_created_by", "__yao_updated_by", "__yao_team_id", "__yao_tenant_id"}
+
+// =============================================================================
+// Assistant Management
+// =============================================================================
+
+// SaveAssistant creates or updates an assistant
+func (r *Redis) SaveAssistant(assistant *types.AssistantModel) (string, error) {
+	if assistant == nil {
+		return "", fmt.Errorf("assistant cannot be nil")
+	}
+
+	// Validate required fields
+	if assistant.Name == "" {
+		return "", fmt.Errorf("field name is required")
+	}
+	if assistant.Type == "" {
+		return "", fmt.Errorf("field type is required")
+	}
+	if assistant.Connector == "" {
+		return "", fmt.Errorf("field connector is required")
+	}
+
+	// Generate assistant_id if not provided
+	if assistant.ID == "" {
+		var err error
+		assistant.ID, err = r.GenerateAssistantID()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	data, err := encodeAssistant(assistant)
+	if err != nil {
+		return "", err
+	}
+
+	// Share field defaults to private
+	if assistant.Share == "" {
+		data["share"] = "private"
+	}
+
+	// Set timestamps, created_at is kept on update
+	now := time.Now().UnixNano()
+	existing, exists, err := r.loadAssistantData(assistant.ID)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		data["created_at"] = existing["created_at"]
+		if assistant.UpdatedAt == 0 {
+			data["updated_at"] = now
+		}
+	} else {
+		if assistant.CreatedAt == 0 {
+			data["created_at"] = now
+		}
+		data["updated_at"] = 0
+	}
+
+	if err := r.setJSON(r.key("assistant", assistant.ID), data, 0); err != nil {
+		return "", err
+	}
+	if err := r.addToSet(r.key("assistants"), assistant.ID); err != nil {
+		return "", err
+	}
+	return assistant.ID, nil
+}
+
+// UpdateAssistant updates specific fields of an assistant
+func (r *Redis) UpdateAssistant(assistantID string, updates map[string]interface{}) error {
+	if assistantID == "" {
+		return fmt.Errorf("assistant_id is required")
+	}
+	if len(updates) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	data, exists, err := r.loadAssistantData(assistantID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("assistant %s not found", assistantID)
+	}
+
+	for key, value := range updates {
+		// Skip system fields that shouldn't be updated directly
+		if key == "assistant_id" || key == "created_at" {
+			continue
+		}
+
+		// Empty strings clear nullable fields
+		if s, ok := value.(string); ok && s == "" {
+			delete(data, key)
+			continue
+		}
+		data[key] = value
+	}
+
+	// Always update updated_at timestamp
+	data["updated_at"] = time.Now().UnixNano()
+
+	// Round trip through the model to reject updates with invalid types
+	if _, err := decodeAssistant(data); err != nil {
+		return fmt.Errorf("invalid assistant updates: %w", err)
+	}
+
+	return r.setJSON(r.key("assistant", assistantID), data, 0)
+}
+
+// DeleteAssistant deletes an assistant by assistant_id
+func (r *Redis) DeleteAssistant(assistantID string) error {
+	key := r.key("assistant", assistantID)
+	if !r.kv.Has(key) {
+		return fmt.Errorf("assistant %s not found", assistantID)
+	}
+	if err := r.kv.Del(key); err != nil {
+		return err
+	}
+	return r.pullFromSet(r.key("assistants"), assistantID)
+}
+
+// GetAssistants retrieves assistants with pagination and filtering
+func (r *Redis) GetAssistants(filter types.AssistantFilter, locale ...string) (*types.AssistantList, error) {
+	matched, err := r.findAssistants(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Same order as the database store: sort asc, updated_at desc
+	sort.SliceStable(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		if a.model.Sort != b.model.Sort {
+			return a.model.Sort < b.model.Sort
+		}
+		return a.model.UpdatedAt > b.model.UpdatedAt
+	})
+
+	// Set defaults for pagination
+	if filter.PageSize <= 0 {
+		filter.PageSize = 20
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+
+	// Calculate pagination
+	total := len(matched)
+	offset := (filter.Page - 1) * filter.PageSize
+	totalPages := int(math.Ceil(float64(total) / float64(filter.PageSize)))
+	nextPage := filter.Page + 1
+	if nextPage > totalPages {
+		nextPage = 0
+	}
+	prevPage := filter.Page - 1
+	if prevPage < 1 {
+		prevPage = 0
+	}
+
+	end := offset + filter.PageSize
+	if offset > total {
+		offset = total
+	}
+	if end > total {
+		end = total
+	}
+
+	assistants := make([]*types.AssistantModel, 0, end-offset)
+	for _, item := range matched[offset:end] {
+		model := item.model
+
+		// Apply select fields (only if fields are explicitly specified)
+		if len(filter.Select) > 0 {
+			model, err = decodeAssistant(selectFields(item.data, filter.Select))
+			if err != nil {
+				log.Error("Failed to convert assistant %s: %s", item.model.ID, err.Error())
+				continue
+			}
+		}
+
+		// Apply i18n translations if locale is provided
+		if len(locale) > 0 && locale[0] != "" {
+			types.TranslateAssistant(model, model.ID, locale[0])
+		}
+
+		assistants = append(assistants, model)
+	}
+
+	return &types.AssistantList{
+		Data:      assistants,
+		Page:      filter.Page,
+		PageSize:  filter.PageSize,
+		PageCount: totalPages,
+		Next:      nextPage,
+		Prev:      prevPage,
+		Total:     total,
+	}, nil
+}
+
+// GetAssistant retrieves a single assistant by ID
+func (r *Redis) GetAssistant(assistantID string, fields []string, locale ...string) (*types.AssistantModel, error) {
+	data, exists, err := r.loadAssistantData(assistantID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("assistant %s not found", assistantID)
+	}
+
+	// If no fields specified, use default fields
+	fieldsToSelect := fields
+	if len(fieldsToSelect) == 0 {
+		fieldsToSelect = types.AssistantDefaultFields
+	}
+
+	model, err := decodeAssistant(selectFields(data, fieldsToSelect))
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply i18n translation if locale is provided
+	if len(locale) > 0 && locale[0] != "" {
+		types.TranslateAssistant(model, assistantID, locale[0])
+	}
+
+	return model, nil
+}
+
+// DeleteAssistants deletes assistants based on filter conditions
+func (r *Redis) DeleteAssistants(filter types.AssistantFilter) (int64, error) {
+	matched, err := r.findAssistants(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	for _, item := range matched {
+		if err := r.kv.Del(r.key("assistant", item.model.ID)); err != nil {
+			return count, err
+		}
+		if err := r.pullFromSet(r.key("assistants"), item.model.ID); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// GetAssistantTags retrieves all unique tags from assistants with filtering
+func (r *Redis) GetAssistantTags(filter types.AssistantFilter, locale ...string) ([]types.Tag, error) {
+	// Tags are collected over every matched assistant, not one page
+	filter.Tags = nil
+	matched, err := r.findAssistants(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	tagSet := map[string]bool{}
+	for _, item := range matched {
+		for _, tag := range item.model.Tags {
+			tagSet[tag] = true
+		}
+	}
+
+	lang := "en"
+	if len(locale) > 0 {
+		lang = locale[0]
+	}
+
+	tags := make([]types.Tag, 0, len(tagSet))
+	for tag := range tagSet {
+		tags = append(tags, types.Tag{
+			Value: tag,
+			Label: i18n.TranslateGlobal(lang, tag).(string),
+		})
+	}
+	return tags, nil
+}
+
+// GenerateAssistantID generates a random-looking 6-digit ID
+func (r *Redis) GenerateAssistantID() (string, error) {
+	maxAttempts := 10 // Maximum number of attempts to generate a unique ID
+	for i := 0; i < maxAttempts; i++ {
+		timestamp := time.Now().UnixNano()
+		random := (timestamp ^ (timestamp >> 12)) % 1000000
+		hash := fmt.Sprintf("%06d", random)
+
+		if !r.kv.Has(r.key("assistant", hash)) {
+			return hash, nil
+		}
+
+		// If ID exists, wait a bit and try again
+		time.Sleep(time.Millisecond)
+	}
+
+	return "", fmt.Errorf("failed to generate unique ID after %d attempts", maxAttempts)
+}
+
+// =============================================================================
+// Helper Functions
+// =============================================================================
+
+// assistantItem is a loaded assistant with its raw stored fields
+type assistantItem struct {
+	model *types.AssistantModel
+	data  map[string]interface{}
+}
+
+// findAssistants loads all assistants matching the filter, permissions are checked with the Access rules
+func (r *Redis) findAssistants(filter types.AssistantFilter) ([]*assistantItem, error) {
+	if err := checkAccess(filter.QueryFilter != nil, filter.Access); err != nil {
+		return nil, err
+	}
+
+	// Read the requested assistants directly, the others through the assistant index set
+	ids := filter.AssistantIDs
+	if filter.AssistantID != "" {
+		ids = []string{filter.AssistantID}
+	}
+	if len(ids) == 0 {
+		var err error
+		ids, err = r.listIDs(r.key("assistants"))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	items := []*assistantItem{}
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		key := r.key("assistant", id)
+		data := map[string]interface{}{}
+		ok, err := r.getJSON(key, &data)
+		if err != nil || !ok {
+			continue
+		}
+
+		model, err := decodeAssistant(data)
+		if err != nil {
+			log.Error("Failed to convert assistant %s: %s", key, err.Error())
+			continue
+		}
+
+		if matchAssistant(model, data, filter) {
+			items = append(items, &assistantItem{model: model, data: data})
+		}
+	}
+	return items, nil
+}
+
+// loadAssistantData loads the stored fields of an assistant
+func (r *Redis) loadAssistantData(assistantID string) (map[string]interface{}, bool, error) {
+	data := map[string]interface{}{}
+	ok, err := r.getJSON(r.key("assistant", assistantID), &data)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// matchAssistant evaluates the assistant filter conditions
+func matchAssistant(model *types.AssistantModel, data map[string]interface{}, filter types.AssistantFilter) bool {
+	if !types.MatchAccess(filter.Access, model.Public, model.YaoCreatedBy, model.YaoTeamID, model.Share) {
+		return false
+	}
+
+	// Any of the tags matches
+	if len(filter.Tags) > 0 {
+		found := false
+		for _, want := range filter.Tags {
+			for _, tag := range model.Tags {
+				if tag == want {
+					found = true
+					break
+				}
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if filter.Keywords != "" {
+		locales, _ := jsoniter.MarshalToString(model.Locales)
+		if !containsFold(model.Name, filter.Keywords) &&
+			!containsFold(model.Description, filter.Keywords) &&
+			!containsFold(model.Capabilities, filter.Keywords) &&
+			!containsFold(locales, filter.Keywords) {
+			return false
+		}
+	}
+
+	if filter.Type != "" && model.Type != filter.Type {
+		return false
+	}
+	if len(filter.Types) > 0 && !inStrings(filter.Types, model.Type) {
+		return false
+	}
+	if filter.Connector != "" && model.Connector != filter.Connector {
+		return false
+	}
+	if filter.AssistantID != "" && model.ID != filter.AssistantID {
+		return false
+	}
+	if len(filter.AssistantIDs) > 0 && !inStrings(filter.AssistantIDs, model.ID) {
+		return false
+	}
+	if filter.Mentionable != nil && model.Mentionable != *filter.Mentionable {
+		return false
+	}
+	if filter.Automated != nil && model.Automated != *filter.Automated {
+		return false
+	}
+	if filter.BuiltIn != nil && model.BuiltIn != *filter.BuiltIn {
+		return false
+	}
+	if filter.Sandbox != nil && (data["sandbox"] != nil) != *filter.Sandbox {
+		return false
+	}
+
+	return true
+}
+
+// encodeAssistant converts an assistant into its stored fields
+func encodeAssistant(assistant *types.AssistantModel) (map[string]interface{}, error) {
+	data := map[string]interface{}{}
+	raw, err := jsoniter.Marshal(assistant)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal assistant: %w", err)
+	}
+	if err := jsoniter.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal assistant: %w", err)
+	}
+
+	permissions := []string{assistant.YaoCreatedBy, assistant.YaoUpdatedBy, assistant.YaoTeamID, assistant.YaoTenantID}
+	for i, field := range permissionFields {
+		if permissions[i] != "" {
+			data[field] = permissions[i]
+		}
+	}
+	return data, nil
+}
+
+// decodeAssistant converts stored fields into an assistant
+func decodeAssistant(data map[string]interface{}) (*types.AssistantModel, error) {
+	raw, err := jsoniter.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	model := &types.AssistantModel{}
+	if err := jsoniter.Unmarshal(raw, model); err != nil {
+		return nil, err
+	}
+
+	model.YaoCreatedBy, _ = data["__yao_created_by"].(string)
+	model.YaoUpdatedBy, _ = data["__yao_updated_by"].(string)
+	model.YaoTeamID, _ = data["__yao_team_id"].(string)
+	model.YaoTenantID, _ = data["__yao_tenant_id"].(string)
+	return model, nil
+}
+
+// selectFields keeps only the whitelisted fields of the stored data
+func selectFields(data map[string]interface{}, fields []string) map[string]interface{} {
+	selected := map[string]interface{}{}
+	for _, field := range types.ValidateAssistantFields(fields) {
+		if field == "id" {
+			continue
+		}
+		if value, has := data[field]; has {
+			selected[field] = value
+		}
+	}
+	return selected
+}
+
+// inStrings reports whether value is in list
+func inStrings(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,215 @@
+package redis_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/yaoapp/yao/agent/store/types"
+)
+
+// TestAssistant tests assistant persistence, filtering and tags
+func TestAssistant(t *testing.T) {
+	store := newTestStore(t)
+
+	for i := 0; i < 4; i++ {
+		assistant := &types.AssistantModel{
+			ID:          fmt.Sprintf("assistant_%d", i),
+			Name:        fmt.Sprintf("Assistant %d", i),
+			Type:        "assistant",
+			Connector:   "openai",
+			Description: "A helpful assistant",
+			Sort:        i % 2,
+			Tags:        []string{fmt.Sprintf("tag_%d", i%2), "common"},
+			Mentionable: i%2 == 0,
+			Options:     map[string]interface{}{"temperature": 0.7},
+			Prompts:     []types.Prompt{{Role: "system", Content: "You are helpful"}},
+			YaoTeamID:   "team_1",
+		}
+		if i == 3 {
+			assistant.Type = "robot"
+			assistant.Connector = "claude"
+		}
+		if _, err := store.SaveAssistant(assistant); err != nil {
+			t.Fatalf("Failed to save assistant: %v", err)
+		}
+	}
+
+	t.Run("SaveGeneratesID", func(t *testing.T) {
+		id, err := store.SaveAssistant(&types.AssistantModel{Name: "Generated", Type: "temp", Connector: "openai"})
+		if err != nil {
+			t.Fatalf("Failed to save assistant: %v", err)
+		}
+		if len(id) != 6 {
+			t.Errorf("Expected a 6-digit ID, got '%s'", id)
+		}
+		if err := store.DeleteAssistant(id); err != nil {
+			t.Fatalf("Failed to delete assistant: %v", err)
+		}
+	})
+
+	t.Run("SaveWithoutNameFails", func(t *testing.T) {
+		if _, err := store.SaveAssistant(&types.AssistantModel{Type: "assistant", Connector: "openai"}); err == nil {
+			t.Error("Expected error when name is missing")
+		}
+	})
+
+	t.Run("GetAssistantAllFields", func(t *testing.T) {
+		fields := []string{"assistant_id", "name", "type", "connector", "options", "prompts", "tags", "share", "created_at", "__yao_team_id"}
+		assistant, err := store.GetAssistant("assistant_0", fields)
+		if err != nil {
+			t.Fatalf("Failed to get assistant: %v", err)
+		}
+		if assistant.Name != "Assistant 0" || assistant.Connector != "openai" {
+			t.Errorf("Unexpected assistant: %+v", assistant)
+		}
+		if assistant.Options["temperature"] != 0.7 {
+			t.Errorf("Expected temperature 0.7, got %v", assistant.Options["temperature"])
+		}
+		if len(assistant.Prompts) != 1 || len(assistant.Tags) != 2 {
+			t.Errorf("Expected prompts and tags to be kept")
+		}
+		if assistant.Share != "private" {
+			t.Errorf("Expected default share 'private', got '%s'", assistant.Share)
+		}
+		if assistant.CreatedAt == 0 {
+			t.Error("Expected created_at to be set")
+		}
+		if assistant.YaoTeamID != "team_1" {
+			t.Errorf("Expected team permission field to be kept, got '%s'", assistant.YaoTeamID)
+		}
+	})
+
+	t.Run("GetNonExistentAssistantFails", func(t *testing.T) {
+		if _, err := store.GetAssistant("missing", nil); err == nil {
+			t.Error("Expected error for non-existent assistant")
+		}
+	})
+
+	t.Run("UpdateAssistant", func(t *testing.T) {
+		err := store.UpdateAssistant("assistant_1", map[string]interface{}{
+			"name":         "Renamed",
+			"description":  "",
+			"assistant_id": "hijacked",
+		})
+		if err != nil {
+			t.Fatalf("Failed to update assistant: %v", err)
+		}
+
+		assistant, err := store.GetAssistant("assistant_1", []string{"name", "description", "updated_at"})
+		if err != nil {
+			t.Fatalf("Failed to get assistant: %v", err)
+		}
+		if assistant.Name != "Renamed" || assistant.Description != "" {
+			t.Errorf("Unexpected update result: %+v", assistant)
+		}
+		if assistant.UpdatedAt == 0 {
+			t.Error("Expected updated_at to be set")
+		}
+	})
+
+	t.Run("GetAssistantsWithFilters", func(t *testing.T) {
+		mentionable := true
+		result, err := store.GetAssistants(types.AssistantFilter{Mentionable: &mentionable})
+		if err != nil {
+			t.Fatalf("Failed to get assistants: %v", err)
+		}
+		if result.Total != 2 {
+			t.Errorf("Expected 2 mentionable assistants, got %d", result.Total)
+		}
+
+		result, err = store.GetAssistants(types.AssistantFilter{Tags: []string{"tag_1"}, Types: []string{"robot"}})
+		if err != nil {
+			t.Fatalf("Failed to get assistants: %v", err)
+		}
+		if result.Total != 1 || result.Data[0].ID != "assistant_3" {
+			t.Errorf("Expected only assistant_3, got %d", result.Total)
+		}
+
+		result, err = store.GetAssistants(types.AssistantFilter{Keywords: "renamed"})
+		if err != nil {
+			t.Fatalf("Failed to get assistants: %v", err)
+		}
+		if result.Total != 1 {
+			t.Errorf("Expected 1 assistant matching keywords, got %d", result.Total)
+		}
+	})
+
+	t.Run("GetAssistantsPagination", func(t *testing.T) {
+		result, err := store.GetAssistants(types.AssistantFilter{Page: 1, PageSize: 3})
+		if err != nil {
+			t.Fatalf("Failed to get assistants: %v", err)
+		}
+		if len(result.Data) != 3 || result.Total != 4 || result.PageCount != 2 || result.Next != 2 || result.Prev != 0 {
+			t.Errorf("Unexpected pagination: %+v", result)
+		}
+		if result.Data[0].Sort != 0 {
+			t.Errorf("Expected sort 0 first, got %d", result.Data[0].Sort)
+		}
+	})
+
+	t.Run("GetAssistantTags", func(t *testing.T) {
+		tags, err := store.GetAssistantTags(types.AssistantFilter{Type: "assistant"})
+		if err != nil {
+			t.Fatalf("Failed to get tags: %v", err)
+		}
+		if len(tags) != 3 {
+			t.Errorf("Expected 3 unique tags, got %d", len(tags))
+		}
+	})
+
+	t.Run("DeleteAssistants", func(t *testing.T) {
+		count, err := store.DeleteAssistants(types.AssistantFilter{Connector: "claude"})
+		if err != nil {
+			t.Fatalf("Failed to delete assistants: %v", err)
+		}
+		if count != 1 {
+			t.Errorf("Expected 1 deleted assistant, got %d", count)
+		}
+
+		if err := store.DeleteAssistant("assistant_3"); err == nil {
+			t.Error("Expected error when deleting an already deleted assistant")
+		}
+	})
+}
+
+// TestAssistantPermission tests that access rules hide other users' private assistants
+func TestAssistantPermission(t *testing.T) {
+	store := newTestStore(t)
+
+	assistants := []*types.AssistantModel{
+		{ID: "perm_alice_private", Name: "Alice Private", Share: "private", YaoCreatedBy: "alice", YaoTeamID: "team_perm"},
+		{ID: "perm_alice_shared", Name: "Alice Shared", Share: "team", YaoCreatedBy: "alice", YaoTeamID: "team_perm"},
+		{ID: "perm_bob_private", Name: "Bob Private", Share: "private", YaoCreatedBy: "bob", YaoTeamID: "team_perm"},
+		{ID: "perm_public", Name: "Public", Public: true, Share: "private", YaoCreatedBy: "carol", YaoTeamID: "team_other"},
+	}
+	for _, assistant := range assistants {
+		assistant.Type = "assistant"
+		assistant.Connector = "openai"
+		if _, err := store.SaveAssistant(assistant); err != nil {
+			t.Fatalf("Failed to save assistant: %v", err)
+		}
+	}
+
+	// Team member access as built by AuthAccessRules
+	access := []types.AccessRule{
+		{Public: true},
+		{TeamID: "team_perm", CreatedBy: "bob"},
+		{TeamID: "team_perm", Share: "team"},
+	}
+
+	result, err := store.GetAssistants(types.AssistantFilter{Access: access})
+	if err != nil {
+		t.Fatalf("Failed to get assistants: %v", err)
+	}
+
+	ids := map[string]bool{}
+	for _, assistant := range result.Data {
+		ids[assistant.ID] = true
+	}
+	if ids["perm_alice_private"] {
+		t.Error("Expected alice's private assistant to be hidden from bob")
+	}
+	if result.Total != 3 || !ids["perm_alice_shared"] || !ids["perm_bob_private"] || !ids["perm_public"] {
+		t.Errorf("Expected shared, own and public assistants, got %v", ids)
+	}
+}
@@ -0,0 +1,419 @@
+package redis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/yao/agent/store/types"
+)
+
+// =============================================================================
+// Chat Management
+// =============================================================================
+
+// CreateChat creates a new chat session
+func (r *Redis) CreateChat(chat *types.Chat) error {
+	if chat == nil {
+		return fmt.Errorf("chat cannot be nil")
+	}
+
+	// Validate required fields
+	if chat.AssistantID == "" {
+		return fmt.Errorf("assistant_id is required")
+	}
+
+	// Generate chat_id if not provided
+	if chat.ChatID == "" {
+		chat.ChatID = uuid.New().String()
+	}
+
+	// Check if chat already exists
+	if r.kv.Has(r.key("chat", chat.ChatID)) {
+		return fmt.Errorf("chat %s already exists", chat.ChatID)
+	}
+
+	// Set defaults
+	if chat.Status == "" {
+		chat.Status = "active"
+	}
+	if chat.Share == "" {
+		chat.Share = "private"
+	}
+
+	now := time.Now()
+	chat.CreatedAt = now
+	chat.UpdatedAt = now
+
+	if err := r.setJSON(r.key("chat", chat.ChatID), chat, r.ttl); err != nil {
+		return err
+	}
+	return r.indexChat(chat)
+}
+
+// GetChat retrieves a single chat by ID
+func (r *Redis) GetChat(chatID string) (*types.Chat, error) {
+	if chatID == "" {
+		return nil, fmt.Errorf("chat_id is required")
+	}
+
+	var chat types.Chat
+	ok, err := r.getJSON(r.key("chat", chatID), &chat)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || chat.ChatID == "" {
+		return nil, fmt.Errorf("chat %s not found", chatID)
+	}
+
+	return &chat, nil
+}
+
+// UpdateChat updates chat fields and refreshes the chat expiry
+func (r *Redis) UpdateChat(chatID string, updates map[string]interface{}) error {
+	if chatID == "" {
+		return fmt.Errorf("chat_id is required")
+	}
+	if len(updates) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	chat, err := r.GetChat(chatID)
+	if err != nil {
+		return err
+	}
+
+	// Merge updates into the stored fields
+	data := map[string]interface{}{}
+	raw, err := jsoniter.Marshal(chat)
+	if err != nil {
+		return fmt.Errorf("failed to marshal chat: %w", err)
+	}
+	if err := jsoniter.Unmarshal(raw, &data); err != nil {
+		return fmt.Errorf("failed to unmarshal chat: %w", err)
+	}
+
+	for key, value := range updates {
+		// Skip system fields
+		if key == "chat_id" || key == "created_at" {
+			continue
+		}
+		data[key] = value
+	}
+
+	// Always update updated_at
+	data["updated_at"] = time.Now()
+
+	raw, err = jsoniter.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal chat updates: %w", err)
+	}
+
+	var updated types.Chat
+	if err := jsoniter.Unmarshal(raw, &updated); err != nil {
+		return fmt.Errorf("invalid chat updates: %w", err)
+	}
+
+	if err := r.setJSON(r.key("chat", chatID), &updated, r.ttl); err != nil {
+		return err
+	}
+
+	// Move the chat to its new owner / team indexes
+	if updated.CreatedBy != chat.CreatedBy || updated.TeamID != chat.TeamID {
+		if err := r.unindexChat(chat); err != nil {
+			return err
+		}
+		if err := r.indexChat(&updated); err != nil {
+			return err
+		}
+	}
+
+	// Keep messages alive as long as the chat
+	return r.touchMessages(chatID)
+}
+
+// DeleteChat deletes a chat and its associated messages
+func (r *Redis) DeleteChat(chatID string) error {
+	if chatID == "" {
+		return fmt.Errorf("chat_id is required")
+	}
+
+	chat, err := r.GetChat(chatID)
+	if err != nil {
+		return err
+	}
+
+	if err := r.deleteMessages(chatID); err != nil {
+		return err
+	}
+	if err := r.unindexChat(chat); err != nil {
+		return err
+	}
+	return r.kv.Del(r.key("chat", chatID))
+}
+
+// ListChats retrieves a paginated list of chats with optional grouping
+func (r *Redis) ListChats(filter types.ChatFilter) (*types.ChatList, error) {
+	// Set defaults
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = 20
+	}
+	if filter.OrderBy == "" {
+		filter.OrderBy = "last_message_at"
+	}
+	if filter.Order == "" {
+		filter.Order = "desc"
+	}
+	if filter.TimeField == "" {
+		filter.TimeField = "last_message_at"
+	}
+
+	if err := checkAccess(filter.QueryFilter != nil, filter.Access); err != nil {
+		return nil, err
+	}
+
+	// Load the candidates from the narrowest chat indexes covering the filter
+	matched := []*types.Chat{}
+	expired := map[string][]string{}
+	seen := map[string]bool{}
+	for _, index := range r.chatIndexesOf(filter) {
+		ids, err := r.listIDs(index)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			var chat types.Chat
+			ok, err := r.getJSON(r.key("chat", id), &chat)
+			if err != nil || !ok || chat.ChatID == "" {
+				expired[index] = append(expired[index], id)
+				continue
+			}
+			if matchChat(&chat, filter) {
+				matched = append(matched, &chat)
+			}
+		}
+	}
+	r.pruneIndexes(expired)
+
+	sortChats(matched, filter.OrderBy, strings.ToLower(filter.Order) != "asc")
+
+	// Calculate pagination
+	total := len(matched)
+	pageCount := int(math.Ceil(float64(total) / float64(filter.PageSize)))
+	if pageCount < 1 {
+		pageCount = 1
+	}
+	offset := (filter.Page - 1) * filter.PageSize
+	end := offset + filter.PageSize
+	if offset > total {
+		offset = total
+	}
+	if end > total {
+		end = total
+	}
+
+	result := &types.ChatList{
+		Data:      matched[offset:end],
+		Page:      filter.Page,
+		PageSize:  filter.PageSize,
+		PageCount: pageCount,
+		Total:     total,
+	}
+
+	// Apply time-based grouping if requested
+	if filter.GroupBy == "time" {
+		result.Groups = types.GroupChatsByTime(result.Data)
+	}
+
+	return result, nil
+}
+
+// =============================================================================
+// Helper Functions
+// =============================================================================
+
+// touchChat refreshes the expiry of a chat, missing chats are ignored
+func (r *Redis) touchChat(chatID string) error {
+	key := r.key("chat", chatID)
+	raw, ok := r.kv.Get(key)
+	if !ok || raw == nil {
+		return nil
+	}
+	return r.kv.Set(key, raw, r.ttl)
+}
+
+// indexChat adds a chat to its index sets
+func (r *Redis) indexChat(chat *types.Chat) error {
+	for _, index := range r.chatIndexes(chat) {
+		if err := r.addToSet(index, chat.ChatID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unindexChat removes a chat from its index sets
+func (r *Redis) unindexChat(chat *types.Chat) error {
+	for _, index := range r.chatIndexes(chat) {
+		if err := r.pullFromSet(index, chat.ChatID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// chatIndexes returns the index keys a chat is listed under
+func (r *Redis) chatIndexes(chat *types.Chat) []string {
+	indexes := []string{r.key("chats", "all")}
+	if chat.CreatedBy != "" {
+		indexes = append(indexes, r.key("chats", "user", chat.CreatedBy))
+	}
+	if chat.TeamID != "" {
+		indexes = append(indexes, r.key("chats", "team", chat.TeamID))
+	}
+	return indexes
+}
+
+// chatIndexesOf returns the index keys covering every chat the filter can match
+func (r *Redis) chatIndexesOf(filter types.ChatFilter) []string {
+	all := []string{r.key("chats", "all")}
+	if filter.UserID != "" {
+		return []string{r.key("chats", "user", filter.UserID)}
+	}
+	if filter.TeamID != "" {
+		return []string{r.key("chats", "team", filter.TeamID)}
+	}
+	if len(filter.Access) == 0 {
+		return all
+	}
+
+	// Every access rule must be covered by a user or team index
+	indexes := []string{}
+	for _, rule := range filter.Access {
+		switch {
+		case rule.CreatedBy != "":
+			indexes = append(indexes, r.key("chats", "user", rule.CreatedBy))
+		case rule.TeamID != "":
+			indexes = append(indexes, r.key("chats", "team", rule.TeamID))
+		default:
+			return all
+		}
+	}
+	return indexes
+}
+
+// pruneIndexes removes expired chats from the index sets and drops their message lists
+// Failures are retried on the next listing
+func (r *Redis) pruneIndexes(expired map[string][]string) {
+	for index, ids := range expired {
+		// Skip chats re-created since they were found missing
+		stale := []string{}
+		for _, id := range ids {
+			if !r.kv.Has(r.key("chat", id)) {
+				stale = append(stale, id)
+			}
+		}
+		if len(stale) == 0 {
+			continue
+		}
+		r.pullFromSet(index, stale...)
+		for _, id := range stale {
+			r.deleteMessages(id)
+		}
+	}
+}
+
+// matchChat evaluates the chat filter conditions, permissions are checked with the Access rules
+func matchChat(chat *types.Chat, filter types.ChatFilter) bool {
+	if !types.MatchAccess(filter.Access, chat.Public, chat.CreatedBy, chat.TeamID, chat.Share) {
+		return false
+	}
+	if filter.UserID != "" && chat.CreatedBy != filter.UserID {
+		return false
+	}
+	if filter.TeamID != "" && chat.TeamID != filter.TeamID {
+		return false
+	}
+	if filter.AssistantID != "" && chat.AssistantID != filter.AssistantID {
+		return false
+	}
+	if filter.Status != "" && chat.Status != filter.Status {
+		return false
+	}
+	if filter.Keywords != "" && !containsFold(chat.Title, filter.Keywords) {
+		return false
+	}
+	if filter.ChatIDPrefix != "" && !strings.HasPrefix(chat.ChatID, filter.ChatIDPrefix) {
+		return false
+	}
+
+	// Time range filter; like SQL, a missing time never matches a range
+	if filter.StartTime != nil || filter.EndTime != nil {
+		t := chatTime(chat, filter.TimeField)
+		if t == nil {
+			return false
+		}
+		if filter.StartTime != nil && t.Before(*filter.StartTime) {
+			return false
+		}
+		if filter.EndTime != nil && t.After(*filter.EndTime) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// chatTime returns the time value of a chat field
+func chatTime(chat *types.Chat, field string) *time.Time {
+	switch field {
+	case "created_at":
+		return &chat.CreatedAt
+	case "updated_at":
+		return &chat.UpdatedAt
+	default:
+		return chat.LastMessageAt
+	}
+}
+
+// sortChats sorts chats by the given field, missing times sort last
+func sortChats(chats []*types.Chat, orderBy string, desc bool) {
+	sort.SliceStable(chats, func(i, j int) bool {
+		a, b := chats[i], chats[j]
+		switch orderBy {
+		case "sort":
+			if desc {
+				return a.Sort > b.Sort
+			}
+			return a.Sort < b.Sort
+
+		case "title":
+			if desc {
+				return a.Title > b.Title
+			}
+			return a.Title < b.Title
+
+		default:
+			ta, tb := chatTime(a, orderBy), chatTime(b, orderBy)
+			if ta == nil || tb == nil {
+				return ta != nil
+			}
+			if desc {
+				return ta.After(*tb)
+			}
+			return ta.Before(*tb)
+		}
+	})
+}
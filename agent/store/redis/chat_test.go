@@ -0,0 +1,436 @@
+package redis_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/yaoapp/gou/store/lru"
+	"github.com/yaoapp/xun/dbal/query"
+	"github.com/yaoapp/yao/agent/store/redis"
+	"github.com/yaoapp/yao/agent/store/types"
+)
+
+// newTestStore creates a redis store backed by an in-memory LRU stand-in
+func newTestStore(t *testing.T) types.Store {
+	kv, err := lru.New(10240)
+	if err != nil {
+		t.Fatalf("Failed to create in-memory store: %v", err)
+	}
+	return redis.New(kv, types.Setting{TTL: 3600})
+}
+
+// TestCreateChat tests creating chat sessions
+func TestCreateChat(t *testing.T) {
+	store := newTestStore(t)
+
+	t.Run("CreateNewChat", func(t *testing.T) {
+		chat := &types.Chat{
+			AssistantID: "test_assistant",
+			Title:       "Test Chat",
+		}
+
+		if err := store.CreateChat(chat); err != nil {
+			t.Fatalf("Failed to create chat: %v", err)
+		}
+		if chat.ChatID == "" {
+			t.Error("Expected chat_id to be generated")
+		}
+		if chat.Status != "active" {
+			t.Errorf("Expected default status 'active', got '%s'", chat.Status)
+		}
+		if chat.Share != "private" {
+			t.Errorf("Expected default share 'private', got '%s'", chat.Share)
+		}
+	})
+
+	t.Run("CreateChatWithAllFields", func(t *testing.T) {
+		now := time.Now()
+		chat := &types.Chat{
+			AssistantID:   "test_assistant",
+			LastConnector: "openai",
+			Title:         "Full Chat",
+			LastMode:      "task",
+			Public:        true,
+			Share:         "team",
+			Sort:          100,
+			LastMessageAt: &now,
+			Metadata:      map[string]interface{}{"source": "test"},
+			CreatedBy:     "user1",
+			TeamID:        "team1",
+		}
+
+		if err := store.CreateChat(chat); err != nil {
+			t.Fatalf("Failed to create chat: %v", err)
+		}
+
+		retrieved, err := store.GetChat(chat.ChatID)
+		if err != nil {
+			t.Fatalf("Failed to retrieve chat: %v", err)
+		}
+		if retrieved.Title != "Full Chat" {
+			t.Errorf("Expected title 'Full Chat', got '%s'", retrieved.Title)
+		}
+		if retrieved.LastConnector != "openai" {
+			t.Errorf("Expected last_connector 'openai', got '%s'", retrieved.LastConnector)
+		}
+		if retrieved.LastMode != "task" {
+			t.Errorf("Expected last_mode 'task', got '%s'", retrieved.LastMode)
+		}
+		if !retrieved.Public || retrieved.Share != "team" || retrieved.Sort != 100 {
+			t.Errorf("Unexpected public/share/sort: %v/%s/%d", retrieved.Public, retrieved.Share, retrieved.Sort)
+		}
+		if retrieved.Metadata["source"] != "test" {
+			t.Errorf("Expected metadata source 'test', got %v", retrieved.Metadata["source"])
+		}
+		if retrieved.CreatedBy != "user1" || retrieved.TeamID != "team1" {
+			t.Errorf("Expected permission fields to be kept, got %s/%s", retrieved.CreatedBy, retrieved.TeamID)
+		}
+	})
+
+	t.Run("CreateDuplicateChatFails", func(t *testing.T) {
+		chat := &types.Chat{AssistantID: "test_assistant"}
+		if err := store.CreateChat(chat); err != nil {
+			t.Fatalf("Failed to create first chat: %v", err)
+		}
+
+		duplicate := &types.Chat{ChatID: chat.ChatID, AssistantID: "test_assistant"}
+		if err := store.CreateChat(duplicate); err == nil {
+			t.Error("Expected error when creating duplicate chat")
+		}
+	})
+
+	t.Run("CreateChatWithoutAssistantIDFails", func(t *testing.T) {
+		if err := store.CreateChat(&types.Chat{Title: "No Assistant"}); err == nil {
+			t.Error("Expected error when assistant_id is missing")
+		}
+	})
+
+	t.Run("CreateNilChatFails", func(t *testing.T) {
+		if err := store.CreateChat(nil); err == nil {
+			t.Error("Expected error when chat is nil")
+		}
+	})
+}
+
+// TestUpdateChat tests updating chat fields
+func TestUpdateChat(t *testing.T) {
+	store := newTestStore(t)
+
+	chat := &types.Chat{AssistantID: "test_assistant", Title: "Original"}
+	if err := store.CreateChat(chat); err != nil {
+		t.Fatalf("Failed to create chat: %v", err)
+	}
+
+	t.Run("UpdateMultipleFields", func(t *testing.T) {
+		now := time.Now()
+		err := store.UpdateChat(chat.ChatID, map[string]interface{}{
+			"title":           "Updated",
+			"status":          "archived",
+			"last_connector":  "claude",
+			"last_message_at": now,
+			"metadata":        map[string]interface{}{"key": "value"},
+		})
+		if err != nil {
+			t.Fatalf("Failed to update chat: %v", err)
+		}
+
+		retrieved, err := store.GetChat(chat.ChatID)
+		if err != nil {
+			t.Fatalf("Failed to retrieve chat: %v", err)
+		}
+		if retrieved.Title != "Updated" || retrieved.Status != "archived" || retrieved.LastConnector != "claude" {
+			t.Errorf("Fields not updated: %+v", retrieved)
+		}
+		if retrieved.LastMessageAt == nil || !retrieved.LastMessageAt.Equal(now) {
+			t.Errorf("Expected last_message_at %v, got %v", now, retrieved.LastMessageAt)
+		}
+		if retrieved.Metadata["key"] != "value" {
+			t.Errorf("Expected metadata key 'value', got %v", retrieved.Metadata["key"])
+		}
+	})
+
+	t.Run("UpdateSkipsSystemFields", func(t *testing.T) {
+		err := store.UpdateChat(chat.ChatID, map[string]interface{}{
+			"chat_id": "hijacked",
+			"title":   "Still Mine",
+		})
+		if err != nil {
+			t.Fatalf("Failed to update chat: %v", err)
+		}
+		if _, err := store.GetChat("hijacked"); err == nil {
+			t.Error("chat_id should not be updatable")
+		}
+	})
+
+	t.Run("UpdateNonExistentChatFails", func(t *testing.T) {
+		if err := store.UpdateChat("missing", map[string]interface{}{"title": "x"}); err == nil {
+			t.Error("Expected error when updating non-existent chat")
+		}
+	})
+
+	t.Run("UpdateWithEmptyFieldsFails", func(t *testing.T) {
+		if err := store.UpdateChat(chat.ChatID, map[string]interface{}{}); err == nil {
+			t.Error("Expected error when updates are empty")
+		}
+	})
+}
+
+// TestDeleteChat tests deleting chats with their messages
+func TestDeleteChat(t *testing.T) {
+	store := newTestStore(t)
+
+	chat := &types.Chat{AssistantID: "test_assistant"}
+	if err := store.CreateChat(chat); err != nil {
+		t.Fatalf("Failed to create chat: %v", err)
+	}
+	err := store.SaveMessages(chat.ChatID, []*types.Message{
+		{MessageID: "del_msg_1", Role: "user", Type: "text", Props: map[string]interface{}{"content": "hi"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save messages: %v", err)
+	}
+
+	t.Run("DeleteExistingChat", func(t *testing.T) {
+		if err := store.DeleteChat(chat.ChatID); err != nil {
+			t.Fatalf("Failed to delete chat: %v", err)
+		}
+		if _, err := store.GetChat(chat.ChatID); err == nil {
+			t.Error("Expected deleted chat to be gone")
+		}
+
+		messages, err := store.GetMessages(chat.ChatID, types.MessageFilter{})
+		if err != nil {
+			t.Fatalf("Failed to get messages: %v", err)
+		}
+		if len(messages) != 0 {
+			t.Errorf("Expected messages to be deleted with the chat, got %d", len(messages))
+		}
+
+		if err := store.UpdateMessage("del_msg_1", map[string]interface{}{"type": "error"}); err == nil {
+			t.Error("Expected message index to be deleted with the chat")
+		}
+	})
+
+	t.Run("DeleteAlreadyDeletedChatFails", func(t *testing.T) {
+		if err := store.DeleteChat(chat.ChatID); err == nil {
+			t.Error("Expected error when deleting an already deleted chat")
+		}
+	})
+
+	t.Run("DeleteWithEmptyIDFails", func(t *testing.T) {
+		if err := store.DeleteChat(""); err == nil {
+			t.Error("Expected error when chat_id is empty")
+		}
+	})
+}
+
+// TestListChats tests filtering, sorting, pagination and grouping
+func TestListChats(t *testing.T) {
+	store := newTestStore(t)
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		lastMessageAt := now.Add(-time.Duration(i) * time.Hour)
+		status := "active"
+		if i%2 == 1 {
+			status = "archived"
+		}
+		chat := &types.Chat{
+			ChatID:        fmt.Sprintf("list_chat_%d", i),
+			AssistantID:   fmt.Sprintf("assistant_%d", i%2),
+			Title:         fmt.Sprintf("Chat Number %d", i),
+			Status:        status,
+			Sort:          i,
+			LastMessageAt: &lastMessageAt,
+			CreatedBy:     fmt.Sprintf("user_%d", i%2),
+			TeamID:        "team_list",
+		}
+		if err := store.CreateChat(chat); err != nil {
+			t.Fatalf("Failed to create chat: %v", err)
+		}
+	}
+
+	// A chat without last_message_at sorts last and is excluded by time ranges
+	if err := store.CreateChat(&types.Chat{ChatID: "list_chat_empty", AssistantID: "assistant_0", Title: "Empty"}); err != nil {
+		t.Fatalf("Failed to create chat: %v", err)
+	}
+
+	t.Run("ListAllChats", func(t *testing.T) {
+		result, err := store.ListChats(types.ChatFilter{})
+		if err != nil {
+			t.Fatalf("Failed to list chats: %v", err)
+		}
+		if result.Total != 6 {
+			t.Errorf("Expected 6 chats, got %d", result.Total)
+		}
+		if result.Data[0].ChatID != "list_chat_0" {
+			t.Errorf("Expected most recent chat first, got %s", result.Data[0].ChatID)
+		}
+		if result.Data[len(result.Data)-1].ChatID != "list_chat_empty" {
+			t.Errorf("Expected chat without messages last, got %s", result.Data[len(result.Data)-1].ChatID)
+		}
+	})
+
+	t.Run("ListChatsByStatusAndAssistant", func(t *testing.T) {
+		result, err := store.ListChats(types.ChatFilter{Status: "archived", AssistantID: "assistant_1"})
+		if err != nil {
+			t.Fatalf("Failed to list chats: %v", err)
+		}
+		if result.Total != 2 {
+			t.Errorf("Expected 2 archived chats, got %d", result.Total)
+		}
+	})
+
+	t.Run("ListChatsByKeywords", func(t *testing.T) {
+		result, err := store.ListChats(types.ChatFilter{Keywords: "number 3"})
+		if err != nil {
+			t.Fatalf("Failed to list chats: %v", err)
+		}
+		if result.Total != 1 || result.Data[0].ChatID != "list_chat_3" {
+			t.Errorf("Expected only list_chat_3, got %d chats", result.Total)
+		}
+	})
+
+	t.Run("ListChatsByUserAndTeam", func(t *testing.T) {
+		result, err := store.ListChats(types.ChatFilter{UserID: "user_0", TeamID: "team_list"})
+		if err != nil {
+			t.Fatalf("Failed to list chats: %v", err)
+		}
+		if result.Total != 3 {
+			t.Errorf("Expected 3 chats for user_0, got %d", result.Total)
+		}
+	})
+
+	t.Run("ListChatsPagination", func(t *testing.T) {
+		result, err := store.ListChats(types.ChatFilter{Page: 2, PageSize: 4})
+		if err != nil {
+			t.Fatalf("Failed to list chats: %v", err)
+		}
+		if len(result.Data) != 2 || result.PageCount != 2 || result.Page != 2 {
+			t.Errorf("Unexpected page: len=%d pagecount=%d page=%d", len(result.Data), result.PageCount, result.Page)
+		}
+
+		beyond, err := store.ListChats(types.ChatFilter{Page: 10, PageSize: 4})
+		if err != nil {
+			t.Fatalf("Failed to list chats: %v", err)
+		}
+		if len(beyond.Data) != 0 {
+			t.Errorf("Expected empty page beyond the end, got %d", len(beyond.Data))
+		}
+	})
+
+	t.Run("ListChatsWithTimeRange", func(t *testing.T) {
+		start := now.Add(-150 * time.Minute)
+		result, err := store.ListChats(types.ChatFilter{StartTime: &start})
+		if err != nil {
+			t.Fatalf("Failed to list chats: %v", err)
+		}
+		if result.Total != 3 {
+			t.Errorf("Expected 3 chats in range, got %d", result.Total)
+		}
+	})
+
+	t.Run("ListChatsWithSorting", func(t *testing.T) {
+		result, err := store.ListChats(types.ChatFilter{OrderBy: "sort", Order: "asc", ChatIDPrefix: "list_chat_"})
+		if err != nil {
+			t.Fatalf("Failed to list chats: %v", err)
+		}
+		if result.Data[0].Sort != 0 {
+			t.Errorf("Expected sort 0 first, got %d", result.Data[0].Sort)
+		}
+	})
+
+	t.Run("ListChatsWithGrouping", func(t *testing.T) {
+		result, err := store.ListChats(types.ChatFilter{GroupBy: "time"})
+		if err != nil {
+			t.Fatalf("Failed to list chats: %v", err)
+		}
+		if len(result.Groups) == 0 {
+			t.Fatal("Expected time groups")
+		}
+		count := 0
+		for _, group := range result.Groups {
+			count += group.Count
+		}
+		if count != len(result.Data) {
+			t.Errorf("Expected grouped count %d, got %d", len(result.Data), count)
+		}
+	})
+}
+
+// TestListChatsPermission tests that access rules keep other users' private chats out of listings
+func TestListChatsPermission(t *testing.T) {
+	store := newTestStore(t)
+
+	chats := []*types.Chat{
+		{ChatID: "perm_alice_private", AssistantID: "a", CreatedBy: "alice", TeamID: "team_perm", Share: "private"},
+		{ChatID: "perm_alice_shared", AssistantID: "a", CreatedBy: "alice", TeamID: "team_perm", Share: "team"},
+		{ChatID: "perm_bob_private", AssistantID: "a", CreatedBy: "bob", TeamID: "team_perm", Share: "private"},
+		{ChatID: "perm_carol_other", AssistantID: "a", CreatedBy: "carol", TeamID: "team_other", Share: "team"},
+	}
+	for _, chat := range chats {
+		if err := store.CreateChat(chat); err != nil {
+			t.Fatalf("Failed to create chat: %v", err)
+		}
+	}
+
+	// Team member access as built by the chat session API: own chats OR team shared chats
+	bobAccess := []types.AccessRule{
+		{CreatedBy: "bob"},
+		{TeamID: "team_perm", Share: "team"},
+	}
+
+	t.Run("TeamMemberCannotListOthersPrivateChats", func(t *testing.T) {
+		result, err := store.ListChats(types.ChatFilter{Access: bobAccess})
+		if err != nil {
+			t.Fatalf("Failed to list chats: %v", err)
+		}
+
+		ids := map[string]bool{}
+		for _, chat := range result.Data {
+			ids[chat.ChatID] = true
+		}
+		if ids["perm_alice_private"] {
+			t.Error("Expected alice's private chat to be hidden from bob")
+		}
+		if ids["perm_carol_other"] {
+			t.Error("Expected another team's chat to be hidden from bob")
+		}
+		if !ids["perm_bob_private"] || !ids["perm_alice_shared"] || result.Total != 2 {
+			t.Errorf("Expected bob's own and the team shared chat, got %v", ids)
+		}
+	})
+
+	t.Run("QueryFilterWithoutAccessFails", func(t *testing.T) {
+		_, err := store.ListChats(types.ChatFilter{QueryFilter: func(query.Query) {}})
+		if err == nil {
+			t.Error("Expected error when permissions are given only as QueryFilter")
+		}
+	})
+
+	t.Run("DeletedAndReassignedChatsLeaveIndexes", func(t *testing.T) {
+		if err := store.UpdateChat("perm_bob_private", map[string]interface{}{"__yao_created_by": "alice"}); err != nil {
+			t.Fatalf("Failed to update chat: %v", err)
+		}
+		if err := store.DeleteChat("perm_alice_shared"); err != nil {
+			t.Fatalf("Failed to delete chat: %v", err)
+		}
+
+		result, err := store.ListChats(types.ChatFilter{Access: bobAccess})
+		if err != nil {
+			t.Fatalf("Failed to list chats: %v", err)
+		}
+		if result.Total != 0 {
+			t.Errorf("Expected no chats for bob, got %d", result.Total)
+		}
+
+		result, err = store.ListChats(types.ChatFilter{UserID: "alice"})
+		if err != nil {
+			t.Fatalf("Failed to list chats: %v", err)
+		}
+		if result.Total != 2 {
+			t.Errorf("Expected 2 chats for alice, got %d", result.Total)
+		}
+	})
+}
@@ -0,0 +1,279 @@
+package redis
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/yao/agent/store/types"
+)
+
+// =============================================================================
+// Message Management
+// =============================================================================
+
+// SaveMessages batch saves messages for a chat
+// Each message is stored under its own key, their IDs are appended to the chat message list in a single command
+func (r *Redis) SaveMessages(chatID string, messages []*types.Message) error {
+	if chatID == "" {
+		return fmt.Errorf("chat_id is required")
+	}
+	if len(messages) == 0 {
+		return nil // Nothing to save
+	}
+
+	// Validate and prepare all messages before touching the store
+	now := time.Now()
+	rows := make([]*types.Message, 0, len(messages))
+	for _, msg := range messages {
+		if msg == nil {
+			continue
+		}
+
+		// Validate required fields
+		if msg.Role == "" {
+			return fmt.Errorf("message role is required")
+		}
+		if msg.Type == "" {
+			return fmt.Errorf("message type is required")
+		}
+		if msg.Props == nil {
+			return fmt.Errorf("message props is required")
+		}
+
+		row := *msg
+		if row.MessageID == "" {
+			row.MessageID = uuid.New().String()
+		}
+		row.ChatID = chatID
+		row.CreatedAt = now
+		row.UpdatedAt = now
+		rows = append(rows, &row)
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	ids := make([]interface{}, 0, len(rows))
+	for _, row := range rows {
+		if err := r.setJSON(r.key("message", row.MessageID), row, r.ttl); err != nil {
+			return err
+		}
+		ids = append(ids, row.MessageID)
+	}
+
+	if err := r.kv.Push(r.key("messages", chatID), ids...); err != nil {
+		return fmt.Errorf("failed to append messages of chat %s: %w", chatID, err)
+	}
+
+	// New messages keep the chat alive
+	return r.touchChat(chatID)
+}
+
+// GetMessages retrieves messages for a chat with filtering
+func (r *Redis) GetMessages(chatID string, filter types.MessageFilter) ([]*types.Message, error) {
+	if chatID == "" {
+		return nil, fmt.Errorf("chat_id is required")
+	}
+
+	all, err := r.loadMessages(chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*types.Message, 0, len(all))
+	for _, msg := range all {
+		if filter.RequestID != "" && msg.RequestID != filter.RequestID {
+			continue
+		}
+		if filter.Role != "" && msg.Role != filter.Role {
+			continue
+		}
+		if filter.BlockID != "" && msg.BlockID != filter.BlockID {
+			continue
+		}
+		if filter.ThreadID != "" && msg.ThreadID != filter.ThreadID {
+			continue
+		}
+		if filter.Type != "" && msg.Type != filter.Type {
+			continue
+		}
+		messages = append(messages, msg)
+	}
+
+	// Limit without Offset returns the N most-recent messages in chronological
+	// order; Limit with Offset is forward pagination (same as the xun store).
+	switch {
+	case filter.Limit > 0 && filter.Offset <= 0:
+		if len(messages) > filter.Limit {
+			messages = messages[len(messages)-filter.Limit:]
+		}
+
+	case filter.Offset > 0:
+		if filter.Offset >= len(messages) {
+			return []*types.Message{}, nil
+		}
+		messages = messages[filter.Offset:]
+		if filter.Limit > 0 && len(messages) > filter.Limit {
+			messages = messages[:filter.Limit]
+		}
+	}
+
+	return messages, nil
+}
+
+// UpdateMessage updates a single message
+func (r *Redis) UpdateMessage(messageID string, updates map[string]interface{}) error {
+	if messageID == "" {
+		return fmt.Errorf("message_id is required")
+	}
+	if len(updates) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	var msg types.Message
+	ok, err := r.getJSON(r.key("message", messageID), &msg)
+	if err != nil {
+		return err
+	}
+	if !ok || msg.MessageID == "" {
+		return fmt.Errorf("message %s not found", messageID)
+	}
+
+	updated, err := mergeMessage(&msg, updates)
+	if err != nil {
+		return err
+	}
+	return r.setJSON(r.key("message", messageID), updated, r.ttl)
+}
+
+// DeleteMessages deletes specific messages from a chat
+func (r *Redis) DeleteMessages(chatID string, messageIDs []string) error {
+	if chatID == "" {
+		return fmt.Errorf("chat_id is required")
+	}
+	if len(messageIDs) == 0 {
+		return nil // Nothing to delete
+	}
+
+	// Only the messages of this chat are deleted
+	ids, err := r.listIDs(r.key("messages", chatID))
+	if err != nil {
+		return err
+	}
+	remove := []string{}
+	for _, id := range ids {
+		if inStrings(messageIDs, id) {
+			remove = append(remove, id)
+		}
+	}
+	if len(remove) == 0 {
+		return nil
+	}
+
+	if err := r.pullFromSet(r.key("messages", chatID), remove...); err != nil {
+		return err
+	}
+	for _, id := range remove {
+		r.kv.Del(r.key("message", id))
+	}
+	return nil
+}
+
+// =============================================================================
+// Helper Functions
+// =============================================================================
+
+// loadMessages loads the messages of a chat in insertion order
+// IDs of expired messages are removed from the list
+func (r *Redis) loadMessages(chatID string) ([]*types.Message, error) {
+	ids, err := r.listIDs(r.key("messages", chatID))
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*types.Message, 0, len(ids))
+	expired := []string{}
+	for _, id := range ids {
+		var msg types.Message
+		ok, err := r.getJSON(r.key("message", id), &msg)
+		if err != nil {
+			return nil, err
+		}
+		if !ok || msg.MessageID == "" {
+			expired = append(expired, id)
+			continue
+		}
+		messages = append(messages, &msg)
+	}
+
+	if len(expired) > 0 {
+		r.pullFromSet(r.key("messages", chatID), expired...)
+	}
+	return messages, nil
+}
+
+// touchMessages refreshes the expiry of the messages of a chat
+func (r *Redis) touchMessages(chatID string) error {
+	ids, err := r.listIDs(r.key("messages", chatID))
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		key := r.key("message", id)
+		raw, ok := r.kv.Get(key)
+		if !ok || raw == nil {
+			continue
+		}
+		if err := r.kv.Set(key, raw, r.ttl); err != nil {
+			return fmt.Errorf("failed to refresh message %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// deleteMessages deletes every message of a chat and its message list
+func (r *Redis) deleteMessages(chatID string) error {
+	ids, err := r.listIDs(r.key("messages", chatID))
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		r.kv.Del(r.key("message", id))
+	}
+	r.kv.Del(r.key("messages", chatID))
+	return nil
+}
+
+// mergeMessage applies field updates to a message, system fields are skipped
+func mergeMessage(msg *types.Message, updates map[string]interface{}) (*types.Message, error) {
+	data := map[string]interface{}{}
+	raw, err := jsoniter.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal message: %w", err)
+	}
+	if err := jsoniter.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal message: %w", err)
+	}
+
+	for key, value := range updates {
+		if key == "message_id" || key == "chat_id" || key == "created_at" {
+			continue
+		}
+		data[key] = value
+	}
+	data["updated_at"] = time.Now()
+
+	raw, err = jsoniter.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal message updates: %w", err)
+	}
+
+	var updated types.Message
+	if err := jsoniter.Unmarshal(raw, &updated); err != nil {
+		return nil, fmt.Errorf("invalid message updates: %w", err)
+	}
+	return &updated, nil
+}
@@ -0,0 +1,239 @@
+package redis_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/yaoapp/gou/store/lru"
+	"github.com/yaoapp/yao/agent/store/redis"
+	"github.com/yaoapp/yao/agent/store/types"
+)
+
+// TestSaveAndGetMessages tests message persistence and filters
+func TestSaveAndGetMessages(t *testing.T) {
+	store := newTestStore(t)
+
+	chat := &types.Chat{AssistantID: "test_assistant"}
+	if err := store.CreateChat(chat); err != nil {
+		t.Fatalf("Failed to create chat: %v", err)
+	}
+
+	messages := []*types.Message{}
+	for i := 0; i < 6; i++ {
+		role := "user"
+		if i%2 == 1 {
+			role = "assistant"
+		}
+		messages = append(messages, &types.Message{
+			MessageID: fmt.Sprintf("msg_%d", i),
+			RequestID: fmt.Sprintf("req_%d", i/2),
+			Role:      role,
+			Type:      "text",
+			Props:     map[string]interface{}{"content": fmt.Sprintf("message %d", i)},
+			BlockID:   fmt.Sprintf("block_%d", i/3),
+			ThreadID:  "thread_1",
+			Sequence:  i,
+		})
+	}
+
+	if err := store.SaveMessages(chat.ChatID, messages[:3]); err != nil {
+		t.Fatalf("Failed to save messages: %v", err)
+	}
+	if err := store.SaveMessages(chat.ChatID, messages[3:]); err != nil {
+		t.Fatalf("Failed to save messages: %v", err)
+	}
+
+	t.Run("GetAllMessagesInOrder", func(t *testing.T) {
+		result, err := store.GetMessages(chat.ChatID, types.MessageFilter{})
+		if err != nil {
+			t.Fatalf("Failed to get messages: %v", err)
+		}
+		if len(result) != 6 {
+			t.Fatalf("Expected 6 messages, got %d", len(result))
+		}
+		for i, msg := range result {
+			if msg.MessageID != fmt.Sprintf("msg_%d", i) {
+				t.Errorf("Expected msg_%d at position %d, got %s", i, i, msg.MessageID)
+			}
+			if msg.ChatID != chat.ChatID {
+				t.Errorf("Expected chat_id %s, got %s", chat.ChatID, msg.ChatID)
+			}
+		}
+	})
+
+	t.Run("FilterByRoleBlockAndRequest", func(t *testing.T) {
+		result, err := store.GetMessages(chat.ChatID, types.MessageFilter{Role: "assistant"})
+		if err != nil {
+			t.Fatalf("Failed to get messages: %v", err)
+		}
+		if len(result) != 3 {
+			t.Errorf("Expected 3 assistant messages, got %d", len(result))
+		}
+
+		result, err = store.GetMessages(chat.ChatID, types.MessageFilter{BlockID: "block_1", ThreadID: "thread_1"})
+		if err != nil {
+			t.Fatalf("Failed to get messages: %v", err)
+		}
+		if len(result) != 3 {
+			t.Errorf("Expected 3 messages in block_1, got %d", len(result))
+		}
+
+		result, err = store.GetMessages(chat.ChatID, types.MessageFilter{RequestID: "req_2"})
+		if err != nil {
+			t.Fatalf("Failed to get messages: %v", err)
+		}
+		if len(result) != 2 {
+			t.Errorf("Expected 2 messages for req_2, got %d", len(result))
+		}
+	})
+
+	t.Run("LimitReturnsMostRecent", func(t *testing.T) {
+		result, err := store.GetMessages(chat.ChatID, types.MessageFilter{Limit: 2})
+		if err != nil {
+			t.Fatalf("Failed to get messages: %v", err)
+		}
+		if len(result) != 2 || result[0].MessageID != "msg_4" || result[1].MessageID != "msg_5" {
+			t.Errorf("Expected [msg_4 msg_5], got %d messages", len(result))
+		}
+	})
+
+	t.Run("LimitWithOffsetPaginatesForward", func(t *testing.T) {
+		result, err := store.GetMessages(chat.ChatID, types.MessageFilter{Limit: 2, Offset: 1})
+		if err != nil {
+			t.Fatalf("Failed to get messages: %v", err)
+		}
+		if len(result) != 2 || result[0].MessageID != "msg_1" || result[1].MessageID != "msg_2" {
+			t.Errorf("Expected [msg_1 msg_2], got %d messages", len(result))
+		}
+	})
+
+	t.Run("SaveInvalidMessageFails", func(t *testing.T) {
+		err := store.SaveMessages(chat.ChatID, []*types.Message{{Role: "user", Type: "text"}})
+		if err == nil {
+			t.Error("Expected error when props is missing")
+		}
+	})
+}
+
+// TestUpdateAndDeleteMessages tests message updates and deletion
+func TestUpdateAndDeleteMessages(t *testing.T) {
+	store := newTestStore(t)
+
+	chat := &types.Chat{AssistantID: "test_assistant"}
+	if err := store.CreateChat(chat); err != nil {
+		t.Fatalf("Failed to create chat: %v", err)
+	}
+
+	err := store.SaveMessages(chat.ChatID, []*types.Message{
+		{MessageID: "upd_1", Role: "assistant", Type: "loading", Props: map[string]interface{}{"message": "..."}},
+		{MessageID: "upd_2", Role: "assistant", Type: "text", Props: map[string]interface{}{"content": "hello"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save messages: %v", err)
+	}
+
+	t.Run("UpdateMessage", func(t *testing.T) {
+		err := store.UpdateMessage("upd_1", map[string]interface{}{
+			"type":  "text",
+			"props": map[string]interface{}{"content": "done"},
+		})
+		if err != nil {
+			t.Fatalf("Failed to update message: %v", err)
+		}
+
+		result, err := store.GetMessages(chat.ChatID, types.MessageFilter{})
+		if err != nil {
+			t.Fatalf("Failed to get messages: %v", err)
+		}
+		if result[0].Type != "text" || result[0].Props["content"] != "done" {
+			t.Errorf("Message not updated: %+v", result[0])
+		}
+	})
+
+	t.Run("UpdateNonExistentMessageFails", func(t *testing.T) {
+		if err := store.UpdateMessage("missing", map[string]interface{}{"type": "text"}); err == nil {
+			t.Error("Expected error when updating non-existent message")
+		}
+	})
+
+	t.Run("DeleteMessages", func(t *testing.T) {
+		if err := store.DeleteMessages(chat.ChatID, []string{"upd_1"}); err != nil {
+			t.Fatalf("Failed to delete messages: %v", err)
+		}
+
+		result, err := store.GetMessages(chat.ChatID, types.MessageFilter{})
+		if err != nil {
+			t.Fatalf("Failed to get messages: %v", err)
+		}
+		if len(result) != 1 || result[0].MessageID != "upd_2" {
+			t.Errorf("Expected only upd_2 to remain, got %d messages", len(result))
+		}
+	})
+}
+
+// TestSharedBackend tests that instances sharing a backend do not lose each other's writes
+func TestSharedBackend(t *testing.T) {
+	kv, err := lru.New(10240)
+	if err != nil {
+		t.Fatalf("Failed to create in-memory store: %v", err)
+	}
+	instances := []types.Store{
+		redis.New(kv, types.Setting{TTL: 3600}),
+		redis.New(kv, types.Setting{TTL: 3600}),
+	}
+
+	chat := &types.Chat{AssistantID: "test_assistant", CreatedBy: "shared_user"}
+	if err := instances[0].CreateChat(chat); err != nil {
+		t.Fatalf("Failed to create chat: %v", err)
+	}
+
+	// Both instances append messages and create chats at the same time
+	var wg sync.WaitGroup
+	for i, store := range instances {
+		for j := 0; j < 20; j++ {
+			wg.Add(1)
+			go func(store types.Store, n int) {
+				defer wg.Done()
+				store.SaveMessages(chat.ChatID, []*types.Message{{
+					MessageID: fmt.Sprintf("shared_msg_%d", n),
+					Role:      "user",
+					Type:      "text",
+					Props:     map[string]interface{}{"content": "hello"},
+				}})
+				store.CreateChat(&types.Chat{AssistantID: "test_assistant", CreatedBy: "shared_user"})
+			}(store, i*20+j)
+		}
+	}
+	wg.Wait()
+
+	messages, err := instances[1].GetMessages(chat.ChatID, types.MessageFilter{})
+	if err != nil {
+		t.Fatalf("Failed to get messages: %v", err)
+	}
+	if len(messages) != 40 {
+		t.Errorf("Expected 40 messages, got %d", len(messages))
+	}
+
+	chats, err := instances[1].ListChats(types.ChatFilter{UserID: "shared_user", PageSize: 100})
+	if err != nil {
+		t.Fatalf("Failed to list chats: %v", err)
+	}
+	if chats.Total != 41 {
+		t.Errorf("Expected 41 chats, got %d", chats.Total)
+	}
+
+	// A message updated on one instance is seen by the other
+	if err := instances[0].UpdateMessage("shared_msg_0", map[string]interface{}{"props": map[string]interface{}{"content": "edited"}}); err != nil {
+		t.Fatalf("Failed to update message: %v", err)
+	}
+	messages, err = instances[1].GetMessages(chat.ChatID, types.MessageFilter{})
+	if err != nil {
+		t.Fatalf("Failed to get messages: %v", err)
+	}
+	for _, msg := range messages {
+		if msg.MessageID == "shared_msg_0" && msg.Props["content"] != "edited" {
+			t.Errorf("Expected the edited message, got %v", msg.Props["content"])
+		}
+	}
+}
@@ -1,187 +1,240 @@
 package redis
 
-import "github.com/yaoapp/yao/agent/store/types"
-
-// Redis represents a Redis-based conversation storage
-type Redis struct{}
-
-// NewRedis create a new redis store
-func NewRedis() types.Store {
-	return &Redis{}
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/gou/connector"
+	"github.com/yaoapp/gou/store"
+	"github.com/yaoapp/yao/agent/store/types"
+)
+
+// DefaultPrefix is the default key prefix for agent data stored in redis
+const DefaultPrefix = "__yao.agent"
+
+// DefaultTTL is the default chat time to live (90 days)
+const DefaultTTL = 90 * 24 * time.Hour
+
+// numberJSON decodes generic maps with json.Number so UnixNano timestamps keep their precision
+var numberJSON = jsoniter.Config{EscapeHTML: true, UseNumber: true}.Froze()
+
+// Redis implements the Store interface on a key-value backend.
+// Entities are stored as JSON strings, collections as native lists and sets,
+// so that every write is a single atomic command and several instances can
+// share the same backend without any process-local lock:
+//
+//	{prefix}:chat:{chat_id}            chat session (expires after TTL)
+//	{prefix}:chats:all                 set of the IDs of all chats
+//	{prefix}:chats:user:{user_id}      set of the IDs of the chats created by a user
+//	{prefix}:chats:team:{team_id}      set of the IDs of the chats of a team
+//	{prefix}:messages:{chat_id}        list of the message IDs of a chat, in insertion order
+//	{prefix}:message:{message_id}      message (expires with the chat)
+//	{prefix}:resume:{chat_id}          list of the resume records of a chat
+//	{prefix}:stack:{stack_id}          stack_id -> chat_id index
+//	{prefix}:search:{request_id}       list of the search records of a request
+//	{prefix}:searches:{chat_id}        set of the request IDs with search records of a chat
+//	{prefix}:search_seq                search record ID counter
+//	{prefix}:assistants                set of the IDs of all assistants
+//	{prefix}:assistant:{assistant_id}  assistant (never expires)
+//
+// Lists and sets carry no expiry, the key-value API cannot set one on them:
+// they are deleted with their chat, and IDs of expired entries are pruned when read.
+// Updating an entity rewrites its key, the last concurrent update wins.
+//
+// Filtering, sorting and pagination are evaluated in memory with the same
+// semantics as the xun (database) implementation. Chats are listed through the
+// chat index sets and assistants through the assistant index set.
+// The SQL QueryFilter callbacks of ChatFilter and AssistantFilter cannot be
+// evaluated on redis; permission filtering uses the Access rules instead, and
+// a QueryFilter without Access rules is rejected rather than ignored.
+type Redis struct {
+	kv      store.Store
+	setting types.Setting
+	prefix  string
+	ttl     time.Duration
 }
 
-// =============================================================================
-// Chat Management
-// =============================================================================
+// NewRedis create a new redis store
+func NewRedis(setting types.Setting) (types.Store, error) {
+	conn, err := connector.Select(setting.Connector)
+	if err != nil {
+		return nil, fmt.Errorf("select store connector %s error: %s", setting.Connector, err.Error())
+	}
 
-// CreateChat creates a new chat session
-func (r *Redis) CreateChat(chat *types.Chat) error {
-	// TODO: implement
-	return nil
-}
+	kv, err := store.New(conn, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create store on connector %s error: %s", setting.Connector, err.Error())
+	}
 
-// GetChat retrieves a single chat by ID
-func (r *Redis) GetChat(chatID string) (*types.Chat, error) {
-	// TODO: implement
-	return nil, nil
+	return New(kv, setting), nil
 }
 
-// UpdateChat updates chat fields
-func (r *Redis) UpdateChat(chatID string, updates map[string]interface{}) error {
-	// TODO: implement
-	return nil
-}
+// New create a redis store on top of an existing key-value store
+// The key prefix can be overridden with setting.Options["prefix"]
+func New(kv store.Store, setting types.Setting) *Redis {
+	prefix := DefaultPrefix
+	if setting.Options != nil {
+		if p, ok := setting.Options["prefix"].(string); ok && p != "" {
+			prefix = p
+		}
+	}
 
-// DeleteChat deletes a chat and its associated messages
-func (r *Redis) DeleteChat(chatID string) error {
-	// TODO: implement
-	return nil
-}
+	ttl := DefaultTTL
+	if setting.TTL > 0 {
+		ttl = time.Duration(setting.TTL) * time.Second
+	}
 
-// ListChats retrieves a paginated list of chats with optional grouping
-func (r *Redis) ListChats(filter types.ChatFilter) (*types.ChatList, error) {
-	// TODO: implement
-	return nil, nil
+	return &Redis{kv: kv, setting: setting, prefix: prefix, ttl: ttl}
 }
 
 // =============================================================================
-// Message Management
+// Keys
 // =============================================================================
 
-// SaveMessages batch saves messages for a chat
-func (r *Redis) SaveMessages(chatID string, messages []*types.Message) error {
-	// TODO: implement
-	return nil
-}
-
-// GetMessages retrieves messages for a chat with filtering
-func (r *Redis) GetMessages(chatID string, filter types.MessageFilter) ([]*types.Message, error) {
-	// TODO: implement
-	return nil, nil
-}
-
-// UpdateMessage updates a single message
-func (r *Redis) UpdateMessage(messageID string, updates map[string]interface{}) error {
-	// TODO: implement
-	return nil
+// key generates a storage key: {prefix}:{parts...}
+func (r *Redis) key(parts ...string) string {
+	return r.prefix + ":" + strings.Join(parts, ":")
 }
 
-// DeleteMessages deletes specific messages from a chat
-func (r *Redis) DeleteMessages(chatID string, messageIDs []string) error {
-	// TODO: implement
+// checkAccess rejects permission filters given only as SQL callbacks
+func checkAccess(queryFilter bool, access []types.AccessRule) error {
+	if queryFilter && len(access) == 0 {
+		return fmt.Errorf("QueryFilter is not supported by the redis store, set Access rules for permission filtering")
+	}
 	return nil
 }
 
 // =============================================================================
-// Resume Management (only called on failure/interrupt)
+// JSON Helpers
 // =============================================================================
 
-// SaveResume batch saves resume records
-func (r *Redis) SaveResume(records []*types.Resume) error {
-	// TODO: implement
-	return nil
-}
-
-// GetResume retrieves all resume records for a chat
-func (r *Redis) GetResume(chatID string) ([]*types.Resume, error) {
-	// TODO: implement
-	return nil, nil
-}
-
-// GetLastResume retrieves the last resume record for a chat
-func (r *Redis) GetLastResume(chatID string) (*types.Resume, error) {
-	// TODO: implement
-	return nil, nil
-}
-
-// GetResumeByStackID retrieves resume records for a specific stack
-func (r *Redis) GetResumeByStackID(stackID string) ([]*types.Resume, error) {
-	// TODO: implement
-	return nil, nil
-}
-
-// GetStackPath returns the stack path from root to the given stack
-func (r *Redis) GetStackPath(stackID string) ([]string, error) {
-	// TODO: implement
-	return nil, nil
-}
-
-// DeleteResume deletes all resume records for a chat
-func (r *Redis) DeleteResume(chatID string) error {
-	// TODO: implement
+// setJSON marshals value and stores it under key
+func (r *Redis) setJSON(key string, value interface{}, ttl time.Duration) error {
+	data, err := jsoniter.MarshalToString(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", key, err)
+	}
+	if err := r.kv.Set(key, data, ttl); err != nil {
+		return fmt.Errorf("failed to save %s: %w", key, err)
+	}
 	return nil
 }
 
-// =============================================================================
-// Assistant Management
-// =============================================================================
-
-// SaveAssistant saves assistant information
-func (r *Redis) SaveAssistant(assistant *types.AssistantModel) (string, error) {
-	// TODO: implement
-	return assistant.ID, nil
-}
-
-// UpdateAssistant updates specific fields of an assistant
-func (r *Redis) UpdateAssistant(assistantID string, updates map[string]interface{}) error {
-	// TODO: implement
+// getJSON loads key into value, returns false if the key does not exist
+func (r *Redis) getJSON(key string, value interface{}) (bool, error) {
+	raw, ok := r.kv.Get(key)
+	if !ok || raw == nil {
+		return false, nil
+	}
+	if err := decodeJSON(raw, value); err != nil {
+		return false, fmt.Errorf("failed to unmarshal %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// decodeJSON decodes a stored JSON value into value
+func decodeJSON(raw interface{}, value interface{}) error {
+	var data []byte
+	switch v := raw.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		// Some backends decode stored JSON on read
+		b, err := jsoniter.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("invalid data type %T in store", raw)
+		}
+		data = b
+	}
+
+	api := jsoniter.ConfigDefault
+	if _, isMap := value.(*map[string]interface{}); isMap {
+		api = numberJSON
+	}
+	return api.Unmarshal(data, value)
+}
+
+// pushJSON appends values to a list as JSON strings in a single command
+func (r *Redis) pushJSON(key string, values ...interface{}) error {
+	items := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		data, err := jsoniter.MarshalToString(value)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", key, err)
+		}
+		items = append(items, data)
+	}
+	if err := r.kv.Push(key, items...); err != nil {
+		return fmt.Errorf("failed to save %s: %w", key, err)
+	}
 	return nil
 }
 
-// DeleteAssistant deletes an assistant
-func (r *Redis) DeleteAssistant(assistantID string) error {
-	// TODO: implement
+// listItems loads the items of a list or set, empty if the key does not exist
+func (r *Redis) listItems(key string) ([]interface{}, error) {
+	items, err := r.kv.ArrayAll(key)
+	if err != nil {
+		if !r.kv.Has(key) {
+			return []interface{}{}, nil
+		}
+		return nil, fmt.Errorf("failed to load %s: %w", key, err)
+	}
+	return items, nil
+}
+
+// listIDs loads the IDs of a list or set
+func (r *Redis) listIDs(key string) ([]string, error) {
+	items, err := r.listItems(key)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		switch v := item.(type) {
+		case string:
+			ids = append(ids, v)
+		case []byte:
+			ids = append(ids, string(v))
+		}
+	}
+	return ids, nil
+}
+
+// addToSet adds an ID to a set
+func (r *Redis) addToSet(key string, id string) error {
+	if err := r.kv.AddToSet(key, id); err != nil {
+		return fmt.Errorf("failed to add %s to %s: %w", id, key, err)
+	}
 	return nil
 }
 
-// GetAssistants retrieves a list of assistants
-func (r *Redis) GetAssistants(filter types.AssistantFilter, locale ...string) (*types.AssistantList, error) {
-	// TODO: implement
-	return &types.AssistantList{}, nil
-}
-
-// GetAssistantTags retrieves all unique tags from assistants with filtering
-func (r *Redis) GetAssistantTags(filter types.AssistantFilter, locale ...string) ([]types.Tag, error) {
-	// TODO: implement
-	return []types.Tag{}, nil
-}
-
-// GetAssistant retrieves a single assistant by ID
-func (r *Redis) GetAssistant(assistantID string, fields []string, locale ...string) (*types.AssistantModel, error) {
-	// TODO: implement
-	return nil, nil
-}
-
-// DeleteAssistants deletes assistants based on filter conditions
-func (r *Redis) DeleteAssistants(filter types.AssistantFilter) (int64, error) {
-	// TODO: implement
-	return 0, nil
-}
-
-// =============================================================================
-// Search Management
-// =============================================================================
-
-// SaveSearch saves a search record for a request
-func (r *Redis) SaveSearch(search *types.Search) error {
-	// TODO: implement
+// pullFromSet removes IDs from a list or set
+func (r *Redis) pullFromSet(key string, ids ...string) error {
+	values := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, id)
+	}
+	if err := r.kv.PullAll(key, values); err != nil && r.kv.Has(key) {
+		return fmt.Errorf("failed to remove from %s: %w", key, err)
+	}
 	return nil
 }
 
-// GetSearches retrieves all search records for a request
-func (r *Redis) GetSearches(requestID string) ([]*types.Search, error) {
-	// TODO: implement
-	return nil, nil
+// getString loads a plain string value
+func (r *Redis) getString(key string) string {
+	raw, ok := r.kv.Get(key)
+	if !ok {
+		return ""
+	}
+	s, _ := raw.(string)
+	return s
 }
 
-// GetReference retrieves a single reference by request ID and index
-func (r *Redis) GetReference(requestID string, index int) (*types.Reference, error) {
-	// TODO: implement
-	return nil, nil
-}
-
-// DeleteSearches deletes all search records for a chat
-func (r *Redis) DeleteSearches(chatID string) error {
-	// TODO: implement
-	return nil
+// containsFold reports whether substr is within s, ignoring case (SQL LIKE semantics)
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
 }
@@ -0,0 +1,222 @@
+package redis
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yaoapp/yao/agent/store/types"
+)
+
+// =============================================================================
+// Resume Management (only called on failure/interrupt)
+// =============================================================================
+
+// SaveResume batch saves resume records
+// Only called when request is interrupted or failed
+func (r *Redis) SaveResume(records []*types.Resume) error {
+	if len(records) == 0 {
+		return nil // Nothing to save
+	}
+
+	// Validate and group records by chat before touching the store
+	now := time.Now()
+	byChat := map[string][]*types.Resume{}
+	chatOrder := []string{}
+	for _, record := range records {
+		if record == nil {
+			continue
+		}
+
+		// Validate required fields
+		if record.ChatID == "" {
+			return fmt.Errorf("chat_id is required")
+		}
+		if record.RequestID == "" {
+			return fmt.Errorf("request_id is required")
+		}
+		if record.AssistantID == "" {
+			return fmt.Errorf("assistant_id is required")
+		}
+		if record.StackID == "" {
+			return fmt.Errorf("stack_id is required")
+		}
+		if record.Type == "" {
+			return fmt.Errorf("type is required")
+		}
+		if record.Status == "" {
+			return fmt.Errorf("status is required")
+		}
+
+		row := *record
+		if row.ResumeID == "" {
+			row.ResumeID = uuid.New().String()
+		}
+		row.CreatedAt = now
+		row.UpdatedAt = now
+
+		if _, has := byChat[row.ChatID]; !has {
+			chatOrder = append(chatOrder, row.ChatID)
+		}
+		byChat[row.ChatID] = append(byChat[row.ChatID], &row)
+	}
+
+	for _, chatID := range chatOrder {
+		rows := byChat[chatID]
+		values := make([]interface{}, 0, len(rows))
+		for _, row := range rows {
+			values = append(values, row)
+		}
+		if err := r.pushJSON(r.key("resume", chatID), values...); err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			if err := r.kv.Set(r.key("stack", row.StackID), chatID, r.ttl); err != nil {
+				return fmt.Errorf("failed to index stack %s: %w", row.StackID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetResume retrieves all resume records for a chat ordered by sequence
+func (r *Redis) GetResume(chatID string) ([]*types.Resume, error) {
+	if chatID == "" {
+		return nil, fmt.Errorf("chat_id is required")
+	}
+
+	records, err := r.loadResume(chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	sortResume(records)
+	return records, nil
+}
+
+// GetLastResume retrieves the last (highest sequence) resume record for a chat
+func (r *Redis) GetLastResume(chatID string) (*types.Resume, error) {
+	records, err := r.GetResume(chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, nil // No resume records found
+	}
+	return records[len(records)-1], nil
+}
+
+// GetResumeByStackID retrieves resume records for a specific stack
+func (r *Redis) GetResumeByStackID(stackID string) ([]*types.Resume, error) {
+	if stackID == "" {
+		return nil, fmt.Errorf("stack_id is required")
+	}
+
+	records := []*types.Resume{}
+	chatID := r.getString(r.key("stack", stackID))
+	if chatID == "" {
+		return records, nil
+	}
+
+	all, err := r.loadResume(chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range all {
+		if record.StackID == stackID {
+			records = append(records, record)
+		}
+	}
+
+	sortResume(records)
+	return records, nil
+}
+
+// GetStackPath returns the stack path from root to the given stack
+// Returns: [root_stack_id, ..., current_stack_id]
+func (r *Redis) GetStackPath(stackID string) ([]string, error) {
+	if stackID == "" {
+		return nil, fmt.Errorf("stack_id is required")
+	}
+
+	path := []string{stackID}
+	visited := map[string]bool{stackID: true}
+	currentStackID := stackID
+
+	// Walk up the stack tree by following stack_parent_id
+	for {
+		records, err := r.GetResumeByStackID(currentStackID)
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == 0 {
+			break
+		}
+
+		parentID := records[0].StackParentID
+		if parentID == "" || visited[parentID] {
+			break // Reached root (or a malformed cycle)
+		}
+
+		// Prepend parent to path
+		path = append([]string{parentID}, path...)
+		visited[parentID] = true
+		currentStackID = parentID
+	}
+
+	return path, nil
+}
+
+// DeleteResume deletes all resume records for a chat
+// Called after successful resume to clean up
+func (r *Redis) DeleteResume(chatID string) error {
+	if chatID == "" {
+		return fmt.Errorf("chat_id is required")
+	}
+
+	records, err := r.loadResume(chatID)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		r.kv.Del(r.key("stack", record.StackID))
+	}
+	r.kv.Del(r.key("resume", chatID))
+	return nil
+}
+
+// =============================================================================
+// Helper Functions
+// =============================================================================
+
+// loadResume loads the resume records of a chat in insertion order
+func (r *Redis) loadResume(chatID string) ([]*types.Resume, error) {
+	key := r.key("resume", chatID)
+	items, err := r.listItems(key)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*types.Resume, 0, len(items))
+	for _, item := range items {
+		var record types.Resume
+		if err := decodeJSON(item, &record); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s: %w", key, err)
+		}
+		records = append(records, &record)
+	}
+	return records, nil
+}
+
+// sortResume sorts resume records by sequence, keeping insertion order for ties
+func sortResume(records []*types.Resume) {
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Sequence < records[j].Sequence
+	})
+}
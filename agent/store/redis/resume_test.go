@@ -0,0 +1,184 @@
+package redis_test
+
+import (
+	"testing"
+
+	"github.com/yaoapp/yao/agent/store/types"
+)
+
+// TestResume tests resume records and stack paths
+func TestResume(t *testing.T) {
+	store := newTestStore(t)
+
+	records := []*types.Resume{
+		{ChatID: "resume_chat", RequestID: "req_1", AssistantID: "root", StackID: "stack_root", StackDepth: 0, Type: types.ResumeTypeLLM, Status: types.ResumeStatusInterrupted, Sequence: 1},
+		{ChatID: "resume_chat", RequestID: "req_1", AssistantID: "child", StackID: "stack_child", StackParentID: "stack_root", StackDepth: 1, Type: types.ResumeTypeDelegate, Status: types.ResumeStatusInterrupted, Sequence: 3},
+		{ChatID: "resume_chat", RequestID: "req_1", AssistantID: "leaf", StackID: "stack_leaf", StackParentID: "stack_child", StackDepth: 2, Type: types.ResumeTypeTool, Status: types.ResumeStatusFailed, Sequence: 2, Error: "tool failed"},
+	}
+
+	if err := store.SaveResume(records); err != nil {
+		t.Fatalf("Failed to save resume records: %v", err)
+	}
+
+	t.Run("GetResumeOrderedBySequence", func(t *testing.T) {
+		result, err := store.GetResume("resume_chat")
+		if err != nil {
+			t.Fatalf("Failed to get resume: %v", err)
+		}
+		if len(result) != 3 {
+			t.Fatalf("Expected 3 records, got %d", len(result))
+		}
+		for i, record := range result {
+			if record.Sequence != i+1 {
+				t.Errorf("Expected sequence %d at position %d, got %d", i+1, i, record.Sequence)
+			}
+			if record.ResumeID == "" {
+				t.Error("Expected resume_id to be generated")
+			}
+		}
+	})
+
+	t.Run("GetLastResume", func(t *testing.T) {
+		last, err := store.GetLastResume("resume_chat")
+		if err != nil {
+			t.Fatalf("Failed to get last resume: %v", err)
+		}
+		if last == nil || last.StackID != "stack_child" {
+			t.Errorf("Expected last resume on stack_child, got %+v", last)
+		}
+
+		none, err := store.GetLastResume("no_resume_chat")
+		if err != nil {
+			t.Fatalf("Failed to get last resume: %v", err)
+		}
+		if none != nil {
+			t.Error("Expected nil when chat has no resume records")
+		}
+	})
+
+	t.Run("GetResumeByStackID", func(t *testing.T) {
+		result, err := store.GetResumeByStackID("stack_leaf")
+		if err != nil {
+			t.Fatalf("Failed to get resume by stack: %v", err)
+		}
+		if len(result) != 1 || result[0].Error != "tool failed" {
+			t.Errorf("Expected the leaf record, got %d records", len(result))
+		}
+	})
+
+	t.Run("GetStackPath", func(t *testing.T) {
+		path, err := store.GetStackPath("stack_leaf")
+		if err != nil {
+			t.Fatalf("Failed to get stack path: %v", err)
+		}
+		expected := []string{"stack_root", "stack_child", "stack_leaf"}
+		if len(path) != len(expected) {
+			t.Fatalf("Expected path %v, got %v", expected, path)
+		}
+		for i := range expected {
+			if path[i] != expected[i] {
+				t.Errorf("Expected path %v, got %v", expected, path)
+				break
+			}
+		}
+	})
+
+	t.Run("SaveInvalidResumeFails", func(t *testing.T) {
+		err := store.SaveResume([]*types.Resume{{ChatID: "resume_chat", RequestID: "req_1"}})
+		if err == nil {
+			t.Error("Expected error when required fields are missing")
+		}
+	})
+
+	t.Run("DeleteResume", func(t *testing.T) {
+		if err := store.DeleteResume("resume_chat"); err != nil {
+			t.Fatalf("Failed to delete resume: %v", err)
+		}
+
+		result, err := store.GetResume("resume_chat")
+		if err != nil {
+			t.Fatalf("Failed to get resume: %v", err)
+		}
+		if len(result) != 0 {
+			t.Errorf("Expected no records after delete, got %d", len(result))
+		}
+
+		byStack, err := store.GetResumeByStackID("stack_root")
+		if err != nil {
+			t.Fatalf("Failed to get resume by stack: %v", err)
+		}
+		if len(byStack) != 0 {
+			t.Errorf("Expected stack index to be cleared, got %d", len(byStack))
+		}
+	})
+}
+
+// TestSearch tests search records and reference lookup
+func TestSearch(t *testing.T) {
+	store := newTestStore(t)
+
+	search := &types.Search{
+		RequestID: "search_req",
+		ChatID:    "search_chat",
+		Query:     "yao agent",
+		Source:    "web",
+		Keywords:  []string{"yao", "agent"},
+		References: []types.Reference{
+			{Index: 1, Type: "web", Title: "Yao", URL: "https://yaoapps.com"},
+			{Index: 2, Type: "web", Title: "Docs", URL: "https://yaoapps.com/docs"},
+		},
+	}
+
+	if err := store.SaveSearch(search); err != nil {
+		t.Fatalf("Failed to save search: %v", err)
+	}
+	if err := store.SaveSearch(&types.Search{RequestID: "search_req", ChatID: "search_chat", Source: "kb"}); err != nil {
+		t.Fatalf("Failed to save search: %v", err)
+	}
+
+	t.Run("GetSearches", func(t *testing.T) {
+		result, err := store.GetSearches("search_req")
+		if err != nil {
+			t.Fatalf("Failed to get searches: %v", err)
+		}
+		if len(result) != 2 || result[0].Source != "web" || result[1].Source != "kb" {
+			t.Errorf("Expected [web kb] searches, got %d", len(result))
+		}
+		if len(result[0].Keywords) != 2 {
+			t.Errorf("Expected 2 keywords, got %d", len(result[0].Keywords))
+		}
+	})
+
+	t.Run("GetReference", func(t *testing.T) {
+		ref, err := store.GetReference("search_req", 2)
+		if err != nil {
+			t.Fatalf("Failed to get reference: %v", err)
+		}
+		if ref.Title != "Docs" {
+			t.Errorf("Expected reference 'Docs', got '%s'", ref.Title)
+		}
+
+		if _, err := store.GetReference("search_req", 9); err == nil {
+			t.Error("Expected error for unknown reference index")
+		}
+	})
+
+	t.Run("SaveSearchWithoutSourceFails", func(t *testing.T) {
+		if err := store.SaveSearch(&types.Search{RequestID: "r", ChatID: "c"}); err == nil {
+			t.Error("Expected error when source is missing")
+		}
+	})
+
+	t.Run("DeleteSearches", func(t *testing.T) {
+		if err := store.DeleteSearches("search_chat"); err != nil {
+			t.Fatalf("Failed to delete searches: %v", err)
+		}
+		result, err := store.GetSearches("search_req")
+		if err != nil {
+			t.Fatalf("Failed to get searches: %v", err)
+		}
+		if len(result) != 0 {
+			t.Errorf("Expected no searches after delete, got %d", len(result))
+		}
+	})
+}
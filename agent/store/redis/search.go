@@ -0,0 +1,116 @@
+package redis
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yaoapp/yao/agent/store/types"
+)
+
+// =============================================================================
+// Search Management
+// =============================================================================
+
+// SaveSearch saves a search record for a request
+func (r *Redis) SaveSearch(search *types.Search) error {
+	if search == nil {
+		return fmt.Errorf("search is nil")
+	}
+	if search.RequestID == "" {
+		return fmt.Errorf("request_id is required")
+	}
+	if search.ChatID == "" {
+		return fmt.Errorf("chat_id is required")
+	}
+	if search.Source == "" {
+		return fmt.Errorf("source is required")
+	}
+
+	// IDs come from a shared counter, so that concurrent saves never reuse one
+	id, err := r.kv.Incr(r.key("search_seq"), 1)
+	if err != nil {
+		return fmt.Errorf("failed to generate search id: %w", err)
+	}
+
+	row := *search
+	row.ID = id
+	row.CreatedAt = time.Now()
+
+	if err := r.pushJSON(r.key("search", search.RequestID), &row); err != nil {
+		return err
+	}
+
+	// Track the request under its chat for DeleteSearches
+	return r.addToSet(r.key("searches", search.ChatID), search.RequestID)
+}
+
+// GetSearches retrieves all search records for a request
+func (r *Redis) GetSearches(requestID string) ([]*types.Search, error) {
+	if requestID == "" {
+		return nil, fmt.Errorf("request_id is required")
+	}
+	return r.loadSearches(requestID)
+}
+
+// GetReference retrieves a single reference by request ID and index
+func (r *Redis) GetReference(requestID string, index int) (*types.Reference, error) {
+	if requestID == "" {
+		return nil, fmt.Errorf("request_id is required")
+	}
+	if index < 1 {
+		return nil, fmt.Errorf("index must be >= 1")
+	}
+
+	searches, err := r.GetSearches(requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the reference with matching index
+	for _, search := range searches {
+		for _, ref := range search.References {
+			if ref.Index == index {
+				return &ref, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("reference not found: request_id=%s, index=%d", requestID, index)
+}
+
+// DeleteSearches deletes all search records for a chat
+func (r *Redis) DeleteSearches(chatID string) error {
+	if chatID == "" {
+		return fmt.Errorf("chat_id is required")
+	}
+
+	requests, err := r.listIDs(r.key("searches", chatID))
+	if err != nil {
+		return err
+	}
+
+	for _, requestID := range requests {
+		r.kv.Del(r.key("search", requestID))
+	}
+	r.kv.Del(r.key("searches", chatID))
+	return nil
+}
+
+// loadSearches loads the search records of a request in creation order
+func (r *Redis) loadSearches(requestID string) ([]*types.Search, error) {
+	key := r.key("search", requestID)
+	items, err := r.listItems(key)
+	if err != nil {
+		return nil, err
+	}
+
+	searches := make([]*types.Search, 0, len(items))
+	for _, item := range items {
+		var search types.Search
+		if err := decodeJSON(item, &search); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s: %w", key, err)
+		}
+		searches = append(searches, &search)
+	}
+	return searches, nil
+}
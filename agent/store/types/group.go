@@ -0,0 +1,58 @@
+package types
+
+import "time"
+
+// GroupChatsByTime groups chats by time periods for UI display
+// Uses last_message_at when available, otherwise created_at; empty groups are omitted
+func GroupChatsByTime(chats []*Chat) []*ChatGroup {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	yesterday := today.AddDate(0, 0, -1)
+	thisWeekStart := today.AddDate(0, 0, -int(today.Weekday()))
+	thisMonthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	groups := map[string]*ChatGroup{
+		"today":      {Key: "today", Label: "Today", Chats: []*Chat{}},
+		"yesterday":  {Key: "yesterday", Label: "Yesterday", Chats: []*Chat{}},
+		"this_week":  {Key: "this_week", Label: "This Week", Chats: []*Chat{}},
+		"this_month": {Key: "this_month", Label: "This Month", Chats: []*Chat{}},
+		"earlier":    {Key: "earlier", Label: "Earlier", Chats: []*Chat{}},
+	}
+
+	for _, chat := range chats {
+		// Use last_message_at if available, otherwise created_at
+		var chatTime time.Time
+		if chat.LastMessageAt != nil {
+			chatTime = *chat.LastMessageAt
+		} else {
+			chatTime = chat.CreatedAt
+		}
+
+		chatDate := time.Date(chatTime.Year(), chatTime.Month(), chatTime.Day(), 0, 0, 0, 0, chatTime.Location())
+
+		switch {
+		case chatDate.Equal(today) || chatDate.After(today):
+			groups["today"].Chats = append(groups["today"].Chats, chat)
+		case chatDate.Equal(yesterday):
+			groups["yesterday"].Chats = append(groups["yesterday"].Chats, chat)
+		case chatDate.After(thisWeekStart) || chatDate.Equal(thisWeekStart):
+			groups["this_week"].Chats = append(groups["this_week"].Chats, chat)
+		case chatDate.After(thisMonthStart) || chatDate.Equal(thisMonthStart):
+			groups["this_month"].Chats = append(groups["this_month"].Chats, chat)
+		default:
+			groups["earlier"].Chats = append(groups["earlier"].Chats, chat)
+		}
+	}
+
+	// Update counts and filter empty groups
+	result := make([]*ChatGroup, 0)
+	for _, key := range []string{"today", "yesterday", "this_week", "this_month", "earlier"} {
+		group := groups[key]
+		group.Count = len(group.Chats)
+		if group.Count > 0 {
+			result = append(result, group)
+		}
+	}
+
+	return result
+}
@@ -76,6 +76,52 @@ type ChatFilter struct {
 
 	// Permission filter (not serialized)
 	QueryFilter func(query.Query) `json:"-"` // Custom query function for permission filtering
+	Access      []AccessRule      `json:"-"` // Structured form of QueryFilter for non-SQL stores
+}
+
+// AccessRule is one alternative of a permission filter in structured form.
+// Stores that cannot evaluate the SQL QueryFilter callback (redis, mongo) use
+// the rules instead: a record is readable when any rule matches, and a rule
+// matches when all of its set conditions hold.
+type AccessRule struct {
+	Public    bool   `json:"public,omitempty"`     // public = true
+	CreatedBy string `json:"created_by,omitempty"` // __yao_created_by = CreatedBy
+	TeamID    string `json:"team_id,omitempty"`    // __yao_team_id = TeamID
+	NoTeam    bool   `json:"no_team,omitempty"`    // __yao_team_id is null
+	Share     string `json:"share,omitempty"`      // share = Share
+}
+
+// Match reports whether a record with the given permission fields satisfies the rule
+func (rule AccessRule) Match(public bool, createdBy, teamID, share string) bool {
+	if rule.Public && !public {
+		return false
+	}
+	if rule.CreatedBy != "" && createdBy != rule.CreatedBy {
+		return false
+	}
+	if rule.TeamID != "" && teamID != rule.TeamID {
+		return false
+	}
+	if rule.NoTeam && teamID != "" {
+		return false
+	}
+	if rule.Share != "" && share != rule.Share {
+		return false
+	}
+	return true
+}
+
+// MatchAccess reports whether any of the rules matches, no rules means no restriction
+func MatchAccess(rules []AccessRule, public bool, createdBy, teamID, share string) bool {
+	if len(rules) == 0 {
+		return true
+	}
+	for _, rule := range rules {
+		if rule.Match(public, createdBy, teamID, share) {
+			return true
+		}
+	}
+	return false
 }
 
 // ChatList paginated response with time-based grouping
@@ -190,6 +236,7 @@ type AssistantFilter struct {
 	PageSize     int               `json:"pagesize,omitempty"`      // Items per page
 	Select       []string          `json:"select,omitempty"`        // Fields to return, returns all fields if empty
 	QueryFilter  func(query.Query) `json:"-"`                       // Custom query function for permission filtering (not serialized)
+	Access       []AccessRule      `json:"-"`                       // Structured form of QueryFilter for non-SQL stores (not serialized)
 }
 
 // AssistantList represents the paginated assistant list response structure
@@ -842,67 +842,5 @@ func (store *Xun) GetAssistantTags(filter types.AssistantFilter, locale ...strin
 
 // translate applies i18n translation to assistant model fields
 func (store *Xun) translate(model *types.AssistantModel, assistantID string, locale string) {
-	if model == nil {
-		return
-	}
-
-	// Translate name
-	if translated := i18n.Translate(assistantID, locale, model.Name); translated != nil {
-		if s, ok := translated.(string); ok {
-			model.Name = s
-		}
-	}
-
-	// Translate description
-	if translated := i18n.Translate(assistantID, locale, model.Description); translated != nil {
-		if s, ok := translated.(string); ok {
-			model.Description = s
-		}
-	}
-
-	// Translate capabilities
-	if translated := i18n.Translate(assistantID, locale, model.Capabilities); translated != nil {
-		if s, ok := translated.(string); ok {
-			model.Capabilities = s
-		}
-	}
-
-	// Translate prompts
-	if model.Prompts != nil {
-		for i := range model.Prompts {
-			if translated := i18n.Translate(assistantID, locale, model.Prompts[i].Name); translated != nil {
-				if s, ok := translated.(string); ok {
-					model.Prompts[i].Name = s
-				}
-			}
-			if translated := i18n.Translate(assistantID, locale, model.Prompts[i].Content); translated != nil {
-				if s, ok := translated.(string); ok {
-					model.Prompts[i].Content = s
-				}
-			}
-		}
-	}
-
-	// Translate placeholder
-	if model.Placeholder != nil {
-		if translated := i18n.Translate(assistantID, locale, model.Placeholder.Title); translated != nil {
-			if s, ok := translated.(string); ok {
-				model.Placeholder.Title = s
-			}
-		}
-		if translated := i18n.Translate(assistantID, locale, model.Placeholder.Description); translated != nil {
-			if s, ok := translated.(string); ok {
-				model.Placeholder.Description = s
-			}
-		}
-		if translated := i18n.Translate(assistantID, locale, model.Placeholder.Prompts); translated != nil {
-			if prompts, ok := translated.([]string); ok {
-				model.Placeholder.Prompts = prompts
-			}
-		}
-	}
-
-	// Tags are NOT translated — they serve as filter keys and must remain
-	// in their original (English) form so that filter.tags round-trips
-	// correctly through the LIKE query on the DB column.
+	types.TranslateAssistant(model, assistantID, locale)
 }
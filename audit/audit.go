@@ -0,0 +1,264 @@
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yaoapp/gou/model"
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/xun/capsule"
+)
+
+// chainMu serializes the appends of this process, so that they do not race each other for the chain head
+// Appends of other instances are serialized by the database: prev_hash is unique, an entry
+// linking to a head that another instance has already linked to fails and is retried
+var chainMu sync.Mutex
+
+// maxChainAttempts bounds the retries of an append that lost the race for the chain head
+const maxChainAttempts = 10
+
+// chainHead returns the hash of the chain head, a variable so that tests can simulate a stale head
+var chainHead = lastHash
+
+var hostname, _ = os.Hostname()
+
+// Record appends an entry to the audit log
+// Before, After and Details are masked, the diff is computed when not given,
+// and the entry is linked to the previous record by its hash.
+func Record(ctx context.Context, e *Entry) error {
+	if e == nil {
+		return fmt.Errorf("audit entry is required")
+	}
+	if e.Operation == "" {
+		return fmt.Errorf("audit operation is required")
+	}
+
+	mod := model.Select(ModelID)
+	if mod == nil {
+		return fmt.Errorf("model %s not found", ModelID)
+	}
+
+	prepare(e)
+
+	chainMu.Lock()
+	defer chainMu.Unlock()
+
+	for attempt := 1; ; attempt++ {
+		prev, err := chainHead(mod)
+		if err != nil {
+			return err
+		}
+		e.PrevHash = prev
+		e.Hash, err = Hash(e)
+		if err != nil {
+			return err
+		}
+
+		id, err := mod.Create(toRow(e))
+		if err == nil {
+			e.ID = int64(id)
+			return nil
+		}
+
+		// Another instance appended first, link to the new head
+		head, headErr := lastHash(mod)
+		if headErr != nil || head == prev || attempt >= maxChainAttempts {
+			return fmt.Errorf("failed to record audit entry: %w", err)
+		}
+	}
+}
+
+// Log records an entry and logs the error instead of returning it,
+// audit failures never fail the audited operation
+func Log(ctx context.Context, e *Entry) {
+	if err := Record(ctx, e); err != nil {
+		op := ""
+		if e != nil {
+			op = e.Operation
+		}
+		log.Error("[Audit] failed to record %s: %v", op, err)
+	}
+}
+
+// Hash returns the SHA-256 of the chained fields of an entry and its previous hash
+func Hash(e *Entry) (string, error) {
+	payload := struct {
+		EventID        string      `json:"event_id"`
+		Operation      string      `json:"operation"`
+		Category       string      `json:"category"`
+		Severity       string      `json:"severity"`
+		UserID         string      `json:"user_id"`
+		TeamID         string      `json:"team_id"`
+		ClientIP       string      `json:"client_ip"`
+		TargetResource string      `json:"target_resource"`
+		ResourceType   string      `json:"resource_type"`
+		Success        bool        `json:"success"`
+		ErrorMessage   string      `json:"error_message"`
+		Before         interface{} `json:"data_before"`
+		After          interface{} `json:"data_after"`
+		Diff           interface{} `json:"diff"`
+		Details        interface{} `json:"details"`
+		Timestamp      int64       `json:"timestamp"`
+		PrevHash       string      `json:"prev_hash"`
+	}{
+		EventID:        e.EventID,
+		Operation:      e.Operation,
+		Category:       e.Category,
+		Severity:       e.Severity,
+		UserID:         e.UserID,
+		TeamID:         e.TeamID,
+		ClientIP:       e.ClientIP,
+		TargetResource: e.TargetResource,
+		ResourceType:   e.ResourceType,
+		Success:        e.Success,
+		ErrorMessage:   e.ErrorMessage,
+		Before:         normalize(e.Before),
+		After:          normalize(e.After),
+		Diff:           normalize(e.Diff),
+		Details:        normalize(e.Details),
+		Timestamp:      e.Timestamp,
+		PrevHash:       e.PrevHash,
+	}
+
+	// encoding/json sorts map keys, so the encoding is canonical
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash audit entry: %w", err)
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Verify walks the audit log in insertion order and checks every hash and link
+func Verify(ctx context.Context) (*VerifyResult, error) {
+	mod := model.Select(ModelID)
+	if mod == nil {
+		return nil, fmt.Errorf("model %s not found", ModelID)
+	}
+
+	result := &VerifyResult{Valid: true}
+	prev := ""
+	var lastID int64
+	for {
+		rows, err := capsule.Query().Table(mod.MetaData.Table.Name).
+			Where("id", ">", lastID).
+			OrderBy("id", "asc").
+			Limit(500).
+			Get()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audit log: %w", err)
+		}
+		if len(rows) == 0 {
+			return result, nil
+		}
+
+		for _, row := range rows {
+			e := fromRow(map[string]interface{}(row))
+			lastID = e.ID
+			result.Checked++
+
+			if e.PrevHash != prev {
+				result.Valid, result.BrokenAt, result.Reason = false, e.EventID, "previous hash does not match"
+				return result, nil
+			}
+			hash, err := Hash(e)
+			if err != nil {
+				return nil, err
+			}
+			if hash != e.Hash {
+				result.Valid, result.BrokenAt, result.Reason = false, e.EventID, "record hash does not match"
+				return result, nil
+			}
+			prev = e.Hash
+		}
+	}
+}
+
+// prepare fills the defaults of an entry and masks its sensitive fields
+func prepare(e *Entry) {
+	if e.EventID == "" {
+		e.EventID = uuid.New().String()
+	}
+	if e.UserID == "" {
+		e.UserID = Anonymous
+	}
+	if e.Severity == "" {
+		e.Severity = SeverityMedium
+	}
+	if e.Hostname == "" {
+		e.Hostname = hostname
+	}
+	if e.Timestamp == 0 {
+		e.Timestamp = time.Now().UnixMilli()
+	}
+
+	// Diff before masking, so that a changed secret still shows up as changed
+	before, after := toMap(normalize(e.Before)), toMap(normalize(e.After))
+	if e.Diff == nil && (before != nil || after != nil) {
+		e.Diff = Diff(before, after)
+	}
+	e.Before = Mask(before)
+	e.After = Mask(after)
+	e.Details = Mask(toMap(normalize(e.Details)))
+}
+
+// lastHash returns the hash of the latest record, empty when the log is empty
+func lastHash(mod *model.Model) (string, error) {
+	row, err := capsule.Query().Table(mod.MetaData.Table.Name).
+		Select("hash").
+		OrderBy("id", "desc").
+		First()
+	if err != nil {
+		return "", fmt.Errorf("failed to read the last audit hash: %w", err)
+	}
+	if row == nil {
+		return "", nil
+	}
+	hash, _ := row.ToMap()["hash"].(string)
+	return hash, nil
+}
+
+// normalize round-trips a value through JSON so that it hashes the same
+// before it is stored and after it is read back
+func normalize(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	switch m := v.(type) {
+	case map[string]interface{}:
+		if m == nil {
+			return nil
+		}
+	case map[string]Change:
+		if m == nil {
+			return nil
+		}
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var out interface{}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil
+	}
+	return out
+}
+
+// ToMap converts a struct or map to the map form used by Before and After, nil if it is not an object
+func ToMap(v interface{}) map[string]interface{} {
+	return toMap(normalize(v))
+}
+
+func toMap(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
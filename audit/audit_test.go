@@ -0,0 +1,130 @@
+package audit_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaoapp/gou/model"
+	"github.com/yaoapp/xun/capsule"
+	"github.com/yaoapp/yao/audit"
+	"github.com/yaoapp/yao/config"
+	"github.com/yaoapp/yao/test"
+)
+
+func TestRecordAndQuery(t *testing.T) {
+	test.Prepare(t, config.Conf)
+	defer test.Clean()
+
+	ctx := context.Background()
+	start := time.Now().Add(-time.Second)
+
+	login := &audit.Entry{
+		Operation: audit.OpLogin,
+		Category:  audit.CategoryAuthentication,
+		UserID:    "audit_test_user",
+		TeamID:    "audit_test_team",
+		ClientIP:  "127.0.0.1",
+		Success:   true,
+		Details:   map[string]interface{}{"auth_source": "password"},
+	}
+	require.NoError(t, audit.Record(ctx, login))
+	assert.NotEmpty(t, login.EventID)
+	assert.Len(t, login.Hash, 64)
+
+	update := &audit.Entry{
+		Operation:      audit.OpRobotUpdate,
+		Category:       audit.CategoryConfig,
+		UserID:         "audit_test_user",
+		TeamID:         "audit_test_team",
+		TargetResource: "robot_1",
+		ResourceType:   "robot",
+		Success:        true,
+		Before:         map[string]interface{}{"name": "Old", "api_key": "sk-1"},
+		After:          map[string]interface{}{"name": "New", "api_key": "sk-2"},
+	}
+	require.NoError(t, audit.Record(ctx, update))
+	assert.Equal(t, login.Hash, update.PrevHash)
+
+	t.Run("get", func(t *testing.T) {
+		e, err := audit.Get(ctx, update.EventID)
+		require.NoError(t, err)
+		require.NotNil(t, e)
+		assert.Equal(t, "robot_1", e.TargetResource)
+		assert.Equal(t, audit.Change{Before: "Old", After: "New"}, e.Diff["name"])
+		assert.Equal(t, audit.Masked, e.After["api_key"])
+		assert.Equal(t, update.Hash, e.Hash)
+
+		e, err = audit.Get(ctx, "not_exists")
+		require.NoError(t, err)
+		assert.Nil(t, e)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		result, err := audit.List(ctx, &audit.Query{UserID: "audit_test_user", From: start})
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Total)
+		assert.Equal(t, update.EventID, result.Data[0].EventID)
+
+		result, err = audit.List(ctx, &audit.Query{TargetResource: "robot_1", ResourceType: "robot"})
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Total)
+
+		result, err = audit.List(ctx, &audit.Query{UserID: "audit_test_user", To: start})
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Total)
+	})
+
+	t.Run("export", func(t *testing.T) {
+		var buf bytes.Buffer
+		n, err := audit.Export(ctx, &audit.Query{TeamID: "audit_test_team"}, &buf, audit.FormatCSV)
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+		records, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		assert.Len(t, records, 3)
+		assert.Equal(t, login.EventID, records[1][0])
+
+		buf.Reset()
+		n, err = audit.Export(ctx, &audit.Query{TeamID: "audit_test_team"}, &buf, audit.FormatJSONL)
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+		scanner := bufio.NewScanner(&buf)
+		require.True(t, scanner.Scan())
+		var first audit.Entry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &first))
+		assert.Equal(t, login.EventID, first.EventID)
+
+		_, err = audit.Export(ctx, nil, &buf, "xml")
+		assert.Error(t, err)
+	})
+
+	t.Run("verify", func(t *testing.T) {
+		result, err := audit.Verify(ctx)
+		require.NoError(t, err)
+		assert.True(t, result.Valid, result.Reason)
+		assert.GreaterOrEqual(t, result.Checked, 2)
+
+		// Tampering with a stored record breaks the chain
+		table := model.Select(audit.ModelID).MetaData.Table.Name
+		_, err = capsule.Query().Table(table).Where("event_id", login.EventID).Update(map[string]interface{}{"success": false})
+		require.NoError(t, err)
+		defer capsule.Query().Table(table).Where("event_id", login.EventID).Update(map[string]interface{}{"success": true})
+
+		result, err = audit.Verify(ctx)
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, login.EventID, result.BrokenAt)
+	})
+}
+
+func TestRecordValidation(t *testing.T) {
+	assert.Error(t, audit.Record(context.Background(), nil))
+	assert.Error(t, audit.Record(context.Background(), &audit.Entry{UserID: "user_1"}))
+}
@@ -0,0 +1,53 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaoapp/gou/model"
+	"github.com/yaoapp/yao/config"
+	"github.com/yaoapp/yao/test"
+)
+
+// TestRecordStaleHead tests that an append racing another instance relinks to the new head
+func TestRecordStaleHead(t *testing.T) {
+	test.Prepare(t, config.Conf)
+	defer test.Clean()
+
+	ctx := context.Background()
+	first := &Entry{Operation: OpLogin, UserID: "chain_test_user", Success: true}
+	require.NoError(t, Record(ctx, first))
+	second := &Entry{Operation: OpLogin, UserID: "chain_test_user", Success: true}
+	require.NoError(t, Record(ctx, second))
+
+	// Another instance linking to an already linked head cannot fork the chain
+	mod := model.Select(ModelID)
+	fork := &Entry{Operation: OpLogin, UserID: "chain_test_user", Success: true}
+	prepare(fork)
+	fork.PrevHash = first.Hash
+	fork.Hash, _ = Hash(fork)
+	_, err := mod.Create(toRow(fork))
+	assert.Error(t, err)
+
+	// The head read before the second entry was appended
+	stale := true
+	chainHead = func(mod *model.Model) (string, error) {
+		if stale {
+			stale = false
+			return first.Hash, nil
+		}
+		return lastHash(mod)
+	}
+	defer func() { chainHead = lastHash }()
+
+	third := &Entry{Operation: OpLogin, UserID: "chain_test_user", Success: true}
+	require.NoError(t, Record(ctx, third))
+	assert.False(t, stale)
+	assert.Equal(t, second.Hash, third.PrevHash)
+
+	result, err := Verify(ctx)
+	require.NoError(t, err)
+	assert.True(t, result.Valid, result.Reason)
+}
@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Masked replaces the value of sensitive fields
+const Masked = "******"
+
+// sensitiveFields are masked in before/after data, diffs and details
+var sensitiveFields = map[string]bool{
+	"password":      true,
+	"password_hash": true,
+	"secret":        true,
+	"client_secret": true,
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"api_key":       true,
+	"private_key":   true,
+	"mfa_secret":    true,
+}
+
+// Diff returns the top-level fields that differ between before and after
+// A field only in before has a nil after, and the other way round.
+func Diff(before, after map[string]interface{}) map[string]Change {
+	diff := map[string]Change{}
+	for key, b := range before {
+		a, ok := after[key]
+		if !ok || !reflect.DeepEqual(a, b) {
+			diff[key] = Change{Before: b, After: a}
+		}
+	}
+	for key, a := range after {
+		if _, ok := before[key]; !ok {
+			diff[key] = Change{Before: nil, After: a}
+		}
+	}
+
+	for key, change := range diff {
+		if isSensitive(key) {
+			diff[key] = Change{Before: maskValue(change.Before), After: maskValue(change.After)}
+			continue
+		}
+		diff[key] = Change{Before: maskNested(change.Before), After: maskNested(change.After)}
+	}
+	return diff
+}
+
+// Mask returns a copy of data with the sensitive fields masked, nested maps included
+func Mask(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+	masked := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		if isSensitive(key) {
+			masked[key] = maskValue(value)
+			continue
+		}
+		masked[key] = maskNested(value)
+	}
+	return masked
+}
+
+func maskNested(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return Mask(v)
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = maskNested(item)
+		}
+		return items
+	}
+	return value
+}
+
+// maskValue keeps nil and empty values so that a diff still shows a secret was set or cleared
+func maskValue(value interface{}) interface{} {
+	if value == nil || value == "" {
+		return value
+	}
+	return Masked
+}
+
+func isSensitive(key string) bool {
+	return sensitiveFields[strings.ToLower(key)]
+}
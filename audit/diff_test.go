@@ -0,0 +1,83 @@
+package audit_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaoapp/yao/audit"
+)
+
+func TestDiff(t *testing.T) {
+	before := map[string]interface{}{"name": "Robot", "role_id": "member", "bio": "old", "password": "secret-1"}
+	after := map[string]interface{}{"name": "Robot", "role_id": "admin", "avatar": "a.png", "password": "secret-2"}
+
+	diff := audit.Diff(before, after)
+	assert.Len(t, diff, 4)
+	assert.NotContains(t, diff, "name")
+	assert.Equal(t, audit.Change{Before: "member", After: "admin"}, diff["role_id"])
+	assert.Equal(t, audit.Change{Before: "old", After: nil}, diff["bio"])
+	assert.Equal(t, audit.Change{Before: nil, After: "a.png"}, diff["avatar"])
+
+	// A changed secret shows up as changed, without its values
+	assert.Equal(t, audit.Change{Before: audit.Masked, After: audit.Masked}, diff["password"])
+
+	assert.Empty(t, audit.Diff(nil, nil))
+}
+
+func TestMask(t *testing.T) {
+	masked := audit.Mask(map[string]interface{}{
+		"name":    "Robot",
+		"API_KEY": "sk-123",
+		"token":   "",
+		"settings": map[string]interface{}{
+			"client_secret": "abc",
+			"list":          []interface{}{map[string]interface{}{"password": "p"}},
+		},
+	})
+
+	assert.Equal(t, "Robot", masked["name"])
+	assert.Equal(t, audit.Masked, masked["API_KEY"])
+	assert.Equal(t, "", masked["token"])
+
+	settings := masked["settings"].(map[string]interface{})
+	assert.Equal(t, audit.Masked, settings["client_secret"])
+	assert.Equal(t, audit.Masked, settings["list"].([]interface{})[0].(map[string]interface{})["password"])
+
+	assert.Nil(t, audit.Mask(nil))
+}
+
+func TestHash(t *testing.T) {
+	e := &audit.Entry{
+		EventID:   "event_1",
+		Operation: audit.OpLogin,
+		UserID:    "user_1",
+		Success:   true,
+		Details:   map[string]interface{}{"auth_source": "password", "count": 1},
+		Timestamp: 1700000000000,
+	}
+
+	hash, err := audit.Hash(e)
+	require.NoError(t, err)
+	assert.Len(t, hash, 64)
+
+	// Same content hashes the same, whatever the Go types of the values
+	same := *e
+	same.Details = map[string]interface{}{"count": float64(1), "auth_source": "password"}
+	sameHash, err := audit.Hash(&same)
+	require.NoError(t, err)
+	assert.Equal(t, hash, sameHash)
+
+	// Any change of the content or of the link changes the hash
+	changed := *e
+	changed.Success = false
+	changedHash, err := audit.Hash(&changed)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, changedHash)
+
+	linked := *e
+	linked.PrevHash = hash
+	linkedHash, err := audit.Hash(&linked)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, linkedHash)
+}
@@ -0,0 +1,379 @@
+package audit
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yaoapp/gou/model"
+	"github.com/yaoapp/xun/capsule"
+	"github.com/yaoapp/xun/dbal/query"
+)
+
+// Export formats
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+// MaxExportRows caps the number of entries written by Export
+var MaxExportRows = 100000
+
+// csvColumns are the columns written by a CSV export, in order
+var csvColumns = []string{
+	"event_id", "timestamp", "operation", "category", "severity", "success",
+	"user_id", "user_name", "team_id", "client_ip", "user_agent",
+	"target_resource", "resource_type", "source", "request_id",
+	"error_message", "diff", "hash", "prev_hash",
+}
+
+// List returns a page of the audit log matching the query, newest first
+func List(ctx context.Context, q *Query) (*ListResult, error) {
+	if q == nil {
+		q = &Query{}
+	}
+	page, pageSize := q.Page, q.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	// The builder is mutated by each call, so count and fetch use their own
+	countQB, err := newQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	total, err := countQB.Count()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+
+	qb, err := newQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := qb.OrderBy("id", "desc").Limit(pageSize).Offset((page - 1) * pageSize).Get()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	data := make([]*Entry, 0, len(rows))
+	for _, row := range rows {
+		data = append(data, fromRow(map[string]interface{}(row)))
+	}
+	return &ListResult{Data: data, Total: int(total), Page: page, PageSize: pageSize}, nil
+}
+
+// Get returns an audit entry by its event ID, nil if not found
+func Get(ctx context.Context, eventID string) (*Entry, error) {
+	qb, err := newQuery(&Query{})
+	if err != nil {
+		return nil, err
+	}
+	row, err := qb.Where("event_id", eventID).First()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit entry: %w", err)
+	}
+	if row == nil || len(row.ToMap()) == 0 {
+		return nil, nil
+	}
+	return fromRow(row.ToMap()), nil
+}
+
+// Export writes the entries matching the query to w as CSV or JSON lines, oldest first
+// Returns the number of entries written, at most MaxExportRows.
+func Export(ctx context.Context, q *Query, w io.Writer, format string) (int, error) {
+	if q == nil {
+		q = &Query{}
+	}
+	if format == "" {
+		format = FormatCSV
+	}
+	if format != FormatCSV && format != FormatJSONL {
+		return 0, fmt.Errorf("unsupported export format: %s", format)
+	}
+
+	var csvWriter *csv.Writer
+	if format == FormatCSV {
+		csvWriter = csv.NewWriter(w)
+		if err := csvWriter.Write(csvColumns); err != nil {
+			return 0, err
+		}
+	}
+
+	written := 0
+	var lastID int64
+	for written < MaxExportRows {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+
+		qb, err := newQuery(q)
+		if err != nil {
+			return written, err
+		}
+		limit := 500
+		if MaxExportRows-written < limit {
+			limit = MaxExportRows - written
+		}
+		rows, err := qb.Where("id", ">", lastID).OrderBy("id", "asc").Limit(limit).Get()
+		if err != nil {
+			return written, fmt.Errorf("failed to export audit entries: %w", err)
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		for _, row := range rows {
+			e := fromRow(map[string]interface{}(row))
+			lastID = e.ID
+			if csvWriter != nil {
+				err = csvWriter.Write(csvRecord(e))
+			} else {
+				err = writeJSONLine(w, e)
+			}
+			if err != nil {
+				return written, err
+			}
+			written++
+		}
+	}
+
+	if csvWriter != nil {
+		csvWriter.Flush()
+		return written, csvWriter.Error()
+	}
+	return written, nil
+}
+
+// newQuery returns a query on the audit log with the filters applied
+func newQuery(q *Query) (query.Query, error) {
+	mod := model.Select(ModelID)
+	if mod == nil {
+		return nil, fmt.Errorf("model %s not found", ModelID)
+	}
+
+	qb := capsule.Query().Table(mod.MetaData.Table.Name)
+	where := func(column string, value string) {
+		if value != "" {
+			qb.Where(column, value)
+		}
+	}
+	where("user_id", q.UserID)
+	where("team_id", q.TeamID)
+	where("operation", q.Operation)
+	where("category", q.Category)
+	where("target_resource", q.TargetResource)
+	where("resource_type", q.ResourceType)
+	if q.Success != nil {
+		qb.Where("success", *q.Success)
+	}
+	if !q.From.IsZero() {
+		qb.Where("timestamp", ">=", q.From.UnixMilli())
+	}
+	if !q.To.IsZero() {
+		qb.Where("timestamp", "<", q.To.UnixMilli())
+	}
+	return qb, nil
+}
+
+func csvRecord(e *Entry) []string {
+	diff := ""
+	if len(e.Diff) > 0 {
+		if raw, err := json.Marshal(e.Diff); err == nil {
+			diff = string(raw)
+		}
+	}
+	return []string{
+		e.EventID, time.UnixMilli(e.Timestamp).UTC().Format(time.RFC3339Nano), e.Operation, e.Category, e.Severity, strconv.FormatBool(e.Success),
+		e.UserID, e.UserName, e.TeamID, e.ClientIP, e.UserAgent,
+		e.TargetResource, e.ResourceType, e.Source, e.RequestID,
+		e.ErrorMessage, diff, e.Hash, e.PrevHash,
+	}
+}
+
+func writeJSONLine(w io.Writer, e *Entry) error {
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(raw, '\n'))
+	return err
+}
+
+func toRow(e *Entry) map[string]interface{} {
+	row := map[string]interface{}{
+		"event_id":  e.EventID,
+		"operation": e.Operation,
+		"severity":  e.Severity,
+		"user_id":   e.UserID,
+		"success":   e.Success,
+		"timestamp": e.Timestamp,
+		"hash":      e.Hash,
+		"prev_hash": e.PrevHash, // The first entry links to "", so that it is unique as well
+	}
+	set := func(key string, value string) {
+		if value != "" {
+			row[key] = value
+		}
+	}
+	set("category", e.Category)
+	set("user_name", e.UserName)
+	set("team_id", e.TeamID)
+	set("session_id", e.SessionID)
+	set("client_ip", e.ClientIP)
+	set("user_agent", e.UserAgent)
+	set("target_resource", e.TargetResource)
+	set("resource_type", e.ResourceType)
+	set("source", e.Source)
+	set("application", e.Application)
+	set("hostname", e.Hostname)
+	set("request_id", e.RequestID)
+	set("trace_id", e.TraceID)
+	set("error_message", e.ErrorMessage)
+	if e.Before != nil {
+		row["data_before"] = e.Before
+	}
+	if e.After != nil {
+		row["data_after"] = e.After
+	}
+	if e.Diff != nil {
+		row["diff"] = e.Diff
+	}
+	if e.Details != nil {
+		row["details"] = e.Details
+	}
+	if len(e.Tags) > 0 {
+		row["tags"] = e.Tags
+	}
+	return row
+}
+
+func fromRow(row map[string]interface{}) *Entry {
+	str := func(key string) string {
+		if v, ok := row[key].(string); ok {
+			return v
+		}
+		return ""
+	}
+	e := &Entry{
+		ID:             toInt64(row["id"]),
+		EventID:        str("event_id"),
+		Category:       str("category"),
+		Severity:       str("severity"),
+		Operation:      str("operation"),
+		TargetResource: str("target_resource"),
+		ResourceType:   str("resource_type"),
+		Success:        toBool(row["success"]),
+		ErrorMessage:   str("error_message"),
+		UserID:         str("user_id"),
+		UserName:       str("user_name"),
+		TeamID:         str("team_id"),
+		SessionID:      str("session_id"),
+		ClientIP:       str("client_ip"),
+		UserAgent:      str("user_agent"),
+		Source:         str("source"),
+		Application:    str("application"),
+		Hostname:       str("hostname"),
+		RequestID:      str("request_id"),
+		TraceID:        str("trace_id"),
+		Timestamp:      toInt64(row["timestamp"]),
+		PrevHash:       str("prev_hash"),
+		Hash:           str("hash"),
+		CreatedAt:      toTime(row["created_at"]),
+	}
+	decode(row["data_before"], &e.Before)
+	decode(row["data_after"], &e.After)
+	decode(row["diff"], &e.Diff)
+	decode(row["details"], &e.Details)
+	decode(row["tags"], &e.Tags)
+	return e
+}
+
+// decode reads a JSON column, which is a string or []byte from the driver or already decoded
+func decode(value interface{}, out interface{}) {
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		return
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		var err error
+		if raw, err = json.Marshal(v); err != nil {
+			return
+		}
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return
+	}
+	_ = json.Unmarshal(raw, out)
+}
+
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int64:
+		return n
+	case int:
+		return int64(n)
+	case int32:
+		return int64(n)
+	case uint64:
+		return int64(n)
+	case float64:
+		return int64(n)
+	case []byte:
+		i, _ := strconv.ParseInt(string(n), 10, 64)
+		return i
+	case string:
+		i, _ := strconv.ParseInt(n, 10, 64)
+		return i
+	}
+	return 0
+}
+
+func toBool(v interface{}) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case int64:
+		return b != 0
+	case int:
+		return b != 0
+	case float64:
+		return b != 0
+	case []byte:
+		return string(b) == "1" || strings.EqualFold(string(b), "true")
+	case string:
+		return b == "1" || strings.EqualFold(b, "true")
+	}
+	return false
+}
+
+func toTime(v interface{}) *time.Time {
+	switch t := v.(type) {
+	case time.Time:
+		return &t
+	case *time.Time:
+		return t
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02T15:04:05"} {
+			if parsed, err := time.Parse(layout, t); err == nil {
+				return &parsed
+			}
+		}
+	}
+	return nil
+}
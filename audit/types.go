@@ -0,0 +1,131 @@
+package audit
+
+import "time"
+
+// ModelID is the model that stores the audit log
+const ModelID = "__yao.audit"
+
+// Anonymous is the actor of entries without a known user, e.g. a failed login of an unknown account
+const Anonymous = "anonymous"
+
+// Categories
+const (
+	CategoryAuthentication = "authentication" // login, logout, token issuance
+	CategoryAuthorization  = "authorization"  // team membership, roles
+	CategoryConfig         = "config"         // assistant, robot and DSL changes
+	CategoryData           = "data"           // files and records
+	CategorySystem         = "system"
+)
+
+// Severities
+const (
+	SeverityLow      = "low"
+	SeverityMedium   = "medium"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+// Operations
+const (
+	OpLogin            = "login"
+	OpTokenIssue       = "token.issue"
+	OpMFAEnable        = "mfa.enable"
+	OpMFADisable       = "mfa.disable"
+	OpMFAVerify        = "mfa.verify"
+	OpAPIKeyCreate     = "api_key.create"
+	OpAPIKeyUpdate     = "api_key.update"
+	OpAPIKeyRegenerate = "api_key.regenerate"
+	OpAPIKeyRevoke     = "api_key.revoke"
+	OpMemberUpdate     = "member.update"
+	OpMemberDelete     = "member.delete"
+	OpAssistantCreate  = "assistant.create"
+	OpAssistantUpdate  = "assistant.update"
+	OpRobotCreate      = "robot.create"
+	OpRobotUpdate      = "robot.update"
+	OpRobotDelete      = "robot.delete"
+	OpDSLCreate        = "dsl.create"
+	OpDSLUpdate        = "dsl.update"
+	OpDSLDelete        = "dsl.delete"
+	OpDSLRollback      = "dsl.rollback"
+	OpFileDelete       = "file.delete"
+)
+
+// Entry is an audit record: who did what, on which resource, and what changed
+type Entry struct {
+	ID       int64  `json:"id,omitempty"`
+	EventID  string `json:"event_id"`
+	Category string `json:"category,omitempty"`
+	Severity string `json:"severity,omitempty"`
+
+	// What
+	Operation      string `json:"operation"`
+	TargetResource string `json:"target_resource,omitempty"` // e.g. robot member ID, file ID, DSL ID
+	ResourceType   string `json:"resource_type,omitempty"`   // e.g. robot, file, model
+	Success        bool   `json:"success"`
+	ErrorMessage   string `json:"error_message,omitempty"`
+
+	// Who
+	UserID    string `json:"user_id"`
+	UserName  string `json:"user_name,omitempty"`
+	TeamID    string `json:"team_id,omitempty"`
+	SessionID string `json:"session_id,omitempty"`
+	ClientIP  string `json:"client_ip,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+
+	// Where
+	Source      string `json:"source,omitempty"` // UI, API, CLI, system
+	Application string `json:"application,omitempty"`
+	Hostname    string `json:"hostname,omitempty"`
+	RequestID   string `json:"request_id,omitempty"`
+	TraceID     string `json:"trace_id,omitempty"`
+
+	// Changes, sensitive fields are masked before they are stored
+	Before  map[string]interface{} `json:"data_before,omitempty"`
+	After   map[string]interface{} `json:"data_after,omitempty"`
+	Diff    map[string]Change      `json:"diff,omitempty"` // computed from Before and After when empty
+	Details map[string]interface{} `json:"details,omitempty"`
+	Tags    []string               `json:"tags,omitempty"`
+
+	// Chain
+	Timestamp int64      `json:"timestamp"` // Unix milliseconds
+	PrevHash  string     `json:"prev_hash,omitempty"`
+	Hash      string     `json:"hash"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// Change is the before and after value of a changed field
+type Change struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Query filters the audit log
+type Query struct {
+	UserID         string    `json:"user_id,omitempty"` // actor
+	TeamID         string    `json:"team_id,omitempty"`
+	Operation      string    `json:"operation,omitempty"`
+	Category       string    `json:"category,omitempty"`
+	TargetResource string    `json:"target_resource,omitempty"`
+	ResourceType   string    `json:"resource_type,omitempty"`
+	Success        *bool     `json:"success,omitempty"`
+	From           time.Time `json:"from,omitempty"` // inclusive
+	To             time.Time `json:"to,omitempty"`   // exclusive
+	Page           int       `json:"page,omitempty"`
+	PageSize       int       `json:"pagesize,omitempty"`
+}
+
+// ListResult is a page of audit entries, newest first
+type ListResult struct {
+	Data     []*Entry `json:"data"`
+	Total    int      `json:"total"`
+	Page     int      `json:"page"`
+	PageSize int      `json:"pagesize"`
+}
+
+// VerifyResult is the result of a hash chain check
+type VerifyResult struct {
+	Valid    bool   `json:"valid"`
+	Checked  int    `json:"checked"`
+	BrokenAt string `json:"broken_at,omitempty"` // event ID of the first record that does not match
+	Reason   string `json:"reason,omitempty"`
+}
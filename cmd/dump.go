@@ -1,10 +1,8 @@
 package cmd
 
 import (
-	"archive/zip"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,13 +10,18 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
-	"github.com/yaoapp/gou/model"
 	"github.com/yaoapp/kun/exception"
 	"github.com/yaoapp/yao/config"
+	"github.com/yaoapp/yao/dump"
 	"github.com/yaoapp/yao/engine"
 )
 
-var dumpModel string
+var dumpModels []string
+var dumpWhere string
+var dumpSince string
+var dumpAttachments bool
+var dumpKB bool
+var dumpChats bool
 var dumpCmd = &cobra.Command{
 	Use:   "dump",
 	Short: L("Dump the application data"),
@@ -69,143 +72,79 @@ var dumpCmd = &cobra.Command{
 			}
 		}
 
-		if dumpModel != "" {
-			fmt.Println(color.YellowString(L("Not supported yet")))
-			os.Exit(1)
-			return
+		option := dump.Option{
+			Selection: dump.Selection{
+				Names:       dumpModels,
+				Attachments: dumpAttachments,
+				KB:          dumpKB,
+				Chats:       dumpChats,
+			},
+			Where: dumpWhere,
 		}
 
-		// Export models
-		files := []string{}
-		for _, mod := range model.Models {
-
-			fmt.Printf("\r%s", color.GreenString(L("Export the models: %s (%s)"), mod.Name, mod.MetaData.Table.Name))
-			jsonfiles, err := mod.Export(5000, func(curr, total int) {
-				fmt.Printf("\r%s", strings.Repeat(" ", 80))
-				fmt.Printf("\r%s", color.GreenString(L("Export the models: %s (%s) %d/%d"), mod.Name, mod.MetaData.Table.Name, curr, total))
-			})
-
+		if dumpSince != "" {
+			since, err := parseSince(dumpSince)
 			if err != nil {
 				fmt.Println(color.RedString(L("Fatal: %s"), err.Error()))
 				os.Exit(1)
 			}
-			files = append(files, jsonfiles...)
+			option.Since = &since
 		}
-		fmt.Printf("\r%s", strings.Repeat(" ", 80))
-		fmt.Printf("\r%s\n", color.GreenString(L("Export the models: ✨DONE✨")))
 
-		// Compress files
-		err = zipfiles(files, output, func(file string) {
+		// Export models and compress files
+		stage := ""
+		manifest, err := dump.Dump(output, option, func(curr string, name string, index int, total int) {
+			if curr != stage {
+				if stage != "" {
+					fmt.Printf("\r%s", strings.Repeat(" ", 80))
+					fmt.Printf("\r%s\n", color.GreenString("%s%s", dumpStages[stage], L("✨DONE✨")))
+				}
+				stage = curr
+			}
 			fmt.Printf("\r%s", strings.Repeat(" ", 80))
-			fmt.Printf("\r%s", color.GreenString(L("Compress the files: %s"), file))
+			fmt.Printf("\r%s", color.GreenString("%s%s %d/%d", dumpStages[curr], name, index, total))
 		})
-		fmt.Printf("\r%s", strings.Repeat(" ", 80))
-		fmt.Printf("\r%s\n", color.GreenString(L("Compress the files: ✨DONE✨")))
+		if stage != "" {
+			fmt.Printf("\r%s", strings.Repeat(" ", 80))
+			fmt.Printf("\r%s\n", color.GreenString("%s%s", dumpStages[stage], L("✨DONE✨")))
+		}
 
 		if err != nil {
 			fmt.Println(color.RedString(L("Fatal: %s"), err.Error()))
 			os.Exit(1)
 		}
 
+		for _, mod := range manifest.Models {
+			fmt.Println(color.WhiteString("  %s (%s) %d", mod.Name, mod.Table, mod.Rows))
+		}
 		fmt.Println(color.GreenString("File: %s", output))
 	},
 }
 
-// func init() {
-// 	// dumpCmd.PersistentFlags().StringVarP(&dumpModel, "name", "n", "", L("Model name"))
-// }
-
-// gzipfiles
-func zipfiles(files []string, output string, process func(file string)) error {
-	outpath := filepath.Dir(output)
-	os.MkdirAll(outpath, 0755)
-
-	outfile, err := os.Create(output)
-	if err != nil {
-		fmt.Println(color.RedString(L("Fatal: %s"), err.Error()))
-		os.Exit(1)
-	}
-	defer outfile.Close()
-
-	w := zip.NewWriter(outfile)
-	defer func() {
-		w.Close()
-		if err != nil {
-			fmt.Println(color.RedString(L("Fatal: %s"), err.Error()))
-			os.Exit(1)
-		}
-	}()
-
-	for _, file := range files {
-		addFile(w, file, "model", process)
-	}
-
-	// Add data path
-	dataPath := filepath.Join(config.Conf.Root, "data")
-	_, err = os.Stat(dataPath)
-	if err == nil {
-		addFolder(w, dataPath, "data", process)
-	}
-
-	return nil
+var dumpStages = map[string]string{
+	"export":   L("Export the models: "),
+	"compress": L("Compress the files: "),
 }
 
-func addFile(w *zip.Writer, file, baseInZip string, process func(file string)) {
-	process(filepath.Join(baseInZip, filepath.Base(file)))
-	dat, err := ioutil.ReadFile(file)
-	if err != nil {
-		fmt.Println(color.RedString(L("Fatal: %s"), err.Error()))
-		os.Exit(1)
-	}
-
-	// Add some files to the archive.
-	f, err := w.Create(filepath.Join(baseInZip, filepath.Base(file)))
-	if err != nil {
-		fmt.Println(color.RedString(L("Fatal: %s"), err.Error()))
-		os.Exit(1)
-	}
-	_, err = f.Write(dat)
-	if err != nil {
-		fmt.Println(color.RedString(L("Fatal: %s"), err.Error()))
-		os.Exit(1)
-	}
-
-	os.Remove(file)
+func init() {
+	dumpCmd.PersistentFlags().StringSliceVarP(&dumpModels, "name", "n", nil, L("Model names or glob patterns"))
+	dumpCmd.PersistentFlags().StringVarP(&dumpWhere, "where", "w", "", L("SQL where clause of the named models"))
+	dumpCmd.PersistentFlags().StringVarP(&dumpSince, "since", "s", "", L("Only dump the rows changed since the time"))
+	dumpCmd.PersistentFlags().BoolVarP(&dumpAttachments, "attachments", "", false, L("Include the attachments"))
+	dumpCmd.PersistentFlags().BoolVarP(&dumpKB, "kb", "", false, L("Include the knowledge base collections"))
+	dumpCmd.PersistentFlags().BoolVarP(&dumpChats, "chats", "", false, L("Include the agent chat history"))
 }
 
-func addFolder(w *zip.Writer, basePath, baseInZip string, process func(file string)) {
-
-	// Open the Directory
-	files, err := ioutil.ReadDir(basePath)
-	if err != nil {
-		fmt.Println(color.RedString(L("Fatal: %s"), err.Error()))
-		os.Exit(1)
+// parseSince parses the since time, RFC3339 or a local date time
+func parseSince(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
 	}
 
-	for _, file := range files {
-		process(filepath.Join(baseInZip, file.Name()))
-		if !file.IsDir() {
-			dat, err := ioutil.ReadFile(filepath.Join(basePath, file.Name()))
-			if err != nil {
-				fmt.Println(color.RedString(L("Fatal: %s"), err.Error()))
-				os.Exit(1)
-			}
-
-			// Add some files to the archive.
-			f, err := w.Create(filepath.Join(baseInZip, file.Name()))
-			if err != nil {
-				fmt.Println(color.RedString(L("Fatal: %s"), err.Error()))
-				os.Exit(1)
-			}
-			_, err = f.Write(dat)
-			if err != nil {
-				fmt.Println(color.RedString(L("Fatal: %s"), err.Error()))
-				os.Exit(1)
-			}
-		} else if file.IsDir() {
-			// Recurse
-			newBase := filepath.Join(basePath, file.Name())
-			addFolder(w, newBase, filepath.Join(baseInZip, file.Name()), process)
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
 		}
 	}
+	return time.Time{}, fmt.Errorf("invalid since time %s", value)
 }
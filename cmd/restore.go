@@ -1,27 +1,29 @@
 package cmd
 
 import (
-	"archive/zip"
-	"errors"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/yaoapp/gou/model"
 	"github.com/yaoapp/kun/exception"
 	"github.com/yaoapp/yao/config"
+	"github.com/yaoapp/yao/dump"
 	"github.com/yaoapp/yao/engine"
 	"github.com/yaoapp/yao/share"
 )
 
 var restoreForce bool = false
 var migrateNoInsert bool = false
+var restoreModelNames []string
+var restoreUpsert bool
+var restoreAttachments bool
+var restoreKB bool
+var restoreChats bool
 var restoreCmd = &cobra.Command{
 	Use:   "restore",
 	Short: L("Restore the application data"),
@@ -54,10 +56,32 @@ var restoreCmd = &cobra.Command{
 		}
 
 		// Unzip files
-		dst := unzipFile(zipfile, func(file string) {
-			fmt.Printf("\r%s", strings.Repeat(" ", 80))
-			fmt.Printf("\r%s", color.GreenString(L("Unzip the file: %s"), file))
-		})
+		archive, err := dump.Open(zipfile)
+		if err != nil {
+			fmt.Println(color.RedString(L("Fatal: %s"), err.Error()))
+			os.Exit(1)
+		}
+		defer archive.Close()
+
+		selection := dump.Selection{
+			Names:       restoreModelNames,
+			Attachments: restoreAttachments,
+			KB:          restoreKB,
+			Chats:       restoreChats,
+		}
+
+		// Verify the manifest checksum before touching data
+		legacy := archive.Manifest == nil
+		if legacy {
+			if len(selection.Names) > 0 || selection.Attachments || selection.KB || selection.Chats || restoreUpsert {
+				archive.Close()
+				exception.New(L("The archive has no manifest, only the full restore is supported."), 400).Throw()
+			}
+			fmt.Println(color.YellowString(L("The archive has no manifest, the checksum is not verified.")))
+		} else if err := archive.Verify(); err != nil {
+			archive.Close()
+			exception.New(err.Error(), 400).Throw()
+		}
 
 		// 加载数据模型
 		loadWarnings, err := engine.Load(config.Conf, engine.LoadOption{Action: "restore"})
@@ -72,16 +96,40 @@ var restoreCmd = &cobra.Command{
 			}
 		}
 
-		// Restore models
-		restoreModels(filepath.Join(dst, "model"), []model.MigrateOption{
-			model.WithDonotInsertValues(migrateNoInsert),
-		})
+		if legacy {
+			// Restore models
+			restoreModels(filepath.Join(archive.Dir, "model"), []model.MigrateOption{
+				model.WithDonotInsertValues(migrateNoInsert),
+			})
 
-		// Restore Data
-		restoreData(filepath.Join(dst, "data"))
+			// Restore Data
+			restoreData(filepath.Join(archive.Dir, "data"))
+
+			fmt.Println(color.GreenString(L("✨DONE✨")))
+			return
+		}
+
+		err = archive.Restore(dump.RestoreOption{
+			Selection:       selection,
+			Upsert:          restoreUpsert,
+			MigrateNoInsert: migrateNoInsert,
+		}, func(stage string, name string, curr int, total int) {
+			fmt.Printf("\r%s", strings.Repeat(" ", 80))
+			switch stage {
+			case "migrate":
+				fmt.Print(color.GreenString(fmt.Sprintf(L("\rUpdate schema model: %s (%s) "), name, fmt.Sprintf("%d/%d", curr, total))))
+			case "restore":
+				fmt.Print(color.GreenString(fmt.Sprintf(L("\rRestore model: %s (%s) "), name, fmt.Sprintf("%d/%d", curr, total))))
+			case "files":
+				fmt.Print(color.GreenString(fmt.Sprintf(L("\rRestore the file: %s (%s) "), name, fmt.Sprintf("%d/%d", curr, total))))
+			}
+		})
+		fmt.Println("")
 
-		// Clean
-		os.RemoveAll(dst)
+		if err != nil {
+			fmt.Println(color.RedString(L("Fatal: %s"), err.Error()))
+			os.Exit(1)
+		}
 
 		fmt.Println(color.GreenString(L("✨DONE✨")))
 	},
@@ -90,6 +138,11 @@ var restoreCmd = &cobra.Command{
 func init() {
 	restoreCmd.PersistentFlags().BoolVarP(&restoreForce, "force", "", false, L("Force restore"))
 	restoreCmd.PersistentFlags().BoolVarP(&migrateNoInsert, "migrate-no-insert", "", false, L("Do not insert values when migrating"))
+	restoreCmd.PersistentFlags().StringSliceVarP(&restoreModelNames, "name", "n", nil, L("Model names or glob patterns"))
+	restoreCmd.PersistentFlags().BoolVarP(&restoreUpsert, "upsert", "", false, L("Upsert rows instead of recreating tables"))
+	restoreCmd.PersistentFlags().BoolVarP(&restoreAttachments, "attachments", "", false, L("Include the attachments"))
+	restoreCmd.PersistentFlags().BoolVarP(&restoreKB, "kb", "", false, L("Include the knowledge base collections"))
+	restoreCmd.PersistentFlags().BoolVarP(&restoreChats, "chats", "", false, L("Include the agent chat history"))
 }
 
 func restoreData(basePath string) {
@@ -149,68 +202,3 @@ func restoreModels(basePath string, migOpts []model.MigrateOption) {
 		}
 	}
 }
-
-func unzipFile(file string, process func(file string)) string {
-	_, err := os.Stat(file)
-
-	if errors.Is(err, os.ErrNotExist) {
-		fmt.Println(color.RedString("%s not exists", file))
-		os.Exit(1)
-	}
-
-	if err != nil {
-		fmt.Println(color.RedString(L("Fatal: %s"), err.Error()))
-		os.Exit(1)
-	}
-
-	dst := filepath.Join(os.TempDir(), fmt.Sprintf("%s-%s", filepath.Base(file), time.Now().Format("20060102150405")))
-	os.MkdirAll(dst, 0755)
-
-	archive, err := zip.OpenReader(file)
-	if err != nil {
-		fmt.Println(color.RedString(L("Fatal: %s"), err.Error()))
-		os.Exit(1)
-	}
-	defer archive.Close()
-
-	for _, f := range archive.File {
-		filePath := filepath.Join(dst, f.Name)
-		process(f.Name)
-
-		if !strings.HasPrefix(filePath, filepath.Clean(dst)+string(os.PathSeparator)) {
-			fmt.Println(color.RedString(L("Fatal: invalid file path")))
-			os.Exit(1)
-		}
-		if f.FileInfo().IsDir() {
-			os.MkdirAll(filePath, os.ModePerm)
-			continue
-		}
-
-		if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
-			fmt.Println(color.RedString(L("Fatal: %s"), err.Error()))
-			os.Exit(1)
-		}
-
-		dstFile, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
-		if err != nil {
-			fmt.Println(color.RedString(L("Fatal: %s"), err.Error()))
-			os.Exit(1)
-		}
-
-		fileInArchive, err := f.Open()
-		if err != nil {
-			fmt.Println(color.RedString(L("Fatal: %s"), err.Error()))
-			os.Exit(1)
-		}
-
-		if _, err := io.Copy(dstFile, fileInArchive); err != nil {
-			fmt.Println(color.RedString(L("Fatal: %s"), err.Error()))
-			os.Exit(1)
-		}
-
-		dstFile.Close()
-		fileInArchive.Close()
-	}
-
-	return dst
-}
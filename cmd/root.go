@@ -76,6 +76,15 @@ var langs = map[string]string{
 	"MCP package management commands":            "MCP 包管理命令",
 	"Robot commands":                             "Robot 包管理命令",
 	"Robot package management commands":          "Robot 包管理命令",
+	"Dump the application data":                  "导出应用数据",
+	"Restore the application data":               "恢复应用数据",
+	"Model names or glob patterns":               "模型名称或通配符",
+	"SQL where clause of the named models":       "指定模型的 SQL 查询条件",
+	"Only dump the rows changed since the time":  "仅导出该时间之后变更的数据",
+	"Include the attachments":                    "包含附件",
+	"Include the knowledge base collections":     "包含知识库集合",
+	"Include the agent chat history":             "包含智能体会话记录",
+	"Upsert rows instead of recreating tables":   "更新或插入数据, 不重建数据表",
 }
 
 // L Language switch
@@ -198,8 +207,8 @@ func init() {
 		loginCmd,
 		logoutCmd,
 		// getCmd,
-		// dumpCmd,
-		// restoreCmd,
+		dumpCmd,
+		restoreCmd,
 		// socketCmd,
 		// websocketCmd,
 		// packCmd,
@@ -27,6 +27,7 @@ import (
 	yaogrpc "github.com/yaoapp/yao/grpc"
 	_ "github.com/yaoapp/yao/grpc/auth"
 	sandboxhandler "github.com/yaoapp/yao/grpc/sandbox"
+	"github.com/yaoapp/yao/job"
 	"github.com/yaoapp/yao/openapi"
 	sandbox "github.com/yaoapp/yao/sandbox/v2"
 	ischedule "github.com/yaoapp/yao/schedule"
@@ -178,6 +179,12 @@ var startCmd = &cobra.Command{
 		ischedule.Start()
 		defer ischedule.Stop()
 
+		// Restore Jobs (reschedules cron jobs)
+		if _, err := job.RestoreJobsFromDatabase(); err != nil {
+			log.Error("[Job] %s", err.Error())
+		}
+		defer job.StopCronScheduler()
+
 		// Pre-flight: detect port conflicts before attempting to start servers.
 		if occupied, proc := portOccupied(config.Conf.Host, config.Conf.Port); occupied {
 			fmt.Println(color.RedString(L("Fatal: HTTP port %d is already in use%s"), config.Conf.Port, proc))
@@ -177,6 +177,14 @@ func LoadWithRoot(root string) Config {
 		}
 	}
 
+	// Event Log - default to the data root
+	if cfg.Event.Log == "" {
+		cfg.Event.Log = filepath.Join(cfg.DataRoot, "events", "events.log")
+	}
+	if !filepath.IsAbs(cfg.Event.Log) {
+		cfg.Event.Log = filepath.Join(cfg.Root, cfg.Event.Log)
+	}
+
 	// Trace Prefix - default prefix for store driver
 	if cfg.Trace.Driver == "store" && cfg.Trace.Prefix == "" {
 		cfg.Trace.Prefix = "trace:"
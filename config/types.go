@@ -37,6 +37,7 @@ type Config struct {
 	Session       Session        `json:"session,omitempty"`                                                                 // Session Config
 	Runtime       Runtime        `json:"runtime,omitempty"`                                                                 // Runtime config
 	Trace         Trace          `json:"trace,omitempty"`                                                                   // Trace config
+	Event         Event          `json:"event,omitempty"`                                                                   // Event bus config
 	Registry      string         `json:"registry,omitempty" env:"YAO_REGISTRY" envDefault:"https://registry.yaoagents.com"` // The package registry server URL
 	GRPC          GRPCConfig     `json:"grpc,omitempty"`
 	HostExec      HostExecConfig `json:"host_exec,omitempty"`
@@ -98,6 +99,12 @@ type HostExecConfig struct {
 	DeniedDirs      []string `json:"denied_dirs,omitempty" env:"YAO_HOST_EXEC_DENIED_DIRS" envSeparator:","`           // Denied directories (higher priority)
 }
 
+// Event bus config
+type Event struct {
+	Durable bool   `json:"durable,omitempty" env:"YAO_EVENT_DURABLE" envDefault:"false"` // Persist the events of durable handlers and replay them on start
+	Log     string `json:"log,omitempty" env:"YAO_EVENT_LOG"`                            // The event log path, default is <YAO_DATA_ROOT>/events/events.log
+}
+
 // Trace config
 type Trace struct {
 	Driver string `json:"driver,omitempty" env:"YAO_TRACE_DRIVER"` // The trace driver. local (development) | store (production)
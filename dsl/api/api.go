@@ -1,49 +1,347 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
 
+	"github.com/gin-gonic/gin"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/gou/api"
+	"github.com/yaoapp/gou/application"
+	"github.com/yaoapp/gou/process"
 	"github.com/yaoapp/yao/dsl/types"
 )
 
-// YaoAPI is the MCP client DSL manager
+// YaoAPI is the API (http) DSL manager
 type YaoAPI struct {
-	root string   // The relative path of the MCP client DSL
+	root string   // The relative path of the API DSL
 	fs   types.IO // The file system IO interface
 	db   types.IO // The database IO interface
 }
 
-// New returns a new connector DSL manager
+// methods the http router accepts
+var methods = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true,
+	"HEAD": true, "OPTIONS": true, "ANY": true,
+}
+
+// New returns a new API DSL manager
 func New(root string, fs types.IO, db types.IO) types.Manager {
 	return &YaoAPI{root: root, fs: fs, db: db}
 }
 
 // Loaded return all loaded DSLs
-func (api *YaoAPI) Loaded(ctx context.Context) (map[string]*types.Info, error) {
-	return nil, nil
+func (a *YaoAPI) Loaded(ctx context.Context) (map[string]*types.Info, error) {
+	infos := map[string]*types.Info{}
+	for id, inst := range api.APIs {
+		if inst == nil {
+			continue
+		}
+		path := inst.File
+		if path == "" {
+			path = types.ToPath(types.TypeAPI, id)
+		}
+		infos[id] = &types.Info{
+			ID:          id,
+			Path:        path,
+			Type:        types.TypeAPI,
+			Label:       inst.HTTP.Name,
+			Description: inst.HTTP.Description,
+			Builtin:     strings.HasPrefix(id, "__yao.") || strings.HasPrefix(id, "widgets."),
+		}
+	}
+	return infos, nil
 }
 
-// Load will unload the DSL first, then load the DSL from DB or file system
-func (api *YaoAPI) Load(ctx context.Context, options *types.LoadOptions) error {
-	return nil
+// Load will unload the DSL first, then load the DSL from DB or file system.
+// The route table is rebuilt so the routes are served without a restart.
+func (a *YaoAPI) Load(ctx context.Context, options *types.LoadOptions) error {
+	if options == nil {
+		return fmt.Errorf("load options is required")
+	}
+
+	if options.ID == "" {
+		return fmt.Errorf("load options id is required")
+	}
+
+	return a.load(options.ID, options.Source, options.Path, options.Store)
 }
 
 // Reload will unload the DSL first, then reload the DSL from DB or file system
-func (api *YaoAPI) Reload(ctx context.Context, options *types.ReloadOptions) error {
+func (a *YaoAPI) Reload(ctx context.Context, options *types.ReloadOptions) error {
+	if options == nil {
+		return fmt.Errorf("reload options is required")
+	}
+
+	if options.ID == "" {
+		return fmt.Errorf("reload options id is required")
+	}
+
+	return a.load(options.ID, options.Source, options.Path, options.Store)
+}
+
+// Unload will unload the DSL from memory and remove its routes
+func (a *YaoAPI) Unload(ctx context.Context, options *types.UnloadOptions) error {
+	if options == nil {
+		return fmt.Errorf("unload options is required")
+	}
+
+	if options.ID == "" {
+		return fmt.Errorf("unload options id is required")
+	}
+
+	if _, has := api.APIs[options.ID]; !has {
+		return fmt.Errorf("api %s not found", options.ID)
+	}
+
+	delete(api.APIs, options.ID)
+	api.BuildRouteTable()
 	return nil
 }
 
-// Unload will unload the DSL from memory
-func (api *YaoAPI) Unload(ctx context.Context, options *types.UnloadOptions) error {
+// load replaces the API with the given id, the previous definition is kept when loading fails
+func (a *YaoAPI) load(id, source, path string, store types.StoreType) error {
+	prev, hasPrev := api.APIs[id]
+	delete(api.APIs, id)
+
+	var err error
+	switch {
+	case source != "":
+		// Case 1: If Source is provided, use LoadSource
+		_, err = api.LoadSource(types.ToPath(types.TypeAPI, id), []byte(source), id)
+
+	case path != "" && store == types.StoreTypeFile:
+		// Case 2: If Path is provided and Store is file, use Load with Path
+		_, err = api.Load(path, id)
+
+	case store == types.StoreTypeDB:
+		// Case 3: If Store is db, get Source from DB first
+		if a.db == nil {
+			err = fmt.Errorf("db io is required for store type db")
+			break
+		}
+		var exists bool
+		source, exists, err = a.db.Source(id)
+		if err != nil {
+			break
+		}
+		if !exists {
+			err = fmt.Errorf("api %s not found in database", id)
+			break
+		}
+		_, err = api.LoadSource(types.ToPath(types.TypeAPI, id), []byte(source), id)
+
+	default:
+		// Case 4: Default case, use Load with ID
+		_, err = api.Load(types.ToPath(types.TypeAPI, id), id)
+	}
+
+	if err != nil {
+		if hasPrev {
+			api.APIs[id] = prev
+		}
+		return err
+	}
+
+	api.BuildRouteTable()
 	return nil
 }
 
-// Validate will validate the DSL from source
-func (api *YaoAPI) Validate(ctx context.Context, source string) (bool, []types.LintMessage) {
-	return false, nil
+// Validate will validate the DSL from source.
+// Paths must be unique and complete, guards and processes must be registered.
+func (a *YaoAPI) Validate(ctx context.Context, source string) (bool, []types.LintMessage) {
+	messages := []types.LintMessage{}
+
+	var def api.HTTP
+	err := application.Parse("<source>.http.yao", []byte(source), &def)
+	if err != nil {
+		messages = append(messages, lintError("", "invalid api source: %s", err.Error()))
+		return false, messages
+	}
+
+	if len(def.Paths) == 0 {
+		messages = append(messages, lintWarning("", "no paths defined"))
+	}
+
+	messages = append(messages, lintGuard("guard", def.Guard)...)
+
+	seen := map[string]bool{}
+	for i, path := range def.Paths {
+		at := fmt.Sprintf("paths[%d]", i)
+		method := strings.ToUpper(path.Method)
+
+		if path.Path == "" {
+			messages = append(messages, lintError(at, "path is required"))
+		}
+
+		if method == "" {
+			messages = append(messages, lintError(at, "method is required"))
+		} else if !methods[method] {
+			messages = append(messages, lintError(at, "method %s is not supported", path.Method))
+		}
+
+		key := method + " " + path.Path
+		if seen[key] {
+			messages = append(messages, lintError(at, "duplicate route %s", key))
+		}
+		seen[key] = true
+
+		if path.Process == "" {
+			messages = append(messages, lintError(at, "process is required"))
+		} else if _, err := process.Of(path.Process); err != nil {
+			messages = append(messages, lintError(at, "process %s not found", path.Process))
+		}
+
+		messages = append(messages, lintGuard(at+".guard", path.Guard)...)
+	}
+
+	for _, message := range messages {
+		if message.Severity == types.LintSeverityError {
+			return false, messages
+		}
+	}
+	return true, messages
+}
+
+// Execute invokes a route of the API in-process, guards are not applied.
+// method is the HTTP method, args are the path relative to the API group,
+// the optional payload and the optional request headers.
+func (a *YaoAPI) Execute(ctx context.Context, id string, method string, args ...any) (any, error) {
+	inst, has := api.APIs[id]
+	if !has {
+		return nil, fmt.Errorf("api %s not found", id)
+	}
+
+	if len(args) < 1 {
+		return nil, fmt.Errorf("path is required")
+	}
+
+	target, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("path should be a string")
+	}
+
+	group := inst.HTTP.Group
+	if group == "" {
+		group = strings.ReplaceAll(id, ".", "/")
+	}
+
+	path, query, _ := strings.Cut(target, "?")
+	route := "/" + strings.Trim(group, "/")
+	if path = strings.Trim(path, "/"); path != "" {
+		route = route + "/" + path
+	}
+	method = strings.ToUpper(method)
+
+	apiDef, _, handler, params, err := api.FindHandler(method, route)
+	if err != nil {
+		return nil, fmt.Errorf("route %s %s not found", method, route)
+	}
+	if apiDef.ID != id {
+		return nil, fmt.Errorf("route %s %s belongs to api %s", method, route, apiDef.ID)
+	}
+
+	var body io.Reader
+	if len(args) > 1 && args[1] != nil {
+		switch payload := args[1].(type) {
+		case string:
+			body = strings.NewReader(payload)
+		case []byte:
+			body = bytes.NewReader(payload)
+		default:
+			data, err := jsoniter.Marshal(payload)
+			if err != nil {
+				return nil, fmt.Errorf("invalid payload: %w", err)
+			}
+			body = bytes.NewReader(data)
+		}
+	}
+
+	uri := route
+	if query != "" {
+		uri = route + "?" + query
+	}
+
+	req := httptest.NewRequest(method, uri, body).WithContext(ctx)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if len(args) > 2 {
+		switch headers := args[2].(type) {
+		case map[string]string:
+			for key, value := range headers {
+				req.Header.Set(key, value)
+			}
+		case map[string]any:
+			for key, value := range headers {
+				req.Header.Set(key, fmt.Sprintf("%v", value))
+			}
+		}
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	for key, value := range params {
+		c.Params = append(c.Params, gin.Param{Key: key, Value: value})
+	}
+	handler(c)
+
+	headers := map[string]string{}
+	for key := range w.Header() {
+		headers[key] = w.Header().Get(key)
+	}
+
+	var data any = w.Body.String()
+	if strings.Contains(w.Header().Get("Content-Type"), "json") {
+		var parsed any
+		if err := jsoniter.Unmarshal(w.Body.Bytes(), &parsed); err == nil {
+			data = parsed
+		}
+	}
+
+	return map[string]any{
+		"status":  w.Code,
+		"headers": headers,
+		"body":    data,
+	}, nil
+}
+
+// lintGuard checks every guard of a comma separated list is registered
+func lintGuard(at string, guard string) []types.LintMessage {
+	messages := []types.LintMessage{}
+	for _, name := range strings.Split(guard, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || name == "-" {
+			continue
+		}
+		if _, has := api.HTTPGuards[name]; has {
+			continue
+		}
+		if _, err := process.Of(name); err == nil {
+			continue
+		}
+		messages = append(messages, lintError(at, "guard %s not found", name))
+	}
+	return messages
+}
+
+func lintError(at string, format string, args ...any) types.LintMessage {
+	return lintMessage(types.LintSeverityError, at, format, args...)
+}
+
+func lintWarning(at string, format string, args ...any) types.LintMessage {
+	return lintMessage(types.LintSeverityWarning, at, format, args...)
 }
 
-// Execute will execute the DSL
-func (api *YaoAPI) Execute(ctx context.Context, id string, method string, args ...any) (any, error) {
-	return nil, nil
+func lintMessage(severity types.LintSeverity, at string, format string, args ...any) types.LintMessage {
+	message := fmt.Sprintf(format, args...)
+	if at != "" {
+		message = at + ": " + message
+	}
+	return types.LintMessage{Message: message, Severity: severity}
 }
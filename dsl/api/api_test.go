@@ -0,0 +1,232 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/gou/api"
+	"github.com/yaoapp/yao/dsl/io"
+	"github.com/yaoapp/yao/dsl/types"
+)
+
+func TestAPILoad(t *testing.T) {
+	testCase := NewTestCase()
+	fsio := io.NewFS(types.TypeAPI)
+	dbio := io.NewDB(types.TypeAPI)
+	manager := New("", fsio, dbio)
+
+	// Test Load with nil options
+	err := manager.Load(context.Background(), nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "load options is required")
+
+	// Test Load with empty ID
+	err = manager.Load(context.Background(), &types.LoadOptions{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "load options id is required")
+
+	// Test Load with Source, the route is served right away
+	err = manager.Load(context.Background(), testCase.LoadOptions())
+	assert.NoError(t, err)
+	_, _, _, _, err = api.FindHandler("GET", "/"+testCase.ID+"/search")
+	assert.NoError(t, err)
+
+	// Test Load from filesystem
+	err = fsio.Create(testCase.CreateOptions())
+	assert.NoError(t, err)
+
+	path := types.ToPath(types.TypeAPI, testCase.ID)
+	err = manager.Load(context.Background(), &types.LoadOptions{
+		ID:    testCase.ID,
+		Path:  path,
+		Store: types.StoreTypeFile,
+	})
+	assert.NoError(t, err)
+
+	// Test Load from database
+	err = dbio.Create(testCase.CreateOptions())
+	assert.NoError(t, err)
+
+	err = manager.Load(context.Background(), &types.LoadOptions{
+		ID:    testCase.ID,
+		Store: types.StoreTypeDB,
+	})
+	assert.NoError(t, err)
+
+	// A broken source keeps the previous definition
+	err = manager.Load(context.Background(), &types.LoadOptions{ID: testCase.ID, Source: "{"})
+	assert.Error(t, err)
+	assert.Contains(t, api.APIs, testCase.ID)
+
+	// Clean up
+	err = manager.Unload(context.Background(), testCase.UnloadOptions())
+	assert.NoError(t, err)
+	err = fsio.Delete(testCase.ID)
+	assert.NoError(t, err)
+	err = dbio.Delete(testCase.ID)
+	assert.NoError(t, err)
+}
+
+func TestAPIUnload(t *testing.T) {
+	testCase := NewTestCase()
+	manager := New("", nil, nil)
+
+	// Test Unload with nil options
+	err := manager.Unload(context.Background(), nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unload options is required")
+
+	// Test Unload with empty ID
+	err = manager.Unload(context.Background(), &types.UnloadOptions{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unload options id is required")
+
+	// Load and then unload, the route is removed
+	err = manager.Load(context.Background(), testCase.LoadOptions())
+	assert.NoError(t, err)
+
+	err = manager.Unload(context.Background(), testCase.UnloadOptions())
+	assert.NoError(t, err)
+	assert.NotContains(t, api.APIs, testCase.ID)
+	_, _, _, _, err = api.FindHandler("GET", "/"+testCase.ID+"/search")
+	assert.Error(t, err)
+
+	// Unload twice
+	err = manager.Unload(context.Background(), testCase.UnloadOptions())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestAPIReload(t *testing.T) {
+	testCase := NewTestCase()
+	manager := New("", nil, nil)
+
+	// Test Reload with nil options
+	err := manager.Reload(context.Background(), nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "reload options is required")
+
+	// Test Reload with empty ID
+	err = manager.Reload(context.Background(), &types.ReloadOptions{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "reload options id is required")
+
+	// Load and then reload, the routes are replaced
+	err = manager.Load(context.Background(), testCase.LoadOptions())
+	assert.NoError(t, err)
+
+	err = manager.Reload(context.Background(), testCase.ReloadOptions())
+	assert.NoError(t, err)
+	_, _, _, _, err = api.FindHandler("GET", "/"+testCase.ID+"/find/1")
+	assert.NoError(t, err)
+	_, _, _, _, err = api.FindHandler("GET", "/"+testCase.ID+"/search")
+	assert.Error(t, err)
+
+	// Clean up
+	err = manager.Unload(context.Background(), testCase.UnloadOptions())
+	assert.NoError(t, err)
+}
+
+func TestAPILoaded(t *testing.T) {
+	testCase := NewTestCase()
+	manager := New("", nil, nil)
+
+	err := manager.Load(context.Background(), testCase.LoadOptions())
+	assert.NoError(t, err)
+
+	infos, err := manager.Loaded(context.Background())
+	assert.NoError(t, err)
+	assert.Contains(t, infos, testCase.ID)
+
+	info := infos[testCase.ID]
+	assert.Equal(t, testCase.ID, info.ID)
+	assert.Equal(t, types.TypeAPI, info.Type)
+	assert.Equal(t, testCase.Label, info.Label)
+	assert.Equal(t, testCase.Description, info.Description)
+	assert.False(t, info.Builtin)
+
+	// Clean up
+	err = manager.Unload(context.Background(), testCase.UnloadOptions())
+	assert.NoError(t, err)
+}
+
+func TestAPIValidate(t *testing.T) {
+	testCase := NewTestCase()
+	manager := New("", nil, nil)
+
+	valid, messages := manager.Validate(context.Background(), testCase.Source)
+	assert.True(t, valid)
+	assert.Empty(t, messages)
+
+	// Invalid source
+	valid, messages = manager.Validate(context.Background(), "{")
+	assert.False(t, valid)
+	assert.Len(t, messages, 1)
+
+	// Missing fields, duplicate routes, unknown processes and guards
+	valid, messages = manager.Validate(context.Background(), `{
+  "name": "Broken",
+  "guard": "not-a-guard",
+  "paths": [
+    { "path": "/a", "method": "GET", "process": "models.__yao.dsl.Get" },
+    { "path": "/a", "method": "GET", "process": "models.__yao.dsl.Get" },
+    { "path": "/b", "method": "FETCH", "process": "not.a.process" },
+    { "path": "", "method": "", "process": "" }
+  ]
+}`)
+	assert.False(t, valid)
+
+	found := []string{}
+	for _, message := range messages {
+		assert.Equal(t, types.LintSeverityError, message.Severity)
+		found = append(found, message.Message)
+	}
+	assert.Contains(t, found, "guard: guard not-a-guard not found")
+	assert.Contains(t, found, "paths[1]: duplicate route GET /a")
+	assert.Contains(t, found, "paths[2]: method FETCH is not supported")
+	assert.Contains(t, found, "paths[2]: process not.a.process not found")
+	assert.Contains(t, found, "paths[3]: path is required")
+	assert.Contains(t, found, "paths[3]: method is required")
+	assert.Contains(t, found, "paths[3]: process is required")
+
+	// No paths is only a warning
+	valid, messages = manager.Validate(context.Background(), `{"name": "Empty"}`)
+	assert.True(t, valid)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, types.LintSeverityWarning, messages[0].Severity)
+}
+
+func TestAPIExecute(t *testing.T) {
+	testCase := NewTestCase()
+	manager := New("", nil, nil)
+
+	// Not loaded
+	_, err := manager.Execute(context.Background(), testCase.ID, "GET", "/search")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+
+	err = manager.Reload(context.Background(), testCase.ReloadOptions())
+	assert.NoError(t, err)
+	defer manager.Unload(context.Background(), testCase.UnloadOptions())
+
+	// Path is required
+	_, err = manager.Execute(context.Background(), testCase.ID, "GET")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "path is required")
+
+	// Unknown route
+	_, err = manager.Execute(context.Background(), testCase.ID, "POST", "/find/1")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+
+	// Invoke the route with a path parameter and a query
+	result, err := manager.Execute(context.Background(), testCase.ID, "GET", "/find/1?select=dsl_id")
+	assert.NoError(t, err)
+
+	res, ok := result.(map[string]any)
+	assert.True(t, ok)
+	assert.Contains(t, res, "status")
+	assert.Contains(t, res, "headers")
+	assert.Contains(t, res, "body")
+}
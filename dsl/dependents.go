@@ -0,0 +1,88 @@
+package dsl
+
+import (
+	"context"
+
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/yao/dsl/types"
+)
+
+// dependent a loaded DSL referencing another DSL
+type dependent struct {
+	dsl *DSL
+	id  string
+}
+
+// collectDependents returns the loaded DSLs referencing the DSL, directly or not.
+// A DSL comes before the DSLs referencing it.
+func collectDependents(ctx context.Context, typ types.Type, id string) []dependent {
+	dependents := []dependent{}
+	visited := map[string]bool{string(typ) + ":" + id: true}
+	collect(ctx, typ, id, visited, &dependents)
+	return dependents
+}
+
+func collect(ctx context.Context, typ types.Type, id string, visited map[string]bool, dependents *[]dependent) {
+	for depType := range factories {
+		inst, err := New(depType)
+		if err != nil {
+			continue
+		}
+
+		dsl := inst.(*DSL)
+		manager, ok := dsl.manager.(types.DependentManager)
+		if !ok {
+			continue
+		}
+
+		ids, err := manager.Dependents(ctx, typ, id)
+		if err != nil {
+			log.Warn("[DSL] %s dependents of %s %s: %s", depType, typ, id, err.Error())
+			continue
+		}
+
+		for _, depID := range ids {
+			key := string(depType) + ":" + depID
+			if visited[key] {
+				continue
+			}
+			visited[key] = true
+			*dependents = append(*dependents, dependent{dsl: dsl, id: depID})
+			collect(ctx, depType, depID, visited, dependents)
+		}
+	}
+}
+
+// unloadDependents unloads the dependents, the most dependent first
+func unloadDependents(ctx context.Context, dependents []dependent) {
+	for i := len(dependents) - 1; i >= 0; i-- {
+		dep := dependents[i]
+		err := dep.dsl.manager.Unload(ctx, &types.UnloadOptions{ID: dep.id})
+		if err != nil {
+			log.Warn("[DSL] unload %s %s: %s", dep.dsl.Type, dep.id, err.Error())
+		}
+	}
+}
+
+// reloadDependents loads the dependents again from their store, a dependent
+// failing to load stays unloaded.
+func reloadDependents(ctx context.Context, dependents []dependent) {
+	for _, dep := range dependents {
+		info, exists, err := dep.dsl.db.Inspect(dep.id)
+		if err == nil && exists {
+			info.Store = types.StoreTypeDB
+		} else {
+			info, exists, err = dep.dsl.fs.Inspect(dep.id)
+			if err != nil || !exists {
+				log.Warn("[DSL] reload %s %s: source not found", dep.dsl.Type, dep.id)
+				continue
+			}
+			info.Store = types.StoreTypeFile
+		}
+
+		err = dep.dsl.manager.Reload(ctx, &types.ReloadOptions{ID: dep.id, Path: info.Path, Store: info.Store})
+		if err != nil {
+			log.Warn("[DSL] reload %s %s: %s", dep.dsl.Type, dep.id, err.Error())
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package dsl
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/yaoapp/gou/application"
+	"github.com/yaoapp/yao/dsl/types"
+)
+
+// diffSources returns the changes between two DSL sources, the sources are
+// parsed (comments and trailing commas are allowed) and compared as JSON values
+func diffSources(file string, from string, to string) ([]types.Change, error) {
+	var fromValue, toValue interface{}
+	if from != "" {
+		err := application.Parse(file, []byte(from), &fromValue)
+		if err != nil {
+			return nil, fmt.Errorf("parse the source of %s: %s", file, err.Error())
+		}
+	}
+
+	if to != "" {
+		err := application.Parse(file, []byte(to), &toValue)
+		if err != nil {
+			return nil, fmt.Errorf("parse the source of %s: %s", file, err.Error())
+		}
+	}
+
+	changes := []types.Change{}
+	diffValues("", fromValue, toValue, &changes)
+	return changes, nil
+}
+
+// diffValues appends the changes between two values, path is a JSON pointer
+func diffValues(path string, from interface{}, to interface{}, changes *[]types.Change) {
+	switch {
+	case from == nil && to == nil:
+		return
+
+	case from == nil:
+		*changes = append(*changes, types.Change{Path: pathOrRoot(path), Op: types.ChangeOpAdd, To: to})
+		return
+
+	case to == nil:
+		*changes = append(*changes, types.Change{Path: pathOrRoot(path), Op: types.ChangeOpRemove, From: from})
+		return
+	}
+
+	fromMap, fromIsMap := from.(map[string]interface{})
+	toMap, toIsMap := to.(map[string]interface{})
+	if fromIsMap && toIsMap {
+		keys := map[string]bool{}
+		for key := range fromMap {
+			keys[key] = true
+		}
+		for key := range toMap {
+			keys[key] = true
+		}
+
+		sorted := make([]string, 0, len(keys))
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+
+		for _, key := range sorted {
+			diffValues(path+"/"+escapePointer(key), fromMap[key], toMap[key], changes)
+		}
+		return
+	}
+
+	fromList, fromIsList := from.([]interface{})
+	toList, toIsList := to.([]interface{})
+	if fromIsList && toIsList {
+		size := len(fromList)
+		if len(toList) > size {
+			size = len(toList)
+		}
+
+		for i := 0; i < size; i++ {
+			var fromItem, toItem interface{}
+			if i < len(fromList) {
+				fromItem = fromList[i]
+			}
+			if i < len(toList) {
+				toItem = toList[i]
+			}
+			diffValues(fmt.Sprintf("%s/%d", path, i), fromItem, toItem, changes)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		*changes = append(*changes, types.Change{Path: pathOrRoot(path), Op: types.ChangeOpReplace, From: from, To: to})
+	}
+}
+
+// escapePointer escapes a key of a JSON pointer (RFC 6901)
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
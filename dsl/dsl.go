@@ -4,22 +4,37 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/yaoapp/kun/log"
 	"github.com/yaoapp/yao/dsl/api"
 	"github.com/yaoapp/yao/dsl/connector"
+	"github.com/yaoapp/yao/dsl/flow"
 	"github.com/yaoapp/yao/dsl/io"
 	"github.com/yaoapp/yao/dsl/mcp"
 	"github.com/yaoapp/yao/dsl/model"
+	"github.com/yaoapp/yao/dsl/pipe"
+	"github.com/yaoapp/yao/dsl/schedule"
+	"github.com/yaoapp/yao/dsl/store"
 	"github.com/yaoapp/yao/dsl/types"
 )
 
 // DSL is the base DSL struct
 type DSL struct {
-	Type    types.Type
-	exts    []string
-	root    string
-	manager types.Manager
-	db      types.IO
-	fs      types.IO
+	Type      types.Type
+	exts      []string
+	root      string
+	manager   types.Manager
+	db        types.IO
+	fs        types.IO
+	revisions types.RevisionIO
+}
+
+// factories the managers registered from outside of this package
+var factories = map[types.Type]types.ManagerFactory{}
+
+// Register registers the manager of a DSL type. It is used by the DSL types
+// whose packages depend on this one (e.g. the widgets) and can't be wired in New.
+func Register(typ types.Type, factory types.ManagerFactory) {
+	factories[typ] = factory
 }
 
 // New returns a new DSL manager
@@ -53,11 +68,30 @@ func New(typ types.Type) (types.DSL, error) {
 		exts = []string{".http.yao", ".http.jsonc", ".http.json"}
 		manager = api.New(root, fs, db)
 
+	case types.TypeFlow:
+		exts = []string{".flow.yao", ".flow.jsonc", ".flow.json"}
+		manager = flow.New(root, fs, db)
+
+	case types.TypePipe:
+		exts = []string{".pipe.yao", ".pipe.jsonc", ".pipe.json"}
+		manager = pipe.New(root, fs, db)
+
+	case types.TypeStore:
+		manager = store.New(root, fs, db)
+
+	case types.TypeSchedule:
+		exts = []string{".sch.yao", ".sch.jsonc", ".sch.json"}
+		manager = schedule.New(root, fs, db)
+
 	default:
-		return nil, fmt.Errorf("dsl manager is not initialized, %s not supported", typ)
+		factory, has := factories[typ]
+		if !has {
+			return nil, fmt.Errorf("dsl manager is not initialized, %s not supported", typ)
+		}
+		manager = factory(root, fs, db)
 	}
 
-	return &DSL{Type: typ, manager: manager, root: root, exts: exts, db: db, fs: fs}, nil
+	return &DSL{Type: typ, manager: manager, root: root, exts: exts, db: db, fs: fs, revisions: io.NewRevision(typ)}, nil
 }
 
 // Inspect DSL
@@ -182,6 +216,11 @@ func (dsl *DSL) List(ctx context.Context, opts *types.ListOptions) ([]*types.Inf
 
 // Create DSL
 func (dsl *DSL) Create(ctx context.Context, options *types.CreateOptions) error {
+	return dsl.create(ctx, options, types.RevisionActionCreate)
+}
+
+// create writes the DSL, records the revision and loads the DSL
+func (dsl *DSL) create(ctx context.Context, options *types.CreateOptions, action types.RevisionAction) error {
 
 	if options == nil {
 		return fmt.Errorf("create options is required")
@@ -209,6 +248,15 @@ func (dsl *DSL) Create(ctx context.Context, options *types.CreateOptions) error
 		}
 	}
 
+	dsl.record(&types.Revision{
+		ID:      options.ID,
+		Action:  action,
+		Store:   options.Store,
+		Source:  options.Source,
+		Author:  options.Author,
+		Message: options.Message,
+	})
+
 	var loadOptions *types.LoadOptions = &types.LoadOptions{
 		ID:      options.ID,
 		Path:    types.ToPath(dsl.Type, options.ID),
@@ -244,6 +292,11 @@ func (dsl *DSL) Exists(ctx context.Context, id string) (bool, error) {
 
 // Update DSL
 func (dsl *DSL) Update(ctx context.Context, options *types.UpdateOptions) error {
+	return dsl.update(ctx, options, types.RevisionActionUpdate)
+}
+
+// update writes the DSL, records the revision and reloads the DSL
+func (dsl *DSL) update(ctx context.Context, options *types.UpdateOptions, action types.RevisionAction) error {
 
 	if options == nil {
 		return fmt.Errorf("update options is required")
@@ -283,25 +336,34 @@ func (dsl *DSL) Update(ctx context.Context, options *types.UpdateOptions) error
 		Options: options.Reload,
 	}
 
-	// Update the DSL in the db
+	// Update the DSL in the db or in the file
+	writer := dsl.fs
 	if info.Store == types.StoreTypeDB {
-		err := dsl.db.Update(options)
-		if err != nil {
-			return err
-		}
-
-		// Reload the DSL
-		return dsl.manager.Reload(ctx, reloadOptions)
+		writer = dsl.db
 	}
 
-	// Update the DSL in the file
-	err = dsl.fs.Update(options)
+	err = writer.Update(options)
 	if err != nil {
 		return err
 	}
 
+	// Record the source after the update, an info update changes the source as well
+	source, _, err := writer.Source(options.ID)
+	if err != nil {
+		log.Error("[DSL] record revision of %s %s: %s", dsl.Type, options.ID, err.Error())
+	} else {
+		dsl.record(&types.Revision{
+			ID:      options.ID,
+			Action:  action,
+			Store:   info.Store,
+			Source:  source,
+			Author:  options.Author,
+			Message: options.Message,
+		})
+	}
+
 	// Reload the DSL
-	return dsl.manager.Reload(ctx, reloadOptions)
+	return dsl.Reload(ctx, reloadOptions)
 }
 
 // Delete DSL
@@ -341,6 +403,12 @@ func (dsl *DSL) Delete(ctx context.Context, options *types.DeleteOptions) error
 		}
 	}
 
+	// The deleted source is kept in the revision, so the DSL can be restored
+	source, err := dsl.Source(ctx, options.ID)
+	if err != nil {
+		return err
+	}
+
 	var opts map[string]interface{}
 	if options.Options != nil {
 		opts = options.Options
@@ -355,22 +423,24 @@ func (dsl *DSL) Delete(ctx context.Context, options *types.DeleteOptions) error
 
 	if info.Store == types.StoreTypeDB {
 		err = dsl.db.Delete(options.ID)
-		if err != nil {
-			return err
-		}
-
-		// Unload the DSL
-		return dsl.manager.Unload(ctx, unloadOptions)
+	} else {
+		err = dsl.fs.Delete(options.ID)
 	}
-
-	err = dsl.fs.Delete(options.ID)
 	if err != nil {
 		return err
 	}
 
-	// Unload the DSL
-	return dsl.manager.Unload(ctx, unloadOptions)
+	dsl.record(&types.Revision{
+		ID:      options.ID,
+		Action:  types.RevisionActionDelete,
+		Store:   info.Store,
+		Source:  source,
+		Author:  options.Author,
+		Message: options.Message,
+	})
 
+	// Unload the DSL
+	return dsl.Unload(ctx, unloadOptions)
 }
 
 // Load DSL
@@ -378,14 +448,33 @@ func (dsl *DSL) Load(ctx context.Context, options *types.LoadOptions) error {
 	return dsl.manager.Load(ctx, options)
 }
 
-// Unload DSL
+// Unload DSL, the loaded DSLs referencing it are unloaded as well
 func (dsl *DSL) Unload(ctx context.Context, options *types.UnloadOptions) error {
-	return dsl.manager.Unload(ctx, options)
+	var dependents []dependent
+	if options != nil && options.ID != "" {
+		dependents = collectDependents(ctx, dsl.Type, options.ID)
+	}
+
+	err := dsl.manager.Unload(ctx, options)
+	if err != nil {
+		return err
+	}
+
+	unloadDependents(ctx, dependents)
+	return nil
 }
 
-// Reload DSL
+// Reload DSL, the loaded DSLs referencing it are unloaded and loaded again
 func (dsl *DSL) Reload(ctx context.Context, options *types.ReloadOptions) error {
-	return dsl.manager.Reload(ctx, options)
+	var dependents []dependent
+	if options != nil && options.ID != "" {
+		dependents = collectDependents(ctx, dsl.Type, options.ID)
+	}
+
+	unloadDependents(ctx, dependents)
+	err := dsl.manager.Reload(ctx, options)
+	reloadDependents(ctx, dependents)
+	return err
 }
 
 // Execute DSL (Some DSLs can be executed)
@@ -20,7 +20,8 @@ import (
 
 // systemModels system models
 var systemModels = map[string]string{
-	"__yao.dsl": "yao/models/dsl.mod.yao",
+	"__yao.dsl":          "yao/models/dsl.mod.yao",
+	"__yao.dsl.revision": "yao/models/dsl/revision.mod.yao",
 }
 
 func TestMain(m *testing.M) {
@@ -782,3 +783,134 @@ func TestDSLFlow(t *testing.T) {
 		}
 	}
 }
+
+// Test DSL revision history (create, update, delete, rollback)
+func TestDSLRevision(t *testing.T) {
+	ctx := context.Background()
+
+	for _, store := range []types.StoreType{types.StoreTypeDB, types.StoreTypeFile} {
+		t.Run(fmt.Sprintf("Model_%s", store), func(t *testing.T) {
+			err := cleanTestData()
+			if err != nil {
+				t.Fatalf("Failed to clean test data: %v", err)
+			}
+
+			dsl, err := New(types.TypeModel)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			tc := NewModelTestCase()
+			author := &types.Author{ID: "1", Name: "Admin"}
+
+			// Create and update
+			options := tc.CreateOptions(store)
+			options.Author = author
+			err = dsl.Create(ctx, options)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			updateOptions := tc.UpdateOptions()
+			updateOptions.Message = "bad edit"
+			err = dsl.Update(ctx, updateOptions)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			revisions, err := dsl.Revisions(ctx, tc.ID)
+			if !assert.Nil(t, err) || !assert.Len(t, revisions, 2) {
+				return
+			}
+			assert.Equal(t, 2, revisions[0].Revision)
+			assert.Equal(t, types.RevisionActionUpdate, revisions[0].Action)
+			assert.Equal(t, "bad edit", revisions[0].Message)
+			assert.Empty(t, revisions[0].Source)
+			assert.Equal(t, types.RevisionActionCreate, revisions[1].Action)
+			assert.Equal(t, author, revisions[1].Author)
+			assert.Len(t, revisions[1].Hash, 64)
+
+			// Revision with source
+			rev, err := dsl.Revision(ctx, tc.ID, 1)
+			if !assert.Nil(t, err) {
+				return
+			}
+			assert.Equal(t, tc.Source, rev.Source)
+
+			_, err = dsl.Revision(ctx, tc.ID, 10)
+			assert.NotNil(t, err)
+
+			// Diff
+			diff, err := dsl.Diff(ctx, tc.ID, 1, 2)
+			if !assert.Nil(t, err) {
+				return
+			}
+			assert.Contains(t, diff.Changes, types.Change{Path: "/label", Op: types.ChangeOpReplace, From: "Test Model", To: "Updated Model"})
+			assert.Contains(t, diff.Changes, types.Change{Path: "/columns/2/option/2", Op: types.ChangeOpAdd, To: "pending"})
+
+			// Rollback
+			err = dsl.Rollback(ctx, &types.RollbackOptions{ID: tc.ID, Revision: 1, Author: author})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			source, err := dsl.Source(ctx, tc.ID)
+			if !assert.Nil(t, err) {
+				return
+			}
+			assert.Equal(t, tc.Source, source)
+
+			revisions, err = dsl.Revisions(ctx, tc.ID)
+			if !assert.Nil(t, err) || !assert.Len(t, revisions, 3) {
+				return
+			}
+			assert.Equal(t, types.RevisionActionRollback, revisions[0].Action)
+			assert.Equal(t, "rollback to revision 1", revisions[0].Message)
+			assert.Equal(t, revisions[2].Hash, revisions[0].Hash)
+
+			// Delete and restore
+			err = dsl.Delete(ctx, tc.DeleteOptions())
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			err = dsl.Rollback(ctx, &types.RollbackOptions{ID: tc.ID, Revision: 4})
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			info, err := dsl.Inspect(ctx, tc.ID)
+			if !assert.Nil(t, err) {
+				return
+			}
+			assert.Equal(t, store, info.Store)
+			assert.Equal(t, types.StatusLoaded, info.Status)
+
+			// Cleanup
+			err = dsl.Delete(ctx, tc.DeleteOptions())
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestDSLDiffSources(t *testing.T) {
+	changes, err := diffSources("models/test.mod.yao", `{
+  // comment
+  "name": "test",
+  "a/b": 1,
+  "columns": [{ "name": "id" }, { "name": "name" }],
+}`, `{"name": "test", "a/b": 2, "label": "Test", "columns": [{ "name": "id" }]}`)
+	assert.Nil(t, err)
+	assert.Equal(t, []types.Change{
+		{Path: "/a~1b", Op: types.ChangeOpReplace, From: float64(1), To: float64(2)},
+		{Path: "/columns/1", Op: types.ChangeOpRemove, From: map[string]interface{}{"name": "name"}},
+		{Path: "/label", Op: types.ChangeOpAdd, To: "Test"},
+	}, changes)
+
+	changes, err = diffSources("models/test.mod.yao", "", `{"name": "test"}`)
+	assert.Nil(t, err)
+	assert.Equal(t, []types.Change{{Path: "/", Op: types.ChangeOpAdd, To: map[string]interface{}{"name": "test"}}}, changes)
+
+	_, err = diffSources("models/test.mod.yao", "{", `{}`)
+	assert.NotNil(t, err)
+}
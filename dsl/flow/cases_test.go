@@ -0,0 +1,106 @@
+package flow
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/gou/application"
+	"github.com/yaoapp/gou/model"
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/yao/config"
+	"github.com/yaoapp/yao/data"
+	"github.com/yaoapp/yao/test"
+)
+
+// systemModels system models
+var systemModels = map[string]string{
+	"__yao.dsl": "yao/models/dsl.mod.yao",
+}
+
+func TestMain(m *testing.M) {
+	// Setup
+	test.Prepare(&testing.T{}, config.Conf)
+	defer test.Clean()
+
+	// Load system models
+	model.WithCrypt([]byte(fmt.Sprintf(`{"key":"%s"}`, config.Conf.DB.AESKey)), "AES")
+	model.WithCrypt([]byte(`{}`), "PASSWORD")
+	err := loadSystemModels()
+	if err != nil {
+		log.Error("Load system models error: %s", err.Error())
+		os.Exit(1)
+	}
+
+	// Load application
+	root := os.Getenv("GOU_TEST_APPLICATION")
+	app, err := application.OpenFromDisk(root) // Load app
+	if err != nil {
+		log.Error("Load application error: %s", err.Error())
+		os.Exit(1)
+	}
+	application.Load(app)
+
+	// Run tests
+	code := m.Run()
+	os.Exit(code)
+}
+
+// loadSystemModels load system models
+func loadSystemModels() error {
+	for id, path := range systemModels {
+		content, err := data.Read(path)
+		if err != nil {
+			return err
+		}
+
+		// Parse model
+		var data map[string]interface{}
+		err = application.Parse(path, content, &data)
+		if err != nil {
+			return err
+		}
+
+		// Set prefix
+		if table, ok := data["table"].(map[string]interface{}); ok {
+			if name, ok := table["name"].(string); ok {
+				table["name"] = "__yao_" + name
+				content, err = jsoniter.Marshal(data)
+				if err != nil {
+					log.Error("failed to marshal model data: %v", err)
+					return fmt.Errorf("failed to marshal model data: %v", err)
+				}
+			}
+		}
+
+		// Load Model
+		mod, err := model.LoadSource(content, id, path)
+		if err != nil {
+			log.Error("load system model %s error: %s", id, err.Error())
+			return err
+		}
+
+		// Drop table first
+		err = mod.DropTable()
+		if err != nil {
+			log.Error("drop table error: %s", err.Error())
+			return err
+		}
+
+		// Auto migrate
+		err = mod.Migrate(false, model.WithDonotInsertValues(true))
+		if err != nil {
+			log.Error("migrate system model %s error: %s", id, err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getTestID generates a unique test ID
+func getTestID() string {
+	return fmt.Sprintf("test_%d", time.Now().UnixNano())
+}
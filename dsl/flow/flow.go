@@ -0,0 +1,160 @@
+package flow
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yaoapp/gou/application"
+	"github.com/yaoapp/gou/flow"
+	"github.com/yaoapp/yao/dsl/types"
+)
+
+// YaoFlow is the flow DSL manager
+type YaoFlow struct {
+	root string   // The relative path of the flow DSL
+	fs   types.IO // The file system IO interface
+	db   types.IO // The database IO interface
+}
+
+// New returns a new flow DSL manager
+func New(root string, fs types.IO, db types.IO) types.Manager {
+	return &YaoFlow{root: root, fs: fs, db: db}
+}
+
+// Loaded return all loaded DSLs
+func (f *YaoFlow) Loaded(ctx context.Context) (map[string]*types.Info, error) {
+	infos := map[string]*types.Info{}
+	for id, inst := range flow.Flows {
+		if inst == nil {
+			continue
+		}
+		infos[id] = &types.Info{
+			ID:          id,
+			Path:        types.ToPath(types.TypeFlow, id),
+			Type:        types.TypeFlow,
+			Label:       inst.Label,
+			Description: inst.Description,
+		}
+	}
+	return infos, nil
+}
+
+// Load will unload the DSL first, then load the DSL from DB or file system
+func (f *YaoFlow) Load(ctx context.Context, options *types.LoadOptions) error {
+	if options == nil {
+		return fmt.Errorf("load options is required")
+	}
+
+	if options.ID == "" {
+		return fmt.Errorf("load options id is required")
+	}
+
+	return f.load(options.ID, options.Source, options.Path, options.Store)
+}
+
+// Reload will unload the DSL first, then reload the DSL from DB or file system
+func (f *YaoFlow) Reload(ctx context.Context, options *types.ReloadOptions) error {
+	if options == nil {
+		return fmt.Errorf("reload options is required")
+	}
+
+	if options.ID == "" {
+		return fmt.Errorf("reload options id is required")
+	}
+
+	return f.load(options.ID, options.Source, options.Path, options.Store)
+}
+
+// Unload will unload the DSL from memory
+func (f *YaoFlow) Unload(ctx context.Context, options *types.UnloadOptions) error {
+	if options == nil {
+		return fmt.Errorf("unload options is required")
+	}
+
+	if options.ID == "" {
+		return fmt.Errorf("unload options id is required")
+	}
+
+	if _, has := flow.Flows[options.ID]; !has {
+		return fmt.Errorf("flow %s not found", options.ID)
+	}
+
+	delete(flow.Flows, options.ID)
+	return nil
+}
+
+// load replaces the flow with the given id
+func (f *YaoFlow) load(id, source, path string, store types.StoreType) error {
+	file := types.ToPath(types.TypeFlow, id)
+
+	switch {
+	case source != "":
+		// Case 1: If Source is provided, use LoadSource
+		_, err := flow.LoadSource([]byte(source), id, file)
+		return err
+
+	case path != "" && store == types.StoreTypeFile:
+		// Case 2: If Path is provided and Store is file, use Load with Path
+		_, err := flow.Load(path, id)
+		return err
+
+	case store == types.StoreTypeDB:
+		// Case 3: If Store is db, get Source from DB first
+		if f.db == nil {
+			return fmt.Errorf("db io is required for store type db")
+		}
+		source, exists, err := f.db.Source(id)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("flow %s not found in database", id)
+		}
+		_, err = flow.LoadSource([]byte(source), id, file)
+		return err
+
+	default:
+		// Case 4: Default case, use Load with ID
+		_, err := flow.Load(file, id)
+		return err
+	}
+}
+
+// Validate will validate the DSL from source
+func (f *YaoFlow) Validate(ctx context.Context, source string) (bool, []types.LintMessage) {
+	var dsl struct {
+		Nodes []struct {
+			Name    string `json:"name"`
+			Process string `json:"process"`
+			Script  string `json:"script"`
+			Query   any    `json:"query"`
+		} `json:"nodes"`
+	}
+
+	err := application.Parse("<source>.flow.yao", []byte(source), &dsl)
+	if err != nil {
+		return false, []types.LintMessage{{Message: fmt.Sprintf("invalid flow source: %s", err.Error()), Severity: types.LintSeverityError}}
+	}
+
+	messages := []types.LintMessage{}
+	names := map[string]bool{}
+	for i, node := range dsl.Nodes {
+		if node.Name == "" {
+			messages = append(messages, types.LintMessage{Message: fmt.Sprintf("nodes[%d]: name is required", i), Severity: types.LintSeverityError})
+		} else if names[node.Name] {
+			messages = append(messages, types.LintMessage{Message: fmt.Sprintf("nodes[%d]: duplicate node name %s", i, node.Name), Severity: types.LintSeverityError})
+		}
+		names[node.Name] = true
+
+		if node.Process == "" && node.Script == "" && node.Query == nil {
+			messages = append(messages, types.LintMessage{Message: fmt.Sprintf("nodes[%d]: process, script or query is required", i), Severity: types.LintSeverityError})
+		}
+	}
+
+	return len(messages) == 0, messages
+}
+
+// Execute will execute the DSL
+func (f *YaoFlow) Execute(ctx context.Context, id string, method string, args ...any) (any, error) {
+	return nil, fmt.Errorf("Not implemented")
+}
@@ -0,0 +1,74 @@
+package flow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/gou/flow"
+	"github.com/yaoapp/yao/dsl/io"
+	"github.com/yaoapp/yao/dsl/types"
+)
+
+const testSource = `{
+  "label": "Test Flow",
+  "version": "1.0.0",
+  "description": "Test Description",
+  "nodes": [{ "name": "rows", "process": "models.__yao.dsl.Get", "args": [{}] }],
+  "output": "{{$res.rows}}"
+}`
+
+func TestFlowLoad(t *testing.T) {
+	id := getTestID()
+	fsio := io.NewFS(types.TypeFlow)
+	dbio := io.NewDB(types.TypeFlow)
+	manager := New("flows", fsio, dbio)
+
+	// Test Load with nil options
+	err := manager.Load(context.Background(), nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "load options is required")
+
+	// Test Load with Source
+	err = manager.Load(context.Background(), &types.LoadOptions{ID: id, Source: testSource})
+	assert.NoError(t, err)
+	assert.Contains(t, flow.Flows, id)
+
+	// Test Load from filesystem
+	err = fsio.Create(&types.CreateOptions{ID: id, Source: testSource})
+	assert.NoError(t, err)
+
+	err = manager.Reload(context.Background(), &types.ReloadOptions{
+		ID:    id,
+		Path:  types.ToPath(types.TypeFlow, id),
+		Store: types.StoreTypeFile,
+	})
+	assert.NoError(t, err)
+
+	// Test Loaded
+	infos, err := manager.Loaded(context.Background())
+	assert.NoError(t, err)
+	assert.Contains(t, infos, id)
+	assert.Equal(t, "Test Flow", infos[id].Label)
+
+	// Test Unload
+	err = manager.Unload(context.Background(), &types.UnloadOptions{ID: id})
+	assert.NoError(t, err)
+	assert.NotContains(t, flow.Flows, id)
+
+	// Clean up
+	err = fsio.Delete(id)
+	assert.NoError(t, err)
+}
+
+func TestFlowValidate(t *testing.T) {
+	manager := New("flows", nil, nil)
+
+	valid, messages := manager.Validate(context.Background(), testSource)
+	assert.True(t, valid)
+	assert.Empty(t, messages)
+
+	valid, messages = manager.Validate(context.Background(), `{"nodes": [{"name": "a", "process": "x"}, {"name": "a"}]}`)
+	assert.False(t, valid)
+	assert.Len(t, messages, 2)
+}
@@ -19,7 +19,8 @@ import (
 
 // systemModels system models
 var systemModels = map[string]string{
-	"__yao.dsl": "yao/models/dsl.mod.yao",
+	"__yao.dsl":          "yao/models/dsl.mod.yao",
+	"__yao.dsl.revision": "yao/models/dsl/revision.mod.yao",
 }
 
 func TestMain(m *testing.M) {
@@ -0,0 +1,166 @@
+package io
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cast"
+	"github.com/yaoapp/gou/model"
+	"github.com/yaoapp/yao/dsl/types"
+)
+
+// Revision is the revision history io, the revisions of both the db and the
+// file system DSLs are saved in the database
+type Revision struct {
+	Type types.Type
+}
+
+// NewRevision create a new revision io
+func NewRevision(typ types.Type) types.RevisionIO {
+	return &Revision{Type: typ}
+}
+
+// Hash returns the SHA-256 hash of a DSL source
+func Hash(source string) string {
+	sum := sha256.Sum256([]byte(source))
+	return hex.EncodeToString(sum[:])
+}
+
+// Record record a new revision of the dsl, the revision number follows the latest one
+func (r *Revision) Record(revision *types.Revision) (*types.Revision, error) {
+	if revision == nil || revision.ID == "" {
+		return nil, fmt.Errorf("%s revision id is required", r.Type)
+	}
+
+	latest, err := r.latest(revision.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	rev := *revision
+	rev.Type = r.Type
+	rev.Revision = latest + 1
+	rev.Hash = Hash(rev.Source)
+	rev.Created = time.Now()
+
+	data := map[string]interface{}{
+		"dsl_id":     rev.ID,
+		"type":       r.Type,
+		"revision":   rev.Revision,
+		"action":     rev.Action,
+		"store":      rev.Store,
+		"source":     rev.Source,
+		"hash":       rev.Hash,
+		"message":    rev.Message,
+		"created_at": rev.Created,
+		"updated_at": rev.Created,
+	}
+	if rev.Author != nil {
+		data["author_id"] = rev.Author.ID
+		data["author_name"] = rev.Author.Name
+	}
+
+	m := model.Select("__yao.dsl.revision")
+	_, err = m.Create(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rev, nil
+}
+
+// List get the revisions of the dsl, the latest first
+func (r *Revision) List(id string) ([]*types.Revision, error) {
+	m := model.Select("__yao.dsl.revision")
+	rows, err := m.Get(model.QueryParam{
+		Wheres: []model.QueryWhere{
+			{Column: "dsl_id", Value: id},
+			{Column: "type", Value: r.Type},
+		},
+		Select: []interface{}{"dsl_id", "revision", "action", "store", "hash", "message", "author_id", "author_name", "created_at"},
+		Orders: []model.QueryOrder{{Column: "revision", Option: "desc"}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := []*types.Revision{}
+	for _, row := range rows {
+		revisions = append(revisions, r.fmtRow(row))
+	}
+	return revisions, nil
+}
+
+// Get get a revision of the dsl with the source
+func (r *Revision) Get(id string, revision int) (*types.Revision, bool, error) {
+	m := model.Select("__yao.dsl.revision")
+	rows, err := m.Get(model.QueryParam{
+		Wheres: []model.QueryWhere{
+			{Column: "dsl_id", Value: id},
+			{Column: "type", Value: r.Type},
+			{Column: "revision", Value: revision},
+		},
+		Select: []interface{}{"dsl_id", "revision", "action", "store", "source", "hash", "message", "author_id", "author_name", "created_at"},
+		Limit:  1,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(rows) == 0 {
+		return nil, false, nil
+	}
+
+	return r.fmtRow(rows[0]), true, nil
+}
+
+// latest returns the latest revision number of the dsl, 0 if there is none
+func (r *Revision) latest(id string) (int, error) {
+	m := model.Select("__yao.dsl.revision")
+	rows, err := m.Get(model.QueryParam{
+		Wheres: []model.QueryWhere{
+			{Column: "dsl_id", Value: id},
+			{Column: "type", Value: r.Type},
+		},
+		Select: []interface{}{"revision"},
+		Orders: []model.QueryOrder{{Column: "revision", Option: "desc"}},
+		Limit:  1,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	return cast.ToInt(rows[0]["revision"]), nil
+}
+
+// fmtRow format the row data for the revision
+func (r *Revision) fmtRow(row map[string]interface{}) *types.Revision {
+	rev := &types.Revision{
+		ID:       cast.ToString(row["dsl_id"]),
+		Type:     r.Type,
+		Revision: cast.ToInt(row["revision"]),
+		Action:   types.RevisionAction(cast.ToString(row["action"])),
+		Store:    types.StoreType(cast.ToString(row["store"])),
+		Source:   cast.ToString(row["source"]),
+		Hash:     cast.ToString(row["hash"]),
+		Message:  cast.ToString(row["message"]),
+	}
+
+	authorID := cast.ToString(row["author_id"])
+	authorName := cast.ToString(row["author_name"])
+	if authorID != "" || authorName != "" {
+		rev.Author = &types.Author{ID: authorID, Name: authorName}
+	}
+
+	if created := toTime(row["created_at"]); created != "" {
+		rev.Created, _ = time.Parse(time.RFC3339, created)
+	}
+
+	return rev
+}
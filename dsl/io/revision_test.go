@@ -0,0 +1,83 @@
+package io
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/yao/dsl/types"
+)
+
+func TestRevisionRecord(t *testing.T) {
+	revisions := NewRevision(types.TypeModel)
+	tc := NewTestCase()
+
+	rev, err := revisions.Record(&types.Revision{ID: tc.ID, Action: types.RevisionActionCreate, Store: types.StoreTypeDB, Source: tc.Source})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, rev.Revision)
+	assert.Equal(t, Hash(tc.Source), rev.Hash)
+
+	rev, err = revisions.Record(&types.Revision{
+		ID:      tc.ID,
+		Action:  types.RevisionActionUpdate,
+		Store:   types.StoreTypeDB,
+		Source:  tc.UpdatedSource,
+		Author:  &types.Author{ID: "1", Name: "Admin"},
+		Message: "update the label",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, rev.Revision)
+
+	// Record without id should fail
+	_, err = revisions.Record(&types.Revision{Source: tc.Source})
+	assert.NotNil(t, err)
+}
+
+func TestRevisionList(t *testing.T) {
+	revisions := NewRevision(types.TypeModel)
+	tc := NewTestCase()
+
+	for _, source := range []string{tc.Source, tc.UpdatedSource} {
+		_, err := revisions.Record(&types.Revision{ID: tc.ID, Action: types.RevisionActionUpdate, Source: source})
+		assert.Nil(t, err)
+	}
+
+	list, err := revisions.List(tc.ID)
+	assert.Nil(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, 2, list[0].Revision)
+	assert.Equal(t, Hash(tc.UpdatedSource), list[0].Hash)
+	assert.Empty(t, list[0].Source)
+	assert.False(t, list[0].Created.IsZero())
+
+	// Other types don't share the history
+	list, err = NewRevision(types.TypeConnector).List(tc.ID)
+	assert.Nil(t, err)
+	assert.Empty(t, list)
+}
+
+func TestRevisionGet(t *testing.T) {
+	revisions := NewRevision(types.TypeModel)
+	tc := NewTestCase()
+
+	_, err := revisions.Record(&types.Revision{
+		ID:      tc.ID,
+		Action:  types.RevisionActionCreate,
+		Store:   types.StoreTypeFile,
+		Source:  tc.Source,
+		Author:  &types.Author{ID: "1", Name: "Admin"},
+		Message: "init",
+	})
+	assert.Nil(t, err)
+
+	rev, exists, err := revisions.Get(tc.ID, 1)
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, tc.Source, rev.Source)
+	assert.Equal(t, types.StoreTypeFile, rev.Store)
+	assert.Equal(t, "init", rev.Message)
+	assert.Equal(t, "Admin", rev.Author.Name)
+
+	_, exists, err = revisions.Get(tc.ID, 2)
+	assert.Nil(t, err)
+	assert.False(t, exists)
+}
@@ -0,0 +1,145 @@
+package pipe
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yaoapp/yao/dsl/types"
+	"github.com/yaoapp/yao/pipe"
+)
+
+// YaoPipe is the pipe DSL manager
+type YaoPipe struct {
+	root string   // The relative path of the pipe DSL
+	fs   types.IO // The file system IO interface
+	db   types.IO // The database IO interface
+}
+
+// New returns a new pipe DSL manager
+func New(root string, fs types.IO, db types.IO) types.Manager {
+	return &YaoPipe{root: root, fs: fs, db: db}
+}
+
+// Loaded return all loaded DSLs
+func (p *YaoPipe) Loaded(ctx context.Context) (map[string]*types.Info, error) {
+	infos := map[string]*types.Info{}
+	for _, id := range pipe.IDs() {
+		pip, err := pipe.Get(id)
+		if err != nil {
+			continue
+		}
+		label := pip.Label
+		if label == "" {
+			label = pip.Name
+		}
+		infos[id] = &types.Info{
+			ID:    id,
+			Path:  types.ToPath(types.TypePipe, id),
+			Type:  types.TypePipe,
+			Label: label,
+		}
+	}
+	return infos, nil
+}
+
+// Load will unload the DSL first, then load the DSL from DB or file system
+func (p *YaoPipe) Load(ctx context.Context, options *types.LoadOptions) error {
+	if options == nil {
+		return fmt.Errorf("load options is required")
+	}
+
+	if options.ID == "" {
+		return fmt.Errorf("load options id is required")
+	}
+
+	return p.load(options.ID, options.Source, options.Path, options.Store)
+}
+
+// Reload will unload the DSL first, then reload the DSL from DB or file system
+func (p *YaoPipe) Reload(ctx context.Context, options *types.ReloadOptions) error {
+	if options == nil {
+		return fmt.Errorf("reload options is required")
+	}
+
+	if options.ID == "" {
+		return fmt.Errorf("reload options id is required")
+	}
+
+	return p.load(options.ID, options.Source, options.Path, options.Store)
+}
+
+// Unload will unload the DSL from memory
+func (p *YaoPipe) Unload(ctx context.Context, options *types.UnloadOptions) error {
+	if options == nil {
+		return fmt.Errorf("unload options is required")
+	}
+
+	if options.ID == "" {
+		return fmt.Errorf("unload options id is required")
+	}
+
+	if _, err := pipe.Get(options.ID); err != nil {
+		return err
+	}
+
+	pipe.Remove(options.ID)
+	return nil
+}
+
+// load replaces the pipe with the given id
+func (p *YaoPipe) load(id, source, path string, store types.StoreType) error {
+	var pip *pipe.Pipe
+	var err error
+
+	switch {
+	case source != "":
+		// Case 1: If Source is provided, use NewSource
+		pip, err = pipe.NewSource([]byte(source), id)
+
+	case path != "" && store == types.StoreTypeFile:
+		// Case 2: If Path is provided and Store is file, use NewFile with Path
+		pip, err = pipe.NewFile(path, p.root)
+
+	case store == types.StoreTypeDB:
+		// Case 3: If Store is db, get Source from DB first
+		if p.db == nil {
+			return fmt.Errorf("db io is required for store type db")
+		}
+		var exists bool
+		source, exists, err = p.db.Source(id)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("pipe %s not found in database", id)
+		}
+		pip, err = pipe.NewSource([]byte(source), id)
+
+	default:
+		// Case 4: Default case, use NewFile with ID
+		pip, err = pipe.NewFile(types.ToPath(types.TypePipe, id), p.root)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	pipe.Set(id, pip)
+	return nil
+}
+
+// Validate will validate the DSL from source
+func (p *YaoPipe) Validate(ctx context.Context, source string) (bool, []types.LintMessage) {
+	// Building the pipe checks the nodes without registering it
+	_, err := pipe.New([]byte(source))
+	if err != nil {
+		return false, []types.LintMessage{{Message: err.Error(), Severity: types.LintSeverityError}}
+	}
+
+	return true, []types.LintMessage{}
+}
+
+// Execute will execute the DSL
+func (p *YaoPipe) Execute(ctx context.Context, id string, method string, args ...any) (any, error) {
+	return nil, fmt.Errorf("Not implemented")
+}
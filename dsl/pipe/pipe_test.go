@@ -0,0 +1,72 @@
+package pipe
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/yao/dsl/io"
+	"github.com/yaoapp/yao/dsl/types"
+	"github.com/yaoapp/yao/pipe"
+)
+
+const testSource = `{
+  "name": "Test Pipe",
+  "label": "Test Pipe",
+  "nodes": [{ "name": "rows", "process": { "name": "models.__yao.dsl.Get", "args": [{}] } }],
+  "output": "{{ $out }}"
+}`
+
+func TestPipeLoad(t *testing.T) {
+	id := getTestID()
+	dbio := io.NewDB(types.TypePipe)
+	manager := New("pipes", io.NewFS(types.TypePipe), dbio)
+
+	// Test Load with nil options
+	err := manager.Load(context.Background(), nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "load options is required")
+
+	// Test Load with Source
+	err = manager.Load(context.Background(), &types.LoadOptions{ID: id, Source: testSource})
+	assert.NoError(t, err)
+
+	pip, err := pipe.Get(id)
+	assert.NoError(t, err)
+	assert.Equal(t, id, pip.ID)
+
+	// Test Load from database
+	err = dbio.Create(&types.CreateOptions{ID: id, Source: testSource})
+	assert.NoError(t, err)
+
+	err = manager.Reload(context.Background(), &types.ReloadOptions{ID: id, Store: types.StoreTypeDB})
+	assert.NoError(t, err)
+
+	infos, err := manager.Loaded(context.Background())
+	assert.NoError(t, err)
+	assert.Contains(t, infos, id)
+	assert.Equal(t, "Test Pipe", infos[id].Label)
+
+	// Test Unload
+	err = manager.Unload(context.Background(), &types.UnloadOptions{ID: id})
+	assert.NoError(t, err)
+	_, err = pipe.Get(id)
+	assert.Error(t, err)
+
+	// Clean up
+	err = dbio.Delete(id)
+	assert.NoError(t, err)
+}
+
+func TestPipeValidate(t *testing.T) {
+	manager := New("pipes", nil, nil)
+
+	valid, messages := manager.Validate(context.Background(), testSource)
+	assert.True(t, valid)
+	assert.Empty(t, messages)
+
+	// Pipes without nodes are rejected
+	valid, messages = manager.Validate(context.Background(), `{"name": "Empty"}`)
+	assert.False(t, valid)
+	assert.Len(t, messages, 1)
+}
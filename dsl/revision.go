@@ -0,0 +1,119 @@
+package dsl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/yao/dsl/types"
+)
+
+// Revisions List the revisions of the DSL, the latest first
+func (dsl *DSL) Revisions(ctx context.Context, id string) ([]*types.Revision, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%s id is required", dsl.Type)
+	}
+	return dsl.revisions.List(id)
+}
+
+// Revision Get a revision of the DSL with its source
+func (dsl *DSL) Revision(ctx context.Context, id string, revision int) (*types.Revision, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%s id is required", dsl.Type)
+	}
+
+	rev, exists, err := dsl.revisions.Get(id, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, fmt.Errorf("%s %s revision %d not found", dsl.Type, id, revision)
+	}
+
+	return rev, nil
+}
+
+// Diff the sources of two revisions of the DSL
+func (dsl *DSL) Diff(ctx context.Context, id string, from int, to int) (*types.Diff, error) {
+	fromRev, err := dsl.Revision(ctx, id, from)
+	if err != nil {
+		return nil, err
+	}
+
+	toRev, err := dsl.Revision(ctx, id, to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := diffSources(types.ToPath(dsl.Type, id), fromRev.Source, toRev.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.Diff{ID: id, Type: dsl.Type, From: from, To: to, Changes: changes}, nil
+}
+
+// Rollback the DSL to the source of a revision and reload it, a deleted DSL
+// is created again. The rollback is recorded as a new revision.
+func (dsl *DSL) Rollback(ctx context.Context, options *types.RollbackOptions) error {
+	if options == nil {
+		return fmt.Errorf("rollback options is required")
+	}
+
+	if options.ID == "" {
+		return fmt.Errorf("rollback options id is required")
+	}
+
+	rev, err := dsl.Revision(ctx, options.ID, options.Revision)
+	if err != nil {
+		return err
+	}
+
+	if rev.Source == "" {
+		return fmt.Errorf("%s %s revision %d has no source", dsl.Type, options.ID, options.Revision)
+	}
+
+	message := options.Message
+	if message == "" {
+		message = fmt.Sprintf("rollback to revision %d", options.Revision)
+	}
+
+	exists, err := dsl.Exists(ctx, options.ID)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return dsl.update(ctx, &types.UpdateOptions{
+			ID:      options.ID,
+			Source:  rev.Source,
+			Reload:  options.Reload,
+			Author:  options.Author,
+			Message: message,
+		}, types.RevisionActionRollback)
+	}
+
+	storeType := options.Store
+	if storeType == "" {
+		storeType = rev.Store
+	}
+
+	return dsl.create(ctx, &types.CreateOptions{
+		ID:      options.ID,
+		Source:  rev.Source,
+		Store:   storeType,
+		Load:    options.Reload,
+		Author:  options.Author,
+		Message: message,
+	}, types.RevisionActionRollback)
+}
+
+// record records a revision of the DSL. The change is already saved, so a
+// failure is logged instead of failing the operation.
+func (dsl *DSL) record(revision *types.Revision) {
+	_, err := dsl.revisions.Record(revision)
+	if err != nil {
+		log.Error("[DSL] record revision of %s %s: %s", dsl.Type, revision.ID, err.Error())
+	}
+}
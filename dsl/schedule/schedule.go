@@ -0,0 +1,167 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yaoapp/gou/application"
+	"github.com/yaoapp/gou/schedule"
+	"github.com/yaoapp/yao/dsl/types"
+)
+
+// YaoSchedule is the schedule DSL manager
+type YaoSchedule struct {
+	root string   // The relative path of the schedule DSL
+	fs   types.IO // The file system IO interface
+	db   types.IO // The database IO interface
+}
+
+// New returns a new schedule DSL manager
+func New(root string, fs types.IO, db types.IO) types.Manager {
+	return &YaoSchedule{root: root, fs: fs, db: db}
+}
+
+// Loaded return all loaded DSLs
+func (s *YaoSchedule) Loaded(ctx context.Context) (map[string]*types.Info, error) {
+	infos := map[string]*types.Info{}
+	for id, sch := range schedule.Schedules {
+		if sch == nil {
+			continue
+		}
+		infos[id] = &types.Info{
+			ID:          id,
+			Path:        types.ToPath(types.TypeSchedule, id),
+			Type:        types.TypeSchedule,
+			Label:       sch.Name,
+			Description: sch.Schedule,
+		}
+	}
+	return infos, nil
+}
+
+// Load will unload the DSL first, then load the DSL from DB or file system.
+// The schedule is started right away.
+func (s *YaoSchedule) Load(ctx context.Context, options *types.LoadOptions) error {
+	if options == nil {
+		return fmt.Errorf("load options is required")
+	}
+
+	if options.ID == "" {
+		return fmt.Errorf("load options id is required")
+	}
+
+	return s.load(options.ID, options.Source, options.Path, options.Store)
+}
+
+// Reload will unload the DSL first, then reload the DSL from DB or file system
+func (s *YaoSchedule) Reload(ctx context.Context, options *types.ReloadOptions) error {
+	if options == nil {
+		return fmt.Errorf("reload options is required")
+	}
+
+	if options.ID == "" {
+		return fmt.Errorf("reload options id is required")
+	}
+
+	return s.load(options.ID, options.Source, options.Path, options.Store)
+}
+
+// Unload will stop the schedule and unload the DSL from memory
+func (s *YaoSchedule) Unload(ctx context.Context, options *types.UnloadOptions) error {
+	if options == nil {
+		return fmt.Errorf("unload options is required")
+	}
+
+	if options.ID == "" {
+		return fmt.Errorf("unload options id is required")
+	}
+
+	if _, has := schedule.Schedules[options.ID]; !has {
+		return fmt.Errorf("schedule %s not found", options.ID)
+	}
+
+	s.stop(options.ID)
+	delete(schedule.Schedules, options.ID)
+	return nil
+}
+
+// load stops the running schedule with the given id, then loads and starts the new one
+func (s *YaoSchedule) load(id, source, path string, store types.StoreType) error {
+	s.stop(id)
+	sch, err := s.read(id, source, path, store)
+	if err != nil {
+		return err
+	}
+	sch.Start()
+	return nil
+}
+
+// stop stops the running schedule with the given id
+func (s *YaoSchedule) stop(id string) {
+	if sch, has := schedule.Schedules[id]; has && sch != nil {
+		sch.Stop()
+	}
+}
+
+// read loads the schedule from the source, the file or the database
+func (s *YaoSchedule) read(id, source, path string, store types.StoreType) (*schedule.Schedule, error) {
+	file := types.ToPath(types.TypeSchedule, id)
+
+	switch {
+	case source != "":
+		// Case 1: If Source is provided, use LoadSource
+		return schedule.LoadSource([]byte(source), id, file)
+
+	case path != "" && store == types.StoreTypeFile:
+		// Case 2: If Path is provided and Store is file, use Load with Path
+		return schedule.Load(path, id)
+
+	case store == types.StoreTypeDB:
+		// Case 3: If Store is db, get Source from DB first
+		if s.db == nil {
+			return nil, fmt.Errorf("db io is required for store type db")
+		}
+		source, exists, err := s.db.Source(id)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("schedule %s not found in database", id)
+		}
+		return schedule.LoadSource([]byte(source), id, file)
+
+	default:
+		// Case 4: Default case, use Load with ID
+		return schedule.Load(file, id)
+	}
+}
+
+// Validate will validate the DSL from source
+func (s *YaoSchedule) Validate(ctx context.Context, source string) (bool, []types.LintMessage) {
+	var dsl struct {
+		Schedule string `json:"schedule"`
+		Process  string `json:"process"`
+		TaskName string `json:"task"`
+	}
+
+	err := application.Parse("<source>.sch.yao", []byte(source), &dsl)
+	if err != nil {
+		return false, []types.LintMessage{{Message: fmt.Sprintf("invalid schedule source: %s", err.Error()), Severity: types.LintSeverityError}}
+	}
+
+	messages := []types.LintMessage{}
+	if dsl.Schedule == "" {
+		messages = append(messages, types.LintMessage{Message: "schedule is required", Severity: types.LintSeverityError})
+	}
+
+	if dsl.Process == "" && dsl.TaskName == "" {
+		messages = append(messages, types.LintMessage{Message: "process or task is required", Severity: types.LintSeverityError})
+	}
+
+	return len(messages) == 0, messages
+}
+
+// Execute will execute the DSL
+func (s *YaoSchedule) Execute(ctx context.Context, id string, method string, args ...any) (any, error) {
+	return nil, fmt.Errorf("Not implemented")
+}
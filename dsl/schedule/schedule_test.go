@@ -0,0 +1,62 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/gou/schedule"
+	"github.com/yaoapp/yao/dsl/io"
+	"github.com/yaoapp/yao/dsl/types"
+)
+
+const testSource = `{"name": "Test Schedule", "schedule": "0 0 * * *", "process": "models.__yao.dsl.Get", "args": [{}]}`
+
+func TestScheduleLoad(t *testing.T) {
+	id := getTestID()
+	dbio := io.NewDB(types.TypeSchedule)
+	manager := New("schedules", io.NewFS(types.TypeSchedule), dbio)
+
+	// Test Load with nil options
+	err := manager.Load(context.Background(), nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "load options is required")
+
+	// Test Load from database
+	err = dbio.Create(&types.CreateOptions{ID: id, Source: testSource})
+	assert.NoError(t, err)
+
+	err = manager.Load(context.Background(), &types.LoadOptions{ID: id, Store: types.StoreTypeDB})
+	assert.NoError(t, err)
+	assert.Contains(t, schedule.Schedules, id)
+
+	// Reload replaces the running schedule
+	err = manager.Reload(context.Background(), &types.ReloadOptions{ID: id, Source: testSource})
+	assert.NoError(t, err)
+
+	infos, err := manager.Loaded(context.Background())
+	assert.NoError(t, err)
+	assert.Contains(t, infos, id)
+	assert.Equal(t, "Test Schedule", infos[id].Label)
+
+	// Test Unload
+	err = manager.Unload(context.Background(), &types.UnloadOptions{ID: id})
+	assert.NoError(t, err)
+	assert.NotContains(t, schedule.Schedules, id)
+
+	// Clean up
+	err = dbio.Delete(id)
+	assert.NoError(t, err)
+}
+
+func TestScheduleValidate(t *testing.T) {
+	manager := New("schedules", nil, nil)
+
+	valid, messages := manager.Validate(context.Background(), testSource)
+	assert.True(t, valid)
+	assert.Empty(t, messages)
+
+	valid, messages = manager.Validate(context.Background(), `{"name": "Broken"}`)
+	assert.False(t, valid)
+	assert.Len(t, messages, 2)
+}
@@ -0,0 +1,147 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/yaoapp/gou/application"
+	"github.com/yaoapp/gou/store"
+	"github.com/yaoapp/yao/dsl/types"
+)
+
+// YaoStore is the store DSL manager
+type YaoStore struct {
+	root string   // The relative path of the store DSL
+	fs   types.IO // The file system IO interface
+	db   types.IO // The database IO interface
+}
+
+// storeTypes the store types supported by gou
+var storeTypes = map[string]bool{"lru": true, "redis": true, "mongo": true, "xun": true}
+
+// New returns a new store DSL manager
+func New(root string, fs types.IO, db types.IO) types.Manager {
+	return &YaoStore{root: root, fs: fs, db: db}
+}
+
+// Loaded return all loaded DSLs
+func (s *YaoStore) Loaded(ctx context.Context) (map[string]*types.Info, error) {
+	infos := map[string]*types.Info{}
+	for id := range store.Pools {
+		infos[id] = &types.Info{
+			ID:      id,
+			Path:    types.ToPath(types.TypeStore, id),
+			Type:    types.TypeStore,
+			Label:   id,
+			Builtin: strings.HasPrefix(id, "__yao."),
+		}
+	}
+	return infos, nil
+}
+
+// Load will unload the DSL first, then load the DSL from DB or file system
+func (s *YaoStore) Load(ctx context.Context, options *types.LoadOptions) error {
+	if options == nil {
+		return fmt.Errorf("load options is required")
+	}
+
+	if options.ID == "" {
+		return fmt.Errorf("load options id is required")
+	}
+
+	return s.load(options.ID, options.Source, options.Path, options.Store)
+}
+
+// Reload will unload the DSL first, then reload the DSL from DB or file system
+func (s *YaoStore) Reload(ctx context.Context, options *types.ReloadOptions) error {
+	if options == nil {
+		return fmt.Errorf("reload options is required")
+	}
+
+	if options.ID == "" {
+		return fmt.Errorf("reload options id is required")
+	}
+
+	return s.load(options.ID, options.Source, options.Path, options.Store)
+}
+
+// Unload will unload the DSL from memory
+func (s *YaoStore) Unload(ctx context.Context, options *types.UnloadOptions) error {
+	if options == nil {
+		return fmt.Errorf("unload options is required")
+	}
+
+	if options.ID == "" {
+		return fmt.Errorf("unload options id is required")
+	}
+
+	if _, has := store.Pools[options.ID]; !has {
+		return fmt.Errorf("store %s not found", options.ID)
+	}
+
+	delete(store.Pools, options.ID)
+	return nil
+}
+
+// load replaces the store with the given id
+func (s *YaoStore) load(id, source, path string, storeType types.StoreType) error {
+	file := types.ToPath(types.TypeStore, id)
+
+	switch {
+	case source != "":
+		// Case 1: If Source is provided, use LoadSource
+		_, err := store.LoadSource([]byte(source), id, file)
+		return err
+
+	case path != "" && storeType == types.StoreTypeFile:
+		// Case 2: If Path is provided and Store is file, use Load with Path
+		_, err := store.Load(path, id)
+		return err
+
+	case storeType == types.StoreTypeDB:
+		// Case 3: If Store is db, get Source from DB first
+		if s.db == nil {
+			return fmt.Errorf("db io is required for store type db")
+		}
+		source, exists, err := s.db.Source(id)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("store %s not found in database", id)
+		}
+		_, err = store.LoadSource([]byte(source), id, file)
+		return err
+
+	default:
+		// Case 4: Default case, use Load with ID
+		_, err := store.Load(file, id)
+		return err
+	}
+}
+
+// Validate will validate the DSL from source
+func (s *YaoStore) Validate(ctx context.Context, source string) (bool, []types.LintMessage) {
+	var dsl struct {
+		Type      string `json:"type"`
+		Connector string `json:"connector"`
+	}
+
+	err := application.Parse("<source>.store.yao", []byte(source), &dsl)
+	if err != nil {
+		return false, []types.LintMessage{{Message: fmt.Sprintf("invalid store source: %s", err.Error()), Severity: types.LintSeverityError}}
+	}
+
+	// A store is either backed by a connector or by one of the built-in types
+	if dsl.Connector == "" && !storeTypes[dsl.Type] {
+		return false, []types.LintMessage{{Message: fmt.Sprintf("store type %q is not supported, or connector is required", dsl.Type), Severity: types.LintSeverityError}}
+	}
+
+	return true, []types.LintMessage{}
+}
+
+// Execute will execute the DSL
+func (s *YaoStore) Execute(ctx context.Context, id string, method string, args ...any) (any, error) {
+	return nil, fmt.Errorf("Not implemented")
+}
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/gou/store"
+	"github.com/yaoapp/yao/dsl/io"
+	"github.com/yaoapp/yao/dsl/types"
+)
+
+const testSource = `{"name": "Test Cache", "type": "lru", "option": {"size": 100}}`
+
+func TestStoreLoad(t *testing.T) {
+	id := getTestID()
+	fsio := io.NewFS(types.TypeStore)
+	dbio := io.NewDB(types.TypeStore)
+	manager := New("stores", fsio, dbio)
+
+	// Test Load with nil options
+	err := manager.Load(context.Background(), nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "load options is required")
+
+	// Test Load with empty ID
+	err = manager.Load(context.Background(), &types.LoadOptions{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "load options id is required")
+
+	// Test Load with Source
+	err = manager.Load(context.Background(), &types.LoadOptions{ID: id, Source: testSource})
+	assert.NoError(t, err)
+	assert.Contains(t, store.Pools, id)
+
+	// Test Load from database
+	err = dbio.Create(&types.CreateOptions{ID: id, Source: testSource})
+	assert.NoError(t, err)
+
+	err = manager.Load(context.Background(), &types.LoadOptions{ID: id, Store: types.StoreTypeDB})
+	assert.NoError(t, err)
+
+	// Test Loaded
+	infos, err := manager.Loaded(context.Background())
+	assert.NoError(t, err)
+	assert.Contains(t, infos, id)
+	assert.Equal(t, types.TypeStore, infos[id].Type)
+
+	// Test Unload
+	err = manager.Unload(context.Background(), &types.UnloadOptions{ID: id})
+	assert.NoError(t, err)
+	assert.NotContains(t, store.Pools, id)
+
+	err = manager.Unload(context.Background(), &types.UnloadOptions{ID: id})
+	assert.Error(t, err)
+
+	// Clean up
+	err = dbio.Delete(id)
+	assert.NoError(t, err)
+}
+
+func TestStoreValidate(t *testing.T) {
+	manager := New("stores", nil, nil)
+
+	valid, messages := manager.Validate(context.Background(), testSource)
+	assert.True(t, valid)
+	assert.Empty(t, messages)
+
+	valid, messages = manager.Validate(context.Background(), `{"name": "Test", "type": "unknown"}`)
+	assert.False(t, valid)
+	assert.Len(t, messages, 1)
+
+	valid, _ = manager.Validate(context.Background(), `{"name": "Test", "connector": "redis"}`)
+	assert.True(t, valid)
+
+	valid, _ = manager.Validate(context.Background(), "{")
+	assert.False(t, valid)
+}
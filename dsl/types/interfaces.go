@@ -27,6 +27,12 @@ type DSL interface {
 
 	// Validate
 	Validate(ctx context.Context, source string) (bool, []LintMessage) // Validate DSL, Validate will validate the DSL from source
+
+	// Revision history
+	Revisions(ctx context.Context, id string) ([]*Revision, error)            // List the revisions of the DSL, the latest first
+	Revision(ctx context.Context, id string, revision int) (*Revision, error) // Get a revision of the DSL with its source
+	Diff(ctx context.Context, id string, from int, to int) (*Diff, error)     // Diff the sources of two revisions of the DSL
+	Rollback(ctx context.Context, options *RollbackOptions) error             // Rollback the DSL to the source of a revision, and reload it
 }
 
 // Manager interface
@@ -50,6 +56,15 @@ type Manager interface {
 	Execute(ctx context.Context, id string, method string, args ...any) (any, error)
 }
 
+// DependentManager is implemented by the managers whose DSLs reference other DSLs
+type DependentManager interface {
+	// Dependents returns the ids of the loaded DSLs referencing the DSL of the given type and id
+	Dependents(ctx context.Context, typ Type, id string) ([]string, error)
+}
+
+// ManagerFactory creates the manager of a DSL type
+type ManagerFactory func(root string, fs IO, db IO) Manager
+
 // IO interface
 type IO interface {
 	Inspect(id string) (*Info, bool, error)
@@ -60,3 +75,10 @@ type IO interface {
 	Delete(id string) error
 	Exists(id string) (bool, error)
 }
+
+// RevisionIO interface
+type RevisionIO interface {
+	Record(revision *Revision) (*Revision, error) // Record a revision, the revision number and hash are generated
+	List(id string) ([]*Revision, error)          // List the revisions of a DSL without the source, the latest first
+	Get(id string, revision int) (*Revision, bool, error)
+}
@@ -16,6 +16,12 @@ type StoreType string
 // LintSeverity for DSL linter
 type LintSeverity string
 
+// RevisionAction the change recorded by a DSL revision
+type RevisionAction string
+
+// ChangeOp the operation of a change in a DSL diff
+type ChangeOp string
+
 // StoreType for DSL store
 const (
 	StoreTypeDB   StoreType = "db"
@@ -37,6 +43,21 @@ const (
 	LintSeverityHint    LintSeverity = "hint"
 )
 
+// RevisionAction the change recorded by a DSL revision
+const (
+	RevisionActionCreate   RevisionAction = "create"
+	RevisionActionUpdate   RevisionAction = "update"
+	RevisionActionDelete   RevisionAction = "delete"
+	RevisionActionRollback RevisionAction = "rollback"
+)
+
+// ChangeOp the operation of a change in a DSL diff
+const (
+	ChangeOpAdd     ChangeOp = "add"
+	ChangeOpRemove  ChangeOp = "remove"
+	ChangeOpReplace ChangeOp = "replace"
+)
+
 // Type for DSL
 const (
 	// TypeModel for model
@@ -115,6 +136,9 @@ type CreateOptions struct {
 	Source string                 // Source is the source of the DSL, if not provided, the DSL will be loaded from the file system
 	Store  StoreType              // Store is the store type of the DSL, if not provided, the DSL will be loaded from the file system
 	Load   map[string]interface{} // LoadOptions is the options for the DSL, if not provided, the DSL will be loaded from the file system
+
+	Author  *Author // Author is the user making the change, recorded in the revision history
+	Message string  // Message describes the change, recorded in the revision history
 }
 
 // UpdateOptions for DSL upsert
@@ -123,6 +147,9 @@ type UpdateOptions struct {
 	Info   *Info                  // Info is the info of the DSL, if not provided, the DSL will be loaded from the file system, one of info or source must be provided
 	Source string                 // Source is the source of the DSL, if not provided, the DSL will be loaded from the file system, one of info or source must be provided
 	Reload map[string]interface{} // ReloadOptions is the options for the DSL, if not provided, the DSL will be loaded from the file system
+
+	Author  *Author // Author is the user making the change, recorded in the revision history
+	Message string  // Message describes the change, recorded in the revision history
 }
 
 // DeleteOptions for DSL delete options
@@ -130,6 +157,56 @@ type DeleteOptions struct {
 	ID      string                 // ID is the id of the DSL, if not provided, a new id will be generated, required
 	Path    string                 // Path is the path of the DSL, if not provided, the DSL will be loaded from the file system
 	Options map[string]interface{} // Options is the options for the DSL, if not provided, the DSL will be loaded from the file system
+
+	Author  *Author // Author is the user making the change, recorded in the revision history
+	Message string  // Message describes the change, recorded in the revision history
+}
+
+// RollbackOptions for DSL rollback options
+type RollbackOptions struct {
+	ID       string                 // ID is the id of the DSL, required
+	Revision int                    // Revision is the revision number to roll back to, required
+	Store    StoreType              // Store is used when the DSL has been deleted, default is the store of the revision
+	Reload   map[string]interface{} // Reload is the options for reloading the DSL
+	Author   *Author                // Author is the user making the rollback
+	Message  string                 // Message describes the rollback, default is "rollback to revision N"
+}
+
+// Author the user making a change to a DSL
+type Author struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// Revision a recorded version of a DSL source
+type Revision struct {
+	ID       string         `json:"id"`                // DSL id
+	Type     Type           `json:"type"`              // DSL type
+	Revision int            `json:"revision"`          // Revision number, starts from 1
+	Action   RevisionAction `json:"action"`            // The change recorded
+	Store    StoreType      `json:"store,omitempty"`   // Storage type of the DSL when the revision was recorded
+	Hash     string         `json:"hash"`              // SHA-256 hash of the source
+	Message  string         `json:"message,omitempty"` // Description of the change
+	Author   *Author        `json:"author,omitempty"`  // The user making the change
+	Source   string         `json:"source,omitempty"`  // Source content, only available when a single revision is requested
+	Created  time.Time      `json:"created"`           // When the revision was recorded
+}
+
+// Diff the changes between two revisions of a DSL
+type Diff struct {
+	ID      string   `json:"id"`
+	Type    Type     `json:"type"`
+	From    int      `json:"from"`
+	To      int      `json:"to"`
+	Changes []Change `json:"changes"`
+}
+
+// Change a difference between two DSL sources, Path is a JSON pointer (e.g. /columns/1/name)
+type Change struct {
+	Path string      `json:"path"`
+	Op   ChangeOp    `json:"op"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
 }
 
 // LoadOptions for DSL load options
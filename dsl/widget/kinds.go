@@ -0,0 +1,206 @@
+package widget
+
+import (
+	"github.com/yaoapp/yao/dsl/types"
+	"github.com/yaoapp/yao/widgets/chart"
+	"github.com/yaoapp/yao/widgets/dashboard"
+	"github.com/yaoapp/yao/widgets/form"
+	"github.com/yaoapp/yao/widgets/list"
+	"github.com/yaoapp/yao/widgets/table"
+)
+
+// bindRefs returns the DSLs referenced by the bind section of a widget
+func bindRefs(modelID, storeID, tableID, formID string) []ref {
+	refs := []ref{}
+	if modelID != "" {
+		refs = append(refs, ref{Type: types.TypeModel, ID: modelID})
+	}
+	if storeID != "" {
+		refs = append(refs, ref{Type: types.TypeStore, ID: storeID})
+	}
+	if tableID != "" {
+		refs = append(refs, ref{Type: types.TypeTable, ID: tableID})
+	}
+	if formID != "" {
+		refs = append(refs, ref{Type: types.TypeForm, ID: formID})
+	}
+	return refs
+}
+
+// tableKind the table widget loader
+type tableKind struct{}
+
+func (tableKind) LoadSource(source []byte, id string) error {
+	_, err := table.LoadSourceSync(source, id)
+	return err
+}
+
+func (tableKind) LoadFile(root string, file string) error {
+	return table.LoadFileSync(root, file)
+}
+
+func (tableKind) Unload(id string) {
+	table.Unload(id)
+}
+
+func (tableKind) Has(id string) bool {
+	return table.Exists(id)
+}
+
+func (tableKind) Names() map[string]string {
+	names := map[string]string{}
+	for id, dsl := range table.Tables {
+		names[id] = dsl.Name
+	}
+	return names
+}
+
+func (tableKind) Refs(id string) []ref {
+	dsl, has := table.Tables[id]
+	if !has || dsl.Action == nil || dsl.Action.Bind == nil {
+		return nil
+	}
+	bind := dsl.Action.Bind
+	return bindRefs(bind.Model, bind.Store, bind.Table, bind.Form)
+}
+
+// formKind the form widget loader
+type formKind struct{}
+
+func (formKind) LoadSource(source []byte, id string) error {
+	_, err := form.LoadSourceSync(source, id)
+	return err
+}
+
+func (formKind) LoadFile(root string, file string) error {
+	return form.LoadFileSync(root, file)
+}
+
+func (formKind) Unload(id string) {
+	form.Unload(id)
+}
+
+func (formKind) Has(id string) bool {
+	return form.Exists(id)
+}
+
+func (formKind) Names() map[string]string {
+	names := map[string]string{}
+	for id, dsl := range form.Forms {
+		names[id] = dsl.Name
+	}
+	return names
+}
+
+func (formKind) Refs(id string) []ref {
+	dsl, has := form.Forms[id]
+	if !has || dsl.Action == nil || dsl.Action.Bind == nil {
+		return nil
+	}
+	bind := dsl.Action.Bind
+	return bindRefs(bind.Model, bind.Store, bind.Table, bind.Form)
+}
+
+// listKind the list widget loader
+type listKind struct{}
+
+func (listKind) LoadSource(source []byte, id string) error {
+	_, err := list.LoadSourceSync(source, id)
+	return err
+}
+
+func (listKind) LoadFile(root string, file string) error {
+	return list.LoadFileSync(root, file)
+}
+
+func (listKind) Unload(id string) {
+	list.Unload(id)
+}
+
+func (listKind) Has(id string) bool {
+	_, has := list.Lists[id]
+	return has
+}
+
+func (listKind) Names() map[string]string {
+	names := map[string]string{}
+	for id, dsl := range list.Lists {
+		names[id] = dsl.Name
+	}
+	return names
+}
+
+func (listKind) Refs(id string) []ref {
+	dsl, has := list.Lists[id]
+	if !has || dsl.Action == nil || dsl.Action.Bind == nil {
+		return nil
+	}
+	bind := dsl.Action.Bind
+	return bindRefs(bind.Model, bind.Store, bind.Table, "")
+}
+
+// chartKind the chart widget loader, charts are not bound to other DSLs
+type chartKind struct{}
+
+func (chartKind) LoadSource(source []byte, id string) error {
+	_, err := chart.LoadSourceSync(source, id)
+	return err
+}
+
+func (chartKind) LoadFile(root string, file string) error {
+	return chart.LoadFileSync(root, file)
+}
+
+func (chartKind) Unload(id string) {
+	chart.Unload(id)
+}
+
+func (chartKind) Refs(id string) []ref {
+	return nil
+}
+
+func (chartKind) Has(id string) bool {
+	_, has := chart.Charts[id]
+	return has
+}
+
+func (chartKind) Names() map[string]string {
+	names := map[string]string{}
+	for id, dsl := range chart.Charts {
+		names[id] = dsl.Name
+	}
+	return names
+}
+
+// dashboardKind the dashboard widget loader, dashboards are not bound to other DSLs
+type dashboardKind struct{}
+
+func (dashboardKind) LoadSource(source []byte, id string) error {
+	_, err := dashboard.LoadSourceSync(source, id)
+	return err
+}
+
+func (dashboardKind) LoadFile(root string, file string) error {
+	return dashboard.LoadFileSync(root, file)
+}
+
+func (dashboardKind) Unload(id string) {
+	dashboard.Unload(id)
+}
+
+func (dashboardKind) Refs(id string) []ref {
+	return nil
+}
+
+func (dashboardKind) Has(id string) bool {
+	_, has := dashboard.Dashboards[id]
+	return has
+}
+
+func (dashboardKind) Names() map[string]string {
+	names := map[string]string{}
+	for id, dsl := range dashboard.Dashboards {
+		names[id] = dsl.Name
+	}
+	return names
+}
@@ -0,0 +1,217 @@
+package widget
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/yaoapp/gou/application"
+	"github.com/yaoapp/gou/model"
+	"github.com/yaoapp/gou/store"
+	"github.com/yaoapp/yao/dsl"
+	"github.com/yaoapp/yao/dsl/types"
+	"github.com/yaoapp/yao/widgets/form"
+	"github.com/yaoapp/yao/widgets/table"
+)
+
+// The widget packages depend on the dsl package, so the managers are
+// registered from here instead of being wired in dsl.New.
+func init() {
+	for typ := range kinds {
+		dsl.Register(typ, func(root string, fs types.IO, db types.IO) types.Manager {
+			return New(typ, root, fs, db)
+		})
+	}
+}
+
+// ref a DSL referenced by a widget
+type ref struct {
+	Type types.Type
+	ID   string
+}
+
+// kind the loader of a widget type
+type kind interface {
+	LoadSource(source []byte, id string) error
+	LoadFile(root string, file string) error
+	Unload(id string)
+	Has(id string) bool
+	Names() map[string]string // loaded widget id -> name
+	Refs(id string) []ref     // the DSLs referenced by a loaded widget
+}
+
+// kinds the supported widget types
+var kinds = map[types.Type]kind{
+	types.TypeTable:     tableKind{},
+	types.TypeForm:      formKind{},
+	types.TypeList:      listKind{},
+	types.TypeChart:     chartKind{},
+	types.TypeDashboard: dashboardKind{},
+}
+
+// YaoWidget is the widget (table, form, list, chart, dashboard) DSL manager
+type YaoWidget struct {
+	typ  types.Type // The widget type
+	kind kind       // The widget loader
+	root string     // The relative path of the widget DSL
+	fs   types.IO   // The file system IO interface
+	db   types.IO   // The database IO interface
+}
+
+// New returns a new widget DSL manager
+func New(typ types.Type, root string, fs types.IO, db types.IO) types.Manager {
+	return &YaoWidget{typ: typ, kind: kinds[typ], root: root, fs: fs, db: db}
+}
+
+// Loaded return all loaded DSLs
+func (w *YaoWidget) Loaded(ctx context.Context) (map[string]*types.Info, error) {
+	infos := map[string]*types.Info{}
+	for id, name := range w.kind.Names() {
+		infos[id] = &types.Info{
+			ID:    id,
+			Path:  types.ToPath(w.typ, id),
+			Type:  w.typ,
+			Label: name,
+		}
+	}
+	return infos, nil
+}
+
+// Load will unload the DSL first, then load the DSL from DB or file system
+func (w *YaoWidget) Load(ctx context.Context, options *types.LoadOptions) error {
+	if options == nil {
+		return fmt.Errorf("load options is required")
+	}
+
+	if options.ID == "" {
+		return fmt.Errorf("load options id is required")
+	}
+
+	return w.load(options.ID, options.Source, options.Path, options.Store)
+}
+
+// Reload will unload the DSL first, then reload the DSL from DB or file system
+func (w *YaoWidget) Reload(ctx context.Context, options *types.ReloadOptions) error {
+	if options == nil {
+		return fmt.Errorf("reload options is required")
+	}
+
+	if options.ID == "" {
+		return fmt.Errorf("reload options id is required")
+	}
+
+	return w.load(options.ID, options.Source, options.Path, options.Store)
+}
+
+// Unload will unload the DSL from memory
+func (w *YaoWidget) Unload(ctx context.Context, options *types.UnloadOptions) error {
+	if options == nil {
+		return fmt.Errorf("unload options is required")
+	}
+
+	if options.ID == "" {
+		return fmt.Errorf("unload options id is required")
+	}
+
+	if !w.kind.Has(options.ID) {
+		return fmt.Errorf("%s %s not found", w.typ, options.ID)
+	}
+
+	w.kind.Unload(options.ID)
+	return nil
+}
+
+// load replaces the widget with the given id
+func (w *YaoWidget) load(id, source, path string, storeType types.StoreType) error {
+	switch {
+	case source != "":
+		// Case 1: If Source is provided, use LoadSource
+		return w.kind.LoadSource([]byte(source), id)
+
+	case path != "" && storeType == types.StoreTypeFile:
+		// Case 2: If Path is provided and Store is file, use LoadFile with Path
+		return w.kind.LoadFile(w.root, path)
+
+	case storeType == types.StoreTypeDB:
+		// Case 3: If Store is db, get Source from DB first
+		if w.db == nil {
+			return fmt.Errorf("db io is required for store type db")
+		}
+		source, exists, err := w.db.Source(id)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("%s %s not found in database", w.typ, id)
+		}
+		return w.kind.LoadSource([]byte(source), id)
+
+	default:
+		// Case 4: Default case, use LoadFile with ID
+		return w.kind.LoadFile(w.root, types.ToPath(w.typ, id))
+	}
+}
+
+// Dependents returns the loaded widgets bound to the DSL of the given type and id
+func (w *YaoWidget) Dependents(ctx context.Context, typ types.Type, id string) ([]string, error) {
+	ids := []string{}
+	for widgetID := range w.kind.Names() {
+		for _, r := range w.kind.Refs(widgetID) {
+			if r.Type == typ && r.ID == id {
+				ids = append(ids, widgetID)
+				break
+			}
+		}
+	}
+	return ids, nil
+}
+
+// Validate will validate the DSL from source, the bound DSLs must be loaded
+func (w *YaoWidget) Validate(ctx context.Context, source string) (bool, []types.LintMessage) {
+	var def struct {
+		Action struct {
+			Bind *struct {
+				Model string `json:"model"`
+				Store string `json:"store"`
+				Table string `json:"table"`
+				Form  string `json:"form"`
+			} `json:"bind"`
+		} `json:"action"`
+	}
+
+	file := filepath.Join(w.root, "<source>.yao")
+	err := application.Parse(file, []byte(source), &def)
+	if err != nil {
+		return false, []types.LintMessage{{Message: fmt.Sprintf("invalid %s source: %s", w.typ, err.Error()), Severity: types.LintSeverityError}}
+	}
+
+	messages := []types.LintMessage{}
+	if bind := def.Action.Bind; bind != nil {
+		if bind.Model != "" && !model.Exists(bind.Model) {
+			messages = append(messages, lintMissing("model", bind.Model))
+		}
+		if _, has := store.Pools[bind.Store]; bind.Store != "" && !has {
+			messages = append(messages, lintMissing("store", bind.Store))
+		}
+		if bind.Table != "" && !table.Exists(bind.Table) {
+			messages = append(messages, lintMissing("table", bind.Table))
+		}
+		if bind.Form != "" && !form.Exists(bind.Form) {
+			messages = append(messages, lintMissing("form", bind.Form))
+		}
+	}
+
+	return len(messages) == 0, messages
+}
+
+// Execute will execute the DSL
+func (w *YaoWidget) Execute(ctx context.Context, id string, method string, args ...any) (any, error) {
+	return nil, fmt.Errorf("Not implemented")
+}
+
+func lintMissing(typ string, id string) types.LintMessage {
+	return types.LintMessage{
+		Message:  fmt.Sprintf("action.bind.%s: %s %s is not loaded", typ, typ, id),
+		Severity: types.LintSeverityError,
+	}
+}
@@ -0,0 +1,112 @@
+package widget
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/yao/dsl"
+	"github.com/yaoapp/yao/dsl/io"
+	"github.com/yaoapp/yao/dsl/types"
+	"github.com/yaoapp/yao/widgets/table"
+)
+
+func tableSource(model string) string {
+	return fmt.Sprintf(`{"name": "Test Table", "action": {"bind": {"model": "%s"}}}`, model)
+}
+
+func modelSource(id string) string {
+	return fmt.Sprintf(`{
+  "name": "%s",
+  "table": { "name": "%s" },
+  "columns": [
+    { "name": "id", "type": "ID" },
+    { "name": "name", "type": "string", "length": 80 }
+  ]
+}`, id, id)
+}
+
+func TestWidgetLoad(t *testing.T) {
+	id := getTestID()
+	manager := New(types.TypeTable, "tables", io.NewFS(types.TypeTable), io.NewDB(types.TypeTable))
+
+	// Test Load with nil options
+	err := manager.Load(context.Background(), nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "load options is required")
+
+	// Test Load with Source
+	err = manager.Load(context.Background(), &types.LoadOptions{ID: id, Source: tableSource("__yao.dsl")})
+	assert.NoError(t, err)
+	assert.True(t, table.Exists(id))
+
+	infos, err := manager.Loaded(context.Background())
+	assert.NoError(t, err)
+	assert.Contains(t, infos, id)
+	assert.Equal(t, "Test Table", infos[id].Label)
+
+	// Test Dependents
+	ids, err := manager.(types.DependentManager).Dependents(context.Background(), types.TypeModel, "__yao.dsl")
+	assert.NoError(t, err)
+	assert.Contains(t, ids, id)
+
+	// Test Unload
+	err = manager.Unload(context.Background(), &types.UnloadOptions{ID: id})
+	assert.NoError(t, err)
+	assert.False(t, table.Exists(id))
+}
+
+func TestWidgetRegistered(t *testing.T) {
+	for _, typ := range []types.Type{types.TypeTable, types.TypeForm, types.TypeList, types.TypeChart, types.TypeDashboard} {
+		_, err := dsl.New(typ)
+		assert.NoError(t, err, typ)
+	}
+}
+
+func TestWidgetDependents(t *testing.T) {
+	ctx := context.Background()
+	modelID := getTestID()
+	tableID := getTestID()
+
+	models, err := dsl.New(types.TypeModel)
+	assert.NoError(t, err)
+	tables, err := dsl.New(types.TypeTable)
+	assert.NoError(t, err)
+
+	err = models.Create(ctx, &types.CreateOptions{ID: modelID, Source: modelSource(modelID), Store: types.StoreTypeDB})
+	assert.NoError(t, err)
+	err = tables.Create(ctx, &types.CreateOptions{ID: tableID, Source: tableSource(modelID), Store: types.StoreTypeDB})
+	assert.NoError(t, err)
+	assert.True(t, table.Exists(tableID))
+
+	// Updating the model loads the bound table again
+	err = models.Update(ctx, &types.UpdateOptions{ID: modelID, Source: modelSource(modelID)})
+	assert.NoError(t, err)
+	assert.True(t, table.Exists(tableID))
+
+	// Deleting the model unloads the bound table
+	err = models.Delete(ctx, &types.DeleteOptions{ID: modelID})
+	assert.NoError(t, err)
+	assert.False(t, table.Exists(tableID))
+
+	// Clean up
+	err = io.NewDB(types.TypeTable).Delete(tableID)
+	assert.NoError(t, err)
+}
+
+func TestWidgetValidate(t *testing.T) {
+	manager := New(types.TypeForm, "forms", nil, nil)
+
+	valid, messages := manager.Validate(context.Background(), tableSource("__yao.dsl"))
+	assert.True(t, valid)
+	assert.Empty(t, messages)
+
+	valid, messages = manager.Validate(context.Background(), tableSource("not.a.model"))
+	assert.False(t, valid)
+	assert.Len(t, messages, 1)
+	assert.Contains(t, messages[0].Message, "not.a.model")
+
+	valid, _ = manager.Validate(context.Background(), "{")
+	assert.False(t, valid)
+}
@@ -0,0 +1,386 @@
+package dump
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yaoapp/gou/connector"
+	"github.com/yaoapp/gou/model"
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/xun/capsule"
+	"github.com/yaoapp/xun/dbal/query"
+	"github.com/yaoapp/yao/attachment"
+	"github.com/yaoapp/yao/config"
+)
+
+// Dump exports the selected models into a zip archive with a manifest.
+// A dump of every model without where and since is a full dump, it also
+// contains the application data folder.
+func Dump(output string, option Option, progress Progress) (*Manifest, error) {
+	if _, err := os.Stat(output); !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%s exists", output)
+	}
+
+	if option.Where != "" && option.isAll() {
+		return nil, fmt.Errorf("the where clause requires the model names")
+	}
+
+	if option.ChunkSize <= 0 {
+		option.ChunkSize = ChunkSizeDefault
+	}
+
+	if progress == nil {
+		progress = func(stage string, name string, curr int, total int) {}
+	}
+
+	candidates := []string{}
+	for name := range model.Models {
+		candidates = append(candidates, name)
+	}
+
+	names, named, err := match(option.Selection, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "yao-dump-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	manifest := &Manifest{
+		Version:   ManifestVersion,
+		CreatedAt: time.Now(),
+		Full:      option.isAll() && option.Where == "" && option.Since == nil,
+		Where:     option.Where,
+		Since:     option.Since,
+		Models:    []ModelManifest{},
+		Files:     map[string]string{},
+	}
+
+	// Path in the archive => path on the disk
+	files := map[string]string{}
+	attachments := [][2]string{}
+	for _, name := range names {
+		mod := model.Select(name)
+		where := ""
+		if named[name] {
+			where = option.Where
+		}
+
+		var visit func(row map[string]interface{})
+		if option.Attachments && !manifest.Full && isAttachmentModel(name) {
+			visit = func(row map[string]interface{}) {
+				uploader, _ := row["uploader"].(string)
+				fileID, _ := row["file_id"].(string)
+				if uploader != "" && fileID != "" {
+					attachments = append(attachments, [2]string{uploader, fileID})
+				}
+			}
+		}
+
+		exported, err := exportModel(mod, where, option.Since, option.ChunkSize, dir, visit, progress)
+		if err != nil {
+			return nil, fmt.Errorf("export %s: %s", name, err.Error())
+		}
+
+		for _, file := range exported.Files {
+			files[file] = filepath.Join(dir, filepath.FromSlash(file))
+		}
+		manifest.Models = append(manifest.Models, *exported)
+	}
+
+	// The data folder of a full dump, or the attachment files
+	if manifest.Full {
+		if err := dataFiles(config.Conf.DataRoot, files); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, file := range attachments {
+			attachmentFile(file[0], file[1], files)
+		}
+	}
+
+	if err := write(output, manifest, files, progress); err != nil {
+		os.Remove(output)
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// exportModel exports the rows of a model into json files of chunk size rows
+func exportModel(mod *model.Model, where string, since *time.Time, chunkSize int, dir string, visit func(row map[string]interface{}), progress Progress) (*ModelManifest, error) {
+	base, err := queryOf(mod)
+	if err != nil {
+		return nil, err
+	}
+
+	table := mod.MetaData.Table.Name
+	incremental := since != nil && mod.MetaData.Option.Timestamps
+	newQuery := func() query.Query {
+		qb := base.New().Table(table)
+		if where != "" {
+			qb.WhereRaw(where)
+		}
+		if incremental {
+			qb.Where(func(qb query.Query) {
+				qb.Where("updated_at", ">=", *since).OrWhere("created_at", ">=", *since)
+			})
+		}
+		return qb
+	}
+
+	total, err := newQuery().Count()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ModelManifest{
+		Name:        mod.Name,
+		Table:       table,
+		PrimaryKey:  mod.PrimaryKey,
+		Incremental: incremental,
+		Files:       []string{},
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "model"), 0755); err != nil {
+		return nil, err
+	}
+
+	for page := 1; result.Rows < int(total); page++ {
+		qb := newQuery()
+		if mod.PrimaryKey != "" {
+			qb.OrderBy(mod.PrimaryKey, "asc")
+		}
+
+		rows, err := qb.Offset((page - 1) * chunkSize).Limit(chunkSize).Get()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rows) == 0 {
+			break
+		}
+
+		data := ExportData{Model: mod.Name, Columns: []string{}, Values: [][]interface{}{}}
+		for column := range rows[0] {
+			data.Columns = append(data.Columns, column)
+		}
+		sort.Strings(data.Columns)
+
+		for _, row := range rows {
+			values := make([]interface{}, len(data.Columns))
+			for i, column := range data.Columns {
+				values[i] = normalize(row[column])
+			}
+			data.Values = append(data.Values, values)
+			if visit != nil {
+				visit(map[string]interface{}(row))
+			}
+		}
+
+		bytes, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+
+		file := fmt.Sprintf("model/%s.%d.json", mod.Name, page)
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(file)), bytes, 0644); err != nil {
+			return nil, err
+		}
+
+		result.Files = append(result.Files, file)
+		result.Rows += len(rows)
+		progress("export", mod.Name, result.Rows, int(total))
+	}
+
+	return result, nil
+}
+
+// queryOf returns the query builder of the model connector
+func queryOf(mod *model.Model) (query.Query, error) {
+	if mod.MetaData.Connector == "" || mod.MetaData.Connector == "default" {
+		if capsule.Global == nil {
+			return nil, fmt.Errorf("database is not connected")
+		}
+		return capsule.Global.Query(), nil
+	}
+
+	conn, err := connector.Select(mod.MetaData.Connector)
+	if err != nil {
+		return nil, fmt.Errorf("select connector %s error: %s", mod.MetaData.Connector, err.Error())
+	}
+	return conn.Query()
+}
+
+// normalize converts the database values to values inserted back as they are
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05.999999")
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return v.Format("2006-01-02 15:04:05.999999")
+	}
+	return value
+}
+
+// dataFiles adds the files of the data folder
+func dataFiles(root string, files map[string]string) error {
+	if _, err := os.Stat(root); err != nil {
+		return nil
+	}
+
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files["data/"+filepath.ToSlash(rel)] = path
+		return nil
+	})
+}
+
+// attachmentFile adds the file of an attachment saved in the data folder,
+// the files of the other storages are not dumped.
+func attachmentFile(uploader string, fileID string, files map[string]string) {
+	manager, has := attachment.Managers[uploader]
+	if !has {
+		log.Warn("[Dump] attachment %s: uploader %s not found", fileID, uploader)
+		return
+	}
+
+	if manager.Driver != "" && manager.Driver != "local" {
+		log.Warn("[Dump] attachment %s: %s storage files are not dumped", fileID, manager.Driver)
+		return
+	}
+
+	path, _, err := manager.LocalPath(context.Background(), fileID)
+	if err != nil {
+		log.Warn("[Dump] attachment %s: %s", fileID, err.Error())
+		return
+	}
+
+	rel, err := filepath.Rel(config.Conf.DataRoot, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+		log.Warn("[Dump] attachment %s: %s is not in the data folder", fileID, path)
+		return
+	}
+	files["data/"+filepath.ToSlash(rel)] = path
+}
+
+// write writes the files and the manifest into the zip archive
+func write(output string, manifest *Manifest, files map[string]string, progress Progress) error {
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return err
+	}
+
+	outfile, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer outfile.Close()
+
+	w := zip.NewWriter(outfile)
+	names := []string{}
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		progress("compress", name, i+1, len(names))
+		hash, err := addFile(w, name, files[name])
+		if err != nil {
+			w.Close()
+			return err
+		}
+		manifest.Files[name] = hash
+	}
+
+	manifest.Checksum = checksum(manifest.Files)
+	bytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		w.Close()
+		return err
+	}
+
+	f, err := w.Create(ManifestFile)
+	if err != nil {
+		w.Close()
+		return err
+	}
+
+	if _, err := f.Write(bytes); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// addFile adds a file into the zip archive and returns its sha256
+func addFile(w *zip.Writer, name string, path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	f, err := w.Create(name)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, hash), file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// checksum returns the sha256 of the sorted files and their hashes
+func checksum(files map[string]string) string {
+	names := []string{}
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hash := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(hash, "%s %s\n", files[name], name)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func isAttachmentModel(name string) bool {
+	for _, attachmentModel := range AttachmentModels {
+		if name == attachmentModel {
+			return true
+		}
+	}
+	return false
+}
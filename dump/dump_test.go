@@ -0,0 +1,146 @@
+package dump
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaoapp/gou/model"
+	"github.com/yaoapp/kun/maps"
+	"github.com/yaoapp/yao/config"
+	"github.com/yaoapp/yao/test"
+)
+
+func TestMatch(t *testing.T) {
+	candidates := []string{"user", "user.profile", "pet", "__yao.attachment", "__yao.agent.chat", "__yao.agent.message"}
+
+	names, named, err := match(Selection{}, candidates)
+	require.NoError(t, err)
+	assert.Len(t, names, len(candidates))
+	assert.True(t, named["pet"])
+
+	names, named, err = match(Selection{Names: []string{"user*"}}, candidates)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user", "user.profile"}, names)
+	assert.True(t, named["user.profile"])
+
+	names, named, err = match(Selection{Names: []string{"pet"}, Attachments: true, Chats: true}, candidates)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"__yao.agent.chat", "__yao.agent.message", "__yao.attachment", "pet"}, names)
+	assert.True(t, named["pet"])
+	assert.False(t, named["__yao.attachment"])
+
+	_, _, err = match(Selection{Names: []string{"not.found"}}, candidates)
+	assert.Error(t, err)
+
+	_, _, err = match(Selection{Names: []string{"[user"}}, candidates)
+	assert.Error(t, err)
+}
+
+func TestDumpRestoreUpsert(t *testing.T) {
+	test.Prepare(t, config.Conf)
+	defer test.Clean()
+
+	if !model.Exists("__yao.role") {
+		t.Skip("__yao.role model not loaded, skipping test")
+	}
+
+	mod := prepareRoles(t)
+	output := filepath.Join(t.TempDir(), "roles.zip")
+
+	manifest, err := Dump(output, Option{Selection: Selection{Names: []string{"__yao.role"}}, Where: "role_id = 'dump-admin'"}, nil)
+	require.NoError(t, err)
+	assert.False(t, manifest.Full)
+	require.Len(t, manifest.Models, 1)
+	assert.Equal(t, 1, manifest.Models[0].Rows)
+
+	// Change the dumped role and remove it, the upsert restores it and keeps the other one
+	_, err = mod.UpdateWhere(model.QueryParam{Wheres: []model.QueryWhere{{Column: "role_id", Value: "dump-admin"}}}, maps.MapStrAny{"name": "Changed"})
+	require.NoError(t, err)
+
+	archive, err := Open(output)
+	require.NoError(t, err)
+	defer archive.Close()
+	require.NotNil(t, archive.Manifest)
+	require.NoError(t, archive.Verify())
+
+	err = archive.Restore(RestoreOption{Upsert: true}, nil)
+	require.NoError(t, err)
+
+	roles, err := mod.Get(model.QueryParam{Orders: []model.QueryOrder{{Column: "role_id"}}})
+	require.NoError(t, err)
+	require.Len(t, roles, 2)
+	assert.Equal(t, "Administrator", roles[0].Get("name"))
+	assert.Equal(t, "dump-user", roles[1].Get("role_id"))
+}
+
+func TestDumpIncremental(t *testing.T) {
+	test.Prepare(t, config.Conf)
+	defer test.Clean()
+
+	if !model.Exists("__yao.role") {
+		t.Skip("__yao.role model not loaded, skipping test")
+	}
+
+	prepareRoles(t)
+	output := filepath.Join(t.TempDir(), "roles.zip")
+
+	since := time.Now().Add(time.Hour)
+	manifest, err := Dump(output, Option{Selection: Selection{Names: []string{"__yao.role"}}, Since: &since}, nil)
+	require.NoError(t, err)
+	require.Len(t, manifest.Models, 1)
+	assert.True(t, manifest.Models[0].Incremental)
+	assert.Equal(t, 0, manifest.Models[0].Rows)
+
+	archive, err := Open(output)
+	require.NoError(t, err)
+	defer archive.Close()
+
+	// An incremental dump never recreates the tables
+	err = archive.Restore(RestoreOption{}, nil)
+	assert.Error(t, err)
+}
+
+func TestVerifyTampered(t *testing.T) {
+	test.Prepare(t, config.Conf)
+	defer test.Clean()
+
+	if !model.Exists("__yao.role") {
+		t.Skip("__yao.role model not loaded, skipping test")
+	}
+
+	prepareRoles(t)
+	output := filepath.Join(t.TempDir(), "roles.zip")
+
+	manifest, err := Dump(output, Option{Selection: Selection{Names: []string{"__yao.role"}}}, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, manifest.Models[0].Files)
+
+	archive, err := Open(output)
+	require.NoError(t, err)
+	defer archive.Close()
+
+	file := filepath.Join(archive.Dir, filepath.FromSlash(manifest.Models[0].Files[0]))
+	require.NoError(t, os.WriteFile(file, []byte(`{"model":"__yao.role","columns":[],"values":[]}`), 0644))
+	assert.Error(t, archive.Verify())
+	assert.Error(t, archive.Restore(RestoreOption{Upsert: true}, nil))
+
+	// The changed manifest is detected by its checksum
+	archive.Manifest.Files[manifest.Models[0].Files[0]] = "changed"
+	assert.Error(t, archive.Verify())
+}
+
+func prepareRoles(t *testing.T) *model.Model {
+	mod := model.Select("__yao.role")
+	_, err := mod.DestroyWhere(model.QueryParam{})
+	require.NoError(t, err)
+
+	_, err = mod.Create(maps.MapStrAny{"role_id": "dump-admin", "name": "Administrator"})
+	require.NoError(t, err)
+	_, err = mod.Create(maps.MapStrAny{"role_id": "dump-user", "name": "User"})
+	require.NoError(t, err)
+	return mod
+}
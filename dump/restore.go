@@ -0,0 +1,375 @@
+package dump
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/yaoapp/gou/model"
+	"github.com/yaoapp/xun/dbal/query"
+	"github.com/yaoapp/yao/config"
+)
+
+// insertSize the number of rows per insert statement
+const insertSize = 100
+
+// Archive an unzipped dump archive
+type Archive struct {
+	Dir      string    // The unzipped files
+	Manifest *Manifest // Nil for the archives dumped before the manifest
+}
+
+// Open unzips the dump archive into a temporary folder, the caller should
+// Close the archive to remove it.
+func Open(file string) (*Archive, error) {
+	reader, err := zip.OpenReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	dir, err := os.MkdirTemp("", "yao-restore-*")
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &Archive{Dir: dir}
+	for _, f := range reader.File {
+		if err := unzip(f, dir); err != nil {
+			archive.Close()
+			return nil, err
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if os.IsNotExist(err) {
+		return archive, nil
+	}
+
+	if err != nil {
+		archive.Close()
+		return nil, err
+	}
+
+	archive.Manifest = &Manifest{}
+	if err := json.Unmarshal(content, archive.Manifest); err != nil {
+		archive.Close()
+		return nil, fmt.Errorf("invalid manifest: %s", err.Error())
+	}
+	return archive, nil
+}
+
+// Close removes the unzipped files
+func (archive *Archive) Close() error {
+	return os.RemoveAll(archive.Dir)
+}
+
+// Verify checks the manifest checksum and the hash of every file in the archive
+func (archive *Archive) Verify() error {
+	manifest := archive.Manifest
+	if manifest == nil {
+		return fmt.Errorf("the archive has no manifest")
+	}
+
+	if manifest.Version > ManifestVersion {
+		return fmt.Errorf("manifest version %d is not supported", manifest.Version)
+	}
+
+	if checksum(manifest.Files) != manifest.Checksum {
+		return fmt.Errorf("manifest checksum mismatch")
+	}
+
+	for name, hash := range manifest.Files {
+		sum, err := hashFile(filepath.Join(archive.Dir, filepath.FromSlash(name)))
+		if err != nil {
+			return fmt.Errorf("file %s: %s", name, err.Error())
+		}
+		if sum != hash {
+			return fmt.Errorf("file %s checksum mismatch", name)
+		}
+	}
+
+	for _, mod := range manifest.Models {
+		for _, file := range mod.Files {
+			if _, has := manifest.Files[file]; !has {
+				return fmt.Errorf("file %s of model %s is not in the manifest", file, mod.Name)
+			}
+		}
+	}
+
+	return filepath.Walk(archive.Dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(archive.Dir, path)
+		if err != nil {
+			return err
+		}
+
+		name := filepath.ToSlash(rel)
+		if _, has := manifest.Files[name]; !has && name != ManifestFile {
+			return fmt.Errorf("file %s is not in the manifest", name)
+		}
+		return nil
+	})
+}
+
+// Restore verifies the archive, then restores the selected models. The tables
+// are recreated unless the upsert option is set, an incremental dump can
+// only be upserted.
+func (archive *Archive) Restore(option RestoreOption, progress Progress) error {
+	if err := archive.Verify(); err != nil {
+		return err
+	}
+
+	manifest := archive.Manifest
+	if manifest.Since != nil && !option.Upsert {
+		return fmt.Errorf("the dump is incremental since %s, restore it with upsert", manifest.Since.Format("2006-01-02 15:04:05"))
+	}
+
+	if progress == nil {
+		progress = func(stage string, name string, curr int, total int) {}
+	}
+
+	candidates := []string{}
+	dumped := map[string]ModelManifest{}
+	for _, mod := range manifest.Models {
+		candidates = append(candidates, mod.Name)
+		dumped[mod.Name] = mod
+	}
+
+	names, _, err := match(option.Selection, candidates)
+	if err != nil {
+		return err
+	}
+
+	// Check all the models before touching the data
+	for _, name := range names {
+		if !model.Exists(name) {
+			return fmt.Errorf("model %s is not loaded", name)
+		}
+	}
+
+	for i, name := range names {
+		mod := model.Select(name)
+		progress("migrate", name, i+1, len(names))
+		if err := mod.Migrate(!option.Upsert, model.WithDonotInsertValues(option.MigrateNoInsert)); err != nil {
+			return fmt.Errorf("migrate %s: %s", name, err.Error())
+		}
+	}
+
+	for _, name := range names {
+		if err := archive.restoreModel(model.Select(name), dumped[name], option.Upsert, progress); err != nil {
+			return fmt.Errorf("restore %s: %s", name, err.Error())
+		}
+	}
+
+	// Replace the data folder with the one of a full dump, otherwise merge the files
+	if manifest.Full && option.isAll() && !option.Upsert {
+		if err := os.RemoveAll(config.Conf.DataRoot); err != nil {
+			return err
+		}
+		return archive.restoreFiles(progress)
+	}
+
+	if option.isAll() || option.Attachments {
+		return archive.restoreFiles(progress)
+	}
+	return nil
+}
+
+// restoreModel inserts or upserts the dumped rows of a model
+func (archive *Archive) restoreModel(mod *model.Model, dumped ModelManifest, upsert bool, progress Progress) error {
+	base, err := queryOf(mod)
+	if err != nil {
+		return err
+	}
+
+	table := mod.MetaData.Table.Name
+	restored := 0
+	for _, file := range dumped.Files {
+		data, err := readExport(filepath.Join(archive.Dir, filepath.FromSlash(file)))
+		if err != nil {
+			return err
+		}
+
+		rows := make([]map[string]interface{}, 0, len(data.Values))
+		for _, values := range data.Values {
+			row := map[string]interface{}{}
+			for i, column := range data.Columns {
+				if i < len(values) {
+					row[column] = values[i]
+				}
+			}
+			rows = append(rows, row)
+		}
+
+		if !upsert {
+			for start := 0; start < len(rows); start += insertSize {
+				end := start + insertSize
+				if end > len(rows) {
+					end = len(rows)
+				}
+				if err := base.New().Table(table).Insert(rows[start:end]); err != nil {
+					return err
+				}
+			}
+		} else {
+			for _, row := range rows {
+				if err := upsertRow(base, table, mod.PrimaryKey, row); err != nil {
+					return err
+				}
+			}
+		}
+
+		restored += len(rows)
+		progress("restore", mod.Name, restored, dumped.Rows)
+	}
+
+	return nil
+}
+
+// restoreFiles copies the data files of the archive into the data folder
+func (archive *Archive) restoreFiles(progress Progress) error {
+	names := []string{}
+	for name := range archive.Manifest.Files {
+		if strings.HasPrefix(name, "data/") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		progress("files", name, i+1, len(names))
+		src := filepath.Join(archive.Dir, filepath.FromSlash(name))
+		dst := filepath.Join(config.Conf.DataRoot, filepath.FromSlash(strings.TrimPrefix(name, "data/")))
+		if err := copyFile(src, dst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upsertRow updates the row with the same primary key, or inserts it
+func upsertRow(base query.Query, table string, primaryKey string, row map[string]interface{}) error {
+	id, has := row[primaryKey]
+	if primaryKey == "" || !has || id == nil {
+		return base.New().Table(table).Insert(row)
+	}
+
+	exists, err := base.New().Table(table).Where(primaryKey, id).Exists()
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		_, err = base.New().Table(table).Where(primaryKey, id).Update(row)
+		return err
+	}
+	return base.New().Table(table).Insert(row)
+}
+
+// readExport reads an exported model file, the integers are kept as int64
+func readExport(path string) (*ExportData, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &ExportData{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if err := decoder.Decode(data); err != nil {
+		return nil, fmt.Errorf("invalid file %s: %s", filepath.Base(path), err.Error())
+	}
+
+	for _, values := range data.Values {
+		for i, value := range values {
+			number, ok := value.(json.Number)
+			if !ok {
+				continue
+			}
+			if v, err := number.Int64(); err == nil {
+				values[i] = v
+			} else if v, err := number.Float64(); err == nil {
+				values[i] = v
+			}
+		}
+	}
+	return data, nil
+}
+
+// unzip extracts a file of the archive into dir
+func unzip(f *zip.File, dir string) error {
+	path := filepath.Join(dir, f.Name)
+	if !strings.HasPrefix(path, filepath.Clean(dir)+string(os.PathSeparator)) {
+		return fmt.Errorf("invalid file path %s", f.Name)
+	}
+
+	if f.FileInfo().IsDir() {
+		return os.MkdirAll(path, 0755)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	src, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	_, err = io.Copy(dst, src)
+	return err
+}
+
+func copyFile(src string, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
+
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
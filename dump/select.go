@@ -0,0 +1,95 @@
+package dump
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/yaoapp/gou/model"
+	"github.com/yaoapp/yao/kb"
+)
+
+// Select returns the loaded models matched by the selection, sorted by name
+func Select(selection Selection) ([]string, error) {
+	candidates := []string{}
+	for name := range model.Models {
+		candidates = append(candidates, name)
+	}
+	selected, _, err := match(selection, candidates)
+	return selected, err
+}
+
+// match returns the candidates matched by the selection, and the ones matched
+// by the names (the where clause applies to them only). Empty names match all
+// the candidates, each name or pattern must match at least one of them.
+func match(selection Selection, candidates []string) ([]string, map[string]bool, error) {
+	named := map[string]bool{}
+	selected := map[string]bool{}
+
+	if len(selection.Names) == 0 {
+		for _, name := range candidates {
+			named[name] = true
+			selected[name] = true
+		}
+	}
+
+	for _, pattern := range selection.Names {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, nil, fmt.Errorf("invalid model pattern %s: %s", pattern, err.Error())
+		}
+
+		found := false
+		for _, name := range candidates {
+			if ok, _ := path.Match(pattern, name); ok {
+				named[name] = true
+				selected[name] = true
+				found = true
+			}
+		}
+
+		if !found {
+			return nil, nil, fmt.Errorf("model %s not found", pattern)
+		}
+	}
+
+	for _, name := range groups(selection) {
+		for _, candidate := range candidates {
+			if candidate == name {
+				selected[name] = true
+				break
+			}
+		}
+	}
+
+	names := []string{}
+	for name := range selected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, named, nil
+}
+
+// groups returns the models of the optional groups in the selection
+func groups(selection Selection) []string {
+	names := []string{}
+	if selection.Attachments {
+		names = append(names, AttachmentModels...)
+	}
+
+	if selection.KB {
+		names = append(names, KBModels...)
+		if config, err := kb.GetConfig(); err == nil {
+			names = append(names, config.CollectionModel, config.DocumentModel)
+		}
+	}
+
+	if selection.Chats {
+		names = append(names, ChatModels...)
+	}
+	return names
+}
+
+// isAll checks if the selection is every model
+func (selection Selection) isAll() bool {
+	return len(selection.Names) == 0
+}
@@ -0,0 +1,79 @@
+package dump
+
+import "time"
+
+// ManifestFile the manifest file name in the dump archive
+const ManifestFile = "manifest.json"
+
+// ManifestVersion the current manifest version
+const ManifestVersion = 1
+
+// ChunkSizeDefault the default number of rows per exported file
+const ChunkSizeDefault = 5000
+
+// Models of the optional groups, dumped only when asked
+var (
+	// AttachmentModels the attachment models, the files are dumped too
+	AttachmentModels = []string{"__yao.attachment"}
+
+	// KBModels the knowledge base collection and document models (the vectors are not dumped)
+	KBModels = []string{"__yao.kb.collection", "__yao.kb.document"}
+
+	// ChatModels the agent chat history models
+	ChatModels = []string{"__yao.agent.chat", "__yao.agent.message", "__yao.agent.resume", "__yao.agent.search"}
+)
+
+// Selection the models selected by dump and restore
+type Selection struct {
+	Names       []string `json:"names,omitempty"`       // Model names or glob patterns, empty means all models
+	Attachments bool     `json:"attachments,omitempty"` // Include the attachments and their files
+	KB          bool     `json:"kb,omitempty"`          // Include the knowledge base collections and documents
+	Chats       bool     `json:"chats,omitempty"`       // Include the agent chat history
+}
+
+// Option the dump option
+type Option struct {
+	Selection
+	Where     string     `json:"where,omitempty"`      // SQL where clause applied to the named models
+	Since     *time.Time `json:"since,omitempty"`      // Only rows created or updated since, for incremental dumps
+	ChunkSize int        `json:"chunk_size,omitempty"` // Rows per exported file, default is 5000
+}
+
+// RestoreOption the restore option
+type RestoreOption struct {
+	Selection
+	Upsert          bool `json:"upsert,omitempty"`            // Upsert the rows by primary key instead of recreating the tables
+	MigrateNoInsert bool `json:"migrate_no_insert,omitempty"` // Do not insert the model values when migrating
+}
+
+// Progress the progress callback, stage is "export", "compress", "migrate", "restore" or "files"
+type Progress func(stage string, name string, curr int, total int)
+
+// Manifest the dump manifest, saved as manifest.json in the archive
+type Manifest struct {
+	Version   int               `json:"version"`
+	CreatedAt time.Time         `json:"created_at"`
+	Full      bool              `json:"full,omitempty"` // All models without filters and the whole data folder
+	Where     string            `json:"where,omitempty"`
+	Since     *time.Time        `json:"since,omitempty"`
+	Models    []ModelManifest   `json:"models"`
+	Files     map[string]string `json:"files"`    // Path in the archive => sha256
+	Checksum  string            `json:"checksum"` // sha256 of the sorted files and their hashes
+}
+
+// ModelManifest the dumped rows of a model
+type ModelManifest struct {
+	Name        string   `json:"name"`
+	Table       string   `json:"table"`
+	PrimaryKey  string   `json:"primary_key,omitempty"`
+	Rows        int      `json:"rows"`
+	Incremental bool     `json:"incremental,omitempty"` // Only the rows changed since the manifest since
+	Files       []string `json:"files"`
+}
+
+// ExportData the content of an exported model file
+type ExportData struct {
+	Model   string          `json:"model"`
+	Columns []string        `json:"columns"`
+	Values  [][]interface{} `json:"values"`
+}
@@ -53,7 +53,8 @@ import (
 	"github.com/yaoapp/yao/widget"
 	"github.com/yaoapp/yao/widgets"
 
-	_ "github.com/yaoapp/yao/trace" // register trace handler/listener via init()
+	_ "github.com/yaoapp/yao/dsl/widget" // register widget DSL managers via init()
+	_ "github.com/yaoapp/yao/trace"      // register trace handler/listener via init()
 )
 
 // LoadHooks used to load custom widgets/processes
@@ -264,7 +265,15 @@ func Load(cfg config.Config, options LoadOption, progressCallback ...func(string
 	}
 
 	// Start Event Service (handlers registered via init(), e.g. trace)
+	// Durable mode replays the unacknowledged events of durable handlers (e.g. robot)
 	err = loadStep("Event", func() error {
+		if cfg.Event.Durable && !event.IsStarted() {
+			store, err := event.NewLogStore(cfg.Event.Log)
+			if err != nil {
+				return err
+			}
+			event.SetStore(store)
+		}
 		return event.Start()
 	}, callback)
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/yaoapp/yao/event/types"
 )
@@ -27,6 +28,7 @@ func prefixOf(typ string) string {
 
 // Push delivers an event asynchronously (fire-and-forget).
 // SID and Auth are extracted from ctx automatically.
+// With Delay or At the event is held by the timer wheel until it is due.
 // Returns the auto-generated event ID.
 func Push(ctx context.Context, typ string, payload any, opts ...types.PushOption) (string, error) {
 	prefix := prefixOf(typ)
@@ -34,7 +36,6 @@ func Push(ctx context.Context, typ string, payload any, opts ...types.PushOption
 	if err != nil {
 		return "", err
 	}
-	_ = entry // used for queue config lookup
 
 	ev := &types.Event{
 		Type:    typ,
@@ -48,18 +49,38 @@ func Push(ctx context.Context, typ string, payload any, opts ...types.PushOption
 		opt(ev)
 	}
 
+	var q *eventQueue
+	if ev.Queue != "" {
+		q, err = svc.queues.get(ev.Queue)
+		if err != nil {
+			return ev.ID, err
+		}
+	}
+
+	// Durable handler: write the event to the log before anyone sees it
+	if entry.Durable {
+		if store := getStore(); store != nil {
+			if err := persist(store, ev); err != nil {
+				return ev.ID, err
+			}
+		}
+	}
+
+	// Delayed: the timer wheel routes the event when it is due
+	if ev.DeliverAt.After(time.Now()) {
+		getDelays().add(context.WithoutCancel(ctx), ev)
+		return ev.ID, nil
+	}
+
 	// Notify listeners and subscribers (non-blocking, before handler)
 	svc.lmgr.notify(ev)
 	svc.smgr.notify(ev)
 
 	// Route to queue or direct dispatch
-	if ev.Queue != "" {
-		q, err := svc.queues.get(ev.Queue)
-		if err != nil {
-			return ev.ID, err
-		}
+	if q != nil {
 		discard := make(chan types.Result, 1)
 		if err := q.enqueue(ctx, ev, discard); err != nil {
+			ack(ev) // rejected, the caller gets the error instead of a replay
 			return ev.ID, err
 		}
 		return ev.ID, nil
@@ -69,6 +90,7 @@ func Push(ctx context.Context, typ string, payload any, opts ...types.PushOption
 	discard := make(chan types.Result, 1)
 	pushCtx := context.WithoutCancel(ctx)
 	if _, err := pool.dispatch(pushCtx, ev, discard); err != nil {
+		ack(ev)
 		return ev.ID, fmt.Errorf("event push: worker unavailable: %w", err)
 	}
 	return ev.ID, nil
@@ -95,6 +117,9 @@ func Call(ctx context.Context, typ string, payload any, opts ...types.PushOption
 	for _, opt := range opts {
 		opt(ev)
 	}
+	if !ev.DeliverAt.IsZero() {
+		return ev.ID, nil, ErrDelayedCall
+	}
 
 	// Notify listeners and subscribers
 	svc.lmgr.notify(ev)
@@ -143,6 +168,7 @@ func QueueCreate(prefix string, id ...string) (string, error) {
 	if err := svc.queues.create(prefix, queueID, entry.QueueSize, pool); err != nil {
 		return "", err
 	}
+	persistQueue(entry, queueID)
 	return queueID, nil
 }
 
@@ -150,10 +176,13 @@ func QueueCreate(prefix string, id ...string) (string, error) {
 // Rejects new events immediately; existing events are drained internally.
 func QueueRelease(queueID string) {
 	svc.queues.release(queueID)
+	releaseQueue(queueID)
 }
 
 // QueueAbort forcefully releases a queue (async).
 // Rejects new events, discards pending events, waits for in-flight to finish.
+// Discarded durable events are acknowledged.
 func QueueAbort(queueID string) {
 	svc.queues.abortOne(queueID)
+	releaseQueue(queueID)
 }
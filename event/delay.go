@@ -0,0 +1,245 @@
+package event
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/yao/event/types"
+)
+
+// Timer wheel resolution: 512 slots of 10ms cover ~5s per round; longer delays wait for more rounds.
+const (
+	wheelTick  = 10 * time.Millisecond
+	wheelSlots = 512
+)
+
+// delayedEvent is a pending delayed event in the wheel.
+type delayedEvent struct {
+	ctx    context.Context
+	ev     *types.Event
+	seq    uint64 // push order, breaks ties between events due at the same time
+	slot   int
+	rounds int
+}
+
+// timerWheel is a hashed timing wheel for delayed events.
+// The ticker goroutine only runs while events are pending. Due events of one tick
+// are fired in (DeliverAt, push order), so events bound to the same queue keep their order.
+type timerWheel struct {
+	mu      sync.Mutex
+	slots   []map[string]*delayedEvent // slot -> event ID -> entry
+	index   map[string]*delayedEvent   // event ID -> entry
+	cursor  int
+	seq     uint64
+	base    time.Time // wall clock of tick 0 of the current run
+	ticked  int64     // ticks processed since base
+	running bool
+	stopCh  chan struct{}
+	fire    func(ctx context.Context, ev *types.Event)
+}
+
+func newTimerWheel(fire func(ctx context.Context, ev *types.Event)) *timerWheel {
+	slots := make([]map[string]*delayedEvent, wheelSlots)
+	for i := range slots {
+		slots[i] = make(map[string]*delayedEvent)
+	}
+	return &timerWheel{
+		slots: slots,
+		index: make(map[string]*delayedEvent),
+		fire:  fire,
+	}
+}
+
+// add schedules an event for ev.DeliverAt.
+func (w *timerWheel) add(ctx context.Context, ev *types.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.running {
+		w.running = true
+		w.base = time.Now()
+		w.ticked = 0
+		w.stopCh = make(chan struct{})
+		go w.run(w.stopCh)
+	}
+
+	// Ticks from the wheel's current position, at least one
+	now := w.base.Add(time.Duration(w.ticked) * wheelTick)
+	ticks := int64((ev.DeliverAt.Sub(now) + wheelTick - 1) / wheelTick)
+	if ticks < 1 {
+		ticks = 1
+	}
+
+	w.seq++
+	entry := &delayedEvent{
+		ctx:    ctx,
+		ev:     ev,
+		seq:    w.seq,
+		slot:   (w.cursor + int(ticks%wheelSlots)) % wheelSlots,
+		rounds: int((ticks - 1) / wheelSlots),
+	}
+	w.slots[entry.slot][ev.ID] = entry
+	w.index[ev.ID] = entry
+}
+
+// cancel removes a pending event. Returns the event, nil if it is not pending.
+func (w *timerWheel) cancel(id string) *types.Event {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	entry, ok := w.index[id]
+	if !ok {
+		return nil
+	}
+	delete(w.index, id)
+	delete(w.slots[entry.slot], id)
+	return entry.ev
+}
+
+// run advances the wheel on the wall clock until no event is pending or stop is closed.
+func (w *timerWheel) run(stop chan struct{}) {
+	ticker := time.NewTicker(wheelTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			due, idle := w.advance(now)
+			for _, entry := range due {
+				w.fire(entry.ctx, entry.ev)
+			}
+			if idle {
+				return
+			}
+		}
+	}
+}
+
+// advance processes the slots up to now and returns the due events in delivery order.
+// idle reports that the wheel is empty and the run goroutine must exit.
+func (w *timerWheel) advance(now time.Time) (due []*delayedEvent, idle bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	target := int64(now.Sub(w.base) / wheelTick)
+	for w.ticked < target {
+		w.ticked++
+		w.cursor = (w.cursor + 1) % wheelSlots
+		for id, entry := range w.slots[w.cursor] {
+			if entry.rounds > 0 {
+				entry.rounds--
+				continue
+			}
+			delete(w.slots[w.cursor], id)
+			delete(w.index, id)
+			due = append(due, entry)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].ev.DeliverAt.Equal(due[j].ev.DeliverAt) {
+			return due[i].ev.DeliverAt.Before(due[j].ev.DeliverAt)
+		}
+		return due[i].seq < due[j].seq
+	})
+
+	if len(w.index) == 0 {
+		w.running = false
+		return due, true
+	}
+	return due, false
+}
+
+// stats returns the pending delayed events summary.
+func (w *timerWheel) stats() types.DelayedStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	stats := types.DelayedStats{Pending: len(w.index), ByPrefix: make(map[string]int)}
+	for _, entry := range w.index {
+		stats.ByPrefix[prefixOf(entry.ev.Type)]++
+		if stats.Next == nil || entry.ev.DeliverAt.Before(*stats.Next) {
+			next := entry.ev.DeliverAt
+			stats.Next = &next
+		}
+	}
+	return stats
+}
+
+// stop discards all pending events. Durable events stay in the store and are rescheduled on Start.
+func (w *timerWheel) stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.running {
+		close(w.stopCh)
+		w.running = false
+	}
+	for i := range w.slots {
+		w.slots[i] = make(map[string]*delayedEvent)
+	}
+	w.index = make(map[string]*delayedEvent)
+}
+
+// deliverDelayed routes a due delayed event like a regular Push.
+func deliverDelayed(ctx context.Context, ev *types.Event) {
+	_, pool, err := getHandler(prefixOf(ev.Type))
+	if err != nil {
+		log.Warn("event delay: type=%s id=%s err=%v (dropped)", ev.Type, ev.ID, err)
+		return
+	}
+
+	svc.lmgr.notify(ev)
+	svc.smgr.notify(ev)
+
+	// Queued events are enqueued in the wheel goroutine to keep their order;
+	// direct dispatch may block on worker slots, so it does not hold up the wheel.
+	discard := make(chan types.Result, 1)
+	if ev.Queue != "" {
+		q, err := svc.queues.get(ev.Queue)
+		if err == nil {
+			err = q.enqueue(ctx, ev, discard)
+		}
+		if err != nil {
+			log.Warn("event delay: type=%s id=%s queue=%s err=%v (dropped)", ev.Type, ev.ID, ev.Queue, err)
+			ack(ev)
+		}
+		return
+	}
+
+	go func() {
+		if _, err := pool.dispatch(ctx, ev, discard); err != nil {
+			log.Warn("event delay: type=%s id=%s err=%v", ev.Type, ev.ID, err)
+		}
+	}()
+}
+
+// Cancel cancels a pending delayed event by ID.
+// Returns false if the event is not pending (unknown, already delivered or cancelled).
+// A cancelled durable event is acknowledged.
+func Cancel(id string) bool {
+	ev := getDelays().cancel(id)
+	if ev == nil {
+		return false
+	}
+	ack(ev)
+	return true
+}
+
+// DelayedStats returns the number of pending delayed events, the earliest delivery time
+// and the pending events per handler prefix.
+func DelayedStats() types.DelayedStats {
+	return getDelays().stats()
+}
+
+// getDelays returns the timer wheel of the current service.
+func getDelays() *timerWheel {
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+	return svc.delays
+}
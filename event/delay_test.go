@@ -0,0 +1,204 @@
+package event_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yaoapp/yao/event"
+	"github.com/yaoapp/yao/event/types"
+)
+
+// --- Delay: delivery after the delay, listeners notified at delivery ---
+
+func TestDelay_Push(t *testing.T) {
+	event.Reset()
+	defer event.Reset()
+
+	h := &recordHandler{}
+	event.Register("delay", h)
+	if err := event.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = event.Stop(context.Background()) }()
+
+	ch := make(chan *types.Event, 4)
+	subID := event.Subscribe("delay.*", ch)
+	defer event.Unsubscribe(subID)
+
+	start := time.Now()
+	if _, err := event.Push(context.Background(), "delay.later", "x", event.Delay(100*time.Millisecond)); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	stats := event.DelayedStats()
+	if stats.Pending != 1 || stats.ByPrefix["delay"] != 1 || stats.Next == nil {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if len(h.getCalls()) != 0 || len(ch) != 0 {
+		t.Fatal("delayed event delivered too early")
+	}
+
+	select {
+	case <-ch:
+		if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+			t.Fatalf("delivered after %v, expected at least 100ms", elapsed)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("delayed event not delivered")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if calls := h.getCalls(); len(calls) != 1 || calls[0] != "delay.later" {
+		t.Fatalf("expected [delay.later], got %v", calls)
+	}
+	if stats := event.DelayedStats(); stats.Pending != 0 {
+		t.Fatalf("expected no pending delayed events, got %d", stats.Pending)
+	}
+}
+
+// --- Delay: due events keep their order in a queue ---
+
+func TestDelay_QueueOrder(t *testing.T) {
+	event.Reset()
+	defer event.Reset()
+
+	h := &orderHandler{}
+	event.Register("delay", h)
+	if err := event.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = event.Stop(context.Background()) }()
+
+	qid, _ := event.QueueCreate("delay")
+	at := time.Now().Add(80 * time.Millisecond)
+
+	// Same due time: push order. Earlier due time first.
+	event.Push(context.Background(), "delay.x", 2, event.Queue(qid), event.At(at))
+	event.Push(context.Background(), "delay.x", 3, event.Queue(qid), event.At(at))
+	event.Push(context.Background(), "delay.x", 1, event.Queue(qid), event.Delay(40*time.Millisecond))
+	event.Push(context.Background(), "delay.x", 0, event.Queue(qid))
+
+	time.Sleep(200 * time.Millisecond)
+	order := h.getOrder()
+	if len(order) != 4 {
+		t.Fatalf("expected 4 events, got %v", order)
+	}
+	for i, v := range order {
+		if v != i {
+			t.Fatalf("expected [0 1 2 3], got %v", order)
+		}
+	}
+}
+
+// --- Delay: a past time delivers immediately ---
+
+func TestDelay_AtPast(t *testing.T) {
+	event.Reset()
+	defer event.Reset()
+
+	h := &recordHandler{}
+	event.Register("delay", h)
+	if err := event.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = event.Stop(context.Background()) }()
+
+	event.Push(context.Background(), "delay.now", "x", event.At(time.Now().Add(-time.Minute)))
+	if stats := event.DelayedStats(); stats.Pending != 0 {
+		t.Fatalf("expected immediate delivery, got %d pending", stats.Pending)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if len(h.getCalls()) != 1 {
+		t.Fatalf("expected 1 call, got %v", h.getCalls())
+	}
+}
+
+// --- Delay: cancel a pending delayed event ---
+
+func TestDelay_Cancel(t *testing.T) {
+	event.Reset()
+	defer event.Reset()
+
+	h := &recordHandler{}
+	event.Register("delay", h)
+	if err := event.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = event.Stop(context.Background()) }()
+
+	id, _ := event.Push(context.Background(), "delay.cancel", "x", event.Delay(80*time.Millisecond))
+	event.Push(context.Background(), "delay.keep", "y", event.Delay(80*time.Millisecond))
+
+	if !event.Cancel(id) {
+		t.Fatal("expected Cancel to return true for a pending event")
+	}
+	if event.Cancel(id) {
+		t.Fatal("expected Cancel to return false for a cancelled event")
+	}
+	if event.Cancel("ev-unknown") {
+		t.Fatal("expected Cancel to return false for an unknown event")
+	}
+	if stats := event.DelayedStats(); stats.Pending != 1 {
+		t.Fatalf("expected 1 pending event, got %d", stats.Pending)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	if calls := h.getCalls(); len(calls) != 1 || calls[0] != "delay.keep" {
+		t.Fatalf("expected [delay.keep], got %v", calls)
+	}
+}
+
+// --- Delay: Call cannot be delayed ---
+
+func TestDelay_Call(t *testing.T) {
+	event.Reset()
+	defer event.Reset()
+
+	event.Register("delay", &recordHandler{})
+	if err := event.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = event.Stop(context.Background()) }()
+
+	_, _, err := event.Call(context.Background(), "delay.call", "x", event.Delay(time.Second))
+	if err != event.ErrDelayedCall {
+		t.Fatalf("expected ErrDelayedCall, got %v", err)
+	}
+}
+
+// --- Delay: Stop discards pending events, durable ones are rescheduled ---
+
+func TestDelay_DurableReschedule(t *testing.T) {
+	defer event.Reset()
+	path := filepath.Join(t.TempDir(), "events.log")
+
+	h1 := &durableHandler{}
+	startDurable(t, path, h1)
+	event.Push(context.Background(), "dur.add", durablePayload{Name: "later", Value: 1}, event.Delay(150*time.Millisecond))
+	cancelID, _ := event.Push(context.Background(), "dur.add", durablePayload{Name: "cancelled", Value: 2}, event.Delay(150*time.Millisecond))
+	event.Cancel(cancelID)
+	_ = event.Stop(context.Background())
+	if stats := event.DelayedStats(); stats.Pending != 0 {
+		t.Fatalf("expected Stop to discard pending events, got %d", stats.Pending)
+	}
+
+	h2 := &durableHandler{ack: map[string]bool{"later": true}}
+	startDurable(t, path, h2)
+	defer func() { _ = event.Stop(context.Background()) }()
+
+	// Still waiting for its delivery time
+	if stats := event.DelayedStats(); stats.Pending != 1 {
+		t.Fatalf("expected the durable delayed event to be rescheduled, got %d pending", stats.Pending)
+	}
+	time.Sleep(300 * time.Millisecond)
+	got, _ := h2.get()
+	if len(got) != 1 || got[0].Name != "later" {
+		t.Fatalf("expected only the non-cancelled event, got %v", got)
+	}
+	if first, _ := h1.get(); len(first) != 0 {
+		t.Fatalf("expected no delivery before Stop, got %v", first)
+	}
+}
@@ -0,0 +1,224 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/yao/event/types"
+)
+
+// SetStore sets the store used by durable handlers. Must be called before Start.
+// The store is closed by Stop; set it again before the next Start.
+func SetStore(store types.Store) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	svc.store = store
+}
+
+// Ack acknowledges a durable event so that it is not replayed on the next Start.
+// It is a no-op for events that were not persisted.
+func Ack(ev *types.Event) error {
+	if ev == nil || !ev.Durable {
+		return nil
+	}
+	store := getStore()
+	if store == nil {
+		return nil
+	}
+	return store.Ack(ev.ID)
+}
+
+// ack acknowledges an event and logs the failure.
+func ack(ev *types.Event) {
+	if err := Ack(ev); err != nil {
+		log.Error("event ack: type=%s id=%s err=%v", ev.Type, ev.ID, err)
+	}
+}
+
+// getStore returns the current store, nil if durable mode is off.
+func getStore() types.Store {
+	svc.mu.RLock()
+	defer svc.mu.RUnlock()
+	return svc.store
+}
+
+// persist writes a pushed event to the store before it is dispatched.
+func persist(store types.Store, ev *types.Event) error {
+	rec := &types.Record{
+		ID:    ev.ID,
+		Type:  ev.Type,
+		Queue: ev.Queue,
+		SID:   ev.SID,
+		Auth:  ev.Auth,
+		Time:  time.Now(),
+	}
+	if !ev.DeliverAt.IsZero() {
+		deliverAt := ev.DeliverAt
+		rec.DeliverAt = &deliverAt
+	}
+	if ev.Payload != nil {
+		payload, err := json.Marshal(ev.Payload)
+		if err != nil {
+			return fmt.Errorf("event push: persist payload: %w", err)
+		}
+		rec.Payload = payload
+	}
+	if err := store.Append(rec); err != nil {
+		return fmt.Errorf("event push: persist: %w", err)
+	}
+	ev.Durable = true
+	return nil
+}
+
+// persistQueue records a queue of a durable handler so that it is recreated on Start.
+func persistQueue(entry *types.HandlerEntry, queueID string) {
+	store := getStore()
+	if store == nil || !entry.Durable {
+		return
+	}
+	if err := store.OpenQueue(queueID, entry.Prefix); err != nil {
+		log.Warn("event queue: persist queue=%s err=%v (queue is not durable)", queueID, err)
+	}
+}
+
+// releaseQueue records a queue release.
+func releaseQueue(queueID string) {
+	store := getStore()
+	if store == nil {
+		return
+	}
+	if err := store.ReleaseQueue(queueID); err != nil {
+		log.Warn("event queue: persist release queue=%s err=%v", queueID, err)
+	}
+}
+
+// replay redelivers the unacknowledged events of durable handlers.
+// Queues are recreated first so that queued events keep their order;
+// queues that were released before the restart are released again once refilled.
+func replay(store types.Store) error {
+	records, queues, err := store.Pending()
+	if err != nil {
+		return fmt.Errorf("event replay: %w", err)
+	}
+
+	released := []string{}
+	for _, q := range queues {
+		seedEventID(q.ID)
+		entry, pool, err := getHandler(q.Prefix)
+		if err != nil || !entry.Durable {
+			log.Warn("event replay: skip queue=%s prefix=%s (no durable handler)", q.ID, q.Prefix)
+			continue
+		}
+		if err := svc.queues.create(q.Prefix, q.ID, entry.QueueSize, pool); err != nil && err != ErrQueueExists {
+			log.Warn("event replay: recreate queue=%s err=%v", q.ID, err)
+			continue
+		}
+		if q.Released {
+			released = append(released, q.ID)
+		}
+	}
+
+	replayed := 0
+	for _, rec := range records {
+		seedEventID(rec.ID)
+		entry, pool, err := getHandler(prefixOf(rec.Type))
+		if err != nil || !entry.Durable {
+			log.Warn("event replay: skip type=%s id=%s (no durable handler)", rec.Type, rec.ID)
+			continue
+		}
+
+		ev := &types.Event{
+			Type:     rec.Type,
+			ID:       rec.ID,
+			Queue:    rec.Queue,
+			SID:      rec.SID,
+			Auth:     rec.Auth,
+			Durable:  true,
+			Replayed: true,
+		}
+		if len(rec.Payload) > 0 {
+			ev.Payload = decodePayload(entry, rec)
+		}
+
+		// Not due yet: back to the timer wheel
+		if rec.DeliverAt != nil && rec.DeliverAt.After(time.Now()) {
+			ev.DeliverAt = *rec.DeliverAt
+			getDelays().add(context.Background(), ev)
+			replayed++
+			continue
+		}
+
+		discard := make(chan types.Result, 1)
+		if ev.Queue != "" {
+			q, err := svc.queues.get(ev.Queue)
+			if err == nil {
+				err = q.enqueue(context.Background(), ev, discard)
+			}
+			if err != nil {
+				log.Warn("event replay: type=%s id=%s queue=%s err=%v", ev.Type, ev.ID, ev.Queue, err)
+				continue
+			}
+		} else if _, err := pool.dispatch(context.Background(), ev, discard); err != nil {
+			log.Warn("event replay: type=%s id=%s err=%v", ev.Type, ev.ID, err)
+			continue
+		}
+		replayed++
+	}
+
+	for _, id := range released {
+		svc.queues.release(id)
+	}
+
+	if replayed > 0 {
+		log.Info("event replay: %d unacknowledged events redelivered", replayed)
+	}
+	return nil
+}
+
+// decodePayload decodes a replayed payload into the payload type registered for its event type.
+// Unregistered types, and payloads that do not decode, are returned as json.RawMessage.
+func decodePayload(entry *types.HandlerEntry, rec *types.Record) any {
+	typ, has := entry.Payloads[rec.Type]
+	if !has || typ == nil {
+		return rec.Payload
+	}
+
+	ptr := typ.Kind() == reflect.Ptr
+	if ptr {
+		typ = typ.Elem()
+	}
+	value := reflect.New(typ)
+	if err := json.Unmarshal(rec.Payload, value.Interface()); err != nil {
+		log.Warn("event replay: decode payload type=%s id=%s err=%v (replayed as JSON)", rec.Type, rec.ID, err)
+		return rec.Payload
+	}
+	if ptr {
+		return value.Interface()
+	}
+	return value.Elem().Interface()
+}
+
+// seedEventID moves the ID counter past a replayed ID ("ev-12", "q-trace-13")
+// so that new events and queues do not reuse it.
+func seedEventID(id string) {
+	i := strings.LastIndexByte(id, '-')
+	if i < 0 {
+		return
+	}
+	n, err := strconv.ParseUint(id[i+1:], 10, 64)
+	if err != nil {
+		return
+	}
+	for {
+		current := eventIDCounter.Load()
+		if current >= n || eventIDCounter.CompareAndSwap(current, n) {
+			return
+		}
+	}
+}
@@ -0,0 +1,353 @@
+package event_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yaoapp/yao/event"
+	"github.com/yaoapp/yao/event/types"
+)
+
+// --- Test handler ---
+
+type durablePayload struct {
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+}
+
+// durableHandler records payloads and acknowledges the events whose name is in ack.
+type durableHandler struct {
+	mu       sync.Mutex
+	ack      map[string]bool
+	block    chan struct{} // when set, Handle waits on it
+	received []durablePayload
+	replayed []bool
+	payloads []any // ev.Payload as delivered
+}
+
+func (h *durableHandler) Handle(ctx context.Context, ev *types.Event, resp chan<- types.Result) {
+	if h.block != nil {
+		<-h.block
+	}
+
+	var p durablePayload
+	if err := ev.Should(&p); err != nil {
+		return
+	}
+	h.mu.Lock()
+	h.received = append(h.received, p)
+	h.replayed = append(h.replayed, ev.Replayed)
+	h.payloads = append(h.payloads, ev.Payload)
+	h.mu.Unlock()
+
+	if h.ack[p.Name] {
+		_ = event.Ack(ev)
+	}
+}
+
+func (h *durableHandler) Shutdown(ctx context.Context) error { return nil }
+
+func (h *durableHandler) get() ([]durablePayload, []bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]durablePayload{}, h.received...), append([]bool{}, h.replayed...)
+}
+
+// startDurable resets the service and starts it with a log store at path.
+func startDurable(t *testing.T, path string, h types.Handler, opts ...types.HandlerOption) {
+	t.Helper()
+	event.Reset()
+	store, err := event.NewLogStore(path)
+	if err != nil {
+		t.Fatalf("NewLogStore failed: %v", err)
+	}
+	event.SetStore(store)
+	event.Register("dur", h, append([]types.HandlerOption{event.Durable()}, opts...)...)
+	if err := event.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+}
+
+// --- Durable: unacknowledged events are replayed on Start ---
+
+func TestDurable_ReplayUnacked(t *testing.T) {
+	defer event.Reset()
+	path := filepath.Join(t.TempDir(), "events.log")
+
+	h1 := &durableHandler{ack: map[string]bool{"acked": true}}
+	startDurable(t, path, h1)
+	event.Push(context.Background(), "dur.add", durablePayload{Name: "acked", Value: 1})
+	event.Push(context.Background(), "dur.add", &durablePayload{Name: "lost", Value: 2})
+	time.Sleep(50 * time.Millisecond)
+	if got, _ := h1.get(); len(got) != 2 {
+		t.Fatalf("expected 2 deliveries, got %v", got)
+	}
+	_ = event.Stop(context.Background())
+
+	// Restart: only the unacknowledged event comes back, decoded by Should
+	h2 := &durableHandler{ack: map[string]bool{"lost": true}}
+	startDurable(t, path, h2)
+	time.Sleep(50 * time.Millisecond)
+	got, replayed := h2.get()
+	if len(got) != 1 || got[0].Name != "lost" || got[0].Value != 2 || !replayed[0] {
+		t.Fatalf("expected the unacknowledged event to be replayed, got %v replayed=%v", got, replayed)
+	}
+
+	if _, err := event.Push(context.Background(), "dur.add", durablePayload{Name: "new"}); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	_ = event.Stop(context.Background())
+
+	// Acknowledged during replay: nothing left
+	h3 := &durableHandler{}
+	startDurable(t, path, h3)
+	defer func() { _ = event.Stop(context.Background()) }()
+	time.Sleep(50 * time.Millisecond)
+	if got, _ := h3.get(); len(got) != 1 || got[0].Name != "new" {
+		t.Fatalf("expected only the unacknowledged new event, got %v", got)
+	}
+}
+
+// --- Durable: replayed payloads are decoded into the registered type ---
+
+func TestDurable_ReplayPayloadType(t *testing.T) {
+	defer event.Reset()
+	path := filepath.Join(t.TempDir(), "events.log")
+
+	store, err := event.NewLogStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Append(&types.Record{ID: "ev-1", Type: "dur.value", Payload: []byte(`{"name":"value","value":1}`)})
+	store.Append(&types.Record{ID: "ev-2", Type: "dur.ptr", Payload: []byte(`{"name":"ptr","value":2}`)})
+	store.Append(&types.Record{ID: "ev-3", Type: "dur.raw", Payload: []byte(`{"name":"raw","value":3}`)})
+	store.Close()
+
+	h := &durableHandler{}
+	startDurable(t, path, h,
+		event.Payload("dur.value", durablePayload{}),
+		event.Payload("dur.ptr", &durablePayload{}),
+	)
+	defer func() { _ = event.Stop(context.Background()) }()
+	time.Sleep(50 * time.Millisecond)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.payloads) != 3 {
+		t.Fatalf("expected 3 replayed events, got %v", h.received)
+	}
+	for i, p := range h.received {
+		switch p.Name {
+		case "value":
+			if v, ok := h.payloads[i].(durablePayload); !ok || v.Value != 1 {
+				t.Errorf("expected durablePayload, got %T", h.payloads[i])
+			}
+		case "ptr":
+			if v, ok := h.payloads[i].(*durablePayload); !ok || v.Value != 2 {
+				t.Errorf("expected *durablePayload, got %T", h.payloads[i])
+			}
+		case "raw":
+			if _, ok := h.payloads[i].(json.RawMessage); !ok {
+				t.Errorf("expected json.RawMessage for an unregistered type, got %T", h.payloads[i])
+			}
+		default:
+			t.Errorf("unexpected payload %v", p)
+		}
+	}
+}
+
+// --- Durable: new events do not reuse replayed IDs ---
+
+func TestDurable_SeedEventID(t *testing.T) {
+	defer event.Reset()
+	path := filepath.Join(t.TempDir(), "events.log")
+
+	store, err := event.NewLogStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Append(&types.Record{ID: "ev-900000000", Type: "dur.add"})
+	store.Close()
+
+	startDurable(t, path, &durableHandler{})
+	defer func() { _ = event.Stop(context.Background()) }()
+
+	id, err := event.Push(context.Background(), "dur.add", durablePayload{Name: "new"})
+	if err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	var n uint64
+	if _, err := fmt.Sscanf(id, "ev-%d", &n); err != nil || n <= 900000000 {
+		t.Fatalf("expected the ID counter to move past the replayed ID, got %s", id)
+	}
+}
+
+// --- Durable: AutoAck acknowledges on return, not on panic ---
+
+type panicOnceHandler struct {
+	mu    sync.Mutex
+	calls int
+	panic bool
+}
+
+func (h *panicOnceHandler) Handle(ctx context.Context, ev *types.Event, resp chan<- types.Result) {
+	h.mu.Lock()
+	h.calls++
+	h.mu.Unlock()
+	if h.panic {
+		panic("boom")
+	}
+}
+
+func (h *panicOnceHandler) Shutdown(ctx context.Context) error { return nil }
+
+func TestDurable_AutoAck(t *testing.T) {
+	defer event.Reset()
+	path := filepath.Join(t.TempDir(), "events.log")
+
+	startDurable(t, path, &panicOnceHandler{}, event.AutoAck())
+	event.Push(context.Background(), "dur.ok", "a")
+	time.Sleep(50 * time.Millisecond)
+	_ = event.Stop(context.Background())
+
+	startDurable(t, path, &panicOnceHandler{panic: true}, event.AutoAck())
+	event.Push(context.Background(), "dur.panic", "b")
+	time.Sleep(50 * time.Millisecond)
+	_ = event.Stop(context.Background())
+
+	h := &panicOnceHandler{}
+	startDurable(t, path, h, event.AutoAck())
+	defer func() { _ = event.Stop(context.Background()) }()
+	time.Sleep(50 * time.Millisecond)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.calls != 1 {
+		t.Fatalf("expected only the panicked event to be replayed, got %d calls", h.calls)
+	}
+}
+
+// --- Durable: queues survive a restart and keep their order ---
+
+func TestDurable_QueueReplay(t *testing.T) {
+	defer event.Reset()
+	path := filepath.Join(t.TempDir(), "events.log")
+
+	// The handler blocks, so Stop discards the queued events
+	h1 := &durableHandler{block: make(chan struct{})}
+	startDurable(t, path, h1)
+	queueID, err := event.QueueCreate("dur")
+	if err != nil {
+		t.Fatalf("QueueCreate failed: %v", err)
+	}
+	for i := 1; i <= 3; i++ {
+		if _, err := event.Push(context.Background(), "dur.add", durablePayload{Name: "q", Value: i}, event.Queue(queueID)); err != nil {
+			t.Fatalf("Push failed: %v", err)
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(h1.block)
+	}()
+	_ = event.Stop(context.Background())
+
+	h2 := &durableHandler{ack: map[string]bool{"q": true}}
+	startDurable(t, path, h2)
+	defer func() { _ = event.Stop(context.Background()) }()
+
+	// The queue is recreated
+	if _, err := event.Push(context.Background(), "dur.add", durablePayload{Name: "q", Value: 4}, event.Queue(queueID)); err != nil {
+		t.Fatalf("expected the queue to be recreated, got %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	got, _ := h2.get()
+	first, _ := h1.get()
+	if len(first)+len(got) < 4 {
+		t.Fatalf("expected every event to be delivered at least once, got %v and %v", first, got)
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i].Value <= got[i-1].Value {
+			t.Fatalf("expected FIFO order after replay, got %v", got)
+		}
+	}
+	if got[len(got)-1].Value != 4 {
+		t.Fatalf("expected the new event after the replayed ones, got %v", got)
+	}
+}
+
+// --- Durable: QueueAbort acknowledges the discarded events ---
+
+func TestDurable_QueueAbortAcks(t *testing.T) {
+	defer event.Reset()
+	path := filepath.Join(t.TempDir(), "events.log")
+
+	h1 := &durableHandler{block: make(chan struct{})}
+	startDurable(t, path, h1)
+	queueID, _ := event.QueueCreate("dur")
+	for i := 1; i <= 3; i++ {
+		event.Push(context.Background(), "dur.add", durablePayload{Name: "q", Value: i}, event.Queue(queueID))
+	}
+	time.Sleep(20 * time.Millisecond)
+	event.QueueAbort(queueID)
+	close(h1.block)
+	time.Sleep(50 * time.Millisecond)
+	_ = event.Stop(context.Background())
+
+	// Only the in-flight event (not acknowledged by the handler) is replayed
+	h2 := &durableHandler{}
+	startDurable(t, path, h2)
+	defer func() { _ = event.Stop(context.Background()) }()
+	time.Sleep(50 * time.Millisecond)
+	if got, _ := h2.get(); len(got) != 1 || got[0].Value != 1 {
+		t.Fatalf("expected only the in-flight event to be replayed, got %v", got)
+	}
+}
+
+// --- LogStore: compaction keeps pending events in order ---
+
+func TestLogStore_Compact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+	store, err := event.NewLogStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"ev-1", "ev-2", "ev-3"} {
+		if err := store.Append(&types.Record{ID: id, Type: "dur.add", Queue: "q-1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store.OpenQueue("q-1", "dur")
+	store.OpenQueue("q-2", "dur")
+	store.ReleaseQueue("q-1")
+	store.ReleaseQueue("q-2")
+	store.Ack("ev-2")
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = event.NewLogStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	records, queues, err := store.Pending()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].ID != "ev-1" || records[1].ID != "ev-3" {
+		t.Fatalf("unexpected pending records: %+v", records)
+	}
+
+	// q-1 is released but still has pending events, q-2 is gone
+	if len(queues) != 1 || queues[0].ID != "q-1" || !queues[0].Released {
+		t.Fatalf("unexpected queues: %+v", queues)
+	}
+}
@@ -1,6 +1,11 @@
 package event
 
-import "github.com/yaoapp/yao/event/types"
+import (
+	"reflect"
+	"time"
+
+	"github.com/yaoapp/yao/event/types"
+)
 
 // MaxWorkers sets the max concurrent worker goroutines for a Handler.
 // Default is 512. Workers are fire-and-forget (goroutine ends after task).
@@ -26,6 +31,37 @@ func QueueSize(n int) types.HandlerOption {
 	}
 }
 
+// Durable persists the events pushed to a Handler before dispatch (at-least-once delivery).
+// Unacknowledged events and the handler's open queues are replayed on Start.
+// The handler acknowledges an event with event.Ack, or use AutoAck. Requires SetStore.
+func Durable() types.HandlerOption {
+	return func(e *types.HandlerEntry) {
+		e.Durable = true
+	}
+}
+
+// AutoAck acknowledges durable events when Handle returns without panic. Implies Durable.
+func AutoAck() types.HandlerOption {
+	return func(e *types.HandlerEntry) {
+		e.Durable = true
+		e.AutoAck = true
+	}
+}
+
+// Payload registers the payload type pushed for an event type of a durable Handler.
+// Replayed payloads of that event type are decoded into it, so the handler sees the type that was pushed;
+// payloads of unregistered event types are replayed as json.RawMessage.
+//
+//	event.Register("billing", h, event.AutoAck(), event.Payload("billing.charge", ChargePayload{}))
+func Payload(typ string, v any) types.HandlerOption {
+	return func(e *types.HandlerEntry) {
+		if e.Payloads == nil {
+			e.Payloads = map[string]reflect.Type{}
+		}
+		e.Payloads[typ] = reflect.TypeOf(v)
+	}
+}
+
 // Queue sets the queue key for a Push/Call invocation.
 // Events with the same queue key are processed serially (FIFO).
 func Queue(key string) types.PushOption {
@@ -34,6 +70,22 @@ func Queue(key string) types.PushOption {
 	}
 }
 
+// Delay delivers a Push after d (timer wheel, 10ms resolution).
+// The event ID returned by Push can be passed to Cancel while the event is pending.
+// Listeners and subscribers are notified at delivery time. Not allowed for Call.
+func Delay(d time.Duration) types.PushOption {
+	return func(ev *types.Event) {
+		ev.DeliverAt = time.Now().Add(d)
+	}
+}
+
+// At delivers a Push at t. A time in the past delivers immediately. Not allowed for Call.
+func At(t time.Time) types.PushOption {
+	return func(ev *types.Event) {
+		ev.DeliverAt = t
+	}
+}
+
 // Filter sets a custom filter function for Listen or Subscribe.
 // Events that do not pass the filter are skipped.
 func Filter(fn func(*types.Event) bool) types.FilterOption {
@@ -22,6 +22,7 @@ type eventQueue struct {
 	ch       chan queueItem
 	released bool
 	aborted  bool
+	dropped  bool // aborted by QueueAbort: discarded durable events are acknowledged
 	mu       sync.Mutex
 	done     chan struct{} // closed when consumer goroutine exits
 }
@@ -59,7 +60,8 @@ func (q *eventQueue) release() {
 
 // abort forcefully stops the queue: rejects new events, discards pending.
 // The consumer goroutine detects the aborted flag and skips remaining items.
-func (q *eventQueue) abort() {
+// When drop is false (Stop), discarded durable events stay unacknowledged and are replayed.
+func (q *eventQueue) abort(drop bool) {
 	q.mu.Lock()
 	if q.aborted {
 		q.mu.Unlock()
@@ -67,6 +69,7 @@ func (q *eventQueue) abort() {
 	}
 	wasReleased := q.released
 	q.aborted = true
+	q.dropped = drop
 	q.released = true
 	if !wasReleased {
 		close(q.ch)
@@ -79,9 +82,12 @@ func (q *eventQueue) consumer(pool *workerPool) {
 	defer close(q.done)
 	for item := range q.ch {
 		q.mu.Lock()
-		aborted := q.aborted
+		aborted, dropped := q.aborted, q.dropped
 		q.mu.Unlock()
 		if aborted {
+			if dropped {
+				ack(item.ev)
+			}
 			continue
 		}
 
@@ -184,7 +190,7 @@ func (qm *queueManager) abortOne(queueID string) {
 	qm.released[queueID] = struct{}{}
 	qm.mu.Unlock()
 
-	q.abort()
+	q.abort(true)
 	go func() { <-q.done }()
 }
 
@@ -200,7 +206,7 @@ func (qm *queueManager) abortAll() {
 	qm.mu.Unlock()
 
 	for _, q := range queues {
-		q.abort()
+		q.abort(false)
 	}
 	for _, q := range queues {
 		<-q.done
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"sync"
 
+	"github.com/yaoapp/kun/log"
 	"github.com/yaoapp/yao/event/types"
 )
 
@@ -18,6 +19,7 @@ var (
 	ErrQueueReleased = errors.New("event: queue already released")
 	ErrNoHandler     = errors.New("event: no handler registered for prefix")
 	ErrHandlerPanic  = errors.New("event: handler panicked")
+	ErrDelayedCall   = errors.New("event: call cannot be delayed")
 )
 
 // Context keys for SID and Auth propagation.
@@ -63,6 +65,8 @@ type service struct {
 	queues   *queueManager                  // queue lifecycle
 	lmgr     *listenerManager               // listener manager
 	smgr     *subManager                    // subscriber manager
+	store    types.Store                    // durable event store; nil disables durable mode
+	delays   *timerWheel                    // delayed events
 }
 
 var svc = &service{}
@@ -92,24 +96,36 @@ func Register(prefix string, handler types.Handler, opts ...types.HandlerOption)
 
 // Start initializes and starts the event service.
 // Called during engine startup, after runtime is ready.
+//
+// When a store is set, the unacknowledged events of durable handlers are
+// replayed after the service is started (outside the lock, so that handlers
+// can Push while the replay is running).
 func Start() error {
 	svc.mu.Lock()
-	defer svc.mu.Unlock()
-
 	if svc.started {
+		svc.mu.Unlock()
 		return ErrAlreadyStart
 	}
 
 	// Create worker pools for each registered handler
 	for prefix, entry := range svc.handlers {
 		svc.pools[prefix] = newWorkerPool(entry)
+		if entry.Durable && svc.store == nil {
+			log.Warn("event: handler %s is durable but no store is set, events are not persisted", prefix)
+		}
 	}
 
 	// Start listener manager
 	svc.lmgr.start()
 
 	svc.started = true
-	return nil
+	store := svc.store
+	svc.mu.Unlock()
+
+	if store == nil {
+		return nil
+	}
+	return replay(store)
 }
 
 // Stop gracefully shuts down the event service.
@@ -139,8 +155,13 @@ func Stop(ctx context.Context) error {
 	}
 	lmgr := svc.lmgr
 	smgr := svc.smgr
+	store := svc.store
+	delays := svc.delays
 	svc.mu.Unlock()
 
+	// Discard pending delayed events (durable ones are rescheduled on the next Start)
+	delays.stop()
+
 	// From here on, started=false prevents any new Push/Call/QueueCreate.
 	// Existing in-flight workers may still call getHandler and get ErrNotStarted,
 	// which is the correct behavior during shutdown.
@@ -166,6 +187,18 @@ func Stop(ctx context.Context) error {
 	// Clear subscribers
 	smgr.clear()
 
+	// Close the durable store; events discarded above stay unacknowledged and are replayed on the next Start
+	if store != nil {
+		svc.mu.Lock()
+		if svc.store == store {
+			svc.store = nil
+		}
+		svc.mu.Unlock()
+		if err := store.Close(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -218,4 +251,9 @@ func (s *service) reset() {
 	s.queues = newQueueManager()
 	s.lmgr = newListenerManager()
 	s.smgr = newSubManager()
+	s.store = nil
+	if s.delays != nil {
+		s.delays.stop()
+	}
+	s.delays = newTimerWheel(deliverDelayed)
 }
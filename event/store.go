@@ -0,0 +1,283 @@
+package event
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/yao/event/types"
+)
+
+// Log entry operations.
+const (
+	opPush    = "push"
+	opAck     = "ack"
+	opQueue   = "queue"
+	opRelease = "release"
+)
+
+// logEntry is one line of the append-only log.
+type logEntry struct {
+	Op     string        `json:"op"`
+	ID     string        `json:"id"`
+	Prefix string        `json:"prefix,omitempty"`
+	Record *types.Record `json:"record,omitempty"`
+}
+
+// logStore is a Store backed by an append-only JSON lines file.
+// Every Append/Ack is written before returning; the file is compacted on open and close
+// so that it only holds unacknowledged events and live queues.
+type logStore struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	seq     uint64
+	pending map[string]*pendingRecord     // event ID -> record
+	queues  map[string]*types.QueueRecord // queue ID -> record
+}
+
+type pendingRecord struct {
+	seq    uint64
+	record *types.Record
+}
+
+// NewLogStore opens (or creates) the append-only event log at path.
+func NewLogStore(path string) (types.Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("event log: %w", err)
+	}
+
+	s := &logStore{
+		path:    path,
+		pending: make(map[string]*pendingRecord),
+		queues:  make(map[string]*types.QueueRecord),
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("event log: %w", err)
+	}
+	s.file = file
+	return s, nil
+}
+
+// load replays the log file into memory. A truncated last line (crash while writing) is ignored.
+func (s *logStore) load() error {
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("event log: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		var entry logEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			log.Warn("event log: skip invalid entry %s:%d: %v", s.path, line, err)
+			continue
+		}
+		s.apply(&entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("event log: %w", err)
+	}
+	return nil
+}
+
+// apply updates the in-memory state with one log entry.
+func (s *logStore) apply(entry *logEntry) {
+	switch entry.Op {
+	case opPush:
+		if entry.Record != nil {
+			s.seq++
+			s.pending[entry.Record.ID] = &pendingRecord{seq: s.seq, record: entry.Record}
+		}
+	case opAck:
+		delete(s.pending, entry.ID)
+	case opQueue:
+		s.queues[entry.ID] = &types.QueueRecord{ID: entry.ID, Prefix: entry.Prefix}
+	case opRelease:
+		q, ok := s.queues[entry.ID]
+		if !ok {
+			return
+		}
+		q.Released = true
+		for _, p := range s.pending {
+			if p.record.Queue == entry.ID {
+				return
+			}
+		}
+		delete(s.queues, entry.ID)
+	}
+}
+
+// write appends one entry to the log and applies it.
+func (s *logStore) write(entry *logEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return fmt.Errorf("event log: closed")
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("event log: %w", err)
+	}
+	if _, err := s.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("event log: %w", err)
+	}
+	s.apply(entry)
+	return nil
+}
+
+// Append writes a pushed event.
+func (s *logStore) Append(rec *types.Record) error {
+	return s.write(&logEntry{Op: opPush, ID: rec.ID, Record: rec})
+}
+
+// Ack removes an event from the pending set. Unknown IDs are ignored.
+func (s *logStore) Ack(id string) error {
+	s.mu.Lock()
+	_, ok := s.pending[id]
+	s.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return s.write(&logEntry{Op: opAck, ID: id})
+}
+
+// OpenQueue records a queue so that it is recreated on Start.
+func (s *logStore) OpenQueue(id, prefix string) error {
+	return s.write(&logEntry{Op: opQueue, ID: id, Prefix: prefix})
+}
+
+// ReleaseQueue marks a queue as released.
+func (s *logStore) ReleaseQueue(id string) error {
+	s.mu.Lock()
+	_, ok := s.queues[id]
+	s.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return s.write(&logEntry{Op: opRelease, ID: id})
+}
+
+// Pending returns the unacknowledged events in append order and the live queues.
+func (s *logStore) Pending() ([]*types.Record, []*types.QueueRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.records(), s.liveQueues(), nil
+}
+
+// Close compacts and closes the log.
+func (s *logStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	if err != nil {
+		return fmt.Errorf("event log: %w", err)
+	}
+	return s.compact()
+}
+
+// records returns the pending records sorted by append order. Caller holds s.mu.
+func (s *logStore) records() []*types.Record {
+	ordered := make([]*pendingRecord, 0, len(s.pending))
+	for _, p := range s.pending {
+		ordered = append(ordered, p)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].seq < ordered[j].seq })
+
+	records := make([]*types.Record, 0, len(ordered))
+	for _, p := range ordered {
+		records = append(records, p.record)
+	}
+	return records
+}
+
+// liveQueues returns the open queues and the released queues that still have pending events. Caller holds s.mu.
+func (s *logStore) liveQueues() []*types.QueueRecord {
+	used := make(map[string]bool)
+	for _, p := range s.pending {
+		if p.record.Queue != "" {
+			used[p.record.Queue] = true
+		}
+	}
+
+	queues := make([]*types.QueueRecord, 0, len(s.queues))
+	for id, q := range s.queues {
+		if q.Released && !used[id] {
+			continue
+		}
+		copied := *q
+		queues = append(queues, &copied)
+	}
+	return queues
+}
+
+// compact rewrites the log with the current state only. Caller holds s.mu or owns s.
+func (s *logStore) compact() error {
+	tmp := s.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("event log: %w", err)
+	}
+
+	w := bufio.NewWriter(file)
+	enc := json.NewEncoder(w)
+	entries := []*logEntry{}
+	for _, rec := range s.records() {
+		entries = append(entries, &logEntry{Op: opPush, ID: rec.ID, Record: rec})
+	}
+	// Queues after events: a release only keeps the queue if it still has pending events
+	for _, q := range s.liveQueues() {
+		entries = append(entries, &logEntry{Op: opQueue, ID: q.ID, Prefix: q.Prefix})
+		if q.Released {
+			entries = append(entries, &logEntry{Op: opRelease, ID: q.ID})
+		}
+	}
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			file.Close()
+			return fmt.Errorf("event log: %w", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("event log: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("event log: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("event log: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("event log: %w", err)
+	}
+	return nil
+}
@@ -21,3 +21,16 @@ type Listener interface {
 	OnEvent(ev *Event)
 	Shutdown(ctx context.Context) error
 }
+
+// Store persists durable events and queues (see event.Durable).
+//
+// Append must not return before the record is written; Pending returns the
+// unacknowledged records in append order.
+type Store interface {
+	Append(rec *Record) error
+	Ack(id string) error
+	OpenQueue(id, prefix string) error
+	ReleaseQueue(id string) error
+	Pending() ([]*Record, []*QueueRecord, error)
+	Close() error
+}
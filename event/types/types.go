@@ -1,8 +1,10 @@
 package types
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/yaoapp/gou/process"
 )
@@ -19,6 +21,10 @@ type Event struct {
 	Payload any             // Business data; concrete type is determined by event type
 	SID     string          // Session ID, extracted from caller context
 	Auth    *AuthorizedInfo // Authorized info, extracted from caller context; may be nil
+
+	DeliverAt time.Time // Delayed delivery time (event.Delay / event.At); zero delivers immediately
+	Durable   bool      // Persisted before dispatch; removed from the log by event.Ack
+	Replayed  bool      // Redelivered from the log on Start; the handler may have seen it before
 }
 
 // Should asserts the Payload to the target pointer type.
@@ -42,6 +48,16 @@ func (ev *Event) Should(target any) error {
 		return fmt.Errorf("event.Should: payload is nil")
 	}
 
+	// Replayed events carry the payload as JSON
+	if raw, ok := ev.Payload.(json.RawMessage); ok {
+		if _, isRaw := target.(*json.RawMessage); !isRaw {
+			if err := json.Unmarshal(raw, target); err != nil {
+				return fmt.Errorf("event.Should: %w", err)
+			}
+			return nil
+		}
+	}
+
 	// Direct assignment: payload is already the expected pointer type
 	payloadVal := reflect.ValueOf(ev.Payload)
 	targetElem := rv.Elem()
@@ -75,9 +91,39 @@ type HandlerOption func(*HandlerEntry)
 type HandlerEntry struct {
 	Prefix          string
 	Handler         Handler
-	MaxWorkers      int // Max concurrent workers, default 512
-	ReservedWorkers int // Workers reserved for Call, default 10
-	QueueSize       int // Per-queue capacity, default 8192
+	MaxWorkers      int                     // Max concurrent workers, default 512
+	ReservedWorkers int                     // Workers reserved for Call, default 10
+	QueueSize       int                     // Per-queue capacity, default 8192
+	Durable         bool                    // Persist pushed events and replay unacknowledged ones on Start
+	AutoAck         bool                    // Acknowledge durable events when Handle returns without panic
+	Payloads        map[string]reflect.Type // Pushed payload type per event type, replayed payloads are decoded into it
+}
+
+// Record is a durable event as written to the Store.
+type Record struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Queue     string          `json:"queue,omitempty"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	SID       string          `json:"sid,omitempty"`
+	Auth      *AuthorizedInfo `json:"auth,omitempty"`
+	DeliverAt *time.Time      `json:"deliver_at,omitempty"`
+	Time      time.Time       `json:"time"`
+}
+
+// QueueRecord is a durable queue as written to the Store.
+// Released queues are kept until their pending events are acknowledged.
+type QueueRecord struct {
+	ID       string `json:"id"`
+	Prefix   string `json:"prefix"`
+	Released bool   `json:"released,omitempty"`
+}
+
+// DelayedStats describes the delayed events waiting for delivery.
+type DelayedStats struct {
+	Pending  int            `json:"pending"`             // Number of pending delayed events
+	Next     *time.Time     `json:"next,omitempty"`      // Earliest delivery time, nil if none
+	ByPrefix map[string]int `json:"by_prefix,omitempty"` // Pending events per handler prefix
 }
 
 // FilterOption configures a Listener or Subscriber registration.
@@ -14,6 +14,7 @@ import (
 // ReservedWorkers reserves slots for Call events so Push cannot starve them.
 type workerPool struct {
 	handler types.Handler
+	autoAck bool
 
 	// semTotal is a buffered channel of size MaxWorkers.
 	semTotal chan struct{}
@@ -33,6 +34,7 @@ func newWorkerPool(entry *types.HandlerEntry) *workerPool {
 	}
 	return &workerPool{
 		handler:  entry.Handler,
+		autoAck:  entry.AutoAck,
 		semTotal: make(chan struct{}, entry.MaxWorkers),
 		semPush:  make(chan struct{}, pushSlots),
 	}
@@ -73,6 +75,9 @@ func (wp *workerPool) dispatch(ctx context.Context, ev *types.Event, resp chan<-
 		defer wp.recoverPanic(ev, resp)
 
 		wp.handler.Handle(ctx, ev, resp)
+		if wp.autoAck && ev.Durable {
+			ack(ev)
+		}
 	}()
 
 	return ch, nil
@@ -2,6 +2,7 @@ package excel
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sync"
@@ -89,6 +90,16 @@ func Open(path string, writable bool) (string, error) {
 	return id, nil
 }
 
+// OpenReader opens a workbook from a reader (e.g. an uploaded file) in read-only mode
+// The workbook is not registered as a handle, the caller must close it
+func OpenReader(reader io.Reader) (*Excel, error) {
+	excelFile, err := excelize.OpenReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("open workbook failed: %w", err)
+	}
+	return &Excel{File: excelFile, create: time.Now().Unix()}, nil
+}
+
 // Close close the excel file
 func Close(handler string) error {
 	excel, ok := openFiles.Load(handler)
@@ -3,6 +3,7 @@ package excel
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -95,6 +96,32 @@ func TestOpenInvalidFile(t *testing.T) {
 	assert.Error(t, err, "should fail to open invalid excel file")
 }
 
+func TestOpenReader(t *testing.T) {
+	xls, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	xls.SetCellValue("Sheet1", "A1", "Name")
+	xls.SetCellValue("Sheet1", "A2", "Yao")
+	buf, err := xls.WriteToBuffer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := OpenReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	rows, err := reader.ReadSheetRows("Sheet1", 0, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Name"}, {"Yao"}}, rows)
+
+	_, err = OpenReader(strings.NewReader("invalid content"))
+	assert.Error(t, err, "should fail to open invalid workbook")
+}
+
 func TestCloseErrors(t *testing.T) {
 	// Test closing non-existent handler
 	err := Close("non-existent-handler")
@@ -63,6 +63,66 @@ func VirtualEndpoint(fullMethod string, req interface{}) (method string, path st
 	case "/yao.Yao/Heartbeat":
 		return "POST", "/grpc/heartbeat"
 
+	case "/yao.Yao/KBListCollections":
+		return "GET", "/grpc/kb/collections"
+
+	case "/yao.Yao/KBCreateCollection":
+		return "POST", "/grpc/kb/collections"
+
+	case "/yao.Yao/KBGetCollection":
+		if r, ok := req.(*pb.KBCollectionRequest); ok && r.CollectionId != "" {
+			return "GET", "/grpc/kb/collections/" + r.CollectionId
+		}
+		return "GET", "/grpc/kb/collections/"
+
+	case "/yao.Yao/KBRemoveCollection":
+		if r, ok := req.(*pb.KBCollectionRequest); ok && r.CollectionId != "" {
+			return "DELETE", "/grpc/kb/collections/" + r.CollectionId
+		}
+		return "DELETE", "/grpc/kb/collections/"
+
+	case "/yao.Yao/KBAddFile", "/yao.Yao/KBAddText", "/yao.Yao/KBAddURL":
+		if r, ok := req.(*pb.KBAddRequest); ok && r.CollectionId != "" {
+			return "POST", fmt.Sprintf("/grpc/kb/collections/%s/documents", r.CollectionId)
+		}
+		return "POST", "/grpc/kb/collections/"
+
+	case "/yao.Yao/KBSearch":
+		return "POST", "/grpc/kb/search"
+
+	case "/yao.Yao/JobCreate":
+		return "POST", "/grpc/job/jobs"
+
+	case "/yao.Yao/JobAdd":
+		if r, ok := req.(*pb.JobAddRequest); ok && r.JobId != "" {
+			return "POST", fmt.Sprintf("/grpc/job/jobs/%s/executions", r.JobId)
+		}
+		return "POST", "/grpc/job/jobs/"
+
+	case "/yao.Yao/JobPush":
+		if r, ok := req.(*pb.JobRequest); ok && r.JobId != "" {
+			return "POST", fmt.Sprintf("/grpc/job/jobs/%s/push", r.JobId)
+		}
+		return "POST", "/grpc/job/jobs/"
+
+	case "/yao.Yao/JobLogs":
+		if r, ok := req.(*pb.JobLogsRequest); ok && r.JobId != "" {
+			return "GET", fmt.Sprintf("/grpc/job/jobs/%s/logs", r.JobId)
+		}
+		return "GET", "/grpc/job/jobs/"
+
+	case "/yao.Yao/JobProgress":
+		return "GET", "/grpc/job/progress"
+
+	case "/yao.Yao/TraceTree":
+		if r, ok := req.(*pb.TraceRequest); ok && r.TraceId != "" {
+			return "GET", "/grpc/trace/traces/" + r.TraceId
+		}
+		return "GET", "/grpc/trace/traces/"
+
+	case "/yao.Yao/TraceSubscribe":
+		return "GET", "/grpc/trace/subscribe"
+
 	case "/tai.tunnel.TaiTunnel/Register":
 		return "POST", "/grpc/tai/register"
 
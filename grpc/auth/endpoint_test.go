@@ -146,3 +146,69 @@ func TestVirtualEndpoint_HeartbeatNilReq(t *testing.T) {
 	assert.Equal(t, "POST", method)
 	assert.Equal(t, "/grpc/heartbeat", path)
 }
+
+func TestVirtualEndpoint_KBCollections(t *testing.T) {
+	method, path := auth.VirtualEndpoint("/yao.Yao/KBListCollections", &pb.KBRequest{})
+	assert.Equal(t, "GET", method)
+	assert.Equal(t, "/grpc/kb/collections", path)
+
+	method, path = auth.VirtualEndpoint("/yao.Yao/KBCreateCollection", &pb.KBRequest{})
+	assert.Equal(t, "POST", method)
+	assert.Equal(t, "/grpc/kb/collections", path)
+
+	method, path = auth.VirtualEndpoint("/yao.Yao/KBGetCollection", &pb.KBCollectionRequest{CollectionId: "docs"})
+	assert.Equal(t, "GET", method)
+	assert.Equal(t, "/grpc/kb/collections/docs", path)
+
+	method, path = auth.VirtualEndpoint("/yao.Yao/KBRemoveCollection", &pb.KBCollectionRequest{CollectionId: "docs"})
+	assert.Equal(t, "DELETE", method)
+	assert.Equal(t, "/grpc/kb/collections/docs", path)
+}
+
+func TestVirtualEndpoint_KBAddDocuments(t *testing.T) {
+	for _, fullMethod := range []string{"/yao.Yao/KBAddFile", "/yao.Yao/KBAddText", "/yao.Yao/KBAddURL"} {
+		method, path := auth.VirtualEndpoint(fullMethod, &pb.KBAddRequest{CollectionId: "docs"})
+		assert.Equal(t, "POST", method)
+		assert.Equal(t, "/grpc/kb/collections/docs/documents", path)
+	}
+
+	method, path := auth.VirtualEndpoint("/yao.Yao/KBSearch", &pb.KBRequest{})
+	assert.Equal(t, "POST", method)
+	assert.Equal(t, "/grpc/kb/search", path)
+}
+
+func TestVirtualEndpoint_Job(t *testing.T) {
+	method, path := auth.VirtualEndpoint("/yao.Yao/JobCreate", &pb.JobCreateRequest{})
+	assert.Equal(t, "POST", method)
+	assert.Equal(t, "/grpc/job/jobs", path)
+
+	method, path = auth.VirtualEndpoint("/yao.Yao/JobAdd", &pb.JobAddRequest{JobId: "j1"})
+	assert.Equal(t, "POST", method)
+	assert.Equal(t, "/grpc/job/jobs/j1/executions", path)
+
+	method, path = auth.VirtualEndpoint("/yao.Yao/JobPush", &pb.JobRequest{JobId: "j1"})
+	assert.Equal(t, "POST", method)
+	assert.Equal(t, "/grpc/job/jobs/j1/push", path)
+
+	method, path = auth.VirtualEndpoint("/yao.Yao/JobLogs", &pb.JobLogsRequest{JobId: "j1"})
+	assert.Equal(t, "GET", method)
+	assert.Equal(t, "/grpc/job/jobs/j1/logs", path)
+
+	method, path = auth.VirtualEndpoint("/yao.Yao/JobProgress", nil)
+	assert.Equal(t, "GET", method)
+	assert.Equal(t, "/grpc/job/progress", path)
+}
+
+func TestVirtualEndpoint_Trace(t *testing.T) {
+	method, path := auth.VirtualEndpoint("/yao.Yao/TraceTree", &pb.TraceRequest{TraceId: "t1"})
+	assert.Equal(t, "GET", method)
+	assert.Equal(t, "/grpc/trace/traces/t1", path)
+
+	method, path = auth.VirtualEndpoint("/yao.Yao/TraceTree", nil)
+	assert.Equal(t, "GET", method)
+	assert.Equal(t, "/grpc/trace/traces/", path)
+
+	method, path = auth.VirtualEndpoint("/yao.Yao/TraceSubscribe", nil)
+	assert.Equal(t, "GET", method)
+	assert.Equal(t, "/grpc/trace/subscribe", path)
+}
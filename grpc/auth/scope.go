@@ -10,6 +10,9 @@ func init() {
 		&acl.ScopeDefinition{Name: "grpc:mcp", Endpoints: []string{"GET /grpc/mcp/tools", "POST /grpc/mcp/call/*", "POST /grpc/mcp/call/", "GET /grpc/mcp/resources", "GET /grpc/mcp/resources/read", "POST /grpc/heartbeat"}},
 		&acl.ScopeDefinition{Name: "grpc:llm", Endpoints: []string{"POST /grpc/llm/completions"}},
 		&acl.ScopeDefinition{Name: "grpc:agent", Endpoints: []string{"POST /grpc/agent/*", "POST /grpc/agent/"}},
+		&acl.ScopeDefinition{Name: "grpc:kb", Endpoints: []string{"GET /grpc/kb/collections", "GET /grpc/kb/collections/*", "POST /grpc/kb/collections", "POST /grpc/kb/collections/*", "DELETE /grpc/kb/collections/*", "DELETE /grpc/kb/collections/", "POST /grpc/kb/search"}},
+		&acl.ScopeDefinition{Name: "grpc:job", Endpoints: []string{"POST /grpc/job/jobs", "POST /grpc/job/jobs/*", "GET /grpc/job/jobs/*", "GET /grpc/job/jobs/", "GET /grpc/job/progress"}},
+		&acl.ScopeDefinition{Name: "grpc:trace", Endpoints: []string{"GET /grpc/trace/traces/*", "GET /grpc/trace/traces/", "GET /grpc/trace/subscribe"}},
 		&acl.ScopeDefinition{Name: "tai:connect", Endpoints: []string{"POST /grpc/tai/register", "POST /grpc/tai/forward"}},
 	)
 }
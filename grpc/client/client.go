@@ -231,6 +231,180 @@ func (c *Client) AgentStream(ctx context.Context, assistantID string, messages,
 	}
 }
 
+// --- Knowledge base ---
+
+// KBListCollections lists collections, filter is a JSON-encoded kb/api ListCollectionsFilter.
+func (c *Client) KBListCollections(ctx context.Context, filter []byte) ([]byte, error) {
+	resp, err := c.svc.KBListCollections(ctx, &pb.KBRequest{Params: filter})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// KBGetCollection returns a collection by ID.
+func (c *Client) KBGetCollection(ctx context.Context, collectionID string) ([]byte, error) {
+	resp, err := c.svc.KBGetCollection(ctx, &pb.KBCollectionRequest{CollectionId: collectionID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// KBCreateCollection creates a collection, params is a JSON-encoded kb/api CreateCollectionParams.
+func (c *Client) KBCreateCollection(ctx context.Context, params []byte) ([]byte, error) {
+	resp, err := c.svc.KBCreateCollection(ctx, &pb.KBRequest{Params: params})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// KBRemoveCollection removes a collection and its documents.
+func (c *Client) KBRemoveCollection(ctx context.Context, collectionID string) ([]byte, error) {
+	resp, err := c.svc.KBRemoveCollection(ctx, &pb.KBCollectionRequest{CollectionId: collectionID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// KBAddFile adds an uploaded file to a collection, async processes it in a job.
+func (c *Client) KBAddFile(ctx context.Context, collectionID string, params []byte, async bool) ([]byte, error) {
+	resp, err := c.svc.KBAddFile(ctx, &pb.KBAddRequest{CollectionId: collectionID, Params: params, Async: async})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// KBAddText adds a text document to a collection, async processes it in a job.
+func (c *Client) KBAddText(ctx context.Context, collectionID string, params []byte, async bool) ([]byte, error) {
+	resp, err := c.svc.KBAddText(ctx, &pb.KBAddRequest{CollectionId: collectionID, Params: params, Async: async})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// KBAddURL adds a web page to a collection, async processes it in a job.
+func (c *Client) KBAddURL(ctx context.Context, collectionID string, params []byte, async bool) ([]byte, error) {
+	resp, err := c.svc.KBAddURL(ctx, &pb.KBAddRequest{CollectionId: collectionID, Params: params, Async: async})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// KBSearch searches collections, queries is a JSON-encoded array of kb/api Query.
+func (c *Client) KBSearch(ctx context.Context, queries []byte) ([]byte, error) {
+	resp, err := c.svc.KBSearch(ctx, &pb.KBRequest{Params: queries})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// --- Job ---
+
+// JobCreate creates a job and returns its ID and the JSON-encoded job.
+func (c *Client) JobCreate(ctx context.Context, data []byte) (string, []byte, error) {
+	resp, err := c.svc.JobCreate(ctx, &pb.JobCreateRequest{Data: data})
+	if err != nil {
+		return "", nil, err
+	}
+	return resp.JobId, resp.Data, nil
+}
+
+// JobAdd adds a process execution to a job and returns the JSON-encoded executions.
+func (c *Client) JobAdd(ctx context.Context, jobID, process string, args []byte, priority int32) ([]byte, error) {
+	resp, err := c.svc.JobAdd(ctx, &pb.JobAddRequest{
+		JobId:    jobID,
+		Process:  process,
+		Args:     args,
+		Priority: priority,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// JobPush pushes the job executions to the worker queue.
+func (c *Client) JobPush(ctx context.Context, jobID string) ([]byte, error) {
+	resp, err := c.svc.JobPush(ctx, &pb.JobRequest{JobId: jobID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// JobProgress streams the job progress until the job is finished.
+func (c *Client) JobProgress(ctx context.Context, jobID string, cb func(data []byte, done bool) error) error {
+	stream, err := c.svc.JobProgress(ctx, &pb.JobRequest{JobId: jobID})
+	if err != nil {
+		return err
+	}
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := cb(chunk.Data, chunk.Done); err != nil {
+			return err
+		}
+		if chunk.Done {
+			return nil
+		}
+	}
+}
+
+// JobLogs lists the job logs, the latest first.
+func (c *Client) JobLogs(ctx context.Context, jobID string, page, pagesize int32) ([]byte, error) {
+	resp, err := c.svc.JobLogs(ctx, &pb.JobLogsRequest{JobId: jobID, Page: page, Pagesize: pagesize})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// --- Trace ---
+
+// TraceTree returns the trace info with the node tree.
+func (c *Client) TraceTree(ctx context.Context, traceID string) ([]byte, error) {
+	resp, err := c.svc.TraceTree(ctx, &pb.TraceRequest{TraceId: traceID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// TraceSubscribe streams the trace updates since a timestamp (ms) until the trace is complete.
+func (c *Client) TraceSubscribe(ctx context.Context, traceID string, since int64, cb func(data []byte, done bool) error) error {
+	stream, err := c.svc.TraceSubscribe(ctx, &pb.TraceSubscribeRequest{TraceId: traceID, Since: since})
+	if err != nil {
+		return err
+	}
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := cb(chunk.Data, chunk.Done); err != nil {
+			return err
+		}
+		if chunk.Done {
+			return nil
+		}
+	}
+}
+
 // --- Sandbox ---
 
 // Heartbeat sends a sandbox heartbeat to the Yao gRPC server.
@@ -19,12 +19,15 @@ import (
 	apihandler "github.com/yaoapp/yao/grpc/api"
 	"github.com/yaoapp/yao/grpc/auth"
 	"github.com/yaoapp/yao/grpc/health"
+	jobhandler "github.com/yaoapp/yao/grpc/job"
+	kbhandler "github.com/yaoapp/yao/grpc/kb"
 	llmhandler "github.com/yaoapp/yao/grpc/llm"
 	mcphandler "github.com/yaoapp/yao/grpc/mcp"
 	"github.com/yaoapp/yao/grpc/pb"
 	runhandler "github.com/yaoapp/yao/grpc/run"
 	sandboxhandler "github.com/yaoapp/yao/grpc/sandbox"
 	shellhandler "github.com/yaoapp/yao/grpc/shell"
+	tracehandler "github.com/yaoapp/yao/grpc/trace"
 	"github.com/yaoapp/yao/tai/registry"
 	"github.com/yaoapp/yao/tai/tunnel"
 	"github.com/yaoapp/yao/tai/tunnel/taipb"
@@ -46,6 +49,9 @@ type yaoServer struct {
 	mcp     mcphandler.Handler
 	llm     llmhandler.Handler
 	agent   agenthandler.Handler
+	kb      kbhandler.Handler
+	job     jobhandler.Handler
+	trace   tracehandler.Handler
 	sandbox *sandboxhandler.Handler
 }
 
@@ -114,6 +120,72 @@ func (s *yaoServer) AgentStream(req *pb.AgentRequest, stream grpc.ServerStreamin
 	return s.agent.AgentStream(req, stream)
 }
 
+// ── Knowledge base ───────────────────────────────────────────────────────────
+
+func (s *yaoServer) KBListCollections(ctx context.Context, req *pb.KBRequest) (*pb.KBResponse, error) {
+	return s.kb.KBListCollections(ctx, req)
+}
+
+func (s *yaoServer) KBGetCollection(ctx context.Context, req *pb.KBCollectionRequest) (*pb.KBResponse, error) {
+	return s.kb.KBGetCollection(ctx, req)
+}
+
+func (s *yaoServer) KBCreateCollection(ctx context.Context, req *pb.KBRequest) (*pb.KBResponse, error) {
+	return s.kb.KBCreateCollection(ctx, req)
+}
+
+func (s *yaoServer) KBRemoveCollection(ctx context.Context, req *pb.KBCollectionRequest) (*pb.KBResponse, error) {
+	return s.kb.KBRemoveCollection(ctx, req)
+}
+
+func (s *yaoServer) KBAddFile(ctx context.Context, req *pb.KBAddRequest) (*pb.KBResponse, error) {
+	return s.kb.KBAddFile(ctx, req)
+}
+
+func (s *yaoServer) KBAddText(ctx context.Context, req *pb.KBAddRequest) (*pb.KBResponse, error) {
+	return s.kb.KBAddText(ctx, req)
+}
+
+func (s *yaoServer) KBAddURL(ctx context.Context, req *pb.KBAddRequest) (*pb.KBResponse, error) {
+	return s.kb.KBAddURL(ctx, req)
+}
+
+func (s *yaoServer) KBSearch(ctx context.Context, req *pb.KBRequest) (*pb.KBResponse, error) {
+	return s.kb.KBSearch(ctx, req)
+}
+
+// ── Job ──────────────────────────────────────────────────────────────────────
+
+func (s *yaoServer) JobCreate(ctx context.Context, req *pb.JobCreateRequest) (*pb.JobResponse, error) {
+	return s.job.JobCreate(ctx, req)
+}
+
+func (s *yaoServer) JobAdd(ctx context.Context, req *pb.JobAddRequest) (*pb.JobResponse, error) {
+	return s.job.JobAdd(ctx, req)
+}
+
+func (s *yaoServer) JobPush(ctx context.Context, req *pb.JobRequest) (*pb.JobResponse, error) {
+	return s.job.JobPush(ctx, req)
+}
+
+func (s *yaoServer) JobProgress(req *pb.JobRequest, stream grpc.ServerStreamingServer[pb.JobChunk]) error {
+	return s.job.JobProgress(req, stream)
+}
+
+func (s *yaoServer) JobLogs(ctx context.Context, req *pb.JobLogsRequest) (*pb.JobResponse, error) {
+	return s.job.JobLogs(ctx, req)
+}
+
+// ── Trace ────────────────────────────────────────────────────────────────────
+
+func (s *yaoServer) TraceTree(ctx context.Context, req *pb.TraceRequest) (*pb.TraceResponse, error) {
+	return s.trace.TraceTree(ctx, req)
+}
+
+func (s *yaoServer) TraceSubscribe(req *pb.TraceSubscribeRequest, stream grpc.ServerStreamingServer[pb.TraceChunk]) error {
+	return s.trace.TraceSubscribe(req, stream)
+}
+
 // ── Sandbox ──────────────────────────────────────────────────────────────────
 
 func (s *yaoServer) Heartbeat(ctx context.Context, req *pb.HeartbeatRequest) (*pb.HeartbeatResponse, error) {
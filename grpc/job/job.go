@@ -0,0 +1,266 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/yaoapp/gou/model"
+	"github.com/yaoapp/yao/grpc/auth"
+	"github.com/yaoapp/yao/grpc/pb"
+	"github.com/yaoapp/yao/job"
+	"github.com/yaoapp/yao/openapi/oauth/types"
+)
+
+// progressInterval is how often JobProgress polls the job status.
+var progressInterval = 500 * time.Millisecond
+
+// finished job statuses, JobProgress ends the stream on any of them.
+var finished = map[string]bool{
+	"completed": true,
+	"failed":    true,
+	"cancelled": true,
+	"disabled":  true,
+	"deleted":   true,
+}
+
+// Handler implements the job gRPC methods.
+type Handler struct{}
+
+// Progress is the job status sent by JobProgress.
+type Progress struct {
+	JobID      string              `json:"job_id"`
+	Status     string              `json:"status"`
+	Executions []ExecutionProgress `json:"executions"`
+}
+
+// ExecutionProgress is the progress of a job execution.
+type ExecutionProgress struct {
+	ExecutionID string           `json:"execution_id"`
+	Status      string           `json:"status"`
+	Progress    int              `json:"progress"`
+	Result      *json.RawMessage `json:"result,omitempty"`
+	Error       *json.RawMessage `json:"error,omitempty"`
+}
+
+// JobCreate creates a once job in goroutine mode owned by the caller.
+// Data is the JSON-encoded job data (name, description, category_name, ...).
+func (h *Handler) JobCreate(ctx context.Context, req *pb.JobCreateRequest) (*pb.JobResponse, error) {
+	data := map[string]interface{}{}
+	if len(req.Data) > 0 {
+		if err := json.Unmarshal(req.Data, &data); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid data JSON: %v", err)
+		}
+	}
+
+	if name, _ := data["name"].(string); name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	if info := auth.GetAuthorizedInfo(ctx); info != nil {
+		data = info.WithCreateScope(data)
+		if info.UserID != "" {
+			data["created_by"] = info.UserID
+		}
+	}
+
+	j, err := job.OnceAndSave(job.GOROUTINE, data)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "create job failed: %v", err)
+	}
+	return respond(j.JobID, j)
+}
+
+// JobAdd adds a process execution to a job.
+func (h *Handler) JobAdd(ctx context.Context, req *pb.JobAddRequest) (*pb.JobResponse, error) {
+	if req.Process == "" {
+		return nil, status.Error(codes.InvalidArgument, "process is required")
+	}
+
+	var args []interface{}
+	if len(req.Args) > 0 {
+		if err := json.Unmarshal(req.Args, &args); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid args JSON: %v", err)
+		}
+	}
+
+	j, err := getJob(ctx, req.JobId)
+	if err != nil {
+		return nil, err
+	}
+
+	options := job.NewExecutionOptions().WithPriority(int(req.Priority))
+	if info := auth.GetAuthorizedInfo(ctx); info != nil {
+		options.SharedData["authorized"] = info.AuthorizedToMap()
+	}
+
+	if err := j.Add(options, req.Process, args...); err != nil {
+		return nil, status.Errorf(codes.Internal, "add execution failed: %v", err)
+	}
+
+	executions, err := j.GetExecutions()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "get executions failed: %v", err)
+	}
+	return respond(j.JobID, executions)
+}
+
+// JobPush pushes the job executions to the worker queue.
+func (h *Handler) JobPush(ctx context.Context, req *pb.JobRequest) (*pb.JobResponse, error) {
+	j, err := getJob(ctx, req.JobId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := j.Push(); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "push job failed: %v", err)
+	}
+	return respond(j.JobID, map[string]interface{}{"job_id": j.JobID, "status": j.Status})
+}
+
+// JobProgress streams the job status and the progress of its executions.
+// A chunk is sent whenever the progress changes, the stream ends with a
+// done chunk once the job is finished.
+func (h *Handler) JobProgress(req *pb.JobRequest, stream grpc.ServerStreamingServer[pb.JobChunk]) error {
+	ctx := stream.Context()
+
+	j, err := getJob(ctx, req.JobId)
+	if err != nil {
+		return err
+	}
+
+	var last *Progress
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+
+	for {
+		current, err := progress(j.JobID)
+		if err != nil {
+			return err
+		}
+
+		done := finished[current.Status]
+		if last == nil || done || !reflect.DeepEqual(last, current) {
+			data, err := json.Marshal(current)
+			if err != nil {
+				return status.Errorf(codes.Internal, "failed to marshal progress: %v", err)
+			}
+			if err := stream.Send(&pb.JobChunk{Data: data, Done: done}); err != nil {
+				return err
+			}
+		}
+
+		if done {
+			return nil
+		}
+		last = current
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// JobLogs lists the logs of a job, the latest first.
+func (h *Handler) JobLogs(ctx context.Context, req *pb.JobLogsRequest) (*pb.JobResponse, error) {
+	j, err := getJob(ctx, req.JobId)
+	if err != nil {
+		return nil, err
+	}
+
+	page := int(req.Page)
+	if page <= 0 {
+		page = 1
+	}
+
+	pagesize := int(req.Pagesize)
+	if pagesize <= 0 || pagesize > 1000 {
+		pagesize = 50
+	}
+
+	result, err := job.ListLogs(j.JobID, model.QueryParam{
+		Orders: []model.QueryOrder{{Column: "timestamp", Option: "desc"}},
+	}, page, pagesize)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "list logs failed: %v", err)
+	}
+	return respond(j.JobID, result)
+}
+
+// getJob returns the job if the caller can access it, same rules as the openapi/job HasJobAccess.
+func getJob(ctx context.Context, jobID string) (*job.Job, error) {
+	if jobID == "" {
+		return nil, status.Error(codes.InvalidArgument, "job_id is required")
+	}
+
+	j, err := job.GetJob(jobID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, status.Errorf(codes.NotFound, "job not found: %s", jobID)
+		}
+		return nil, status.Errorf(codes.Internal, "get job failed: %v", err)
+	}
+
+	if !hasAccess(auth.GetAuthorizedInfo(ctx), j) {
+		return nil, status.Errorf(codes.NotFound, "job not found: %s", jobID)
+	}
+	return j, nil
+}
+
+func hasAccess(info *types.AuthorizedInfo, j *job.Job) bool {
+	if info == nil {
+		return true
+	}
+
+	scope := info.AccessScope()
+	if info.Constraints.TeamOnly && info.TeamID != "" && info.UserID != "" {
+		return j.YaoTeamID == scope.TeamID
+	}
+
+	if info.Constraints.OwnerOnly && info.UserID != "" {
+		return j.YaoTeamID == "" && j.YaoCreatedBy == scope.CreatedBy
+	}
+
+	return true
+}
+
+// progress reads the job status and the progress of its executions from the database.
+func progress(jobID string) (*Progress, error) {
+	j, err := job.GetJob(jobID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "get job failed: %v", err)
+	}
+
+	executions, err := job.GetExecutions(jobID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "get executions failed: %v", err)
+	}
+
+	p := &Progress{JobID: jobID, Status: j.Status, Executions: []ExecutionProgress{}}
+	for _, execution := range executions {
+		p.Executions = append(p.Executions, ExecutionProgress{
+			ExecutionID: execution.ExecutionID,
+			Status:      execution.Status,
+			Progress:    execution.Progress,
+			Result:      execution.Result,
+			Error:       execution.ErrorInfo,
+		})
+	}
+	return p, nil
+}
+
+func respond(jobID string, result interface{}) (*pb.JobResponse, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to marshal result: %v", err)
+	}
+	return &pb.JobResponse{JobId: jobID, Data: data}, nil
+}
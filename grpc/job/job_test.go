@@ -0,0 +1,116 @@
+package job_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	jobhandler "github.com/yaoapp/yao/grpc/job"
+	"github.com/yaoapp/yao/grpc/pb"
+	"github.com/yaoapp/yao/grpc/tests/testutils"
+)
+
+func TestJob_CreateAddPushProgress(t *testing.T) {
+	conn := testutils.Prepare(t)
+	defer testutils.Clean()
+
+	client := testutils.NewClient(conn)
+	token := testutils.ObtainAccessToken(t, "grpc:job")
+	ctx := testutils.WithToken(context.Background(), token)
+
+	data, _ := json.Marshal(map[string]interface{}{"name": "grpc job test"})
+	created, err := client.JobCreate(ctx, &pb.JobCreateRequest{Data: data})
+	require.NoError(t, err)
+	require.NotEmpty(t, created.JobId)
+
+	args, _ := json.Marshal([]interface{}{})
+	added, err := client.JobAdd(ctx, &pb.JobAddRequest{
+		JobId:   created.JobId,
+		Process: "utils.app.Ping",
+		Args:    args,
+	})
+	require.NoError(t, err)
+
+	var executions []map[string]interface{}
+	require.NoError(t, json.Unmarshal(added.Data, &executions))
+	assert.Len(t, executions, 1)
+
+	_, err = client.JobPush(ctx, &pb.JobRequest{JobId: created.JobId})
+	require.NoError(t, err)
+
+	stream, err := client.JobProgress(ctx, &pb.JobRequest{JobId: created.JobId})
+	require.NoError(t, err)
+
+	var last jobhandler.Progress
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(chunk.Data, &last))
+		if chunk.Done {
+			break
+		}
+	}
+	assert.Equal(t, created.JobId, last.JobID)
+	assert.Equal(t, "completed", last.Status)
+
+	logs, err := client.JobLogs(ctx, &pb.JobLogsRequest{JobId: created.JobId})
+	require.NoError(t, err)
+	assert.NotEmpty(t, logs.Data)
+}
+
+func TestJobCreate_NameRequired(t *testing.T) {
+	conn := testutils.Prepare(t)
+	defer testutils.Clean()
+
+	client := testutils.NewClient(conn)
+	token := testutils.ObtainAccessToken(t, "grpc:job")
+	ctx := testutils.WithToken(context.Background(), token)
+
+	_, err := client.JobCreate(ctx, &pb.JobCreateRequest{Data: []byte(`{}`)})
+	assert.Error(t, err)
+	st, _ := status.FromError(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+}
+
+func TestJobPush_NotFound(t *testing.T) {
+	conn := testutils.Prepare(t)
+	defer testutils.Clean()
+
+	client := testutils.NewClient(conn)
+	token := testutils.ObtainAccessToken(t, "grpc:job")
+	ctx := testutils.WithToken(context.Background(), token)
+
+	_, err := client.JobPush(ctx, &pb.JobRequest{JobId: "nonexistent-job-id"})
+	assert.Error(t, err)
+	st, _ := status.FromError(err)
+	assert.Equal(t, codes.NotFound, st.Code())
+}
+
+func TestJobProgress_EmptyID(t *testing.T) {
+	conn := testutils.Prepare(t)
+	defer testutils.Clean()
+
+	client := testutils.NewClient(conn)
+	token := testutils.ObtainAccessToken(t, "grpc:job")
+	ctx := testutils.WithToken(context.Background(), token)
+
+	stream, err := client.JobProgress(ctx, &pb.JobRequest{})
+	if err != nil {
+		st, _ := status.FromError(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		return
+	}
+	_, err = stream.Recv()
+	assert.Error(t, err)
+	st, _ := status.FromError(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+}
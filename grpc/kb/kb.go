@@ -0,0 +1,351 @@
+package kb
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/yaoapp/gou/model"
+	"github.com/yaoapp/yao/grpc/auth"
+	"github.com/yaoapp/yao/grpc/pb"
+	"github.com/yaoapp/yao/kb"
+	kbapi "github.com/yaoapp/yao/kb/api"
+	"github.com/yaoapp/yao/openapi/oauth/types"
+	"github.com/yaoapp/yao/openapi/utils"
+)
+
+// Handler implements the knowledge base gRPC methods.
+// Mirrors the openapi/kb handlers on top of the global kb.API instance.
+type Handler struct{}
+
+// KBListCollections lists the collections the caller can access.
+// Params is a JSON-encoded kb/api ListCollectionsFilter.
+func (h *Handler) KBListCollections(ctx context.Context, req *pb.KBRequest) (*pb.KBResponse, error) {
+	if kb.API == nil {
+		return nil, status.Error(codes.Unavailable, "knowledge base not initialized")
+	}
+
+	filter := &kbapi.ListCollectionsFilter{}
+	if err := decode(req.Params, filter); err != nil {
+		return nil, err
+	}
+	filter.AuthFilters = authFilters(auth.GetAuthorizedInfo(ctx))
+
+	result, err := kb.API.ListCollections(ctx, filter)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "ListCollections failed: %v", err)
+	}
+	return respond(result)
+}
+
+// KBGetCollection returns a collection by ID.
+func (h *Handler) KBGetCollection(ctx context.Context, req *pb.KBCollectionRequest) (*pb.KBResponse, error) {
+	if kb.API == nil {
+		return nil, status.Error(codes.Unavailable, "knowledge base not initialized")
+	}
+
+	if err := checkCollection(ctx, req.CollectionId, true); err != nil {
+		return nil, err
+	}
+
+	result, err := kb.API.GetCollection(ctx, req.CollectionId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "GetCollection failed: %v", err)
+	}
+	return respond(result)
+}
+
+// KBCreateCollection creates a collection owned by the caller.
+// Params is a JSON-encoded kb/api CreateCollectionParams.
+func (h *Handler) KBCreateCollection(ctx context.Context, req *pb.KBRequest) (*pb.KBResponse, error) {
+	if kb.API == nil {
+		return nil, status.Error(codes.Unavailable, "knowledge base not initialized")
+	}
+
+	params := &kbapi.CreateCollectionParams{}
+	if err := decode(req.Params, params); err != nil {
+		return nil, err
+	}
+	if params.ID == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	params.AuthScope = createScope(ctx)
+
+	result, err := kb.API.CreateCollection(ctx, params)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "CreateCollection failed: %v", err)
+	}
+	return respond(result)
+}
+
+// KBRemoveCollection removes a collection and its documents.
+func (h *Handler) KBRemoveCollection(ctx context.Context, req *pb.KBCollectionRequest) (*pb.KBResponse, error) {
+	if kb.API == nil {
+		return nil, status.Error(codes.Unavailable, "knowledge base not initialized")
+	}
+
+	if err := checkCollection(ctx, req.CollectionId, false); err != nil {
+		return nil, err
+	}
+
+	result, err := kb.API.RemoveCollection(ctx, req.CollectionId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "RemoveCollection failed: %v", err)
+	}
+	return respond(result)
+}
+
+// KBAddFile adds an uploaded file to a collection.
+// Params is a JSON-encoded kb/api AddFileParams.
+func (h *Handler) KBAddFile(ctx context.Context, req *pb.KBAddRequest) (*pb.KBResponse, error) {
+	if kb.API == nil {
+		return nil, status.Error(codes.Unavailable, "knowledge base not initialized")
+	}
+
+	params := &kbapi.AddFileParams{}
+	if err := decode(req.Params, params); err != nil {
+		return nil, err
+	}
+	params.CollectionID = req.CollectionId
+	if params.FileID == "" {
+		return nil, status.Error(codes.InvalidArgument, "file_id is required")
+	}
+
+	if err := checkCollection(ctx, params.CollectionID, false); err != nil {
+		return nil, err
+	}
+	params.AuthScope = createScope(ctx)
+
+	if req.Async {
+		result, err := kb.API.AddFileAsync(ctx, params)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "AddFileAsync failed: %v", err)
+		}
+		return respond(result)
+	}
+
+	result, err := kb.API.AddFile(ctx, params)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "AddFile failed: %v", err)
+	}
+	return respond(result)
+}
+
+// KBAddText adds a text document to a collection.
+// Params is a JSON-encoded kb/api AddTextParams.
+func (h *Handler) KBAddText(ctx context.Context, req *pb.KBAddRequest) (*pb.KBResponse, error) {
+	if kb.API == nil {
+		return nil, status.Error(codes.Unavailable, "knowledge base not initialized")
+	}
+
+	params := &kbapi.AddTextParams{}
+	if err := decode(req.Params, params); err != nil {
+		return nil, err
+	}
+	params.CollectionID = req.CollectionId
+	if params.Text == "" {
+		return nil, status.Error(codes.InvalidArgument, "text is required")
+	}
+
+	if err := checkCollection(ctx, params.CollectionID, false); err != nil {
+		return nil, err
+	}
+	params.AuthScope = createScope(ctx)
+
+	if req.Async {
+		result, err := kb.API.AddTextAsync(ctx, params)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "AddTextAsync failed: %v", err)
+		}
+		return respond(result)
+	}
+
+	result, err := kb.API.AddText(ctx, params)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "AddText failed: %v", err)
+	}
+	return respond(result)
+}
+
+// KBAddURL adds a web page to a collection.
+// Params is a JSON-encoded kb/api AddURLParams.
+func (h *Handler) KBAddURL(ctx context.Context, req *pb.KBAddRequest) (*pb.KBResponse, error) {
+	if kb.API == nil {
+		return nil, status.Error(codes.Unavailable, "knowledge base not initialized")
+	}
+
+	params := &kbapi.AddURLParams{}
+	if err := decode(req.Params, params); err != nil {
+		return nil, err
+	}
+	params.CollectionID = req.CollectionId
+	if params.URL == "" {
+		return nil, status.Error(codes.InvalidArgument, "url is required")
+	}
+
+	if err := checkCollection(ctx, params.CollectionID, false); err != nil {
+		return nil, err
+	}
+	params.AuthScope = createScope(ctx)
+
+	if req.Async {
+		result, err := kb.API.AddURLAsync(ctx, params)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "AddURLAsync failed: %v", err)
+		}
+		return respond(result)
+	}
+
+	result, err := kb.API.AddURL(ctx, params)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "AddURL failed: %v", err)
+	}
+	return respond(result)
+}
+
+// KBSearch searches one or more collections.
+// Params is a JSON-encoded array of kb/api Query.
+func (h *Handler) KBSearch(ctx context.Context, req *pb.KBRequest) (*pb.KBResponse, error) {
+	if kb.API == nil {
+		return nil, status.Error(codes.Unavailable, "knowledge base not initialized")
+	}
+
+	queries := []kbapi.Query{}
+	if err := decode(req.Params, &queries); err != nil {
+		return nil, err
+	}
+
+	checked := map[string]bool{}
+	for _, query := range queries {
+		if checked[query.CollectionID] {
+			continue
+		}
+		if err := checkCollection(ctx, query.CollectionID, true); err != nil {
+			return nil, err
+		}
+		checked[query.CollectionID] = true
+	}
+
+	result, err := kb.API.Search(ctx, queries)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Search failed: %v", err)
+	}
+	return respond(result)
+}
+
+func decode(data []byte, v interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid params JSON: %v", err)
+	}
+	return nil
+}
+
+func respond(result interface{}) (*pb.KBResponse, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to marshal result: %v", err)
+	}
+	return &pb.KBResponse{Data: data}, nil
+}
+
+// createScope returns the auth scope fields saved with created collections and documents.
+func createScope(ctx context.Context) map[string]interface{} {
+	info := auth.GetAuthorizedInfo(ctx)
+	if info == nil {
+		return nil
+	}
+	return info.WithCreateScope(nil)
+}
+
+// checkCollection checks the caller can access the collection, same rules as
+// the openapi/kb checkCollectionPermission. Readable also allows public and
+// team shared collections.
+func checkCollection(ctx context.Context, collectionID string, readable bool) error {
+	if collectionID == "" {
+		return status.Error(codes.InvalidArgument, "collection_id is required")
+	}
+
+	info := auth.GetAuthorizedInfo(ctx)
+	if info == nil || (!info.Constraints.TeamOnly && !info.Constraints.OwnerOnly) {
+		return nil
+	}
+
+	config, err := kb.GetConfig()
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get KB config: %v", err)
+	}
+
+	collection, err := config.FindCollection(collectionID, model.QueryParam{
+		Select: []interface{}{"collection_id", "__yao_created_by", "__yao_team_id", "public", "share"},
+		Wheres: []model.QueryWhere{{Column: "collection_id", Value: collectionID}},
+		Limit:  1,
+	})
+	if err != nil || len(collection) == 0 {
+		return status.Errorf(codes.NotFound, "collection not found: %s", collectionID)
+	}
+
+	if readable {
+		if utils.ToBool(collection["public"]) {
+			return nil
+		}
+		if collection["share"] == "team" && info.Constraints.TeamOnly {
+			return nil
+		}
+	}
+
+	owner := collection["__yao_created_by"] == info.UserID
+	team := collection["__yao_team_id"] == info.TeamID
+	if info.Constraints.TeamOnly && info.Constraints.OwnerOnly && owner && team {
+		return nil
+	}
+	if info.Constraints.OwnerOnly && owner {
+		return nil
+	}
+	if info.Constraints.TeamOnly && team {
+		return nil
+	}
+
+	return status.Errorf(codes.PermissionDenied, "no permission to access collection: %s", collectionID)
+}
+
+// authFilters returns the list filters for the caller, same rules as the openapi/kb AuthFilter.
+func authFilters(info *types.AuthorizedInfo) []model.QueryWhere {
+	if info == nil {
+		return nil
+	}
+
+	scope := info.AccessScope()
+	if info.Constraints.TeamOnly && info.TeamID != "" && info.UserID != "" {
+		return []model.QueryWhere{{
+			Wheres: []model.QueryWhere{
+				{Column: "public", Value: true, Method: "orwhere"},
+				{Wheres: []model.QueryWhere{
+					{Column: "__yao_team_id", Value: scope.TeamID},
+					{Wheres: []model.QueryWhere{
+						{Column: "__yao_created_by", Value: scope.CreatedBy},
+						{Column: "share", Value: "team", Method: "orwhere"},
+					}},
+				}, Method: "orwhere"},
+			},
+		}}
+	}
+
+	if info.Constraints.OwnerOnly && info.UserID != "" {
+		return []model.QueryWhere{{
+			Wheres: []model.QueryWhere{
+				{Column: "public", Value: true, Method: "orwhere"},
+				{Wheres: []model.QueryWhere{
+					{Column: "__yao_team_id", OP: "null"},
+					{Column: "__yao_created_by", Value: scope.CreatedBy},
+				}, Method: "orwhere"},
+			},
+		}}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package kb_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/yaoapp/yao/grpc/pb"
+	"github.com/yaoapp/yao/grpc/tests/testutils"
+)
+
+func TestKBGetCollection_EmptyID(t *testing.T) {
+	conn := testutils.Prepare(t)
+	defer testutils.Clean()
+
+	client := testutils.NewClient(conn)
+	token := testutils.ObtainAccessToken(t, "grpc:kb")
+	ctx := testutils.WithToken(context.Background(), token)
+
+	_, err := client.KBGetCollection(ctx, &pb.KBCollectionRequest{})
+	assert.Error(t, err)
+	st, _ := status.FromError(err)
+	assert.Contains(t, []codes.Code{codes.InvalidArgument, codes.Unavailable}, st.Code())
+}
+
+func TestKBCreateCollection_InvalidParams(t *testing.T) {
+	conn := testutils.Prepare(t)
+	defer testutils.Clean()
+
+	client := testutils.NewClient(conn)
+	token := testutils.ObtainAccessToken(t, "grpc:kb")
+	ctx := testutils.WithToken(context.Background(), token)
+
+	_, err := client.KBCreateCollection(ctx, &pb.KBRequest{Params: []byte("{invalid")})
+	assert.Error(t, err)
+	st, _ := status.FromError(err)
+	assert.Contains(t, []codes.Code{codes.InvalidArgument, codes.Unavailable}, st.Code())
+}
+
+func TestKBAddText_EmptyText(t *testing.T) {
+	conn := testutils.Prepare(t)
+	defer testutils.Clean()
+
+	client := testutils.NewClient(conn)
+	token := testutils.ObtainAccessToken(t, "grpc:kb")
+	ctx := testutils.WithToken(context.Background(), token)
+
+	_, err := client.KBAddText(ctx, &pb.KBAddRequest{
+		CollectionId: "grpc-test-collection",
+		Params:       []byte(`{}`),
+	})
+	assert.Error(t, err)
+	st, _ := status.FromError(err)
+	assert.Contains(t, []codes.Code{codes.InvalidArgument, codes.Unavailable}, st.Code())
+}
+
+func TestKBListCollections_WrongScope(t *testing.T) {
+	conn := testutils.Prepare(t)
+	defer testutils.Clean()
+
+	client := testutils.NewClient(conn)
+	token := testutils.ObtainAccessToken(t, "grpc:run")
+	ctx := testutils.WithToken(context.Background(), token)
+
+	_, err := client.KBListCollections(ctx, &pb.KBRequest{})
+	assert.Error(t, err)
+	st, _ := status.FromError(err)
+	assert.Equal(t, codes.PermissionDenied, st.Code())
+}
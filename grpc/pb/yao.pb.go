@@ -1226,6 +1226,712 @@ func (x *HeartbeatResponse) GetAction() string {
 	return ""
 }
 
+type KBRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Params        []byte                 `protobuf:"bytes,1,opt,name=params,proto3" json:"params,omitempty"` // JSON-encoded kb/api params (list filter, collection params or search queries)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KBRequest) Reset() {
+	*x = KBRequest{}
+	mi := &file_grpc_pb_yao_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KBRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KBRequest) ProtoMessage() {}
+
+func (x *KBRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_pb_yao_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KBRequest.ProtoReflect.Descriptor instead.
+func (*KBRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_pb_yao_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *KBRequest) GetParams() []byte {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+type KBCollectionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CollectionId  string                 `protobuf:"bytes,1,opt,name=collection_id,json=collectionId,proto3" json:"collection_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KBCollectionRequest) Reset() {
+	*x = KBCollectionRequest{}
+	mi := &file_grpc_pb_yao_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KBCollectionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KBCollectionRequest) ProtoMessage() {}
+
+func (x *KBCollectionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_pb_yao_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KBCollectionRequest.ProtoReflect.Descriptor instead.
+func (*KBCollectionRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_pb_yao_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *KBCollectionRequest) GetCollectionId() string {
+	if x != nil {
+		return x.CollectionId
+	}
+	return ""
+}
+
+type KBAddRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CollectionId  string                 `protobuf:"bytes,1,opt,name=collection_id,json=collectionId,proto3" json:"collection_id,omitempty"`
+	Params        []byte                 `protobuf:"bytes,2,opt,name=params,proto3" json:"params,omitempty"` // JSON-encoded kb/api AddFileParams, AddTextParams or AddURLParams
+	Async         bool                   `protobuf:"varint,3,opt,name=async,proto3" json:"async,omitempty"`  // process in a job, the result carries job_id and doc_id
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KBAddRequest) Reset() {
+	*x = KBAddRequest{}
+	mi := &file_grpc_pb_yao_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KBAddRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KBAddRequest) ProtoMessage() {}
+
+func (x *KBAddRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_pb_yao_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KBAddRequest.ProtoReflect.Descriptor instead.
+func (*KBAddRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_pb_yao_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *KBAddRequest) GetCollectionId() string {
+	if x != nil {
+		return x.CollectionId
+	}
+	return ""
+}
+
+func (x *KBAddRequest) GetParams() []byte {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *KBAddRequest) GetAsync() bool {
+	if x != nil {
+		return x.Async
+	}
+	return false
+}
+
+type KBResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"` // JSON-encoded result
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KBResponse) Reset() {
+	*x = KBResponse{}
+	mi := &file_grpc_pb_yao_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KBResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KBResponse) ProtoMessage() {}
+
+func (x *KBResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_pb_yao_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KBResponse.ProtoReflect.Descriptor instead.
+func (*KBResponse) Descriptor() ([]byte, []int) {
+	return file_grpc_pb_yao_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *KBResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type JobCreateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"` // JSON-encoded job data (name, description, category_name, ...)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobCreateRequest) Reset() {
+	*x = JobCreateRequest{}
+	mi := &file_grpc_pb_yao_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobCreateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobCreateRequest) ProtoMessage() {}
+
+func (x *JobCreateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_pb_yao_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobCreateRequest.ProtoReflect.Descriptor instead.
+func (*JobCreateRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_pb_yao_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *JobCreateRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type JobAddRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Process       string                 `protobuf:"bytes,2,opt,name=process,proto3" json:"process,omitempty"`
+	Args          []byte                 `protobuf:"bytes,3,opt,name=args,proto3" json:"args,omitempty"` // JSON-encoded argument array
+	Priority      int32                  `protobuf:"varint,4,opt,name=priority,proto3" json:"priority,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobAddRequest) Reset() {
+	*x = JobAddRequest{}
+	mi := &file_grpc_pb_yao_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobAddRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobAddRequest) ProtoMessage() {}
+
+func (x *JobAddRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_pb_yao_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobAddRequest.ProtoReflect.Descriptor instead.
+func (*JobAddRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_pb_yao_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *JobAddRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobAddRequest) GetProcess() string {
+	if x != nil {
+		return x.Process
+	}
+	return ""
+}
+
+func (x *JobAddRequest) GetArgs() []byte {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *JobAddRequest) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+type JobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobRequest) Reset() {
+	*x = JobRequest{}
+	mi := &file_grpc_pb_yao_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobRequest) ProtoMessage() {}
+
+func (x *JobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_pb_yao_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobRequest.ProtoReflect.Descriptor instead.
+func (*JobRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_pb_yao_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *JobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type JobLogsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Page          int32                  `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`         // 0 = 1
+	Pagesize      int32                  `protobuf:"varint,3,opt,name=pagesize,proto3" json:"pagesize,omitempty"` // 0 = 50
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobLogsRequest) Reset() {
+	*x = JobLogsRequest{}
+	mi := &file_grpc_pb_yao_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobLogsRequest) ProtoMessage() {}
+
+func (x *JobLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_pb_yao_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobLogsRequest.ProtoReflect.Descriptor instead.
+func (*JobLogsRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_pb_yao_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *JobLogsRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobLogsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *JobLogsRequest) GetPagesize() int32 {
+	if x != nil {
+		return x.Pagesize
+	}
+	return 0
+}
+
+type JobResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"` // JSON-encoded result
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobResponse) Reset() {
+	*x = JobResponse{}
+	mi := &file_grpc_pb_yao_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobResponse) ProtoMessage() {}
+
+func (x *JobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_pb_yao_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobResponse.ProtoReflect.Descriptor instead.
+func (*JobResponse) Descriptor() ([]byte, []int) {
+	return file_grpc_pb_yao_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *JobResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// Each chunk carries the JSON-encoded job status with the progress of its executions.
+type JobChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Done          bool                   `protobuf:"varint,2,opt,name=done,proto3" json:"done,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobChunk) Reset() {
+	*x = JobChunk{}
+	mi := &file_grpc_pb_yao_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobChunk) ProtoMessage() {}
+
+func (x *JobChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_pb_yao_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobChunk.ProtoReflect.Descriptor instead.
+func (*JobChunk) Descriptor() ([]byte, []int) {
+	return file_grpc_pb_yao_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *JobChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *JobChunk) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+type TraceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TraceId       string                 `protobuf:"bytes,1,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TraceRequest) Reset() {
+	*x = TraceRequest{}
+	mi := &file_grpc_pb_yao_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceRequest) ProtoMessage() {}
+
+func (x *TraceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_pb_yao_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceRequest.ProtoReflect.Descriptor instead.
+func (*TraceRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_pb_yao_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *TraceRequest) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+type TraceSubscribeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TraceId       string                 `protobuf:"bytes,1,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	Since         int64                  `protobuf:"varint,2,opt,name=since,proto3" json:"since,omitempty"` // milliseconds since epoch, 0 = replay from the beginning
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TraceSubscribeRequest) Reset() {
+	*x = TraceSubscribeRequest{}
+	mi := &file_grpc_pb_yao_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceSubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceSubscribeRequest) ProtoMessage() {}
+
+func (x *TraceSubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_pb_yao_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceSubscribeRequest.ProtoReflect.Descriptor instead.
+func (*TraceSubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_grpc_pb_yao_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *TraceSubscribeRequest) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+func (x *TraceSubscribeRequest) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+type TraceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"` // JSON-encoded trace info with the node tree
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TraceResponse) Reset() {
+	*x = TraceResponse{}
+	mi := &file_grpc_pb_yao_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceResponse) ProtoMessage() {}
+
+func (x *TraceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_pb_yao_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceResponse.ProtoReflect.Descriptor instead.
+func (*TraceResponse) Descriptor() ([]byte, []int) {
+	return file_grpc_pb_yao_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *TraceResponse) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// Each chunk carries a JSON-serialized trace/types.TraceUpdate.
+type TraceChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Done          bool                   `protobuf:"varint,2,opt,name=done,proto3" json:"done,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TraceChunk) Reset() {
+	*x = TraceChunk{}
+	mi := &file_grpc_pb_yao_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceChunk) ProtoMessage() {}
+
+func (x *TraceChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_grpc_pb_yao_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceChunk.ProtoReflect.Descriptor instead.
+func (*TraceChunk) Descriptor() ([]byte, []int) {
+	return file_grpc_pb_yao_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *TraceChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *TraceChunk) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
 var File_grpc_pb_yao_proto protoreflect.FileDescriptor
 
 const file_grpc_pb_yao_proto_rawDesc = "" +
@@ -1317,7 +2023,49 @@ const file_grpc_pb_yao_proto_rawDesc = "" +
 	"\tmem_bytes\x18\x03 \x01(\x03R\bmemBytes\x12#\n" +
 	"\rrunning_procs\x18\x04 \x01(\x05R\frunningProcs\"+\n" +
 	"\x11HeartbeatResponse\x12\x16\n" +
-	"\x06action\x18\x01 \x01(\tR\x06action2\xf4\x05\n" +
+	"\x06action\x18\x01 \x01(\tR\x06action\"#\n" +
+	"\tKBRequest\x12\x16\n" +
+	"\x06params\x18\x01 \x01(\fR\x06params\":\n" +
+	"\x13KBCollectionRequest\x12#\n" +
+	"\rcollection_id\x18\x01 \x01(\tR\fcollectionId\"a\n" +
+	"\fKBAddRequest\x12#\n" +
+	"\rcollection_id\x18\x01 \x01(\tR\fcollectionId\x12\x16\n" +
+	"\x06params\x18\x02 \x01(\fR\x06params\x12\x14\n" +
+	"\x05async\x18\x03 \x01(\bR\x05async\" \n" +
+	"\n" +
+	"KBResponse\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"&\n" +
+	"\x10JobCreateRequest\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"p\n" +
+	"\rJobAddRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x18\n" +
+	"\aprocess\x18\x02 \x01(\tR\aprocess\x12\x12\n" +
+	"\x04args\x18\x03 \x01(\fR\x04args\x12\x1a\n" +
+	"\bpriority\x18\x04 \x01(\x05R\bpriority\"#\n" +
+	"\n" +
+	"JobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"W\n" +
+	"\x0eJobLogsRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\x05R\x04page\x12\x1a\n" +
+	"\bpagesize\x18\x03 \x01(\x05R\bpagesize\"8\n" +
+	"\vJobResponse\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\"2\n" +
+	"\bJobChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12\x12\n" +
+	"\x04done\x18\x02 \x01(\bR\x04done\")\n" +
+	"\fTraceRequest\x12\x19\n" +
+	"\btrace_id\x18\x01 \x01(\tR\atraceId\"H\n" +
+	"\x15TraceSubscribeRequest\x12\x19\n" +
+	"\btrace_id\x18\x01 \x01(\tR\atraceId\x12\x14\n" +
+	"\x05since\x18\x02 \x01(\x03R\x05since\"#\n" +
+	"\rTraceResponse\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\"4\n" +
+	"\n" +
+	"TraceChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12\x12\n" +
+	"\x04done\x18\x02 \x01(\bR\x04done2\x8b\f\n" +
 	"\x03Yao\x12(\n" +
 	"\x03Run\x12\x0f.yao.RunRequest\x1a\x10.yao.RunResponse\x12'\n" +
 	"\x06Stream\x12\x0f.yao.RunRequest\x1a\n" +
@@ -1335,7 +2083,22 @@ const file_grpc_pb_yao_proto_rawDesc = "" +
 	"\vAgentStream\x12\x11.yao.AgentRequest\x1a\x0f.yao.AgentChunk0\x01\x12+\n" +
 	"\aHealthz\x12\n" +
 	".yao.Empty\x1a\x14.yao.HealthzResponse\x12:\n" +
-	"\tHeartbeat\x12\x15.yao.HeartbeatRequest\x1a\x16.yao.HeartbeatResponseB\x1fZ\x1dgithub.com/yaoapp/yao/grpc/pbb\x06proto3"
+	"\tHeartbeat\x12\x15.yao.HeartbeatRequest\x1a\x16.yao.HeartbeatResponse\x124\n" +
+	"\x11KBListCollections\x12\x0e.yao.KBRequest\x1a\x0f.yao.KBResponse\x12<\n" +
+	"\x0fKBGetCollection\x12\x18.yao.KBCollectionRequest\x1a\x0f.yao.KBResponse\x125\n" +
+	"\x12KBCreateCollection\x12\x0e.yao.KBRequest\x1a\x0f.yao.KBResponse\x12?\n" +
+	"\x12KBRemoveCollection\x12\x18.yao.KBCollectionRequest\x1a\x0f.yao.KBResponse\x12/\n" +
+	"\tKBAddFile\x12\x11.yao.KBAddRequest\x1a\x0f.yao.KBResponse\x12/\n" +
+	"\tKBAddText\x12\x11.yao.KBAddRequest\x1a\x0f.yao.KBResponse\x12.\n" +
+	"\bKBAddURL\x12\x11.yao.KBAddRequest\x1a\x0f.yao.KBResponse\x12+\n" +
+	"\bKBSearch\x12\x0e.yao.KBRequest\x1a\x0f.yao.KBResponse\x124\n" +
+	"\tJobCreate\x12\x15.yao.JobCreateRequest\x1a\x10.yao.JobResponse\x12.\n" +
+	"\x06JobAdd\x12\x12.yao.JobAddRequest\x1a\x10.yao.JobResponse\x12,\n" +
+	"\aJobPush\x12\x0f.yao.JobRequest\x1a\x10.yao.JobResponse\x12/\n" +
+	"\vJobProgress\x12\x0f.yao.JobRequest\x1a\r.yao.JobChunk0\x01\x120\n" +
+	"\aJobLogs\x12\x13.yao.JobLogsRequest\x1a\x10.yao.JobResponse\x122\n" +
+	"\tTraceTree\x12\x11.yao.TraceRequest\x1a\x12.yao.TraceResponse\x12?\n" +
+	"\x0eTraceSubscribe\x12\x1a.yao.TraceSubscribeRequest\x1a\x0f.yao.TraceChunk0\x01B\x1fZ\x1dgithub.com/yaoapp/yao/grpc/pbb\x06proto3"
 
 var (
 	file_grpc_pb_yao_proto_rawDescOnce sync.Once
@@ -1349,39 +2112,53 @@ func file_grpc_pb_yao_proto_rawDescGZIP() []byte {
 	return file_grpc_pb_yao_proto_rawDescData
 }
 
-var file_grpc_pb_yao_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
+var file_grpc_pb_yao_proto_msgTypes = make([]protoimpl.MessageInfo, 40)
 var file_grpc_pb_yao_proto_goTypes = []any{
-	(*RunRequest)(nil),           // 0: yao.RunRequest
-	(*RunResponse)(nil),          // 1: yao.RunResponse
-	(*Chunk)(nil),                // 2: yao.Chunk
-	(*ShellRequest)(nil),         // 3: yao.ShellRequest
-	(*ShellResponse)(nil),        // 4: yao.ShellResponse
-	(*APIRequest)(nil),           // 5: yao.APIRequest
-	(*APIResponse)(nil),          // 6: yao.APIResponse
-	(*MCPListRequest)(nil),       // 7: yao.MCPListRequest
-	(*MCPListResponse)(nil),      // 8: yao.MCPListResponse
-	(*MCPCallRequest)(nil),       // 9: yao.MCPCallRequest
-	(*MCPCallResponse)(nil),      // 10: yao.MCPCallResponse
-	(*MCPResourcesResponse)(nil), // 11: yao.MCPResourcesResponse
-	(*MCPResourceRequest)(nil),   // 12: yao.MCPResourceRequest
-	(*MCPResourceResponse)(nil),  // 13: yao.MCPResourceResponse
-	(*ChatRequest)(nil),          // 14: yao.ChatRequest
-	(*ChatResponse)(nil),         // 15: yao.ChatResponse
-	(*ChatChunk)(nil),            // 16: yao.ChatChunk
-	(*AgentRequest)(nil),         // 17: yao.AgentRequest
-	(*AgentChunk)(nil),           // 18: yao.AgentChunk
-	(*Empty)(nil),                // 19: yao.Empty
-	(*HealthzResponse)(nil),      // 20: yao.HealthzResponse
-	(*HeartbeatRequest)(nil),     // 21: yao.HeartbeatRequest
-	(*HeartbeatResponse)(nil),    // 22: yao.HeartbeatResponse
-	nil,                          // 23: yao.ShellRequest.EnvEntry
-	nil,                          // 24: yao.APIRequest.HeadersEntry
-	nil,                          // 25: yao.APIResponse.HeadersEntry
+	(*RunRequest)(nil),            // 0: yao.RunRequest
+	(*RunResponse)(nil),           // 1: yao.RunResponse
+	(*Chunk)(nil),                 // 2: yao.Chunk
+	(*ShellRequest)(nil),          // 3: yao.ShellRequest
+	(*ShellResponse)(nil),         // 4: yao.ShellResponse
+	(*APIRequest)(nil),            // 5: yao.APIRequest
+	(*APIResponse)(nil),           // 6: yao.APIResponse
+	(*MCPListRequest)(nil),        // 7: yao.MCPListRequest
+	(*MCPListResponse)(nil),       // 8: yao.MCPListResponse
+	(*MCPCallRequest)(nil),        // 9: yao.MCPCallRequest
+	(*MCPCallResponse)(nil),       // 10: yao.MCPCallResponse
+	(*MCPResourcesResponse)(nil),  // 11: yao.MCPResourcesResponse
+	(*MCPResourceRequest)(nil),    // 12: yao.MCPResourceRequest
+	(*MCPResourceResponse)(nil),   // 13: yao.MCPResourceResponse
+	(*ChatRequest)(nil),           // 14: yao.ChatRequest
+	(*ChatResponse)(nil),          // 15: yao.ChatResponse
+	(*ChatChunk)(nil),             // 16: yao.ChatChunk
+	(*AgentRequest)(nil),          // 17: yao.AgentRequest
+	(*AgentChunk)(nil),            // 18: yao.AgentChunk
+	(*Empty)(nil),                 // 19: yao.Empty
+	(*HealthzResponse)(nil),       // 20: yao.HealthzResponse
+	(*HeartbeatRequest)(nil),      // 21: yao.HeartbeatRequest
+	(*HeartbeatResponse)(nil),     // 22: yao.HeartbeatResponse
+	(*KBRequest)(nil),             // 23: yao.KBRequest
+	(*KBCollectionRequest)(nil),   // 24: yao.KBCollectionRequest
+	(*KBAddRequest)(nil),          // 25: yao.KBAddRequest
+	(*KBResponse)(nil),            // 26: yao.KBResponse
+	(*JobCreateRequest)(nil),      // 27: yao.JobCreateRequest
+	(*JobAddRequest)(nil),         // 28: yao.JobAddRequest
+	(*JobRequest)(nil),            // 29: yao.JobRequest
+	(*JobLogsRequest)(nil),        // 30: yao.JobLogsRequest
+	(*JobResponse)(nil),           // 31: yao.JobResponse
+	(*JobChunk)(nil),              // 32: yao.JobChunk
+	(*TraceRequest)(nil),          // 33: yao.TraceRequest
+	(*TraceSubscribeRequest)(nil), // 34: yao.TraceSubscribeRequest
+	(*TraceResponse)(nil),         // 35: yao.TraceResponse
+	(*TraceChunk)(nil),            // 36: yao.TraceChunk
+	nil,                           // 37: yao.ShellRequest.EnvEntry
+	nil,                           // 38: yao.APIRequest.HeadersEntry
+	nil,                           // 39: yao.APIResponse.HeadersEntry
 }
 var file_grpc_pb_yao_proto_depIdxs = []int32{
-	23, // 0: yao.ShellRequest.env:type_name -> yao.ShellRequest.EnvEntry
-	24, // 1: yao.APIRequest.headers:type_name -> yao.APIRequest.HeadersEntry
-	25, // 2: yao.APIResponse.headers:type_name -> yao.APIResponse.HeadersEntry
+	37, // 0: yao.ShellRequest.env:type_name -> yao.ShellRequest.EnvEntry
+	38, // 1: yao.APIRequest.headers:type_name -> yao.APIRequest.HeadersEntry
+	39, // 2: yao.APIResponse.headers:type_name -> yao.APIResponse.HeadersEntry
 	0,  // 3: yao.Yao.Run:input_type -> yao.RunRequest
 	0,  // 4: yao.Yao.Stream:input_type -> yao.RunRequest
 	3,  // 5: yao.Yao.Shell:input_type -> yao.ShellRequest
@@ -1396,22 +2173,52 @@ var file_grpc_pb_yao_proto_depIdxs = []int32{
 	17, // 14: yao.Yao.AgentStream:input_type -> yao.AgentRequest
 	19, // 15: yao.Yao.Healthz:input_type -> yao.Empty
 	21, // 16: yao.Yao.Heartbeat:input_type -> yao.HeartbeatRequest
-	1,  // 17: yao.Yao.Run:output_type -> yao.RunResponse
-	2,  // 18: yao.Yao.Stream:output_type -> yao.Chunk
-	4,  // 19: yao.Yao.Shell:output_type -> yao.ShellResponse
-	2,  // 20: yao.Yao.ShellStream:output_type -> yao.Chunk
-	6,  // 21: yao.Yao.API:output_type -> yao.APIResponse
-	8,  // 22: yao.Yao.MCPListTools:output_type -> yao.MCPListResponse
-	10, // 23: yao.Yao.MCPCallTool:output_type -> yao.MCPCallResponse
-	11, // 24: yao.Yao.MCPListResources:output_type -> yao.MCPResourcesResponse
-	13, // 25: yao.Yao.MCPReadResource:output_type -> yao.MCPResourceResponse
-	15, // 26: yao.Yao.ChatCompletions:output_type -> yao.ChatResponse
-	16, // 27: yao.Yao.ChatCompletionsStream:output_type -> yao.ChatChunk
-	18, // 28: yao.Yao.AgentStream:output_type -> yao.AgentChunk
-	20, // 29: yao.Yao.Healthz:output_type -> yao.HealthzResponse
-	22, // 30: yao.Yao.Heartbeat:output_type -> yao.HeartbeatResponse
-	17, // [17:31] is the sub-list for method output_type
-	3,  // [3:17] is the sub-list for method input_type
+	23, // 17: yao.Yao.KBListCollections:input_type -> yao.KBRequest
+	24, // 18: yao.Yao.KBGetCollection:input_type -> yao.KBCollectionRequest
+	23, // 19: yao.Yao.KBCreateCollection:input_type -> yao.KBRequest
+	24, // 20: yao.Yao.KBRemoveCollection:input_type -> yao.KBCollectionRequest
+	25, // 21: yao.Yao.KBAddFile:input_type -> yao.KBAddRequest
+	25, // 22: yao.Yao.KBAddText:input_type -> yao.KBAddRequest
+	25, // 23: yao.Yao.KBAddURL:input_type -> yao.KBAddRequest
+	23, // 24: yao.Yao.KBSearch:input_type -> yao.KBRequest
+	27, // 25: yao.Yao.JobCreate:input_type -> yao.JobCreateRequest
+	28, // 26: yao.Yao.JobAdd:input_type -> yao.JobAddRequest
+	29, // 27: yao.Yao.JobPush:input_type -> yao.JobRequest
+	29, // 28: yao.Yao.JobProgress:input_type -> yao.JobRequest
+	30, // 29: yao.Yao.JobLogs:input_type -> yao.JobLogsRequest
+	33, // 30: yao.Yao.TraceTree:input_type -> yao.TraceRequest
+	34, // 31: yao.Yao.TraceSubscribe:input_type -> yao.TraceSubscribeRequest
+	1,  // 32: yao.Yao.Run:output_type -> yao.RunResponse
+	2,  // 33: yao.Yao.Stream:output_type -> yao.Chunk
+	4,  // 34: yao.Yao.Shell:output_type -> yao.ShellResponse
+	2,  // 35: yao.Yao.ShellStream:output_type -> yao.Chunk
+	6,  // 36: yao.Yao.API:output_type -> yao.APIResponse
+	8,  // 37: yao.Yao.MCPListTools:output_type -> yao.MCPListResponse
+	10, // 38: yao.Yao.MCPCallTool:output_type -> yao.MCPCallResponse
+	11, // 39: yao.Yao.MCPListResources:output_type -> yao.MCPResourcesResponse
+	13, // 40: yao.Yao.MCPReadResource:output_type -> yao.MCPResourceResponse
+	15, // 41: yao.Yao.ChatCompletions:output_type -> yao.ChatResponse
+	16, // 42: yao.Yao.ChatCompletionsStream:output_type -> yao.ChatChunk
+	18, // 43: yao.Yao.AgentStream:output_type -> yao.AgentChunk
+	20, // 44: yao.Yao.Healthz:output_type -> yao.HealthzResponse
+	22, // 45: yao.Yao.Heartbeat:output_type -> yao.HeartbeatResponse
+	26, // 46: yao.Yao.KBListCollections:output_type -> yao.KBResponse
+	26, // 47: yao.Yao.KBGetCollection:output_type -> yao.KBResponse
+	26, // 48: yao.Yao.KBCreateCollection:output_type -> yao.KBResponse
+	26, // 49: yao.Yao.KBRemoveCollection:output_type -> yao.KBResponse
+	26, // 50: yao.Yao.KBAddFile:output_type -> yao.KBResponse
+	26, // 51: yao.Yao.KBAddText:output_type -> yao.KBResponse
+	26, // 52: yao.Yao.KBAddURL:output_type -> yao.KBResponse
+	26, // 53: yao.Yao.KBSearch:output_type -> yao.KBResponse
+	31, // 54: yao.Yao.JobCreate:output_type -> yao.JobResponse
+	31, // 55: yao.Yao.JobAdd:output_type -> yao.JobResponse
+	31, // 56: yao.Yao.JobPush:output_type -> yao.JobResponse
+	32, // 57: yao.Yao.JobProgress:output_type -> yao.JobChunk
+	31, // 58: yao.Yao.JobLogs:output_type -> yao.JobResponse
+	35, // 59: yao.Yao.TraceTree:output_type -> yao.TraceResponse
+	36, // 60: yao.Yao.TraceSubscribe:output_type -> yao.TraceChunk
+	32, // [32:61] is the sub-list for method output_type
+	3,  // [3:32] is the sub-list for method input_type
 	3,  // [3:3] is the sub-list for extension type_name
 	3,  // [3:3] is the sub-list for extension extendee
 	0,  // [0:3] is the sub-list for field type_name
@@ -1428,7 +2235,7 @@ func file_grpc_pb_yao_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_grpc_pb_yao_proto_rawDesc), len(file_grpc_pb_yao_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   26,
+			NumMessages:   40,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
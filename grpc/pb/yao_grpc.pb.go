@@ -33,6 +33,21 @@ const (
 	Yao_AgentStream_FullMethodName           = "/yao.Yao/AgentStream"
 	Yao_Healthz_FullMethodName               = "/yao.Yao/Healthz"
 	Yao_Heartbeat_FullMethodName             = "/yao.Yao/Heartbeat"
+	Yao_KBListCollections_FullMethodName     = "/yao.Yao/KBListCollections"
+	Yao_KBGetCollection_FullMethodName       = "/yao.Yao/KBGetCollection"
+	Yao_KBCreateCollection_FullMethodName    = "/yao.Yao/KBCreateCollection"
+	Yao_KBRemoveCollection_FullMethodName    = "/yao.Yao/KBRemoveCollection"
+	Yao_KBAddFile_FullMethodName             = "/yao.Yao/KBAddFile"
+	Yao_KBAddText_FullMethodName             = "/yao.Yao/KBAddText"
+	Yao_KBAddURL_FullMethodName              = "/yao.Yao/KBAddURL"
+	Yao_KBSearch_FullMethodName              = "/yao.Yao/KBSearch"
+	Yao_JobCreate_FullMethodName             = "/yao.Yao/JobCreate"
+	Yao_JobAdd_FullMethodName                = "/yao.Yao/JobAdd"
+	Yao_JobPush_FullMethodName               = "/yao.Yao/JobPush"
+	Yao_JobProgress_FullMethodName           = "/yao.Yao/JobProgress"
+	Yao_JobLogs_FullMethodName               = "/yao.Yao/JobLogs"
+	Yao_TraceTree_FullMethodName             = "/yao.Yao/TraceTree"
+	Yao_TraceSubscribe_FullMethodName        = "/yao.Yao/TraceSubscribe"
 )
 
 // YaoClient is the client API for Yao service.
@@ -62,6 +77,24 @@ type YaoClient interface {
 	Healthz(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*HealthzResponse, error)
 	// Sandbox
 	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
+	// Knowledge base
+	KBListCollections(ctx context.Context, in *KBRequest, opts ...grpc.CallOption) (*KBResponse, error)
+	KBGetCollection(ctx context.Context, in *KBCollectionRequest, opts ...grpc.CallOption) (*KBResponse, error)
+	KBCreateCollection(ctx context.Context, in *KBRequest, opts ...grpc.CallOption) (*KBResponse, error)
+	KBRemoveCollection(ctx context.Context, in *KBCollectionRequest, opts ...grpc.CallOption) (*KBResponse, error)
+	KBAddFile(ctx context.Context, in *KBAddRequest, opts ...grpc.CallOption) (*KBResponse, error)
+	KBAddText(ctx context.Context, in *KBAddRequest, opts ...grpc.CallOption) (*KBResponse, error)
+	KBAddURL(ctx context.Context, in *KBAddRequest, opts ...grpc.CallOption) (*KBResponse, error)
+	KBSearch(ctx context.Context, in *KBRequest, opts ...grpc.CallOption) (*KBResponse, error)
+	// Job
+	JobCreate(ctx context.Context, in *JobCreateRequest, opts ...grpc.CallOption) (*JobResponse, error)
+	JobAdd(ctx context.Context, in *JobAddRequest, opts ...grpc.CallOption) (*JobResponse, error)
+	JobPush(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobResponse, error)
+	JobProgress(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[JobChunk], error)
+	JobLogs(ctx context.Context, in *JobLogsRequest, opts ...grpc.CallOption) (*JobResponse, error)
+	// Trace
+	TraceTree(ctx context.Context, in *TraceRequest, opts ...grpc.CallOption) (*TraceResponse, error)
+	TraceSubscribe(ctx context.Context, in *TraceSubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TraceChunk], error)
 }
 
 type yaoClient struct {
@@ -248,6 +281,174 @@ func (c *yaoClient) Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ..
 	return out, nil
 }
 
+func (c *yaoClient) KBListCollections(ctx context.Context, in *KBRequest, opts ...grpc.CallOption) (*KBResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KBResponse)
+	err := c.cc.Invoke(ctx, Yao_KBListCollections_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *yaoClient) KBGetCollection(ctx context.Context, in *KBCollectionRequest, opts ...grpc.CallOption) (*KBResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KBResponse)
+	err := c.cc.Invoke(ctx, Yao_KBGetCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *yaoClient) KBCreateCollection(ctx context.Context, in *KBRequest, opts ...grpc.CallOption) (*KBResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KBResponse)
+	err := c.cc.Invoke(ctx, Yao_KBCreateCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *yaoClient) KBRemoveCollection(ctx context.Context, in *KBCollectionRequest, opts ...grpc.CallOption) (*KBResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KBResponse)
+	err := c.cc.Invoke(ctx, Yao_KBRemoveCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *yaoClient) KBAddFile(ctx context.Context, in *KBAddRequest, opts ...grpc.CallOption) (*KBResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KBResponse)
+	err := c.cc.Invoke(ctx, Yao_KBAddFile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *yaoClient) KBAddText(ctx context.Context, in *KBAddRequest, opts ...grpc.CallOption) (*KBResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KBResponse)
+	err := c.cc.Invoke(ctx, Yao_KBAddText_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *yaoClient) KBAddURL(ctx context.Context, in *KBAddRequest, opts ...grpc.CallOption) (*KBResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KBResponse)
+	err := c.cc.Invoke(ctx, Yao_KBAddURL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *yaoClient) KBSearch(ctx context.Context, in *KBRequest, opts ...grpc.CallOption) (*KBResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KBResponse)
+	err := c.cc.Invoke(ctx, Yao_KBSearch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *yaoClient) JobCreate(ctx context.Context, in *JobCreateRequest, opts ...grpc.CallOption) (*JobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobResponse)
+	err := c.cc.Invoke(ctx, Yao_JobCreate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *yaoClient) JobAdd(ctx context.Context, in *JobAddRequest, opts ...grpc.CallOption) (*JobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobResponse)
+	err := c.cc.Invoke(ctx, Yao_JobAdd_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *yaoClient) JobPush(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobResponse)
+	err := c.cc.Invoke(ctx, Yao_JobPush_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *yaoClient) JobProgress(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[JobChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Yao_ServiceDesc.Streams[4], Yao_JobProgress_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[JobRequest, JobChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Yao_JobProgressClient = grpc.ServerStreamingClient[JobChunk]
+
+func (c *yaoClient) JobLogs(ctx context.Context, in *JobLogsRequest, opts ...grpc.CallOption) (*JobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobResponse)
+	err := c.cc.Invoke(ctx, Yao_JobLogs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *yaoClient) TraceTree(ctx context.Context, in *TraceRequest, opts ...grpc.CallOption) (*TraceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TraceResponse)
+	err := c.cc.Invoke(ctx, Yao_TraceTree_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *yaoClient) TraceSubscribe(ctx context.Context, in *TraceSubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TraceChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Yao_ServiceDesc.Streams[5], Yao_TraceSubscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[TraceSubscribeRequest, TraceChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Yao_TraceSubscribeClient = grpc.ServerStreamingClient[TraceChunk]
+
 // YaoServer is the server API for Yao service.
 // All implementations must embed UnimplementedYaoServer
 // for forward compatibility.
@@ -275,6 +476,24 @@ type YaoServer interface {
 	Healthz(context.Context, *Empty) (*HealthzResponse, error)
 	// Sandbox
 	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
+	// Knowledge base
+	KBListCollections(context.Context, *KBRequest) (*KBResponse, error)
+	KBGetCollection(context.Context, *KBCollectionRequest) (*KBResponse, error)
+	KBCreateCollection(context.Context, *KBRequest) (*KBResponse, error)
+	KBRemoveCollection(context.Context, *KBCollectionRequest) (*KBResponse, error)
+	KBAddFile(context.Context, *KBAddRequest) (*KBResponse, error)
+	KBAddText(context.Context, *KBAddRequest) (*KBResponse, error)
+	KBAddURL(context.Context, *KBAddRequest) (*KBResponse, error)
+	KBSearch(context.Context, *KBRequest) (*KBResponse, error)
+	// Job
+	JobCreate(context.Context, *JobCreateRequest) (*JobResponse, error)
+	JobAdd(context.Context, *JobAddRequest) (*JobResponse, error)
+	JobPush(context.Context, *JobRequest) (*JobResponse, error)
+	JobProgress(*JobRequest, grpc.ServerStreamingServer[JobChunk]) error
+	JobLogs(context.Context, *JobLogsRequest) (*JobResponse, error)
+	// Trace
+	TraceTree(context.Context, *TraceRequest) (*TraceResponse, error)
+	TraceSubscribe(*TraceSubscribeRequest, grpc.ServerStreamingServer[TraceChunk]) error
 	mustEmbedUnimplementedYaoServer()
 }
 
@@ -327,6 +546,51 @@ func (UnimplementedYaoServer) Healthz(context.Context, *Empty) (*HealthzResponse
 func (UnimplementedYaoServer) Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Heartbeat not implemented")
 }
+func (UnimplementedYaoServer) KBListCollections(context.Context, *KBRequest) (*KBResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method KBListCollections not implemented")
+}
+func (UnimplementedYaoServer) KBGetCollection(context.Context, *KBCollectionRequest) (*KBResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method KBGetCollection not implemented")
+}
+func (UnimplementedYaoServer) KBCreateCollection(context.Context, *KBRequest) (*KBResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method KBCreateCollection not implemented")
+}
+func (UnimplementedYaoServer) KBRemoveCollection(context.Context, *KBCollectionRequest) (*KBResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method KBRemoveCollection not implemented")
+}
+func (UnimplementedYaoServer) KBAddFile(context.Context, *KBAddRequest) (*KBResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method KBAddFile not implemented")
+}
+func (UnimplementedYaoServer) KBAddText(context.Context, *KBAddRequest) (*KBResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method KBAddText not implemented")
+}
+func (UnimplementedYaoServer) KBAddURL(context.Context, *KBAddRequest) (*KBResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method KBAddURL not implemented")
+}
+func (UnimplementedYaoServer) KBSearch(context.Context, *KBRequest) (*KBResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method KBSearch not implemented")
+}
+func (UnimplementedYaoServer) JobCreate(context.Context, *JobCreateRequest) (*JobResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method JobCreate not implemented")
+}
+func (UnimplementedYaoServer) JobAdd(context.Context, *JobAddRequest) (*JobResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method JobAdd not implemented")
+}
+func (UnimplementedYaoServer) JobPush(context.Context, *JobRequest) (*JobResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method JobPush not implemented")
+}
+func (UnimplementedYaoServer) JobProgress(*JobRequest, grpc.ServerStreamingServer[JobChunk]) error {
+	return status.Error(codes.Unimplemented, "method JobProgress not implemented")
+}
+func (UnimplementedYaoServer) JobLogs(context.Context, *JobLogsRequest) (*JobResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method JobLogs not implemented")
+}
+func (UnimplementedYaoServer) TraceTree(context.Context, *TraceRequest) (*TraceResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method TraceTree not implemented")
+}
+func (UnimplementedYaoServer) TraceSubscribe(*TraceSubscribeRequest, grpc.ServerStreamingServer[TraceChunk]) error {
+	return status.Error(codes.Unimplemented, "method TraceSubscribe not implemented")
+}
 func (UnimplementedYaoServer) mustEmbedUnimplementedYaoServer() {}
 func (UnimplementedYaoServer) testEmbeddedByValue()             {}
 
@@ -572,6 +836,262 @@ func _Yao_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Yao_KBListCollections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KBRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(YaoServer).KBListCollections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Yao_KBListCollections_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(YaoServer).KBListCollections(ctx, req.(*KBRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Yao_KBGetCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KBCollectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(YaoServer).KBGetCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Yao_KBGetCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(YaoServer).KBGetCollection(ctx, req.(*KBCollectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Yao_KBCreateCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KBRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(YaoServer).KBCreateCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Yao_KBCreateCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(YaoServer).KBCreateCollection(ctx, req.(*KBRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Yao_KBRemoveCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KBCollectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(YaoServer).KBRemoveCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Yao_KBRemoveCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(YaoServer).KBRemoveCollection(ctx, req.(*KBCollectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Yao_KBAddFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KBAddRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(YaoServer).KBAddFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Yao_KBAddFile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(YaoServer).KBAddFile(ctx, req.(*KBAddRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Yao_KBAddText_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KBAddRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(YaoServer).KBAddText(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Yao_KBAddText_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(YaoServer).KBAddText(ctx, req.(*KBAddRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Yao_KBAddURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KBAddRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(YaoServer).KBAddURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Yao_KBAddURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(YaoServer).KBAddURL(ctx, req.(*KBAddRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Yao_KBSearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KBRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(YaoServer).KBSearch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Yao_KBSearch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(YaoServer).KBSearch(ctx, req.(*KBRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Yao_JobCreate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobCreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(YaoServer).JobCreate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Yao_JobCreate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(YaoServer).JobCreate(ctx, req.(*JobCreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Yao_JobAdd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobAddRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(YaoServer).JobAdd(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Yao_JobAdd_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(YaoServer).JobAdd(ctx, req.(*JobAddRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Yao_JobPush_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(YaoServer).JobPush(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Yao_JobPush_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(YaoServer).JobPush(ctx, req.(*JobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Yao_JobProgress_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(JobRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(YaoServer).JobProgress(m, &grpc.GenericServerStream[JobRequest, JobChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Yao_JobProgressServer = grpc.ServerStreamingServer[JobChunk]
+
+func _Yao_JobLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobLogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(YaoServer).JobLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Yao_JobLogs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(YaoServer).JobLogs(ctx, req.(*JobLogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Yao_TraceTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TraceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(YaoServer).TraceTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Yao_TraceTree_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(YaoServer).TraceTree(ctx, req.(*TraceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Yao_TraceSubscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TraceSubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(YaoServer).TraceSubscribe(m, &grpc.GenericServerStream[TraceSubscribeRequest, TraceChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Yao_TraceSubscribeServer = grpc.ServerStreamingServer[TraceChunk]
+
 // Yao_ServiceDesc is the grpc.ServiceDesc for Yao service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -619,6 +1139,58 @@ var Yao_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Heartbeat",
 			Handler:    _Yao_Heartbeat_Handler,
 		},
+		{
+			MethodName: "KBListCollections",
+			Handler:    _Yao_KBListCollections_Handler,
+		},
+		{
+			MethodName: "KBGetCollection",
+			Handler:    _Yao_KBGetCollection_Handler,
+		},
+		{
+			MethodName: "KBCreateCollection",
+			Handler:    _Yao_KBCreateCollection_Handler,
+		},
+		{
+			MethodName: "KBRemoveCollection",
+			Handler:    _Yao_KBRemoveCollection_Handler,
+		},
+		{
+			MethodName: "KBAddFile",
+			Handler:    _Yao_KBAddFile_Handler,
+		},
+		{
+			MethodName: "KBAddText",
+			Handler:    _Yao_KBAddText_Handler,
+		},
+		{
+			MethodName: "KBAddURL",
+			Handler:    _Yao_KBAddURL_Handler,
+		},
+		{
+			MethodName: "KBSearch",
+			Handler:    _Yao_KBSearch_Handler,
+		},
+		{
+			MethodName: "JobCreate",
+			Handler:    _Yao_JobCreate_Handler,
+		},
+		{
+			MethodName: "JobAdd",
+			Handler:    _Yao_JobAdd_Handler,
+		},
+		{
+			MethodName: "JobPush",
+			Handler:    _Yao_JobPush_Handler,
+		},
+		{
+			MethodName: "JobLogs",
+			Handler:    _Yao_JobLogs_Handler,
+		},
+		{
+			MethodName: "TraceTree",
+			Handler:    _Yao_TraceTree_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -641,6 +1213,16 @@ var Yao_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Yao_AgentStream_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "JobProgress",
+			Handler:       _Yao_JobProgress_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "TraceSubscribe",
+			Handler:       _Yao_TraceSubscribe_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "grpc/pb/yao.proto",
 }
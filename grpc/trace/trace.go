@@ -0,0 +1,175 @@
+package trace
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/yaoapp/yao/config"
+	"github.com/yaoapp/yao/grpc/auth"
+	"github.com/yaoapp/yao/grpc/pb"
+	oauthtypes "github.com/yaoapp/yao/openapi/oauth/types"
+	"github.com/yaoapp/yao/trace"
+	"github.com/yaoapp/yao/trace/types"
+)
+
+// Handler implements the trace gRPC methods.
+type Handler struct{}
+
+// TraceTree returns the trace info with the node tree, the root node carries its children.
+func (h *Handler) TraceTree(ctx context.Context, req *pb.TraceRequest) (*pb.TraceResponse, error) {
+	manager, info, release, err := load(ctx, req.TraceId)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	nodes, err := manager.GetAllNodes()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get nodes: %v", err)
+	}
+
+	// GetAllNodes flattens the tree loaded from the storage, the first one is the root
+	var root *types.TraceNode
+	if len(nodes) > 0 {
+		root = nodes[0]
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"id":         info.ID,
+		"status":     info.Status,
+		"created_at": info.CreatedAt,
+		"updated_at": info.UpdatedAt,
+		"archived":   info.Archived,
+		"count":      len(nodes),
+		"root":       root,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to marshal trace: %v", err)
+	}
+	return &pb.TraceResponse{Data: data}, nil
+}
+
+// TraceSubscribe streams the trace updates since the given timestamp, the
+// historical updates are replayed first. The stream ends with a done chunk
+// once the trace is complete.
+func (h *Handler) TraceSubscribe(req *pb.TraceSubscribeRequest, stream grpc.ServerStreamingServer[pb.TraceChunk]) error {
+	ctx := stream.Context()
+
+	manager, _, release, err := load(ctx, req.TraceId)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	updates, cancel, err := manager.SubscribeFrom(req.Since)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to subscribe: %v", err)
+	}
+	defer cancel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case update, ok := <-updates:
+			if !ok {
+				return stream.Send(&pb.TraceChunk{Done: true})
+			}
+
+			data, err := json.Marshal(update)
+			if err != nil {
+				return status.Errorf(codes.Internal, "failed to marshal update: %v", err)
+			}
+
+			done := update.Type == types.UpdateTypeComplete
+			if err := stream.Send(&pb.TraceChunk{Data: data, Done: done}); err != nil {
+				return err
+			}
+			if done {
+				return nil
+			}
+		}
+	}
+}
+
+// load returns the trace manager if the caller can read the trace, a trace
+// loaded from the storage is released by the returned function.
+// Mirrors openapi/trace loadTraceManager.
+func load(ctx context.Context, traceID string) (types.Manager, *types.TraceInfo, func(), error) {
+	if traceID == "" {
+		return nil, nil, nil, status.Error(codes.InvalidArgument, "trace_id is required")
+	}
+
+	driverType, driverOptions, err := driver()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	info, err := trace.GetInfo(ctx, driverType, traceID, driverOptions...)
+	if err != nil {
+		return nil, nil, nil, status.Errorf(codes.NotFound, "trace not found: %v", err)
+	}
+
+	if !permitted(auth.GetAuthorizedInfo(ctx), info) {
+		return nil, nil, nil, status.Errorf(codes.PermissionDenied, "no permission to access trace: %s", traceID)
+	}
+
+	if trace.IsLoaded(traceID) {
+		manager, err := trace.Load(traceID)
+		if err != nil {
+			return nil, nil, nil, status.Errorf(codes.Internal, "failed to load trace: %v", err)
+		}
+		return manager, info, func() {}, nil
+	}
+
+	_, manager, err := trace.LoadFromStorage(ctx, driverType, traceID, driverOptions...)
+	if err != nil {
+		return nil, nil, nil, status.Errorf(codes.Internal, "failed to load trace from storage: %v", err)
+	}
+	return manager, info, func() { trace.Release(traceID) }, nil
+}
+
+// permitted checks the trace read permission, same rules as openapi/trace checkTracePermission.
+func permitted(info *oauthtypes.AuthorizedInfo, traceInfo *types.TraceInfo) bool {
+	if info == nil {
+		return false
+	}
+
+	if !info.Constraints.TeamOnly && !info.Constraints.OwnerOnly {
+		return true
+	}
+
+	if info.Constraints.TeamOnly && info.Constraints.OwnerOnly &&
+		traceInfo.CreatedBy == info.UserID && traceInfo.TeamID == info.TeamID {
+		return true
+	}
+
+	if info.Constraints.OwnerOnly && traceInfo.CreatedBy == info.UserID {
+		return true
+	}
+
+	return info.Constraints.TeamOnly && traceInfo.TeamID == info.TeamID
+}
+
+// driver returns the configured trace driver type and options.
+func driver() (string, []any, error) {
+	cfg := config.Conf
+	switch cfg.Trace.Driver {
+	case "store":
+		if cfg.Trace.Store == "" {
+			return "", nil, status.Error(codes.Internal, "trace store ID not configured")
+		}
+		return trace.Store, []any{cfg.Trace.Store, cfg.Trace.Prefix}, nil
+
+	case "local", "":
+		return trace.Local, []any{cfg.Trace.Path}, nil
+
+	default:
+		return "", nil, status.Errorf(codes.Internal, "unsupported trace driver: %s", cfg.Trace.Driver)
+	}
+}
@@ -0,0 +1,75 @@
+package trace_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/yaoapp/yao/grpc/pb"
+	"github.com/yaoapp/yao/grpc/tests/testutils"
+)
+
+func TestTraceTree_EmptyID(t *testing.T) {
+	conn := testutils.Prepare(t)
+	defer testutils.Clean()
+
+	client := testutils.NewClient(conn)
+	token := testutils.ObtainAccessToken(t, "grpc:trace")
+	ctx := testutils.WithToken(context.Background(), token)
+
+	_, err := client.TraceTree(ctx, &pb.TraceRequest{})
+	assert.Error(t, err)
+	st, _ := status.FromError(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+}
+
+func TestTraceTree_NotFound(t *testing.T) {
+	conn := testutils.Prepare(t)
+	defer testutils.Clean()
+
+	client := testutils.NewClient(conn)
+	token := testutils.ObtainAccessToken(t, "grpc:trace")
+	ctx := testutils.WithToken(context.Background(), token)
+
+	_, err := client.TraceTree(ctx, &pb.TraceRequest{TraceId: "nonexistent-trace-id"})
+	assert.Error(t, err)
+	st, _ := status.FromError(err)
+	assert.Equal(t, codes.NotFound, st.Code())
+}
+
+func TestTraceSubscribe_NotFound(t *testing.T) {
+	conn := testutils.Prepare(t)
+	defer testutils.Clean()
+
+	client := testutils.NewClient(conn)
+	token := testutils.ObtainAccessToken(t, "grpc:trace")
+	ctx := testutils.WithToken(context.Background(), token)
+
+	stream, err := client.TraceSubscribe(ctx, &pb.TraceSubscribeRequest{TraceId: "nonexistent-trace-id"})
+	if err != nil {
+		st, _ := status.FromError(err)
+		assert.Equal(t, codes.NotFound, st.Code())
+		return
+	}
+	_, err = stream.Recv()
+	assert.Error(t, err)
+	st, _ := status.FromError(err)
+	assert.Equal(t, codes.NotFound, st.Code())
+}
+
+func TestTraceTree_WrongScope(t *testing.T) {
+	conn := testutils.Prepare(t)
+	defer testutils.Clean()
+
+	client := testutils.NewClient(conn)
+	token := testutils.ObtainAccessToken(t, "grpc:job")
+	ctx := testutils.WithToken(context.Background(), token)
+
+	_, err := client.TraceTree(ctx, &pb.TraceRequest{TraceId: "some-trace-id"})
+	assert.Error(t, err)
+	st, _ := status.FromError(err)
+	assert.Equal(t, codes.PermissionDenied, st.Code())
+}
@@ -0,0 +1,150 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultAPIBase = "https://slack.com/api"
+
+// Bot represents a single Slack app installation bound to a bot token.
+// Each registered robot gets its own Bot. All API methods live on Bot so
+// callers never need to pass the tokens around.
+type Bot struct {
+	token         string // bot token (xoxb-...), used for the Web API
+	appToken      string // app-level token (xapp-...), only needed for Socket Mode
+	signingSecret string // verifies Events API requests
+	apiBase       string // e.g. "https://slack.com/api"
+	httpClient    *http.Client
+}
+
+// BotOption configures optional Bot parameters.
+type BotOption func(*Bot)
+
+// WithAPIBase sets a custom Web API base URL (e.g. a mock server in tests).
+func WithAPIBase(url string) BotOption {
+	return func(b *Bot) {
+		b.apiBase = strings.TrimRight(url, "/")
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for all API calls.
+func WithHTTPClient(client *http.Client) BotOption {
+	return func(b *Bot) {
+		b.httpClient = client
+	}
+}
+
+// NewBot creates a Bot bound to the given bot token.
+// appToken is only required for Socket Mode, signingSecret only for the Events API.
+func NewBot(token, appToken, signingSecret string, opts ...BotOption) *Bot {
+	b := &Bot{
+		token:         token,
+		appToken:      appToken,
+		signingSecret: signingSecret,
+		apiBase:       defaultAPIBase,
+		httpClient:    &http.Client{Timeout: 60 * time.Second},
+	}
+	for _, o := range opts {
+		o(b)
+	}
+	return b
+}
+
+// Token returns the raw bot token.
+func (b *Bot) Token() string { return b.token }
+
+// AppToken returns the app-level token (may be empty).
+func (b *Bot) AppToken() string { return b.appToken }
+
+// SigningSecret returns the Events API signing secret (may be empty).
+func (b *Bot) SigningSecret() string { return b.signingSecret }
+
+// APIBase returns the Web API base URL.
+func (b *Bot) APIBase() string { return b.apiBase }
+
+// APIError is returned when the Web API answers with ok=false.
+type APIError struct {
+	Method string
+	Code   string // e.g. "invalid_auth", "channel_not_found"
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("slack %s: %s", e.Method, e.Code)
+}
+
+// AuthInfo is the result of auth.test.
+type AuthInfo struct {
+	URL    string `json:"url"`
+	Team   string `json:"team"`
+	User   string `json:"user"`
+	TeamID string `json:"team_id"`
+	UserID string `json:"user_id"`
+	BotID  string `json:"bot_id,omitempty"`
+}
+
+// AuthTest calls auth.test to verify the bot token and returns the bot identity.
+func (b *Bot) AuthTest(ctx context.Context) (*AuthInfo, error) {
+	var info AuthInfo
+	if err := b.call(ctx, b.token, "auth.test", nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// call posts form parameters to a Web API method and decodes the response into out.
+// Every method accepts application/x-www-form-urlencoded, JSON values are passed as strings.
+func (b *Bot) call(ctx context.Context, token, method string, params url.Values, out interface{}) error {
+	if token == "" {
+		return fmt.Errorf("slack %s: token is required", method)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.apiBase+"/"+method, strings.NewReader(params.Encode()))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("slack %s: rate limited, retry after %ss", method, resp.Header.Get("Retry-After"))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack %s: HTTP %d: %s", method, resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error,omitempty"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("unmarshal: %w", err)
+	}
+	if !result.OK {
+		return &APIError{Method: method, Code: result.Error}
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(body, out); err != nil {
+			return fmt.Errorf("unmarshal %s: %w", method, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package slack
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ConvertedMessage is the unified output after parsing a Slack message event.
+type ConvertedMessage struct {
+	EventID     string      `json:"event_id,omitempty"`
+	TS          string      `json:"ts"`
+	Channel     string      `json:"channel"`
+	ChannelType string      `json:"channel_type,omitempty"`
+	TeamID      string      `json:"team_id,omitempty"`
+	UserID      string      `json:"user_id"`
+	IsBot       bool        `json:"is_bot"`
+	Text        string      `json:"text,omitempty"`
+	ThreadTS    string      `json:"thread_ts,omitempty"`
+	MediaItems  []MediaItem `json:"media,omitempty"`
+	IsMention   bool        `json:"is_mention"`
+	IsDM        bool        `json:"is_dm"`
+}
+
+// MediaItem describes a single file shared in a Slack message.
+type MediaItem struct {
+	Type     MediaType `json:"type"`
+	FileID   string    `json:"file_id"`
+	URL      string    `json:"url"` // url_private_download, requires the bot token
+	FileName string    `json:"file_name"`
+	MimeType string    `json:"mime_type,omitempty"`
+	Size     int64     `json:"size,omitempty"`
+	Wrapper  string    `json:"wrapper,omitempty"`
+}
+
+// MediaType indicates the file type.
+type MediaType string
+
+const (
+	MediaImage    MediaType = "image"
+	MediaVideo    MediaType = "video"
+	MediaAudio    MediaType = "audio"
+	MediaDocument MediaType = "document"
+)
+
+// HasMedia returns true if the message contains media.
+func (cm *ConvertedMessage) HasMedia() bool { return len(cm.MediaItems) > 0 }
+
+// HasText returns true if the message contains text.
+func (cm *ConvertedMessage) HasText() bool { return cm.Text != "" }
+
+// ReplyThreadTS returns the ts replies are threaded under: the thread root
+// for messages already in a thread, the message itself otherwise.
+func (cm *ConvertedMessage) ReplyThreadTS() string {
+	if cm.ThreadTS != "" {
+		return cm.ThreadTS
+	}
+	return cm.TS
+}
+
+// ConvertEnvelope transforms an event_callback envelope into a ConvertedMessage.
+// Returns nil for events that are not user messages.
+func ConvertEnvelope(env *EventsAPIEnvelope) *ConvertedMessage {
+	if env == nil {
+		return nil
+	}
+	ev, err := env.MessageEvent()
+	if err != nil || ev == nil {
+		return nil
+	}
+	cm := ConvertMessageEvent(ev)
+	if cm == nil {
+		return nil
+	}
+	cm.EventID = env.EventID
+	if cm.TeamID == "" {
+		cm.TeamID = env.TeamID
+	}
+	return cm
+}
+
+// ConvertMessageEvent transforms a message or app_mention event into a ConvertedMessage.
+// Edits, deletions, joins and other system subtypes return nil.
+func ConvertMessageEvent(ev *MessageEvent) *ConvertedMessage {
+	if ev == nil || ev.TS == "" {
+		return nil
+	}
+	switch ev.Subtype {
+	case "", "file_share", "thread_broadcast", "bot_message":
+	default:
+		return nil
+	}
+
+	cm := &ConvertedMessage{
+		TS:          ev.TS,
+		Channel:     ev.Channel,
+		ChannelType: ev.ChannelType,
+		TeamID:      ev.Team,
+		UserID:      ev.User,
+		IsBot:       ev.BotID != "" || ev.Subtype == "bot_message",
+		Text:        PlainText(StripLeadingMention(ev.Text)),
+		ThreadTS:    ev.ThreadTS,
+		IsMention:   ev.Type == EventAppMention,
+		IsDM:        ev.ChannelType == "im" || strings.HasPrefix(ev.Channel, "D"),
+	}
+
+	for _, f := range ev.Files {
+		url := f.URLPrivateDownload
+		if url == "" {
+			url = f.URLPrivate
+		}
+		name := f.Name
+		if name == "" {
+			name = f.Title
+		}
+		cm.MediaItems = append(cm.MediaItems, MediaItem{
+			Type:     detectMediaType(f.Mimetype),
+			FileID:   f.ID,
+			URL:      url,
+			FileName: name,
+			MimeType: f.Mimetype,
+			Size:     f.Size,
+		})
+	}
+
+	return cm
+}
+
+var (
+	reLeadingMention = regexp.MustCompile(`^(\s*<@[A-Z0-9]+(\|[^>]*)?>\s*)+`)
+	reSlackLink      = regexp.MustCompile(`<([^<>|]+)(\|([^<>]*))?>`)
+)
+
+// StripLeadingMention removes the <@BOT> mention(s) a message starts with.
+func StripLeadingMention(text string) string {
+	return strings.TrimSpace(reLeadingMention.ReplaceAllString(text, ""))
+}
+
+// PlainText converts Slack's escaped text to plain text: links become
+// "label (url)", user and channel references keep their label, and the
+// &amp; &lt; &gt; entities are decoded.
+func PlainText(text string) string {
+	text = reSlackLink.ReplaceAllStringFunc(text, func(match string) string {
+		m := reSlackLink.FindStringSubmatch(match)
+		target, label := m[1], m[3]
+		switch {
+		case strings.HasPrefix(target, "@"), strings.HasPrefix(target, "#"):
+			if label != "" {
+				return string(target[0]) + label
+			}
+			return target
+		case strings.HasPrefix(target, "!"):
+			if label != "" {
+				return label
+			}
+			return "@" + strings.TrimPrefix(target, "!")
+		}
+		target = strings.TrimPrefix(target, "mailto:")
+		if label != "" && label != target {
+			return label + " (" + target + ")"
+		}
+		return target
+	})
+	text = strings.ReplaceAll(text, "&lt;", "<")
+	text = strings.ReplaceAll(text, "&gt;", ">")
+	text = strings.ReplaceAll(text, "&amp;", "&")
+	return text
+}
+
+func detectMediaType(mimeType string) MediaType {
+	lower := strings.ToLower(mimeType)
+	switch {
+	case strings.HasPrefix(lower, "image/"):
+		return MediaImage
+	case strings.HasPrefix(lower, "video/"):
+		return MediaVideo
+	case strings.HasPrefix(lower, "audio/"):
+		return MediaAudio
+	default:
+		return MediaDocument
+	}
+}
@@ -0,0 +1,106 @@
+package slack
+
+import (
+	"testing"
+)
+
+func TestConvertEnvelope_Mention(t *testing.T) {
+	env, err := ParseEventsAPI([]byte(`{
+		"type": "event_callback",
+		"team_id": "T001",
+		"event_id": "Ev001",
+		"event": {
+			"type": "app_mention",
+			"channel": "C001",
+			"user": "U001",
+			"text": "<@U0BOT> summarize <https://yaoapps.com|the site> &amp; reply",
+			"ts": "1700000000.000100"
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("ParseEventsAPI: %v", err)
+	}
+
+	cm := ConvertEnvelope(env)
+	if cm == nil {
+		t.Fatal("expected non-nil ConvertedMessage")
+	}
+	if cm.Text != "summarize the site (https://yaoapps.com) & reply" {
+		t.Errorf("unexpected text %q", cm.Text)
+	}
+	if cm.EventID != "Ev001" || cm.TeamID != "T001" || cm.UserID != "U001" {
+		t.Errorf("unexpected ids %+v", cm)
+	}
+	if !cm.IsMention || cm.IsDM || cm.IsBot {
+		t.Errorf("unexpected flags mention=%v dm=%v bot=%v", cm.IsMention, cm.IsDM, cm.IsBot)
+	}
+	if cm.ReplyThreadTS() != "1700000000.000100" {
+		t.Errorf("a new message should start a thread on itself, got %q", cm.ReplyThreadTS())
+	}
+}
+
+func TestConvertMessageEvent_ThreadDMWithFile(t *testing.T) {
+	cm := ConvertMessageEvent(&MessageEvent{
+		Type:        EventMessage,
+		Subtype:     "file_share",
+		Channel:     "D001",
+		ChannelType: "im",
+		User:        "U001",
+		TS:          "1700000000.000200",
+		ThreadTS:    "1700000000.000100",
+		Files: []File{
+			{ID: "F001", Name: "chart.png", Mimetype: "image/png", URLPrivate: "https://files.slack.com/a", URLPrivateDownload: "https://files.slack.com/a/download"},
+			{ID: "F002", Title: "report", Mimetype: "application/pdf", URLPrivate: "https://files.slack.com/b"},
+		},
+	})
+	if cm == nil {
+		t.Fatal("expected non-nil ConvertedMessage")
+	}
+	if !cm.IsDM {
+		t.Error("expected IsDM=true")
+	}
+	if cm.HasText() || !cm.HasMedia() {
+		t.Errorf("expected media only, got text=%q media=%d", cm.Text, len(cm.MediaItems))
+	}
+	if cm.ReplyThreadTS() != "1700000000.000100" {
+		t.Errorf("replies should stay in the thread, got %q", cm.ReplyThreadTS())
+	}
+	if cm.MediaItems[0].Type != MediaImage || cm.MediaItems[0].URL != "https://files.slack.com/a/download" {
+		t.Errorf("unexpected first media %+v", cm.MediaItems[0])
+	}
+	if cm.MediaItems[1].Type != MediaDocument || cm.MediaItems[1].FileName != "report" || cm.MediaItems[1].URL != "https://files.slack.com/b" {
+		t.Errorf("unexpected second media %+v", cm.MediaItems[1])
+	}
+}
+
+func TestConvertMessageEvent_Skipped(t *testing.T) {
+	for _, subtype := range []string{"message_changed", "message_deleted", "channel_join"} {
+		if cm := ConvertMessageEvent(&MessageEvent{Type: EventMessage, Subtype: subtype, TS: "1.0"}); cm != nil {
+			t.Errorf("subtype %s should be skipped", subtype)
+		}
+	}
+
+	cm := ConvertMessageEvent(&MessageEvent{Type: EventMessage, BotID: "B001", Text: "echo", TS: "1.0"})
+	if cm == nil || !cm.IsBot {
+		t.Error("bot messages should be flagged IsBot")
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{"hi <@U001|alice>", "hi @alice"},
+		{"see <#C001|general>", "see #general"},
+		{"<!here> ping", "@here ping"},
+		{"<https://a.com>", "https://a.com"},
+		{"<mailto:a@b.com|a@b.com>", "a@b.com"},
+		{"a &lt;b&gt;", "a <b>"},
+	}
+	for _, tc := range cases {
+		if got := PlainText(tc.input); got != tc.want {
+			t.Errorf("PlainText(%q) = %q, want %q", tc.input, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/yao/attachment"
+)
+
+const defaultUploader = "__yao.attachment"
+
+// FileResult holds the attachment wrapper and metadata for a downloaded
+// Slack file that has been stored via the attachment manager.
+type FileResult struct {
+	Wrapper  string // e.g. __yao.attachment://ccd472d11feb96e03a3fc468f494045c
+	MimeType string
+	FileName string
+}
+
+// DownloadFile downloads a private Slack file (url_private / url_private_download).
+// Caller must close the returned ReadCloser.
+func (b *Bot) DownloadFile(ctx context.Context, fileURL string) (io.ReadCloser, string, int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return nil, "", 0, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+b.token)
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, "", 0, fmt.Errorf("download file: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, "", 0, fmt.Errorf("download file: status %d", resp.StatusCode)
+	}
+
+	// Slack answers with its login page instead of the file when the token lacks files:read
+	contentType := resp.Header.Get("Content-Type")
+	if strings.HasPrefix(contentType, "text/html") {
+		resp.Body.Close()
+		return nil, "", 0, fmt.Errorf("download file: got an HTML page, check the files:read scope")
+	}
+	return resp.Body, contentType, resp.ContentLength, nil
+}
+
+// DownloadAndStore downloads a Slack file, stores it through the attachment
+// manager and returns the wrapper string. The Slack file ID is used as the
+// Content-Fingerprint so the same file is never downloaded and stored twice.
+func (b *Bot) DownloadAndStore(ctx context.Context, mi *MediaItem, groups []string) (*FileResult, error) {
+	manager, exists := attachment.Managers[defaultUploader]
+	if !exists {
+		return nil, fmt.Errorf("attachment manager %s not found", defaultUploader)
+	}
+
+	mimeType := mi.MimeType
+	filename := mi.FileName
+	if filename == "" {
+		filename = mi.FileID
+	}
+
+	probeID := fingerprintKey(mi.FileID, groups)
+	if manager.Exists(ctx, probeID) {
+		wrapper := fmt.Sprintf("%s://%s", defaultUploader, probeID)
+		log.Trace("slack file: cache hit file_id=%s wrapper=%s", mi.FileID, wrapper)
+		return &FileResult{Wrapper: wrapper, MimeType: mimeType, FileName: filename}, nil
+	}
+
+	body, contentType, size, err := b.DownloadFile(ctx, mi.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if mimeType == "" {
+		mimeType = contentType
+	}
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+	if size <= 0 {
+		size = int64(len(data))
+	}
+
+	header := &attachment.FileHeader{
+		FileHeader: &multipart.FileHeader{
+			Filename: filename,
+			Size:     size,
+			Header:   make(textproto.MIMEHeader),
+		},
+	}
+	header.Header.Set("Content-Type", mimeType)
+	header.Header.Set("Content-Fingerprint", mi.FileID)
+	if ext := filepath.Ext(filename); ext != "" {
+		header.Header.Set("Content-Extension", ext)
+	}
+
+	option := attachment.UploadOption{
+		OriginalFilename: filename,
+		Groups:           groups,
+	}
+
+	uploaded, err := manager.Upload(ctx, header, bytes.NewReader(data), option)
+	if err != nil {
+		return nil, fmt.Errorf("attachment upload: %w", err)
+	}
+
+	wrapper := fmt.Sprintf("%s://%s", defaultUploader, uploaded.ID)
+	return &FileResult{Wrapper: wrapper, MimeType: mimeType, FileName: filename}, nil
+}
+
+// ResolveMedia downloads and stores all media items in the ConvertedMessage,
+// filling each MediaItem.Wrapper with the attachment wrapper string.
+// Items that fail to download are logged and left with an empty Wrapper.
+func (b *Bot) ResolveMedia(ctx context.Context, cm *ConvertedMessage, groups []string) {
+	if cm == nil {
+		return
+	}
+	for i := range cm.MediaItems {
+		mi := &cm.MediaItems[i]
+		if mi.URL == "" {
+			continue
+		}
+		result, err := b.DownloadAndStore(ctx, mi, groups)
+		if err != nil {
+			log.Error("slack ResolveMedia: %s %s: %v", mi.Type, mi.FileID, err)
+			continue
+		}
+		mi.Wrapper = result.Wrapper
+		if result.MimeType != "" {
+			mi.MimeType = result.MimeType
+		}
+	}
+}
+
+// UploadFile uploads a file to a channel with the external upload flow:
+// files.getUploadURLExternal, the upload itself, then files.completeUploadExternal.
+// When threadTS is set the file is shared in that thread.
+func (b *Bot) UploadFile(ctx context.Context, channel, threadTS, filename string, data []byte, comment string) error {
+	params := url.Values{}
+	params.Set("filename", filename)
+	params.Set("length", strconv.Itoa(len(data)))
+
+	var ticket struct {
+		UploadURL string `json:"upload_url"`
+		FileID    string `json:"file_id"`
+	}
+	if err := b.call(ctx, b.token, "files.getUploadURLExternal", params, &ticket); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ticket.UploadURL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("create upload request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("upload file: %w", err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("upload file: status %d", resp.StatusCode)
+	}
+
+	files, err := json.Marshal([]map[string]string{{"id": ticket.FileID, "title": filename}})
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+	params = url.Values{}
+	params.Set("files", string(files))
+	params.Set("channel_id", channel)
+	if threadTS != "" {
+		params.Set("thread_ts", threadTS)
+	}
+	if comment != "" {
+		params.Set("initial_comment", FormatSlackMrkdwn(comment))
+	}
+	return b.call(ctx, b.token, "files.completeUploadExternal", params, nil)
+}
+
+// SendMedia uploads a file from a Yao attachment wrapper
+// (e.g. "__yao.attachment://ccd472d11feb96e03a3fc468f494045c") to a channel.
+func (b *Bot) SendMedia(ctx context.Context, channel, threadTS, wrapper, caption string) error {
+	managerName, fileID, err := parseWrapper(wrapper)
+	if err != nil {
+		return err
+	}
+
+	manager, exists := attachment.Managers[managerName]
+	if !exists {
+		return fmt.Errorf("attachment manager %s not found", managerName)
+	}
+
+	resp, err := manager.Download(ctx, fileID)
+	if err != nil {
+		return fmt.Errorf("attachment download %s: %w", fileID, err)
+	}
+	defer resp.Reader.Close()
+
+	data, err := io.ReadAll(resp.Reader)
+	if err != nil {
+		return fmt.Errorf("attachment read %s: %w", fileID, err)
+	}
+
+	filename := caption
+	if filename == "" || filepath.Ext(filename) == "" {
+		filename = fileID + resp.Extension
+	}
+	return b.UploadFile(ctx, channel, threadTS, filename, data, "")
+}
+
+// parseWrapper splits "__yao.attachment://fileID" into manager name and file ID.
+func parseWrapper(wrapper string) (managerName string, fileID string, err error) {
+	idx := strings.Index(wrapper, "://")
+	if idx < 0 {
+		return "", "", fmt.Errorf("invalid attachment wrapper: %s", wrapper)
+	}
+	return wrapper[:idx], wrapper[idx+3:], nil
+}
+
+// fingerprintKey reproduces the file_id that attachment.Manager would
+// generate when Content-Fingerprint is set, so we can probe Exists() before
+// downloading anything.
+func fingerprintKey(key string, groups []string) string {
+	parts := make([]string, 0, len(groups)+1)
+	parts = append(parts, groups...)
+	parts = append(parts, key)
+	storagePath := strings.Join(parts, "/")
+	hash := md5.Sum([]byte(storagePath))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,229 @@
+package slack
+
+import (
+	"regexp"
+	"strings"
+)
+
+// FormatSlackMrkdwn converts standard Markdown to Slack's mrkdwn.
+//
+// Conversions:
+//   - **bold** / __bold__  → *bold*
+//   - *italic*             → _italic_
+//   - ~~strike~~           → ~strike~
+//   - [text](url)          → <url|text>
+//   - ![alt](url)          → <url|alt>
+//   - # heading            → *heading*
+//   - - item               → • item
+//   - tables               → pre-formatted code block
+//
+// Code spans and blocks are kept as is (the language hint is dropped), and
+// &, < and > are escaped everywhere as Slack requires.
+func FormatSlackMrkdwn(md string) string {
+	md = strings.ReplaceAll(md, "\r\n", "\n")
+
+	var out strings.Builder
+	lines := strings.Split(md, "\n")
+
+	inCodeBlock := false
+	inTable := false
+	var tableRows [][]string
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+			out.WriteString("```\n")
+			continue
+		}
+		if inCodeBlock {
+			out.WriteString(escapeMrkdwn(line) + "\n")
+			continue
+		}
+
+		if isTableRow(line) {
+			if !inTable {
+				inTable = true
+				tableRows = nil
+			}
+			if isTableSeparator(line) {
+				continue
+			}
+			tableRows = append(tableRows, parseTableRow(line))
+			continue
+		}
+		if inTable {
+			flushTable(&out, tableRows)
+			inTable = false
+			tableRows = nil
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "---" || trimmed == "***" || trimmed == "___" {
+			out.WriteString("——————\n")
+			continue
+		}
+
+		if m := reHeading.FindStringSubmatch(line); m != nil {
+			out.WriteString("*" + formatInline(strings.Trim(m[2], "*_ ")) + "*\n")
+			continue
+		}
+
+		if m := reBlockquote.FindStringSubmatch(line); m != nil {
+			out.WriteString("> " + formatInline(m[1]) + "\n")
+			continue
+		}
+
+		if m := reUnorderedList.FindStringSubmatch(line); m != nil {
+			out.WriteString(m[1] + "• " + formatInline(m[2]) + "\n")
+			continue
+		}
+
+		out.WriteString(formatInline(line) + "\n")
+	}
+
+	if inCodeBlock {
+		out.WriteString("```\n")
+	}
+	if inTable {
+		flushTable(&out, tableRows)
+	}
+
+	return strings.TrimRight(out.String(), "\n")
+}
+
+var (
+	reHeading       = regexp.MustCompile(`^(#{1,6})\s+(.+)$`)
+	reBlockquote    = regexp.MustCompile(`^>\s?(.*)$`)
+	reUnorderedList = regexp.MustCompile(`^(\s*)[-*+]\s+(.+)$`)
+
+	reImage         = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+	reLink          = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	reBold          = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	reBoldAlt       = regexp.MustCompile(`__(.+?)__`)
+	reItalic        = regexp.MustCompile(`\*([^*\s][^*]*?)\*`)
+	reStrikethrough = regexp.MustCompile(`~~(.+?)~~`)
+
+	reTableRow = regexp.MustCompile(`^\|.*\|$`)
+	reTableSep = regexp.MustCompile(`^\|[\s\-:|]+\|$`)
+)
+
+// boldMark stands in for bold markers while italics are converted.
+const boldMark = "\x00"
+
+// formatInline converts inline Markdown, leaving `code` spans untouched.
+func formatInline(line string) string {
+	segments := strings.Split(line, "`")
+	if len(segments)%2 == 0 {
+		// Unbalanced backticks, treat the whole line as text
+		return formatText(line)
+	}
+
+	var out strings.Builder
+	for i, seg := range segments {
+		if i%2 == 1 {
+			out.WriteString("`" + escapeMrkdwn(seg) + "`")
+			continue
+		}
+		out.WriteString(formatText(seg))
+	}
+	return out.String()
+}
+
+// formatText converts the inline Markdown of text outside code spans.
+// Order matters: escape first, then links (which add < >), bold before italic.
+func formatText(text string) string {
+	text = escapeMrkdwn(text)
+
+	text = reImage.ReplaceAllStringFunc(text, func(match string) string {
+		m := reImage.FindStringSubmatch(match)
+		if m[1] == "" {
+			return "<" + m[2] + ">"
+		}
+		return "<" + m[2] + "|" + m[1] + ">"
+	})
+	text = reLink.ReplaceAllString(text, "<$2|$1>")
+
+	text = reBold.ReplaceAllString(text, boldMark+"$1"+boldMark)
+	text = reBoldAlt.ReplaceAllString(text, boldMark+"$1"+boldMark)
+	text = reItalic.ReplaceAllString(text, "_${1}_")
+	text = strings.ReplaceAll(text, boldMark, "*")
+	text = reStrikethrough.ReplaceAllString(text, "~$1~")
+
+	return text
+}
+
+// escapeMrkdwn escapes the three characters Slack uses as control sequences.
+func escapeMrkdwn(s string) string {
+	s = strings.ReplaceAll(s, "&", "&amp;")
+	s = strings.ReplaceAll(s, "<", "&lt;")
+	s = strings.ReplaceAll(s, ">", "&gt;")
+	return s
+}
+
+func isTableRow(line string) bool {
+	return reTableRow.MatchString(strings.TrimSpace(line))
+}
+
+func isTableSeparator(line string) bool {
+	return reTableSep.MatchString(strings.TrimSpace(line))
+}
+
+func parseTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	line = strings.TrimSuffix(line, "|")
+	cells := strings.Split(line, "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}
+
+// flushTable renders table rows as an aligned code block.
+func flushTable(out *strings.Builder, rows [][]string) {
+	if len(rows) == 0 {
+		return
+	}
+	colWidths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			if i < len(colWidths) && len([]rune(cell)) > colWidths[i] {
+				colWidths[i] = len([]rune(cell))
+			}
+		}
+	}
+
+	out.WriteString("```\n")
+	for ri, row := range rows {
+		for ci, cell := range row {
+			if ci > 0 {
+				out.WriteString(" | ")
+			}
+			if ci < len(colWidths) {
+				cell = padRight(cell, colWidths[ci])
+			}
+			out.WriteString(escapeMrkdwn(cell))
+		}
+		out.WriteString("\n")
+		if ri == 0 && len(rows) > 1 {
+			for ci, w := range colWidths {
+				if ci > 0 {
+					out.WriteString("-+-")
+				}
+				out.WriteString(strings.Repeat("-", w))
+			}
+			out.WriteString("\n")
+		}
+	}
+	out.WriteString("```\n")
+}
+
+func padRight(s string, width int) string {
+	n := len([]rune(s))
+	if n >= width {
+		return s
+	}
+	return s + strings.Repeat(" ", width-n)
+}
@@ -0,0 +1,51 @@
+package slack
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatSlackMrkdwn_Inline(t *testing.T) {
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{"**bold** and __bold__", "*bold* and *bold*"},
+		{"*italic* text", "_italic_ text"},
+		{"**bold** and *italic*", "*bold* and _italic_"},
+		{"~~gone~~", "~gone~"},
+		{"see [docs](https://yaoapps.com/docs)", "see <https://yaoapps.com/docs|docs>"},
+		{"![chart](https://example.com/a.png)", "<https://example.com/a.png|chart>"},
+		{"a < b && c > d", "a &lt; b &amp;&amp; c &gt; d"},
+		{"run `a **b** <c>` now", "run `a **b** &lt;c&gt;` now"},
+		{"2 * 3 * 4", "2 * 3 * 4"},
+	}
+	for _, tc := range cases {
+		if got := FormatSlackMrkdwn(tc.input); got != tc.want {
+			t.Errorf("FormatSlackMrkdwn(%q) = %q, want %q", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestFormatSlackMrkdwn_Blocks(t *testing.T) {
+	md := "# Title\n- one\n- two\n> quoted\n---\n```go\nif a < b {}\n```"
+	got := FormatSlackMrkdwn(md)
+	want := "*Title*\n• one\n• two\n> quoted\n——————\n```\nif a &lt; b {}\n```"
+	if got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFormatSlackMrkdwn_Table(t *testing.T) {
+	md := "| Name | Score |\n|------|-------|\n| Alice | 95 |\n| Bob | 87 |"
+	got := FormatSlackMrkdwn(md)
+	if !strings.HasPrefix(got, "```\n") || !strings.HasSuffix(got, "```") {
+		t.Fatalf("table should be rendered as a code block, got:\n%s", got)
+	}
+	if !strings.Contains(got, "Alice | 95") {
+		t.Errorf("table cells missing, got:\n%s", got)
+	}
+	if strings.Contains(got, "|---") {
+		t.Errorf("markdown separator should be dropped, got:\n%s", got)
+	}
+}
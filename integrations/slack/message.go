@@ -0,0 +1,63 @@
+package slack
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxMessageLength keeps each chat.postMessage well below Slack's 4000
+// character guideline for the text field.
+const maxMessageLength = 3500
+
+// SendMessage posts a message to a channel. Markdown is converted to mrkdwn and
+// long texts are split into several messages. When threadTS is set the
+// message is posted as a reply in that thread.
+// Returns the ts of the last message posted.
+func (b *Bot) SendMessage(ctx context.Context, channel, text, threadTS string) (string, error) {
+	var ts string
+	for _, chunk := range splitText(FormatSlackMrkdwn(text), maxMessageLength) {
+		params := url.Values{}
+		params.Set("channel", channel)
+		params.Set("text", chunk)
+		params.Set("mrkdwn", "true")
+		if threadTS != "" {
+			params.Set("thread_ts", threadTS)
+		}
+
+		var result struct {
+			TS string `json:"ts"`
+		}
+		if err := b.call(ctx, b.token, "chat.postMessage", params, &result); err != nil {
+			return ts, err
+		}
+		ts = result.TS
+	}
+	return ts, nil
+}
+
+// splitText splits text into chunks of at most limit bytes, preferring line
+// breaks and never cutting a UTF-8 character in half.
+func splitText(text string, limit int) []string {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+
+	var chunks []string
+	for len(text) > limit {
+		cut := strings.LastIndex(text[:limit], "\n")
+		if cut <= 0 {
+			cut = limit
+			for cut > 0 && !utf8.RuneStart(text[cut]) {
+				cut--
+			}
+		}
+		chunks = append(chunks, text[:cut])
+		text = strings.TrimLeft(text[cut:], "\n")
+	}
+	if text != "" {
+		chunks = append(chunks, text)
+	}
+	return chunks
+}
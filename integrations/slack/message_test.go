@@ -0,0 +1,90 @@
+package slack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSendMessage(t *testing.T) {
+	var posted []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/chat.postMessage" {
+			t.Errorf("unexpected method %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer xoxb-test" {
+			t.Errorf("unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		r.ParseForm()
+		if r.Form.Get("thread_ts") != "1.0" {
+			t.Errorf("expected thread_ts=1.0, got %q", r.Form.Get("thread_ts"))
+		}
+		posted = append(posted, r.Form.Get("text"))
+		w.Write([]byte(`{"ok":true,"ts":"2.0"}`))
+	}))
+	defer srv.Close()
+
+	b := NewBot("xoxb-test", "", "", WithAPIBase(srv.URL))
+	ts, err := b.SendMessage(context.Background(), "C001", "**hello**", "1.0")
+	if err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+	if ts != "2.0" {
+		t.Errorf("expected ts 2.0, got %s", ts)
+	}
+	if len(posted) != 1 || posted[0] != "*hello*" {
+		t.Errorf("unexpected posted text %v", posted)
+	}
+}
+
+func TestSendMessage_APIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+	}))
+	defer srv.Close()
+
+	b := NewBot("xoxb-test", "", "", WithAPIBase(srv.URL))
+	_, err := b.SendMessage(context.Background(), "C404", "hi", "")
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if apiErr.Code != "channel_not_found" {
+		t.Errorf("unexpected code %s", apiErr.Code)
+	}
+}
+
+func TestSplitText(t *testing.T) {
+	if chunks := splitText("   ", 10); chunks != nil {
+		t.Errorf("blank text should give no chunks, got %v", chunks)
+	}
+
+	chunks := splitText("line one\nline two\nline three", 18)
+	if len(chunks) != 2 || chunks[0] != "line one\nline two" || chunks[1] != "line three" {
+		t.Errorf("should split on line breaks, got %q", chunks)
+	}
+
+	long := strings.Repeat("你好", 10)
+	chunks = splitText(long, 7)
+	for _, chunk := range chunks {
+		if len(chunk) > 7 || !utf8.ValidString(chunk) {
+			t.Errorf("chunk %q cuts a character", chunk)
+		}
+	}
+	if strings.Join(chunks, "") != long {
+		t.Errorf("chunks should add up to the text, got %q", chunks)
+	}
+}
+
+func TestParseWrapper(t *testing.T) {
+	manager, fileID, err := parseWrapper("__yao.attachment://abc123")
+	if err != nil || manager != "__yao.attachment" || fileID != "abc123" {
+		t.Errorf("parseWrapper = (%q, %q, %v)", manager, fileID, err)
+	}
+	if _, _, err := parseWrapper("no-separator"); err == nil {
+		t.Error("expected error for a wrapper without separator")
+	}
+}
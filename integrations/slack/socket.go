@@ -0,0 +1,99 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gorilla/websocket"
+	"github.com/yaoapp/kun/log"
+)
+
+// Socket Mode envelope types.
+const (
+	SocketHello      = "hello"
+	SocketEventsAPI  = "events_api"
+	SocketDisconnect = "disconnect"
+)
+
+// SocketEnvelope is a message received over a Socket Mode connection.
+type SocketEnvelope struct {
+	EnvelopeID string          `json:"envelope_id,omitempty"`
+	Type       string          `json:"type"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+	Reason     string          `json:"reason,omitempty"` // disconnect reason, e.g. "refresh_requested"
+}
+
+// OpenConnection calls apps.connections.open with the app-level token and
+// returns the WebSocket URL of a new Socket Mode connection.
+func (b *Bot) OpenConnection(ctx context.Context) (string, error) {
+	var result struct {
+		URL string `json:"url"`
+	}
+	if err := b.call(ctx, b.appToken, "apps.connections.open", nil, &result); err != nil {
+		return "", err
+	}
+	return result.URL, nil
+}
+
+// RunSocketMode opens a Socket Mode connection and calls handler for every
+// Events API envelope, acknowledging each one before handling it.
+// It blocks until ctx is cancelled (returns nil) or the connection ends
+// (returns the reason); callers reconnect by calling it again.
+func (b *Bot) RunSocketMode(ctx context.Context, handler func(*EventsAPIEnvelope)) error {
+	wsURL, err := b.OpenConnection(ctx)
+	if err != nil {
+		return fmt.Errorf("apps.connections.open: %w", err)
+	}
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, wsURL, nil)
+	if err != nil {
+		return fmt.Errorf("dial socket mode: %w", err)
+	}
+	defer conn.Close()
+
+	// Unblock ReadJSON when the context ends
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		var env SocketEnvelope
+		if err := conn.ReadJSON(&env); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("read socket mode: %w", err)
+		}
+
+		// Slack redelivers envelopes that are not acknowledged within 3 seconds
+		if env.EnvelopeID != "" {
+			ack := map[string]string{"envelope_id": env.EnvelopeID}
+			if err := conn.WriteJSON(ack); err != nil {
+				return fmt.Errorf("ack socket mode: %w", err)
+			}
+		}
+
+		switch env.Type {
+		case SocketHello:
+			log.Trace("slack socket mode: connected")
+
+		case SocketDisconnect:
+			return fmt.Errorf("socket mode disconnect: %s", env.Reason)
+
+		case SocketEventsAPI:
+			var event EventsAPIEnvelope
+			if err := json.Unmarshal(env.Payload, &event); err != nil {
+				log.Error("slack socket mode: invalid events_api payload: %v", err)
+				continue
+			}
+			handler(&event)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package slack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestRunSocketMode(t *testing.T) {
+	acks := make(chan string, 4)
+	upgrader := websocket.Upgrader{}
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	mux.HandleFunc("/apps.connections.open", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer xapp-test" {
+			t.Errorf("apps.connections.open must use the app token, got %q", r.Header.Get("Authorization"))
+		}
+		w.Write([]byte(`{"ok":true,"url":"ws` + strings.TrimPrefix(srv.URL, "http") + `/socket"}`))
+	})
+	mux.HandleFunc("/socket", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		conn.WriteJSON(map[string]any{"type": "hello"})
+		conn.WriteJSON(map[string]any{
+			"envelope_id": "env-1",
+			"type":        "events_api",
+			"payload": map[string]any{
+				"type":     "event_callback",
+				"event_id": "Ev001",
+				"event":    map[string]any{"type": "message", "channel": "D001", "user": "U001", "text": "hi", "ts": "1.0"},
+			},
+		})
+
+		var ack map[string]string
+		if err := conn.ReadJSON(&ack); err == nil {
+			acks <- ack["envelope_id"]
+		}
+		conn.WriteJSON(map[string]any{"type": "disconnect", "reason": "refresh_requested"})
+		conn.ReadMessage()
+	})
+
+	b := NewBot("xoxb-test", "xapp-test", "", WithAPIBase(srv.URL))
+
+	var received []*ConvertedMessage
+	err := b.RunSocketMode(context.Background(), func(env *EventsAPIEnvelope) {
+		received = append(received, ConvertEnvelope(env))
+	})
+	if err == nil || !strings.Contains(err.Error(), "refresh_requested") {
+		t.Fatalf("expected a disconnect error, got %v", err)
+	}
+
+	if got := <-acks; got != "env-1" {
+		t.Errorf("expected ack for env-1, got %q", got)
+	}
+	if len(received) != 1 || received[0] == nil || received[0].Text != "hi" || received[0].EventID != "Ev001" {
+		t.Fatalf("unexpected events %+v", received)
+	}
+}
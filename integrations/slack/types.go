@@ -0,0 +1,70 @@
+package slack
+
+import "encoding/json"
+
+// Events API envelope types.
+const (
+	EnvelopeURLVerification = "url_verification"
+	EnvelopeEventCallback   = "event_callback"
+)
+
+// Event types handled by the robot adapter.
+const (
+	EventMessage    = "message"
+	EventAppMention = "app_mention"
+)
+
+// EventsAPIEnvelope is the outer payload of an Events API request.
+// Socket Mode delivers the same envelope inside its events_api payload.
+type EventsAPIEnvelope struct {
+	Type      string          `json:"type"` // url_verification | event_callback
+	Challenge string          `json:"challenge,omitempty"`
+	TeamID    string          `json:"team_id,omitempty"`
+	APIAppID  string          `json:"api_app_id,omitempty"`
+	EventID   string          `json:"event_id,omitempty"`
+	EventTime int64           `json:"event_time,omitempty"`
+	Event     json.RawMessage `json:"event,omitempty"`
+}
+
+// MessageEvent is a message or app_mention event.
+type MessageEvent struct {
+	Type        string `json:"type"`
+	Subtype     string `json:"subtype,omitempty"`
+	Channel     string `json:"channel"`
+	ChannelType string `json:"channel_type,omitempty"` // im | mpim | channel | group
+	User        string `json:"user,omitempty"`
+	BotID       string `json:"bot_id,omitempty"`
+	Text        string `json:"text,omitempty"`
+	TS          string `json:"ts"`
+	ThreadTS    string `json:"thread_ts,omitempty"`
+	Team        string `json:"team,omitempty"`
+	Files       []File `json:"files,omitempty"`
+}
+
+// File is a file shared in a message.
+type File struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name,omitempty"`
+	Title              string `json:"title,omitempty"`
+	Mimetype           string `json:"mimetype,omitempty"`
+	Filetype           string `json:"filetype,omitempty"`
+	Size               int64  `json:"size,omitempty"`
+	URLPrivate         string `json:"url_private,omitempty"`
+	URLPrivateDownload string `json:"url_private_download,omitempty"`
+}
+
+// MessageEvent decodes the inner event of an event_callback envelope.
+// Returns nil for other event types.
+func (e *EventsAPIEnvelope) MessageEvent() (*MessageEvent, error) {
+	if e.Type != EnvelopeEventCallback || len(e.Event) == 0 {
+		return nil, nil
+	}
+	var ev MessageEvent
+	if err := json.Unmarshal(e.Event, &ev); err != nil {
+		return nil, err
+	}
+	if ev.Type != EventMessage && ev.Type != EventAppMention {
+		return nil, nil
+	}
+	return &ev, nil
+}
@@ -0,0 +1,54 @@
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// signatureMaxAge is how old an Events API request may be, older requests are replays.
+const signatureMaxAge = 5 * time.Minute
+
+// VerifySignature checks the X-Slack-Signature header of an Events API request
+// against the bot's signing secret using constant-time comparison.
+// Requests are rejected when no signing secret is configured.
+func (b *Bot) VerifySignature(timestamp, signature string, body []byte) bool {
+	return verifySignature(b.signingSecret, timestamp, signature, body, time.Now())
+}
+
+func verifySignature(secret, timestamp, signature string, body []byte, now time.Time) bool {
+	if secret == "" || timestamp == "" || signature == "" {
+		return false
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	age := now.Sub(time.Unix(ts, 0))
+	if age > signatureMaxAge || age < -signatureMaxAge {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// ParseEventsAPI decodes the body of an Events API request.
+func ParseEventsAPI(body []byte) (*EventsAPIEnvelope, error) {
+	var env EventsAPIEnvelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, fmt.Errorf("unmarshal events api payload: %w", err)
+	}
+	if env.Type == "" {
+		return nil, fmt.Errorf("events api payload has no type")
+	}
+	return &env, nil
+}
@@ -0,0 +1,73 @@
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"type":"event_callback"}`)
+	now := time.Now()
+	ts := strconv.FormatInt(now.Unix(), 10)
+	sig := sign("s3cr3t", ts, body)
+
+	b := NewBot("xoxb-test", "", "s3cr3t")
+	if !b.VerifySignature(ts, sig, body) {
+		t.Fatal("should pass with a valid signature")
+	}
+	if b.VerifySignature(ts, sig, []byte(`{"type":"tampered"}`)) {
+		t.Fatal("should reject a tampered body")
+	}
+	if b.VerifySignature(ts, sign("wrong", ts, body), body) {
+		t.Fatal("should reject a signature made with another secret")
+	}
+	if b.VerifySignature("", sig, body) || b.VerifySignature(ts, "", body) {
+		t.Fatal("should reject missing headers")
+	}
+}
+
+func TestVerifySignature_Replay(t *testing.T) {
+	body := []byte(`{}`)
+	old := time.Now().Add(-10 * time.Minute)
+	ts := strconv.FormatInt(old.Unix(), 10)
+	if verifySignature("s3cr3t", ts, sign("s3cr3t", ts, body), body, time.Now()) {
+		t.Fatal("should reject a request older than five minutes")
+	}
+}
+
+func TestVerifySignature_NoSecret(t *testing.T) {
+	body := []byte(`{}`)
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	b := NewBot("xoxb-test", "", "")
+	if b.VerifySignature(ts, sign("", ts, body), body) {
+		t.Fatal("should reject requests when no signing secret is configured")
+	}
+}
+
+func TestParseEventsAPI(t *testing.T) {
+	env, err := ParseEventsAPI([]byte(`{"type":"url_verification","challenge":"abc"}`))
+	if err != nil {
+		t.Fatalf("ParseEventsAPI: %v", err)
+	}
+	if env.Type != EnvelopeURLVerification || env.Challenge != "abc" {
+		t.Fatalf("unexpected envelope %+v", env)
+	}
+
+	if _, err := ParseEventsAPI([]byte(`{}`)); err == nil {
+		t.Fatal("expected error for a payload without type")
+	}
+	if _, err := ParseEventsAPI([]byte(`not json`)); err == nil {
+		t.Fatal("expected error for invalid JSON")
+	}
+}
@@ -0,0 +1,442 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/robfig/cron/v3"
+	"github.com/yaoapp/kun/log"
+)
+
+// MissedRunPolicy defines what happens to the cron ticks missed while the server was down
+type MissedRunPolicy string
+
+// MissedRunPolicy constants
+const (
+	MissedRunSkip    MissedRunPolicy = "skip"    // Ignore missed ticks, wait for the next one (default)
+	MissedRunCatchUp MissedRunPolicy = "catchup" // Run the missed ticks one after another
+)
+
+// DefaultCronMaxCatchUp the default maximum number of missed ticks to run
+const DefaultCronMaxCatchUp = 10
+
+// cronPollInterval how often pending catch-up runs check if the previous run is finished
+var cronPollInterval = time.Second
+
+// cronParser parses standard cron expressions, the seconds field is optional
+// Descriptors (@daily, @every 1h) and the CRON_TZ= prefix are supported
+var cronParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// CronOptions holds the cron scheduling options, stored in the job config under "cron"
+type CronOptions struct {
+	Timezone        string          `json:"timezone,omitempty"`          // IANA timezone, e.g. "Asia/Shanghai", default: local
+	MissedRunPolicy MissedRunPolicy `json:"missed_run_policy,omitempty"` // skip | catchup, default: skip
+	MaxCatchUp      int             `json:"max_catch_up,omitempty"`      // Maximum number of missed ticks to run, default: 10
+}
+
+// cronScheduler fires cron jobs on their schedule
+type cronScheduler struct {
+	entries map[string]*cronEntry // jobID -> entry
+	mu      sync.Mutex
+}
+
+// cronEntry is a scheduled cron job
+type cronEntry struct {
+	job      *Job
+	schedule cron.Schedule
+	options  *CronOptions
+	next     time.Time
+	pending  []time.Time // Missed ticks waiting to run (catch-up)
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+// Global cron scheduler instance
+var globalCronScheduler *cronScheduler
+var cronSchedulerOnce sync.Once
+
+// getCronScheduler returns the global cron scheduler instance
+func getCronScheduler() *cronScheduler {
+	cronSchedulerOnce.Do(func() {
+		globalCronScheduler = &cronScheduler{entries: make(map[string]*cronEntry)}
+	})
+	return globalCronScheduler
+}
+
+// ParseCronExpression parses a cron expression in the given timezone
+func ParseCronExpression(expression string, timezone string) (cron.Schedule, error) {
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return nil, fmt.Errorf("cron expression is required")
+	}
+
+	// An explicit CRON_TZ= or TZ= prefix takes precedence over the timezone option
+	if timezone != "" && !strings.HasPrefix(expression, "CRON_TZ=") && !strings.HasPrefix(expression, "TZ=") {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return nil, fmt.Errorf("invalid timezone %s: %w", timezone, err)
+		}
+		expression = fmt.Sprintf("CRON_TZ=%s %s", timezone, expression)
+	}
+
+	schedule, err := cronParser.Parse(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression %s: %w", expression, err)
+	}
+	return schedule, nil
+}
+
+// NextRunTime returns the next time a cron expression fires after the given time
+func NextRunTime(expression string, timezone string, from time.Time) (time.Time, error) {
+	schedule, err := ParseCronExpression(expression, timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.Next(from), nil
+}
+
+// CronOptions returns the cron options of the job
+func (j *Job) CronOptions() *CronOptions {
+	options := &CronOptions{}
+	if raw, ok := j.Config["cron"]; ok && raw != nil {
+		data, err := jsoniter.Marshal(raw)
+		if err == nil {
+			jsoniter.Unmarshal(data, options)
+		}
+	}
+
+	if options.MissedRunPolicy == "" {
+		options.MissedRunPolicy = MissedRunSkip
+	}
+	if options.MaxCatchUp <= 0 {
+		options.MaxCatchUp = DefaultCronMaxCatchUp
+	}
+	return options
+}
+
+// SetCronOptions set the cron options of the job
+func (j *Job) SetCronOptions(options *CronOptions) *Job {
+	if j.Config == nil {
+		j.Config = map[string]interface{}{}
+	}
+	j.Config["cron"] = options
+	return j
+}
+
+// cronSchedule parses the schedule expression of the job
+func (j *Job) cronSchedule() (cron.Schedule, error) {
+	if j.ScheduleExpression == nil {
+		return nil, fmt.Errorf("job %s has no schedule expression", j.JobID)
+	}
+	return ParseCronExpression(*j.ScheduleExpression, j.CronOptions().Timezone)
+}
+
+// IsScheduled returns true if the cron job is scheduled in this process
+func IsScheduled(jobID string) bool {
+	scheduler := getCronScheduler()
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+	_, ok := scheduler.entries[jobID]
+	return ok
+}
+
+// StopCronScheduler stops all scheduled cron jobs
+func StopCronScheduler() {
+	scheduler := getCronScheduler()
+	scheduler.mu.Lock()
+	for jobID, entry := range scheduler.entries {
+		entry.cancel()
+		delete(scheduler.entries, jobID)
+	}
+	scheduler.mu.Unlock()
+	log.Info("Job cron scheduler stopped")
+}
+
+// add schedules a cron job, replacing any previous schedule of the same job
+// When restoring after a restart, the ticks missed since next_run_at are handled by the missed run policy
+func (s *cronScheduler) add(job *Job, restore bool) error {
+	schedule, err := job.cronSchedule()
+	if err != nil {
+		return err
+	}
+	options := job.CronOptions()
+	now := time.Now()
+
+	next := schedule.Next(now)
+	if next.IsZero() {
+		return fmt.Errorf("cron expression %s never fires", *job.ScheduleExpression)
+	}
+
+	// Initialize job context for cancellation
+	if job.ctx == nil || job.ctx.Err() != nil {
+		job.ctx, job.cancel = context.WithCancel(context.Background())
+	}
+	job.executionMutex.Lock()
+	if job.executionContexts == nil {
+		job.executionContexts = make(map[string]context.CancelFunc)
+	}
+	job.executionMutex.Unlock()
+
+	ctx, cancel := context.WithCancel(job.ctx)
+	entry := &cronEntry{job: job, schedule: schedule, options: options, next: next, ctx: ctx, cancel: cancel}
+
+	if restore {
+		// Runs left queued or running by the previous process will never finish
+		if err := interruptScheduledExecutions(job.JobID); err != nil {
+			log.Warn("Failed to interrupt stale executions of job %s: %v", job.JobID, err)
+		}
+		entry.pending = missedTicks(job, schedule, options, now)
+	}
+
+	job.NextRunAt = &next
+	job.Status = "ready"
+	if err := SaveJob(job); err != nil {
+		cancel()
+		return fmt.Errorf("failed to update job schedule: %w", err)
+	}
+
+	s.mu.Lock()
+	if previous, ok := s.entries[job.JobID]; ok {
+		previous.cancel()
+	}
+	s.entries[job.JobID] = entry
+	s.mu.Unlock()
+
+	go s.run(entry)
+	log.Info("Job %s scheduled with cron expression %s, next run at %s", job.JobID, *job.ScheduleExpression, next.Format(time.RFC3339))
+	return nil
+}
+
+// remove unschedules a cron job
+func (s *cronScheduler) remove(jobID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry, ok := s.entries[jobID]; ok {
+		entry.cancel()
+		delete(s.entries, jobID)
+	}
+}
+
+// release removes the entry once its loop has exited
+func (s *cronScheduler) release(entry *cronEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if current, ok := s.entries[entry.job.JobID]; ok && current == entry {
+		delete(s.entries, entry.job.JobID)
+	}
+}
+
+// run is the scheduling loop of a cron job
+func (s *cronScheduler) run(entry *cronEntry) {
+	defer s.release(entry)
+
+	for {
+		wait := time.Until(entry.next)
+		if len(entry.pending) > 0 && wait > cronPollInterval {
+			wait = cronPollInterval
+		}
+		if wait < 0 {
+			wait = 0
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-entry.ctx.Done():
+			timer.Stop()
+			return
+
+		case now := <-timer.C:
+			if !now.Before(entry.next) {
+				tick := entry.next
+				entry.next = entry.schedule.Next(now)
+				if entry.next.IsZero() {
+					log.Warn("Job %s cron expression never fires again, unscheduled", entry.job.JobID)
+					return
+				}
+				next := entry.next
+				entry.job.NextRunAt = &next
+				if err := updateNextRunAt(entry.job.JobID, next); err != nil {
+					log.Warn("Failed to save next run time of job %s: %v", entry.job.JobID, err)
+				}
+
+				// Ticks arriving during a catch-up wait for their turn
+				if len(entry.pending) > 0 {
+					entry.pending = append(entry.pending, tick)
+				} else {
+					entry.fire(tick, false)
+				}
+			}
+
+			if len(entry.pending) > 0 && entry.fire(entry.pending[0], true) {
+				entry.pending = entry.pending[1:]
+			}
+		}
+	}
+}
+
+// fire creates and submits the executions of a tick
+// Returns false if the tick could not run because the previous run is still active
+func (e *cronEntry) fire(tick time.Time, catchUp bool) bool {
+	job := e.job
+
+	active, err := countActiveScheduledExecutions(job.JobID)
+	if err != nil {
+		log.Warn("Failed to check active executions of job %s: %v", job.JobID, err)
+		return false
+	}
+
+	if active > 0 {
+		if catchUp {
+			return false // Retried on the next poll
+		}
+		cronLog(job, "warning", "Skipped run scheduled at %s: the previous run is still active", tick.Format(time.RFC3339))
+		return true
+	}
+
+	templates, err := getCronTemplates(job.JobID)
+	if err != nil {
+		log.Warn("Failed to get executions of job %s: %v", job.JobID, err)
+		return true
+	}
+	if len(templates) == 0 {
+		cronLog(job, "warning", "Skipped run scheduled at %s: no executions found", tick.Format(time.RFC3339))
+		return true
+	}
+
+	if hasDependencies(templates) {
+		if _, err := validateDAG(templates); err != nil {
+			cronLog(job, "warning", "Skipped run scheduled at %s: invalid execution dependencies: %v", tick.Format(time.RFC3339), err)
+			return true
+		}
+		job.dag = true
+	}
+
+	for _, template := range templates {
+		if err := e.submit(template, tick, catchUp); err != nil {
+			log.Error("Job %s failed to run execution %s scheduled at %s: %v", job.JobID, template.ExecutionID, tick.Format(time.RFC3339), err)
+		}
+	}
+	return true
+}
+
+// submit creates a new execution from a template and submits it to the worker manager
+// Executions depending on other executions are submitted once their upstream executions are completed
+func (e *cronEntry) submit(template *Execution, tick time.Time, catchUp bool) error {
+	job := e.job
+
+	config, configSnapshot, err := copyExecutionConfig(template)
+	if err != nil {
+		return err
+	}
+
+	triggerContext, err := jsoniter.Marshal(map[string]interface{}{
+		"template_execution_id": template.ExecutionID,
+		"scheduled_at":          tick,
+		"catch_up":              catchUp,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to serialize trigger context: %w", err)
+	}
+	rawTriggerContext := json.RawMessage(triggerContext)
+
+	triggerSource := "cron"
+	runID := cronRunID(job.JobID, tick)
+	execution := &Execution{
+		JobID:            job.JobID,
+		Status:           "queued",
+		TriggerCategory:  "scheduled",
+		TriggerSource:    &triggerSource,
+		TriggerContext:   &rawTriggerContext,
+		ScheduledAt:      &tick,
+		RunID:            &runID,
+		TimeoutSeconds:   template.TimeoutSeconds,
+		ExecutionConfig:  config,
+		ConfigSnapshot:   configSnapshot,
+		ExecutionOptions: template.ExecutionOptions,
+		CreatedAt:        time.Now(),
+		UpdatedAt:        time.Now(),
+	}
+
+	if err := SaveExecution(execution); err != nil {
+		return fmt.Errorf("failed to create execution record: %w", err)
+	}
+
+	if len(dependsOn(execution)) > 0 {
+		return nil
+	}
+
+	if err := job.submit(execution); err != nil {
+		execution.Status = "failed"
+		execution.Error("Failed to submit scheduled execution: %v", err)
+		if saveErr := SaveExecution(execution); saveErr != nil {
+			log.Warn("Failed to save execution status (database may be closed): %v", saveErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// cronRunID returns the run ID of the executions created for a cron tick
+func cronRunID(jobID string, tick time.Time) string {
+	return jobID + "@" + tick.UTC().Format(time.RFC3339)
+}
+
+// missedTicks returns the ticks missed since the job next_run_at according to the missed run policy
+func missedTicks(job *Job, schedule cron.Schedule, options *CronOptions, now time.Time) []time.Time {
+	if job.NextRunAt == nil || job.NextRunAt.IsZero() || job.NextRunAt.After(now) {
+		return nil
+	}
+
+	ticks := []time.Time{}
+	overflow := false
+	for tick := *job.NextRunAt; !tick.IsZero() && !tick.After(now); tick = schedule.Next(tick) {
+		if len(ticks) >= options.MaxCatchUp {
+			overflow = true
+			break
+		}
+		ticks = append(ticks, tick)
+	}
+
+	count := fmt.Sprintf("%d", len(ticks))
+	if overflow {
+		count = fmt.Sprintf("more than %d", len(ticks))
+	}
+
+	if options.MissedRunPolicy != MissedRunCatchUp {
+		cronLog(job, "warning", "Skipped %s missed run(s) since %s", count, job.NextRunAt.Format(time.RFC3339))
+		return nil
+	}
+
+	if overflow {
+		cronLog(job, "warning", "Missed %s runs since %s, only the first %d will run", count, job.NextRunAt.Format(time.RFC3339), options.MaxCatchUp)
+	}
+	cronLog(job, "info", "Catching up %d missed run(s) since %s", len(ticks), job.NextRunAt.Format(time.RFC3339))
+	return ticks
+}
+
+// cronLog saves a job level scheduling log
+func cronLog(job *Job, level string, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	source := "cron"
+	logEntry := &Log{
+		JobID:     job.JobID,
+		Level:     level,
+		Message:   message,
+		Source:    &source,
+		Timestamp: time.Now(),
+		Sequence:  0,
+	}
+	if err := SaveLog(logEntry); err != nil {
+		log.Warn("Failed to save cron log (database may be closed): %v", err)
+	}
+
+	if level == "warning" {
+		log.Warn("[Job:%s] %s", job.JobID, message)
+		return
+	}
+	log.Info("[Job:%s] %s", job.JobID, message)
+}
@@ -0,0 +1,204 @@
+package job_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yaoapp/yao/config"
+	"github.com/yaoapp/yao/job"
+	"github.com/yaoapp/yao/test"
+)
+
+// TestParseCronExpression tests expression parsing with seconds and timezone
+func TestParseCronExpression(t *testing.T) {
+	from := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("Standard", func(t *testing.T) {
+		next, err := job.NextRunTime("30 * * * *", "UTC", from)
+		if err != nil {
+			t.Fatalf("Failed to parse expression: %v", err)
+		}
+		if !next.Equal(time.Date(2025, 1, 1, 10, 30, 0, 0, time.UTC)) {
+			t.Errorf("Unexpected next run time: %v", next)
+		}
+	})
+
+	t.Run("Seconds", func(t *testing.T) {
+		next, err := job.NextRunTime("*/15 * * * * *", "UTC", from)
+		if err != nil {
+			t.Fatalf("Failed to parse expression: %v", err)
+		}
+		if !next.Equal(from.Add(15 * time.Second)) {
+			t.Errorf("Unexpected next run time: %v", next)
+		}
+	})
+
+	t.Run("Timezone", func(t *testing.T) {
+		// 02:00 in Shanghai is 18:00 UTC the day before
+		next, err := job.NextRunTime("0 2 * * *", "Asia/Shanghai", from)
+		if err != nil {
+			t.Fatalf("Failed to parse expression: %v", err)
+		}
+		if !next.UTC().Equal(time.Date(2025, 1, 1, 18, 0, 0, 0, time.UTC)) {
+			t.Errorf("Unexpected next run time: %v", next.UTC())
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		if _, err := job.ParseCronExpression("not a cron", ""); err == nil {
+			t.Error("Expected an error for an invalid expression")
+		}
+		if _, err := job.ParseCronExpression("0 2 * * *", "Mars/Olympus"); err == nil {
+			t.Error("Expected an error for an invalid timezone")
+		}
+		if _, err := job.Cron(job.GOROUTINE, map[string]interface{}{}, "61 * * * *"); err == nil {
+			t.Error("Expected Cron to reject an invalid expression")
+		}
+	})
+}
+
+// TestCronSchedule tests that a pushed cron job runs on each tick without overlapping
+func TestCronSchedule(t *testing.T) {
+	test.Prepare(&testing.T{}, config.Conf)
+	defer test.Clean()
+
+	testJob, err := job.CronAndSave(job.GOROUTINE, map[string]interface{}{
+		"name":        "Test Cron Schedule",
+		"description": "Cron job running every second",
+	}, "* * * * * *")
+	if err != nil {
+		t.Fatalf("Failed to create cron job: %v", err)
+	}
+
+	var calls, running, overlaps int32
+	err = testJob.AddFunc(nil, "test.cron.tick", func(ctx *job.ExecutionContext) error {
+		if atomic.AddInt32(&running, 1) > 1 {
+			atomic.AddInt32(&overlaps, 1)
+		}
+		defer atomic.AddInt32(&running, -1)
+		atomic.AddInt32(&calls, 1)
+
+		// Longer than the tick interval, the next tick must be skipped
+		time.Sleep(1500 * time.Millisecond)
+		return nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to add function execution: %v", err)
+	}
+
+	if err := testJob.Push(); err != nil {
+		t.Fatalf("Failed to push job: %v", err)
+	}
+	if !job.IsScheduled(testJob.JobID) {
+		t.Fatalf("Expected job to be scheduled")
+	}
+
+	time.Sleep(5 * time.Second)
+
+	if err := testJob.Stop(); err != nil {
+		t.Fatalf("Failed to stop job: %v", err)
+	}
+	if job.IsScheduled(testJob.JobID) {
+		t.Errorf("Expected job to be unscheduled after stop")
+	}
+
+	n := atomic.LoadInt32(&calls)
+	if n < 1 || n > 3 {
+		t.Errorf("Expected 1 to 3 runs, got %d", n)
+	}
+	if atomic.LoadInt32(&overlaps) > 0 {
+		t.Errorf("Expected no overlapping runs")
+	}
+
+	executions, err := testJob.GetExecutions()
+	if err != nil {
+		t.Fatalf("Failed to get executions: %v", err)
+	}
+
+	scheduled := 0
+	for _, execution := range executions {
+		if execution.TriggerCategory == "scheduled" {
+			scheduled++
+			if execution.ScheduledAt == nil {
+				t.Errorf("Expected scheduled execution to have scheduled_at")
+			}
+		}
+	}
+	if scheduled < int(n) {
+		t.Errorf("Expected at least %d scheduled executions, got %d", n, scheduled)
+	}
+
+	// No more runs after stop
+	time.Sleep(1500 * time.Millisecond)
+	if after := atomic.LoadInt32(&calls); after != n {
+		t.Errorf("Expected no runs after stop, got %d more", after-n)
+	}
+}
+
+// TestCronMissedRuns tests the missed run policies when restoring jobs
+func TestCronMissedRuns(t *testing.T) {
+	test.Prepare(&testing.T{}, config.Conf)
+	defer test.Clean()
+	defer job.StopCronScheduler()
+
+	// Keep away from the minute boundary so that no regular tick fires during the test
+	if now := time.Now(); now.Second() > 45 {
+		time.Sleep(now.Truncate(time.Minute).Add(time.Minute + time.Second).Sub(now))
+	}
+
+	newJob := func(name string, policy job.MissedRunPolicy, calls *int32) *job.Job {
+		testJob, err := job.CronAndSave(job.GOROUTINE, map[string]interface{}{"name": name}, "0 * * * * *")
+		if err != nil {
+			t.Fatalf("Failed to create cron job: %v", err)
+		}
+
+		err = testJob.AddFunc(nil, "test.cron.missed", func(ctx *job.ExecutionContext) error {
+			atomic.AddInt32(calls, 1)
+			return nil
+		}, nil)
+		if err != nil {
+			t.Fatalf("Failed to add function execution: %v", err)
+		}
+
+		// Simulate a server that was down for 5 minutes
+		missedSince := time.Now().Add(-5 * time.Minute)
+		testJob.SetCronOptions(&job.CronOptions{MissedRunPolicy: policy, MaxCatchUp: 2})
+		testJob.NextRunAt = &missedSince
+		testJob.Status = "ready"
+		if err := job.SaveJob(testJob); err != nil {
+			t.Fatalf("Failed to save job: %v", err)
+		}
+		return testJob
+	}
+
+	var skipCalls, catchUpCalls int32
+	skipJob := newJob("Test Cron Skip", job.MissedRunSkip, &skipCalls)
+	catchUpJob := newJob("Test Cron Catch Up", job.MissedRunCatchUp, &catchUpCalls)
+
+	if _, err := job.RestoreJobsFromDatabase(); err != nil {
+		t.Fatalf("Failed to restore jobs: %v", err)
+	}
+	if !job.IsScheduled(skipJob.JobID) || !job.IsScheduled(catchUpJob.JobID) {
+		t.Fatalf("Expected restored cron jobs to be scheduled")
+	}
+
+	time.Sleep(4 * time.Second)
+
+	if n := atomic.LoadInt32(&catchUpCalls); n != 2 {
+		t.Errorf("Expected 2 catch-up runs (max_catch_up), got %d", n)
+	}
+
+	// Skip: nothing runs until the next tick
+	if n := atomic.LoadInt32(&skipCalls); n != 0 {
+		t.Errorf("Expected missed runs to be skipped, got %d runs", n)
+	}
+
+	restored, err := job.GetJob(skipJob.JobID)
+	if err != nil {
+		t.Fatalf("Failed to get job: %v", err)
+	}
+	if restored.NextRunAt == nil || !restored.NextRunAt.After(time.Now()) {
+		t.Errorf("Expected next_run_at to be moved to the next tick, got %v", restored.NextRunAt)
+	}
+}
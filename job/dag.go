@@ -0,0 +1,476 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/gou/model"
+	"github.com/yaoapp/kun/log"
+)
+
+// UpstreamFailurePolicy defines what happens to an execution when one of its upstream executions fails
+type UpstreamFailurePolicy string
+
+// UpstreamFailurePolicy constants
+const (
+	UpstreamFailureSkip UpstreamFailurePolicy = "skip" // Mark the execution (and its branch) as skipped (default)
+	UpstreamFailureFail UpstreamFailurePolicy = "fail" // Mark the execution (and its branch) as failed
+)
+
+// UpstreamDataKey the SharedData key holding the results of the upstream executions, indexed by key
+const UpstreamDataKey = "upstream"
+
+// DAG the dependency graph of a job run
+type DAG struct {
+	JobID     string     `json:"job_id"`
+	Status    string     `json:"status"` // pending | running | completed | failed
+	Progress  int        `json:"progress"`
+	Total     int        `json:"total"`
+	Completed int        `json:"completed"`
+	Failed    int        `json:"failed"`
+	Skipped   int        `json:"skipped"`
+	Nodes     []*DAGNode `json:"nodes"`
+	Edges     []DAGEdge  `json:"edges"`
+}
+
+// DAGNode an execution of the dependency graph (the latest attempt when retried)
+type DAGNode struct {
+	Key          string   `json:"key"`
+	ExecutionID  string   `json:"execution_id"`
+	Name         string   `json:"name,omitempty"`
+	Status       string   `json:"status"`
+	Progress     int      `json:"progress"`
+	RetryAttempt int      `json:"retry_attempt"`
+	Level        int      `json:"level"` // Depth in the graph, roots are 0
+	DependsOn    []string `json:"depends_on,omitempty"`
+}
+
+// DAGEdge a dependency between two executions, from upstream to downstream key
+type DAGEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// upstreamFailedStatus statuses of an upstream execution that stop its downstream branch
+var upstreamFailedStatus = map[string]bool{
+	"failed": true, "dead_letter": true, "cancelled": true, "skipped": true, "timeout": true, "killed": true,
+}
+
+// dagFinishedStatus statuses of an execution that will not change anymore
+var dagFinishedStatus = map[string]bool{
+	"completed": true, "failed": true, "dead_letter": true, "cancelled": true, "skipped": true, "timeout": true, "killed": true,
+}
+
+// dagProgress summary of a job run returned when an execution finishes
+type dagProgress struct {
+	pending bool // Some executions are not finished yet
+	failed  bool // Some executions failed or were skipped
+}
+
+// hasDependencies returns true if any execution depends on another one
+func hasDependencies(executions []*Execution) bool {
+	for _, execution := range executions {
+		if len(dependsOn(execution)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// executionKey returns the key of an execution, the execution ID when no key is set
+func executionKey(execution *Execution) string {
+	if execution.ExecutionOptions != nil && execution.ExecutionOptions.Key != "" {
+		return execution.ExecutionOptions.Key
+	}
+	return execution.ExecutionID
+}
+
+// dependsOn returns the upstream keys of an execution
+func dependsOn(execution *Execution) []string {
+	if execution.ExecutionOptions == nil {
+		return nil
+	}
+	return execution.ExecutionOptions.DependsOn
+}
+
+// latestAttempts keeps the latest attempt of each execution key
+func latestAttempts(executions []*Execution) map[string]*Execution {
+	nodes := map[string]*Execution{}
+	for _, execution := range executions {
+		key := executionKey(execution)
+		if current, ok := nodes[key]; !ok || execution.RetryAttempt > current.RetryAttempt {
+			nodes[key] = execution
+		}
+	}
+	return nodes
+}
+
+// validateDAG checks that the keys are unique, the dependencies exist and there is no cycle
+// Returns the depth of each key
+func validateDAG(executions []*Execution) (map[string]int, error) {
+	deps := map[string][]string{}
+	for _, execution := range executions {
+		key := executionKey(execution)
+		if _, ok := deps[key]; ok {
+			return nil, fmt.Errorf("duplicate execution key: %s", key)
+		}
+		deps[key] = dependsOn(execution)
+	}
+
+	for key, upstreams := range deps {
+		for _, upstream := range upstreams {
+			if upstream == key {
+				return nil, fmt.Errorf("execution %s depends on itself", key)
+			}
+			if _, ok := deps[upstream]; !ok {
+				return nil, fmt.Errorf("execution %s depends on unknown execution %s", key, upstream)
+			}
+		}
+	}
+
+	// Kahn's algorithm, the depth of a node is the longest path from a root
+	indegree := map[string]int{}
+	downstreams := map[string][]string{}
+	for key, upstreams := range deps {
+		indegree[key] = len(upstreams)
+		for _, upstream := range upstreams {
+			downstreams[upstream] = append(downstreams[upstream], key)
+		}
+	}
+
+	levels := map[string]int{}
+	queue := []string{}
+	for key, degree := range indegree {
+		if degree == 0 {
+			queue = append(queue, key)
+			levels[key] = 0
+		}
+	}
+
+	visited := 0
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		visited++
+		for _, downstream := range downstreams[key] {
+			if levels[key]+1 > levels[downstream] {
+				levels[downstream] = levels[key] + 1
+			}
+			indegree[downstream]--
+			if indegree[downstream] == 0 {
+				queue = append(queue, downstream)
+			}
+		}
+	}
+
+	if visited != len(deps) {
+		cycle := []string{}
+		for key, degree := range indegree {
+			if degree > 0 {
+				cycle = append(cycle, key)
+			}
+		}
+		sort.Strings(cycle)
+		return nil, fmt.Errorf("dependency cycle between executions: %s", strings.Join(cycle, ", "))
+	}
+
+	return levels, nil
+}
+
+// advanceDAG submits the executions whose upstream executions are all completed
+// and stops the branches of the failed ones. Called each time an execution of the job finishes.
+func (j *Job) advanceDAG(finished *Execution) (*dagProgress, error) {
+	j.dagMutex.Lock()
+	defer j.dagMutex.Unlock()
+
+	// Executions of the same run only
+	run, err := getRunExecutions(j.JobID, finished.RunID)
+	if err != nil {
+		return nil, err
+	}
+	nodes := latestAttempts(run)
+
+	// Propagate until nothing changes, a stopped branch may stop the next one
+	for changed := true; changed; {
+		changed = false
+		for key, node := range nodes {
+			if node.Status != "queued" || len(dependsOn(node)) == 0 || j.isSubmitted(node.ExecutionID) || node.ParentExecutionID != nil {
+				continue
+			}
+
+			ready := true
+			var failedUpstream *Execution
+			for _, upstream := range dependsOn(node) {
+				upstreamNode, ok := nodes[upstream]
+				if !ok {
+					continue
+				}
+				if upstreamFailedStatus[upstreamNode.Status] {
+					failedUpstream = upstreamNode
+					break
+				}
+				if upstreamNode.Status != "completed" {
+					ready = false
+				}
+			}
+
+			if failedUpstream != nil {
+				stopBranch(node, executionKey(failedUpstream), failedUpstream.Status)
+				changed = true
+				continue
+			}
+
+			if ready {
+				if err := j.submitDownstream(node, nodes); err != nil {
+					log.Error("Job %s failed to submit execution %s: %v", j.JobID, key, err)
+				}
+			}
+		}
+	}
+
+	progress := &dagProgress{}
+	for _, node := range nodes {
+		if !dagFinishedStatus[node.Status] {
+			progress.pending = true
+		}
+		if node.Status != "completed" && dagFinishedStatus[node.Status] {
+			progress.failed = true
+		}
+	}
+	return progress, nil
+}
+
+// isSubmitted returns true if the execution has been submitted to the worker manager
+func (j *Job) isSubmitted(executionID string) bool {
+	j.executionMutex.RLock()
+	defer j.executionMutex.RUnlock()
+	_, ok := j.executionContexts[executionID]
+	return ok
+}
+
+// stopBranch marks an execution as skipped or failed because an upstream execution failed
+func stopBranch(execution *Execution, upstream string, status string) {
+	policy := UpstreamFailureSkip
+	if execution.ExecutionOptions != nil && execution.ExecutionOptions.OnUpstreamFailure != "" {
+		policy = execution.ExecutionOptions.OnUpstreamFailure
+	}
+
+	now := time.Now()
+	execution.EndedAt = &now
+	execution.Status = "skipped"
+	if policy == UpstreamFailureFail {
+		execution.Status = "failed"
+		errorData, _ := jsoniter.Marshal(map[string]interface{}{
+			"error":    fmt.Sprintf("upstream execution %s %s", upstream, status),
+			"upstream": upstream,
+			"time":     now,
+		})
+		execution.ErrorInfo = (*json.RawMessage)(&errorData)
+	}
+
+	if err := SaveExecution(execution); err != nil {
+		log.Warn("Failed to save execution status (database may be closed): %v", err)
+	}
+	execution.Warn("Execution %s: upstream execution %s %s", execution.Status, upstream, status)
+}
+
+// submitDownstream passes the upstream results to an execution and submits it
+func (j *Job) submitDownstream(execution *Execution, nodes map[string]*Execution) error {
+	upstreamData := map[string]interface{}{}
+	for _, upstream := range dependsOn(execution) {
+		node, ok := nodes[upstream]
+		if !ok || node.Result == nil {
+			upstreamData[upstream] = nil
+			continue
+		}
+		var result interface{}
+		if err := jsoniter.Unmarshal(*node.Result, &result); err != nil {
+			return fmt.Errorf("failed to read the result of execution %s: %w", upstream, err)
+		}
+		upstreamData[upstream] = result
+	}
+
+	// Copy the options, they may be shared with a cron template or a retried execution
+	options := *execution.ExecutionOptions
+	options.SharedData = make(map[string]interface{}, len(execution.ExecutionOptions.SharedData)+1)
+	for key, value := range execution.ExecutionOptions.SharedData {
+		options.SharedData[key] = value
+	}
+	options.SharedData[UpstreamDataKey] = upstreamData
+	execution.ExecutionOptions = &options
+
+	if err := SaveExecution(execution); err != nil {
+		return fmt.Errorf("failed to save execution: %w", err)
+	}
+	return j.submit(execution)
+}
+
+// submit submits an execution with a context derived from the job context
+func (j *Job) submit(execution *Execution) error {
+	if j.ctx == nil {
+		j.ctx, j.cancel = context.WithCancel(context.Background())
+	}
+
+	ctx, cancel := context.WithCancel(j.ctx)
+	j.executionMutex.Lock()
+	if j.executionContexts == nil {
+		j.executionContexts = make(map[string]context.CancelFunc)
+	}
+	j.executionContexts[execution.ExecutionID] = cancel
+	j.executionMutex.Unlock()
+
+	if err := GetWorkerManager().SubmitJob(ctx, j, execution); err != nil {
+		cancel()
+		j.executionMutex.Lock()
+		delete(j.executionContexts, execution.ExecutionID)
+		j.executionMutex.Unlock()
+		return err
+	}
+	return nil
+}
+
+// cancelPendingDAG cancels the executions still waiting for their upstream executions
+func (j *Job) cancelPendingDAG() {
+	wheres := []model.QueryWhere{{Column: "status", Value: "queued"}}
+	// Cron templates are never run directly
+	if j.ScheduleType == string(ScheduleTypeCron) {
+		wheres = append(wheres, model.QueryWhere{Column: "trigger_category", Value: "scheduled"})
+	}
+	executions, err := getExecutions(j.JobID, wheres...)
+	if err != nil {
+		log.Warn("Failed to get executions of job %s: %v", j.JobID, err)
+		return
+	}
+
+	now := time.Now()
+	for _, execution := range executions {
+		if len(dependsOn(execution)) == 0 || j.isSubmitted(execution.ExecutionID) {
+			continue
+		}
+		execution.Status = "cancelled"
+		execution.EndedAt = &now
+		if err := SaveExecution(execution); err != nil {
+			log.Warn("Failed to save execution status (database may be closed): %v", err)
+		}
+	}
+}
+
+// GetDAG returns the dependency graph of the latest run of the job
+func (j *Job) GetDAG() (*DAG, error) {
+	return GetDAG(j.JobID)
+}
+
+// GetDAG returns the dependency graph of the latest run of a job
+// For cron jobs the latest run is the latest tick
+func GetDAG(jobID string) (*DAG, error) {
+	// Scheduled runs take precedence over cron templates
+	latest, err := getLatestExecution(jobID, model.QueryWhere{Column: "trigger_category", Value: "scheduled"})
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil {
+		latest, err = getLatestExecution(jobID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dag := &DAG{JobID: jobID, Status: "pending", Nodes: []*DAGNode{}, Edges: []DAGEdge{}}
+	if latest == nil {
+		return dag, nil
+	}
+
+	run, err := getRunExecutions(jobID, latest.RunID)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := latestAttempts(run)
+	list := make([]*Execution, 0, len(nodes))
+	for _, node := range nodes {
+		list = append(list, node)
+	}
+
+	levels, err := validateDAG(list)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := false
+	running := false
+	totalProgress := 0
+	for key, node := range nodes {
+		name := ""
+		if node.ExecutionConfig != nil {
+			name = node.ExecutionConfig.ProcessName
+			if name == "" {
+				name = node.ExecutionConfig.FuncName
+			}
+			if name == "" {
+				name = node.ExecutionConfig.Command
+			}
+		}
+
+		dag.Nodes = append(dag.Nodes, &DAGNode{
+			Key:          key,
+			ExecutionID:  node.ExecutionID,
+			Name:         name,
+			Status:       node.Status,
+			Progress:     node.Progress,
+			RetryAttempt: node.RetryAttempt,
+			Level:        levels[key],
+			DependsOn:    dependsOn(node),
+		})
+		for _, upstream := range dependsOn(node) {
+			dag.Edges = append(dag.Edges, DAGEdge{From: upstream, To: key})
+		}
+
+		totalProgress += node.Progress
+		switch node.Status {
+		case "completed":
+			dag.Completed++
+		case "skipped":
+			dag.Skipped++
+		case "queued":
+			pending = true
+		case "running", "initializing":
+			running = true
+		default:
+			if dagFinishedStatus[node.Status] {
+				dag.Failed++
+			}
+		}
+	}
+
+	sort.Slice(dag.Nodes, func(a, b int) bool {
+		if dag.Nodes[a].Level != dag.Nodes[b].Level {
+			return dag.Nodes[a].Level < dag.Nodes[b].Level
+		}
+		return dag.Nodes[a].Key < dag.Nodes[b].Key
+	})
+	sort.Slice(dag.Edges, func(a, b int) bool {
+		if dag.Edges[a].From != dag.Edges[b].From {
+			return dag.Edges[a].From < dag.Edges[b].From
+		}
+		return dag.Edges[a].To < dag.Edges[b].To
+	})
+
+	dag.Total = len(dag.Nodes)
+	dag.Progress = totalProgress / dag.Total
+	switch {
+	case running || (pending && (dag.Completed > 0 || dag.Failed > 0 || dag.Skipped > 0)):
+		dag.Status = "running"
+	case pending:
+		dag.Status = "pending"
+	case dag.Failed > 0 || dag.Skipped > 0:
+		dag.Status = "failed"
+	default:
+		dag.Status = "completed"
+	}
+	return dag, nil
+}
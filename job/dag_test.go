@@ -0,0 +1,223 @@
+package job_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yaoapp/yao/config"
+	"github.com/yaoapp/yao/job"
+	"github.com/yaoapp/yao/test"
+)
+
+// TestDAGExecution tests that executions run after their upstream executions and receive their results
+func TestDAGExecution(t *testing.T) {
+	test.Prepare(&testing.T{}, config.Conf)
+	defer test.Clean()
+
+	testJob, err := job.OnceAndSave(job.GOROUTINE, map[string]interface{}{
+		"name":        "Test DAG Execution",
+		"description": "extract -> (transform, validate) -> load",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+
+	var mu sync.Mutex
+	order := []string{}
+	upstreams := map[string]map[string]interface{}{}
+	step := func(key string, result interface{}) job.ExecutionFunc {
+		return func(ctx *job.ExecutionContext) error {
+			mu.Lock()
+			order = append(order, key)
+			if data, ok := ctx.Execution.ExecutionOptions.SharedData[job.UpstreamDataKey].(map[string]interface{}); ok {
+				upstreams[key] = data
+			}
+			mu.Unlock()
+
+			time.Sleep(100 * time.Millisecond)
+			return ctx.SetResult(result)
+		}
+	}
+
+	steps := []struct {
+		key       string
+		dependsOn []string
+	}{
+		{"load", []string{"transform", "validate"}},
+		{"transform", []string{"extract"}},
+		{"validate", []string{"extract"}},
+		{"extract", nil},
+	}
+	for _, s := range steps {
+		options := job.NewExecutionOptions().WithKey(s.key).WithDependsOn(s.dependsOn...)
+		if err := testJob.AddFunc(options, "test.dag."+s.key, step(s.key, map[string]interface{}{"step": s.key}), nil); err != nil {
+			t.Fatalf("Failed to add execution %s: %v", s.key, err)
+		}
+	}
+
+	if err := testJob.Push(); err != nil {
+		t.Fatalf("Failed to push job: %v", err)
+	}
+
+	time.Sleep(3 * time.Second)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(order) != 4 {
+		t.Fatalf("Expected 4 executions to run, got %v", order)
+	}
+	if order[0] != "extract" || order[3] != "load" {
+		t.Errorf("Unexpected execution order: %v", order)
+	}
+
+	// Upstream results are passed by key
+	load := upstreams["load"]
+	if len(load) != 2 {
+		t.Fatalf("Expected load to receive 2 upstream results, got %v", load)
+	}
+	if transform, ok := load["transform"].(map[string]interface{}); !ok || transform["step"] != "transform" {
+		t.Errorf("Expected the transform result, got %v", load["transform"])
+	}
+	if extract, ok := upstreams["validate"]["extract"].(map[string]interface{}); !ok || extract["step"] != "extract" {
+		t.Errorf("Expected the extract result, got %v", upstreams["validate"])
+	}
+
+	dag, err := testJob.GetDAG()
+	if err != nil {
+		t.Fatalf("Failed to get DAG: %v", err)
+	}
+	if dag.Status != "completed" || dag.Total != 4 || dag.Completed != 4 || len(dag.Edges) != 4 {
+		t.Errorf("Unexpected DAG state: status=%s total=%d completed=%d edges=%d", dag.Status, dag.Total, dag.Completed, len(dag.Edges))
+	}
+	for _, node := range dag.Nodes {
+		expected := map[string]int{"extract": 0, "transform": 1, "validate": 1, "load": 2}[node.Key]
+		if node.Level != expected {
+			t.Errorf("Expected %s at level %d, got %d", node.Key, expected, node.Level)
+		}
+	}
+
+	// Added executions form the run of the job
+	executions, err := testJob.GetExecutions()
+	if err != nil {
+		t.Fatalf("Failed to get executions: %v", err)
+	}
+	for _, execution := range executions {
+		if execution.RunID == nil || *execution.RunID != testJob.JobID {
+			t.Errorf("Expected execution %s to belong to run %s, got %v", execution.ExecutionID, testJob.JobID, execution.RunID)
+		}
+	}
+}
+
+// TestDAGUpstreamFailure tests that the branches of a failed execution are skipped or failed
+func TestDAGUpstreamFailure(t *testing.T) {
+	test.Prepare(&testing.T{}, config.Conf)
+	defer test.Clean()
+
+	testJob, err := job.OnceAndSave(job.GOROUTINE, map[string]interface{}{
+		"name":        "Test DAG Upstream Failure",
+		"description": "extract fails, its branch stops, the independent execution runs",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+
+	var mu sync.Mutex
+	ran := map[string]bool{}
+	add := func(options *job.ExecutionOptions, fail bool) {
+		err := testJob.AddFunc(options, "test.dag."+options.Key, func(ctx *job.ExecutionContext) error {
+			mu.Lock()
+			ran[options.Key] = true
+			mu.Unlock()
+			if fail {
+				return fmt.Errorf("intentional test error")
+			}
+			return nil
+		}, nil)
+		if err != nil {
+			t.Fatalf("Failed to add execution %s: %v", options.Key, err)
+		}
+	}
+
+	add(job.NewExecutionOptions().WithKey("extract"), true)
+	add(job.NewExecutionOptions().WithKey("transform").WithDependsOn("extract"), false)
+	add(job.NewExecutionOptions().WithKey("load").WithDependsOn("transform").WithUpstreamFailure(job.UpstreamFailureFail), false)
+	add(job.NewExecutionOptions().WithKey("audit"), false)
+
+	if err := testJob.Push(); err != nil {
+		t.Fatalf("Failed to push job: %v", err)
+	}
+
+	time.Sleep(2 * time.Second)
+
+	mu.Lock()
+	if ran["transform"] || ran["load"] {
+		t.Errorf("Expected the downstream executions of extract not to run")
+	}
+	if !ran["audit"] {
+		t.Errorf("Expected the independent execution to run")
+	}
+	mu.Unlock()
+
+	dag, err := testJob.GetDAG()
+	if err != nil {
+		t.Fatalf("Failed to get DAG: %v", err)
+	}
+
+	status := map[string]string{}
+	for _, node := range dag.Nodes {
+		status[node.Key] = node.Status
+	}
+	expected := map[string]string{"extract": "failed", "transform": "skipped", "load": "failed", "audit": "completed"}
+	for key, want := range expected {
+		if status[key] != want {
+			t.Errorf("Expected %s to be '%s', got '%s'", key, want, status[key])
+		}
+	}
+	if dag.Status != "failed" || dag.Skipped != 1 || dag.Failed != 2 {
+		t.Errorf("Unexpected DAG state: status=%s failed=%d skipped=%d", dag.Status, dag.Failed, dag.Skipped)
+	}
+
+	updated, err := job.GetJob(testJob.JobID)
+	if err != nil {
+		t.Fatalf("Failed to get job: %v", err)
+	}
+	if updated.Status != "failed" {
+		t.Errorf("Expected job status 'failed', got '%s'", updated.Status)
+	}
+}
+
+// TestDAGInvalid tests that invalid dependencies are rejected on push
+func TestDAGInvalid(t *testing.T) {
+	test.Prepare(&testing.T{}, config.Conf)
+	defer test.Clean()
+
+	noop := func(ctx *job.ExecutionContext) error { return nil }
+
+	t.Run("Cycle", func(t *testing.T) {
+		testJob, err := job.OnceAndSave(job.GOROUTINE, map[string]interface{}{"name": "Test DAG Cycle"})
+		if err != nil {
+			t.Fatalf("Failed to create job: %v", err)
+		}
+		testJob.AddFunc(job.NewExecutionOptions().WithKey("a").WithDependsOn("b"), "test.dag.a", noop, nil)
+		testJob.AddFunc(job.NewExecutionOptions().WithKey("b").WithDependsOn("a"), "test.dag.b", noop, nil)
+
+		if err := testJob.Push(); err == nil {
+			t.Error("Expected an error for a dependency cycle")
+		}
+	})
+
+	t.Run("UnknownDependency", func(t *testing.T) {
+		testJob, err := job.OnceAndSave(job.GOROUTINE, map[string]interface{}{"name": "Test DAG Unknown"})
+		if err != nil {
+			t.Fatalf("Failed to create job: %v", err)
+		}
+		testJob.AddFunc(job.NewExecutionOptions().WithKey("a").WithDependsOn("missing"), "test.dag.a", noop, nil)
+
+		if err := testJob.Push(); err == nil {
+			t.Error("Expected an error for an unknown dependency")
+		}
+	})
+}
@@ -36,7 +36,7 @@ var CategoryFields = []interface{}{
 var ExecutionFields = []interface{}{
 	"id", "execution_id", "job_id", "status", "trigger_category", "trigger_source",
 	"trigger_context", "scheduled_at", "worker_id", "process_id", "retry_attempt",
-	"parent_execution_id", "started_at", "ended_at", "timeout_seconds", "duration",
+	"parent_execution_id", "run_id", "started_at", "ended_at", "timeout_seconds", "duration",
 	"progress", "execution_config", "execution_options", "config_snapshot",
 	"result", "error_info", "stack_trace", "metrics", "context", "created_at", "updated_at",
 }
@@ -766,6 +766,40 @@ func RemoveLogs(ids []string) error {
 
 // GetExecutions get executions by job_id
 func GetExecutions(jobID string) ([]*Execution, error) {
+	return getExecutions(jobID)
+}
+
+// getCronTemplates get the executions a cron job runs on each tick
+// Executions created by the scheduler (and their retries) are not templates
+func getCronTemplates(jobID string) ([]*Execution, error) {
+	return getExecutions(jobID, model.QueryWhere{Column: "trigger_category", OP: "!=", Value: "scheduled"})
+}
+
+// getRunExecutions get the executions of a job run, executions saved without a run ID form one run
+func getRunExecutions(jobID string, runID *string) ([]*Execution, error) {
+	if runID == nil {
+		return getExecutions(jobID, model.QueryWhere{Column: "run_id", OP: "null"})
+	}
+	return getExecutions(jobID, model.QueryWhere{Column: "run_id", Value: *runID})
+}
+
+// getLatestExecution get the latest execution by job_id with additional conditions, nil if none
+func getLatestExecution(jobID string, wheres ...model.QueryWhere) (*Execution, error) {
+	executions, err := queryExecutions(jobID, 1, wheres...)
+	if err != nil || len(executions) == 0 {
+		return nil, err
+	}
+	return executions[0], nil
+}
+
+// getExecutions get executions by job_id with additional conditions
+func getExecutions(jobID string, wheres ...model.QueryWhere) ([]*Execution, error) {
+	return queryExecutions(jobID, 0, wheres...)
+}
+
+// queryExecutions get executions by job_id with additional conditions, latest first
+// A limit of 0 returns all of them
+func queryExecutions(jobID string, limit int, wheres ...model.QueryWhere) ([]*Execution, error) {
 	mod := model.Select("__yao.job.execution")
 	if mod == nil {
 		return nil, fmt.Errorf("job execution model not found")
@@ -773,12 +807,57 @@ func GetExecutions(jobID string) ([]*Execution, error) {
 
 	param := model.QueryParam{
 		Select: ExecutionFields,
-		Wheres: []model.QueryWhere{
+		Wheres: append([]model.QueryWhere{
 			{Column: "job_id", Value: jobID},
-		},
+		}, wheres...),
 		Orders: []model.QueryOrder{
 			{Column: "created_at", Option: "desc"},
 		},
+		Limit: limit,
+	}
+
+	results, err := mod.Get(param)
+	if err != nil {
+		return nil, err
+	}
+
+	executions := make([]*Execution, 0, len(results))
+	for _, result := range results {
+		execution := &Execution{}
+		if err := mapToStruct(result, execution); err != nil {
+			continue
+		}
+
+		// Restore ExecutionConfig from ConfigSnapshot if available
+		if execution.ConfigSnapshot != nil && len(*execution.ConfigSnapshot) > 0 {
+			var config ExecutionConfig
+			if err := jsoniter.Unmarshal(*execution.ConfigSnapshot, &config); err == nil {
+				execution.ExecutionConfig = &config
+			}
+		}
+
+		executions = append(executions, execution)
+	}
+
+	return executions, nil
+}
+
+// getPendingRetries get the retry executions of all jobs that are still waiting to run
+func getPendingRetries() ([]*Execution, error) {
+	mod := model.Select("__yao.job.execution")
+	if mod == nil {
+		return nil, fmt.Errorf("job execution model not found")
+	}
+
+	param := model.QueryParam{
+		Select: ExecutionFields,
+		Wheres: []model.QueryWhere{
+			{Column: "trigger_source", Value: "retry"},
+			{Column: "status", Value: "queued"},
+		},
+		Orders: []model.QueryOrder{
+			{Column: "scheduled_at", Option: "asc"},
+		},
 	}
 
 	results, err := mod.Get(param)
@@ -853,6 +932,40 @@ func CountExecutions(jobID string, param model.QueryParam) (int, error) {
 	}
 }
 
+// countActiveScheduledExecutions count the scheduled executions of a job that are not finished yet
+func countActiveScheduledExecutions(jobID string) (int, error) {
+	return CountExecutions(jobID, model.QueryParam{
+		Wheres: []model.QueryWhere{
+			{Column: "trigger_category", Value: "scheduled"},
+			{Column: "status", OP: "in", Value: []string{"queued", "initializing", "running"}},
+		},
+	})
+}
+
+// interruptScheduledExecutions mark the unfinished scheduled executions of a job as failed
+func interruptScheduledExecutions(jobID string) error {
+	mod := model.Select("__yao.job.execution")
+	if mod == nil {
+		return fmt.Errorf("job execution model not found")
+	}
+
+	param := model.QueryParam{
+		Wheres: []model.QueryWhere{
+			{Column: "job_id", Value: jobID},
+			{Column: "trigger_category", Value: "scheduled"},
+			{Column: "status", OP: "in", Value: []string{"queued", "initializing", "running"}},
+		},
+	}
+
+	now := time.Now()
+	_, err := mod.UpdateWhere(param, map[string]interface{}{
+		"status":     "failed",
+		"ended_at":   now,
+		"updated_at": now,
+	})
+	return err
+}
+
 // RemoveExecutions remove executions by execution_id
 func RemoveExecutions(ids []string) error {
 	mod := model.Select("__yao.job.execution")
@@ -1247,6 +1360,27 @@ func mapToStruct(m maps.MapStr, v interface{}) error {
 	return jsoniter.Unmarshal(data, v)
 }
 
+// updateNextRunAt updates the next run time of a job
+func updateNextRunAt(jobID string, next time.Time) error {
+	mod := model.Select("__yao.job")
+	if mod == nil {
+		return fmt.Errorf("job model not found")
+	}
+
+	param := model.QueryParam{
+		Wheres: []model.QueryWhere{
+			{Column: "job_id", Value: jobID},
+		},
+		Limit: 1,
+	}
+
+	_, err := mod.UpdateWhere(param, map[string]interface{}{
+		"next_run_at": next,
+		"updated_at":  time.Now(),
+	})
+	return err
+}
+
 // updateJobProgress updates job progress and status based on its executions
 func updateJobProgress(jobID string) error {
 	// Skip if jobID is empty
@@ -1264,12 +1398,30 @@ func updateJobProgress(jobID string) error {
 		return nil // No executions to process
 	}
 
+	// Executions that have been retried are superseded by their retry
+	retried := map[string]bool{}
+	for _, execution := range executions {
+		if execution.ParentExecutionID != nil && *execution.ParentExecutionID != "" {
+			retried[*execution.ParentExecutionID] = true
+		}
+	}
+	if len(retried) > 0 {
+		current := make([]*Execution, 0, len(executions))
+		for _, execution := range executions {
+			if !retried[execution.ExecutionID] {
+				current = append(current, execution)
+			}
+		}
+		executions = current
+	}
+
 	// Calculate overall job progress and status
 	totalExecutions := len(executions)
 	completedCount := 0
 	failedCount := 0
 	runningCount := 0
 	cancelledCount := 0
+	skippedCount := 0 // Skipped because an upstream execution failed
 	totalProgress := 0
 
 	for _, execution := range executions {
@@ -1278,12 +1430,14 @@ func updateJobProgress(jobID string) error {
 		switch execution.Status {
 		case "completed":
 			completedCount++
-		case "failed":
+		case "failed", "dead_letter":
 			failedCount++
 		case "running":
 			runningCount++
 		case "cancelled":
 			cancelledCount++
+		case "skipped":
+			skippedCount++
 		}
 	}
 
@@ -1296,11 +1450,11 @@ func updateJobProgress(jobID string) error {
 		jobStatus = "cancelled" // All executions are cancelled
 	} else if completedCount == totalExecutions {
 		jobStatus = "completed"
-	} else if failedCount > 0 && runningCount == 0 && completedCount+failedCount+cancelledCount == totalExecutions {
+	} else if failedCount > 0 && runningCount == 0 && completedCount+failedCount+cancelledCount+skippedCount == totalExecutions {
 		jobStatus = "failed"
 	} else if runningCount > 0 || completedCount > 0 {
 		jobStatus = "running"
-	} else if cancelledCount > 0 && cancelledCount+completedCount+failedCount == totalExecutions {
+	} else if cancelledCount > 0 && cancelledCount+completedCount+failedCount+skippedCount == totalExecutions {
 		// Mix of cancelled with completed/failed, no running
 		jobStatus = "cancelled"
 	} else {
@@ -73,6 +73,7 @@ func (j *Job) addExecution(options *ExecutionOptions, config *ExecutionConfig) e
 		JobID:            j.JobID,
 		Status:           "queued",
 		TriggerCategory:  "manual",
+		RunID:            &j.JobID, // Added executions are run together by Push
 		RetryAttempt:     0,
 		Progress:         0,
 		ExecutionConfig:  config,          // Keep in memory for runtime use
@@ -253,9 +253,6 @@ func (g *Goroutine) ExecuteFunc(ctx context.Context, work *WorkRequest, progress
 		return fmt.Errorf("execution function not found in registry (funcID: %s)", funcID)
 	}
 
-	// Ensure cleanup after execution (success or failure)
-	defer UnregisterFunc(funcID)
-
 	funcName := config.FuncName
 	if funcName == "" {
 		funcName = "anonymous"
@@ -316,6 +313,20 @@ func (g *Goroutine) ExecuteFunc(ctx context.Context, work *WorkRequest, progress
 	return nil
 }
 
+// releaseFunc removes the Go function of an execution from the global registry
+func releaseFunc(execution *Execution) {
+	config := execution.ExecutionConfig
+	if config == nil || config.Type != ExecutionTypeFunc {
+		return
+	}
+
+	funcID := config.FuncID
+	if funcID == "" {
+		funcID = execution.ExecutionID
+	}
+	UnregisterFunc(funcID)
+}
+
 // extractProgressData extracts progress and message from callback data
 func extractProgressData(data map[string]interface{}) (int, string) {
 	var progressInt int = -1 // Default to -1 to indicate no progress value
@@ -139,7 +139,17 @@ func Cron(mode ModeType, data map[string]interface{}, expression string) (*Job,
 	if err != nil {
 		return nil, err
 	}
-	return makeJob(raw)
+
+	job, err := makeJob(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the expression early, it is evaluated again when the job is scheduled
+	if _, err := job.cronSchedule(); err != nil {
+		return nil, err
+	}
+	return job, nil
 }
 
 // CronAndSave create a new cron job and save it immediately
@@ -197,6 +207,19 @@ func (j *Job) Push() error {
 		return fmt.Errorf("no executions found for job %s", j.JobID)
 	}
 
+	// Executions depending on other executions wait for them, the graph must be valid
+	if hasDependencies(executions) {
+		if _, err := validateDAG(executions); err != nil {
+			return fmt.Errorf("invalid execution dependencies: %w", err)
+		}
+		j.dag = true
+	}
+
+	// Cron jobs are fired by the scheduler, each tick runs a copy of the executions
+	if j.ScheduleType == string(ScheduleTypeCron) {
+		return getCronScheduler().add(j, false)
+	}
+
 	// Sort executions by priority (higher priority first)
 	sort.Slice(executions, func(i, j int) bool {
 		priorityI := 0
@@ -232,6 +255,11 @@ func (j *Job) Push() error {
 	// Submit executions and ensure all are added successfully
 	var submitErrors []string
 	for _, execution := range executions {
+		// Submitted once their upstream executions are completed
+		if len(dependsOn(execution)) > 0 {
+			continue
+		}
+
 		// Create execution-specific context derived from job context
 		execCtx, execCancel := context.WithCancel(j.ctx)
 
@@ -269,6 +297,11 @@ func (j *Job) Stop() error {
 		return fmt.Errorf("failed to update job status: %w", err)
 	}
 
+	// Unschedule cron jobs
+	if j.ScheduleType == string(ScheduleTypeCron) {
+		getCronScheduler().remove(j.JobID)
+	}
+
 	// Cancel all running executions using job context
 	if j.cancel != nil {
 		j.cancel()
@@ -304,6 +337,9 @@ func (j *Job) Stop() error {
 	j.executionContexts = make(map[string]context.CancelFunc)
 	j.executionMutex.Unlock()
 
+	// Cancel the executions waiting for their upstream executions
+	j.cancelPendingDAG()
+
 	return nil
 }
 
@@ -441,6 +477,23 @@ func RestoreJobsFromDatabase() ([]*Job, error) {
 	// No need to restore handlers since we only use Yao processes and commands
 	// Both are fully serializable and self-contained
 
-	log.Info("Restored %d jobs from database", len(activeJobs))
+	// Reschedule cron jobs, missed runs are handled by their missed run policy
+	scheduler := getCronScheduler()
+	for _, job := range activeJobs {
+		if job.ScheduleType != string(ScheduleTypeCron) {
+			continue
+		}
+		if err := scheduler.add(job, true); err != nil {
+			log.Error("Failed to schedule cron job %s: %v", job.JobID, err)
+		}
+	}
+
+	// Re-queue the retries that were waiting for their backoff delay
+	retries, err := restoreRetries()
+	if err != nil {
+		log.Error("Failed to restore pending retries: %v", err)
+	}
+
+	log.Info("Restored %d jobs and %d pending retries from database", len(activeJobs), retries)
 	return activeJobs, nil
 }
@@ -0,0 +1,322 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/yaoapp/gou/model"
+	"github.com/yaoapp/kun/log"
+)
+
+// RetryPolicy defines how a failed execution is retried
+// Delays grow exponentially: initial_interval * multiplier^(attempt-1), capped at max_interval,
+// then randomized by +/- jitter (a ratio between 0 and 1)
+type RetryPolicy struct {
+	MaxRetries      int     `json:"max_retries"`      // Maximum number of retries (0 = no retry)
+	InitialInterval int     `json:"initial_interval"` // Delay before the first retry in milliseconds, default: 1000
+	MaxInterval     int     `json:"max_interval"`     // Maximum delay in milliseconds, default: 300000 (5 minutes)
+	Multiplier      float64 `json:"multiplier"`       // Backoff multiplier, default: 2
+	Jitter          float64 `json:"jitter"`           // Jitter ratio (0-1), default: 0.2
+}
+
+// Retry policy defaults
+const (
+	DefaultRetryInitialInterval = 1000
+	DefaultRetryMaxInterval     = 5 * 60 * 1000
+	DefaultRetryMultiplier      = 2.0
+	DefaultRetryJitter          = 0.2
+)
+
+// NewRetryPolicy creates a retry policy with default backoff settings
+func NewRetryPolicy(maxRetries int) *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries:      maxRetries,
+		InitialInterval: DefaultRetryInitialInterval,
+		MaxInterval:     DefaultRetryMaxInterval,
+		Multiplier:      DefaultRetryMultiplier,
+		Jitter:          DefaultRetryJitter,
+	}
+}
+
+// Backoff returns the delay before the given retry attempt (1-based)
+func (p *RetryPolicy) Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	initial := float64(p.InitialInterval)
+	if initial <= 0 {
+		initial = DefaultRetryInitialInterval
+	}
+	maxInterval := float64(p.MaxInterval)
+	if maxInterval <= 0 {
+		maxInterval = DefaultRetryMaxInterval
+	}
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = DefaultRetryMultiplier
+	}
+
+	delay := math.Min(initial*math.Pow(multiplier, float64(attempt-1)), maxInterval)
+
+	// Randomize the delay to avoid retry storms
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		delay = delay * (1 + jitter*(rand.Float64()*2-1))
+		delay = math.Min(delay, maxInterval)
+	}
+
+	return time.Duration(delay) * time.Millisecond
+}
+
+// retryPolicy returns the retry policy of an execution
+// The execution options take precedence over the job MaxRetryCount
+func retryPolicy(job *Job, execution *Execution) *RetryPolicy {
+	if execution.ExecutionOptions != nil && execution.ExecutionOptions.Retry != nil {
+		return execution.ExecutionOptions.Retry
+	}
+	if job.MaxRetryCount > 0 {
+		return NewRetryPolicy(job.MaxRetryCount)
+	}
+	return nil
+}
+
+// handleFailure retries a failed execution or moves it to the dead letter status
+// Returns true if a retry has been scheduled
+func (w *Worker) handleFailure(work *WorkRequest, cause error) bool {
+	// Cancelled executions (job stopped) are never retried
+	if work.Context.Err() != nil || work.Execution.Status == "cancelled" {
+		return false
+	}
+
+	policy := retryPolicy(work.Job, work.Execution)
+	if policy == nil || policy.MaxRetries <= 0 {
+		return false
+	}
+
+	if work.Execution.RetryAttempt >= policy.MaxRetries {
+		work.Execution.Status = "dead_letter"
+		work.Execution.Error("Retries exhausted after %d attempts, moved to dead letter: %v", policy.MaxRetries, cause)
+		return false
+	}
+
+	retry, err := w.scheduleRetry(work, policy)
+	if err != nil {
+		work.Execution.Status = "dead_letter"
+		work.Execution.Error("Failed to schedule retry, moved to dead letter: %v", err)
+		return false
+	}
+
+	log.Info("Job %s execution %s will be retried as %s", work.Job.JobID, work.Execution.ExecutionID, retry.ExecutionID)
+	return true
+}
+
+// scheduleRetry creates the retry execution and submits it after the backoff delay
+// The retry is saved as queued with scheduled_at set to the next attempt time,
+// so that RestoreJobsFromDatabase can re-queue it after a restart
+func (w *Worker) scheduleRetry(work *WorkRequest, policy *RetryPolicy) (*Execution, error) {
+	parent := work.Execution
+	attempt := parent.RetryAttempt + 1
+	delay := policy.Backoff(attempt)
+	scheduledAt := time.Now().Add(delay)
+
+	config, configSnapshot, err := copyExecutionConfig(parent)
+	if err != nil {
+		return nil, err
+	}
+
+	triggerSource := "retry"
+	retry := &Execution{
+		JobID:             parent.JobID,
+		Status:            "queued",
+		TriggerCategory:   parent.TriggerCategory,
+		TriggerSource:     &triggerSource,
+		TriggerContext:    parent.TriggerContext,
+		ScheduledAt:       &scheduledAt,
+		RetryAttempt:      attempt,
+		ParentExecutionID: &parent.ExecutionID,
+		RunID:             parent.RunID,
+		TimeoutSeconds:    parent.TimeoutSeconds,
+		ExecutionConfig:   config,
+		ConfigSnapshot:    configSnapshot,
+		ExecutionOptions:  parent.ExecutionOptions,
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
+	}
+
+	if err := SaveExecution(retry); err != nil {
+		return nil, fmt.Errorf("failed to create retry execution: %w", err)
+	}
+
+	parent.Warn("Retry %d/%d scheduled in %v as execution %s", attempt, policy.MaxRetries, delay.Round(time.Millisecond), retry.ExecutionID)
+	retry.Info("Retry %d/%d of execution %s", attempt, policy.MaxRetries, parent.ExecutionID)
+
+	work.Job.submitRetry(retry)
+	return retry, nil
+}
+
+// submitRetry submits a queued retry execution once its scheduled time is reached
+func (j *Job) submitRetry(retry *Execution) {
+	delay := time.Duration(0)
+	if retry.ScheduledAt != nil {
+		delay = time.Until(*retry.ScheduledAt)
+	}
+
+	// Derive the retry context from the job so that stopping the job cancels pending retries
+	if j.ctx == nil {
+		j.ctx, j.cancel = context.WithCancel(context.Background())
+	}
+	ctx, cancel := context.WithCancel(j.ctx)
+
+	j.executionMutex.Lock()
+	if j.executionContexts == nil {
+		j.executionContexts = make(map[string]context.CancelFunc)
+	}
+	j.executionContexts[retry.ExecutionID] = cancel
+	j.executionMutex.Unlock()
+
+	time.AfterFunc(delay, func() {
+		if ctx.Err() != nil {
+			log.Warn("Job %s retry execution %s cancelled before start", j.JobID, retry.ExecutionID)
+			return
+		}
+
+		if err := GetWorkerManager().SubmitJob(ctx, j, retry); err != nil {
+			cancel()
+			j.executionMutex.Lock()
+			delete(j.executionContexts, retry.ExecutionID)
+			j.executionMutex.Unlock()
+
+			retry.Status = "dead_letter"
+			retry.Error("Failed to submit retry, moved to dead letter: %v", err)
+			if saveErr := SaveExecution(retry); saveErr != nil {
+				log.Warn("Failed to save retry execution status (database may be closed): %v", saveErr)
+			}
+		}
+	})
+}
+
+// restoreRetries re-queues the retries that were pending when the server stopped
+// Due retries are submitted immediately, the others at their scheduled time
+func restoreRetries() (int, error) {
+	retries, err := getPendingRetries()
+	if err != nil {
+		return 0, err
+	}
+
+	jobs := map[string]*Job{}
+	restored := 0
+	for _, retry := range retries {
+		job, ok := jobs[retry.JobID]
+		if !ok {
+			job, err = GetJob(retry.JobID)
+			if err != nil {
+				log.Warn("Failed to get job %s of retry execution %s: %v", retry.JobID, retry.ExecutionID, err)
+				continue
+			}
+			jobs[retry.JobID] = job
+		}
+
+		if !job.Enabled || job.Status == "disabled" {
+			continue
+		}
+
+		// Go functions live in memory and are lost on restart
+		if config := retry.ExecutionConfig; config != nil && config.Type == ExecutionTypeFunc {
+			if _, ok := GetFunc(config.FuncID); !ok {
+				retry.Status = "dead_letter"
+				retry.Error("Function %s is not registered after restart, moved to dead letter", config.FuncID)
+				if err := SaveExecution(retry); err != nil {
+					log.Warn("Failed to save retry execution status: %v", err)
+				}
+				continue
+			}
+		}
+
+		job.submitRetry(retry)
+		restored++
+	}
+
+	return restored, nil
+}
+
+// copyExecutionConfig copies the execution config of an execution for a new run
+// A Go function keeps the ID it was registered with
+func copyExecutionConfig(execution *Execution) (*ExecutionConfig, *json.RawMessage, error) {
+	var config *ExecutionConfig
+	if execution.ExecutionConfig != nil {
+		copied := *execution.ExecutionConfig
+		if copied.Type == ExecutionTypeFunc && copied.FuncID == "" {
+			copied.FuncID = execution.ExecutionID
+		}
+		config = &copied
+	}
+
+	configBytes, err := jsoniter.Marshal(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to serialize execution config: %w", err)
+	}
+	configSnapshot := json.RawMessage(configBytes)
+	return config, &configSnapshot, nil
+}
+
+// GetRetryChain returns the execution and all its retries, ordered by retry attempt
+// Any execution of the chain can be given
+func GetRetryChain(executionID string) ([]*Execution, error) {
+	execution, err := GetExecution(executionID, model.QueryParam{})
+	if err != nil {
+		return nil, err
+	}
+
+	executions, err := GetExecutions(execution.JobID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*Execution, len(executions))
+	children := map[string][]*Execution{}
+	for _, item := range executions {
+		byID[item.ExecutionID] = item
+		if item.ParentExecutionID != nil && *item.ParentExecutionID != "" {
+			children[*item.ParentExecutionID] = append(children[*item.ParentExecutionID], item)
+		}
+	}
+
+	// Walk up to the first attempt
+	root := execution
+	visited := map[string]bool{root.ExecutionID: true}
+	for root.ParentExecutionID != nil && *root.ParentExecutionID != "" {
+		parent, ok := byID[*root.ParentExecutionID]
+		if !ok || visited[parent.ExecutionID] {
+			break
+		}
+		visited[parent.ExecutionID] = true
+		root = parent
+	}
+
+	// Walk down through the retries
+	chain := []*Execution{}
+	queue := []*Execution{root}
+	seen := map[string]bool{}
+	for len(queue) > 0 {
+		item := queue[0]
+		queue = queue[1:]
+		if seen[item.ExecutionID] {
+			continue
+		}
+		seen[item.ExecutionID] = true
+		chain = append(chain, item)
+		queue = append(queue, children[item.ExecutionID]...)
+	}
+
+	sort.SliceStable(chain, func(i, j int) bool {
+		return chain[i].RetryAttempt < chain[j].RetryAttempt
+	})
+	return chain, nil
+}
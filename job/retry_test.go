@@ -0,0 +1,259 @@
+package job_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yaoapp/gou/model"
+	"github.com/yaoapp/yao/config"
+	"github.com/yaoapp/yao/job"
+	"github.com/yaoapp/yao/test"
+)
+
+// TestRetryPolicyBackoff tests exponential backoff with cap and jitter
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := &job.RetryPolicy{MaxRetries: 5, InitialInterval: 100, MaxInterval: 1000, Multiplier: 2}
+
+	expected := []time.Duration{100, 200, 400, 800, 1000, 1000}
+	for i, want := range expected {
+		if got := policy.Backoff(i + 1); got != want*time.Millisecond {
+			t.Errorf("Attempt %d: expected %v, got %v", i+1, want*time.Millisecond, got)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		got := policy.Backoff(2)
+		if got < 100*time.Millisecond || got > 300*time.Millisecond {
+			t.Fatalf("Expected jittered delay within [100ms, 300ms], got %v", got)
+		}
+	}
+
+	defaults := job.NewRetryPolicy(3)
+	if defaults.InitialInterval != job.DefaultRetryInitialInterval || defaults.Multiplier != job.DefaultRetryMultiplier {
+		t.Errorf("Unexpected default policy: %+v", defaults)
+	}
+}
+
+// TestRetryDeadLetter tests that a failing execution is retried then moved to dead letter
+func TestRetryDeadLetter(t *testing.T) {
+	test.Prepare(&testing.T{}, config.Conf)
+	defer test.Clean()
+
+	testJob, err := job.OnceAndSave(job.GOROUTINE, map[string]interface{}{
+		"name":        "Test Retry Dead Letter",
+		"description": "Testing retries with backoff and dead letter",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+
+	var calls int32
+	options := job.NewExecutionOptions().WithRetry(&job.RetryPolicy{MaxRetries: 2, InitialInterval: 50, Multiplier: 2})
+	err = testJob.AddFunc(options, "test.retry.fail", func(ctx *job.ExecutionContext) error {
+		atomic.AddInt32(&calls, 1)
+		return fmt.Errorf("intentional test error")
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to add function execution: %v", err)
+	}
+
+	if err := testJob.Push(); err != nil {
+		t.Fatalf("Failed to push job: %v", err)
+	}
+
+	// Wait for the first attempt and both retries
+	time.Sleep(3 * time.Second)
+
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Errorf("Expected 3 calls (1 attempt + 2 retries), got %d", n)
+	}
+
+	executions, err := testJob.GetExecutions()
+	if err != nil {
+		t.Fatalf("Failed to get executions: %v", err)
+	}
+	if len(executions) != 3 {
+		t.Fatalf("Expected 3 executions, got %d", len(executions))
+	}
+
+	chain, err := job.GetRetryChain(executions[0].ExecutionID)
+	if err != nil {
+		t.Fatalf("Failed to get retry chain: %v", err)
+	}
+	if len(chain) != 3 {
+		t.Fatalf("Expected a chain of 3 executions, got %d", len(chain))
+	}
+
+	for i, execution := range chain {
+		if execution.RetryAttempt != i {
+			t.Errorf("Expected retry attempt %d, got %d", i, execution.RetryAttempt)
+		}
+		if i > 0 && (execution.ParentExecutionID == nil || *execution.ParentExecutionID != chain[i-1].ExecutionID) {
+			t.Errorf("Expected retry %d to reference its parent execution", i)
+		}
+	}
+
+	if chain[0].Status != "failed" || chain[1].Status != "failed" {
+		t.Errorf("Expected retried executions to be 'failed', got '%s' and '%s'", chain[0].Status, chain[1].Status)
+	}
+	if chain[2].Status != "dead_letter" {
+		t.Errorf("Expected last execution status 'dead_letter', got '%s'", chain[2].Status)
+	}
+
+	// The function is released once no retry is pending
+	funcID := chain[0].ExecutionID
+	if fn, ok := job.GetFunc(funcID); ok || fn != nil {
+		t.Errorf("Expected function to be removed from global registry after dead letter")
+	}
+}
+
+// TestRetrySucceeds tests that a retry can recover a failed execution
+func TestRetrySucceeds(t *testing.T) {
+	test.Prepare(&testing.T{}, config.Conf)
+	defer test.Clean()
+
+	testJob, err := job.OnceAndSave(job.GOROUTINE, map[string]interface{}{
+		"name":            "Test Retry Succeeds",
+		"description":     "Testing a retry that succeeds",
+		"max_retry_count": 3,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+
+	var calls int32
+	options := job.NewExecutionOptions().WithRetry(&job.RetryPolicy{MaxRetries: 3, InitialInterval: 50})
+	err = testJob.AddFunc(options, "test.retry.flaky", func(ctx *job.ExecutionContext) error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			return fmt.Errorf("first attempt fails")
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to add function execution: %v", err)
+	}
+
+	if err := testJob.Push(); err != nil {
+		t.Fatalf("Failed to push job: %v", err)
+	}
+
+	time.Sleep(2 * time.Second)
+
+	executions, err := testJob.GetExecutions()
+	if err != nil {
+		t.Fatalf("Failed to get executions: %v", err)
+	}
+	if len(executions) != 2 {
+		t.Fatalf("Expected 2 executions, got %d", len(executions))
+	}
+
+	chain, err := job.GetRetryChain(executions[0].ExecutionID)
+	if err != nil {
+		t.Fatalf("Failed to get retry chain: %v", err)
+	}
+	if len(chain) != 2 || chain[1].Status != "completed" || chain[1].RetryAttempt != 1 {
+		t.Errorf("Expected the retry to complete, got %d executions", len(chain))
+	}
+
+	updated, err := job.GetJob(testJob.JobID)
+	if err != nil {
+		t.Fatalf("Failed to get job: %v", err)
+	}
+	if updated.Status != "completed" {
+		t.Errorf("Expected job status 'completed', got '%s'", updated.Status)
+	}
+}
+
+// TestRetryRestore tests that retries pending when the server stopped are re-queued on restore
+func TestRetryRestore(t *testing.T) {
+	test.Prepare(&testing.T{}, config.Conf)
+	defer test.Clean()
+
+	testJob, err := job.OnceAndSave(job.GOROUTINE, map[string]interface{}{
+		"name":        "Test Retry Restore",
+		"description": "Testing pending retries are restored from the database",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+
+	var calls int32
+	err = testJob.AddFunc(nil, "test.retry.restore", func(ctx *job.ExecutionContext) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to add function execution: %v", err)
+	}
+
+	executions, err := testJob.GetExecutions()
+	if err != nil || len(executions) != 1 {
+		t.Fatalf("Failed to get executions: %v", err)
+	}
+	parent := executions[0]
+	parent.Status = "failed"
+	if err := job.SaveExecution(parent); err != nil {
+		t.Fatalf("Failed to save execution: %v", err)
+	}
+
+	// Simulate retries saved before a restart, one due and one scheduled later
+	newRetry := func(scheduledAt time.Time) *job.Execution {
+		config := *parent.ExecutionConfig
+		config.FuncID = parent.ExecutionID
+		snapshot, _ := json.Marshal(config)
+		configSnapshot := json.RawMessage(snapshot)
+		source := "retry"
+		retry := &job.Execution{
+			JobID:             testJob.JobID,
+			Status:            "queued",
+			TriggerCategory:   parent.TriggerCategory,
+			TriggerSource:     &source,
+			ScheduledAt:       &scheduledAt,
+			RetryAttempt:      1,
+			ParentExecutionID: &parent.ExecutionID,
+			ExecutionConfig:   &config,
+			ConfigSnapshot:    &configSnapshot,
+			CreatedAt:         time.Now(),
+			UpdatedAt:         time.Now(),
+		}
+		if err := job.SaveExecution(retry); err != nil {
+			t.Fatalf("Failed to save retry execution: %v", err)
+		}
+		return retry
+	}
+	due := newRetry(time.Now().Add(-time.Minute))
+	later := newRetry(time.Now().Add(time.Second))
+
+	testJob.Status = "queued"
+	if err := job.SaveJob(testJob); err != nil {
+		t.Fatalf("Failed to save job: %v", err)
+	}
+
+	if _, err := job.RestoreJobsFromDatabase(); err != nil {
+		t.Fatalf("Failed to restore jobs: %v", err)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Expected the due retry to run immediately, got %d calls", n)
+	}
+
+	time.Sleep(2 * time.Second)
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("Expected the later retry to run at its scheduled time, got %d calls", n)
+	}
+
+	for _, retry := range []*job.Execution{due, later} {
+		restored, err := job.GetExecution(retry.ExecutionID, model.QueryParam{})
+		if err != nil {
+			t.Fatalf("Failed to get retry execution: %v", err)
+		}
+		if restored.Status != "completed" {
+			t.Errorf("Expected retry execution %s to be completed, got '%s'", retry.ExecutionID, restored.Status)
+		}
+	}
+}
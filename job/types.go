@@ -3,8 +3,11 @@ package job
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"sync"
 	"time"
+
+	jsoniter "github.com/json-iterator/go"
 )
 
 // ScheduleType the schedule type
@@ -64,8 +67,12 @@ const (
 
 // ExecutionOptions holds common execution options
 type ExecutionOptions struct {
-	Priority   int                    `json:"priority"`    // Execution priority (higher = more important)
-	SharedData map[string]interface{} `json:"shared_data"` // Shared data (session, context, etc.)
+	Priority          int                    `json:"priority"`                      // Execution priority (higher = more important)
+	SharedData        map[string]interface{} `json:"shared_data"`                   // Shared data (session, context, etc.)
+	Retry             *RetryPolicy           `json:"retry,omitempty"`               // Retry policy (overrides the job max_retry_count)
+	Key               string                 `json:"key,omitempty"`                 // Unique key in the job, referenced by depends_on
+	DependsOn         []string               `json:"depends_on,omitempty"`          // Keys of the upstream executions
+	OnUpstreamFailure UpstreamFailurePolicy  `json:"on_upstream_failure,omitempty"` // skip | fail, default: skip
 }
 
 // NewExecutionOptions creates a new ExecutionOptions with default values
@@ -88,6 +95,30 @@ func (o *ExecutionOptions) WithSharedData(data map[string]interface{}) *Executio
 	return o
 }
 
+// WithRetry sets the retry policy and returns the options for chaining
+func (o *ExecutionOptions) WithRetry(policy *RetryPolicy) *ExecutionOptions {
+	o.Retry = policy
+	return o
+}
+
+// WithKey sets the execution key and returns the options for chaining
+func (o *ExecutionOptions) WithKey(key string) *ExecutionOptions {
+	o.Key = key
+	return o
+}
+
+// WithDependsOn sets the upstream execution keys and returns the options for chaining
+func (o *ExecutionOptions) WithDependsOn(keys ...string) *ExecutionOptions {
+	o.DependsOn = keys
+	return o
+}
+
+// WithUpstreamFailure sets what happens when an upstream execution fails and returns the options for chaining
+func (o *ExecutionOptions) WithUpstreamFailure(policy UpstreamFailurePolicy) *ExecutionOptions {
+	o.OnUpstreamFailure = policy
+	return o
+}
+
 // AddSharedData adds a key-value pair to shared data and returns the options for chaining
 func (o *ExecutionOptions) AddSharedData(key string, value interface{}) *ExecutionOptions {
 	if o.SharedData == nil {
@@ -108,6 +139,17 @@ type ExecutionContext struct {
 	Args      map[string]interface{} // Function arguments
 }
 
+// SetResult sets the execution result, downstream executions receive it in SharedData["upstream"]
+func (c *ExecutionContext) SetResult(value interface{}) error {
+	data, err := jsoniter.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to serialize execution result: %w", err)
+	}
+	result := json.RawMessage(data)
+	c.Execution.Result = &result
+	return nil
+}
+
 // funcRegistry is a global registry for ExecutionFunc
 // Key is the funcID (execution_id), value is the function
 var funcRegistry = make(map[string]ExecutionFunc)
@@ -195,6 +237,10 @@ type Job struct {
 	// Job-level cancellation for running executions
 	executionContexts map[string]context.CancelFunc // executionID -> cancel function
 	executionMutex    sync.RWMutex
+
+	// Dependency graph of the executions
+	dag      bool
+	dagMutex sync.Mutex
 }
 
 // Category represents job categories for organization
@@ -229,6 +275,7 @@ type Execution struct {
 	ProcessID         *string           `json:"process_id,omitempty"`          // nullable: true
 	RetryAttempt      int               `json:"retry_attempt"`                 // default: 0
 	ParentExecutionID *string           `json:"parent_execution_id,omitempty"` // nullable: true
+	RunID             *string           `json:"run_id,omitempty"`              // nullable: true
 	StartedAt         *time.Time        `json:"started_at,omitempty"`          // nullable: true
 	EndedAt           *time.Time        `json:"ended_at,omitempty"`            // nullable: true
 	TimeoutSeconds    *int              `json:"timeout_seconds,omitempty"`     // nullable: true
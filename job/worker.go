@@ -286,6 +286,7 @@ func (w *Worker) processWork(work *WorkRequest) {
 	work.Execution.Job = work.Job // Set job reference for progress updates
 
 	var err error
+	retrying := false
 	startTime := time.Now()
 
 	// Execute based on mode
@@ -331,6 +332,9 @@ func (w *Worker) processWork(work *WorkRequest) {
 		if err := SaveLog(logEntry); err != nil {
 			log.Warn("Failed to save error log (database may be closed): %v", err)
 		}
+
+		// Retry with backoff, or move to dead letter once retries are exhausted
+		retrying = w.handleFailure(work, err)
 	} else {
 		work.Execution.Status = "completed"
 		work.Execution.Progress = 100
@@ -359,10 +363,36 @@ func (w *Worker) processWork(work *WorkRequest) {
 		log.Warn("Failed to save final execution status (database may be closed): %v", err)
 	}
 
+	// Release and clean up execution context from job
+	work.Job.executionMutex.Lock()
+	if cancel, ok := work.Job.executionContexts[work.Execution.ExecutionID]; ok {
+		cancel()
+		delete(work.Job.executionContexts, work.Execution.ExecutionID)
+	}
+	work.Job.executionMutex.Unlock()
+
+	// Submit the downstream executions, or stop their branches on failure
+	dag := &dagProgress{}
+	if work.Job.dag && !retrying {
+		progress, err := work.Job.advanceDAG(work.Execution)
+		if err != nil {
+			log.Warn("Failed to advance the dependency graph of job %s: %v", work.Job.JobID, err)
+		} else {
+			dag = progress
+		}
+	}
+
 	// Update job status
-	if work.Job.ScheduleType == string(ScheduleTypeOnce) {
+	switch {
+	case retrying:
+		work.Job.Status = "queued" // Waiting for the retry
+	case dag.pending:
+		work.Job.Status = "running" // Waiting for the downstream executions
+	case work.Job.ScheduleType == string(ScheduleTypeOnce) && (work.Execution.Status == "dead_letter" || dag.failed):
+		work.Job.Status = "failed"
+	case work.Job.ScheduleType == string(ScheduleTypeOnce):
 		work.Job.Status = "completed"
-	} else {
+	default:
 		work.Job.Status = "ready" // Ready for next execution
 	}
 	work.Job.CurrentExecutionID = nil
@@ -370,12 +400,11 @@ func (w *Worker) processWork(work *WorkRequest) {
 		log.Warn("Failed to save final job status (database may be closed): %v", err)
 	}
 
-	// Clean up execution context from job
-	work.Job.executionMutex.Lock()
-	if work.Job.executionContexts != nil {
-		delete(work.Job.executionContexts, work.Execution.ExecutionID)
+	// Release the Go function once no retry needs it anymore
+	// Cron jobs keep it for the next tick
+	if !retrying && work.Job.ScheduleType != string(ScheduleTypeCron) {
+		releaseFunc(work.Execution)
 	}
-	work.Job.executionMutex.Unlock()
 
 	log.Debug("Worker %s finished processing job %s", w.ID, work.Job.JobID)
 }
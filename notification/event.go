@@ -0,0 +1,45 @@
+package notification
+
+import (
+	"context"
+
+	"github.com/yaoapp/yao/event"
+	eventtypes "github.com/yaoapp/yao/event/types"
+)
+
+// notificationHandler enables event.Push routing for notification.* events.
+// Delivery to users happens through the subscribers (see Subscribe).
+type notificationHandler struct{}
+
+func (h *notificationHandler) Handle(ctx context.Context, ev *eventtypes.Event, resp chan<- eventtypes.Result) {
+	resp <- eventtypes.Result{}
+}
+
+func (h *notificationHandler) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func init() {
+	event.Register("notification", &notificationHandler{})
+}
+
+// Subscribe subscribes to the notification events of a user (in a team, if given)
+// Events are dropped when ch is full. Call the returned function to unsubscribe, it closes ch.
+func Subscribe(userID string, teamID string, ch chan<- *eventtypes.Event) func() {
+	id := event.Subscribe("notification.*", ch, event.Filter(func(ev *eventtypes.Event) bool {
+		return Matches(ev, userID, teamID)
+	}))
+	return func() { event.Unsubscribe(id) }
+}
+
+// Matches checks if a notification event is for a user (in a team, if given)
+// Read and deleted events of the whole user reach every team of the user
+func Matches(ev *eventtypes.Event, userID string, teamID string) bool {
+	switch payload := ev.Payload.(type) {
+	case *Notification:
+		return payload.UserID == userID && (teamID == "" || payload.TeamID == teamID)
+	case *Change:
+		return payload.UserID == userID && (teamID == "" || payload.TeamID == "" || payload.TeamID == teamID)
+	}
+	return false
+}
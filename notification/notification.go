@@ -0,0 +1,342 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yaoapp/gou/model"
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/xun/capsule"
+	"github.com/yaoapp/xun/dbal/query"
+	"github.com/yaoapp/yao/event"
+)
+
+// Send stores a notification and pushes it to the subscribers of its user
+func Send(ctx context.Context, n *Notification) (*Notification, error) {
+	if n == nil {
+		return nil, fmt.Errorf("notification is required")
+	}
+	if n.UserID == "" {
+		return nil, fmt.Errorf("notification user_id is required")
+	}
+	if n.Type == "" {
+		return nil, fmt.Errorf("notification type is required")
+	}
+
+	mod := model.Select(ModelID)
+	if mod == nil {
+		return nil, fmt.Errorf("model %s not found", ModelID)
+	}
+
+	if n.NotificationID == "" {
+		n.NotificationID = uuid.New().String()
+	}
+	now := time.Now()
+	n.Read = false
+	n.ReadAt = nil
+	n.CreatedAt = &now
+
+	id, err := mod.Create(toRow(n))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create notification: %w", err)
+	}
+	n.ID = int64(id)
+
+	if _, err := event.Push(ctx, EventCreated, n); err != nil {
+		log.Warn("notification %s stored but not pushed: %v", n.NotificationID, err)
+	}
+	return n, nil
+}
+
+// Get returns a notification of a user, nil if not found
+func Get(ctx context.Context, userID string, notificationID string) (*Notification, error) {
+	mod := model.Select(ModelID)
+	if mod == nil {
+		return nil, fmt.Errorf("model %s not found", ModelID)
+	}
+
+	rows, err := mod.Get(model.QueryParam{
+		Wheres: []model.QueryWhere{
+			{Column: "notification_id", Value: notificationID},
+			{Column: "user_id", Value: userID},
+		},
+		Limit: 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return fromRow(rows[0]), nil
+}
+
+// List returns the notifications of a user, newest first
+// With a TeamID, only the notifications of that team are listed
+func List(ctx context.Context, opts *ListOptions) (*ListResult, error) {
+	if opts == nil || opts.UserID == "" {
+		return nil, fmt.Errorf("user_id is required")
+	}
+
+	mod := model.Select(ModelID)
+	if mod == nil {
+		return nil, fmt.Errorf("model %s not found", ModelID)
+	}
+
+	page, pageSize := opts.Page, opts.PageSize
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	wheres := []model.QueryWhere{{Column: "user_id", Value: opts.UserID}}
+	if opts.TeamID != "" {
+		wheres = append(wheres, model.QueryWhere{Column: "team_id", Value: opts.TeamID})
+	}
+	if opts.Type != "" {
+		wheres = append(wheres, model.QueryWhere{Column: "type", Value: opts.Type})
+	}
+	if opts.Source != "" {
+		wheres = append(wheres, model.QueryWhere{Column: "source", Value: opts.Source})
+	}
+	if opts.Unread {
+		wheres = append(wheres, model.QueryWhere{Column: "is_read", Value: false})
+	}
+
+	res, err := mod.Paginate(model.QueryParam{
+		Wheres: wheres,
+		Orders: []model.QueryOrder{{Column: "id", Option: "desc"}},
+	}, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+
+	total := 0
+	switch v := res["total"].(type) {
+	case int64:
+		total = int(v)
+	case int:
+		total = v
+	}
+
+	data := []*Notification{}
+	for _, row := range toRows(res["data"]) {
+		data = append(data, fromRow(row))
+	}
+	return &ListResult{Data: data, Total: total, Page: page, PageSize: pageSize}, nil
+}
+
+// UnreadCount returns the number of unread notifications of a user (in a team, if given)
+func UnreadCount(ctx context.Context, userID string, teamID string) (int, error) {
+	qb, err := newQuery(userID, teamID)
+	if err != nil {
+		return 0, err
+	}
+	count, err := qb.Where("is_read", false).Count()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return int(count), nil
+}
+
+// MarkRead marks notifications of a user as read, all of them (in a team, if given) when ids is empty
+// Returns the number of notifications marked
+func MarkRead(ctx context.Context, userID string, teamID string, ids []string) (int, error) {
+	qb, err := newQuery(userID, teamID)
+	if err != nil {
+		return 0, err
+	}
+	if len(ids) > 0 {
+		qb.WhereIn("notification_id", toValues(ids))
+	}
+
+	now := time.Now()
+	n, err := qb.Where("is_read", false).Update(map[string]interface{}{
+		"is_read":    true,
+		"read_at":    now,
+		"updated_at": now,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+
+	if n > 0 {
+		pushChange(ctx, EventRead, &Change{UserID: userID, TeamID: teamID, IDs: ids, Count: int(n)})
+	}
+	return int(n), nil
+}
+
+// Delete deletes notifications of a user, returns the number of notifications deleted
+func Delete(ctx context.Context, userID string, ids []string) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	qb, err := newQuery(userID, "")
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := qb.WhereIn("notification_id", toValues(ids)).Delete()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete notifications: %w", err)
+	}
+
+	if n > 0 {
+		pushChange(ctx, EventDeleted, &Change{UserID: userID, IDs: ids, Count: int(n)})
+	}
+	return int(n), nil
+}
+
+// newQuery returns a query on the notifications of a user
+func newQuery(userID string, teamID string) (query.Query, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("user_id is required")
+	}
+	mod := model.Select(ModelID)
+	if mod == nil {
+		return nil, fmt.Errorf("model %s not found", ModelID)
+	}
+
+	qb := capsule.Query().Table(mod.MetaData.Table.Name).Where("user_id", userID)
+	if teamID != "" {
+		qb.Where("team_id", teamID)
+	}
+	return qb, nil
+}
+
+// pushChange notifies the subscribers of a user that notifications changed
+func pushChange(ctx context.Context, typ string, change *Change) {
+	if _, err := event.Push(ctx, typ, change); err != nil {
+		log.Warn("notification event %s not pushed: %v", typ, err)
+	}
+}
+
+func toRow(n *Notification) map[string]interface{} {
+	row := map[string]interface{}{
+		"notification_id": n.NotificationID,
+		"user_id":         n.UserID,
+		"type":            n.Type,
+		"is_read":         false,
+	}
+	set := func(key string, value string) {
+		if value != "" {
+			row[key] = value
+		}
+	}
+	set("team_id", n.TeamID)
+	set("title", n.Title)
+	set("content", n.Content)
+	set("link", n.Link)
+	set("source", n.Source)
+	set("source_id", n.SourceID)
+	set("sender_id", n.SenderID)
+	if n.Data != nil {
+		row["data"] = n.Data
+	}
+	return row
+}
+
+func fromRow(row map[string]interface{}) *Notification {
+	n := &Notification{}
+	switch id := row["id"].(type) {
+	case float64:
+		n.ID = int64(id)
+	case int64:
+		n.ID = id
+	case int:
+		n.ID = int64(id)
+	}
+
+	str := func(key string) string {
+		if v, ok := row[key].(string); ok {
+			return v
+		}
+		return ""
+	}
+	n.NotificationID = str("notification_id")
+	n.UserID = str("user_id")
+	n.TeamID = str("team_id")
+	n.Type = str("type")
+	n.Title = str("title")
+	n.Content = str("content")
+	n.Link = str("link")
+	n.Source = str("source")
+	n.SourceID = str("source_id")
+	n.SenderID = str("sender_id")
+	n.Read = toBool(row["is_read"])
+	n.ReadAt = toTime(row["read_at"])
+	n.CreatedAt = toTime(row["created_at"])
+
+	switch data := row["data"].(type) {
+	case map[string]interface{}:
+		n.Data = data
+	case string:
+		if data != "" {
+			_ = json.Unmarshal([]byte(data), &n.Data)
+		}
+	}
+	return n
+}
+
+func toRows(data interface{}) []map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil
+	}
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(raw, &rows); err != nil {
+		return nil
+	}
+	return rows
+}
+
+func toValues(ids []string) []interface{} {
+	values := make([]interface{}, len(ids))
+	for i, id := range ids {
+		values[i] = id
+	}
+	return values
+}
+
+func toBool(v interface{}) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case int64:
+		return b != 0
+	case int:
+		return b != 0
+	case float64:
+		return b != 0
+	case string:
+		return b == "1" || b == "true"
+	}
+	return false
+}
+
+func toTime(v interface{}) *time.Time {
+	switch t := v.(type) {
+	case time.Time:
+		return &t
+	case *time.Time:
+		return t
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02T15:04:05"} {
+			if parsed, err := time.Parse(layout, t); err == nil {
+				return &parsed
+			}
+		}
+	}
+	return nil
+}
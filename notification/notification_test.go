@@ -0,0 +1,122 @@
+package notification_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaoapp/yao/config"
+	eventtypes "github.com/yaoapp/yao/event/types"
+	"github.com/yaoapp/yao/notification"
+	"github.com/yaoapp/yao/test"
+)
+
+func TestNotificationCRUD(t *testing.T) {
+	test.Prepare(t, config.Conf)
+	defer test.Clean()
+
+	ctx := context.Background()
+	userID := "notification_test_user"
+	defer notification.MarkRead(ctx, userID, "", nil)
+
+	first, err := notification.Send(ctx, &notification.Notification{
+		UserID:   userID,
+		TeamID:   "team_1",
+		Type:     "robot.delivery",
+		Title:    "Weekly report",
+		Content:  "## Report",
+		Data:     map[string]interface{}{"execution_id": "exec_1"},
+		Source:   notification.SourceRobot,
+		SourceID: "exec_1",
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, first.NotificationID)
+
+	second, err := notification.Send(ctx, &notification.Notification{UserID: userID, TeamID: "team_2", Type: "system", Title: "Welcome"})
+	require.NoError(t, err)
+	defer notification.Delete(ctx, userID, []string{first.NotificationID, second.NotificationID})
+
+	t.Run("get", func(t *testing.T) {
+		n, err := notification.Get(ctx, userID, first.NotificationID)
+		require.NoError(t, err)
+		require.NotNil(t, n)
+		assert.Equal(t, "Weekly report", n.Title)
+		assert.Equal(t, "exec_1", n.Data["execution_id"])
+		assert.False(t, n.Read)
+
+		// Other users cannot read it
+		n, err = notification.Get(ctx, "someone_else", first.NotificationID)
+		require.NoError(t, err)
+		assert.Nil(t, n)
+	})
+
+	t.Run("list and unread count", func(t *testing.T) {
+		result, err := notification.List(ctx, &notification.ListOptions{UserID: userID})
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Total)
+		assert.Equal(t, second.NotificationID, result.Data[0].NotificationID)
+
+		result, err = notification.List(ctx, &notification.ListOptions{UserID: userID, TeamID: "team_1"})
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Total)
+
+		count, err := notification.UnreadCount(ctx, userID, "")
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("mark read", func(t *testing.T) {
+		n, err := notification.MarkRead(ctx, userID, "", []string{first.NotificationID})
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+
+		count, err := notification.UnreadCount(ctx, userID, "")
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		n, err = notification.MarkRead(ctx, userID, "", nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+
+		result, err := notification.List(ctx, &notification.ListOptions{UserID: userID, Unread: true})
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Total)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		n, err := notification.Delete(ctx, userID, []string{second.NotificationID})
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+
+		got, err := notification.Get(ctx, userID, second.NotificationID)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+}
+
+func TestSendValidation(t *testing.T) {
+	_, err := notification.Send(context.Background(), nil)
+	assert.Error(t, err)
+
+	_, err = notification.Send(context.Background(), &notification.Notification{Type: "system"})
+	assert.Error(t, err)
+
+	_, err = notification.Send(context.Background(), &notification.Notification{UserID: "user_1"})
+	assert.Error(t, err)
+}
+
+func TestMatches(t *testing.T) {
+	created := &eventtypes.Event{Type: notification.EventCreated, Payload: &notification.Notification{UserID: "user_1", TeamID: "team_1"}}
+	read := &eventtypes.Event{Type: notification.EventRead, Payload: &notification.Change{UserID: "user_1"}}
+
+	assert.True(t, notification.Matches(created, "user_1", ""))
+	assert.True(t, notification.Matches(created, "user_1", "team_1"))
+	assert.False(t, notification.Matches(created, "user_1", "team_2"))
+	assert.False(t, notification.Matches(created, "user_2", ""))
+
+	assert.True(t, notification.Matches(read, "user_1", "team_2"))
+	assert.False(t, notification.Matches(read, "user_2", ""))
+
+	assert.False(t, notification.Matches(&eventtypes.Event{Type: notification.EventCreated, Payload: "x"}, "user_1", ""))
+}
@@ -0,0 +1,65 @@
+package notification
+
+import "time"
+
+// ModelID is the model that stores the notifications
+const ModelID = "__yao.notification"
+
+// Event types pushed to the event bus, subscribers get them in real time
+const (
+	EventCreated = "notification.created" // payload: *Notification
+	EventRead    = "notification.read"    // payload: *Change
+	EventDeleted = "notification.deleted" // payload: *Change
+)
+
+// Sources of the notifications
+const (
+	SourceRobot  = "robot"
+	SourceSystem = "system"
+)
+
+// Notification is an in-app notification of a user
+type Notification struct {
+	ID             int64                  `json:"id,omitempty"`
+	NotificationID string                 `json:"notification_id"`
+	UserID         string                 `json:"user_id"`           // recipient
+	TeamID         string                 `json:"team_id,omitempty"` // empty = personal
+	Type           string                 `json:"type"`              // robot.delivery, system, etc.
+	Title          string                 `json:"title,omitempty"`
+	Content        string                 `json:"content,omitempty"` // markdown
+	Link           string                 `json:"link,omitempty"`
+	Data           map[string]interface{} `json:"data,omitempty"`
+	Source         string                 `json:"source,omitempty"`    // robot, system, etc.
+	SourceID       string                 `json:"source_id,omitempty"` // e.g. execution ID
+	SenderID       string                 `json:"sender_id,omitempty"` // e.g. robot member ID
+	Read           bool                   `json:"is_read"`
+	ReadAt         *time.Time             `json:"read_at,omitempty"`
+	CreatedAt      *time.Time             `json:"created_at,omitempty"`
+}
+
+// Change is the payload of the read and deleted events
+type Change struct {
+	UserID string   `json:"user_id"`
+	TeamID string   `json:"team_id,omitempty"`
+	IDs    []string `json:"notification_ids,omitempty"` // empty = all notifications of the user (and team)
+	Count  int      `json:"count"`
+}
+
+// ListOptions filters the notifications of a user
+type ListOptions struct {
+	UserID   string `json:"user_id"`
+	TeamID   string `json:"team_id,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Unread   bool   `json:"unread,omitempty"` // only unread notifications
+	Page     int    `json:"page,omitempty"`
+	PageSize int    `json:"pagesize,omitempty"`
+}
+
+// ListResult is a page of notifications
+type ListResult struct {
+	Data     []*Notification `json:"data"`
+	Total    int             `json:"total"`
+	Page     int             `json:"page"`
+	PageSize int             `json:"pagesize"`
+}
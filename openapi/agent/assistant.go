@@ -10,6 +10,8 @@ import (
 	"github.com/yaoapp/yao/agent"
 	assistantPkg "github.com/yaoapp/yao/agent/assistant"
 	agenttypes "github.com/yaoapp/yao/agent/store/types"
+	"github.com/yaoapp/yao/audit"
+	openapiAudit "github.com/yaoapp/yao/openapi/audit"
 	"github.com/yaoapp/yao/openapi/oauth/authorized"
 	"github.com/yaoapp/yao/openapi/oauth/types"
 	"github.com/yaoapp/yao/openapi/response"
@@ -160,6 +162,7 @@ func ListAssistants(c *gin.Context) {
 
 	// Apply permission-based filtering (Scope filtering)
 	filter.QueryFilter = AuthQueryFilter(c, authInfo)
+	filter.Access = AuthAccessRules(c, authInfo)
 
 	// Use the existing GetAssistants method from agent.Store
 	result, err := agentInstance.Store.GetAssistants(filter, locale)
@@ -348,6 +351,7 @@ func ListAssistantTags(c *gin.Context) {
 
 	// Apply permission-based filtering (Scope filtering)
 	filter.QueryFilter = AuthQueryFilter(c, authInfo)
+	filter.Access = AuthAccessRules(c, authInfo)
 
 	// Get tags with filter
 	tags, err := agentInstance.Store.GetAssistantTags(filter, locale)
@@ -414,6 +418,7 @@ func CreateAssistant(c *gin.Context) {
 
 	// Save assistant using Store
 	id, err := agentInstance.Store.SaveAssistant(model)
+	auditAssistant(c, audit.OpAssistantCreate, id, model.YaoTeamID, nil, assistantData, err)
 	if err != nil {
 		log.Error("Failed to create assistant: %v", err)
 		errorResp := &response.ErrorResponse{
@@ -511,8 +516,13 @@ func UpdateAssistant(c *gin.Context) {
 		updateData["__yao_updated_by"] = scope.UpdatedBy
 	}
 
-	// Update assistant using Store
+	// Update assistant using Store, keeping the previous state for the audit log
+	var before map[string]interface{}
+	if existing, getErr := agentInstance.Store.GetAssistant(assistantID, nil); getErr == nil {
+		before = audit.ToMap(existing)
+	}
 	err = agentInstance.Store.UpdateAssistant(assistantID, updateData)
+	auditAssistant(c, audit.OpAssistantUpdate, assistantID, "", before, updateData, err)
 	if err != nil {
 		log.Error("Failed to update assistant %s: %v", assistantID, err)
 		// Check if it's a "not found" error
@@ -669,3 +679,27 @@ func checkAssistantPermission(authInfo *types.AuthorizedInfo, assistantID string
 
 	return false, fmt.Errorf("no permission to access assistant: %s", assistantID)
 }
+
+// auditAssistant records a change of an assistant config, err is nil on success
+// For updates, only the updated fields are kept from before.
+func auditAssistant(c *gin.Context, operation string, assistantID string, teamID string, before map[string]interface{}, after map[string]interface{}, err error) {
+	e := &audit.Entry{
+		Operation:      operation,
+		Category:       audit.CategoryConfig,
+		TeamID:         teamID,
+		TargetResource: assistantID,
+		ResourceType:   "assistant",
+		Success:        err == nil,
+		After:          after,
+	}
+	if before != nil {
+		e.Before = map[string]interface{}{}
+		for key := range after {
+			e.Before[key] = before[key]
+		}
+	}
+	if err != nil {
+		e.ErrorMessage = err.Error()
+	}
+	openapiAudit.Log(c, e)
+}
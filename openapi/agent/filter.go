@@ -127,6 +127,36 @@ func AuthQueryFilter(c *gin.Context, authInfo *types.AuthorizedInfo) func(query.
 	return nil
 }
 
+// AuthAccessRules returns the permission constraints of AuthQueryFilter as access rules
+// Stores that cannot evaluate query callbacks (redis, mongo) filter with these rules,
+// so callers setting QueryFilter should set Access from this function as well
+func AuthAccessRules(c *gin.Context, authInfo *types.AuthorizedInfo) []agenttypes.AccessRule {
+	if authInfo == nil {
+		return nil
+	}
+
+	scope := authInfo.AccessScope()
+
+	// Team only - public records, own team records and team shared records
+	if authInfo.Constraints.TeamOnly && authorized.IsTeamMember(c) {
+		return []agenttypes.AccessRule{
+			{Public: true},
+			{TeamID: scope.TeamID, CreatedBy: scope.CreatedBy},
+			{TeamID: scope.TeamID, Share: "team"},
+		}
+	}
+
+	// Owner only - public records and own personal records
+	if authInfo.Constraints.OwnerOnly && authInfo.UserID != "" {
+		return []agenttypes.AccessRule{
+			{Public: true},
+			{NoTeam: true, CreatedBy: scope.CreatedBy},
+		}
+	}
+
+	return nil
+}
+
 // FilterBuiltInFields filters sensitive fields for built-in assistants in a list
 // For built-in assistants, code-level fields (prompts, prompt_presets, workflow, kb, mcp, options, source) should be cleared
 func FilterBuiltInFields(assistants []*agenttypes.AssistantModel) {
@@ -54,6 +54,7 @@ func GetModels(c *gin.Context) {
 
 	// Apply permission-based filtering (Scope filtering)
 	filter.QueryFilter = AuthQueryFilter(c, authInfo)
+	filter.Access = AuthAccessRules(c, authInfo)
 
 	assistantsResponse, err := agentInstance.Store.GetAssistants(filter, locale)
 	if err != nil {
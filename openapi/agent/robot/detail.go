@@ -7,6 +7,8 @@ import (
 	"github.com/yaoapp/kun/log"
 	robotapi "github.com/yaoapp/yao/agent/robot/api"
 	robottypes "github.com/yaoapp/yao/agent/robot/types"
+	"github.com/yaoapp/yao/audit"
+	openapiAudit "github.com/yaoapp/yao/openapi/audit"
 	"github.com/yaoapp/yao/openapi/oauth/authorized"
 	"github.com/yaoapp/yao/openapi/response"
 )
@@ -220,6 +222,7 @@ func CreateRobot(c *gin.Context) {
 
 	// Call API layer
 	robotResp, err := robotapi.CreateRobot(ctx, apiReq)
+	auditRobot(c, audit.OpRobotCreate, req.MemberID, req.TeamID, nil, robotResp, err)
 	if err != nil {
 		log.Error("Failed to create robot: %v", err)
 
@@ -318,6 +321,7 @@ func UpdateRobot(c *gin.Context) {
 
 	// Call API layer
 	robotResp, err := robotapi.UpdateRobot(ctx, robotID, apiReq)
+	auditRobot(c, audit.OpRobotUpdate, robotID, existingRobot.YaoTeamID, existingRobot, robotResp, err)
 	if err != nil {
 		log.Error("Failed to update robot %s: %v", robotID, err)
 
@@ -394,6 +398,7 @@ func DeleteRobot(c *gin.Context) {
 
 	// Call API layer
 	err = robotapi.RemoveRobot(ctx, robotID)
+	auditRobot(c, audit.OpRobotDelete, robotID, existingRobot.YaoTeamID, existingRobot, nil, err)
 	if err != nil {
 		log.Error("Failed to delete robot %s: %v", robotID, err)
 
@@ -430,3 +435,24 @@ func DeleteRobot(c *gin.Context) {
 		"deleted":   true,
 	})
 }
+
+// auditRobot records a change of a robot config, err is nil on success
+func auditRobot(c *gin.Context, operation string, robotID string, teamID string, before interface{}, after interface{}, err error) {
+	e := &audit.Entry{
+		Operation:      operation,
+		Category:       audit.CategoryConfig,
+		TeamID:         teamID,
+		TargetResource: robotID,
+		ResourceType:   "robot",
+		Success:        err == nil,
+		Before:         audit.ToMap(before),
+		After:          audit.ToMap(after),
+	}
+	if operation == audit.OpRobotDelete {
+		e.Severity = audit.SeverityHigh
+	}
+	if err != nil {
+		e.ErrorMessage = err.Error()
+	}
+	openapiAudit.Log(c, e)
+}
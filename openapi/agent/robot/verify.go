@@ -10,13 +10,14 @@ import (
 	"github.com/yaoapp/yao/integrations/dingtalk"
 	"github.com/yaoapp/yao/integrations/discord"
 	"github.com/yaoapp/yao/integrations/feishu"
+	"github.com/yaoapp/yao/integrations/slack"
 	"github.com/yaoapp/yao/integrations/telegram"
 	"github.com/yaoapp/yao/openapi/response"
 )
 
 // VerifyIntegrationRequest — POST body for credential verification.
 type VerifyIntegrationRequest struct {
-	Provider string         `json:"provider" binding:"required"` // telegram | feishu | dingtalk | discord
+	Provider string         `json:"provider" binding:"required"` // telegram | feishu | dingtalk | discord | slack
 	Config   map[string]any `json:"config" binding:"required"`
 }
 
@@ -55,6 +56,8 @@ func VerifyIntegration(c *gin.Context) {
 		resp = verifyDingtalk(ctx, req.Config)
 	case "discord":
 		resp = verifyDiscord(ctx, req.Config)
+	case "slack":
+		resp = verifySlack(ctx, req.Config)
 	default:
 		response.RespondWithError(c, response.StatusBadRequest, &response.ErrorResponse{
 			Code:             response.ErrInvalidRequest.Code,
@@ -177,3 +180,34 @@ func verifyDiscord(ctx context.Context, cfg map[string]any) VerifyIntegrationRes
 		},
 	}
 }
+
+func verifySlack(ctx context.Context, cfg map[string]any) VerifyIntegrationResponse {
+	botToken := str(cfg, "bot_token")
+	if botToken == "" {
+		return VerifyIntegrationResponse{Valid: false, Error: "bot_token is required"}
+	}
+
+	bot := slack.NewBot(botToken, str(cfg, "app_token"), str(cfg, "signing_secret"))
+	auth, err := bot.AuthTest(ctx)
+	if err != nil {
+		return VerifyIntegrationResponse{Valid: false, Error: err.Error()}
+	}
+
+	// The app token is optional, it is only checked when Socket Mode is used
+	if bot.AppToken() != "" {
+		if _, err := bot.OpenConnection(ctx); err != nil {
+			return VerifyIntegrationResponse{Valid: false, Error: "app_token: " + err.Error()}
+		}
+	}
+
+	return VerifyIntegrationResponse{
+		Valid: true,
+		Info: map[string]any{
+			"id":      auth.UserID,
+			"bot_id":  auth.BotID,
+			"name":    auth.User,
+			"team":    auth.Team,
+			"team_id": auth.TeamID,
+		},
+	}
+}
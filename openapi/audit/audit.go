@@ -1,3 +1,247 @@
 package audit
 
-// Audit Log
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/yao/audit"
+	"github.com/yaoapp/yao/openapi/oauth/acl"
+	"github.com/yaoapp/yao/openapi/oauth/authorized"
+	oauthTypes "github.com/yaoapp/yao/openapi/oauth/types"
+	"github.com/yaoapp/yao/openapi/response"
+)
+
+func init() {
+	acl.Register(
+		&acl.ScopeDefinition{
+			Name:        "audit:read:all",
+			Description: "Read and export the whole audit log, verify its hash chain",
+			Endpoints:   []string{"GET /audit/logs", "GET /audit/logs/:eventID", "GET /audit/export", "GET /audit/verify"},
+		},
+		&acl.ScopeDefinition{
+			Name:        "audit:read:team",
+			Description: "Read and export the audit log of the current team",
+			Team:        true,
+			Endpoints:   []string{"GET /audit/logs", "GET /audit/logs/:eventID", "GET /audit/export"},
+		},
+	)
+}
+
+// Attach attaches the audit log handlers to the router
+func Attach(group *gin.RouterGroup, oauth oauthTypes.OAuth) {
+	group.Use(oauth.Guard)
+
+	group.GET("/logs", ListLogs)
+	group.GET("/logs/:eventID", GetLog)
+	group.GET("/export", ExportLogs)
+	group.GET("/verify", VerifyLogs)
+}
+
+// FromGin fills the actor and request fields of an entry from the request
+func FromGin(c *gin.Context, e *audit.Entry) *audit.Entry {
+	if e.Source == "" {
+		e.Source = "api"
+	}
+	if e.ClientIP == "" {
+		e.ClientIP = c.ClientIP()
+	}
+	if e.UserAgent == "" {
+		e.UserAgent = c.Request.UserAgent()
+	}
+	if e.RequestID == "" {
+		e.RequestID = c.GetHeader("X-Request-ID")
+	}
+	if authInfo := authorized.GetInfo(c); authInfo != nil {
+		if e.UserID == "" {
+			e.UserID = authInfo.UserID
+		}
+		if e.TeamID == "" {
+			e.TeamID = authInfo.TeamID
+		}
+		if e.SessionID == "" {
+			e.SessionID = authInfo.SessionID
+		}
+	}
+	return e
+}
+
+// Log records an audit entry for the request, errors are logged and never fail the request
+func Log(c *gin.Context, e *audit.Entry) {
+	audit.Log(c.Request.Context(), FromGin(c, e))
+}
+
+// ListLogs lists the audit log, newest first
+// GET /api/__yao/openapi/v1/audit/logs?user_id=&team_id=&operation=&category=&resource=&resource_type=&success=&from=&to=&page=1&pagesize=20
+// from/to are RFC3339 or Unix milliseconds
+func ListLogs(c *gin.Context) {
+	q, ok := parseQuery(c)
+	if !ok {
+		return
+	}
+
+	result, err := audit.List(c.Request.Context(), q)
+	if err != nil {
+		respondServerError(c, "Failed to list audit logs", err)
+		return
+	}
+	response.RespondWithSuccess(c, response.StatusOK, result)
+}
+
+// GetLog returns an audit entry
+// GET /api/__yao/openapi/v1/audit/logs/:eventID
+func GetLog(c *gin.Context) {
+	e, err := audit.Get(c.Request.Context(), c.Param("eventID"))
+	if err != nil {
+		respondServerError(c, "Failed to get audit log", err)
+		return
+	}
+	if e == nil || !canAccess(authorized.GetInfo(c), e) {
+		response.RespondWithError(c, response.StatusNotFound, &response.ErrorResponse{
+			Code:             response.ErrInvalidRequest.Code,
+			ErrorDescription: "Audit log not found",
+		})
+		return
+	}
+	response.RespondWithSuccess(c, response.StatusOK, e)
+}
+
+// ExportLogs downloads the audit log as CSV or JSON lines, oldest first
+// GET /api/__yao/openapi/v1/audit/export?format=csv|jsonl&... (same filters as /logs)
+func ExportLogs(c *gin.Context) {
+	q, ok := parseQuery(c)
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", audit.FormatCSV)
+	contentType := "text/csv"
+	switch format {
+	case audit.FormatCSV:
+	case audit.FormatJSONL:
+		contentType = "application/x-ndjson"
+	default:
+		respondBadRequest(c, "Unsupported format: "+format)
+		return
+	}
+
+	filename := fmt.Sprintf("audit-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(response.StatusOK)
+
+	// Headers are already sent, a failure can only be logged
+	if _, err := audit.Export(c.Request.Context(), q, c.Writer, format); err != nil {
+		log.Error("[OpenAPI Audit] Failed to export audit logs: %v", err)
+	}
+}
+
+// VerifyLogs checks the hash chain of the whole audit log
+// GET /api/__yao/openapi/v1/audit/verify
+func VerifyLogs(c *gin.Context) {
+	authInfo := authorized.GetInfo(c)
+	if authInfo == nil || authInfo.Constraints.TeamOnly || authInfo.Constraints.OwnerOnly {
+		response.RespondWithError(c, response.StatusForbidden, &response.ErrorResponse{
+			Code:             response.ErrAccessDenied.Code,
+			ErrorDescription: "Verifying the audit log requires unrestricted access",
+		})
+		return
+	}
+
+	result, err := audit.Verify(c.Request.Context())
+	if err != nil {
+		respondServerError(c, "Failed to verify audit logs", err)
+		return
+	}
+	response.RespondWithSuccess(c, response.StatusOK, result)
+}
+
+// parseQuery reads the filters of the request and restricts them to what the caller may see
+func parseQuery(c *gin.Context) (*audit.Query, bool) {
+	q := &audit.Query{
+		UserID:         c.Query("user_id"),
+		TeamID:         c.Query("team_id"),
+		Operation:      c.Query("operation"),
+		Category:       c.Query("category"),
+		TargetResource: c.Query("resource"),
+		ResourceType:   c.Query("resource_type"),
+	}
+	if page, err := strconv.Atoi(c.Query("page")); err == nil {
+		q.Page = page
+	}
+	if pageSize, err := strconv.Atoi(c.Query("pagesize")); err == nil {
+		q.PageSize = pageSize
+	}
+	if v := c.Query("success"); v != "" {
+		success, err := strconv.ParseBool(v)
+		if err != nil {
+			respondBadRequest(c, "Invalid success: "+v)
+			return nil, false
+		}
+		q.Success = &success
+	}
+
+	var err error
+	if q.From, err = parseTime(c.Query("from")); err != nil {
+		respondBadRequest(c, "Invalid from: "+err.Error())
+		return nil, false
+	}
+	if q.To, err = parseTime(c.Query("to")); err != nil {
+		respondBadRequest(c, "Invalid to: "+err.Error())
+		return nil, false
+	}
+
+	// Team and owner constraints override the requested filters
+	authInfo := authorized.GetInfo(c)
+	if authInfo != nil {
+		if authInfo.Constraints.TeamOnly {
+			q.TeamID = authInfo.TeamID
+		}
+		if authInfo.Constraints.OwnerOnly {
+			q.UserID = authInfo.UserID
+		}
+	}
+	return q, true
+}
+
+// parseTime accepts RFC3339 or Unix milliseconds, empty is the zero time
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.UnixMilli(ms), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// canAccess checks an entry against the team and owner constraints of the caller
+func canAccess(authInfo *oauthTypes.AuthorizedInfo, e *audit.Entry) bool {
+	if authInfo == nil {
+		return false
+	}
+	if authInfo.Constraints.TeamOnly && e.TeamID != authInfo.TeamID {
+		return false
+	}
+	if authInfo.Constraints.OwnerOnly && e.UserID != authInfo.UserID {
+		return false
+	}
+	return true
+}
+
+func respondBadRequest(c *gin.Context, message string) {
+	response.RespondWithError(c, response.StatusBadRequest, &response.ErrorResponse{
+		Code:             response.ErrInvalidRequest.Code,
+		ErrorDescription: message,
+	})
+}
+
+func respondServerError(c *gin.Context, message string, err error) {
+	log.Error("[OpenAPI Audit] %s: %v", message, err)
+	response.RespondWithError(c, response.StatusInternalServerError, &response.ErrorResponse{
+		Code:             response.ErrServerError.Code,
+		ErrorDescription: message + ": " + err.Error(),
+	})
+}
@@ -510,6 +510,11 @@ func buildChatFilter(c *gin.Context, authInfo *oauthtypes.AuthorizedInfo) storet
 						})
 				})
 			}
+			// Same constraints for stores that cannot evaluate QueryFilter
+			filter.Access = []storetypes.AccessRule{
+				{CreatedBy: authInfo.UserID},
+				{TeamID: authInfo.TeamID, Share: "team"},
+			}
 			// Clear direct filters since we're using QueryFilter
 			filter.UserID = ""
 			filter.TeamID = ""
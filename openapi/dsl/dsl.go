@@ -5,8 +5,11 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yaoapp/yao/audit"
 	"github.com/yaoapp/yao/dsl"
 	"github.com/yaoapp/yao/dsl/types"
+	openapiAudit "github.com/yaoapp/yao/openapi/audit"
+	"github.com/yaoapp/yao/openapi/oauth/authorized"
 	oauthTypes "github.com/yaoapp/yao/openapi/oauth/types"
 )
 
@@ -30,6 +33,12 @@ func Attach(group *gin.RouterGroup, oauth oauthTypes.OAuth) {
 	group.PUT("/update/:type", update)
 	group.DELETE("/delete/:type/:id", delete)
 
+	// DSL Revision history
+	group.GET("/revisions/:type/:id", revisions)
+	group.GET("/revision/:type/:id/:revision", revision)
+	group.GET("/diff/:type/:id", diff)
+	group.POST("/rollback/:type/:id", rollback)
+
 	// DSL Load management
 	group.POST("/load/:type", load)
 	group.POST("/unload/:type", unload)
@@ -207,7 +216,9 @@ func create(c *gin.Context) {
 		return
 	}
 
+	options.Author = author(c)
 	err = dslManager.Create(c.Request.Context(), &options)
+	auditDSL(c, audit.OpDSLCreate, dslType, options.ID, nil, map[string]interface{}{"source": options.Source, "store": options.Store}, err)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -242,7 +253,17 @@ func update(c *gin.Context) {
 		return
 	}
 
+	var before map[string]interface{}
+	if sourceCode, sourceErr := dslManager.Source(c.Request.Context(), options.ID); sourceErr == nil {
+		before = map[string]interface{}{"source": sourceCode}
+	}
+	after := map[string]interface{}{"source": options.Source}
+	if options.Info != nil {
+		after["info"] = audit.ToMap(options.Info)
+	}
+	options.Author = author(c)
 	err = dslManager.Update(c.Request.Context(), &options)
+	auditDSL(c, audit.OpDSLUpdate, dslType, options.ID, before, after, err)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -274,7 +295,13 @@ func delete(c *gin.Context) {
 	// Try to bind JSON body if provided
 	c.ShouldBindJSON(&options)
 
+	var before map[string]interface{}
+	if sourceCode, sourceErr := dslManager.Source(c.Request.Context(), id); sourceErr == nil {
+		before = map[string]interface{}{"source": sourceCode}
+	}
+	options.Author = author(c)
 	err = dslManager.Delete(c.Request.Context(), &options)
+	auditDSL(c, audit.OpDSLDelete, dslType, id, before, nil, err)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -283,6 +310,143 @@ func delete(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "DSL deleted successfully"})
 }
 
+// List the revisions of a DSL
+func revisions(c *gin.Context) {
+	dslType := types.Type(c.Param("type"))
+	id := c.Param("id")
+
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "DSL ID is required"})
+		return
+	}
+
+	dslManager, err := dsl.New(dslType)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid DSL type: " + string(dslType)})
+		return
+	}
+
+	list, err := dslManager.Revisions(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// Get a revision of a DSL with its source
+func revision(c *gin.Context) {
+	dslType := types.Type(c.Param("type"))
+	id := c.Param("id")
+
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "DSL ID is required"})
+		return
+	}
+
+	number, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision: " + c.Param("revision")})
+		return
+	}
+
+	dslManager, err := dsl.New(dslType)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid DSL type: " + string(dslType)})
+		return
+	}
+
+	rev, err := dslManager.Revision(c.Request.Context(), id, number)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rev)
+}
+
+// Diff two revisions of a DSL
+func diff(c *gin.Context) {
+	dslType := types.Type(c.Param("type"))
+	id := c.Param("id")
+
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "DSL ID is required"})
+		return
+	}
+
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from revision: " + c.Query("from")})
+		return
+	}
+
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to revision: " + c.Query("to")})
+		return
+	}
+
+	dslManager, err := dsl.New(dslType)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid DSL type: " + string(dslType)})
+		return
+	}
+
+	result, err := dslManager.Diff(c.Request.Context(), id, from, to)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Rollback a DSL to a revision
+func rollback(c *gin.Context) {
+	dslType := types.Type(c.Param("type"))
+	id := c.Param("id")
+
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "DSL ID is required"})
+		return
+	}
+
+	dslManager, err := dsl.New(dslType)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid DSL type: " + string(dslType)})
+		return
+	}
+
+	var options types.RollbackOptions
+	if err := c.ShouldBindJSON(&options); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	if options.Revision <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Revision is required"})
+		return
+	}
+
+	options.ID = id
+	options.Author = author(c)
+
+	var before map[string]interface{}
+	if sourceCode, sourceErr := dslManager.Source(c.Request.Context(), id); sourceErr == nil {
+		before = map[string]interface{}{"source": sourceCode}
+	}
+	err = dslManager.Rollback(c.Request.Context(), &options)
+	auditDSL(c, audit.OpDSLRollback, dslType, id, before, map[string]interface{}{"revision": options.Revision}, err)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "DSL rolled back successfully"})
+}
+
 // Load a DSL
 func load(c *gin.Context) {
 	dslType := types.Type(c.Param("type"))
@@ -440,3 +604,36 @@ func validate(c *gin.Context) {
 		"messages": messages,
 	})
 }
+
+// auditDSL records a change of a DSL, err is nil on success
+func auditDSL(c *gin.Context, operation string, dslType types.Type, id string, before map[string]interface{}, after map[string]interface{}, err error) {
+	e := &audit.Entry{
+		Operation:      operation,
+		Category:       audit.CategoryConfig,
+		TargetResource: id,
+		ResourceType:   string(dslType),
+		Success:        err == nil,
+		Before:         before,
+		After:          after,
+	}
+	if operation == audit.OpDSLDelete {
+		e.Severity = audit.SeverityHigh
+	}
+	if err != nil {
+		e.ErrorMessage = err.Error()
+	}
+	openapiAudit.Log(c, e)
+}
+
+// author returns the authorized user recorded in the DSL revisions
+func author(c *gin.Context) *types.Author {
+	info := authorized.GetInfo(c)
+	id := info.UserID
+	if id == "" {
+		id = info.Subject
+	}
+	if id == "" {
+		return nil
+	}
+	return &types.Author{ID: id}
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/yaoapp/gou/model"
 	"github.com/yaoapp/yao/attachment"
+	"github.com/yaoapp/yao/audit"
+	openapiAudit "github.com/yaoapp/yao/openapi/audit"
 	"github.com/yaoapp/yao/openapi/oauth/authorized"
 	"github.com/yaoapp/yao/openapi/oauth/types"
 	"github.com/yaoapp/yao/openapi/response"
@@ -353,6 +355,20 @@ func delete(c *gin.Context) {
 
 	// Delete the file (permission already checked)
 	err = manager.Delete(c.Request.Context(), fileID)
+	auditEntry := &audit.Entry{
+		Operation:      audit.OpFileDelete,
+		Category:       audit.CategoryData,
+		Severity:       audit.SeverityHigh,
+		TargetResource: fileID,
+		ResourceType:   "file",
+		Success:        err == nil,
+		Before:         audit.ToMap(fileInfo),
+		Details:        map[string]interface{}{"uploader": uploaderID},
+	}
+	if err != nil {
+		auditEntry.ErrorMessage = err.Error()
+	}
+	openapiAudit.Log(c, auditEntry)
 	if err != nil {
 		errorResp := &response.ErrorResponse{
 			Code:             response.ErrServerError.Code,
@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"sync"
 
 	"github.com/gin-gonic/gin"
 	"github.com/yaoapp/kun/log"
@@ -22,6 +23,33 @@ type WebhookPayload struct {
 	Query    map[string]string `json:"query,omitempty"`
 }
 
+// Responder answers a webhook request synchronously, for platforms that expect
+// a response body (e.g. the Slack url_verification challenge). It returns true
+// when it has written the response; the payload is then not pushed as an event.
+type Responder func(c *gin.Context, payload *WebhookPayload) bool
+
+var (
+	respondersMu sync.RWMutex
+	responders   = map[string]Responder{}
+)
+
+// RegisterResponder sets the Responder of a provider, nil removes it.
+func RegisterResponder(provider string, fn Responder) {
+	respondersMu.Lock()
+	defer respondersMu.Unlock()
+	if fn == nil {
+		delete(responders, provider)
+		return
+	}
+	responders[provider] = fn
+}
+
+func getResponder(provider string) Responder {
+	respondersMu.RLock()
+	defer respondersMu.RUnlock()
+	return responders[provider]
+}
+
 // Attach registers the integrations webhook endpoints.
 // These are public endpoints (no OAuth) since external platforms push here.
 func Attach(group *gin.RouterGroup) {
@@ -31,7 +59,8 @@ func Attach(group *gin.RouterGroup) {
 
 // webhookHandler receives webhooks from external platforms, packs the raw
 // request into a WebhookPayload, and pushes an event for async processing.
-// It returns HTTP 200 immediately — subscribers handle the rest.
+// It returns HTTP 200 immediately — subscribers handle the rest — unless a
+// Responder of the provider answers the request itself.
 func webhookHandler(c *gin.Context) {
 	provider := c.Param("provider")
 	appID := c.Param("app_id")
@@ -64,6 +93,10 @@ func webhookHandler(c *gin.Context) {
 	payload.Headers = flattenHeaders(c.Request.Header)
 	payload.Query = flattenQuery(c.Request.URL.Query())
 
+	if respond := getResponder(provider); respond != nil && respond(c, &payload) {
+		return
+	}
+
 	c.Status(http.StatusOK)
 
 	eventType := "integration.webhook." + provider
@@ -242,6 +242,14 @@ func GetExecutionProgress(c *gin.Context) {
 		"retry_attempt": execution.RetryAttempt,
 	}
 
+	// Add retry info if this is a retry
+	if execution.ParentExecutionID != nil {
+		response["parent_execution_id"] = execution.ParentExecutionID
+	}
+	if execution.ScheduledAt != nil {
+		response["scheduled_at"] = execution.ScheduledAt
+	}
+
 	// Add error info if available
 	if execution.ErrorInfo != nil {
 		response["error_info"] = execution.ErrorInfo
@@ -255,6 +263,59 @@ func GetExecutionProgress(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// ListExecutionRetries lists the retry history of an execution (first attempt and all retries)
+func ListExecutionRetries(c *gin.Context) {
+	// Get authorized information
+	authInfo := authorized.GetInfo(c)
+
+	executionID := c.Param("executionID")
+	if executionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "execution_id is required"})
+		return
+	}
+
+	// Get the execution
+	execution, err := job.GetExecution(executionID, model.QueryParam{})
+	if err != nil {
+		log.Error("Failed to get execution %s: %v", executionID, err)
+		if err.Error() == "execution not found: "+executionID {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Execution not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	// Get the job to check access
+	jobInstance, err := job.GetJob(execution.JobID)
+	if err != nil {
+		log.Error("Failed to get job %s for execution %s: %v", execution.JobID, executionID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Check if user has access to the job
+	if !HasJobAccess(c, authInfo, jobInstance) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Execution not found"})
+		return
+	}
+
+	chain, err := job.GetRetryChain(executionID)
+	if err != nil {
+		log.Error("Failed to get retry chain of execution %s: %v", executionID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":         chain,
+		"total":        len(chain),
+		"execution_id": executionID,
+		"job_id":       execution.JobID,
+		"dead_letter":  len(chain) > 0 && chain[len(chain)-1].Status == "dead_letter",
+	})
+}
+
 // ========================
 // Process Handlers
 // ========================
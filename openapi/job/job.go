@@ -13,6 +13,7 @@ func init() {
 		"jobs.get":         ProcessGetJob,
 		"jobs.count":       ProcessCountJobs,
 		"jobs.stop":        ProcessStopJob,
+		"jobs.dag":         ProcessGetJobDAG,
 		"executions.list":  ProcessListExecutions,
 		"executions.get":   ProcessGetExecution,
 		"executions.count": ProcessCountExecutions,
@@ -38,6 +39,7 @@ func Attach(group *gin.RouterGroup, oauth types.OAuth) {
 	group.GET("/jobs/:jobID/executions", ListExecutions)
 	group.GET("/executions/:executionID", GetExecution)
 	group.POST("/executions/:executionID/stop", StopExecution)
+	group.GET("/executions/:executionID/retries", ListExecutionRetries)
 
 	// Log Management
 	group.GET("/jobs/:jobID/logs", ListLogs)
@@ -49,6 +51,7 @@ func Attach(group *gin.RouterGroup, oauth types.OAuth) {
 
 	// Progress and Status
 	group.GET("/jobs/:jobID/progress", GetJobProgress)
+	group.GET("/jobs/:jobID/dag", GetJobDAG)
 	group.GET("/executions/:executionID/progress", GetExecutionProgress)
 
 	// Statistics
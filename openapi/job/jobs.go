@@ -229,6 +229,8 @@ func GetJobProgress(c *gin.Context) {
 	completedCount := 0
 	runningCount := 0
 	failedCount := 0
+	deadLetterCount := 0
+	skippedCount := 0
 	totalProgress := 0
 
 	for _, execution := range executions {
@@ -240,6 +242,10 @@ func GetJobProgress(c *gin.Context) {
 			runningCount++
 		case "failed":
 			failedCount++
+		case "dead_letter":
+			deadLetterCount++
+		case "skipped":
+			skippedCount++
 		}
 	}
 
@@ -249,20 +255,73 @@ func GetJobProgress(c *gin.Context) {
 	}
 
 	response := gin.H{
-		"job_id":           jobID,
-		"status":           jobInstance.Status,
-		"progress":         averageProgress,
-		"total_executions": totalExecutions,
-		"completed_count":  completedCount,
-		"running_count":    runningCount,
-		"failed_count":     failedCount,
-		"last_run_at":      jobInstance.LastRunAt,
-		"next_run_at":      jobInstance.NextRunAt,
+		"job_id":            jobID,
+		"status":            jobInstance.Status,
+		"progress":          averageProgress,
+		"total_executions":  totalExecutions,
+		"completed_count":   completedCount,
+		"running_count":     runningCount,
+		"failed_count":      failedCount,
+		"dead_letter_count": deadLetterCount,
+		"skipped_count":     skippedCount,
+		"last_run_at":       jobInstance.LastRunAt,
+		"next_run_at":       jobInstance.NextRunAt,
+	}
+
+	// Dependency graph progress of the latest run
+	if dag, err := job.GetDAG(jobID); err == nil && len(dag.Edges) > 0 {
+		response["dag"] = gin.H{
+			"status":    dag.Status,
+			"progress":  dag.Progress,
+			"total":     dag.Total,
+			"completed": dag.Completed,
+			"failed":    dag.Failed,
+			"skipped":   dag.Skipped,
+		}
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// GetJobDAG gets the dependency graph of the latest run of a job
+func GetJobDAG(c *gin.Context) {
+	// Get authorized information
+	authInfo := authorized.GetInfo(c)
+
+	jobID := c.Param("jobID")
+	if jobID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "job_id is required"})
+		return
+	}
+
+	// Get the job first
+	jobInstance, err := job.GetJob(jobID)
+	if err != nil {
+		log.Error("Failed to get job %s: %v", jobID, err)
+		if err.Error() == "job not found: "+jobID {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	// Check if user has access to this job
+	if !HasJobAccess(c, authInfo, jobInstance) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+
+	dag, err := job.GetDAG(jobID)
+	if err != nil {
+		log.Error("Failed to get dependency graph of job %s: %v", jobID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dag)
+}
+
 // GetStats gets overall job statistics
 func GetStats(c *gin.Context) {
 	// Get authorized information
@@ -470,3 +529,24 @@ func ProcessStopJob(process *process.Process) interface{} {
 
 	return map[string]interface{}{"message": "Job stopped successfully", "job_id": jobID}
 }
+
+// ProcessGetJobDAG process handler for getting the dependency graph of a job
+func ProcessGetJobDAG(process *process.Process) interface{} {
+	args := process.Args
+	if len(args) == 0 {
+		return map[string]interface{}{"error": "job_id is required"}
+	}
+
+	jobID, ok := args[0].(string)
+	if !ok {
+		return map[string]interface{}{"error": "job_id must be a string"}
+	}
+
+	dag, err := job.GetDAG(jobID)
+	if err != nil {
+		log.Error("Failed to get dependency graph of job %s: %v", jobID, err)
+		return map[string]interface{}{"error": err.Error()}
+	}
+
+	return dag
+}
@@ -0,0 +1,189 @@
+package notification
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/yao/notification"
+	"github.com/yaoapp/yao/openapi/oauth/authorized"
+	oauthTypes "github.com/yaoapp/yao/openapi/oauth/types"
+	"github.com/yaoapp/yao/openapi/response"
+)
+
+// Attach attaches the notification handlers to the router
+func Attach(group *gin.RouterGroup, oauth oauthTypes.OAuth) {
+	group.Use(oauth.Guard)
+
+	group.GET("", ListNotifications)
+	group.GET("/unread", GetUnreadCount)
+	group.GET("/stream", StreamNotifications)
+	group.POST("/read", MarkRead)
+	group.GET("/:notificationID", GetNotification)
+	group.POST("/:notificationID/read", MarkOneRead)
+	group.DELETE("/:notificationID", DeleteNotification)
+}
+
+// MarkReadRequest is the body of POST /notifications/read
+type MarkReadRequest struct {
+	NotificationIDs []string `json:"notification_ids,omitempty"` // empty = all
+}
+
+// ListNotifications lists the notifications of the current user
+// GET /api/__yao/openapi/v1/notifications?page=1&pagesize=20&unread=true&type=&source=
+func ListNotifications(c *gin.Context) {
+	authInfo := authorized.GetInfo(c)
+	if !requireUser(c, authInfo) {
+		return
+	}
+
+	opts := &notification.ListOptions{
+		UserID: authInfo.UserID,
+		TeamID: authInfo.TeamID,
+		Type:   c.Query("type"),
+		Source: c.Query("source"),
+	}
+	if page, err := strconv.Atoi(c.Query("page")); err == nil {
+		opts.Page = page
+	}
+	if pageSize, err := strconv.Atoi(c.Query("pagesize")); err == nil {
+		opts.PageSize = pageSize
+	}
+	switch c.Query("unread") {
+	case "true", "1", "yes":
+		opts.Unread = true
+	}
+
+	result, err := notification.List(c.Request.Context(), opts)
+	if err != nil {
+		respondServerError(c, "Failed to list notifications", err)
+		return
+	}
+	response.RespondWithSuccess(c, response.StatusOK, result)
+}
+
+// GetUnreadCount returns the number of unread notifications of the current user
+// GET /api/__yao/openapi/v1/notifications/unread
+func GetUnreadCount(c *gin.Context) {
+	authInfo := authorized.GetInfo(c)
+	if !requireUser(c, authInfo) {
+		return
+	}
+
+	count, err := notification.UnreadCount(c.Request.Context(), authInfo.UserID, authInfo.TeamID)
+	if err != nil {
+		respondServerError(c, "Failed to count unread notifications", err)
+		return
+	}
+	response.RespondWithSuccess(c, response.StatusOK, gin.H{"count": count})
+}
+
+// GetNotification returns a notification of the current user
+// GET /api/__yao/openapi/v1/notifications/:notificationID
+func GetNotification(c *gin.Context) {
+	authInfo := authorized.GetInfo(c)
+	if !requireUser(c, authInfo) {
+		return
+	}
+
+	n, err := notification.Get(c.Request.Context(), authInfo.UserID, c.Param("notificationID"))
+	if err != nil {
+		respondServerError(c, "Failed to get notification", err)
+		return
+	}
+	if n == nil {
+		respondNotFound(c)
+		return
+	}
+	response.RespondWithSuccess(c, response.StatusOK, n)
+}
+
+// MarkRead marks notifications of the current user as read, all of them when no IDs are given
+// POST /api/__yao/openapi/v1/notifications/read
+func MarkRead(c *gin.Context) {
+	authInfo := authorized.GetInfo(c)
+	if !requireUser(c, authInfo) {
+		return
+	}
+
+	var req MarkReadRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.RespondWithError(c, response.StatusBadRequest, &response.ErrorResponse{
+				Code:             response.ErrInvalidRequest.Code,
+				ErrorDescription: "Invalid request body: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	count, err := notification.MarkRead(c.Request.Context(), authInfo.UserID, authInfo.TeamID, req.NotificationIDs)
+	if err != nil {
+		respondServerError(c, "Failed to mark notifications as read", err)
+		return
+	}
+	response.RespondWithSuccess(c, response.StatusOK, gin.H{"count": count})
+}
+
+// MarkOneRead marks a notification of the current user as read
+// POST /api/__yao/openapi/v1/notifications/:notificationID/read
+func MarkOneRead(c *gin.Context) {
+	authInfo := authorized.GetInfo(c)
+	if !requireUser(c, authInfo) {
+		return
+	}
+
+	count, err := notification.MarkRead(c.Request.Context(), authInfo.UserID, "", []string{c.Param("notificationID")})
+	if err != nil {
+		respondServerError(c, "Failed to mark notification as read", err)
+		return
+	}
+	response.RespondWithSuccess(c, response.StatusOK, gin.H{"count": count})
+}
+
+// DeleteNotification deletes a notification of the current user
+// DELETE /api/__yao/openapi/v1/notifications/:notificationID
+func DeleteNotification(c *gin.Context) {
+	authInfo := authorized.GetInfo(c)
+	if !requireUser(c, authInfo) {
+		return
+	}
+
+	count, err := notification.Delete(c.Request.Context(), authInfo.UserID, []string{c.Param("notificationID")})
+	if err != nil {
+		respondServerError(c, "Failed to delete notification", err)
+		return
+	}
+	if count == 0 {
+		respondNotFound(c)
+		return
+	}
+	response.RespondWithSuccess(c, response.StatusOK, gin.H{"count": count})
+}
+
+// requireUser responds with 401 when the request is not made by a user
+func requireUser(c *gin.Context, authInfo *oauthTypes.AuthorizedInfo) bool {
+	if authInfo == nil || authInfo.UserID == "" {
+		response.RespondWithError(c, response.StatusUnauthorized, &response.ErrorResponse{
+			Code:             response.ErrInvalidToken.Code,
+			ErrorDescription: "User authentication required",
+		})
+		return false
+	}
+	return true
+}
+
+func respondNotFound(c *gin.Context) {
+	response.RespondWithError(c, response.StatusNotFound, &response.ErrorResponse{
+		Code:             response.ErrInvalidRequest.Code,
+		ErrorDescription: "Notification not found",
+	})
+}
+
+func respondServerError(c *gin.Context, message string, err error) {
+	log.Error("[OpenAPI Notification] %s: %v", message, err)
+	response.RespondWithError(c, response.StatusInternalServerError, &response.ErrorResponse{
+		Code:             response.ErrServerError.Code,
+		ErrorDescription: message + ": " + err.Error(),
+	})
+}
@@ -0,0 +1,88 @@
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	eventtypes "github.com/yaoapp/yao/event/types"
+	"github.com/yaoapp/yao/notification"
+	"github.com/yaoapp/yao/openapi/oauth/authorized"
+)
+
+// streamHeartbeat keeps idle connections open through proxies
+var streamHeartbeat = 30 * time.Second
+
+// StreamNotifications streams the notification events of the current user (SSE)
+// GET /api/__yao/openapi/v1/notifications/stream
+//
+// Events:
+//   - ready: {"unread": n} once subscribed
+//   - notification.created: the new notification
+//   - notification.read / notification.deleted: {"user_id", "notification_ids", "count"}
+func StreamNotifications(c *gin.Context) {
+	authInfo := authorized.GetInfo(c)
+	if !requireUser(c, authInfo) {
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	events := make(chan *eventtypes.Event, 64)
+	unsubscribe := notification.Subscribe(authInfo.UserID, authInfo.TeamID, events)
+	defer unsubscribe()
+
+	unread, err := notification.UnreadCount(c.Request.Context(), authInfo.UserID, authInfo.TeamID)
+	if err != nil {
+		sendSSEEvent(c.Writer, "error", gin.H{"error": err.Error()})
+		return
+	}
+	if sendSSEEvent(c.Writer, "ready", gin.H{"unread": unread}) != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	clientGone := c.Request.Context().Done()
+	for {
+		select {
+		case <-clientGone:
+			return
+
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if sendSSEEvent(c.Writer, ev.Type, ev.Payload) != nil {
+				return
+			}
+		}
+	}
+}
+
+// sendSSEEvent writes one SSE event and flushes it to the client
+func sendSSEEvent(w io.Writer, name string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, payload); err != nil {
+		return err
+	}
+	if flusher, ok := w.(gin.ResponseWriter); ok {
+		flusher.Flush()
+	}
+	return nil
+}
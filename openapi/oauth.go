@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yaoapp/yao/audit"
+	openapiAudit "github.com/yaoapp/yao/openapi/audit"
 	"github.com/yaoapp/yao/openapi/oauth"
 	"github.com/yaoapp/yao/openapi/oauth/types"
 	"github.com/yaoapp/yao/openapi/response"
@@ -224,6 +226,7 @@ func (openapi *OpenAPI) handleStandardTokenGrant(c *gin.Context, grantType strin
 
 	// Call OAuth service to handle the token request
 	token, err := openapi.OAuth.Token(c, grantType, code, clientID, codeVerifier)
+	auditTokenGrant(c, grantType, clientID, err)
 	if err != nil {
 		// Convert OAuth service error to token error response with security headers
 		if oauthErr, ok := err.(*response.ErrorResponse); ok {
@@ -282,6 +285,7 @@ func (openapi *OpenAPI) handleRefreshTokenGrant(c *gin.Context) {
 	} else {
 		refreshResponse, err = openapi.OAuth.RefreshToken(c, refreshToken)
 	}
+	auditTokenGrant(c, types.GrantTypeRefreshToken, clientID, err)
 	if err != nil {
 		// Convert OAuth service error to token error response with security headers
 		if oauthErr, ok := err.(*response.ErrorResponse); ok {
@@ -759,3 +763,22 @@ func extractBearerToken(c *gin.Context) string {
 	}
 	return ""
 }
+
+// auditTokenGrant records a token request of an OAuth client, err is nil on success
+func auditTokenGrant(c *gin.Context, grantType string, clientID string, err error) {
+	e := &audit.Entry{
+		Operation:      audit.OpTokenIssue,
+		Category:       audit.CategoryAuthentication,
+		Severity:       audit.SeverityLow,
+		UserID:         clientID,
+		TargetResource: clientID,
+		ResourceType:   "client",
+		Success:        err == nil,
+		Details:        map[string]interface{}{"grant_type": grantType, "client_id": clientID},
+	}
+	if err != nil {
+		e.Severity = audit.SeverityMedium
+		e.ErrorMessage = err.Error()
+	}
+	openapiAudit.Log(c, e)
+}
@@ -887,6 +887,101 @@ func (m *ScopeManager) GetScopeConstraints(scopeName string, method, path string
 	return info
 }
 
+// Defined checks if a scope or a scope alias is defined
+func (m *ScopeManager) Defined(name string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.scopeIndex[name]; ok {
+		return true
+	}
+	_, ok := m.aliasIndex[name]
+	return ok
+}
+
+// ValidateScopes checks the scopes that can be granted to a credential (e.g. an API key)
+// Builtin scopes are reserved for temporary tokens. Without a loaded scope manager only builtin scopes are rejected.
+func ValidateScopes(scopes []string) error {
+	var manager *ScopeManager
+	if enforcer, ok := Global.(*ACL); ok && enforcer.Enabled() {
+		manager = enforcer.Scope
+	}
+
+	builtinScopesMutex.RLock()
+	defer builtinScopesMutex.RUnlock()
+
+	for _, scope := range scopes {
+		if _, builtin := builtinScopes[scope]; builtin {
+			return fmt.Errorf("scope %s is reserved", scope)
+		}
+		if manager != nil && !manager.Defined(scope) {
+			return fmt.Errorf("scope %s is not defined", scope)
+		}
+	}
+	return nil
+}
+
+// ExclusiveAllows checks that a token with the given scopes (space separated) may access an endpoint
+// A token with an exclusive builtin scope is accepted on the endpoints of that scope only, whether ACL is enabled or not.
+// The path is matched from the end, so the base URL prefix does not matter.
+func ExclusiveAllows(scope string, method, path string) bool {
+	builtinScopesMutex.RLock()
+	defer builtinScopesMutex.RUnlock()
+
+	for _, name := range strings.Fields(scope) {
+		definition, ok := builtinScopes[name]
+		if !ok || !definition.Exclusive {
+			continue
+		}
+		if !matchScopeEndpoints(definition.Endpoints, method, path) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasExclusiveScope checks if the given scopes (space separated) contain an exclusive builtin scope
+func HasExclusiveScope(scope string) bool {
+	builtinScopesMutex.RLock()
+	defer builtinScopesMutex.RUnlock()
+
+	for _, name := range strings.Fields(scope) {
+		if definition, ok := builtinScopes[name]; ok && definition.Exclusive {
+			return true
+		}
+	}
+	return false
+}
+
+// matchScopeEndpoints checks if the request path ends with one of the endpoints (format: METHOD /path)
+func matchScopeEndpoints(endpoints []string, method, path string) bool {
+	pathParts := strings.Split(strings.Trim(normalizePath(path), "/"), "/")
+	for _, endpoint := range endpoints {
+		fields := strings.Fields(endpoint)
+		if len(fields) != 2 || fields[0] != method {
+			continue
+		}
+
+		patternParts := strings.Split(strings.Trim(fields[1], "/"), "/")
+		if len(patternParts) > len(pathParts) {
+			continue
+		}
+
+		tail := pathParts[len(pathParts)-len(patternParts):]
+		matched := true
+		for i := range patternParts {
+			if !strings.HasPrefix(patternParts[i], ":") && patternParts[i] != tail[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
 // Reload reloads the scope configuration
 func (m *ScopeManager) Reload() error {
 	m.mu.Lock()
@@ -147,3 +147,67 @@ func TestPathMatchingExactPaths(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateScopes(t *testing.T) {
+	Register(&ScopeDefinition{Name: "builtin:test:validate", Endpoints: []string{"GET /test/validate"}})
+
+	manager := &ScopeManager{
+		scopeIndex: map[string]*Scope{"kb:read": {Name: "kb:read"}},
+		aliasIndex: map[string][]string{"kb:all": {"kb:read"}},
+	}
+	if !manager.Defined("kb:read") || !manager.Defined("kb:all") || manager.Defined("kb:write") {
+		t.Fatalf("Defined should match scopes and aliases only")
+	}
+
+	original := Global
+	defer func() { Global = original }()
+
+	// Without a scope manager only builtin scopes are rejected
+	Global = nil
+	if err := ValidateScopes([]string{"anything"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidateScopes([]string{"builtin:test:validate"}); err == nil {
+		t.Errorf("builtin scopes must be rejected")
+	}
+
+	Global = &ACL{Config: &Config{Enabled: true}, Scope: manager}
+	if err := ValidateScopes([]string{"kb:read", "kb:all"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidateScopes([]string{"kb:read", "kb:write"}); err == nil {
+		t.Errorf("undefined scopes must be rejected")
+	}
+}
+
+func TestExclusiveAllows(t *testing.T) {
+	Register(
+		&ScopeDefinition{Name: "builtin:test:exclusive", Exclusive: true, Endpoints: []string{"GET /test/mfa", "POST /test/mfa/:method/verify"}},
+		&ScopeDefinition{Name: "builtin:test:shared", Endpoints: []string{"GET /test/shared"}},
+	)
+
+	tests := []struct {
+		scope   string
+		method  string
+		path    string
+		allowed bool
+	}{
+		{"builtin:test:exclusive", "GET", "/v1/test/mfa", true},
+		{"builtin:test:exclusive", "GET", "/test/mfa/", true},
+		{"builtin:test:exclusive", "POST", "/v1/test/mfa/totp/verify", true},
+		{"builtin:test:exclusive", "POST", "/v1/test/mfa", false},
+		{"builtin:test:exclusive", "GET", "/v1/user/profile", false},
+		{"openid builtin:test:exclusive", "GET", "/v1/user/profile", false},
+		{"builtin:test:shared", "GET", "/v1/user/profile", true},
+		{"openid", "GET", "/v1/user/profile", true},
+	}
+	for _, tc := range tests {
+		if got := ExclusiveAllows(tc.scope, tc.method, tc.path); got != tc.allowed {
+			t.Errorf("ExclusiveAllows(%q, %s %s) = %v, expected %v", tc.scope, tc.method, tc.path, got, tc.allowed)
+		}
+	}
+
+	if !HasExclusiveScope("openid builtin:test:exclusive") || HasExclusiveScope("builtin:test:shared") {
+		t.Errorf("HasExclusiveScope should match exclusive builtin scopes only")
+	}
+}
@@ -88,6 +88,7 @@ type ScopeDefinition struct {
 	Team        bool                   `json:"team" yaml:"team"`                       // Team only
 	Extra       map[string]interface{} `json:"extra,omitempty" yaml:"extra,omitempty"` // Extra constraints
 	Endpoints   []string               `json:"endpoints" yaml:"endpoints"`             // Endpoint list (format: METHOD /path)
+	Exclusive   bool                   `json:"exclusive" yaml:"exclusive"`             // Tokens with this scope are accepted on its endpoints only, even with ACL disabled
 }
 
 // ============ Runtime Structures (optimized for querying) ============
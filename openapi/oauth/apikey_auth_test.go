@@ -0,0 +1,92 @@
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaoapp/kun/maps"
+	"github.com/yaoapp/yao/openapi/oauth/authorized"
+)
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	service, _, _, cleanup := setupOAuthTestEnvironment(t)
+	defer cleanup()
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	userID := "apikey_guard_user_" + time.Now().Format("150405.000000")
+	provider := service.userProvider
+	defer provider.DeleteUserAPIKeys(ctx, userID)
+
+	keyID, key, err := provider.CreateAPIKey(ctx, userID, maps.MapStrAny{
+		"name":    "Guard",
+		"team_id": "team_guard",
+		"scopes":  []string{"kb:read", "chat:write"},
+	})
+	require.NoError(t, err)
+
+	authenticate := func(key string) (*httptest.ResponseRecorder, *gin.Context, bool) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		c.Request.Header.Set("Authorization", "Bearer "+key)
+		c.Request.RemoteAddr = "203.0.113.9:1234"
+		return w, c, service.Authenticate(c)
+	}
+
+	t.Run("key authenticates with its scopes, user and team", func(t *testing.T) {
+		w, c, ok := authenticate(key)
+		require.True(t, ok, w.Body.String())
+
+		info := authorized.GetInfo(c)
+		assert.Equal(t, userID, info.UserID)
+		assert.Equal(t, "team_guard", info.TeamID)
+		assert.Equal(t, keyID, info.ClientID)
+		assert.Equal(t, keyID, info.APIKeyID)
+		assert.Equal(t, "kb:read chat:write", info.Scope)
+
+		apiKey, err := provider.GetAPIKey(ctx, keyID)
+		require.NoError(t, err)
+		assert.Equal(t, "203.0.113.9", apiKey["last_used_ip"])
+	})
+
+	t.Run("key authenticates without gin", func(t *testing.T) {
+		result, err := service.AuthenticateToken(AuthInput{AccessToken: "Bearer " + key})
+		require.NoError(t, err)
+		assert.Equal(t, userID, result.Info.UserID)
+		assert.Equal(t, keyID, result.Info.APIKeyID)
+	})
+
+	t.Run("unknown key is rejected", func(t *testing.T) {
+		w, _, ok := authenticate(key + "x")
+		assert.False(t, ok)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("expired key is rejected", func(t *testing.T) {
+		require.NoError(t, provider.UpdateAPIKey(ctx, keyID, maps.MapStrAny{"expires_at": time.Now().Add(-time.Minute)}))
+		w, _, ok := authenticate(key)
+		assert.False(t, ok)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		require.NoError(t, provider.UpdateAPIKey(ctx, keyID, maps.MapStrAny{"expires_at": nil}))
+	})
+
+	t.Run("revoked key is rejected immediately", func(t *testing.T) {
+		_, _, ok := authenticate(key)
+		require.True(t, ok)
+
+		require.NoError(t, provider.DeleteAPIKey(ctx, keyID))
+		w, _, ok := authenticate(key)
+		assert.False(t, ok)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		_, err := service.AuthenticateToken(AuthInput{AccessToken: key})
+		assert.Error(t, err)
+	})
+}
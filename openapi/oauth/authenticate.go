@@ -1,12 +1,16 @@
 package oauth
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	jsoniter "github.com/json-iterator/go"
 	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/yao/openapi/oauth/acl"
+	"github.com/yaoapp/yao/openapi/oauth/providers/user"
 	"github.com/yaoapp/yao/openapi/oauth/types"
 )
 
@@ -16,6 +20,7 @@ type AuthInput struct {
 	AccessToken  string
 	RefreshToken string
 	SessionID    string
+	ClientIP     string // Recorded as the last use of API keys
 }
 
 // AuthResult holds the outcome of a successful authentication.
@@ -31,13 +36,16 @@ type AuthResult struct {
 // extracting tokens from the transport and delivering refreshed tokens
 // back to the client.
 func (s *Service) AuthenticateToken(input AuthInput) (*AuthResult, error) {
-	token := input.AccessToken
+	token := strings.TrimPrefix(input.AccessToken, "Bearer ")
 
-	// API Key resolution (same as getAccessToken in guard.go)
-	if s.isAPIKey(token) {
-		token = s.getAccessTokenFromAPIKey(token)
+	// API Key resolution (same as Authenticate in guard.go)
+	if strings.HasPrefix(token, user.APIKeyPrefix) {
+		info, err := s.authorizeAPIKey(context.Background(), token, input.ClientIP)
+		if err != nil {
+			return nil, fmt.Errorf("%s", types.ErrInvalidToken.Error())
+		}
+		return &AuthResult{Info: info}, nil
 	}
-	token = strings.TrimPrefix(token, "Bearer ")
 
 	if token == "" {
 		return nil, fmt.Errorf("%s", types.ErrTokenMissing.Error())
@@ -71,6 +79,11 @@ func (s *Service) AuthenticateToken(input AuthInput) (*AuthResult, error) {
 		}
 	}
 
+	// Temporary tokens (e.g. MFA verification) are bound to the HTTP endpoints of their scope
+	if acl.HasExclusiveScope(claims.Scope) {
+		return nil, fmt.Errorf("%s", types.ErrInsufficientScope.Error())
+	}
+
 	info := s.buildAuthInfo(claims, input.SessionID)
 
 	return &AuthResult{
@@ -230,3 +243,65 @@ func (s *Service) buildAuthInfo(claims *types.TokenClaims, sessionID string) *ty
 
 	return info
 }
+
+// authorizeAPIKey resolves an API key through the user provider and builds its authorized info.
+// Keys are looked up on every call, so revoked, regenerated and expired keys are rejected immediately.
+// The key acts as its own client: the client ID is the key ID and the scopes are the key scopes.
+func (s *Service) authorizeAPIKey(ctx context.Context, key string, ip string) (*types.AuthorizedInfo, error) {
+	if s.userProvider == nil {
+		return nil, fmt.Errorf("user provider not available")
+	}
+
+	apiKey, err := s.userProvider.VerifyAPIKey(ctx, key, ip)
+	if err != nil {
+		return nil, err
+	}
+
+	keyID, _ := apiKey["key_id"].(string)
+	userID, _ := apiKey["user_id"].(string)
+	teamID, _ := apiKey["team_id"].(string)
+	if keyID == "" || userID == "" {
+		return nil, fmt.Errorf("api key %s has no owner", keyID)
+	}
+
+	subject, err := s.Subject(keyID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the subject of api key %s: %w", keyID, err)
+	}
+
+	return &types.AuthorizedInfo{
+		Subject:  subject,
+		ClientID: keyID,
+		UserID:   userID,
+		TeamID:   teamID,
+		Scope:    strings.Join(apiKeyScopes(apiKey["scopes"]), " "),
+		APIKeyID: keyID,
+	}, nil
+}
+
+// apiKeyScopes reads the scopes of an API key, stored as a JSON array
+func apiKeyScopes(value interface{}) []string {
+	var raw []interface{}
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		raw = v
+	case string:
+		if err := jsoniter.UnmarshalFromString(v, &raw); err != nil {
+			return nil
+		}
+	case []byte:
+		if err := jsoniter.Unmarshal(v, &raw); err != nil {
+			return nil
+		}
+	}
+
+	scopes := make([]string, 0, len(raw))
+	for _, item := range raw {
+		if scope, ok := item.(string); ok && scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
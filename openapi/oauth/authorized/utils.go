@@ -93,6 +93,12 @@ func GetInfo(c *gin.Context) *types.AuthorizedInfo {
 		}
 	}
 
+	if apiKeyID, ok := c.Get("__api_key_id"); ok {
+		if keyStr, ok := apiKeyID.(string); ok {
+			info.APIKeyID = keyStr
+		}
+	}
+
 	// Get data access constraints (set by ACL enforcement)
 	info.Constraints = GetConstraints(c)
 
@@ -192,3 +198,19 @@ func SetInfo(c *gin.Context, claims *types.TokenClaims, sessionID string, userID
 		}
 	}
 }
+
+// SetAPIKeyInfo sets the authorized information of an API key in the gin context
+// API keys are not tokens, the info is resolved by the guard instead of read from claims
+func SetAPIKeyInfo(c *gin.Context, info *types.AuthorizedInfo) {
+	c.Set("__subject", info.Subject)
+	c.Set("__scope", info.Scope)
+	c.Set("__client_id", info.ClientID)
+	c.Set("__user_id", info.UserID)
+	c.Set("__api_key_id", info.APIKeyID)
+	if info.TeamID != "" {
+		c.Set("__team_id", info.TeamID)
+	}
+	if info.TenantID != "" {
+		c.Set("__tenant_id", info.TenantID)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/yaoapp/kun/log"
 	"github.com/yaoapp/yao/openapi/oauth/acl"
 	"github.com/yaoapp/yao/openapi/oauth/authorized"
+	"github.com/yaoapp/yao/openapi/oauth/providers/user"
 	"github.com/yaoapp/yao/openapi/oauth/types"
 	"github.com/yaoapp/yao/openapi/response"
 )
@@ -28,11 +29,29 @@ type refreshGate struct {
 
 // Guard is the OAuth guard middleware
 func (s *Service) Guard(c *gin.Context) {
+	// Requests without a valid credential are limited by client IP, checked before authenticating
+	if !s.rateLimitAnonymous(c) {
+		return // Limit exceeded, response already sent
+	}
+
 	// Authenticate first (validates token and sets authorized info)
 	if !s.Authenticate(c) {
+		s.countAnonymous(c)
 		return // Authentication failed, response already sent
 	}
 
+	// Temporary tokens (e.g. MFA verification) only pass on the endpoints of their scope
+	if info := authorized.GetInfo(c); info != nil && !acl.ExclusiveAllows(info.Scope, c.Request.Method, c.Request.URL.Path) {
+		response.RespondWithError(c, http.StatusForbidden, types.ErrInsufficientScope)
+		c.Abort()
+		return
+	}
+
+	// Count the request against its rate limit (client, API key, user or IP)
+	if !s.RateLimit(c) {
+		return // Limit exceeded, response already sent
+	}
+
 	// Check if ACL is enabled
 	if acl.Global == nil || !acl.Global.Enabled() {
 		return
@@ -59,6 +78,11 @@ func (s *Service) Guard(c *gin.Context) {
 // This method only performs authentication without ACL checks
 // Returns true if authentication succeeded, false otherwise
 func (s *Service) Authenticate(c *gin.Context) bool {
+	// API keys are resolved from the key store on every request
+	if key := s.getAPIKey(c); key != "" {
+		return s.authenticateAPIKey(c, key)
+	}
+
 	token := s.getAccessToken(c)
 	if token == "" {
 		response.RespondWithError(c, http.StatusUnauthorized, types.ErrTokenMissing)
@@ -104,6 +128,20 @@ func (s *Service) Authenticate(c *gin.Context) bool {
 	return true
 }
 
+// authenticateAPIKey validates an API key and sets its authorized info in context
+// Returns true if authentication succeeded, false otherwise
+func (s *Service) authenticateAPIKey(c *gin.Context, key string) bool {
+	info, err := s.authorizeAPIKey(c.Request.Context(), key, c.ClientIP())
+	if err != nil {
+		response.RespondWithError(c, http.StatusUnauthorized, types.ErrInvalidToken)
+		c.Abort()
+		return false
+	}
+
+	authorized.SetAPIKeyInfo(c, info)
+	return true
+}
+
 // GetAuthorizedInfo gets authorized info from context
 // Deprecated: Use authorized.GetInfo(c) instead
 func GetAuthorizedInfo(c *gin.Context) *types.AuthorizedInfo {
@@ -261,6 +299,16 @@ func (s *Service) getAccessToken(c *gin.Context) string {
 	return accessToken
 }
 
+// getAPIKey returns the API key sent as bearer token, empty if the request carries no API key
+// Self-service API keys are told apart from access tokens by their prefix
+func (s *Service) getAPIKey(c *gin.Context) string {
+	key := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !strings.HasPrefix(key, user.APIKeyPrefix) {
+		return ""
+	}
+	return key
+}
+
 // GetAccessToken gets the access token from the request (public method)
 func (s *Service) GetAccessToken(c *gin.Context) string {
 	return s.getAccessToken(c)
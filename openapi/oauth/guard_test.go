@@ -0,0 +1,55 @@
+package oauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaoapp/yao/openapi/oauth/acl"
+)
+
+func TestGuardExclusiveScope(t *testing.T) {
+	service, _, _, cleanup := setupOAuthTestEnvironment(t)
+	defer cleanup()
+	gin.SetMode(gin.TestMode)
+
+	// ACL disabled
+	original := acl.Global
+	acl.Global = nil
+	defer func() { acl.Global = original }()
+
+	scope := "builtin:test:guard:mfa"
+	acl.Register(&acl.ScopeDefinition{Name: scope, Exclusive: true, Endpoints: []string{"GET /user/mfa", "POST /user/mfa/totp/verify"}})
+
+	clientID := "guard-exclusive-client"
+	subject, err := service.Subject(clientID, "guard_exclusive_user_"+time.Now().Format("150405.000000"))
+	require.NoError(t, err)
+	token, err := service.MakeAccessToken(clientID, scope, subject, 600)
+	require.NoError(t, err)
+
+	guard := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(method, path, nil)
+		c.Request.Header.Set("Authorization", "Bearer "+token)
+
+		service.Guard(c)
+		if !c.IsAborted() {
+			c.Status(http.StatusOK)
+		}
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, guard(http.MethodGet, "/v1/user/mfa").Code)
+	assert.Equal(t, http.StatusOK, guard(http.MethodPost, "/v1/user/mfa/totp/verify").Code)
+	assert.Equal(t, http.StatusForbidden, guard(http.MethodPost, "/v1/user/mfa/totp/disable").Code)
+	assert.Equal(t, http.StatusForbidden, guard(http.MethodGet, "/v1/user/profile").Code)
+
+	// No HTTP endpoint outside of Guard
+	_, err = service.AuthenticateToken(AuthInput{AccessToken: token})
+	assert.Error(t, err)
+}
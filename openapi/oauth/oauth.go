@@ -242,6 +242,12 @@ func setConfigDefaults(config *Config) error {
 	if config.Security.StateParameterLength == 0 {
 		config.Security.StateParameterLength = 32
 	}
+	if config.Security.RateLimitRequests <= 0 {
+		config.Security.RateLimitRequests = 100
+	}
+	if config.Security.RateLimitWindow <= 0 {
+		config.Security.RateLimitWindow = time.Minute
+	}
 
 	// Client defaults
 	if config.Client.DefaultClientType == "" {
@@ -0,0 +1,257 @@
+package user
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yaoapp/gou/model"
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/kun/maps"
+)
+
+// APIKeyPrefix is the prefix of every API key, it tells API keys apart from access tokens
+const APIKeyPrefix = "yao_sk_"
+
+// apiKeyTouchInterval limits how often the last use of an API key is written
+const apiKeyTouchInterval = time.Minute
+
+// API Key Resource
+
+// CreateAPIKey creates an API key for a user and returns its key_id and the key
+// The key is returned only once, only its hash is stored.
+func (u *DefaultUser) CreateAPIKey(ctx context.Context, userID string, apiKeyData maps.MapStrAny) (string, string, error) {
+	keyID, err := generateNanoID(16)
+	if err != nil {
+		return "", "", fmt.Errorf(ErrFailedToGenerateAPIKey, err)
+	}
+	keyID = "ak_" + keyID
+
+	key, err := generateAPIKey()
+	if err != nil {
+		return "", "", err
+	}
+
+	// Server generated fields can not be set by the caller
+	for _, field := range []string{"id", "key_id", "key_hash", "key_prefix", "last_used_at", "last_used_ip"} {
+		delete(apiKeyData, field)
+	}
+	apiKeyData["key_id"] = keyID
+	apiKeyData["user_id"] = userID
+	apiKeyData["key_hash"] = hashAPIKey(key)
+	apiKeyData["key_prefix"] = apiKeyDisplayPrefix(key)
+
+	m := model.Select(u.apiKeyModel)
+	_, err = m.Create(apiKeyData)
+	if err != nil {
+		return "", "", fmt.Errorf(ErrFailedToCreateAPIKey, err)
+	}
+
+	return keyID, key, nil
+}
+
+// GetAPIKey retrieves an API key by its key_id
+func (u *DefaultUser) GetAPIKey(ctx context.Context, keyID string) (maps.MapStrAny, error) {
+	return u.getAPIKeyWhere(model.QueryWhere{Column: "key_id", Value: keyID})
+}
+
+// GetUserAPIKeys retrieves the API keys of a user, newest first
+// With an empty teamID only the personal keys are returned.
+func (u *DefaultUser) GetUserAPIKeys(ctx context.Context, userID string, teamID string) ([]maps.MapStrAny, error) {
+	wheres := []model.QueryWhere{{Column: "user_id", Value: userID}}
+	if teamID != "" {
+		wheres = append(wheres, model.QueryWhere{Column: "team_id", Value: teamID})
+	} else {
+		wheres = append(wheres, model.QueryWhere{Column: "team_id", OP: "null"})
+	}
+
+	m := model.Select(u.apiKeyModel)
+	keys, err := m.Get(model.QueryParam{
+		Select: DefaultAPIKeyFields,
+		Wheres: wheres,
+		Orders: []model.QueryOrder{
+			{Column: "id", Option: "desc"},
+		},
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf(ErrFailedToGetAPIKey, err)
+	}
+
+	return keys, nil
+}
+
+// UpdateAPIKey updates the name, scopes or expiry of an API key
+func (u *DefaultUser) UpdateAPIKey(ctx context.Context, keyID string, apiKeyData maps.MapStrAny) error {
+	// The owner and the secret never change here, see RegenerateAPIKey
+	immutableFields := []string{"id", "key_id", "user_id", "team_id", "key_hash", "key_prefix", "created_at"}
+	for _, field := range immutableFields {
+		delete(apiKeyData, field)
+	}
+
+	if len(apiKeyData) == 0 {
+		return nil
+	}
+
+	return u.updateAPIKey(ctx, keyID, apiKeyData)
+}
+
+// RegenerateAPIKey replaces the key of an API key and returns the new key
+// The previous key stops working immediately.
+func (u *DefaultUser) RegenerateAPIKey(ctx context.Context, keyID string) (string, error) {
+	key, err := generateAPIKey()
+	if err != nil {
+		return "", err
+	}
+
+	err = u.updateAPIKey(ctx, keyID, maps.MapStrAny{
+		"key_hash":     hashAPIKey(key),
+		"key_prefix":   apiKeyDisplayPrefix(key),
+		"last_used_at": nil,
+		"last_used_ip": nil,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+// DeleteAPIKey revokes an API key
+func (u *DefaultUser) DeleteAPIKey(ctx context.Context, keyID string) error {
+	m := model.Select(u.apiKeyModel)
+	affected, err := m.DeleteWhere(model.QueryParam{
+		Wheres: []model.QueryWhere{
+			{Column: "key_id", Value: keyID},
+		},
+		Limit: 1,
+	})
+
+	if err != nil {
+		return fmt.Errorf(ErrFailedToDeleteAPIKey, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf(ErrAPIKeyNotFound)
+	}
+
+	return nil
+}
+
+// DeleteUserAPIKeys revokes all API keys of a user
+func (u *DefaultUser) DeleteUserAPIKeys(ctx context.Context, userID string) error {
+	m := model.Select(u.apiKeyModel)
+	_, err := m.DeleteWhere(model.QueryParam{
+		Wheres: []model.QueryWhere{
+			{Column: "user_id", Value: userID},
+		},
+	})
+
+	if err != nil {
+		return fmt.Errorf(ErrFailedToDeleteAPIKey, err)
+	}
+
+	return nil
+}
+
+// VerifyAPIKey returns the API key matching the key and records its use
+// Keys are looked up on every call, so a revoked or regenerated key is rejected immediately.
+func (u *DefaultUser) VerifyAPIKey(ctx context.Context, key string, ip string) (maps.MapStrAny, error) {
+	if !strings.HasPrefix(key, APIKeyPrefix) {
+		return nil, fmt.Errorf(ErrAPIKeyNotFound)
+	}
+
+	apiKey, err := u.getAPIKeyWhere(model.QueryWhere{Column: "key_hash", Value: hashAPIKey(key)})
+	if err != nil {
+		return nil, err
+	}
+
+	if expired, err := checkTimeExpired(apiKey["expires_at"]); err == nil && expired {
+		return nil, fmt.Errorf(ErrAPIKeyExpired)
+	}
+
+	// Record the use, at most once per interval unless the client IP changes
+	keyID, _ := apiKey["key_id"].(string)
+	lastIP, _ := apiKey["last_used_ip"].(string)
+	lastUsed, _ := parseTimeFromDB(apiKey["last_used_at"])
+	if lastUsed == nil || time.Since(*lastUsed) > apiKeyTouchInterval || lastIP != ip {
+		now := time.Now()
+		err := u.updateAPIKey(ctx, keyID, maps.MapStrAny{"last_used_at": now, "last_used_ip": ip})
+		if err != nil {
+			log.Warn("Failed to record the use of api key %s: %v", keyID, err)
+		} else {
+			apiKey["last_used_at"] = now
+			apiKey["last_used_ip"] = ip
+		}
+	}
+
+	return apiKey, nil
+}
+
+func (u *DefaultUser) getAPIKeyWhere(where model.QueryWhere) (maps.MapStrAny, error) {
+	m := model.Select(u.apiKeyModel)
+	keys, err := m.Get(model.QueryParam{
+		Select: DefaultAPIKeyFields,
+		Wheres: []model.QueryWhere{where},
+		Limit:  1,
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf(ErrFailedToGetAPIKey, err)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf(ErrAPIKeyNotFound)
+	}
+
+	return keys[0], nil
+}
+
+func (u *DefaultUser) updateAPIKey(ctx context.Context, keyID string, apiKeyData maps.MapStrAny) error {
+	m := model.Select(u.apiKeyModel)
+	affected, err := m.UpdateWhere(model.QueryParam{
+		Wheres: []model.QueryWhere{
+			{Column: "key_id", Value: keyID},
+		},
+		Limit: 1,
+	}, apiKeyData)
+
+	if err != nil {
+		return fmt.Errorf(ErrFailedToUpdateAPIKey, err)
+	}
+
+	if affected == 0 {
+		if _, err := u.GetAPIKey(ctx, keyID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// generateAPIKey generates a new API key (~230 bits of entropy)
+func generateAPIKey() (string, error) {
+	secret, err := generateNanoID(40)
+	if err != nil {
+		return "", fmt.Errorf(ErrFailedToGenerateAPIKey, err)
+	}
+	return APIKeyPrefix + secret, nil
+}
+
+// hashAPIKey returns the stored hash of an API key
+// Keys are random, a fast hash is enough and allows looking them up.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// apiKeyDisplayPrefix returns the visible part of an API key
+func apiKeyDisplayPrefix(key string) string {
+	if len(key) <= len(APIKeyPrefix)+6 {
+		return key
+	}
+	return key[:len(APIKeyPrefix)+6]
+}
@@ -0,0 +1,121 @@
+package user_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaoapp/kun/maps"
+	"github.com/yaoapp/yao/openapi/oauth/providers/user"
+)
+
+func TestAPIKeyOperations(t *testing.T) {
+	prepare(t)
+	defer clean()
+
+	ctx := context.Background()
+	testUUID := strings.ReplaceAll(uuid.New().String(), "-", "")[:8]
+	_, testUserID := setupTestUser(t, ctx, createTestUserData("apikey"+testUUID))
+	defer testProvider.DeleteUserAPIKeys(ctx, testUserID)
+
+	keyID, key, err := testProvider.CreateAPIKey(ctx, testUserID, maps.MapStrAny{
+		"name":     "CI",
+		"scopes":   []string{"kb:read"},
+		"key_hash": "ignored",
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(keyID, "ak_"))
+	assert.True(t, strings.HasPrefix(key, user.APIKeyPrefix))
+
+	teamKeyID, _, err := testProvider.CreateAPIKey(ctx, testUserID, maps.MapStrAny{
+		"name":    "Team",
+		"team_id": "team_" + testUUID,
+	})
+	require.NoError(t, err)
+
+	t.Run("Get", func(t *testing.T) {
+		apiKey, err := testProvider.GetAPIKey(ctx, keyID)
+		require.NoError(t, err)
+		assert.Equal(t, testUserID, apiKey["user_id"])
+		assert.Equal(t, key[:len(user.APIKeyPrefix)+6], apiKey["key_prefix"])
+		assert.NotContains(t, apiKey, "key_hash")
+
+		personal, err := testProvider.GetUserAPIKeys(ctx, testUserID, "")
+		require.NoError(t, err)
+		require.Len(t, personal, 1)
+		assert.Equal(t, keyID, personal[0]["key_id"])
+
+		team, err := testProvider.GetUserAPIKeys(ctx, testUserID, "team_"+testUUID)
+		require.NoError(t, err)
+		require.Len(t, team, 1)
+		assert.Equal(t, teamKeyID, team[0]["key_id"])
+	})
+
+	t.Run("Verify", func(t *testing.T) {
+		apiKey, err := testProvider.VerifyAPIKey(ctx, key, "127.0.0.1")
+		require.NoError(t, err)
+		assert.Equal(t, keyID, apiKey["key_id"])
+
+		stored, err := testProvider.GetAPIKey(ctx, keyID)
+		require.NoError(t, err)
+		assert.Equal(t, "127.0.0.1", stored["last_used_ip"])
+		assert.NotNil(t, stored["last_used_at"])
+
+		_, err = testProvider.VerifyAPIKey(ctx, key+"x", "127.0.0.1")
+		assert.Error(t, err)
+		_, err = testProvider.VerifyAPIKey(ctx, "not_an_api_key", "127.0.0.1")
+		assert.Error(t, err)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		err := testProvider.UpdateAPIKey(ctx, keyID, maps.MapStrAny{
+			"name":    "CI renamed",
+			"user_id": "another_user", // Ignored
+		})
+		require.NoError(t, err)
+
+		apiKey, err := testProvider.GetAPIKey(ctx, keyID)
+		require.NoError(t, err)
+		assert.Equal(t, "CI renamed", apiKey["name"])
+		assert.Equal(t, testUserID, apiKey["user_id"])
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		err := testProvider.UpdateAPIKey(ctx, keyID, maps.MapStrAny{"expires_at": time.Now().Add(-time.Hour)})
+		require.NoError(t, err)
+
+		_, err = testProvider.VerifyAPIKey(ctx, key, "127.0.0.1")
+		assert.Error(t, err)
+
+		err = testProvider.UpdateAPIKey(ctx, keyID, maps.MapStrAny{"expires_at": nil})
+		require.NoError(t, err)
+	})
+
+	t.Run("Regenerate", func(t *testing.T) {
+		newKey, err := testProvider.RegenerateAPIKey(ctx, keyID)
+		require.NoError(t, err)
+		assert.NotEqual(t, key, newKey)
+
+		_, err = testProvider.VerifyAPIKey(ctx, key, "127.0.0.1")
+		assert.Error(t, err, "the previous key must stop working")
+
+		_, err = testProvider.VerifyAPIKey(ctx, newKey, "127.0.0.1")
+		assert.NoError(t, err)
+		key = newKey
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		err := testProvider.DeleteAPIKey(ctx, keyID)
+		require.NoError(t, err)
+
+		_, err = testProvider.VerifyAPIKey(ctx, key, "127.0.0.1")
+		assert.Error(t, err, "a revoked key must stop working")
+
+		err = testProvider.DeleteAPIKey(ctx, keyID)
+		assert.Error(t, err)
+	})
+}
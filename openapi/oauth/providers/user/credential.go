@@ -0,0 +1,140 @@
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yaoapp/gou/model"
+	"github.com/yaoapp/kun/maps"
+)
+
+// WebAuthn Credential Resource
+
+// CreateCredential stores a verified WebAuthn credential for a user
+func (u *DefaultUser) CreateCredential(ctx context.Context, userID string, credentialData maps.MapStrAny) (interface{}, error) {
+	credentialData["user_id"] = userID
+	if _, exists := credentialData["sign_count"]; !exists {
+		credentialData["sign_count"] = 0
+	}
+
+	m := model.Select(u.credentialModel)
+	id, err := m.Create(credentialData)
+	if err != nil {
+		return nil, fmt.Errorf(ErrFailedToCreateCredential, err)
+	}
+
+	return id, nil
+}
+
+// GetCredential retrieves a WebAuthn credential by its credential ID
+func (u *DefaultUser) GetCredential(ctx context.Context, credentialID string) (maps.MapStrAny, error) {
+	m := model.Select(u.credentialModel)
+	credentials, err := m.Get(model.QueryParam{
+		Select: DefaultCredentialFields,
+		Wheres: []model.QueryWhere{
+			{Column: "credential_id", Value: credentialID},
+		},
+		Limit: 1,
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf(ErrFailedToGetCredential, err)
+	}
+
+	if len(credentials) == 0 {
+		return nil, fmt.Errorf(ErrCredentialNotFound)
+	}
+
+	return credentials[0], nil
+}
+
+// GetUserCredentials retrieves all WebAuthn credentials of a user, newest first
+func (u *DefaultUser) GetUserCredentials(ctx context.Context, userID string) ([]maps.MapStrAny, error) {
+	m := model.Select(u.credentialModel)
+	credentials, err := m.Get(model.QueryParam{
+		Select: DefaultCredentialFields,
+		Wheres: []model.QueryWhere{
+			{Column: "user_id", Value: userID},
+		},
+		Orders: []model.QueryOrder{
+			{Column: "id", Option: "desc"},
+		},
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf(ErrFailedToGetCredential, err)
+	}
+
+	return credentials, nil
+}
+
+// UpdateCredential updates the name, sign count or last use of a WebAuthn credential
+func (u *DefaultUser) UpdateCredential(ctx context.Context, credentialID string, credentialData maps.MapStrAny) error {
+	// The key material and owner never change
+	immutableFields := []string{"id", "credential_id", "user_id", "rp_id", "public_key", "algorithm", "created_at"}
+	for _, field := range immutableFields {
+		delete(credentialData, field)
+	}
+
+	if len(credentialData) == 0 {
+		return nil
+	}
+
+	m := model.Select(u.credentialModel)
+	affected, err := m.UpdateWhere(model.QueryParam{
+		Wheres: []model.QueryWhere{
+			{Column: "credential_id", Value: credentialID},
+		},
+		Limit: 1,
+	}, credentialData)
+
+	if err != nil {
+		return fmt.Errorf(ErrFailedToUpdateCredential, err)
+	}
+
+	if affected == 0 {
+		if _, err := u.GetCredential(ctx, credentialID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteCredential removes a WebAuthn credential of a user
+func (u *DefaultUser) DeleteCredential(ctx context.Context, userID string, credentialID string) error {
+	m := model.Select(u.credentialModel)
+	affected, err := m.DeleteWhere(model.QueryParam{
+		Wheres: []model.QueryWhere{
+			{Column: "user_id", Value: userID},
+			{Column: "credential_id", Value: credentialID},
+		},
+		Limit: 1,
+	})
+
+	if err != nil {
+		return fmt.Errorf(ErrFailedToDeleteCredential, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf(ErrCredentialNotFound)
+	}
+
+	return nil
+}
+
+// DeleteUserCredentials removes all WebAuthn credentials of a user
+func (u *DefaultUser) DeleteUserCredentials(ctx context.Context, userID string) error {
+	m := model.Select(u.credentialModel)
+	_, err := m.DeleteWhere(model.QueryParam{
+		Wheres: []model.QueryWhere{
+			{Column: "user_id", Value: userID},
+		},
+	})
+
+	if err != nil {
+		return fmt.Errorf(ErrFailedToDeleteCredential, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package user_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaoapp/kun/maps"
+)
+
+func TestCredentialOperations(t *testing.T) {
+	prepare(t)
+	defer clean()
+
+	ctx := context.Background()
+	testUUID := strings.ReplaceAll(uuid.New().String(), "-", "")[:8]
+	_, testUserID := setupTestUser(t, ctx, createTestUserData("credential"+testUUID))
+	defer testProvider.DeleteUserCredentials(ctx, testUserID)
+
+	credentialID := "cred_" + testUUID
+	_, err := testProvider.CreateCredential(ctx, testUserID, maps.MapStrAny{
+		"credential_id": credentialID,
+		"name":          "Security Key",
+		"rp_id":         "localhost",
+		"public_key":    "MFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAE",
+		"algorithm":     -7,
+		"transports":    []string{"usb"},
+	})
+	require.NoError(t, err)
+
+	t.Run("Get", func(t *testing.T) {
+		credential, err := testProvider.GetCredential(ctx, credentialID)
+		require.NoError(t, err)
+		assert.Equal(t, testUserID, credential["user_id"])
+		assert.Equal(t, "localhost", credential["rp_id"])
+
+		credentials, err := testProvider.GetUserCredentials(ctx, testUserID)
+		require.NoError(t, err)
+		assert.Len(t, credentials, 1)
+
+		_, err = testProvider.GetCredential(ctx, "cred_not_exists_"+testUUID)
+		assert.Error(t, err)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		err := testProvider.UpdateCredential(ctx, credentialID, maps.MapStrAny{
+			"sign_count": 5,
+			"user_id":    "another_user", // Ignored
+		})
+		require.NoError(t, err)
+
+		credential, err := testProvider.GetCredential(ctx, credentialID)
+		require.NoError(t, err)
+		assert.Equal(t, testUserID, credential["user_id"])
+		assert.EqualValues(t, 5, credential["sign_count"])
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		// Only the owner can delete a credential
+		err := testProvider.DeleteCredential(ctx, "another_user", credentialID)
+		assert.Error(t, err)
+
+		err = testProvider.DeleteCredential(ctx, testUserID, credentialID)
+		require.NoError(t, err)
+
+		credentials, err := testProvider.GetUserCredentials(ctx, testUserID)
+		require.NoError(t, err)
+		assert.Len(t, credentials, 0)
+	})
+}
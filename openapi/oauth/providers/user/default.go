@@ -63,6 +63,23 @@ const (
 	ErrFailedToVerifyMFACode     = "failed to verify MFA code: %w"
 	ErrFailedToUpdateMFAStatus   = "failed to update MFA status: %w"
 	ErrRecoveryCodeNotFound      = "recovery code not found or already used"
+	ErrInvalidMFAChannel         = "invalid MFA channel: %s"
+
+	// Credential related errors
+	ErrCredentialNotFound       = "credential not found"
+	ErrFailedToGetCredential    = "failed to get credential: %w"
+	ErrFailedToCreateCredential = "failed to create credential: %w"
+	ErrFailedToUpdateCredential = "failed to update credential: %w"
+	ErrFailedToDeleteCredential = "failed to delete credential: %w"
+
+	// API Key related errors
+	ErrAPIKeyNotFound         = "api key not found"
+	ErrAPIKeyExpired          = "api key has expired"
+	ErrFailedToGenerateAPIKey = "failed to generate api key: %w"
+	ErrFailedToGetAPIKey      = "failed to get api key: %w"
+	ErrFailedToCreateAPIKey   = "failed to create api key: %w"
+	ErrFailedToUpdateAPIKey   = "failed to update api key: %w"
+	ErrFailedToDeleteAPIKey   = "failed to delete api key: %w"
 )
 
 // Default field lists - used when not configured
@@ -72,7 +89,7 @@ var (
 		"id", "user_id", "preferred_username", "email", "email_verified", "name", "given_name", "family_name",
 		"middle_name", "nickname", "profile", "picture", "website", "gender", "birthdate", "zoneinfo", "locale",
 		"phone_number", "phone_number_verified", "address", "theme", "status", "role_id", "type_id",
-		"mfa_enabled", "last_login_at", "last_login_ip", "last_login_user_agent", "last_login_device",
+		"mfa_enabled", "mfa_sms_enabled", "mfa_email_enabled", "last_login_at", "last_login_ip", "last_login_user_agent", "last_login_device",
 		"last_login_platform", "metadata", "created_at", "updated_at",
 	}
 
@@ -85,13 +102,25 @@ var (
 	// DefaultAuthUserFields contains fields needed for authentication
 	DefaultAuthUserFields = []interface{}{
 		"id", "user_id", "preferred_username", "email", "password_hash", "status", "role_id", "type_id",
-		"email_verified", "phone_number_verified", "mfa_enabled", "last_login_at",
+		"email_verified", "phone_number_verified", "mfa_enabled", "mfa_sms_enabled", "mfa_email_enabled", "last_login_at",
 	}
 
 	// DefaultMFAUserFields contains fields needed for MFA authentication
 	DefaultMFAUserFields = []interface{}{
 		"id", "user_id", "mfa_enabled", "mfa_secret", "mfa_issuer", "mfa_algorithm",
-		"mfa_digits", "mfa_period", "mfa_recovery_hash", "mfa_enabled_at",
+		"mfa_digits", "mfa_period", "mfa_recovery_hash", "mfa_enabled_at", "mfa_sms_enabled", "mfa_email_enabled",
+	}
+
+	// DefaultCredentialFields contains WebAuthn credential fields
+	DefaultCredentialFields = []interface{}{
+		"id", "credential_id", "user_id", "name", "rp_id", "public_key", "algorithm", "sign_count",
+		"aaguid", "transports", "user_verified", "last_used_at", "created_at", "updated_at",
+	}
+
+	// DefaultAPIKeyFields contains API key fields, the key hash is never returned
+	DefaultAPIKeyFields = []interface{}{
+		"id", "key_id", "user_id", "team_id", "name", "key_prefix", "scopes", "expires_at",
+		"last_used_at", "last_used_ip", "created_at", "updated_at",
 	}
 
 	// DefaultOAuthAccountFields contains basic OAuth account fields
@@ -192,6 +221,8 @@ type DefaultUser struct {
 	roleModel         string
 	typeModel         string
 	oauthAccountModel string
+	credentialModel   string
+	apiKeyModel       string
 	teamModel         string
 	memberModel       string
 	invitationModel   string
@@ -248,6 +279,8 @@ type DefaultUserOptions struct {
 	RoleModel         string // bind to a specific role model
 	TypeModel         string // bind to a specific type model
 	OAuthAccountModel string // bind to a specific oauth account model
+	CredentialModel   string // bind to a specific webauthn credential model
+	APIKeyModel       string // bind to a specific api key model
 	TeamModel         string // bind to a specific team model
 	MemberModel       string // bind to a specific member model
 	InvitationModel   string // bind to a specific invitation code model
@@ -309,6 +342,16 @@ func NewDefaultUser(options *DefaultUserOptions) *DefaultUser {
 		oauthAccountModel = "__yao.user.oauth_account"
 	}
 
+	credentialModel := options.CredentialModel
+	if credentialModel == "" {
+		credentialModel = "__yao.user.credential"
+	}
+
+	apiKeyModel := options.APIKeyModel
+	if apiKeyModel == "" {
+		apiKeyModel = "__yao.user.api_key"
+	}
+
 	teamModel := options.TeamModel
 	if teamModel == "" {
 		teamModel = "__yao.team"
@@ -411,6 +454,8 @@ func NewDefaultUser(options *DefaultUserOptions) *DefaultUser {
 		roleModel:         roleModel,
 		typeModel:         typeModel,
 		oauthAccountModel: oauthAccountModel,
+		credentialModel:   credentialModel,
+		apiKeyModel:       apiKeyModel,
 		teamModel:         teamModel,
 		memberModel:       memberModel,
 		invitationModel:   invitationModel,
@@ -392,6 +392,7 @@ func (u *DefaultUser) UpdateUser(ctx context.Context, userID string, userData ma
 	sensitiveFields := []string{
 		"password", "password_hash", "password_changed_at",
 		"mfa_secret", "mfa_recovery_hash", "mfa_enabled", "mfa_enabled_at",
+		"mfa_sms_enabled", "mfa_email_enabled",
 	}
 
 	for _, field := range sensitiveFields {
@@ -459,6 +460,18 @@ func (u *DefaultUser) DeleteUser(ctx context.Context, userID string) error {
 		log.Warn("Failed to delete OAuth accounts for user %s: %v", userID, err)
 	}
 
+	// Delete all WebAuthn credentials for this user
+	err = u.DeleteUserCredentials(ctx, userID)
+	if err != nil {
+		log.Warn("Failed to delete credentials for user %s: %v", userID, err)
+	}
+
+	// Revoke all API keys of this user
+	err = u.DeleteUserAPIKeys(ctx, userID)
+	if err != nil {
+		log.Warn("Failed to delete api keys for user %s: %v", userID, err)
+	}
+
 	// 2. Clear user role assignment (set role_id to null)
 	err = u.ClearUserRole(ctx, userID)
 	if err != nil {
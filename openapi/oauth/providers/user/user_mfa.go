@@ -294,6 +294,40 @@ func (u *DefaultUser) DisableMFA(ctx context.Context, userID string, code string
 	return nil
 }
 
+// ResetMFA disables TOTP without a code, for users who lost their authenticator
+// The caller must have verified the user another way (e.g. a code sent by email).
+func (u *DefaultUser) ResetMFA(ctx context.Context, userID string) error {
+	m := model.Select(u.model)
+	affected, err := m.UpdateWhere(model.QueryParam{
+		Wheres: []model.QueryWhere{
+			{Column: "user_id", Value: userID},
+		},
+		Limit: 1,
+	}, maps.MapStrAny{
+		"mfa_enabled":          false,
+		"mfa_secret":           nil,
+		"mfa_recovery_hash":    nil,
+		"mfa_enabled_at":       nil,
+		"mfa_last_verified_at": nil,
+	})
+
+	if err != nil {
+		return fmt.Errorf(ErrFailedToUpdateMFAStatus, err)
+	}
+
+	if affected == 0 {
+		exists, checkErr := u.UserExists(ctx, userID)
+		if checkErr != nil {
+			return fmt.Errorf(ErrFailedToUpdateMFAStatus, checkErr)
+		}
+		if !exists {
+			return fmt.Errorf(ErrUserNotFound)
+		}
+	}
+
+	return nil
+}
+
 // VerifyMFACode verifies a TOTP code for user
 func (u *DefaultUser) VerifyMFACode(ctx context.Context, userID string, code string) (bool, error) {
 	// Get user and MFA status
@@ -576,6 +610,7 @@ func (u *DefaultUser) GetMFAConfig(ctx context.Context, userID string) (maps.Map
 			"user_id", "mfa_enabled", "mfa_issuer", "mfa_algorithm",
 			"mfa_digits", "mfa_period", "mfa_enabled_at", "mfa_last_verified_at",
 			"mfa_recovery_hash", // Include recovery hash field
+			"mfa_sms_enabled", "mfa_email_enabled",
 		},
 		Wheres: []model.QueryWhere{
 			{Column: "user_id", Value: userID},
@@ -602,8 +637,10 @@ func (u *DefaultUser) GetMFAConfig(ctx context.Context, userID string) (maps.Map
 	}
 
 	config := maps.MapStrAny{
-		"user_id":     userID,
-		"mfa_enabled": mfaEnabled,
+		"user_id":           userID,
+		"mfa_enabled":       mfaEnabled,
+		"mfa_sms_enabled":   toBool(user["mfa_sms_enabled"]),
+		"mfa_email_enabled": toBool(user["mfa_email_enabled"]),
 	}
 
 	if mfaEnabled {
@@ -633,6 +670,44 @@ func (u *DefaultUser) GetMFAConfig(ctx context.Context, userID string) (maps.Map
 	return config, nil
 }
 
+// SetOTPMFAEnabled enables or disables one-time codes sent by "sms" or "email" as a second factor
+// Whether the user owns the phone number or email address is checked by the caller.
+func (u *DefaultUser) SetOTPMFAEnabled(ctx context.Context, userID string, channel string, enabled bool) error {
+	var column string
+	switch channel {
+	case "sms":
+		column = "mfa_sms_enabled"
+	case "email":
+		column = "mfa_email_enabled"
+	default:
+		return fmt.Errorf(ErrInvalidMFAChannel, channel)
+	}
+
+	m := model.Select(u.model)
+	affected, err := m.UpdateWhere(model.QueryParam{
+		Wheres: []model.QueryWhere{
+			{Column: "user_id", Value: userID},
+		},
+		Limit: 1,
+	}, maps.MapStrAny{column: enabled})
+
+	if err != nil {
+		return fmt.Errorf(ErrFailedToUpdateMFAStatus, err)
+	}
+
+	if affected == 0 {
+		exists, checkErr := u.UserExists(ctx, userID)
+		if checkErr != nil {
+			return fmt.Errorf(ErrFailedToUpdateMFAStatus, checkErr)
+		}
+		if !exists {
+			return fmt.Errorf(ErrUserNotFound)
+		}
+	}
+
+	return nil
+}
+
 // Helper function to generate recovery codes
 func generateRecoveryCode(length int) (string, error) {
 	// Use alphanumeric charset (excluding similar-looking characters for better UX)
@@ -660,3 +735,16 @@ func generateRecoveryCode(length int) (string, error) {
 
 	return result, nil
 }
+
+// toBool converts a boolean column value, databases return bool or integers
+func toBool(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case int64:
+		return v != 0
+	case int:
+		return v != 0
+	}
+	return false
+}
@@ -479,3 +479,33 @@ func TestMFAStateMachine(t *testing.T) {
 		assert.Contains(t, err.Error(), "MFA is not enabled")
 	})
 }
+
+func TestOTPMFA(t *testing.T) {
+	prepare(t)
+	defer clean()
+
+	ctx := context.Background()
+	testUUID := strings.ReplaceAll(uuid.New().String(), "-", "")[:8]
+	_, testUserID := setupTestUser(t, ctx, createTestUserData(testUUID))
+
+	require.NoError(t, testProvider.SetOTPMFAEnabled(ctx, testUserID, "sms", true))
+	config, err := testProvider.GetMFAConfig(ctx, testUserID)
+	require.NoError(t, err)
+	assert.Equal(t, true, config["mfa_sms_enabled"])
+	assert.Equal(t, false, config["mfa_email_enabled"])
+	assert.Equal(t, false, config["mfa_enabled"]) // TOTP is independent
+
+	// Profile updates cannot switch it off
+	require.NoError(t, testProvider.UpdateUser(ctx, testUserID, map[string]interface{}{"mfa_sms_enabled": false}))
+	config, err = testProvider.GetMFAConfig(ctx, testUserID)
+	require.NoError(t, err)
+	assert.Equal(t, true, config["mfa_sms_enabled"])
+
+	require.NoError(t, testProvider.SetOTPMFAEnabled(ctx, testUserID, "sms", false))
+	config, err = testProvider.GetMFAConfig(ctx, testUserID)
+	require.NoError(t, err)
+	assert.Equal(t, false, config["mfa_sms_enabled"])
+
+	assert.Error(t, testProvider.SetOTPMFAEnabled(ctx, testUserID, "fax", true))
+	assert.Error(t, testProvider.SetOTPMFAEnabled(ctx, "not_exists_"+testUUID, "email", true))
+}
@@ -0,0 +1,203 @@
+package oauth
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/yao/openapi/oauth/authorized"
+	"github.com/yaoapp/yao/openapi/oauth/types"
+	"github.com/yaoapp/yao/openapi/response"
+)
+
+// RateLimitResult is the state of a rate limit key after counting a request
+type RateLimitResult struct {
+	Allowed   bool          // Whether the request is within the limit
+	Limit     int           // Requests allowed per window
+	Remaining int           // Requests left in the current window
+	Reset     time.Duration // Time until the current window ends
+}
+
+// RateLimit counts the request against its rate limit key and sets the RateLimit-* headers
+// Returns false, with a 429 sent, when the limit is exceeded.
+// Store errors never block requests.
+func (s *Service) RateLimit(c *gin.Context) bool {
+	if !s.config.Security.RateLimitEnabled {
+		return true
+	}
+
+	result, err := s.CheckRateLimit(s.rateLimitKey(c), time.Now())
+	if err != nil {
+		log.Warn("[OAuth] Rate limit check failed: %v", err)
+		return true
+	}
+
+	s.setRateLimitHeaders(c, result)
+	if !result.Allowed {
+		s.respondRateLimited(c, result)
+		return false
+	}
+	return true
+}
+
+// rateLimitAnonymous rejects, with a 429 sent, requests from a client IP that has used up its
+// limit of requests without a valid credential. It runs before authentication and does not
+// count the request, failed authentications are counted by countAnonymous.
+func (s *Service) rateLimitAnonymous(c *gin.Context) bool {
+	if !s.config.Security.RateLimitEnabled {
+		return true
+	}
+
+	result, err := s.checkRateLimit(s.anonymousRateLimitKey(c), time.Now(), false)
+	if err != nil {
+		log.Warn("[OAuth] Rate limit check failed: %v", err)
+		return true
+	}
+
+	if !result.Allowed {
+		s.setRateLimitHeaders(c, result)
+		s.respondRateLimited(c, result)
+		return false
+	}
+	return true
+}
+
+// countAnonymous counts a request that failed authentication against its client IP
+func (s *Service) countAnonymous(c *gin.Context) {
+	if !s.config.Security.RateLimitEnabled {
+		return
+	}
+
+	if _, err := s.checkRateLimit(s.anonymousRateLimitKey(c), time.Now(), true); err != nil {
+		log.Warn("[OAuth] Rate limit check failed: %v", err)
+	}
+}
+
+// setRateLimitHeaders sets the RateLimit-* headers of a rate limit result
+func (s *Service) setRateLimitHeaders(c *gin.Context, result *RateLimitResult) {
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(resetSeconds(result)))
+	c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", result.Limit, int(s.config.Security.RateLimitWindow.Seconds())))
+}
+
+// respondRateLimited sends the 429 response of an exceeded rate limit
+func (s *Service) respondRateLimited(c *gin.Context, result *RateLimitResult) {
+	c.Header("Retry-After", strconv.Itoa(resetSeconds(result)))
+	response.RespondWithError(c, http.StatusTooManyRequests, types.ErrRateLimitExceeded)
+	c.Abort()
+}
+
+// CheckRateLimit counts a request for key at now and checks it against the configured limit
+//
+// It is a sliding window counter: the count of the previous window, weighted by how much
+// of it still overlaps the sliding window, plus the count of the current window.
+// Counters live in the OAuth store, so the limit is shared by all instances using it.
+func (s *Service) CheckRateLimit(key string, now time.Time) (*RateLimitResult, error) {
+	return s.checkRateLimit(key, now, true)
+}
+
+// checkRateLimit checks key at now against the configured limit, counting the request when count is true
+// Without counting, the result tells whether one more request is allowed.
+func (s *Service) checkRateLimit(key string, now time.Time, count bool) (*RateLimitResult, error) {
+	limit := s.config.Security.RateLimitRequests
+	window := s.config.Security.RateLimitWindow
+
+	index := now.UnixNano() / int64(window)
+	elapsed := time.Duration(now.UnixNano() - index*int64(window))
+	current := s.rateLimitCounterKey(key, index)
+	previous := s.rateLimitCounterKey(key, index-1)
+
+	// Create the counter with an expiry first, Incr keeps it
+	value, err := s.store.GetSet(current, 2*window, func(string) (interface{}, error) { return int64(0), nil })
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rate limit counter: %w", err)
+	}
+	currCount := toInt64(value)
+	if count {
+		currCount, err = s.store.Incr(current, 1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count request: %w", err)
+		}
+	}
+
+	var prevCount int64
+	if value, ok := s.store.Get(previous); ok {
+		prevCount = toInt64(value)
+	}
+
+	weight := float64(window-elapsed) / float64(window)
+	estimated := int(math.Floor(float64(prevCount)*weight)) + int(currCount)
+
+	remaining := limit - estimated
+	if remaining < 0 {
+		remaining = 0
+	}
+	allowed := estimated <= limit
+	if !count {
+		allowed = estimated < limit
+	}
+	return &RateLimitResult{
+		Allowed:   allowed,
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     window - elapsed,
+	}, nil
+}
+
+// rateLimitKey identifies who an authenticated request is counted for:
+// the OAuth client (when limiting by client ID), the API key, the user, or the client IP
+func (s *Service) rateLimitKey(c *gin.Context) string {
+	info := authorized.GetInfo(c)
+	if s.config.Security.RateLimitByClientID && info != nil && info.ClientID != "" {
+		return "client:" + info.ClientID
+	}
+	if info != nil && info.APIKeyID != "" {
+		return "apikey:" + info.APIKeyID
+	}
+	if info != nil && info.UserID != "" {
+		return "user:" + info.UserID
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// anonymousRateLimitKey identifies the client IP requests without a valid credential are counted for
+func (s *Service) anonymousRateLimitKey(c *gin.Context) string {
+	return "anonymous:" + c.ClientIP()
+}
+
+// rateLimitCounterKey generates a key for the request counter of a window
+func (s *Service) rateLimitCounterKey(key string, window int64) string {
+	return fmt.Sprintf("%soauth:ratelimit:%s:%d", s.prefix, key, window)
+}
+
+// resetSeconds returns the time until the window of result ends, rounded up to seconds
+func resetSeconds(result *RateLimitResult) int {
+	return int(math.Ceil(result.Reset.Seconds()))
+}
+
+func toInt64(value interface{}) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint64:
+		return int64(v)
+	case float64:
+		return int64(v)
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	case []byte:
+		n, _ := strconv.ParseInt(string(v), 10, 64)
+		return n
+	}
+	return 0
+}
@@ -0,0 +1,188 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaoapp/kun/maps"
+	"github.com/yaoapp/yao/openapi/oauth/providers/user"
+)
+
+func TestCheckRateLimit(t *testing.T) {
+	service, _, _, cleanup := setupOAuthTestEnvironment(t)
+	defer cleanup()
+
+	service.config.Security.RateLimitRequests = 3
+	service.config.Security.RateLimitWindow = time.Minute
+	key := "user:ratelimit_test_" + time.Now().Format("150405.000000")
+
+	// Start of a window, nothing in the previous one
+	start := time.Now().Truncate(time.Minute).Add(time.Minute)
+
+	t.Run("within the limit", func(t *testing.T) {
+		for i := 1; i <= 3; i++ {
+			result, err := service.CheckRateLimit(key, start.Add(time.Duration(i)*time.Second))
+			require.NoError(t, err)
+			assert.True(t, result.Allowed)
+			assert.Equal(t, 3, result.Limit)
+			assert.Equal(t, 3-i, result.Remaining)
+		}
+	})
+
+	t.Run("over the limit", func(t *testing.T) {
+		result, err := service.CheckRateLimit(key, start.Add(10*time.Second))
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, 0, result.Remaining)
+		assert.Equal(t, 50*time.Second, result.Reset)
+	})
+
+	t.Run("previous window slides out", func(t *testing.T) {
+		// 4 requests in the previous window, weighted 3/4 at 15s in
+		result, err := service.CheckRateLimit(key, start.Add(time.Minute+15*time.Second))
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+
+		// Weighted 1/6 at 50s in: 0 + 2 requests in this window
+		result, err = service.CheckRateLimit(key, start.Add(time.Minute+50*time.Second))
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+	})
+
+	t.Run("keys are independent", func(t *testing.T) {
+		result, err := service.CheckRateLimit(key+"_other", start.Add(10*time.Second))
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 2, result.Remaining)
+	})
+}
+
+func TestRateLimitGuard(t *testing.T) {
+	service, _, _, cleanup := setupOAuthTestEnvironment(t)
+	defer cleanup()
+	gin.SetMode(gin.TestMode)
+
+	service.config.Security.RateLimitRequests = 1
+	service.config.Security.RateLimitWindow = time.Minute
+	suffix := time.Now().Format("150405.000000")
+
+	// guardFrom sends a request with the bearer token from the client address through the OAuth guard
+	guardFrom := func(addr, token string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		if token != "" {
+			c.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+		c.Request.RemoteAddr = addr
+
+		service.Guard(c)
+		if !c.IsAborted() {
+			c.Status(http.StatusOK)
+		}
+		return w
+	}
+	guard := func(token string) *httptest.ResponseRecorder {
+		return guardFrom("203.0.113.7:1234", token)
+	}
+
+	// Client addresses unique to this run, counters of previous runs may still be in the window
+	run := time.Now().UnixNano() & 0xffff
+	addr := func(n int) string {
+		return fmt.Sprintf("[2001:db8:%x::%d]:1234", run, n)
+	}
+
+	userToken := func(userID string) string {
+		clientID := "ratelimit-client-" + suffix
+		subject, err := service.Subject(clientID, userID)
+		require.NoError(t, err)
+		token, err := service.MakeAccessToken(clientID, "openid", subject, 3600)
+		require.NoError(t, err)
+		return token
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		service.config.Security.RateLimitEnabled = false
+		w := guard(userToken("ratelimit_user_disabled_" + suffix))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+	})
+
+	t.Run("keyed by user", func(t *testing.T) {
+		service.config.Security.RateLimitEnabled = true
+		token := userToken("ratelimit_user_a_" + suffix)
+
+		w := guard(token)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "1", w.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "1;w=60", w.Header().Get("RateLimit-Policy"))
+
+		w = guard(token)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+		// Another user from the same IP has its own limit
+		w = guard(userToken("ratelimit_user_b_" + suffix))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("keyed by API key", func(t *testing.T) {
+		service.config.Security.RateLimitEnabled = true
+		ctx := context.Background()
+		userID := "ratelimit_apikey_user_" + suffix
+		defer service.userProvider.DeleteUserAPIKeys(ctx, userID)
+
+		_, first, err := service.userProvider.CreateAPIKey(ctx, userID, maps.MapStrAny{"name": "First"})
+		require.NoError(t, err)
+		_, second, err := service.userProvider.CreateAPIKey(ctx, userID, maps.MapStrAny{"name": "Second"})
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, guard(first).Code)
+		assert.Equal(t, http.StatusTooManyRequests, guard(first).Code)
+
+		// Keys of the same user are limited independently
+		assert.Equal(t, http.StatusOK, guard(second).Code)
+	})
+
+	t.Run("anonymous keyed by IP", func(t *testing.T) {
+		service.config.Security.RateLimitEnabled = true
+		client, other := addr(1), addr(2)
+
+		w := guardFrom(client, "")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		w = guardFrom(client, "")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "1", w.Header().Get("RateLimit-Limit"))
+		assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+		// Other addresses have their own limit
+		assert.Equal(t, http.StatusUnauthorized, guardFrom(other, "").Code)
+	})
+
+	t.Run("failed authentication keyed by IP", func(t *testing.T) {
+		service.config.Security.RateLimitEnabled = true
+		client := addr(3)
+
+		assert.Equal(t, http.StatusUnauthorized, guardFrom(client, "invalid-token").Code)
+		assert.Equal(t, http.StatusTooManyRequests, guardFrom(client, "invalid-token").Code)
+		assert.Equal(t, http.StatusTooManyRequests, guardFrom(client, user.APIKeyPrefix+"invalid").Code)
+	})
+
+	t.Run("valid credentials are not counted by IP", func(t *testing.T) {
+		service.config.Security.RateLimitEnabled = true
+		client := addr(4)
+
+		assert.Equal(t, http.StatusOK, guardFrom(client, userToken("ratelimit_user_c_"+suffix)).Code)
+		assert.Equal(t, http.StatusOK, guardFrom(client, userToken("ratelimit_user_d_"+suffix)).Code)
+		assert.Equal(t, http.StatusUnauthorized, guardFrom(client, "").Code)
+	})
+}
@@ -190,11 +190,31 @@ type UserProvider interface {
 	GenerateMFASecret(ctx context.Context, userID string, options *MFAOptions) (string, string, error)
 	EnableMFA(ctx context.Context, userID string, secret string, code string) error
 	DisableMFA(ctx context.Context, userID string, code string) error
+	ResetMFA(ctx context.Context, userID string) error
 	VerifyMFACode(ctx context.Context, userID string, code string) (bool, error)
 	GenerateRecoveryCodes(ctx context.Context, userID string) ([]string, error)
 	VerifyRecoveryCode(ctx context.Context, userID string, code string) (bool, error)
 	IsMFAEnabled(ctx context.Context, userID string) (bool, error)
 	GetMFAConfig(ctx context.Context, userID string) (maps.MapStrAny, error)
+	SetOTPMFAEnabled(ctx context.Context, userID string, channel string, enabled bool) error
+
+	// User WebAuthn Credentials
+	CreateCredential(ctx context.Context, userID string, credentialData maps.MapStrAny) (interface{}, error)
+	GetCredential(ctx context.Context, credentialID string) (maps.MapStrAny, error)
+	GetUserCredentials(ctx context.Context, userID string) ([]maps.MapStrAny, error)
+	UpdateCredential(ctx context.Context, credentialID string, credentialData maps.MapStrAny) error
+	DeleteCredential(ctx context.Context, userID string, credentialID string) error
+	DeleteUserCredentials(ctx context.Context, userID string) error
+
+	// User API Keys
+	CreateAPIKey(ctx context.Context, userID string, apiKeyData maps.MapStrAny) (string, string, error)
+	GetAPIKey(ctx context.Context, keyID string) (maps.MapStrAny, error)
+	GetUserAPIKeys(ctx context.Context, userID string, teamID string) ([]maps.MapStrAny, error)
+	UpdateAPIKey(ctx context.Context, keyID string, apiKeyData maps.MapStrAny) error
+	RegenerateAPIKey(ctx context.Context, keyID string) (string, error)
+	DeleteAPIKey(ctx context.Context, keyID string) error
+	DeleteUserAPIKeys(ctx context.Context, userID string) error
+	VerifyAPIKey(ctx context.Context, key string, ip string) (maps.MapStrAny, error)
 
 	// ============================================================================
 	// OAuth Account Resource
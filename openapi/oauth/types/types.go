@@ -630,6 +630,7 @@ type AuthorizedInfo struct {
 	RememberMe bool   `json:"remember_me,omitempty"` // Remember Me flag preserved from login
 	AuthSource string `json:"auth_source,omitempty"` // Authentication source preserved from login
 	OAuthEmail string `json:"oauth_email,omitempty"` // OAuth account email preserved from login
+	APIKeyID   string `json:"api_key_id,omitempty"`  // API key used to authenticate, empty for tokens
 
 	// Data access constraints (set by ACL enforcement)
 	Constraints DataConstraints `json:"constraints,omitempty"`
@@ -0,0 +1,399 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// COSE algorithm identifiers supported for credential public keys
+const (
+	AlgES256 = -7
+	AlgEdDSA = -8
+	AlgRS256 = -257
+)
+
+// Authenticator data flags
+const (
+	FlagUserPresent            byte = 0x01
+	FlagUserVerified           byte = 0x04
+	FlagAttestedCredentialData byte = 0x40
+)
+
+// Client data types
+const (
+	TypeCreate = "webauthn.create"
+	TypeGet    = "webauthn.get"
+)
+
+// DefaultTimeout is the ceremony timeout sent to the browser, in milliseconds
+const DefaultTimeout = 300000
+
+// RelyingParty is the site credentials are scoped to
+type RelyingParty struct {
+	ID      string   // Domain, e.g. "example.com"
+	Name    string   // Display name
+	Origins []string // Allowed origins, e.g. "https://example.com"
+}
+
+// ClientData is the collected client data signed by the authenticator
+type ClientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin,omitempty"`
+}
+
+// AuthenticatorData is the parsed authenticator data
+type AuthenticatorData struct {
+	RPIDHash     []byte
+	Flags        byte
+	SignCount    uint32
+	AAGUID       []byte // Only with attested credential data
+	CredentialID []byte // Only with attested credential data
+}
+
+// Registration is the result of navigator.credentials.create(), binary fields are base64url
+// The public key is the SubjectPublicKeyInfo returned by AuthenticatorAttestationResponse.getPublicKey()
+// and the authenticator data the one returned by getAuthenticatorData(), so no CBOR decoding is needed.
+// Attestation statements are not verified.
+type Registration struct {
+	ID                 string   `json:"id"`
+	ClientDataJSON     string   `json:"client_data_json"`
+	AuthenticatorData  string   `json:"authenticator_data"`
+	PublicKey          string   `json:"public_key"`
+	PublicKeyAlgorithm int      `json:"public_key_algorithm"`
+	Transports         []string `json:"transports,omitempty"`
+}
+
+// Assertion is the result of navigator.credentials.get(), binary fields are base64url
+type Assertion struct {
+	ID                string `json:"id"`
+	ClientDataJSON    string `json:"client_data_json"`
+	AuthenticatorData string `json:"authenticator_data"`
+	Signature         string `json:"signature"`
+	UserHandle        string `json:"user_handle,omitempty"`
+}
+
+// Credential is a verified credential to store
+type Credential struct {
+	ID           string   // base64url credential ID
+	PublicKey    []byte   // DER encoded SubjectPublicKeyInfo
+	Algorithm    int      // COSE algorithm
+	SignCount    uint32   // Signature counter
+	AAGUID       string   // Authenticator model, hex
+	Transports   []string // Transport hints
+	UserVerified bool     // Whether the user was verified during registration
+}
+
+// CredentialDescriptor identifies a credential in options
+type CredentialDescriptor struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"`
+	Transports []string `json:"transports,omitempty"`
+}
+
+// CredentialParameter is a supported credential type and algorithm
+type CredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int    `json:"alg"`
+}
+
+// CreationOptions are the options for navigator.credentials.create(), binary fields are base64url
+type CreationOptions struct {
+	Challenge              string                 `json:"challenge"`
+	RP                     map[string]string      `json:"rp"`
+	User                   map[string]string      `json:"user"`
+	PubKeyCredParams       []CredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int                    `json:"timeout"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials,omitempty"`
+	AuthenticatorSelection map[string]string      `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+// RequestOptions are the options for navigator.credentials.get(), binary fields are base64url
+type RequestOptions struct {
+	Challenge        string                 `json:"challenge"`
+	Timeout          int                    `json:"timeout"`
+	RPID             string                 `json:"rpId"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials,omitempty"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+// NewChallenge generates a random challenge, base64url encoded
+func NewChallenge() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate challenge: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// CreationOptions returns the options to register a credential for a user
+func (rp *RelyingParty) CreationOptions(challenge, userID, name, displayName string, exclude []CredentialDescriptor) *CreationOptions {
+	if displayName == "" {
+		displayName = name
+	}
+	return &CreationOptions{
+		Challenge: challenge,
+		RP:        map[string]string{"id": rp.ID, "name": rp.Name},
+		User: map[string]string{
+			"id":          base64.RawURLEncoding.EncodeToString([]byte(userID)),
+			"name":        name,
+			"displayName": displayName,
+		},
+		PubKeyCredParams: []CredentialParameter{
+			{Type: "public-key", Alg: AlgES256},
+			{Type: "public-key", Alg: AlgEdDSA},
+			{Type: "public-key", Alg: AlgRS256},
+		},
+		Timeout:            DefaultTimeout,
+		ExcludeCredentials: exclude,
+		AuthenticatorSelection: map[string]string{
+			"residentKey":      "preferred",
+			"userVerification": "preferred",
+		},
+		Attestation: "none",
+	}
+}
+
+// RequestOptions returns the options to assert one of the allowed credentials
+func (rp *RelyingParty) RequestOptions(challenge string, allow []CredentialDescriptor) *RequestOptions {
+	return &RequestOptions{
+		Challenge:        challenge,
+		Timeout:          DefaultTimeout,
+		RPID:             rp.ID,
+		AllowCredentials: allow,
+		UserVerification: "preferred",
+	}
+}
+
+// VerifyRegistration verifies a registration against the challenge it was created for
+func (rp *RelyingParty) VerifyRegistration(challenge string, r *Registration) (*Credential, error) {
+	if r == nil {
+		return nil, fmt.Errorf("registration is required")
+	}
+
+	if _, err := rp.verifyClientData(r.ClientDataJSON, TypeCreate, challenge); err != nil {
+		return nil, err
+	}
+
+	rawAuthData, err := Decode(r.AuthenticatorData)
+	if err != nil {
+		return nil, fmt.Errorf("invalid authenticator data: %w", err)
+	}
+	authData, err := rp.verifyAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if authData.CredentialID == nil {
+		return nil, fmt.Errorf("authenticator data has no attested credential")
+	}
+
+	id, err := Decode(r.ID)
+	if err != nil || !bytes.Equal(id, authData.CredentialID) {
+		return nil, fmt.Errorf("credential ID does not match the authenticator data")
+	}
+
+	publicKey, err := Decode(r.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	if _, err := parsePublicKey(publicKey, r.PublicKeyAlgorithm); err != nil {
+		return nil, err
+	}
+
+	return &Credential{
+		ID:           base64.RawURLEncoding.EncodeToString(authData.CredentialID),
+		PublicKey:    publicKey,
+		Algorithm:    r.PublicKeyAlgorithm,
+		SignCount:    authData.SignCount,
+		AAGUID:       hex.EncodeToString(authData.AAGUID),
+		Transports:   r.Transports,
+		UserVerified: authData.Flags&FlagUserVerified != 0,
+	}, nil
+}
+
+// VerifyAssertion verifies an assertion of a stored credential against the challenge it was created for
+// Returns the new signature counter to store.
+func (rp *RelyingParty) VerifyAssertion(challenge string, credential *Credential, a *Assertion) (uint32, error) {
+	if a == nil || credential == nil {
+		return 0, fmt.Errorf("assertion and credential are required")
+	}
+
+	id, err := Decode(a.ID)
+	if err != nil || base64.RawURLEncoding.EncodeToString(id) != credential.ID {
+		return 0, fmt.Errorf("assertion is for another credential")
+	}
+
+	clientDataJSON, err := rp.verifyClientData(a.ClientDataJSON, TypeGet, challenge)
+	if err != nil {
+		return 0, err
+	}
+
+	rawAuthData, err := Decode(a.AuthenticatorData)
+	if err != nil {
+		return 0, fmt.Errorf("invalid authenticator data: %w", err)
+	}
+	authData, err := rp.verifyAuthenticatorData(rawAuthData)
+	if err != nil {
+		return 0, err
+	}
+
+	signature, err := Decode(a.Signature)
+	if err != nil {
+		return 0, fmt.Errorf("invalid signature: %w", err)
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(append([]byte{}, rawAuthData...), clientDataHash[:]...)
+	if err := verifySignature(credential.PublicKey, credential.Algorithm, signed, signature); err != nil {
+		return 0, err
+	}
+
+	// A counter that does not increase means the authenticator may have been cloned
+	if authData.SignCount != 0 || credential.SignCount != 0 {
+		if authData.SignCount <= credential.SignCount {
+			return 0, fmt.Errorf("signature counter did not increase, the authenticator may be cloned")
+		}
+	}
+	return authData.SignCount, nil
+}
+
+// ParseAuthenticatorData parses authenticator data, extensions are ignored
+func ParseAuthenticatorData(data []byte) (*AuthenticatorData, error) {
+	if len(data) < 37 {
+		return nil, fmt.Errorf("authenticator data is too short")
+	}
+
+	authData := &AuthenticatorData{
+		RPIDHash:  data[:32],
+		Flags:     data[32],
+		SignCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+
+	if authData.Flags&FlagAttestedCredentialData != 0 {
+		rest := data[37:]
+		if len(rest) < 18 {
+			return nil, fmt.Errorf("attested credential data is too short")
+		}
+		length := int(binary.BigEndian.Uint16(rest[16:18]))
+		if length == 0 || len(rest) < 18+length {
+			return nil, fmt.Errorf("invalid credential ID length")
+		}
+		authData.AAGUID = rest[:16]
+		authData.CredentialID = rest[18 : 18+length]
+	}
+	return authData, nil
+}
+
+// Decode decodes base64url, with or without padding
+func Decode(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+}
+
+// verifyClientData checks the type, challenge and origin of the client data, returns the raw JSON
+func (rp *RelyingParty) verifyClientData(encoded string, typ string, challenge string) ([]byte, error) {
+	raw, err := Decode(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client data: %w", err)
+	}
+
+	var clientData ClientData
+	if err := json.Unmarshal(raw, &clientData); err != nil {
+		return nil, fmt.Errorf("invalid client data: %w", err)
+	}
+
+	if clientData.Type != typ {
+		return nil, fmt.Errorf("invalid client data type: %s", clientData.Type)
+	}
+	if challenge == "" || subtle.ConstantTimeCompare([]byte(strings.TrimRight(clientData.Challenge, "=")), []byte(challenge)) != 1 {
+		return nil, fmt.Errorf("challenge does not match")
+	}
+
+	for _, origin := range rp.Origins {
+		if clientData.Origin == origin {
+			return raw, nil
+		}
+	}
+	return nil, fmt.Errorf("origin is not allowed: %s", clientData.Origin)
+}
+
+// verifyAuthenticatorData checks the RP ID hash and user presence
+func (rp *RelyingParty) verifyAuthenticatorData(raw []byte) (*AuthenticatorData, error) {
+	authData, err := ParseAuthenticatorData(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	rpIDHash := sha256.Sum256([]byte(rp.ID))
+	if !bytes.Equal(authData.RPIDHash, rpIDHash[:]) {
+		return nil, fmt.Errorf("relying party ID does not match")
+	}
+	if authData.Flags&FlagUserPresent == 0 {
+		return nil, fmt.Errorf("user was not present")
+	}
+	return authData, nil
+}
+
+// parsePublicKey parses a SubjectPublicKeyInfo and checks it matches the algorithm
+func parsePublicKey(der []byte, alg int) (crypto.PublicKey, error) {
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		if alg == AlgES256 && k.Curve == elliptic.P256() {
+			return k, nil
+		}
+	case ed25519.PublicKey:
+		if alg == AlgEdDSA {
+			return k, nil
+		}
+	case *rsa.PublicKey:
+		if alg == AlgRS256 {
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("public key does not match algorithm %d", alg)
+}
+
+// verifySignature verifies a signature made with the credential private key
+func verifySignature(der []byte, alg int, data []byte, signature []byte) error {
+	key, err := parsePublicKey(der, alg)
+	if err != nil {
+		return err
+	}
+
+	valid := false
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		valid = ecdsa.VerifyASN1(k, digest[:], signature)
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(k, data, signature)
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(data)
+		valid = rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature) == nil
+	}
+
+	if !valid {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
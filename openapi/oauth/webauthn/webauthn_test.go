@@ -0,0 +1,170 @@
+package webauthn_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaoapp/yao/openapi/oauth/webauthn"
+)
+
+var rp = &webauthn.RelyingParty{ID: "example.com", Name: "Example", Origins: []string{"https://example.com"}}
+
+var b64 = base64.RawURLEncoding.EncodeToString
+
+func clientData(t *testing.T, typ, challenge, origin string) []byte {
+	raw, err := json.Marshal(webauthn.ClientData{Type: typ, Challenge: challenge, Origin: origin})
+	require.NoError(t, err)
+	return raw
+}
+
+func authData(rpID string, flags byte, signCount uint32, credentialID []byte) []byte {
+	hash := sha256.Sum256([]byte(rpID))
+	data := append([]byte{}, hash[:]...)
+	data = append(data, flags)
+	data = binary.BigEndian.AppendUint32(data, signCount)
+	if credentialID != nil {
+		data = append(data, make([]byte, 16)...) // AAGUID
+		data = binary.BigEndian.AppendUint16(data, uint16(len(credentialID)))
+		data = append(data, credentialID...)
+	}
+	return data
+}
+
+func sign(t *testing.T, key *ecdsa.PrivateKey, authData, clientData []byte) []byte {
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	return signature
+}
+
+func TestRegistrationAndAssertion(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	credentialID := []byte("credential-1")
+
+	challenge, err := webauthn.NewChallenge()
+	require.NoError(t, err)
+
+	registration := &webauthn.Registration{
+		ID:                 b64(credentialID),
+		ClientDataJSON:     b64(clientData(t, webauthn.TypeCreate, challenge, "https://example.com")),
+		AuthenticatorData:  b64(authData("example.com", webauthn.FlagUserPresent|webauthn.FlagUserVerified|webauthn.FlagAttestedCredentialData, 0, credentialID)),
+		PublicKey:          b64(publicKey),
+		PublicKeyAlgorithm: webauthn.AlgES256,
+	}
+
+	credential, err := rp.VerifyRegistration(challenge, registration)
+	require.NoError(t, err)
+	assert.Equal(t, b64(credentialID), credential.ID)
+	assert.True(t, credential.UserVerified)
+
+	t.Run("registration errors", func(t *testing.T) {
+		_, err := rp.VerifyRegistration("another", registration)
+		assert.Error(t, err)
+
+		wrongOrigin := *registration
+		wrongOrigin.ClientDataJSON = b64(clientData(t, webauthn.TypeCreate, challenge, "https://evil.com"))
+		_, err = rp.VerifyRegistration(challenge, &wrongOrigin)
+		assert.Error(t, err)
+
+		wrongRP := *registration
+		wrongRP.AuthenticatorData = b64(authData("evil.com", webauthn.FlagUserPresent|webauthn.FlagAttestedCredentialData, 0, credentialID))
+		_, err = rp.VerifyRegistration(challenge, &wrongRP)
+		assert.Error(t, err)
+
+		wrongAlg := *registration
+		wrongAlg.PublicKeyAlgorithm = webauthn.AlgRS256
+		_, err = rp.VerifyRegistration(challenge, &wrongAlg)
+		assert.Error(t, err)
+	})
+
+	assertion := func(challenge string, signCount uint32) *webauthn.Assertion {
+		data := clientData(t, webauthn.TypeGet, challenge, "https://example.com")
+		auth := authData("example.com", webauthn.FlagUserPresent, signCount, nil)
+		return &webauthn.Assertion{
+			ID:                b64(credentialID),
+			ClientDataJSON:    b64(data),
+			AuthenticatorData: b64(auth),
+			Signature:         b64(sign(t, key, auth, data)),
+		}
+	}
+
+	t.Run("assertion", func(t *testing.T) {
+		challenge, err := webauthn.NewChallenge()
+		require.NoError(t, err)
+		count, err := rp.VerifyAssertion(challenge, credential, assertion(challenge, 1))
+		require.NoError(t, err)
+		assert.Equal(t, uint32(1), count)
+		credential.SignCount = count
+	})
+
+	t.Run("assertion errors", func(t *testing.T) {
+		challenge, err := webauthn.NewChallenge()
+		require.NoError(t, err)
+
+		_, err = rp.VerifyAssertion("another", credential, assertion(challenge, 2))
+		assert.Error(t, err)
+
+		// Counter going backwards
+		_, err = rp.VerifyAssertion(challenge, credential, assertion(challenge, 1))
+		assert.Error(t, err)
+
+		// Tampered authenticator data
+		a := assertion(challenge, 2)
+		a.AuthenticatorData = b64(authData("example.com", webauthn.FlagUserPresent, 3, nil))
+		_, err = rp.VerifyAssertion(challenge, credential, a)
+		assert.Error(t, err)
+	})
+}
+
+func TestEd25519Assertion(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(public)
+	require.NoError(t, err)
+
+	credential := &webauthn.Credential{ID: b64([]byte("credential-2")), PublicKey: der, Algorithm: webauthn.AlgEdDSA}
+	challenge, err := webauthn.NewChallenge()
+	require.NoError(t, err)
+
+	data := clientData(t, webauthn.TypeGet, challenge, "https://example.com")
+	auth := authData("example.com", webauthn.FlagUserPresent, 0, nil)
+	clientDataHash := sha256.Sum256(data)
+	signature := ed25519.Sign(private, append(append([]byte{}, auth...), clientDataHash[:]...))
+
+	count, err := rp.VerifyAssertion(challenge, credential, &webauthn.Assertion{
+		ID:                credential.ID,
+		ClientDataJSON:    b64(data),
+		AuthenticatorData: b64(auth),
+		Signature:         b64(signature),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), count)
+}
+
+func TestParseAuthenticatorData(t *testing.T) {
+	_, err := webauthn.ParseAuthenticatorData([]byte("short"))
+	assert.Error(t, err)
+
+	data := authData("example.com", webauthn.FlagUserPresent|webauthn.FlagAttestedCredentialData, 7, []byte("id"))
+	parsed, err := webauthn.ParseAuthenticatorData(data)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(7), parsed.SignCount)
+	assert.Equal(t, []byte("id"), parsed.CredentialID)
+
+	_, err = webauthn.ParseAuthenticatorData(data[:len(data)-1])
+	assert.Error(t, err)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/yaoapp/yao/config"
 	"github.com/yaoapp/yao/openapi/agent"
 	"github.com/yaoapp/yao/openapi/app"
+	openapiAudit "github.com/yaoapp/yao/openapi/audit"
 	"github.com/yaoapp/yao/openapi/captcha"
 	"github.com/yaoapp/yao/openapi/chat"
 	openapiComputer "github.com/yaoapp/yao/openapi/computer"
@@ -21,6 +22,7 @@ import (
 	"github.com/yaoapp/yao/openapi/mcp"
 	"github.com/yaoapp/yao/openapi/messenger"
 	"github.com/yaoapp/yao/openapi/nodes"
+	openapiNotification "github.com/yaoapp/yao/openapi/notification"
 	"github.com/yaoapp/yao/openapi/oauth"
 	"github.com/yaoapp/yao/openapi/oauth/acl"
 	"github.com/yaoapp/yao/openapi/oauth/types"
@@ -155,6 +157,12 @@ func (openapi *OpenAPI) Attach(router *gin.Engine) {
 	// Messenger webhook handlers
 	messenger.Attach(group.Group("/messenger"), openapi.OAuth)
 
+	// Notification handlers
+	openapiNotification.Attach(group.Group("/notifications"), openapi.OAuth)
+
+	// Audit log handlers
+	openapiAudit.Attach(group.Group("/audit"), openapi.OAuth)
+
 	// Integrations webhook handlers (public, no OAuth - external platforms push here)
 	openintegrations.Attach(group.Group("/integrations"))
 
@@ -0,0 +1,110 @@
+package user_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaoapp/yao/openapi"
+	"github.com/yaoapp/yao/openapi/oauth"
+	"github.com/yaoapp/yao/openapi/oauth/providers/user"
+	"github.com/yaoapp/yao/openapi/tests/testutils"
+	openapiuser "github.com/yaoapp/yao/openapi/user"
+)
+
+// TestAPIKeys tests the API key management endpoints
+func TestAPIKeys(t *testing.T) {
+	serverURL := testutils.Prepare(t)
+	defer testutils.Clean()
+
+	baseURL := ""
+	if openapi.Server != nil && openapi.Server.Config != nil {
+		baseURL = openapi.Server.Config.BaseURL
+	}
+
+	client := testutils.RegisterTestClient(t, "API Key Test Client", []string{"https://localhost/callback"})
+	defer testutils.CleanupTestClient(t, client.ClientID)
+
+	tokenInfo := testutils.ObtainAccessTokenWithRootPermission(t, serverURL, client.ClientID, client.ClientSecret, "https://localhost/callback", "openid profile email")
+	keysURL := serverURL + baseURL + "/user/api-keys"
+
+	provider, err := oauth.OAuth.GetUserProvider()
+	require.NoError(t, err)
+
+	// Invalid requests
+	status, _ := mfaRequest(t, "POST", keysURL, tokenInfo.AccessToken, map[string]interface{}{"name": "No scopes"})
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = mfaRequest(t, "POST", keysURL, tokenInfo.AccessToken, map[string]interface{}{"name": "Reserved", "scopes": []string{openapiuser.ScopeMFAVerification}})
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = mfaRequest(t, "POST", keysURL, tokenInfo.AccessToken, map[string]interface{}{"name": "Expired", "scopes": []string{"kb:read"}, "expires_at": time.Now().Add(-time.Hour)})
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	// Create, the key is only shown once
+	status, body := mfaRequest(t, "POST", keysURL, tokenInfo.AccessToken, map[string]interface{}{
+		"name":       "CI",
+		"scopes":     []string{"kb:read"},
+		"expires_at": time.Now().Add(24 * time.Hour),
+	})
+	require.Equal(t, http.StatusCreated, status, string(body))
+
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &created))
+	keyID, _ := created["key_id"].(string)
+	key, _ := created["key"].(string)
+	require.NotEmpty(t, keyID)
+	assert.True(t, strings.HasPrefix(key, user.APIKeyPrefix))
+	assert.NotContains(t, created, "key_hash")
+
+	// List and get never return the key
+	status, body = mfaRequest(t, "GET", keysURL, tokenInfo.AccessToken, nil)
+	require.Equal(t, http.StatusOK, status, string(body))
+	assert.Contains(t, string(body), keyID)
+	assert.NotContains(t, string(body), key)
+
+	status, body = mfaRequest(t, "GET", keysURL+"/"+keyID, tokenInfo.AccessToken, nil)
+	require.Equal(t, http.StatusOK, status, string(body))
+	assert.NotContains(t, string(body), key)
+
+	status, _ = mfaRequest(t, "GET", keysURL+"/ak_not_exists", tokenInfo.AccessToken, nil)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	// Update
+	status, body = mfaRequest(t, "PUT", keysURL+"/"+keyID, tokenInfo.AccessToken, map[string]interface{}{"name": "CI renamed"})
+	require.Equal(t, http.StatusOK, status, string(body))
+	assert.Contains(t, string(body), "CI renamed")
+
+	ctx := t.Context()
+	_, err = provider.VerifyAPIKey(ctx, key, "127.0.0.1")
+	require.NoError(t, err)
+
+	// Regenerate, the previous key stops working
+	status, body = mfaRequest(t, "POST", keysURL+"/"+keyID+"/regenerate", tokenInfo.AccessToken, nil)
+	require.Equal(t, http.StatusOK, status, string(body))
+
+	var regenerated map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &regenerated))
+	newKey, _ := regenerated["key"].(string)
+	require.NotEmpty(t, newKey)
+	assert.NotEqual(t, key, newKey)
+
+	_, err = provider.VerifyAPIKey(ctx, key, "127.0.0.1")
+	assert.Error(t, err)
+	_, err = provider.VerifyAPIKey(ctx, newKey, "127.0.0.1")
+	require.NoError(t, err)
+
+	// Revoke, the key is rejected immediately
+	status, body = mfaRequest(t, "DELETE", keysURL+"/"+keyID, tokenInfo.AccessToken, nil)
+	require.Equal(t, http.StatusOK, status, string(body))
+
+	_, err = provider.VerifyAPIKey(ctx, newKey, "127.0.0.1")
+	assert.Error(t, err)
+
+	status, _ = mfaRequest(t, "GET", keysURL+"/"+keyID, tokenInfo.AccessToken, nil)
+	assert.Equal(t, http.StatusNotFound, status)
+}
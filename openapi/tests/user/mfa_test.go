@@ -0,0 +1,148 @@
+package user_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaoapp/yao/openapi"
+	"github.com/yaoapp/yao/openapi/tests/testutils"
+	"github.com/yaoapp/yao/openapi/user"
+)
+
+// TestMFATOTP tests the TOTP setup, the login challenge and disabling TOTP
+func TestMFATOTP(t *testing.T) {
+	serverURL := testutils.Prepare(t)
+	defer testutils.Clean()
+
+	baseURL := ""
+	if openapi.Server != nil && openapi.Server.Config != nil {
+		baseURL = openapi.Server.Config.BaseURL
+	}
+
+	client := testutils.RegisterTestClient(t, "MFA Test Client", []string{"https://localhost/callback"})
+	defer testutils.CleanupTestClient(t, client.ClientID)
+
+	tokenInfo := testutils.ObtainAccessTokenWithRootPermission(t, serverURL, client.ClientID, client.ClientSecret, "https://localhost/callback", "openid profile email")
+	mfaURL := serverURL + baseURL + "/user/mfa"
+
+	// Setup: a new secret is generated while TOTP is disabled
+	status, body := mfaRequest(t, "GET", mfaURL+"/totp", tokenInfo.AccessToken, nil)
+	require.Equal(t, http.StatusOK, status, string(body))
+
+	var setup map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &setup))
+	secret, _ := setup["secret"].(string)
+	require.NotEmpty(t, secret)
+	assert.Contains(t, setup["otpauth_url"], "otpauth://totp/")
+	assert.Contains(t, setup["qr_code"], "data:image/png;base64,")
+
+	// Enable with a wrong code
+	status, _ = mfaRequest(t, "POST", mfaURL+"/totp/enable", tokenInfo.AccessToken, map[string]interface{}{"secret": secret, "code": "000000"})
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	// Enable with a valid code
+	code, err := totp.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+	status, body = mfaRequest(t, "POST", mfaURL+"/totp/enable", tokenInfo.AccessToken, map[string]interface{}{"secret": secret, "code": code})
+	require.Equal(t, http.StatusOK, status, string(body))
+
+	var enabled map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &enabled))
+	assert.Equal(t, true, enabled["mfa_enabled"])
+	assert.NotEmpty(t, enabled["recovery_codes"])
+
+	// Status lists TOTP and recovery codes
+	status, body = mfaRequest(t, "GET", mfaURL, tokenInfo.AccessToken, nil)
+	require.Equal(t, http.StatusOK, status, string(body))
+	assert.Contains(t, string(body), `"totp"`)
+	assert.Contains(t, string(body), `"recovery_code"`)
+
+	// Login now requires the second factor
+	login, err := user.LoginByUserID(tokenInfo.UserID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, user.LoginStatusMFA, login.Status)
+	assert.Equal(t, user.ScopeMFAVerification, login.Scope)
+	assert.NotEmpty(t, login.AccessToken)
+
+	// The MFA token cannot change the MFA settings
+	status, _ = mfaRequest(t, "POST", mfaURL+"/totp/disable", login.AccessToken, map[string]interface{}{"code": code})
+	assert.Equal(t, http.StatusForbidden, status)
+
+	// Wrong code
+	status, _ = mfaRequest(t, "POST", mfaURL+"/totp/verify", login.AccessToken, map[string]interface{}{"code": "000000"})
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	// Valid code completes the login
+	code, err = totp.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+	status, body = mfaRequest(t, "POST", mfaURL+"/totp/verify", login.AccessToken, map[string]interface{}{"code": code})
+	require.Equal(t, http.StatusOK, status, string(body))
+
+	var issued map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &issued))
+	assert.Equal(t, string(user.LoginStatusSuccess), issued["status"])
+	assert.NotEmpty(t, issued["access_token"])
+
+	// Disable TOTP
+	status, body = mfaRequest(t, "POST", mfaURL+"/totp/disable", tokenInfo.AccessToken, map[string]interface{}{"code": code})
+	require.Equal(t, http.StatusOK, status, string(body))
+
+	login, err = user.LoginByUserID(tokenInfo.UserID, nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, user.LoginStatusMFA, login.Status)
+}
+
+// TestMFAWebAuthnRequiresOrigin tests that passkey ceremonies need the browser origin
+func TestMFAWebAuthnRequiresOrigin(t *testing.T) {
+	serverURL := testutils.Prepare(t)
+	defer testutils.Clean()
+
+	baseURL := ""
+	if openapi.Server != nil && openapi.Server.Config != nil {
+		baseURL = openapi.Server.Config.BaseURL
+	}
+
+	client := testutils.RegisterTestClient(t, "MFA WebAuthn Test Client", []string{"https://localhost/callback"})
+	defer testutils.CleanupTestClient(t, client.ClientID)
+
+	tokenInfo := testutils.ObtainAccessTokenWithRootPermission(t, serverURL, client.ClientID, client.ClientSecret, "https://localhost/callback", "openid profile email")
+	mfaURL := serverURL + baseURL + "/user/mfa"
+
+	status, _ := mfaRequest(t, "POST", mfaURL+"/webauthn/register/options", tokenInfo.AccessToken, nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, body := mfaRequest(t, "GET", mfaURL+"/webauthn", tokenInfo.AccessToken, nil)
+	require.Equal(t, http.StatusOK, status, string(body))
+	assert.Contains(t, string(body), `"data":[]`)
+}
+
+func mfaRequest(t *testing.T, method, url, token string, payload map[string]interface{}) (int, []byte) {
+	var reader io.Reader
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		require.NoError(t, err)
+		reader = strings.NewReader(string(raw))
+	}
+
+	req, err := http.NewRequest(method, url, reader)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, body
+}
@@ -122,3 +122,23 @@ func TestMaskEmail(t *testing.T) {
 		})
 	}
 }
+
+// TestMaskPhone tests the MaskPhone utility function
+func TestMaskPhone(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"empty", "", ""},
+		{"international number", "+8613800138000", "+*********8000"},
+		{"local number", "5551234567", "******4567"},
+		{"short number", "1234", "****"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, user.MaskPhone(tc.input))
+		})
+	}
+}
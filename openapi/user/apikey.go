@@ -0,0 +1,299 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/kun/maps"
+	"github.com/yaoapp/yao/audit"
+	openapiAudit "github.com/yaoapp/yao/openapi/audit"
+	"github.com/yaoapp/yao/openapi/oauth"
+	"github.com/yaoapp/yao/openapi/oauth/acl"
+	oauthtypes "github.com/yaoapp/yao/openapi/oauth/types"
+	"github.com/yaoapp/yao/openapi/response"
+	"github.com/yaoapp/yao/openapi/utils"
+)
+
+// API Key Management Handlers
+// Keys belong to the user, and to the team selected when they are created.
+
+// GinAPIKeyList handles GET /api-keys - List the API keys of the user
+func GinAPIKeyList(c *gin.Context) {
+	authInfo, provider, ok := apiKeyContext(c)
+	if !ok {
+		return
+	}
+
+	keys, err := provider.GetUserAPIKeys(c.Request.Context(), authInfo.UserID, authInfo.TeamID)
+	if err != nil {
+		log.Error("Failed to get api keys: %v", err)
+		respondAPIKeyError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to retrieve API keys")
+		return
+	}
+
+	data := make([]maps.MapStrAny, 0, len(keys))
+	for _, key := range keys {
+		data = append(data, apiKeyResponse(key))
+	}
+	response.RespondWithSuccess(c, response.StatusOK, gin.H{"data": data})
+}
+
+// GinAPIKeyCreate handles POST /api-keys - Create an API key
+// The key is only returned in this response.
+func GinAPIKeyCreate(c *gin.Context) {
+	authInfo, provider, ok := apiKeyContext(c)
+	if !ok {
+		return
+	}
+
+	var req APIKeyCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondAPIKeyError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, "Invalid request body: "+err.Error())
+		return
+	}
+
+	if err := validateAPIKeyAccess(req.Scopes, req.ExpiresAt); err != nil {
+		respondAPIKeyError(c, response.StatusBadRequest, response.ErrInvalidScope.Code, err.Error())
+		return
+	}
+
+	data := maps.MapStrAny{
+		"name":   req.Name,
+		"scopes": req.Scopes,
+	}
+	if authInfo.TeamID != "" {
+		data["team_id"] = authInfo.TeamID
+	}
+	if req.ExpiresAt != nil {
+		data["expires_at"] = *req.ExpiresAt
+	}
+
+	keyID, key, err := provider.CreateAPIKey(c.Request.Context(), authInfo.UserID, data)
+	auditAPIKey(c, audit.OpAPIKeyCreate, keyID, nil, maps.MapStrAny{"name": req.Name, "scopes": req.Scopes}, err)
+	if err != nil {
+		log.Error("Failed to create api key: %v", err)
+		respondAPIKeyError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to create API key")
+		return
+	}
+
+	respondWithAPIKey(c, provider, keyID, key, response.StatusCreated)
+}
+
+// GinAPIKeyGet handles GET /api-keys/:key_id - Get an API key
+func GinAPIKeyGet(c *gin.Context) {
+	authInfo, provider, ok := apiKeyContext(c)
+	if !ok {
+		return
+	}
+
+	key, ok := ownedAPIKey(c, provider, authInfo)
+	if !ok {
+		return
+	}
+
+	response.RespondWithSuccess(c, response.StatusOK, apiKeyResponse(key))
+}
+
+// GinAPIKeyUpdate handles PUT /api-keys/:key_id - Update the name, scopes or expiry of an API key
+func GinAPIKeyUpdate(c *gin.Context) {
+	authInfo, provider, ok := apiKeyContext(c)
+	if !ok {
+		return
+	}
+
+	var req APIKeyUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondAPIKeyError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, "Invalid request body: "+err.Error())
+		return
+	}
+
+	if req.Scopes != nil && len(req.Scopes) == 0 {
+		respondAPIKeyError(c, response.StatusBadRequest, response.ErrInvalidScope.Code, "At least one scope is required")
+		return
+	}
+
+	if err := validateAPIKeyAccess(req.Scopes, req.ExpiresAt); err != nil {
+		respondAPIKeyError(c, response.StatusBadRequest, response.ErrInvalidScope.Code, err.Error())
+		return
+	}
+
+	before, ok := ownedAPIKey(c, provider, authInfo)
+	if !ok {
+		return
+	}
+
+	data := maps.MapStrAny{}
+	if req.Name != nil {
+		data["name"] = *req.Name
+	}
+	if req.Scopes != nil {
+		data["scopes"] = req.Scopes
+	}
+	if req.ExpiresAt != nil {
+		data["expires_at"] = *req.ExpiresAt
+	}
+
+	keyID := c.Param("key_id")
+	after := maps.MapStrAny{}
+	for field, value := range data {
+		after[field] = value
+	}
+
+	err := provider.UpdateAPIKey(c.Request.Context(), keyID, data)
+	auditAPIKey(c, audit.OpAPIKeyUpdate, keyID, before, after, err)
+	if err != nil {
+		log.Error("Failed to update api key: %v", err)
+		respondAPIKeyError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to update API key")
+		return
+	}
+
+	respondWithAPIKey(c, provider, keyID, "", response.StatusOK)
+}
+
+// GinAPIKeyDelete handles DELETE /api-keys/:key_id - Revoke an API key
+// The key is rejected from the next request on.
+func GinAPIKeyDelete(c *gin.Context) {
+	authInfo, provider, ok := apiKeyContext(c)
+	if !ok {
+		return
+	}
+
+	before, ok := ownedAPIKey(c, provider, authInfo)
+	if !ok {
+		return
+	}
+
+	keyID := c.Param("key_id")
+	err := provider.DeleteAPIKey(c.Request.Context(), keyID)
+	auditAPIKey(c, audit.OpAPIKeyRevoke, keyID, before, nil, err)
+	if err != nil {
+		log.Error("Failed to revoke api key: %v", err)
+		respondAPIKeyError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to revoke API key")
+		return
+	}
+
+	response.RespondWithSuccess(c, response.StatusOK, gin.H{"message": "API key revoked"})
+}
+
+// GinAPIKeyRegenerate handles POST /api-keys/:key_id/regenerate - Replace the key of an API key
+// The previous key stops working, the new key is only returned in this response.
+func GinAPIKeyRegenerate(c *gin.Context) {
+	authInfo, provider, ok := apiKeyContext(c)
+	if !ok {
+		return
+	}
+
+	if _, ok := ownedAPIKey(c, provider, authInfo); !ok {
+		return
+	}
+
+	keyID := c.Param("key_id")
+	key, err := provider.RegenerateAPIKey(c.Request.Context(), keyID)
+	auditAPIKey(c, audit.OpAPIKeyRegenerate, keyID, nil, nil, err)
+	if err != nil {
+		log.Error("Failed to regenerate api key: %v", err)
+		respondAPIKeyError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to regenerate API key")
+		return
+	}
+
+	respondWithAPIKey(c, provider, keyID, key, response.StatusOK)
+}
+
+// apiKeyContext returns the authorized user and the user provider
+func apiKeyContext(c *gin.Context) (*oauthtypes.AuthorizedInfo, oauthtypes.UserProvider, bool) {
+	authInfo := oauth.GetAuthorizedInfo(c)
+	if authInfo == nil || authInfo.UserID == "" {
+		respondAPIKeyError(c, response.StatusUnauthorized, response.ErrInvalidClient.Code, "User not authenticated")
+		return nil, nil, false
+	}
+
+	provider, err := oauth.OAuth.GetUserProvider()
+	if err != nil {
+		respondAPIKeyError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to get user provider: "+err.Error())
+		return nil, nil, false
+	}
+	return authInfo, provider, true
+}
+
+// ownedAPIKey returns the API key of the request if it belongs to the user and the current team
+func ownedAPIKey(c *gin.Context, provider oauthtypes.UserProvider, authInfo *oauthtypes.AuthorizedInfo) (maps.MapStrAny, bool) {
+	key, err := provider.GetAPIKey(c.Request.Context(), c.Param("key_id"))
+	if err != nil || utils.ToString(key["user_id"]) != authInfo.UserID || utils.ToString(key["team_id"]) != authInfo.TeamID {
+		respondAPIKeyError(c, response.StatusNotFound, response.ErrInvalidRequest.Code, "API key not found")
+		return nil, false
+	}
+	return key, true
+}
+
+// validateAPIKeyAccess checks the scopes and the expiry given to an API key
+func validateAPIKeyAccess(scopes []string, expiresAt *time.Time) error {
+	if err := acl.ValidateScopes(scopes); err != nil {
+		return err
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return fmt.Errorf("expires_at must be in the future")
+	}
+	return nil
+}
+
+// respondWithAPIKey responds with the stored API key, and the key itself when it was just generated
+func respondWithAPIKey(c *gin.Context, provider oauthtypes.UserProvider, keyID string, key string, status int) {
+	result := gin.H{"key_id": keyID}
+	if stored, err := provider.GetAPIKey(c.Request.Context(), keyID); err == nil {
+		result = apiKeyResponse(stored)
+	} else {
+		log.Warn("Failed to get api key %s: %v", keyID, err)
+	}
+
+	if key != "" {
+		result["key"] = key
+	}
+	response.RespondWithSuccess(c, status, result)
+}
+
+// apiKeyResponse returns the public fields of an API key
+func apiKeyResponse(key maps.MapStrAny) gin.H {
+	result := gin.H{}
+	for _, field := range []string{"key_id", "name", "key_prefix", "team_id", "scopes", "expires_at", "last_used_at", "last_used_ip", "created_at", "updated_at"} {
+		if value, ok := key[field]; ok {
+			result[field] = value
+		}
+	}
+	return result
+}
+
+// auditAPIKey records a change of an API key, err is nil on success
+func auditAPIKey(c *gin.Context, operation string, keyID string, before maps.MapStrAny, after maps.MapStrAny, err error) {
+	e := &audit.Entry{
+		Operation:      operation,
+		Category:       audit.CategoryAuthentication,
+		TargetResource: keyID,
+		ResourceType:   "api_key",
+		Success:        err == nil,
+	}
+	if operation == audit.OpAPIKeyRevoke {
+		e.Severity = audit.SeverityHigh
+	}
+	if before != nil {
+		e.Before = map[string]interface{}{}
+		for _, field := range []string{"name", "scopes", "expires_at"} {
+			e.Before[field] = before[field]
+		}
+	}
+	if after != nil {
+		e.After = map[string]interface{}(after)
+	}
+	if err != nil {
+		e.ErrorMessage = err.Error()
+	}
+	openapiAudit.Log(c, e)
+}
+
+func respondAPIKeyError(c *gin.Context, status int, code string, description string) {
+	response.RespondWithError(c, status, &response.ErrorResponse{
+		Code:             code,
+		ErrorDescription: description,
+	})
+}
@@ -0,0 +1,112 @@
+package user
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yaoapp/kun/maps"
+	"github.com/yaoapp/yao/audit"
+	openapiAudit "github.com/yaoapp/yao/openapi/audit"
+)
+
+// auditLogin records a login attempt, err is nil on success
+func auditLogin(ctx context.Context, userID string, username string, loginCtx *LoginContext, authSource string, err error) {
+	e := &audit.Entry{
+		Operation:    audit.OpLogin,
+		Category:     audit.CategoryAuthentication,
+		Severity:     audit.SeverityLow,
+		UserID:       userID,
+		UserName:     username,
+		ResourceType: "user",
+		Source:       "api",
+		Success:      err == nil,
+		Details:      map[string]interface{}{"auth_source": authSource},
+	}
+	if userID != "" {
+		e.TargetResource = userID
+	}
+	if err != nil {
+		e.Severity = audit.SeverityMedium
+		e.ErrorMessage = err.Error()
+	}
+	if loginCtx != nil {
+		e.ClientIP = loginCtx.IP
+		e.UserAgent = loginCtx.UserAgent
+	}
+	audit.Log(ctx, e)
+}
+
+// auditTokenIssue records the tokens issued to a user, tokens themselves are never stored
+func auditTokenIssue(ctx context.Context, params *IssueTokensParams, resp *LoginResponse) {
+	e := &audit.Entry{
+		Operation:      audit.OpTokenIssue,
+		Category:       audit.CategoryAuthentication,
+		Severity:       audit.SeverityLow,
+		UserID:         params.UserID,
+		TeamID:         params.TeamID,
+		TargetResource: params.Subject,
+		ResourceType:   "token",
+		Source:         "api",
+		Success:        true,
+		Details: map[string]interface{}{
+			"scope":                    strings.Join(params.Scopes, " "),
+			"expires_in":               resp.ExpiresIn,
+			"refresh_token_expires_in": resp.RefreshTokenExpiresIn,
+			"auth_source":              params.AuthSource,
+		},
+	}
+	if params.LoginCtx != nil {
+		e.ClientIP = params.LoginCtx.IP
+		e.UserAgent = params.LoginCtx.UserAgent
+		if e.Details["auth_source"] == "" {
+			e.Details["auth_source"] = params.LoginCtx.AuthSource
+		}
+	}
+	audit.Log(ctx, e)
+}
+
+// auditMember records a change of a team member, err is nil on success
+// With both before and after, only the fields of after are kept from before.
+func auditMember(c *gin.Context, operation string, teamID string, memberID string, before maps.MapStrAny, after maps.MapStrAny, err error) {
+	e := &audit.Entry{
+		Operation:      operation,
+		Category:       audit.CategoryAuthorization,
+		TeamID:         teamID,
+		TargetResource: memberID,
+		ResourceType:   "member",
+		Success:        err == nil,
+	}
+	if strings.HasPrefix(operation, "robot.") {
+		e.Category = audit.CategoryConfig
+	}
+	if operation == audit.OpMemberDelete {
+		e.Severity = audit.SeverityHigh
+	}
+	if err != nil {
+		e.ErrorMessage = err.Error()
+	}
+
+	if before != nil {
+		e.Before = map[string]interface{}(before)
+		if after != nil {
+			e.Before = map[string]interface{}{}
+			for key := range after {
+				e.Before[key] = before[key]
+			}
+		}
+	}
+	if after != nil {
+		e.After = map[string]interface{}(after)
+	}
+	openapiAudit.Log(c, e)
+}
+
+// memberSnapshot returns the current state of a member for the audit log, nil if it cannot be read
+func memberSnapshot(ctx context.Context, userID, teamID, memberID string) maps.MapStrAny {
+	member, err := memberGet(ctx, userID, teamID, memberID)
+	if err != nil {
+		return nil
+	}
+	return member
+}
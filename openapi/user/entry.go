@@ -961,6 +961,7 @@ func GinEntryLogin(c *gin.Context) {
 	if userID == "" {
 		_, userID, err = checkUserExists(ctx, usernameTypeStr, usernameStr)
 		if err != nil || userID == "" {
+			auditLogin(ctx, "", usernameStr, makeLoginContext(c), "password", fmt.Errorf("user not found"))
 			errorResp := &response.ErrorResponse{
 				Code:             response.ErrInvalidRequest.Code,
 				ErrorDescription: "Invalid username or password",
@@ -998,6 +999,7 @@ func GinEntryLogin(c *gin.Context) {
 	valid, err := userProvider.VerifyPassword(ctx, req.Password, passwordHash)
 	if err != nil || !valid {
 		log.Warn("Password verification failed for user %s", userID)
+		auditLogin(ctx, userID, usernameStr, makeLoginContext(c), "password", fmt.Errorf("invalid password"))
 		errorResp := &response.ErrorResponse{
 			Code:             response.ErrInvalidRequest.Code,
 			ErrorDescription: "Invalid username or password",
@@ -1011,6 +1013,7 @@ func GinEntryLogin(c *gin.Context) {
 	loginCtx.RememberMe = req.RememberMe // Set Remember Me from request
 	loginCtx.AuthSource = "password"     // Logged in via email+password
 	loginResponse, err := LoginByUserID(userID, loginCtx)
+	auditLogin(ctx, userID, usernameStr, loginCtx, "password", err)
 	if err != nil {
 		log.Error("Failed to login user %s: %v", userID, err)
 		errorResp := &response.ErrorResponse{
@@ -198,11 +198,23 @@ func LoginThirdParty(providerID string, userinfo *oauthtypes.OIDCUserInfo, login
 		loginCtx.OAuthEmail = userinfo.Email
 	}
 
-	return LoginByUserID(userID, loginCtx)
+	resp, err := LoginByUserID(userID, loginCtx)
+	auditLogin(ctx, userID, userinfo.Email, loginCtx, providerID, err)
+	return resp, err
 }
 
 // LoginByUserID is the handler for login by user ID
 func LoginByUserID(userid string, loginCtx *LoginContext) (*LoginResponse, error) {
+	return loginByUserID(userid, loginCtx, false)
+}
+
+// LoginAfterMFA continues the login of a user who has passed the second factor
+func LoginAfterMFA(userid string, loginCtx *LoginContext) (*LoginResponse, error) {
+	return loginByUserID(userid, loginCtx, true)
+}
+
+// loginByUserID logs in a user, the MFA step is skipped when mfaVerified
+func loginByUserID(userid string, loginCtx *LoginContext, mfaVerified bool) (*LoginResponse, error) {
 	// Get User
 	userProvider, err := oauth.OAuth.GetUserProvider()
 	if err != nil {
@@ -275,19 +287,25 @@ func LoginByUserID(userid string, loginCtx *LoginContext) (*LoginResponse, error
 		return nil, fmt.Errorf("account status is invalid: %s", status)
 	}
 
-	// Get MFA enabled status from user data
-	mfaEnabled := utils.ToBool(user["mfa_enabled"])
+	// Get MFA enabled status from user data (any second factor configured)
+	mfaEnabled := len(userMFAMethods(ctx, userProvider, userid, user)) > 0
 
-	// If MFA enabled, generate MFA token
-	if mfaEnabled {
+	// If MFA enabled, generate MFA token, tokens are issued after the second factor
+	if mfaEnabled && !mfaVerified {
 		// Sign temporary access token for MFA
 		var mfaExpire int = 10 * 60 // 10 minutes
 
-		// Prepare extra claims to preserve Remember Me state
+		// Prepare extra claims to preserve Remember Me and AuthSource state
 		extraClaims := make(map[string]interface{})
 		if loginCtx != nil && loginCtx.RememberMe {
 			extraClaims["remember_me"] = true
 		}
+		if loginCtx != nil && loginCtx.AuthSource != "" {
+			extraClaims["auth_source"] = loginCtx.AuthSource
+		}
+		if loginCtx != nil && loginCtx.OAuthEmail != "" {
+			extraClaims["oauth_email"] = loginCtx.OAuthEmail
+		}
 
 		accessToken, err := oauth.OAuth.MakeAccessToken(yaoClientConfig.ClientID, ScopeMFAVerification, subject, mfaExpire, extraClaims)
 		if err != nil {
@@ -846,7 +864,7 @@ func issueTokens(ctx context.Context, params *IssueTokensParams) (*LoginResponse
 		}
 	}
 
-	return &LoginResponse{
+	resp := &LoginResponse{
 		UserID:                params.UserID,
 		Subject:               params.Subject,
 		AccessToken:           accessToken,
@@ -858,7 +876,9 @@ func issueTokens(ctx context.Context, params *IssueTokensParams) (*LoginResponse
 		MFAEnabled:            utils.ToBool(params.User["mfa_enabled"]),
 		Scope:                 strings.Join(params.Scopes, " "),
 		Status:                LoginStatusSuccess,
-	}, nil
+	}
+	auditTokenIssue(ctx, params, resp)
+	return resp, nil
 }
 
 // prepareUserKBCollection prepares KB collection for user (called asynchronously after login)
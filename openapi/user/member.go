@@ -13,6 +13,7 @@ import (
 	"github.com/yaoapp/kun/exception"
 	"github.com/yaoapp/kun/log"
 	"github.com/yaoapp/kun/maps"
+	"github.com/yaoapp/yao/audit"
 	"github.com/yaoapp/yao/openapi/oauth"
 	"github.com/yaoapp/yao/openapi/oauth/authorized"
 	"github.com/yaoapp/yao/openapi/response"
@@ -333,6 +334,7 @@ func GinMemberCreateRobot(c *gin.Context) {
 
 	// Call business logic
 	memberID, err := memberCreateRobot(c.Request.Context(), authInfo.UserID, teamID, robotData)
+	auditMember(c, audit.OpRobotCreate, teamID, memberID, nil, baseData, err)
 	if err != nil {
 		log.Error("Failed to create robot member: %v", err)
 		// Check error type for appropriate response
@@ -468,7 +470,9 @@ func GinMemberUpdateRobot(c *gin.Context) {
 	robotData := authInfo.WithUpdateScope(updateData)
 
 	// Call business logic
+	before := memberSnapshot(c.Request.Context(), authInfo.UserID, teamID, memberID)
 	err := memberUpdateRobot(c.Request.Context(), authInfo.UserID, teamID, memberID, robotData)
+	auditMember(c, audit.OpRobotUpdate, teamID, memberID, before, updateData, err)
 	if err != nil {
 		log.Error("Failed to update robot member: %v", err)
 		// Check error type for appropriate response
@@ -562,7 +566,9 @@ func GinMemberUpdate(c *gin.Context) {
 	}
 
 	// Call business logic
+	before := memberSnapshot(c.Request.Context(), authInfo.UserID, teamID, memberID)
 	err := memberUpdate(c.Request.Context(), authInfo.UserID, teamID, memberID, updateData)
+	auditMember(c, audit.OpMemberUpdate, teamID, memberID, before, updateData, err)
 	if err != nil {
 		log.Error("Failed to update member: %v", err)
 		// Check error type for appropriate response
@@ -748,7 +754,9 @@ func GinMemberDelete(c *gin.Context) {
 	}
 
 	// Call business logic
+	before := memberSnapshot(c.Request.Context(), authInfo.UserID, teamID, memberID)
 	err := memberDelete(c.Request.Context(), authInfo.UserID, teamID, memberID)
+	auditMember(c, audit.OpMemberDelete, teamID, memberID, before, nil, err)
 	if err != nil {
 		log.Error("Failed to delete member: %v", err)
 		// Check error type for appropriate response
@@ -0,0 +1,780 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"image/png"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pquerna/otp"
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/kun/maps"
+	"github.com/yaoapp/yao/audit"
+	openapiAudit "github.com/yaoapp/yao/openapi/audit"
+	"github.com/yaoapp/yao/openapi/oauth"
+	oauthtypes "github.com/yaoapp/yao/openapi/oauth/types"
+	"github.com/yaoapp/yao/openapi/response"
+	"github.com/yaoapp/yao/openapi/utils"
+	"github.com/yaoapp/yao/share"
+	utilsotp "github.com/yaoapp/yao/utils/otp"
+)
+
+const (
+	mfaMaxAttempts    = 5                // Failed second factor attempts allowed per window
+	mfaAttemptsWindow = 10 * time.Minute // Same as the lifetime of the MFA token
+	mfaOTPExpire      = 10 * time.Minute // One-time codes sent by SMS or email
+	mfaOTPCooldown    = time.Minute      // Minimum interval between two codes
+)
+
+// ==== MFA Status ====
+
+// GinMFAStatus handles GET /mfa - Get the second factors configured for the user
+// Also available to the MFA verification token, so the login page knows which factors to offer.
+func GinMFAStatus(c *gin.Context) {
+	authInfo, ok := mfaAuthInfo(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	provider, err := oauth.OAuth.GetUserProvider()
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to get user provider: "+err.Error())
+		return
+	}
+
+	user, err := provider.GetUser(ctx, authInfo.UserID)
+	if err != nil {
+		respondMFAError(c, response.StatusNotFound, response.ErrInvalidRequest.Code, "User not found")
+		return
+	}
+
+	credentials, err := provider.GetUserCredentials(ctx, authInfo.UserID)
+	if err != nil {
+		log.Warn("Failed to get webauthn credentials: %v", err)
+	}
+
+	result := gin.H{
+		"methods":  userMFAMethods(ctx, provider, authInfo.UserID, user),
+		"totp":     utils.ToBool(user["mfa_enabled"]),
+		"sms":      utils.ToBool(user["mfa_sms_enabled"]),
+		"email":    utils.ToBool(user["mfa_email_enabled"]),
+		"webauthn": len(credentials),
+	}
+	if phone := utils.ToString(user["phone_number"]); phone != "" {
+		result["phone_number"] = MaskPhone(phone)
+	}
+	if email := utils.ToString(user["email"]); email != "" {
+		result["email_address"] = MaskEmail(email)
+	}
+
+	response.RespondWithSuccess(c, response.StatusOK, result)
+}
+
+// ==== TOTP ====
+
+// GinTOTPGet handles GET /mfa/totp - Get TOTP status, or a new secret with its provisioning URI and QR code
+// A new secret replaces the previous pending one and is only active after POST /mfa/totp/enable.
+func GinTOTPGet(c *gin.Context) {
+	authInfo, ok := mfaAuthInfo(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	provider, err := oauth.OAuth.GetUserProvider()
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to get user provider: "+err.Error())
+		return
+	}
+
+	config, err := provider.GetMFAConfig(ctx, authInfo.UserID)
+	if err != nil {
+		respondMFAError(c, response.StatusNotFound, response.ErrInvalidRequest.Code, "User not found")
+		return
+	}
+
+	// Enabled, or during login: status only
+	if utils.ToBool(config["mfa_enabled"]) || authInfo.Scope == ScopeMFAVerification {
+		response.RespondWithSuccess(c, response.StatusOK, config)
+		return
+	}
+
+	user, err := provider.GetUser(ctx, authInfo.UserID)
+	if err != nil {
+		respondMFAError(c, response.StatusNotFound, response.ErrInvalidRequest.Code, "User not found")
+		return
+	}
+
+	options := &oauthtypes.MFAOptions{AccountName: totpAccountName(authInfo.UserID, user)}
+	if share.App.Name != "" {
+		options.Issuer = share.App.Name
+	}
+
+	secret, uri, err := provider.GenerateMFASecret(ctx, authInfo.UserID, options)
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to generate TOTP secret: "+err.Error())
+		return
+	}
+
+	result := gin.H{
+		"mfa_enabled": false,
+		"secret":      secret,
+		"otpauth_url": uri,
+	}
+	if qrCode, err := totpQRCode(uri); err == nil {
+		result["qr_code"] = qrCode
+	} else {
+		log.Warn("Failed to render TOTP QR code: %v", err)
+	}
+
+	response.RespondWithSuccess(c, response.StatusOK, result)
+}
+
+// GinTOTPEnable handles POST /mfa/totp/enable - Enable TOTP with a code from the authenticator app
+// Returns the recovery codes, they are only shown once.
+func GinTOTPEnable(c *gin.Context) {
+	authInfo, ok := mfaAuthInfo(c)
+	if !ok || !mfaNotVerifying(c, authInfo) {
+		return
+	}
+
+	var req TOTPEnableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondMFAError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, "Invalid request body: "+err.Error())
+		return
+	}
+
+	ctx := c.Request.Context()
+	provider, err := oauth.OAuth.GetUserProvider()
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to get user provider: "+err.Error())
+		return
+	}
+
+	err = provider.EnableMFA(ctx, authInfo.UserID, req.Secret, req.Code)
+	auditMFA(c, audit.OpMFAEnable, MFAMethodTOTP, err)
+	if err != nil {
+		respondMFAError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, "Failed to enable TOTP: "+err.Error())
+		return
+	}
+
+	codes, err := provider.GenerateRecoveryCodes(ctx, authInfo.UserID)
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to generate recovery codes: "+err.Error())
+		return
+	}
+
+	response.RespondWithSuccess(c, response.StatusOK, gin.H{
+		"mfa_enabled":    true,
+		"recovery_codes": codes,
+	})
+}
+
+// GinTOTPDisable handles POST /mfa/totp/disable - Disable TOTP with a code from the authenticator app
+func GinTOTPDisable(c *gin.Context) {
+	authInfo, ok := mfaAuthInfo(c)
+	if !ok || !mfaNotVerifying(c, authInfo) {
+		return
+	}
+
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondMFAError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, "Invalid request body: "+err.Error())
+		return
+	}
+
+	if !mfaAttemptsAllowed(c, authInfo.UserID) {
+		return
+	}
+
+	provider, err := oauth.OAuth.GetUserProvider()
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to get user provider: "+err.Error())
+		return
+	}
+
+	err = provider.DisableMFA(c.Request.Context(), authInfo.UserID, strings.TrimSpace(req.Code))
+	auditMFA(c, audit.OpMFADisable, MFAMethodTOTP, err)
+	if err != nil {
+		mfaAttemptFailed(authInfo.UserID)
+		respondMFAError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, "Failed to disable TOTP: "+err.Error())
+		return
+	}
+	mfaAttemptsReset(authInfo.UserID)
+
+	response.RespondWithSuccess(c, response.StatusOK, gin.H{"mfa_enabled": false})
+}
+
+// GinTOTPVerify handles POST /mfa/totp/verify - Verify a TOTP code or a recovery code
+// With the MFA verification token it completes the login and issues the tokens.
+func GinTOTPVerify(c *gin.Context) {
+	authInfo, ok := mfaAuthInfo(c)
+	if !ok {
+		return
+	}
+
+	var req TOTPVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil || (req.Code == "" && req.RecoveryCode == "") {
+		respondMFAError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, "Code or recovery code is required")
+		return
+	}
+
+	if !mfaAttemptsAllowed(c, authInfo.UserID) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	provider, err := oauth.OAuth.GetUserProvider()
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to get user provider: "+err.Error())
+		return
+	}
+
+	method := MFAMethodTOTP
+	var valid bool
+	if req.RecoveryCode != "" {
+		method = MFAMethodRecovery
+		valid, err = provider.VerifyRecoveryCode(ctx, authInfo.UserID, strings.TrimSpace(req.RecoveryCode))
+	} else {
+		valid, err = provider.VerifyMFACode(ctx, authInfo.UserID, strings.TrimSpace(req.Code))
+	}
+
+	if err == nil && !valid {
+		err = fmt.Errorf("invalid code")
+	}
+	mfaVerified(c, authInfo, method, err)
+}
+
+// GinTOTPRecoveryCodes handles GET /mfa/totp/recovery-codes - Get the number of unused recovery codes
+// Recovery codes are stored hashed, they can only be read when generated.
+func GinTOTPRecoveryCodes(c *gin.Context) {
+	authInfo, ok := mfaAuthInfo(c)
+	if !ok || !mfaNotVerifying(c, authInfo) {
+		return
+	}
+
+	provider, err := oauth.OAuth.GetUserProvider()
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to get user provider: "+err.Error())
+		return
+	}
+
+	config, err := provider.GetMFAConfig(c.Request.Context(), authInfo.UserID)
+	if err != nil {
+		respondMFAError(c, response.StatusNotFound, response.ErrInvalidRequest.Code, "User not found")
+		return
+	}
+
+	if !utils.ToBool(config["mfa_enabled"]) {
+		respondMFAError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, "TOTP is not enabled")
+		return
+	}
+
+	response.RespondWithSuccess(c, response.StatusOK, gin.H{
+		"available": utils.ToInt(config["recovery_codes_available"]),
+	})
+}
+
+// GinTOTPRecoveryCodesRegenerate handles POST /mfa/totp/recovery-codes/regenerate - Replace all recovery codes
+// Requires a TOTP code, the previous codes stop working.
+func GinTOTPRecoveryCodesRegenerate(c *gin.Context) {
+	authInfo, ok := mfaAuthInfo(c)
+	if !ok || !mfaNotVerifying(c, authInfo) {
+		return
+	}
+
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondMFAError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, "Invalid request body: "+err.Error())
+		return
+	}
+
+	if !mfaAttemptsAllowed(c, authInfo.UserID) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	provider, err := oauth.OAuth.GetUserProvider()
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to get user provider: "+err.Error())
+		return
+	}
+
+	valid, err := provider.VerifyMFACode(ctx, authInfo.UserID, strings.TrimSpace(req.Code))
+	if err != nil || !valid {
+		mfaAttemptFailed(authInfo.UserID)
+		respondMFAError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, "Invalid TOTP code")
+		return
+	}
+	mfaAttemptsReset(authInfo.UserID)
+
+	codes, err := provider.GenerateRecoveryCodes(ctx, authInfo.UserID)
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to generate recovery codes: "+err.Error())
+		return
+	}
+
+	response.RespondWithSuccess(c, response.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// GinTOTPReset handles POST /mfa/totp/reset - Reset TOTP when the authenticator is lost
+// Requires a code sent to the email address (POST /mfa/email/verification-code),
+// returns a new secret to enroll again.
+func GinTOTPReset(c *gin.Context) {
+	authInfo, ok := mfaAuthInfo(c)
+	if !ok || !mfaNotVerifying(c, authInfo) {
+		return
+	}
+
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondMFAError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, "Invalid request body: "+err.Error())
+		return
+	}
+
+	if !mfaAttemptsAllowed(c, authInfo.UserID) {
+		return
+	}
+
+	if !validateMFAOTP(MFAMethodEmail, authInfo.UserID, req.Code) {
+		mfaAttemptFailed(authInfo.UserID)
+		respondMFAError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, "Invalid or expired verification code")
+		return
+	}
+	mfaAttemptsReset(authInfo.UserID)
+
+	provider, err := oauth.OAuth.GetUserProvider()
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to get user provider: "+err.Error())
+		return
+	}
+
+	err = provider.ResetMFA(c.Request.Context(), authInfo.UserID)
+	auditMFA(c, audit.OpMFADisable, MFAMethodTOTP, err)
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to reset TOTP: "+err.Error())
+		return
+	}
+
+	// Continue with a new enrollment
+	GinTOTPGet(c)
+}
+
+// ==== SMS and Email one-time codes ====
+
+// GinSMSStatus handles GET /mfa/sms - Get SMS MFA status
+func GinSMSStatus(c *gin.Context) { otpMFAStatus(c, MFAMethodSMS) }
+
+// GinSMSEnable handles POST /mfa/sms/enable - Enable SMS MFA with a code sent to the phone number
+func GinSMSEnable(c *gin.Context) { otpMFASet(c, MFAMethodSMS, true) }
+
+// GinSMSDisable handles POST /mfa/sms/disable - Disable SMS MFA with a code sent to the phone number
+func GinSMSDisable(c *gin.Context) { otpMFASet(c, MFAMethodSMS, false) }
+
+// GinSMSSendCode handles POST /mfa/sms/verification-code - Send a code to the phone number
+func GinSMSSendCode(c *gin.Context) { otpMFASend(c, MFAMethodSMS) }
+
+// GinSMSVerify handles POST /mfa/sms/verify - Verify a code sent to the phone number
+func GinSMSVerify(c *gin.Context) { otpMFAVerify(c, MFAMethodSMS) }
+
+// GinEmailMFAStatus handles GET /mfa/email - Get email MFA status
+func GinEmailMFAStatus(c *gin.Context) { otpMFAStatus(c, MFAMethodEmail) }
+
+// GinEmailMFAEnable handles POST /mfa/email/enable - Enable email MFA with a code sent to the email address
+func GinEmailMFAEnable(c *gin.Context) { otpMFASet(c, MFAMethodEmail, true) }
+
+// GinEmailMFADisable handles POST /mfa/email/disable - Disable email MFA with a code sent to the email address
+func GinEmailMFADisable(c *gin.Context) { otpMFASet(c, MFAMethodEmail, false) }
+
+// GinEmailMFASendCode handles POST /mfa/email/verification-code - Send a code to the email address
+func GinEmailMFASendCode(c *gin.Context) { otpMFASend(c, MFAMethodEmail) }
+
+// GinEmailMFAVerify handles POST /mfa/email/verify - Verify a code sent to the email address
+func GinEmailMFAVerify(c *gin.Context) { otpMFAVerify(c, MFAMethodEmail) }
+
+// otpMFAStatus responds whether the channel is enabled and where codes are sent to
+func otpMFAStatus(c *gin.Context, channel string) {
+	authInfo, ok := mfaAuthInfo(c)
+	if !ok {
+		return
+	}
+
+	user, ok := mfaUser(c, authInfo.UserID)
+	if !ok {
+		return
+	}
+
+	to, _ := otpDestination(user, channel)
+	if channel == MFAMethodSMS {
+		to = MaskPhone(to)
+	} else {
+		to = MaskEmail(to)
+	}
+
+	response.RespondWithSuccess(c, response.StatusOK, gin.H{
+		"enabled": utils.ToBool(user[otpEnabledColumn(channel)]),
+		"to":      to,
+	})
+}
+
+// otpMFASend sends a one-time code through the channel
+// The code is bound to the user, the client never gets an ID to replay.
+func otpMFASend(c *gin.Context, channel string) {
+	authInfo, ok := mfaAuthInfo(c)
+	if !ok {
+		return
+	}
+
+	user, ok := mfaUser(c, authInfo.UserID)
+	if !ok {
+		return
+	}
+
+	to, usernameType := otpDestination(user, channel)
+	if to == "" {
+		respondMFAError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, fmt.Sprintf("No %s configured for this account", usernameType))
+		return
+	}
+
+	// During login only enabled channels can be used
+	if authInfo.Scope == ScopeMFAVerification && !utils.ToBool(user[otpEnabledColumn(channel)]) {
+		respondMFAError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, channel+" MFA is not enabled")
+		return
+	}
+
+	cache := oauth.OAuth.GetCache()
+	cooldownKey := mfaOTPCooldownKey(channel, authInfo.UserID)
+	if cache.Has(cooldownKey) {
+		c.Header("Retry-After", fmt.Sprintf("%d", int(mfaOTPCooldown.Seconds())))
+		respondMFAError(c, http.StatusTooManyRequests, response.ErrInvalidRequest.Code, "A code was sent recently, please wait before requesting another one")
+		return
+	}
+
+	locale := c.Query("locale")
+	config := GetEntryConfig(locale)
+	if config == nil {
+		respondMFAError(c, response.StatusNotFound, response.ErrInvalidRequest.Code, "Entry configuration not found")
+		return
+	}
+
+	otpID, code := generateEntryOTP()
+	if err := sendVerificationMessage(c.Request.Context(), config, usernameType, to, code, locale); err != nil {
+		log.Error("Failed to send MFA code: %v", err)
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to send verification code")
+		return
+	}
+
+	if err := cache.Set(mfaOTPKey(channel, authInfo.UserID), otpID, mfaOTPExpire); err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to store verification code: "+err.Error())
+		return
+	}
+	cache.Set(cooldownKey, true, mfaOTPCooldown)
+
+	response.RespondWithSuccess(c, response.StatusOK, gin.H{
+		"expires_in": int(mfaOTPExpire.Seconds()),
+	})
+}
+
+// otpMFASet enables or disables the channel after checking a code sent through it
+func otpMFASet(c *gin.Context, channel string, enabled bool) {
+	authInfo, ok := mfaAuthInfo(c)
+	if !ok || !mfaNotVerifying(c, authInfo) {
+		return
+	}
+
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondMFAError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, "Invalid request body: "+err.Error())
+		return
+	}
+
+	if !mfaAttemptsAllowed(c, authInfo.UserID) {
+		return
+	}
+
+	if !validateMFAOTP(channel, authInfo.UserID, req.Code) {
+		mfaAttemptFailed(authInfo.UserID)
+		respondMFAError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, "Invalid or expired verification code")
+		return
+	}
+	mfaAttemptsReset(authInfo.UserID)
+
+	provider, err := oauth.OAuth.GetUserProvider()
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to get user provider: "+err.Error())
+		return
+	}
+
+	err = provider.SetOTPMFAEnabled(c.Request.Context(), authInfo.UserID, channel, enabled)
+	operation := audit.OpMFAEnable
+	if !enabled {
+		operation = audit.OpMFADisable
+	}
+	auditMFA(c, operation, channel, err)
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to update MFA: "+err.Error())
+		return
+	}
+
+	response.RespondWithSuccess(c, response.StatusOK, gin.H{"enabled": enabled})
+}
+
+// otpMFAVerify verifies a code sent through the channel
+// With the MFA verification token it completes the login and issues the tokens.
+func otpMFAVerify(c *gin.Context, channel string) {
+	authInfo, ok := mfaAuthInfo(c)
+	if !ok {
+		return
+	}
+
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondMFAError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, "Invalid request body: "+err.Error())
+		return
+	}
+
+	if !mfaAttemptsAllowed(c, authInfo.UserID) {
+		return
+	}
+
+	var err error
+	if !validateMFAOTP(channel, authInfo.UserID, req.Code) {
+		err = fmt.Errorf("invalid or expired verification code")
+	}
+	mfaVerified(c, authInfo, channel, err)
+}
+
+// validateMFAOTP checks and consumes the code sent to the user through the channel
+func validateMFAOTP(channel, userID, code string) bool {
+	cache := oauth.OAuth.GetCache()
+	key := mfaOTPKey(channel, userID)
+	value, ok := cache.Get(key)
+	if !ok {
+		return false
+	}
+
+	otpID, _ := value.(string)
+	if otpID == "" || !utilsotp.Validate(otpID, strings.TrimSpace(code), true) {
+		return false
+	}
+
+	cache.Del(key)
+	return true
+}
+
+// otpDestination returns where codes of the channel are sent to and the username type used by the messenger
+func otpDestination(user maps.MapStrAny, channel string) (string, string) {
+	if channel == MFAMethodSMS {
+		return utils.ToString(user["phone_number"]), "mobile"
+	}
+	return utils.ToString(user["email"]), "email"
+}
+
+// otpEnabledColumn returns the user column flagging the channel as a second factor
+func otpEnabledColumn(channel string) string {
+	return fmt.Sprintf("mfa_%s_enabled", channel)
+}
+
+// ==== Login ====
+
+// mfaVerified completes a second factor check, err is nil when the factor is valid
+// With the MFA verification token the login continues and the final tokens are issued,
+// otherwise it only confirms the factor (step-up verification).
+func mfaVerified(c *gin.Context, authInfo *oauthtypes.AuthorizedInfo, method string, err error) {
+	auditMFA(c, audit.OpMFAVerify, method, err)
+	if err != nil {
+		mfaAttemptFailed(authInfo.UserID)
+		respondMFAError(c, response.StatusUnauthorized, response.ErrAccessDenied.Code, "Verification failed: "+err.Error())
+		return
+	}
+	mfaAttemptsReset(authInfo.UserID)
+
+	if authInfo.Scope != ScopeMFAVerification {
+		response.RespondWithSuccess(c, response.StatusOK, gin.H{"verified": true, "method": method})
+		return
+	}
+
+	loginCtx := makeLoginContext(c)
+	loginCtx.RememberMe = authInfo.RememberMe
+	loginCtx.AuthSource = authInfo.AuthSource
+	loginCtx.OAuthEmail = authInfo.OAuthEmail
+
+	loginResponse, err := LoginAfterMFA(authInfo.UserID, loginCtx)
+	if err != nil {
+		log.Error("Failed to login after MFA: %v", err)
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to login: "+err.Error())
+		return
+	}
+
+	// Revoke the MFA verification token, it must not be replayed
+	if currentToken := oauth.OAuth.GetAccessToken(c); currentToken != "" {
+		if err := oauth.OAuth.Revoke(c.Request.Context(), currentToken, "access_token"); err != nil {
+			log.Warn("Failed to revoke MFA token: %v", err)
+		}
+	}
+
+	SendLoginCookies(c, loginResponse, "")
+	response.RespondWithSuccess(c, response.StatusOK, loginResponse)
+}
+
+// userMFAMethods returns the second factors configured for a user, empty when MFA is off
+func userMFAMethods(ctx context.Context, provider oauthtypes.UserProvider, userID string, user maps.MapStrAny) []string {
+	methods := []string{}
+	if utils.ToBool(user["mfa_enabled"]) {
+		methods = append(methods, MFAMethodTOTP, MFAMethodRecovery)
+	}
+	if credentials, err := provider.GetUserCredentials(ctx, userID); err == nil && len(credentials) > 0 {
+		methods = append(methods, MFAMethodWebAuthn)
+	}
+	if utils.ToBool(user["mfa_sms_enabled"]) {
+		methods = append(methods, MFAMethodSMS)
+	}
+	if utils.ToBool(user["mfa_email_enabled"]) {
+		methods = append(methods, MFAMethodEmail)
+	}
+	return methods
+}
+
+// ==== Helpers ====
+
+// mfaAuthInfo returns the authorized user, or responds 401
+func mfaAuthInfo(c *gin.Context) (*oauthtypes.AuthorizedInfo, bool) {
+	authInfo := oauth.GetAuthorizedInfo(c)
+	if authInfo == nil || authInfo.UserID == "" {
+		respondMFAError(c, response.StatusUnauthorized, response.ErrInvalidClient.Code, "User not authenticated")
+		return nil, false
+	}
+	return authInfo, true
+}
+
+// mfaNotVerifying responds 403 when called with the MFA verification token
+// Changing second factors requires a fully logged-in session.
+func mfaNotVerifying(c *gin.Context, authInfo *oauthtypes.AuthorizedInfo) bool {
+	if authInfo.Scope == ScopeMFAVerification {
+		respondMFAError(c, response.StatusForbidden, response.ErrAccessDenied.Code, "Complete the login before changing MFA settings")
+		return false
+	}
+	return true
+}
+
+// mfaUser returns the user data, or responds with an error
+func mfaUser(c *gin.Context, userID string) (maps.MapStrAny, bool) {
+	provider, err := oauth.OAuth.GetUserProvider()
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to get user provider: "+err.Error())
+		return nil, false
+	}
+
+	user, err := provider.GetUser(c.Request.Context(), userID)
+	if err != nil {
+		respondMFAError(c, response.StatusNotFound, response.ErrInvalidRequest.Code, "User not found")
+		return nil, false
+	}
+	return user, true
+}
+
+// mfaAttemptsAllowed responds 429 when the user has used up the failed attempts of the window
+func mfaAttemptsAllowed(c *gin.Context, userID string) bool {
+	value, ok := oauth.OAuth.GetCache().Get(mfaAttemptsKey(userID))
+	if ok && utils.ToInt(value) >= mfaMaxAttempts {
+		c.Header("Retry-After", fmt.Sprintf("%d", int(mfaAttemptsWindow.Seconds())))
+		respondMFAError(c, http.StatusTooManyRequests, response.ErrAccessDenied.Code, "Too many failed attempts, please try again later")
+		return false
+	}
+	return true
+}
+
+// mfaAttemptFailed counts a failed second factor attempt
+func mfaAttemptFailed(userID string) {
+	cache := oauth.OAuth.GetCache()
+	key := mfaAttemptsKey(userID)
+
+	// Create the counter with an expiry first, Incr keeps it
+	_, err := cache.GetSet(key, mfaAttemptsWindow, func(string) (interface{}, error) { return int64(0), nil })
+	if err == nil {
+		_, err = cache.Incr(key, 1)
+	}
+	if err != nil {
+		log.Warn("Failed to count MFA attempt: %v", err)
+	}
+}
+
+// mfaAttemptsReset clears the failed attempts after a successful verification
+func mfaAttemptsReset(userID string) {
+	oauth.OAuth.GetCache().Del(mfaAttemptsKey(userID))
+}
+
+// auditMFA records a change or a check of a second factor, err is nil on success
+func auditMFA(c *gin.Context, operation string, method string, err error) {
+	e := &audit.Entry{
+		Operation:    operation,
+		Category:     audit.CategoryAuthentication,
+		ResourceType: "user",
+		Success:      err == nil,
+		Details:      map[string]interface{}{"method": method},
+	}
+	if operation == audit.OpMFADisable {
+		e.Severity = audit.SeverityHigh
+	}
+	if authInfo := oauth.GetAuthorizedInfo(c); authInfo != nil {
+		e.TargetResource = authInfo.UserID
+	}
+	if err != nil {
+		e.ErrorMessage = err.Error()
+	}
+	openapiAudit.Log(c, e)
+}
+
+// totpAccountName returns the account name shown in authenticator apps
+func totpAccountName(userID string, user maps.MapStrAny) string {
+	for _, field := range []string{"email", "preferred_username", "phone_number"} {
+		if value := utils.ToString(user[field]); value != "" {
+			return value
+		}
+	}
+	return userID
+}
+
+// totpQRCode renders a provisioning URI as a PNG data URL
+func totpQRCode(uri string) (string, error) {
+	key, err := otp.NewKeyFromURL(uri)
+	if err != nil {
+		return "", err
+	}
+
+	img, err := key.Image(256, 256)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+func respondMFAError(c *gin.Context, status int, code string, description string) {
+	response.RespondWithError(c, status, &response.ErrorResponse{
+		Code:             code,
+		ErrorDescription: description,
+	})
+}
+
+func mfaAttemptsKey(userID string) string {
+	return fmt.Sprintf("mfa:attempts:%s", userID)
+}
+
+func mfaOTPKey(channel, userID string) string {
+	return fmt.Sprintf("mfa:otp:%s:%s", channel, userID)
+}
+
+func mfaOTPCooldownKey(channel, userID string) string {
+	return fmt.Sprintf("mfa:otp:cooldown:%s:%s", channel, userID)
+}
@@ -0,0 +1,382 @@
+package user
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/kun/maps"
+	"github.com/yaoapp/yao/audit"
+	"github.com/yaoapp/yao/openapi/oauth"
+	"github.com/yaoapp/yao/openapi/oauth/webauthn"
+	"github.com/yaoapp/yao/openapi/response"
+	"github.com/yaoapp/yao/openapi/utils"
+	"github.com/yaoapp/yao/share"
+)
+
+// webauthnChallengeExpire is how long a registration or login ceremony can take
+const webauthnChallengeExpire = 5 * time.Minute
+
+// webauthnChallenge is the state of a pending ceremony
+type webauthnChallenge struct {
+	Challenge string `json:"challenge"`
+	RPID      string `json:"rp_id"`
+	Origin    string `json:"origin"`
+}
+
+// GinWebAuthnList handles GET /mfa/webauthn - List the passkeys of the user
+func GinWebAuthnList(c *gin.Context) {
+	authInfo, ok := mfaAuthInfo(c)
+	if !ok || !mfaNotVerifying(c, authInfo) {
+		return
+	}
+
+	provider, err := oauth.OAuth.GetUserProvider()
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to get user provider: "+err.Error())
+		return
+	}
+
+	credentials, err := provider.GetUserCredentials(c.Request.Context(), authInfo.UserID)
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to get passkeys: "+err.Error())
+		return
+	}
+
+	data := make([]gin.H, 0, len(credentials))
+	for _, credential := range credentials {
+		data = append(data, credentialSummary(credential))
+	}
+	response.RespondWithSuccess(c, response.StatusOK, gin.H{"data": data})
+}
+
+// GinWebAuthnRegisterOptions handles POST /mfa/webauthn/register/options - Start a passkey registration
+// Returns the options for navigator.credentials.create().
+func GinWebAuthnRegisterOptions(c *gin.Context) {
+	authInfo, ok := mfaAuthInfo(c)
+	if !ok || !mfaNotVerifying(c, authInfo) {
+		return
+	}
+
+	rp, err := webauthnRelyingParty(c)
+	if err != nil {
+		respondMFAError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, err.Error())
+		return
+	}
+
+	user, ok := mfaUser(c, authInfo.UserID)
+	if !ok {
+		return
+	}
+
+	provider, err := oauth.OAuth.GetUserProvider()
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to get user provider: "+err.Error())
+		return
+	}
+
+	credentials, err := provider.GetUserCredentials(c.Request.Context(), authInfo.UserID)
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to get passkeys: "+err.Error())
+		return
+	}
+
+	challenge, err := saveWebAuthnChallenge("register", authInfo.UserID, rp)
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, err.Error())
+		return
+	}
+
+	name := totpAccountName(authInfo.UserID, user)
+	options := rp.CreationOptions(challenge, authInfo.UserID, name, utils.ToString(user["name"]), credentialDescriptors(credentials, rp.ID))
+	response.RespondWithSuccess(c, response.StatusOK, options)
+}
+
+// GinWebAuthnRegister handles POST /mfa/webauthn/register - Finish a passkey registration
+func GinWebAuthnRegister(c *gin.Context) {
+	authInfo, ok := mfaAuthInfo(c)
+	if !ok || !mfaNotVerifying(c, authInfo) {
+		return
+	}
+
+	var req WebAuthnRegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondMFAError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, "Invalid request body: "+err.Error())
+		return
+	}
+
+	state, err := takeWebAuthnChallenge("register", authInfo.UserID)
+	if err != nil {
+		respondMFAError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, err.Error())
+		return
+	}
+
+	rp := &webauthn.RelyingParty{ID: state.RPID, Origins: []string{state.Origin}}
+	credential, err := rp.VerifyRegistration(state.Challenge, req.Credential)
+	if err != nil {
+		auditMFA(c, audit.OpMFAEnable, MFAMethodWebAuthn, err)
+		respondMFAError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, "Invalid passkey: "+err.Error())
+		return
+	}
+
+	provider, err := oauth.OAuth.GetUserProvider()
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to get user provider: "+err.Error())
+		return
+	}
+
+	data := maps.MapStrAny{
+		"credential_id": credential.ID,
+		"name":          req.Name,
+		"rp_id":         rp.ID,
+		"public_key":    base64.StdEncoding.EncodeToString(credential.PublicKey),
+		"algorithm":     credential.Algorithm,
+		"sign_count":    credential.SignCount,
+		"aaguid":        credential.AAGUID,
+		"transports":    credential.Transports,
+		"user_verified": credential.UserVerified,
+	}
+	_, err = provider.CreateCredential(c.Request.Context(), authInfo.UserID, data)
+	auditMFA(c, audit.OpMFAEnable, MFAMethodWebAuthn, err)
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to save passkey: "+err.Error())
+		return
+	}
+
+	response.RespondWithSuccess(c, response.StatusCreated, credentialSummary(data))
+}
+
+// GinWebAuthnDelete handles DELETE /mfa/webauthn/:credential_id - Remove a passkey
+func GinWebAuthnDelete(c *gin.Context) {
+	authInfo, ok := mfaAuthInfo(c)
+	if !ok || !mfaNotVerifying(c, authInfo) {
+		return
+	}
+
+	provider, err := oauth.OAuth.GetUserProvider()
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to get user provider: "+err.Error())
+		return
+	}
+
+	err = provider.DeleteCredential(c.Request.Context(), authInfo.UserID, c.Param("credential_id"))
+	auditMFA(c, audit.OpMFADisable, MFAMethodWebAuthn, err)
+	if err != nil {
+		respondMFAError(c, response.StatusNotFound, response.ErrInvalidRequest.Code, "Passkey not found")
+		return
+	}
+
+	response.RespondWithSuccess(c, response.StatusOK, gin.H{"message": "Passkey removed"})
+}
+
+// GinWebAuthnLoginOptions handles POST /mfa/webauthn/login/options - Start a passkey assertion
+// Returns the options for navigator.credentials.get().
+func GinWebAuthnLoginOptions(c *gin.Context) {
+	authInfo, ok := mfaAuthInfo(c)
+	if !ok {
+		return
+	}
+
+	rp, err := webauthnRelyingParty(c)
+	if err != nil {
+		respondMFAError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, err.Error())
+		return
+	}
+
+	provider, err := oauth.OAuth.GetUserProvider()
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to get user provider: "+err.Error())
+		return
+	}
+
+	credentials, err := provider.GetUserCredentials(c.Request.Context(), authInfo.UserID)
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to get passkeys: "+err.Error())
+		return
+	}
+
+	allow := credentialDescriptors(credentials, rp.ID)
+	if len(allow) == 0 {
+		respondMFAError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, "No passkey registered for "+rp.ID)
+		return
+	}
+
+	challenge, err := saveWebAuthnChallenge("login", authInfo.UserID, rp)
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, err.Error())
+		return
+	}
+
+	response.RespondWithSuccess(c, response.StatusOK, rp.RequestOptions(challenge, allow))
+}
+
+// GinWebAuthnVerify handles POST /mfa/webauthn/verify - Verify a passkey assertion
+// With the MFA verification token it completes the login and issues the tokens.
+func GinWebAuthnVerify(c *gin.Context) {
+	authInfo, ok := mfaAuthInfo(c)
+	if !ok {
+		return
+	}
+
+	var assertion webauthn.Assertion
+	if err := c.ShouldBindJSON(&assertion); err != nil || assertion.ID == "" {
+		respondMFAError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, "Invalid request body")
+		return
+	}
+
+	if !mfaAttemptsAllowed(c, authInfo.UserID) {
+		return
+	}
+
+	state, err := takeWebAuthnChallenge("login", authInfo.UserID)
+	if err != nil {
+		respondMFAError(c, response.StatusBadRequest, response.ErrInvalidRequest.Code, err.Error())
+		return
+	}
+
+	provider, err := oauth.OAuth.GetUserProvider()
+	if err != nil {
+		respondMFAError(c, response.StatusInternalServerError, response.ErrServerError.Code, "Failed to get user provider: "+err.Error())
+		return
+	}
+
+	ctx := c.Request.Context()
+	credentialID := assertion.ID
+	if raw, err := webauthn.Decode(assertion.ID); err == nil {
+		credentialID = base64.RawURLEncoding.EncodeToString(raw)
+	}
+
+	row, err := provider.GetCredential(ctx, credentialID)
+	if err == nil && (utils.ToString(row["user_id"]) != authInfo.UserID || utils.ToString(row["rp_id"]) != state.RPID) {
+		err = fmt.Errorf("passkey not found")
+	}
+
+	var signCount uint32
+	if err == nil {
+		var credential *webauthn.Credential
+		credential, err = storedCredential(row)
+		if err == nil {
+			rp := &webauthn.RelyingParty{ID: state.RPID, Origins: []string{state.Origin}}
+			signCount, err = rp.VerifyAssertion(state.Challenge, credential, &assertion)
+		}
+	}
+
+	if err == nil {
+		updateErr := provider.UpdateCredential(ctx, credentialID, maps.MapStrAny{
+			"sign_count":   signCount,
+			"last_used_at": time.Now(),
+		})
+		if updateErr != nil {
+			log.Warn("Failed to update passkey %s: %v", credentialID, updateErr)
+		}
+	}
+
+	mfaVerified(c, authInfo, MFAMethodWebAuthn, err)
+}
+
+// webauthnRelyingParty derives the relying party from the Origin header
+// The RP ID is the host of the page calling the WebAuthn API, browsers always send the Origin.
+func webauthnRelyingParty(c *gin.Context) (*webauthn.RelyingParty, error) {
+	origin := c.GetHeader("Origin")
+	if origin == "" {
+		return nil, fmt.Errorf("the Origin header is required")
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid Origin header: %s", origin)
+	}
+
+	name := share.App.Name
+	if name == "" {
+		name = u.Hostname()
+	}
+	return &webauthn.RelyingParty{ID: u.Hostname(), Name: name, Origins: []string{origin}}, nil
+}
+
+// saveWebAuthnChallenge starts a ceremony, only the latest one of a user is valid
+func saveWebAuthnChallenge(ceremony string, userID string, rp *webauthn.RelyingParty) (string, error) {
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return "", err
+	}
+
+	raw, err := json.Marshal(webauthnChallenge{Challenge: challenge, RPID: rp.ID, Origin: rp.Origins[0]})
+	if err != nil {
+		return "", err
+	}
+
+	if err := oauth.OAuth.GetCache().Set(webauthnChallengeKey(ceremony, userID), string(raw), webauthnChallengeExpire); err != nil {
+		return "", fmt.Errorf("failed to store challenge: %w", err)
+	}
+	return challenge, nil
+}
+
+// takeWebAuthnChallenge gets and removes the pending ceremony, a challenge is only used once
+func takeWebAuthnChallenge(ceremony string, userID string) (*webauthnChallenge, error) {
+	cache := oauth.OAuth.GetCache()
+	key := webauthnChallengeKey(ceremony, userID)
+	value, ok := cache.Get(key)
+	if !ok {
+		return nil, fmt.Errorf("challenge not found or expired")
+	}
+	cache.Del(key)
+
+	raw, _ := value.(string)
+	var state webauthnChallenge
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		return nil, fmt.Errorf("invalid challenge: %w", err)
+	}
+	return &state, nil
+}
+
+// storedCredential converts a credential row for verification
+func storedCredential(row maps.MapStrAny) (*webauthn.Credential, error) {
+	publicKey, err := base64.StdEncoding.DecodeString(utils.ToString(row["public_key"]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid stored public key: %w", err)
+	}
+	return &webauthn.Credential{
+		ID:        utils.ToString(row["credential_id"]),
+		PublicKey: publicKey,
+		Algorithm: utils.ToInt(row["algorithm"]),
+		SignCount: uint32(utils.ToInt64(row["sign_count"])),
+	}, nil
+}
+
+// credentialDescriptors lists the credentials of a relying party for WebAuthn options
+func credentialDescriptors(credentials []maps.MapStrAny, rpID string) []webauthn.CredentialDescriptor {
+	descriptors := []webauthn.CredentialDescriptor{}
+	for _, credential := range credentials {
+		if utils.ToString(credential["rp_id"]) != rpID {
+			continue
+		}
+		descriptor := webauthn.CredentialDescriptor{Type: "public-key", ID: utils.ToString(credential["credential_id"])}
+		if transports, ok := credential["transports"].([]interface{}); ok {
+			for _, transport := range transports {
+				descriptor.Transports = append(descriptor.Transports, utils.ToString(transport))
+			}
+		}
+		descriptors = append(descriptors, descriptor)
+	}
+	return descriptors
+}
+
+// credentialSummary returns the public fields of a credential
+func credentialSummary(credential maps.MapStrAny) gin.H {
+	summary := gin.H{}
+	for _, field := range []string{"credential_id", "name", "rp_id", "aaguid", "transports", "user_verified", "last_used_at", "created_at"} {
+		if value, ok := credential[field]; ok {
+			summary[field] = value
+		}
+	}
+	return summary
+}
+
+func webauthnChallengeKey(ceremony, userID string) string {
+	return fmt.Sprintf("mfa:webauthn:%s:%s", ceremony, userID)
+}
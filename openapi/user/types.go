@@ -1,7 +1,10 @@
 package user
 
 import (
+	"time"
+
 	oauthtypes "github.com/yaoapp/yao/openapi/oauth/types"
+	"github.com/yaoapp/yao/openapi/oauth/webauthn"
 )
 
 // LoginStatus represents the login status
@@ -415,6 +418,40 @@ type TeamSelectionRequest struct {
 	TeamID string `json:"team_id" binding:"required"`
 }
 
+// ==== MFA API Types ====
+
+// MFA methods, the second factors a user can pass at login
+const (
+	MFAMethodTOTP     = "totp"
+	MFAMethodRecovery = "recovery_code"
+	MFAMethodSMS      = "sms"
+	MFAMethodEmail    = "email"
+	MFAMethodWebAuthn = "webauthn"
+)
+
+// MFACodeRequest represents a request carrying a one-time code
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// TOTPEnableRequest represents the request to enable TOTP
+type TOTPEnableRequest struct {
+	Code   string `json:"code" binding:"required"`
+	Secret string `json:"secret,omitempty"` // Defaults to the secret from GET /mfa/totp
+}
+
+// TOTPVerifyRequest represents the request to verify a TOTP code or a recovery code
+type TOTPVerifyRequest struct {
+	Code         string `json:"code,omitempty"`
+	RecoveryCode string `json:"recovery_code,omitempty"`
+}
+
+// WebAuthnRegisterRequest represents the request to register a passkey
+type WebAuthnRegisterRequest struct {
+	Name       string                 `json:"name,omitempty"`
+	Credential *webauthn.Registration `json:"credential" binding:"required"`
+}
+
 // ==== Member API Types ====
 
 // MemberResponse represents a team member in API responses
@@ -717,3 +754,17 @@ type InviteConfig struct {
 	BaseURL   string            `json:"base_url,omitempty"` // Base URL for invitation links
 	Templates map[string]string `json:"templates,omitempty"`
 }
+
+// APIKeyCreateRequest represents the request to create an API key
+type APIKeyCreateRequest struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // Never expires if empty
+}
+
+// APIKeyUpdateRequest represents the request to update an API key, empty fields are unchanged
+type APIKeyUpdateRequest struct {
+	Name      *string    `json:"name,omitempty" binding:"omitempty,max=100"`
+	Scopes    []string   `json:"scopes,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
@@ -17,10 +17,17 @@ func init() {
 		&acl.ScopeDefinition{
 			Name:        ScopeMFAVerification,
 			Description: "MFA verification - temporary access for completing MFA challenge",
+			Exclusive:   true, // Rejected on other endpoints even with ACL disabled
 			Endpoints: []string{
+				"GET /user/mfa",
+				"GET /user/mfa/totp",
 				"POST /user/mfa/totp/verify",
+				"POST /user/mfa/sms/verification-code",
 				"POST /user/mfa/sms/verify",
-				"GET /user/mfa/totp",
+				"POST /user/mfa/email/verification-code",
+				"POST /user/mfa/email/verify",
+				"POST /user/mfa/webauthn/login/options",
+				"POST /user/mfa/webauthn/verify",
 			},
 		},
 		// Team selection scope - allows users to select a team and issue new tokens
@@ -234,12 +241,12 @@ func attachUsage(group *gin.RouterGroup, oauth types.OAuth) {
 
 // User API Keys Management
 func attachAPIKeys(group *gin.RouterGroup, oauth types.OAuth) {
-	group.GET("/api-keys", oauth.Guard, placeholder)                     // Get all user API keys
-	group.POST("/api-keys", oauth.Guard, placeholder)                    // Create new API key
-	group.GET("/api-keys/:key_id", oauth.Guard, placeholder)             // Get specific API key details
-	group.PUT("/api-keys/:key_id", oauth.Guard, placeholder)             // Update API key (name, permissions)
-	group.DELETE("/api-keys/:key_id", oauth.Guard, placeholder)          // Delete API key
-	group.POST("/api-keys/:key_id/regenerate", oauth.Guard, placeholder) // Regenerate API key
+	group.GET("/api-keys", oauth.Guard, GinAPIKeyList)                           // Get all user API keys
+	group.POST("/api-keys", oauth.Guard, GinAPIKeyCreate)                        // Create new API key
+	group.GET("/api-keys/:key_id", oauth.Guard, GinAPIKeyGet)                    // Get specific API key details
+	group.PUT("/api-keys/:key_id", oauth.Guard, GinAPIKeyUpdate)                 // Update API key (name, scopes, expiry)
+	group.DELETE("/api-keys/:key_id", oauth.Guard, GinAPIKeyDelete)              // Revoke API key
+	group.POST("/api-keys/:key_id/regenerate", oauth.Guard, GinAPIKeyRegenerate) // Regenerate API key
 }
 
 // User Subscription Management
@@ -294,21 +301,38 @@ func attachMFA(group *gin.RouterGroup, oauth types.OAuth) {
 	mfa := group.Group("/mfa")
 	mfa.Use(oauth.Guard)
 
+	mfa.GET("", GinMFAStatus) // Get enabled MFA methods
+
 	// TOTP Management
-	mfa.GET("/totp", placeholder)                            // Get TOTP QR code and setup info
-	mfa.POST("/totp/enable", placeholder)                    // Enable TOTP with verification
-	mfa.POST("/totp/disable", placeholder)                   // Disable TOTP with verification
-	mfa.POST("/totp/verify", placeholder)                    // Verify TOTP code
-	mfa.GET("/totp/recovery-codes", placeholder)             // Get TOTP recovery codes
-	mfa.POST("/totp/recovery-codes/regenerate", placeholder) // Regenerate recovery codes
-	mfa.POST("/totp/reset", placeholder)                     // Reset TOTP (requires email verification)
+	mfa.GET("/totp", GinTOTPGet)                                                // Get TOTP QR code and setup info
+	mfa.POST("/totp/enable", GinTOTPEnable)                                     // Enable TOTP with verification
+	mfa.POST("/totp/disable", GinTOTPDisable)                                   // Disable TOTP with verification
+	mfa.POST("/totp/verify", GinTOTPVerify)                                     // Verify TOTP code
+	mfa.GET("/totp/recovery-codes", GinTOTPRecoveryCodes)                       // Get TOTP recovery codes
+	mfa.POST("/totp/recovery-codes/regenerate", GinTOTPRecoveryCodesRegenerate) // Regenerate recovery codes
+	mfa.POST("/totp/reset", GinTOTPReset)                                       // Reset TOTP (requires email verification)
 
 	// SMS MFA Management
-	mfa.GET("/sms", placeholder)                    // Get SMS MFA status
-	mfa.POST("/sms/enable", placeholder)            // Enable SMS MFA
-	mfa.POST("/sms/disable", placeholder)           // Disable SMS MFA
-	mfa.POST("/sms/verification-code", placeholder) // Send SMS verification code
-	mfa.POST("/sms/verify", placeholder)            // Verify SMS code
+	mfa.GET("/sms", GinSMSStatus)                      // Get SMS MFA status
+	mfa.POST("/sms/enable", GinSMSEnable)              // Enable SMS MFA
+	mfa.POST("/sms/disable", GinSMSDisable)            // Disable SMS MFA
+	mfa.POST("/sms/verification-code", GinSMSSendCode) // Send SMS verification code
+	mfa.POST("/sms/verify", GinSMSVerify)              // Verify SMS code
+
+	// Email MFA Management
+	mfa.GET("/email", GinEmailMFAStatus)                      // Get email MFA status
+	mfa.POST("/email/enable", GinEmailMFAEnable)              // Enable email MFA
+	mfa.POST("/email/disable", GinEmailMFADisable)            // Disable email MFA
+	mfa.POST("/email/verification-code", GinEmailMFASendCode) // Send email verification code
+	mfa.POST("/email/verify", GinEmailMFAVerify)              // Verify email code
+
+	// WebAuthn (Passkey) Management
+	mfa.GET("/webauthn", GinWebAuthnList)                              // List passkeys
+	mfa.POST("/webauthn/register/options", GinWebAuthnRegisterOptions) // Start passkey registration
+	mfa.POST("/webauthn/register", GinWebAuthnRegister)                // Finish passkey registration
+	mfa.DELETE("/webauthn/:credential_id", GinWebAuthnDelete)          // Remove a passkey
+	mfa.POST("/webauthn/login/options", GinWebAuthnLoginOptions)       // Start passkey assertion
+	mfa.POST("/webauthn/verify", GinWebAuthnVerify)                    // Verify passkey assertion
 }
 
 // Third party login (OAuth)
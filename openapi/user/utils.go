@@ -70,6 +70,28 @@ func MaskEmail(email string) string {
 	return masked + "@" + domain
 }
 
+// MaskPhone masks a phone number for privacy protection
+// Keeps a leading "+" and the last 4 digits, masks the rest with *
+// Examples:
+//   - "+8613800138000" -> "+*********8000"
+//   - "123" -> "***"
+func MaskPhone(phone string) string {
+	if phone == "" {
+		return ""
+	}
+
+	prefix := ""
+	if strings.HasPrefix(phone, "+") {
+		prefix = "+"
+		phone = phone[1:]
+	}
+
+	if len(phone) <= 4 {
+		return prefix + strings.Repeat("*", len(phone))
+	}
+	return prefix + strings.Repeat("*", len(phone)-4) + phone[len(phone)-4:]
+}
+
 // parseUserAgent extracts device and platform information from User-Agent string
 // Returns device type ("mobile", "tablet", "desktop") and platform ("ios", "android", "web", etc.)
 func parseUserAgent(userAgent string) (device string, platform string) {
@@ -3,6 +3,7 @@ package pipe
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/yaoapp/gou/application"
@@ -87,6 +88,23 @@ func NewFile(file string, root string) (*Pipe, error) {
 	return &pipe, nil
 }
 
+// NewSource create pipe from source with the given id
+func NewSource(source []byte, id string) (*Pipe, error) {
+	file := filepath.Join("pipes", share.File(id, ".pipe.yao"))
+	pipe := Pipe{ID: id}
+	err := application.Parse(file, source, &pipe)
+	if err != nil {
+		return nil, fmt.Errorf("parse pipe: %s", err)
+	}
+
+	err = (&pipe).build()
+	if err != nil {
+		return nil, fmt.Errorf("build pipe: %s", err)
+	}
+
+	return &pipe, nil
+}
+
 // Set pipe to
 func Set(id string, pipe *Pipe) {
 	pipes[id] = pipe
@@ -99,6 +117,15 @@ func Remove(id string) {
 	}
 }
 
+// IDs returns the ids of the loaded pipes
+func IDs() []string {
+	ids := make([]string, 0, len(pipes))
+	for id := range pipes {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
 // Get the pipe
 func Get(id string) (*Pipe, error) {
 	if pipe, has := pipes[id]; has {
@@ -33,6 +33,8 @@ type Box struct {
 	system        SystemInfo
 	displayName   string
 	workDir       string
+	memory        int64   // reserved memory limit in bytes, 0 if unlimited
+	cpus          float64 // reserved CPU limit, 0 if unlimited
 	ws            taiworkspace.FS
 	manager       *Manager
 }
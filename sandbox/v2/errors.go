@@ -7,4 +7,5 @@ var (
 	ErrNotFound     = errors.New("sandbox: not found")
 	ErrNodeNotFound = errors.New("sandbox: node not found")
 	ErrNodeMissing  = errors.New("sandbox: node ID is required")
+	ErrNoCapacity   = errors.New("sandbox: no node has capacity")
 )
@@ -4,3 +4,10 @@ package sandbox
 func ResetForTest() {
 	mgr = nil
 }
+
+// AddBoxForTest registers a running box without a container, for placement tests.
+func (m *Manager) AddBoxForTest(id, nodeID, owner string, memory int64, cpus float64) {
+	b := &Box{id: id, nodeID: nodeID, owner: owner, memory: memory, cpus: cpus, manager: m}
+	b.status.Store("running")
+	m.boxes.Store(id, b)
+}
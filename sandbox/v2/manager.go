@@ -112,17 +112,11 @@ func (m *Manager) Create(ctx context.Context, opts CreateOptions) (*Box, error)
 			if err != nil {
 				targetNode := nodeID
 				if targetNode == "" {
-					if nodes := wsm.Nodes(); len(nodes) > 0 {
-						for _, n := range nodes {
-							if n.Online {
-								targetNode = n.Name
-								break
-							}
-						}
+					placed, err := m.Place(opts)
+					if err != nil {
+						return nil, fmt.Errorf("sandbox: resolve workspace %q: %w", opts.WorkspaceID, err)
 					}
-				}
-				if targetNode == "" {
-					return nil, fmt.Errorf("sandbox: resolve workspace %q: no available node", opts.WorkspaceID)
+					targetNode = placed
 				}
 				wsID := opts.WorkspaceID
 				if wsID == opts.Owner {
@@ -151,7 +145,11 @@ func (m *Manager) Create(ctx context.Context, opts CreateOptions) (*Box, error)
 	}
 
 	if nodeID == "" {
-		return nil, ErrNodeMissing
+		placed, err := m.Place(opts)
+		if err != nil {
+			return nil, err
+		}
+		nodeID = placed
 	}
 
 	id := opts.ID
@@ -209,6 +207,8 @@ func (m *Manager) Create(ctx context.Context, opts CreateOptions) (*Box, error)
 		workspaceID:  opts.WorkspaceID,
 		workDir:      boxWorkDir,
 		displayName:  opts.DisplayName,
+		memory:       opts.Memory,
+		cpus:         opts.CPUs,
 		system:       sys,
 	}
 	box.status.Store("running")
@@ -343,12 +343,21 @@ func (m *Manager) buildTaiCreateOptions(opts CreateOptions, nodeID, sandboxID st
 	if opts.VNC {
 		labels["sandbox-vnc"] = "true"
 	}
+	if opts.NoNetwork {
+		labels["sandbox-network"] = "none"
+	}
 	if opts.WorkspaceID != "" {
 		labels["workspace-id"] = opts.WorkspaceID
 	}
 	if opts.DisplayName != "" {
 		labels["sandbox-display-name"] = opts.DisplayName
 	}
+	if opts.Memory > 0 {
+		labels["sandbox-memory"] = strconv.FormatInt(opts.Memory, 10)
+	}
+	if opts.CPUs > 0 {
+		labels["sandbox-cpus"] = strconv.FormatFloat(opts.CPUs, 'f', -1, 64)
+	}
 	if sys.OS != "" {
 		labels["sandbox-sys-os"] = sys.OS
 	}
@@ -419,6 +428,7 @@ func (m *Manager) buildTaiCreateOptions(opts CreateOptions, nodeID, sandboxID st
 		VNC:        opts.VNC,
 		Ports:      ports,
 		Labels:     labels,
+		NoNetwork:  opts.NoNetwork,
 	}
 }
 
@@ -461,6 +471,8 @@ func (m *Manager) recoverBoxes(ctx context.Context, nodeID string, res *tai.Conn
 			sys = inferSystemInfo(ctx, res, c.Image)
 		}
 		policy := LifecyclePolicy(c.Labels["sandbox-policy"])
+		memory, _ := strconv.ParseInt(c.Labels["sandbox-memory"], 10, 64)
+		cpus, _ := strconv.ParseFloat(c.Labels["sandbox-cpus"], 64)
 		box := &Box{
 			id:          sandboxID,
 			containerID: cid,
@@ -474,6 +486,8 @@ func (m *Manager) recoverBoxes(ctx context.Context, nodeID string, res *tai.Conn
 			vnc:         hasVNC,
 			workDir:     "/workspace",
 			displayName: c.Labels["sandbox-display-name"],
+			memory:      memory,
+			cpus:        cpus,
 			system:      sys,
 			manager:     m,
 		}
@@ -2,6 +2,7 @@ package sandbox_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -171,11 +172,13 @@ func TestCreateNoImage(t *testing.T) {
 func TestCreateNoNodeID(t *testing.T) {
 	m, _ := setupManager(t, nodeConfig{Name: "local", Addr: testLocalAddr()})
 
+	// Without NodeID the box is placed; no node carries the selected label
 	_, err := m.Create(context.Background(), sandbox.CreateOptions{
-		Image: testImage(),
+		Image:        testImage(),
+		NodeSelector: map[string]string{"sandbox-test": "no-such-node"},
 	})
-	if err != sandbox.ErrNodeMissing {
-		t.Errorf("err = %v, want ErrNodeMissing", err)
+	if !errors.Is(err, sandbox.ErrNoCapacity) {
+		t.Errorf("err = %v, want ErrNoCapacity", err)
 	}
 }
 
@@ -0,0 +1,220 @@
+package sandbox
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yaoapp/yao/tai"
+	taitypes "github.com/yaoapp/yao/tai/types"
+)
+
+// Placement tuning. A reported usage older than usageTTL is ignored and the
+// node is scored on its static capacity and the resources reserved by its boxes.
+const (
+	usageTTL      = time.Minute
+	minFreeRatio  = 0.05 // a node with less free memory or CPU than this is full
+	weightMemory  = 0.4
+	weightCPU     = 0.3
+	weightBoxes   = 0.3
+	affinityBonus = 0.2  // per matching preferred label
+	spreadPenalty = 0.25 // per box of the same owner already on the node
+)
+
+// NodeScore is the placement score of one node for a CreateOptions.
+// Rejected nodes carry a Reason and are never picked.
+type NodeScore struct {
+	NodeID     string
+	Score      float64
+	FreeMem    int64   // bytes, -1 when the node capacity is unknown
+	FreeCPU    float64 // cores, -1 when the node capacity is unknown
+	Boxes      int     // active boxes on the node
+	OwnerBoxes int     // active boxes of opts.Owner on the node
+	Reason     string  // why the node was rejected, empty for candidates
+}
+
+// nodeLoad is the load the manager itself puts on a node.
+type nodeLoad struct {
+	boxes      int
+	ownerBoxes int
+	memory     int64
+	cpus       float64
+}
+
+// Place picks the node for a new box: the online node with a container
+// runtime that matches opts.NodeSelector, has room for opts.Memory/opts.CPUs
+// and scores best on free resources, box count, opts.NodeAffinity and
+// spreading the boxes of opts.Owner. Returns ErrNoCapacity, with the reason
+// of every node, when no node qualifies.
+func (m *Manager) Place(opts CreateOptions) (string, error) {
+	scores := m.ScoreNodes(opts)
+	if len(scores) == 0 {
+		return "", ErrNotAvailable
+	}
+	if scores[0].Reason == "" {
+		return scores[0].NodeID, nil
+	}
+
+	reasons := make([]string, 0, len(scores))
+	for _, s := range scores {
+		reasons = append(reasons, fmt.Sprintf("%s: %s", s.NodeID, s.Reason))
+	}
+	return "", fmt.Errorf("%w (%s)", ErrNoCapacity, strings.Join(reasons, "; "))
+}
+
+// ScoreNodes scores every registered node for opts. Candidates come first,
+// best score first; rejected nodes follow.
+func (m *Manager) ScoreNodes(opts CreateOptions) []NodeScore {
+	loads := m.nodeLoads(opts.Owner)
+	nodes := m.Nodes()
+	scores := make([]NodeScore, 0, len(nodes))
+	for _, node := range nodes {
+		hasRuntime := node.Capabilities.Docker
+		if res, ok := tai.GetResources(node.TaiID); ok && res.Runtime != nil {
+			hasRuntime = true
+		}
+		scores = append(scores, scoreNode(node, hasRuntime, loads[node.TaiID], opts))
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		ri, rj := scores[i].Reason != "", scores[j].Reason != ""
+		if ri != rj {
+			return !ri
+		}
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		return scores[i].NodeID < scores[j].NodeID
+	})
+	return scores
+}
+
+// nodeLoads sums the active boxes and their reserved resources per node.
+func (m *Manager) nodeLoads(owner string) map[string]*nodeLoad {
+	loads := map[string]*nodeLoad{}
+	m.boxes.Range(func(_, v any) bool {
+		b := v.(*Box)
+		switch s, _ := b.status.Load().(string); s {
+		case "exited", "stopped":
+			return true
+		}
+		load, ok := loads[b.nodeID]
+		if !ok {
+			load = &nodeLoad{}
+			loads[b.nodeID] = load
+		}
+		load.boxes++
+		load.memory += b.memory
+		load.cpus += b.cpus
+		if owner != "" && b.owner == owner {
+			load.ownerBoxes++
+		}
+		return true
+	})
+	return loads
+}
+
+// scoreNode filters and scores one node.
+func scoreNode(node taitypes.NodeMeta, hasRuntime bool, load *nodeLoad, opts CreateOptions) NodeScore {
+	if load == nil {
+		load = &nodeLoad{}
+	}
+	score := NodeScore{NodeID: node.TaiID, FreeMem: -1, FreeCPU: -1, Boxes: load.boxes, OwnerBoxes: load.ownerBoxes}
+
+	if node.Status != "" && node.Status != "online" {
+		score.Reason = "node is " + node.Status
+		return score
+	}
+	if !hasRuntime {
+		score.Reason = "no container runtime"
+		return score
+	}
+	for k, v := range opts.NodeSelector {
+		if node.Labels[k] != v {
+			score.Reason = fmt.Sprintf("label %s=%s not matched", k, v)
+			return score
+		}
+	}
+
+	usage := nodeUsage(node)
+
+	// Memory: the reported usage, or at least what the boxes reserved
+	memRatio := 0.5
+	totalMem := node.System.TotalMem
+	if usage != nil && usage.MemTotal > 0 {
+		totalMem = usage.MemTotal
+	}
+	if totalMem > 0 {
+		used := load.memory
+		if usage != nil && usage.MemUsed > used {
+			used = usage.MemUsed
+		}
+		score.FreeMem = max(totalMem-used, 0)
+		memRatio = float64(score.FreeMem) / float64(totalMem)
+		if opts.Memory > 0 && score.FreeMem < opts.Memory {
+			score.Reason = fmt.Sprintf("insufficient memory (free %s, need %s)", formatBytes(score.FreeMem), formatBytes(opts.Memory))
+			return score
+		}
+		if memRatio < minFreeRatio {
+			score.Reason = fmt.Sprintf("memory exhausted (free %s of %s)", formatBytes(score.FreeMem), formatBytes(totalMem))
+			return score
+		}
+	}
+
+	// CPU: the load average is only an estimate, so it lowers the score but
+	// only the cores reserved by the boxes can reject a node
+	cpuRatio := 0.5
+	if numCPU := float64(node.System.NumCPU); numCPU > 0 {
+		reserved := max(numCPU-load.cpus, 0)
+		used := load.cpus
+		if usage != nil && usage.CPUPercent/100*numCPU > used {
+			used = usage.CPUPercent / 100 * numCPU
+		}
+		score.FreeCPU = max(numCPU-used, 0)
+		cpuRatio = score.FreeCPU / numCPU
+		if opts.CPUs > 0 && reserved < opts.CPUs {
+			score.Reason = fmt.Sprintf("insufficient CPU (unreserved %.2f, need %.2f)", reserved, opts.CPUs)
+			return score
+		}
+		if reserved/numCPU < minFreeRatio {
+			score.Reason = fmt.Sprintf("CPU exhausted (unreserved %.2f of %d)", reserved, node.System.NumCPU)
+			return score
+		}
+	}
+
+	score.Score = weightMemory*memRatio + weightCPU*cpuRatio + weightBoxes/float64(1+load.boxes)
+	for k, v := range opts.NodeAffinity {
+		if node.Labels[k] == v {
+			score.Score += affinityBonus
+		}
+	}
+	score.Score -= spreadPenalty * float64(load.ownerBoxes)
+	return score
+}
+
+// nodeUsage returns the fresh usage of a node. The local node is sampled
+// directly when it has not reported any.
+func nodeUsage(node taitypes.NodeMeta) *taitypes.Usage {
+	if node.Usage != nil && time.Since(node.Usage.UpdatedAt) <= usageTTL {
+		return node.Usage
+	}
+	if node.Mode == "local" {
+		usage := tai.CollectUsage()
+		return &usage
+	}
+	return nil
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
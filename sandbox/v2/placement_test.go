@@ -0,0 +1,166 @@
+package sandbox_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	sandbox "github.com/yaoapp/yao/sandbox/v2"
+	"github.com/yaoapp/yao/tai/registry"
+	taitypes "github.com/yaoapp/yao/tai/types"
+)
+
+const gib = int64(1) << 30
+
+// placementGroup is the label that isolates the fake nodes of these tests
+// from the real nodes other tests register.
+var placementGroup = map[string]string{"placement-test": "1"}
+
+type fakeNode struct {
+	id     string
+	cpus   int
+	mem    int64
+	usage  *taitypes.Usage
+	labels map[string]string
+	noRT   bool
+}
+
+func setupPlacement(t *testing.T, nodes ...fakeNode) *sandbox.Manager {
+	t.Helper()
+	if registry.Global() == nil {
+		registry.Init(nil)
+	}
+	reg := registry.Global()
+	for _, n := range nodes {
+		labels := map[string]string{"placement-test": "1"}
+		for k, v := range n.labels {
+			labels[k] = v
+		}
+		reg.Register(&registry.TaiNode{
+			TaiID:        n.id,
+			Mode:         "direct",
+			Labels:       labels,
+			Capabilities: taitypes.Capabilities{Docker: !n.noRT},
+			System:       taitypes.SystemInfo{NumCPU: n.cpus, TotalMem: n.mem},
+		})
+		if n.usage != nil {
+			reg.UpdateUsage(n.id, *n.usage)
+		}
+		id := n.id
+		t.Cleanup(func() { reg.Unregister(id) })
+	}
+
+	sandbox.Init()
+	m := sandbox.M()
+	t.Cleanup(func() { m.Close() })
+	return m
+}
+
+func TestPlaceMostFree(t *testing.T) {
+	m := setupPlacement(t,
+		fakeNode{id: "place-busy", cpus: 4, mem: 8 * gib, usage: &taitypes.Usage{CPUPercent: 80, MemUsed: 6 * gib}},
+		fakeNode{id: "place-idle", cpus: 4, mem: 8 * gib, usage: &taitypes.Usage{CPUPercent: 10, MemUsed: 1 * gib}},
+	)
+
+	nodeID, err := m.Place(sandbox.CreateOptions{NodeSelector: placementGroup})
+	if err != nil {
+		t.Fatalf("Place: %v", err)
+	}
+	if nodeID != "place-idle" {
+		t.Errorf("Place = %q, want place-idle", nodeID)
+	}
+}
+
+func TestPlaceStaleUsage(t *testing.T) {
+	stale := time.Now().Add(-10 * time.Minute)
+	m := setupPlacement(t,
+		fakeNode{id: "place-stale", cpus: 4, mem: 8 * gib, usage: &taitypes.Usage{CPUPercent: 99, MemUsed: 8 * gib, UpdatedAt: stale}},
+	)
+
+	// A stale report is ignored, the node is scored on its capacity
+	scores := m.ScoreNodes(sandbox.CreateOptions{NodeSelector: placementGroup})
+	if len(scores) == 0 || scores[0].NodeID != "place-stale" || scores[0].Reason != "" {
+		t.Fatalf("scores = %+v, want place-stale as a candidate", scores)
+	}
+	if scores[0].FreeMem != 8*gib {
+		t.Errorf("FreeMem = %d, want %d", scores[0].FreeMem, 8*gib)
+	}
+}
+
+func TestPlaceSelectorAndAffinity(t *testing.T) {
+	m := setupPlacement(t,
+		fakeNode{id: "place-cpu", cpus: 4, mem: 8 * gib, labels: map[string]string{"pool": "cpu"}},
+		fakeNode{id: "place-gpu-a", cpus: 4, mem: 8 * gib, labels: map[string]string{"pool": "gpu", "zone": "a"}},
+		fakeNode{id: "place-gpu-b", cpus: 4, mem: 8 * gib, labels: map[string]string{"pool": "gpu", "zone": "b"}},
+	)
+
+	nodeID, err := m.Place(sandbox.CreateOptions{
+		NodeSelector: map[string]string{"placement-test": "1", "pool": "gpu"},
+		NodeAffinity: map[string]string{"zone": "b"},
+	})
+	if err != nil {
+		t.Fatalf("Place: %v", err)
+	}
+	if nodeID != "place-gpu-b" {
+		t.Errorf("Place = %q, want place-gpu-b", nodeID)
+	}
+
+	_, err = m.Place(sandbox.CreateOptions{NodeSelector: map[string]string{"placement-test": "1", "pool": "tpu"}})
+	if !errors.Is(err, sandbox.ErrNoCapacity) {
+		t.Errorf("err = %v, want ErrNoCapacity", err)
+	}
+}
+
+func TestPlaceSpreadOwner(t *testing.T) {
+	m := setupPlacement(t,
+		fakeNode{id: "place-spread-a", cpus: 8, mem: 16 * gib},
+		fakeNode{id: "place-spread-b", cpus: 8, mem: 16 * gib},
+	)
+	m.AddBoxForTest("place-spread-box", "place-spread-a", "alice", 0, 0)
+	t.Cleanup(func() { m.Remove(context.Background(), "place-spread-box") })
+
+	nodeID, err := m.Place(sandbox.CreateOptions{Owner: "alice", NodeSelector: placementGroup})
+	if err != nil {
+		t.Fatalf("Place: %v", err)
+	}
+	if nodeID != "place-spread-b" {
+		t.Errorf("Place = %q, want place-spread-b", nodeID)
+	}
+
+	scores := m.ScoreNodes(sandbox.CreateOptions{Owner: "alice", NodeSelector: placementGroup})
+	for _, s := range scores {
+		if s.NodeID == "place-spread-a" && (s.Boxes != 1 || s.OwnerBoxes != 1) {
+			t.Errorf("place-spread-a: Boxes = %d, OwnerBoxes = %d, want 1 and 1", s.Boxes, s.OwnerBoxes)
+		}
+	}
+}
+
+func TestPlaceNoCapacity(t *testing.T) {
+	m := setupPlacement(t,
+		fakeNode{id: "place-small", cpus: 2, mem: 2 * gib},
+		fakeNode{id: "place-full", cpus: 4, mem: 8 * gib, usage: &taitypes.Usage{CPUPercent: 10, MemUsed: 1 * gib}},
+		fakeNode{id: "place-nort", cpus: 8, mem: 32 * gib, noRT: true},
+	)
+	m.AddBoxForTest("place-small-box", "place-small", "bob", 1*gib, 1)
+	m.AddBoxForTest("place-full-box", "place-full", "bob", 0, 4)
+	t.Cleanup(func() {
+		m.Remove(context.Background(), "place-small-box")
+		m.Remove(context.Background(), "place-full-box")
+	})
+
+	_, err := m.Place(sandbox.CreateOptions{Memory: 2 * gib, NodeSelector: placementGroup})
+	if !errors.Is(err, sandbox.ErrNoCapacity) {
+		t.Fatalf("err = %v, want ErrNoCapacity", err)
+	}
+	for _, want := range []string{
+		"place-small: insufficient memory",
+		"place-full: CPU exhausted",
+		"place-nort: no container runtime",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %q, want it to mention %q", err, want)
+		}
+	}
+}
@@ -102,6 +102,7 @@ type CreateOptions struct {
 	CPUs        float64
 	VNC         bool
 	Ports       []PortMapping
+	NoNetwork   bool // no network access: the box cannot reach Yao over gRPC either
 	Policy      LifecyclePolicy
 	IdleTimeout time.Duration
 	MaxLifetime time.Duration
@@ -112,6 +113,10 @@ type CreateOptions struct {
 	MountPath   string
 	DisplayName string
 	Locale      string
+
+	// Placement, used when NodeID is empty (see Manager.Place)
+	NodeSelector map[string]string // node labels the node must carry
+	NodeAffinity map[string]string // node labels the node should carry
 }
 
 type ListOptions struct {
@@ -87,20 +87,22 @@ func extractBearer(r *http.Request) string {
 
 // registerRequest is the JSON body for POST /tai-nodes/register.
 type registerRequest struct {
-	NodeID       string           `json:"node_id,omitempty"`
-	ClientID     string           `json:"client_id,omitempty"`
-	MachineID    string           `json:"machine_id"`
-	DisplayName  string           `json:"display_name,omitempty"`
-	Version      string           `json:"version"`
-	Addr         string           `json:"addr"`
-	Ports        map[string]int   `json:"ports"`
-	Capabilities map[string]bool  `json:"capabilities"`
-	System       types.SystemInfo `json:"system"`
+	NodeID       string            `json:"node_id,omitempty"`
+	ClientID     string            `json:"client_id,omitempty"`
+	MachineID    string            `json:"machine_id"`
+	DisplayName  string            `json:"display_name,omitempty"`
+	Version      string            `json:"version"`
+	Addr         string            `json:"addr"`
+	Ports        map[string]int    `json:"ports"`
+	Capabilities map[string]bool   `json:"capabilities"`
+	System       types.SystemInfo  `json:"system"`
+	Labels       map[string]string `json:"labels,omitempty"`
 }
 
 // heartbeatRequest is the JSON body for POST /tai-nodes/heartbeat.
 type heartbeatRequest struct {
-	TaiID string `json:"tai_id"`
+	TaiID string       `json:"tai_id"`
+	Usage *types.Usage `json:"usage,omitempty"`
 }
 
 // HandleRegister handles POST /tai-nodes/register.
@@ -165,6 +167,7 @@ func HandleRegister(c *gin.Context) {
 		Addr:         addr,
 		Ports:        portsFromMap(req.Ports),
 		Capabilities: capsFromMap(req.Capabilities),
+		Labels:       req.Labels,
 	}
 	reg.Register(node)
 	slog.Info("[register] node registered via API",
@@ -233,6 +236,9 @@ func HandleHeartbeat(c *gin.Context) {
 	}
 
 	reg.UpdatePing(req.TaiID)
+	if req.Usage != nil {
+		reg.UpdateUsage(req.TaiID, *req.Usage)
+	}
 	c.JSON(http.StatusOK, gin.H{"status": "ok"})
 }
 
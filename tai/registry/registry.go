@@ -34,7 +34,9 @@ type TaiNode struct {
 	Status      string // "online" | "offline" | "connecting"
 	ConnectedAt time.Time
 	LastPing    time.Time
-	DisplayName string // optional human-readable name for UI
+	DisplayName string            // optional human-readable name for UI
+	Labels      map[string]string // node labels for sandbox placement
+	Usage       *types.Usage      // last reported resource usage
 
 	resources any // *tai.ConnResources; stored as any to avoid import cycle
 
@@ -49,9 +51,30 @@ func (n *TaiNode) meta() types.NodeMeta {
 		Ports: n.Ports, Capabilities: n.Capabilities,
 		Status: n.Status, ConnectedAt: n.ConnectedAt, LastPing: n.LastPing,
 		DisplayName: n.DisplayName,
+		Labels:      copyLabels(n.Labels),
+		Usage:       copyUsage(n.Usage),
 	}
 }
 
+func copyLabels(labels map[string]string) map[string]string {
+	if labels == nil {
+		return nil
+	}
+	out := make(map[string]string, len(labels))
+	for k, v := range labels {
+		out[k] = v
+	}
+	return out
+}
+
+func copyUsage(usage *types.Usage) *types.Usage {
+	if usage == nil {
+		return nil
+	}
+	u := *usage
+	return &u
+}
+
 // tunnelListener wraps a TCP listener that bridges each accepted connection
 // through the tunnel to a specific Tai port.
 type tunnelListener struct {
@@ -187,6 +210,18 @@ func (r *Registry) UpdatePing(taiID string) {
 	}
 }
 
+// UpdateUsage records the resource usage reported with a heartbeat.
+func (r *Registry) UpdateUsage(taiID string, usage types.Usage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if n, ok := r.nodes[taiID]; ok {
+		if usage.UpdatedAt.IsZero() {
+			usage.UpdatedAt = time.Now()
+		}
+		n.Usage = &usage
+	}
+}
+
 // ResourceCloser is implemented by *tai.ConnResources to allow the registry
 // to close resources without importing the tai package (avoids import cycle).
 type ResourceCloser interface {
@@ -141,6 +141,36 @@ func TestUpdatePing_NonexistentNode(t *testing.T) {
 	r.UpdatePing("ghost")
 }
 
+func TestUpdateUsage(t *testing.T) {
+	r := newTestRegistry()
+	r.Register(&TaiNode{TaiID: "tai-001", Labels: map[string]string{"zone": "a"}})
+
+	r.UpdateUsage("tai-001", types.Usage{CPUPercent: 42, MemUsed: 1 << 30, MemTotal: 4 << 30, Containers: 3})
+	snap, _ := r.Get("tai-001")
+	if snap.Usage == nil {
+		t.Fatal("Usage should be set")
+	}
+	if snap.Usage.CPUPercent != 42 || snap.Usage.Containers != 3 {
+		t.Errorf("Usage = %+v, want CPUPercent 42 and 3 containers", *snap.Usage)
+	}
+	if snap.Usage.UpdatedAt.IsZero() {
+		t.Error("UpdatedAt should default to now")
+	}
+	if snap.Labels["zone"] != "a" {
+		t.Errorf("Labels[zone] = %q, want a", snap.Labels["zone"])
+	}
+
+	// The snapshot is a copy
+	snap.Labels["zone"] = "b"
+	snap.Usage.CPUPercent = 0
+	again, _ := r.Get("tai-001")
+	if again.Labels["zone"] != "a" || again.Usage.CPUPercent != 42 {
+		t.Error("mutating a snapshot should not change the registry")
+	}
+
+	r.UpdateUsage("ghost", types.Usage{})
+}
+
 func TestGenerateChannelID_Unique(t *testing.T) {
 	seen := make(map[string]bool)
 	for i := 0; i < 100; i++ {
@@ -35,6 +35,12 @@ func (d *dockerCore) create(ctx context.Context, opts CreateOptions, addVNCPorts
 		ExtraHosts: []string{"host.tai.internal:host-gateway"},
 	}
 
+	// Without network the container cannot reach the host or publish ports
+	if opts.NoNetwork {
+		hostCfg.NetworkMode = "none"
+		hostCfg.ExtraHosts = nil
+	}
+
 	if opts.Memory > 0 {
 		hostCfg.Resources.Memory = opts.Memory
 	}
@@ -77,7 +83,7 @@ func (d *dockerCore) create(ctx context.Context, opts CreateOptions, addVNCPorts
 		}
 	}
 
-	if len(exposedPorts) > 0 {
+	if len(exposedPorts) > 0 && !opts.NoNetwork {
 		cfg.ExposedPorts = exposedPorts
 		hostCfg.PortBindings = portBindings
 	}
@@ -130,6 +130,11 @@ func (s *k8sSandbox) Create(ctx context.Context, opts CreateOptions) (string, er
 	for k, v := range opts.Labels {
 		labels[k] = v
 	}
+	// Pods have no network switch: NoNetwork is enforced by a deny-all
+	// NetworkPolicy selecting the sandbox-network=none label
+	if opts.NoNetwork {
+		labels["sandbox-network"] = "none"
+	}
 
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -45,6 +45,7 @@ type CreateOptions struct {
 	Ports      []PortMapping
 	Labels     map[string]string // container/pod labels for discovery and management
 	User       string            // container user, e.g. "1000:1000" or "sandbox"
+	NoNetwork  bool              // run without network access (no ports are published)
 }
 
 // PortMapping maps a container port to a host port.
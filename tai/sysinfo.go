@@ -1,9 +1,13 @@
 package tai
 
 import (
+	"bufio"
 	"os"
 	"os/exec"
 	goruntime "runtime"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/yaoapp/yao/tai/types"
 )
@@ -23,6 +27,51 @@ func CollectSystemInfo() types.SystemInfo {
 	}
 }
 
+// CollectUsage samples the resource usage of the local host, in the same
+// shape as the usage a remote Tai node reports with its heartbeats.
+// CPU usage is estimated from the 1-minute load average. Only Linux is
+// sampled; other platforms report zero usage.
+func CollectUsage() types.Usage {
+	usage := types.Usage{UpdatedAt: time.Now()}
+	if goruntime.GOOS != "linux" {
+		return usage
+	}
+
+	if data, err := os.ReadFile("/proc/loadavg"); err == nil {
+		if fields := strings.Fields(string(data)); len(fields) > 0 {
+			load, _ := strconv.ParseFloat(fields[0], 64)
+			usage.CPUPercent = min(load/float64(goruntime.NumCPU())*100, 100)
+		}
+	}
+
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return usage
+	}
+	defer file.Close()
+
+	var total, available int64
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		kb, _ := strconv.ParseInt(fields[1], 10, 64)
+		switch fields[0] {
+		case "MemTotal:":
+			total = kb * 1024
+		case "MemAvailable:":
+			available = kb * 1024
+		}
+	}
+	if total > 0 {
+		usage.MemTotal = total
+		usage.MemUsed = total - available
+	}
+	return usage
+}
+
 func detectShell() string {
 	if goruntime.GOOS != "windows" {
 		return "sh"
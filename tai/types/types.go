@@ -65,4 +65,15 @@ type NodeMeta struct {
 	ConnectedAt  time.Time
 	LastPing     time.Time
 	DisplayName  string
+	Labels       map[string]string // node labels for sandbox placement (e.g. "gpu": "true", "zone": "cn-1")
+	Usage        *Usage            // last reported resource usage, nil if never reported
+}
+
+// Usage is the resource usage of a Tai node, reported with heartbeats.
+type Usage struct {
+	CPUPercent float64   `json:"cpu_percent"`          // whole node, 0-100
+	MemUsed    int64     `json:"mem_used"`             // bytes
+	MemTotal   int64     `json:"mem_total,omitempty"`  // bytes, overrides SystemInfo.TotalMem when set
+	Containers int       `json:"containers,omitempty"` // running containers, all owners
+	UpdatedAt  time.Time `json:"updated_at"`
 }
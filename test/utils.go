@@ -211,6 +211,7 @@ var testSystemModels = map[string]string{
 	"__yao.audit":              "yao/models/audit.mod.yao",
 	"__yao.config":             "yao/models/config.mod.yao",
 	"__yao.dsl":                "yao/models/dsl.mod.yao",
+	"__yao.dsl.revision":       "yao/models/dsl/revision.mod.yao",
 	"__yao.invitation":         "yao/models/invitation.mod.yao",
 	"__yao.job.category":       "yao/models/job/category.mod.yao",
 	"__yao.job":                "yao/models/job/job.mod.yao",
@@ -220,10 +221,13 @@ var testSystemModels = map[string]string{
 	"__yao.kb.document":        "yao/models/kb/document.mod.yao",
 	"__yao.team":               "yao/models/team.mod.yao",
 	"__yao.member":             "yao/models/member.mod.yao",
+	"__yao.notification":       "yao/models/notification.mod.yao",
 	"__yao.user":               "yao/models/user.mod.yao",
 	"__yao.role":               "yao/models/role.mod.yao",
 	"__yao.user.type":          "yao/models/user/type.mod.yao",
 	"__yao.user.oauth_account": "yao/models/user/oauth_account.mod.yao",
+	"__yao.user.credential":    "yao/models/user/credential.mod.yao",
+	"__yao.user.api_key":       "yao/models/user/api_key.mod.yao",
 }
 
 var testSystemStores = map[string]string{
@@ -2,7 +2,9 @@ package chart
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
+	"sync"
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/yaoapp/gou/application"
@@ -33,6 +35,7 @@ import (
 
 // Charts the loaded chart widgets
 var Charts map[string]*DSL = map[string]*DSL{}
+var lock sync.Mutex
 
 // New create a new DSL
 func New(id string) *DSL {
@@ -109,6 +112,43 @@ func LoadFile(root string, file string) error {
 	return nil
 }
 
+// LoadFileSync load chart dsl by file
+func LoadFileSync(root string, file string) error {
+	lock.Lock()
+	defer lock.Unlock()
+	return LoadFile(root, file)
+}
+
+// LoadSourceSync load chart dsl by source
+func LoadSourceSync(source []byte, id string) (*DSL, error) {
+	lock.Lock()
+	defer lock.Unlock()
+	return LoadSource(source, id)
+}
+
+// LoadSource load chart dsl by source
+func LoadSource(source []byte, id string) (*DSL, error) {
+	file := filepath.Join("charts", share.File(id, ".chart.yao"))
+	dsl := New(id)
+	err := application.Parse(file, source, dsl)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] %s", id, err.Error())
+	}
+
+	err = dsl.parse(id, "charts")
+	if err != nil {
+		return nil, err
+	}
+
+	Charts[id] = dsl
+	return dsl, nil
+}
+
+// Unload unload the chart
+func Unload(id string) {
+	delete(Charts, id)
+}
+
 // LoadData load via data
 func (dsl *DSL) parse(id string, root string) error {
 
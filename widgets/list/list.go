@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/yaoapp/gou/application"
@@ -45,6 +46,7 @@ import (
 
 // Lists the loaded list widgets
 var Lists map[string]*DSL = map[string]*DSL{}
+var lock sync.Mutex
 
 // New create a new DSL
 func New(id string) *DSL {
@@ -121,6 +123,43 @@ func LoadFile(root string, file string) error {
 	return nil
 }
 
+// LoadFileSync load list dsl by file
+func LoadFileSync(root string, file string) error {
+	lock.Lock()
+	defer lock.Unlock()
+	return LoadFile(root, file)
+}
+
+// LoadSourceSync load list dsl by source
+func LoadSourceSync(source []byte, id string) (*DSL, error) {
+	lock.Lock()
+	defer lock.Unlock()
+	return LoadSource(source, id)
+}
+
+// LoadSource load list dsl by source
+func LoadSource(source []byte, id string) (*DSL, error) {
+	file := filepath.Join("lists", share.File(id, ".list.yao"))
+	dsl := New(id)
+	err := application.Parse(file, source, dsl)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] %s", id, err.Error())
+	}
+
+	err = dsl.parse(id, "lists")
+	if err != nil {
+		return nil, err
+	}
+
+	Lists[id] = dsl
+	return dsl, nil
+}
+
+// Unload unload the list
+func Unload(id string) {
+	delete(Lists, id)
+}
+
 // LoadID load via id
 func LoadID(id string, root string) error {
 	file := filepath.Join("lists", share.File(id, ".yao"))
@@ -118,7 +118,7 @@ func Load(cfg config.Config) error {
 	}
 
 	messages := []string{}
-	exts := []string{"*.tab.yao", "*.tab.json", "*.tab.jsonc"}
+	exts := []string{"*.tab.yao", "*.tab.json", "*.tab.jsonc", "*.table.yao", "*.table.json", "*.table.jsonc"}
 	err = application.App.Walk("tables", func(root, file string, isdir bool) error {
 		if isdir {
 			return nil
@@ -160,6 +160,12 @@ func LoadID(id string) error {
 		return LoadFile("tables", file)
 	}
 
+	// Tables saved by the DSL manager
+	file = filepath.Join("tables", share.File(id, ".table.yao"))
+	if exists, _ := application.App.Exists(file); exists {
+		return LoadFile("tables", file)
+	}
+
 	return fmt.Errorf("table %s not found", id)
 }
 